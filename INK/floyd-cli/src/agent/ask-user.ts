@@ -0,0 +1,107 @@
+/**
+ * Ask User Tool
+ *
+ * In-process ask_user tool for clarifying questions mid-run. The agent loop
+ * awaits the tool call, so the run pauses while the TUI shows the question
+ * (with optional multiple-choice answers) and the reply becomes the tool result.
+ *
+ * @module agent/ask-user
+ */
+
+import {MCPClientManager, type MCPTool} from 'floyd-agent-core';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * A pending question shown to the user
+ */
+export interface AskUserQuestion {
+	question: string;
+	options?: string[];
+}
+
+/**
+ * Presents a question and resolves with the user's reply
+ */
+export type AskUserHandler = (question: AskUserQuestion) => Promise<string>;
+
+// ============================================================================
+// TOOL DEFINITION
+// ============================================================================
+
+export const ASK_USER_TOOL: MCPTool = {
+	name: 'ask_user',
+	description:
+		'Ask the user a clarifying question when requirements are ambiguous instead of guessing. ' +
+		'Execution pauses until they answer. Provide options for multiple-choice questions.',
+	inputSchema: {
+		type: 'object',
+		properties: {
+			question: {type: 'string', description: 'The question to ask'},
+			options: {
+				type: 'array',
+				items: {type: 'string'},
+				description: 'Optional multiple-choice answers',
+			},
+		},
+		required: ['question'],
+	},
+};
+
+/**
+ * Normalize raw tool input into a question
+ */
+export function parseAskUserInput(input: Record<string, any>): AskUserQuestion {
+	const options = Array.isArray(input['options'])
+		? input['options'].filter((o): o is string => typeof o === 'string' && o.trim() !== '')
+		: [];
+	return {
+		question: String(input['question'] ?? '').trim() || 'The agent needs your input.',
+		options: options.length > 0 ? options : undefined,
+	};
+}
+
+// ============================================================================
+// MCP CLIENT MANAGER
+// ============================================================================
+
+/**
+ * MCPClientManager that also serves the in-process ask_user tool
+ *
+ * ask_user is only advertised once a handler is registered, so headless
+ * runs never see a tool they cannot answer.
+ */
+export class InteractiveMCPClientManager extends MCPClientManager {
+	private askUserHandler: AskUserHandler | null = null;
+
+	/**
+	 * Register (or clear) the handler that answers ask_user calls
+	 */
+	setAskUserHandler(handler: AskUserHandler | null): void {
+		this.askUserHandler = handler;
+	}
+
+	override async listTools(): Promise<MCPTool[]> {
+		const tools = await super.listTools();
+		if (!this.askUserHandler || tools.some(t => t.name === ASK_USER_TOOL.name)) {
+			return tools;
+		}
+		return [...tools, ASK_USER_TOOL];
+	}
+
+	override async callTool(
+		name: string,
+		args: Record<string, any>,
+	): ReturnType<MCPClientManager['callTool']> {
+		if (name !== ASK_USER_TOOL.name || !this.askUserHandler) {
+			return super.callTool(name, args);
+		}
+
+		const answer = await this.askUserHandler(parseAskUserInput(args));
+		return {
+			content: [{type: 'text', text: answer || '(no answer)'}],
+		};
+	}
+}
@@ -6,10 +6,21 @@
  */
 import { useState, useEffect, useRef, useCallback, useMemo } from 'react';
 import { Box, Text, useInput, useApp } from 'ink';
-import { AgentEngine, MCPClientManager } from 'floyd-agent-core';
+import {
+	AgentEngine,
+	formatToolStats,
+	getCredentialStore,
+	validateApiKey,
+	parseProvider,
+	maskKey,
+	inferProviderFromEndpoint,
+	type TimingEvent,
+	type RequestOverrides,
+} from 'floyd-agent-core';
 import { SessionManager } from './store/session-store.js';
 import { ConfigLoader } from './utils/config.js';
 import { BUILTIN_SERVERS } from './config/builtin-servers.js';
+import { InteractiveMCPClientManager, type AskUserQuestion } from './agent/ask-user.js';
 import { StreamProcessor } from './streaming/stream-engine.js';
 import { StreamTagParser } from './streaming/tag-parser.js';
 import { getRandomWhimsicalPhrase } from './utils/whimsical-phrases.js';
@@ -31,6 +42,18 @@ import {
 	type CommandItem,
 } from './ui/components/CommandPalette.js';
 import { runDockCommand, parseDockArgs } from './commands/dock.js';
+import { createAppCommandRegistry, type AppCommandHandlers } from './commands/app-commands.js';
+import { looksLikeSlashCommand, formatUnknownCommand } from './commands/slash-completion.js';
+import { getDefaultRegistry as getSkillRegistry } from './skills/skill-registry.js';
+import type { SkillMetadata } from './skills/skill-definition.js';
+import {discoverSkillPacks, selectSkillPacks, buildSkillPrompt, applySkillPrompt, type SkillPack} from './skills/skill-packs.js';
+import { listWorkspaceFiles, buildMentionAttachments } from './utils/file-mentions.js';
+import { buildPinnedContext, unpinPaths } from './utils/pinned-context.js';
+import { loadImageAttachment, saveImageAttachment, formatImageMarkers, formatSize, type ImageAttachment } from './utils/image-attachments.js';
+import { getOfflineReason, describeOffline, runShellLine, readFileLines, OFFLINE_HINT } from './utils/offline.js';
+import { ToolPlaygroundOverlay } from './ui/overlays/ToolPlaygroundOverlay.js';
+import { AskUserOverlay } from './ui/overlays/AskUserOverlay.js';
+import { STREAM_FLUSH_INTERVAL } from './utils/minimal-mode.js';
 import type { ThinkingStatus } from './ui/agent/ThinkingStream.js';
 import type { Task } from './ui/agent/TaskChecklist.js';
 import type { ToolExecution } from './ui/monitor/ToolTimeline.js';
@@ -51,7 +74,39 @@ import {
 	BrowserSessionDashboard,
 	ResourceDashboard,
 	SessionHistoryDashboard,
+	ProgressLogTable,
 } from './ui/dashboard/index.js';
+import {
+	readProgress,
+	parseProgressArgs,
+	exportTranscript,
+	parseTranscriptFormat,
+	type ProgressEntry,
+	type ProgressQuery,
+	type TranscriptFormat,
+} from 'floyd-agent-core/utils';
+import {snapshotWorkingTree, summarizeRunChanges} from './utils/run-changes.js';
+import {livePaneForToolStart, livePaneForToolEnd, type LivePaneContent} from './utils/live-pane.js';
+import {getPasteStore} from './utils/bracketed-paste.js';
+import {copyToClipboard, lastCodeBlock, readClipboardImage} from './utils/clipboard.js';
+import {parseRetryArgs} from './utils/retry.js';
+import {formatRequestParams, parseSetArgs} from './utils/request-params.js';
+import {getLogger} from './utils/logger.js';
+import {CacheManager, formatCacheStats, type CacheStats, type CacheTier} from './cache/cache-manager.js';
+import {SessionWarmer} from './utils/session-warmer.js';
+import {formatLiveTiming, formatRequestTiming, startLiveTiming, type LiveTiming} from './utils/request-timing.js';
+import {
+	initialRunState,
+	reduceRunEvent,
+	diffRunState,
+	describeRunError,
+	type RunEvent,
+} from './store/run-state.js';
+import {readLogTail, formatLogRecord, type LogRecordLevel} from './utils/log-file.js';
+import {listToolCalls, numberToolCalls} from './utils/tool-results.js';
+import {findPrompt, numberPrompts} from './utils/prompt-edit.js';
+import {readContinuationPrompt} from './utils/continuation.js';
+import {getThemeManager} from './theme/user-themes.js';
 import {
 	selectTokenUsage,
 	selectToolPerformance,
@@ -94,6 +149,28 @@ type AppProps = {
 	chrome?: boolean;
 };
 
+// ============================================================================
+// API KEY
+// ============================================================================
+
+const apiEndpointFromEnv = () =>
+	process.env['FLOYD_GLM_ENDPOINT'] || process.env['GLM_ENDPOINT'] || 'https://api.z.ai/api/anthropic';
+
+/**
+ * API key from the environment, else the one stored with /auth login
+ */
+function resolveApiKey(): string | null {
+	const fromEnv = process.env['FLOYD_GLM_API_KEY'] || process.env['GLM_API_KEY'];
+	if (fromEnv) {
+		return fromEnv;
+	}
+	try {
+		return getCredentialStore().resolve(inferProviderFromEndpoint(apiEndpointFromEnv()));
+	} catch {
+		return null;
+	}
+}
+
 // ============================================================================
 // MESSAGE CONVERSION UTILITIES
 // ============================================================================
@@ -111,6 +188,19 @@ function toChatMessage(msg: Message | ConversationMessage): ChatMessage {
 				: JSON.stringify(msg.content),
 		timestamp: 'timestamp' in msg ? new Date(msg.timestamp) : new Date(),
 		streaming: 'streaming' in msg ? (msg as ConversationMessage).streaming : false,
+		thinking: 'thinking' in msg ? (msg as ConversationMessage).thinking : undefined,
+		toolCalls:
+			'toolCalls' in msg
+				? msg.toolCalls?.map(call => ({
+						id: call.id,
+						name: call.name,
+						status: call.status,
+						result: call.output,
+						error: call.error,
+						duration: call.duration,
+						timestamp: new Date(call.startedAt),
+				  }))
+				: undefined,
 	};
 }
 
@@ -127,6 +217,21 @@ function toChatMessage(msg: Message | ConversationMessage): ChatMessage {
 // MONITOR OVERLAY COMPONENT
 // ============================================================================
 
+/**
+ * One SUPERCACHE tier in the shape the memory dashboard shows
+ */
+function toCacheData(stats: CacheStats[], tier: CacheTier, name: string) {
+	const stat = stats.find(s => s.tier === tier);
+	return {
+		name,
+		entries: stat?.count ?? 0,
+		sizeBytes: stat?.totalSize ?? 0,
+		hits: stat?.hits ?? 0,
+		misses: stat?.misses ?? 0,
+		lastAccess: stat?.newestEntry ?? 0,
+	};
+}
+
 function MonitorOverlay() {
 	const tokenData = useFloydStore(selectTokenUsage);
 	const toolData = useFloydStore(selectToolPerformance);
@@ -134,6 +239,13 @@ function MonitorOverlay() {
 	const productivityData = useFloydStore(selectProductivity);
 	const responseTimeData = useFloydStore(selectResponseTimes);
 	const costData = useFloydStore(selectCosts);
+	const [progressEntries, setProgressEntries] = useState<ProgressEntry[]>([]);
+	const [cacheStats, setCacheStats] = useState<CacheStats[]>([]);
+
+	useEffect(() => {
+		setProgressEntries(readProgress());
+		new CacheManager(process.cwd()).getStats().then(setCacheStats);
+	}, []);
 
 	// Handle keyboard input for closing the overlay
 	useInput((input, key) => {
@@ -174,10 +286,10 @@ function MonitorOverlay() {
 
 				<Box flexDirection="column" gap={1}>
 					<MemoryDashboard
-						projectCache={{ name: 'Project', entries: 0, sizeBytes: 0, hits: 0, misses: 0, lastAccess: Date.now() }}
-						reasoningCache={{ name: 'Reasoning', entries: 0, sizeBytes: 0, hits: 0, misses: 0, lastAccess: Date.now() }}
-						vaultCache={{ name: 'Vault', entries: 0, sizeBytes: 0, hits: 0, misses: 0, lastAccess: Date.now() }}
-						totalMemoryMB={0}
+						projectCache={toCacheData(cacheStats, 'project', 'Project')}
+						reasoningCache={toCacheData(cacheStats, 'reasoning', 'Reasoning')}
+						vaultCache={toCacheData(cacheStats, 'vault', 'Vault')}
+						totalMemoryMB={process.memoryUsage().rss / (1024 * 1024)}
 					/>
 					<ResponseTimeDashboard data={responseTimeData} />
 					<CostAnalysisDashboard data={costData} />
@@ -245,6 +357,42 @@ function MonitorOverlay() {
 					data={[]}
 				/>
 			</Box>
+
+			<ProgressLogTable entries={progressEntries} height={8} focus />
+		</Box>
+	);
+}
+
+// ============================================================================
+// PROGRESS OVERLAY COMPONENT
+// ============================================================================
+
+function ProgressOverlay({ query, onClose }: { query: ProgressQuery; onClose: () => void }) {
+	const [entries] = useState(() => readProgress());
+	const rows = Math.max(5, (process.stdout.rows || 24) - 10);
+	const width = Math.max(80, (process.stdout.columns || 80) - 2);
+
+	useInput((input, key) => {
+		if (key.escape || input === 'q') {
+			onClose();
+		}
+	});
+
+	return (
+		<Box flexDirection="column" padding={1}>
+			<Box
+				flexDirection="row"
+				justifyContent="space-between"
+				paddingX={1}
+				borderStyle="double"
+				borderColor={floydTheme.colors.borderFocus}
+			>
+				<Text bold color={floydRoles.headerTitle}>
+					FLOYD PROGRESS
+				</Text>
+				<Text dimColor>Press Esc to return</Text>
+			</Box>
+			<ProgressLogTable entries={entries} query={query} height={rows} width={width} focus />
 		</Box>
 	);
 }
@@ -264,23 +412,123 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 	const [isThinking, setIsThinking] = useState(false);
 	const [agentStatus, setAgentStatus] = useState<ThinkingStatus>('idle');
 	const [currentWhimsicalPhrase, setCurrentWhimsicalPhrase] = useState<string | null>(null);
+	// Timing of the last LLM request, shown in the status bar
+	const [requestTiming, setRequestTiming] = useState<string | null>(null);
+	// Timing of the request in flight, shown in the input footer while streaming
+	const [liveTiming, setLiveTiming] = useState<string | null>(null);
+	const liveTimingRef = useRef<LiveTiming | null>(null);
 	// Removed showHelp local state - using Zustand store
 	// Removed localMessages - using Zustand store as single source of truth
 
 	// Agent visualization state
 	const [tasks, setTasks] = useState<Task[]>([]);
 	const [toolExecutions, setToolExecutions] = useState<ToolExecution[]>([]);
+	// Live pane (Ctrl+O): the latest tool's file diff or output, and what
+	// each running tool showed when it started
+	const [livePane, setLivePane] = useState<LivePaneContent | null>(null);
+	const livePaneStartsRef = useRef(new Map<string, Promise<LivePaneContent>>());
 	const [events, setEvents] = useState<StreamEvent[]>([]);
 	// Removed showMonitor local state - using Zustand store
 	const [showAgentViz, setShowAgentViz] = useState(false);
+	// Progress log query for the /progress view (null when closed)
+	const [progressQuery, setProgressQuery] = useState<ProgressQuery | null>(null);
+	// Skills found in .floyd/skills and ~/.floyd/skills (for /skill)
+	const [skills, setSkills] = useState<SkillMetadata[]>([]);
+	// SKILL.md packs; their instructions join the system prompt while relevant
+	const [skillPacks, setSkillPacks] = useState<SkillPack[]>([]);
+	const skillPacksRef = useRef<SkillPack[]>([]);
+	// Packs switched on with /skill enable stay in the prompt until disabled
+	const enabledSkillsRef = useRef(new Set<string>());
+	// Without an API key the model is never called; /run and /read still work
+	const offlineReason = useMemo(() => getOfflineReason(process.env, resolveApiKey()), []);
+
+	// Slash commands typed into the input; handlers are read through a ref so
+	// the registry (and the input's completion popup) stays stable
+	const slashHandlersRef = useRef<AppCommandHandlers | null>(null);
+	const slashCommands = useMemo(() => createAppCommandRegistry(() => slashHandlersRef.current!), []);
+
+	// Terminals send an empty paste when the clipboard holds an image
+	useEffect(() => getPasteStore().onEmptyPaste(() => void slashHandlersRef.current?.pasteImage([])), []);
+
+	// Workspace files for @mention completion, re-listed after each run since
+	// the agent may have created files
+	const [mentionFiles, setMentionFiles] = useState<string[]>([]);
+	const refreshMentionFiles = useCallback(() => {
+		listWorkspaceFiles(process.cwd())
+			.then(setMentionFiles)
+			.catch(error => getLogger().warn('Failed to list workspace files', {error: String(error)}));
+	}, []);
+	useEffect(() => {
+		refreshMentionFiles();
+	}, [refreshMentionFiles]);
+
+	// Images queued with /attach, sent with the next message
+	const pendingImagesRef = useRef<ImageAttachment[]>([]);
+	// Last message sent to the agent, as typed, for /retry
+	const lastRunRef = useRef<{value: string; images: ImageAttachment[]} | null>(null);
+	// Prompt loaded into the input by /edit; sending it replays from there
+	const [editingPrompt, setEditingPrompt] = useState<{number: number; text: string} | null>(null);
+	const editingPromptRef = useRef(editingPrompt);
+	editingPromptRef.current = editingPrompt;
+	// Files and directories pinned with Ctrl+F, read and sent with every message
+	const [pinnedPaths, setPinnedPaths] = useState<string[]>([]);
+	const pinnedPathsRef = useRef(pinnedPaths);
+	pinnedPathsRef.current = pinnedPaths;
+
+	// Tool results shown in full (Enter on a focused block, or /expand <n>)
+	const [expandedToolIds, setExpandedToolIds] = useState<ReadonlySet<string>>(new Set());
+	const toggleToolExpanded = useCallback((id: string) => {
+		setExpandedToolIds(prev => {
+			const next = new Set(prev);
+			if (!next.delete(id)) {
+				next.add(id);
+			}
+			return next;
+		});
+	}, []);
+
+	// Theme from ~/.floyd/themes/ (FLOYD_THEME picks one); the palette is
+	// applied to the shared theme objects, so a change only needs a re-render
+	const [, setThemeVersion] = useState(0);
+	useEffect(() => {
+		const themes = getThemeManager();
+		for (const problem of themes.errors) {
+			getLogger().warn('Theme problem', {problem});
+		}
+		return themes.onChange(() => setThemeVersion(version => version + 1));
+	}, []);
+
+	// Interrupt-and-steer: a message sent while the agent is busy stops the
+	// current reply and is sent as soon as the run has wound down
+	const interruptRunRef = useRef<(() => void) | null>(null);
+	const steeringRef = useRef<string | null>(null);
+	// Set by /cancel and Esc: the reply stops the same way, but nothing follows it
+	const cancelRequestedRef = useRef(false);
+
+	// Stop the current reply and keep what it wrote so far (false when idle)
+	const cancelRun = useCallback(() => {
+		if (!interruptRunRef.current) {
+			return false;
+		}
+		steeringRef.current = null;
+		cancelRequestedRef.current = true;
+		interruptRunRef.current();
+		return true;
+	}, []);
 
 	// Refs for engine instances
 	const engineRef = useRef<AgentEngine | null>(null);
 	const permissionManagerRef = useRef<PermissionManager | null>(null);
+	// Pre-warmed spare engine for instant new sessions
+	const sessionWarmerRef = useRef<SessionWarmer<AgentEngine> | null>(null);
 
 	// Permission state
 	const [permissionRequest, setPermissionRequest] = useState<PermissionRequest | null>(null);
 
+	// ask_user state - the run is paused until the pending question is answered
+	const [pendingQuestion, setPendingQuestion] = useState<AskUserQuestion | null>(null);
+	const askUserResolveRef = useRef<((answer: string) => void) | null>(null);
+
 	// Zustand store selectors - stable references to prevent infinite re-render loops
 	// NOTE: Use stable selectors for values, useCallback for actions to prevent infinite re-render loops
 	const selectMessages = useCallback((state: any) => state.messages, []);
@@ -294,6 +542,7 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 	const selectToggleSafetyMode = useCallback((state: any) => state.toggleSafetyMode, []);
 	const selectShowHelp = useCallback((state: any) => state.showHelp, []);
 	const selectShowMonitor = useCallback((state: any) => state.showMonitor, []);
+	const selectShowToolPlayground = useCallback((state: any) => state.showToolPlayground, []);
 
 	const storeMessages = useFloydStore(selectMessages);
 	const streamingContent = useFloydStore(selectStreamingContent);
@@ -306,6 +555,7 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 	const toggleSafetyMode = useFloydStore(selectToggleSafetyMode);
 	const showHelp = useFloydStore(selectShowHelp);
 	const showMonitor = useFloydStore(selectShowMonitor);
+	const showToolPlayground = useFloydStore(selectShowToolPlayground);
 
 	// Overlay state setters
 	const setShowHelp = useCallback((value: boolean) => {
@@ -321,6 +571,28 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 		useFloydStore.getState().toggleOverlay('showMonitor');
 	}, []);
 
+	// Tool playground bridges to the engine
+	const playgroundListTools = useCallback(async () => {
+		return engineRef.current ? engineRef.current.listTools() : [];
+	}, []);
+	const playgroundCallTool = useCallback(async (toolName: string, input: Record<string, unknown>) => {
+		if (!engineRef.current) {
+			throw new Error('Agent engine not initialized');
+		}
+		return engineRef.current.callTool(toolName, input);
+	}, []);
+	const playgroundInject = useCallback((toolName: string, input: Record<string, unknown>, output: string) => {
+		const content = `[Tool playground] ${toolName}(${JSON.stringify(input)}) returned:\n\n${output}`;
+		// Add to the engine history so the model sees it on the next turn
+		engineRef.current?.history.push({ role: 'user', content });
+		useFloydStore.getState().addMessage({
+			id: `system-${Date.now()}`,
+			role: 'system',
+			content: `[OK] Injected ${toolName} result into conversation context`,
+			timestamp: Date.now(),
+		});
+	}, []);
+
 	// Permission response handler
 	const handlePermissionResponse = useCallback((response: PermissionResponse) => {
 		if (permissionManagerRef.current) {
@@ -353,7 +625,14 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 		const init = async () => {
 			try {
 				// Initialize shared MCPClientManager with built-in servers
-				const mcpManager = new MCPClientManager(BUILTIN_SERVERS);
+				const mcpManager = new InteractiveMCPClientManager(BUILTIN_SERVERS);
+				mcpManager.setAskUserHandler(
+					question =>
+						new Promise<string>(resolve => {
+							askUserResolveRef.current = resolve;
+							setPendingQuestion(question);
+						}),
+				);
 
 				if (chrome) {
 					await mcpManager.startServer(3000); // Default port for Chrome bridge
@@ -371,33 +650,43 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 				await mcpManager.connectExternalServers(process.cwd());
 
 				// Support both FLOYD_GLM_* and GLM_* env var formats
-				const apiKey = process.env['FLOYD_GLM_API_KEY'] || process.env['GLM_API_KEY'] || 'dummy-key';
+				// Keys stored with /auth login are used when the variables are unset
+				const apiKey = resolveApiKey() || 'dummy-key';
 				// Fixed endpoint: Use api.z.ai/api/anthropic (Anthropic-compatible format)
-				const apiEndpoint = process.env['FLOYD_GLM_ENDPOINT'] || process.env['GLM_ENDPOINT'] || 'https://api.z.ai/api/anthropic';
+				const apiEndpoint = apiEndpointFromEnv();
 				const apiModel = process.env['FLOYD_GLM_MODEL'] || process.env['GLM_MODEL'] || 'claude-sonnet-4-20250514';
 
-				if (!process.env['FLOYD_GLM_API_KEY'] && !process.env['GLM_API_KEY']) {
-					// We warn but continue with dummy for UI testing if requested
-					// or we could block.
-				}
 
-				engineRef.current = new AgentEngine(
-					{
-						apiKey,
-						baseURL: apiEndpoint,
-						model: apiModel,
-						enableThinkingMode: true,
-						temperature: 0.2,
-					},
-					mcpManager,
-					sessionManager,
-					permissionManager,
-					config,
-				);
-				await engineRef.current.initSession(process.cwd());
+				// Engines share the MCP servers, session store and permissions,
+				// so a new session only needs a fresh engine on top of them
+				const createEngine = async () => {
+					const engine = new AgentEngine(
+						{
+							apiKey,
+							baseURL: apiEndpoint,
+							model: apiModel,
+							enableThinkingMode: true,
+							temperature: 0.2,
+							...config.request,
+							onStreamingFallback: event =>
+								getLogger().warn(`streaming.${event.type}`, {...event}),
+						},
+						mcpManager,
+						sessionManager,
+						permissionManager,
+						config,
+					);
+					await engine.initSession(process.cwd());
+					return engine;
+				};
+
+				engineRef.current = await createEngine();
 
 				setAgentStatus('idle');
 
+				sessionWarmerRef.current = new SessionWarmer(createEngine);
+				sessionWarmerRef.current.warm();
+
 				// Initialize Zustand store session
 				useFloydStore
 					.getState()
@@ -414,8 +703,18 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 				};
 				// Use getState() directly to avoid including addMessage in dependencies
 				useFloydStore.getState().addMessage(greeting);
+				const offline = getOfflineReason(process.env, resolveApiKey());
+				if (offline) {
+					useFloydStore.getState().addMessage({
+						id: `system-offline-${Date.now()}`,
+						role: 'system',
+						content: `${describeOffline(offline)}\n${OFFLINE_HINT}`,
+						timestamp: Date.now(),
+					});
+				}
 				// Removed setLocalMessages - UI reads from Zustand store
-			} catch {
+			} catch (error) {
+				getLogger().error('Agent initialization failed', {error: String(error)});
 				setAgentStatus('error');
 			}
 		};
@@ -423,13 +722,140 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 		// Only depend on chrome - addMessage is accessed via getState()
 	}, [chrome]);
 
+	// ============================================================================
+	// TRANSCRIPT EXPORT
+	// ============================================================================
+
+	const exportConversation = useCallback(
+		(format: TranscriptFormat) => {
+			exportTranscript(useFloydStore.getState().messages, {format}, process.cwd())
+				.then(filePath => {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: `[OK] Transcript exported to ${filePath}`,
+						timestamp: Date.now(),
+					});
+				})
+				.catch(error => {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: `[!] Transcript export failed: ${error instanceof Error ? error.message : String(error)}`,
+						timestamp: Date.now(),
+					});
+				});
+		},
+		[addMessage],
+	);
+
+	// ============================================================================
+	// REQUEST TIMING
+	// ============================================================================
+
+	// Timings go to the log file, the monitor's response time panel and the
+	// status bar, so slow runs can be traced to the network or the model
+	const handleTiming = useCallback((event: TimingEvent) => {
+		// Completions are the useful summary; the intermediate events are for debugging
+		if (event.type === 'request_complete' || event.type === 'tool_complete') {
+			getLogger().info(`timing.${event.type}`, event);
+		} else {
+			getLogger().debug(`timing.${event.type}`, event);
+		}
+
+		const store = useFloydStore.getState();
+		if (event.type === 'request_sent') {
+			liveTimingRef.current = startLiveTiming(event.at);
+			setLiveTiming(formatLiveTiming(liveTimingRef.current, event.at));
+		} else if (event.type === 'first_token') {
+			if (liveTimingRef.current) {
+				liveTimingRef.current.firstTokenAt = event.at;
+				liveTimingRef.current.lastTokenAt = event.at;
+				setLiveTiming(formatLiveTiming(liveTimingRef.current, event.at));
+			}
+		} else if (event.type === 'request_complete') {
+			liveTimingRef.current = null;
+			setLiveTiming(null);
+			store.recordResponseTime(event.durationMs, event.firstTokenMs);
+			if (event.inputTokens !== undefined && event.outputTokens !== undefined) {
+				store.recordTokenUsage(event.inputTokens, event.outputTokens);
+			}
+			setRequestTiming(formatRequestTiming(event));
+		} else if (event.type === 'tool_complete') {
+			store.recordToolCall(event.tool, event.durationMs, event.success);
+		}
+	}, []);
+
+	// Streamed text feeds the live rate and tells a slow model from a stalled one
+	const handleChunk = useCallback((chunk: string) => {
+		const live = liveTimingRef.current;
+		if (live) {
+			live.chars += chunk.length;
+			live.lastTokenAt = Date.now();
+		}
+	}, []);
+
+	// Refresh the live timing once a second so elapsed time keeps moving
+	// while nothing arrives
+	useEffect(() => {
+		if (!isThinking) {
+			liveTimingRef.current = null;
+			setLiveTiming(null);
+			return;
+		}
+		const timer = setInterval(() => {
+			if (liveTimingRef.current) {
+				setLiveTiming(formatLiveTiming(liveTimingRef.current));
+			}
+		}, 1000);
+		return () => clearInterval(timer);
+	}, [isThinking]);
+
 	// ============================================================================
 	// MESSAGE SUBMISSION
 	// ============================================================================
 
 	const handleSubmit = useCallback(
-		async (value: string) => {
-			if (!value.trim() || isThinking) return;
+		async (value: string, overrides?: RequestOverrides) => {
+			if (!value.trim()) return;
+
+			// Slash commands are defined in commands/app-commands.ts
+			const [head, ...rest] = value.trim().split(/\s+/);
+
+			if (isThinking) {
+				if (head === '/cancel') {
+					cancelRun();
+					return;
+				}
+				if (head.startsWith('/')) {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: '[!] Commands wait until the agent finishes. Send plain text to steer it, or /cancel to stop it.',
+						timestamp: Date.now(),
+					});
+					return;
+				}
+				// Latest instruction wins if several arrive before the run stops
+				steeringRef.current = value;
+				interruptRunRef.current?.();
+				return;
+			}
+
+			const slashCommand = head.startsWith('/') ? slashCommands.get(head.slice(1)) : undefined;
+			if (slashCommand) {
+				await slashCommand.handler(rest, {args: rest, input: value});
+				return;
+			}
+			if (looksLikeSlashCommand(value)) {
+				addMessage({
+					id: `system-${Date.now()}`,
+					role: 'system',
+					content: formatUnknownCommand(head.slice(1), slashCommands),
+					timestamp: Date.now(),
+				});
+				return;
+			}
 
 			// Check for dock commands (e.g., ":dock btop" or ":btop")
 			const dockArgs = parseDockArgs(value.trim().split(/\s+/));
@@ -467,172 +893,249 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 				return;
 			}
 
-			if (!engineRef.current) return;
-
-			// Add user message
-			const userMsg: Message = { role: 'user', content: value };
-			const userMsgStore: ConversationMessage = {
-				id: `user-${Date.now()}`,
-				role: 'user',
-				content: value,
-				timestamp: Date.now(),
-			};
+			if (offlineReason) {
+				addMessage({
+					id: `system-${Date.now()}`,
+					role: 'system',
+					content: `${describeOffline(offlineReason)}\n${OFFLINE_HINT}`,
+					timestamp: Date.now(),
+				});
+				return;
+			}
 
-			// Single source of truth - only update store, not localMessages
-			addMessage(userMsgStore);
+			const engine = engineRef.current;
+			if (!engine) return;
+
+			// An edited prompt replaces the original and everything after it
+			const editing = editingPromptRef.current;
+			if (editing) {
+				editingPromptRef.current = null;
+				setEditingPrompt(null);
+				const {messages, removeMessage} = useFloydStore.getState();
+				const range = findPrompt(messages, editing.number);
+				if (range) {
+					await engine.rewindTo(range.fromEnd);
+					for (const message of messages.slice(range.start)) {
+						removeMessage(message.id);
+					}
+				}
+			}
 
-			setIsThinking(true);
-			setAgentStatus('thinking');
-			setAgentStoreStatus('thinking');
+			const images = pendingImagesRef.current;
+			pendingImagesRef.current = [];
+			lastRunRef.current = {value, images};
+
+			// Only skills relevant to this request (or enabled) are in the prompt
+			const system = engine.history[0];
+			if (system?.role === 'system' && typeof system.content === 'string') {
+				const activeSkills = selectSkillPacks(skillPacksRef.current, value, enabledSkillsRef.current);
+				system.content = applySkillPrompt(system.content, buildSkillPrompt(activeSkills));
+				if (activeSkills.length > 0) {
+					getLogger().info('Skills in prompt', {skills: activeSkills.map(pack => pack.id)});
+				}
+			}
 
-			// Show whimsical phrase while thinking (stored for status bar, NOT added to conversation)
-			const whimsicalPhrase = getRandomWhimsicalPhrase();
-			setCurrentWhimsicalPhrase(whimsicalPhrase.text);
-			setAgentStoreStatus('thinking');
+			// The run is a sequence of RunEvents; the reducer decides the state
+			// and this loop only applies the resulting updates
+			let runState = initialRunState();
+			const dispatch = (event: RunEvent) => {
+				const next = reduceRunEvent(runState, event);
+				for (const effect of diffRunState(runState, next)) {
+					switch (effect.type) {
+						case 'add_message':
+							addMessage(effect.message);
+							break;
+						case 'update_message':
+							updateMessage(effect.id, effect.updates);
+							break;
+						case 'append_streaming':
+							appendStreamingContent(effect.text);
+							break;
+						case 'clear_streaming':
+							clearStreamingContent();
+							break;
+						case 'set_busy':
+							setIsThinking(effect.busy);
+							break;
+						case 'set_status':
+							setAgentStatus(effect.status);
+							if (effect.status !== 'error' && effect.status !== 'complete') {
+								setAgentStoreStatus(effect.status);
+							}
+							break;
+						case 'set_phrase':
+							// Shown in the status bar, NOT added to the conversation
+							setCurrentWhimsicalPhrase(effect.phrase);
+							break;
+					}
+				}
+				runState = next;
+			};
 
-			try {
-				const generator = engineRef.current.sendMessage(value);
-				let currentAssistantMessage = '';
+			const now = Date.now();
+			dispatch({
+				type: 'submitted',
+				text: images.length > 0 ? `${formatImageMarkers(images)}\n${value}` : value,
+				userMessageId: `user-${now}`,
+				assistantMessageId: `assistant-${now}`,
+				phrase: getRandomWhimsicalPhrase().text,
+				at: now,
+			});
 
-				// Add placeholder for assistant message
-				const assistantMsgId = `assistant-${Date.now()}`;
-				const assistantPlaceholder: ConversationMessage = {
-					id: assistantMsgId,
-					role: 'assistant',
-					content: '',
-					timestamp: Date.now(),
-					streaming: true,
-				};
-				addMessage(assistantPlaceholder);
-				// Removed setLocalMessages - UI reads from Zustand store via messages prop
+			// Uncommitted changes before the run, to tell which files it touched
+			const changesBefore = await snapshotWorkingTree().catch(() => null);
 
-				clearStreamingContent();
+			try {
+				// Files mentioned with @path are sent along so the model need not read them
+				const attachments = await buildMentionAttachments(value, process.cwd());
+				if (attachments.attached.length > 0) {
+					getLogger().info('Attached mentioned files', {files: attachments.attached});
+				}
+				// Pinned files are re-read for every message, so edits show up
+				const pinned = await buildPinnedContext(pinnedPathsRef.current);
+				if (pinned.truncated) {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: `[!] Pinned context is limited to ${pinned.files.length} files; /unpin some to choose which.`,
+						timestamp: Date.now(),
+					});
+				}
+				// The transcript keeps "[pasted N lines]"; the model gets the paste
+				const message = getPasteStore().expand(value);
+				const generator = engine.sendMessage(
+					[message, pinned.text, attachments.text].filter(Boolean).join('\n\n'),
+					{
+						onTiming: handleTiming,
+						onChunk: handleChunk,
+						onThinking: text => dispatch({type: 'thinking', text}),
+						onToolStart: toolCall => {
+							dispatch({type: 'tool_started', id: toolCall.id, name: toolCall.name, at: Date.now()});
+							const started = livePaneForToolStart(toolCall);
+							livePaneStartsRef.current.set(toolCall.id, started);
+							void started.then(setLivePane);
+						},
+						onToolComplete: toolCall => {
+							dispatch({
+								type: 'tool_finished',
+								id: toolCall.id,
+								output: toolCall.output,
+								error: toolCall.error,
+								at: Date.now(),
+							});
+							const started = livePaneStartsRef.current.get(toolCall.id) ?? Promise.resolve(null);
+							livePaneStartsRef.current.delete(toolCall.id);
+							void started.then(content => livePaneForToolEnd(toolCall, content)).then(setLivePane);
+						},
+					},
+					images.map(({mediaType, data}) => ({mediaType, data})),
+					overrides,
+				);
 
 				// Create stream processor with throttling
 				const streamProcessor = new StreamProcessor({
 					rateLimitEnabled: true,
 					maxTokensPerSecond: 1000, // Increased for performance
-					flushInterval: 33, // 30fps for smooth updates
+					flushInterval: STREAM_FLUSH_INTERVAL, // 30fps, slower batches in minimal mode
 					maxBufferSize: 65536, // Increased buffer
 				});
 
 				// Create tag parser for thinking blocks
 				const tagParser = new StreamTagParser(['thinking']);
 
-				// DEFINE THESE VARIABLES HERE (Fixes Error 1 & 2)
-				let inThinkingBlock = false;
-
-				// Set up stream processor event handlers
 				streamProcessor.on('data', (data: string) => {
-					currentAssistantMessage += data;
-					appendStreamingContent(data);
-
-					// Update the existing assistant message in store (don't add new ones)
-					// Single source of truth - UI reads from Zustand store
-					updateMessage(assistantMsgId, {
-						content: currentAssistantMessage,
-						streaming: true,
-					});
-				});
-
-				streamProcessor.on('end', () => {
-					// Stream complete
+					dispatch({type: 'text', text: data});
 				});
 
 				streamProcessor.on('error', (error: Error) => {
 					throw error;
 				});
 
+				// Stepped by hand so a steering message can stop the stream
+				// between chunks
+				const iterator = generator[Symbol.asyncIterator]();
+				let interrupted = false as boolean;
+				const interruption = new Promise<null>(resolve => {
+					interruptRunRef.current = () => {
+						interrupted = true;
+						resolve(null);
+					};
+				});
+
 				// Process generator through stream processor
-				for await (const chunk of generator) {
+				while (true) {
+					const step = iterator.next();
+					// A step abandoned by an interruption may still fail later
+					step.catch(() => {});
+					const next = await Promise.race([step, interruption]);
+					if (!next || next.done) break;
+					const chunk = next.value;
 					// Process chunk through tag parser to handle split tokens
 					for (const event of tagParser.process(chunk)) {
 						if (event.type === 'tag_open' && event.tagName === 'thinking') {
-							inThinkingBlock = true;
-							setAgentStatus('thinking');
-							setAgentStoreStatus('thinking');
 							// Show new whimsical phrase for this thinking block
-							const newPhrase = getRandomWhimsicalPhrase();
-							setCurrentWhimsicalPhrase(newPhrase.text);
-							continue;
-						}
-
-						if (event.type === 'tag_close' && event.tagName === 'thinking') {
-							inThinkingBlock = false;
+							dispatch({type: 'thinking_started', phrase: getRandomWhimsicalPhrase().text});
+						} else if (event.type === 'tag_close' && event.tagName === 'thinking') {
 							// Add pause after thinking completes (800ms)
 							await new Promise(resolve => setTimeout(resolve, 800));
-							setAgentStatus('streaming');
-							setAgentStoreStatus('streaming');
-							setCurrentWhimsicalPhrase(null); // Clear phrase when streaming starts
-							continue;
-						}
-
-						if (event.type === 'text' && event.content) {
-							// Handle thinking content separately
-							if (inThinkingBlock) {
-								// Thinking content is already dimmed by orchestrator
-								// Just update thinking state, don't add to main message
-								continue;
-							}
-
+							dispatch({type: 'thinking_ended'});
+						} else if (event.type === 'text' && event.content && !runState.inThinkingBlock) {
 							// Regular content - process through stream processor for throttling
 							streamProcessor.processChunk({
 								text: event.content,
 								type: 'text',
 							});
+						} else if (event.type === 'text' && event.content) {
+							// Inside <thinking>: shown in the collapsible thinking block
+							dispatch({type: 'thinking', text: event.content});
 						}
 					}
 				}
 
+				const reason = cancelRequestedRef.current ? 'cancelled' : 'interrupted';
+				if (interrupted) {
+					// Lets the engine finish the step in flight (closing the LLM
+					// stream or waiting for a running tool), then keeps the partial
+					// reply in its history
+					await iterator.return(undefined);
+					await engine.recordInterruption(reason);
+				}
+
 				// Complete the stream processor
 				streamProcessor.complete();
 
-				// Finalize the message
-				clearStreamingContent();
-				updateMessage(assistantMsgId, {
-					content: currentAssistantMessage,
-					streaming: false,
-					timestamp: Date.now(),
-				});
+				dispatch(interrupted ? {type: 'interrupted', at: Date.now(), reason} : {type: 'completed', at: Date.now()});
+				if (interrupted && reason === 'cancelled') {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: 'Cancelled. The partial reply is kept; send "keep going" to continue it.',
+						timestamp: Date.now(),
+					});
+				}
 			} catch (error: unknown) {
-				// Extract detailed error information
-				let errorMessage = 'An unexpected error occurred';
-				let errorDetails = '';
-
-				if (error instanceof Error) {
-					errorMessage = error.message;
-					if (error.stack) {
-						// Get first few lines of stack for debugging
-						errorDetails = error.stack.split('\n').slice(0, 3).join('\n');
-					}
-					// Check for common API errors
-					if (errorMessage.includes('fetch') || errorMessage.includes('network')) {
-						errorDetails = 'Network error - check your internet connection and API endpoint';
-					} else if (errorMessage.includes('401') || errorMessage.includes('Unauthorized')) {
-						errorDetails = 'API authentication failed - check your API key';
-					} else if (errorMessage.includes('429')) {
-						errorDetails = 'Rate limit exceeded - please wait and try again';
-					} else if (errorMessage.includes('500') || errorMessage.includes('502') || errorMessage.includes('503')) {
-						errorDetails = 'API server error - the service may be temporarily unavailable';
-					}
-				} else {
-					errorMessage = String(error);
+				const at = Date.now();
+				dispatch({type: 'failed', ...describeRunError(error), errorMessageId: `error-${at}`, at});
+			} finally {
+				interruptRunRef.current = null;
+				cancelRequestedRef.current = false;
+				dispatch({type: 'finished'});
+				refreshMentionFiles();
+
+				// Diffstat of the files the run changed (also logged to progress.md)
+				const diffStat = await summarizeRunChanges(changesBefore, value).catch(error => {
+					getLogger().warn('Run change summary failed', {error: String(error)});
+					return null;
+				});
+				if (diffStat) {
+					addMessage({id: `changes-${Date.now()}`, role: 'system', content: diffStat, timestamp: Date.now()});
 				}
 
-				const errorMsg: ConversationMessage = {
-					id: `error-${Date.now()}`,
-					role: 'assistant',
-					content: `[!] Error: ${errorMessage}${errorDetails ? `\n\n${errorDetails}` : ''}`,
-					timestamp: Date.now(),
-				};
-				addMessage(errorMsg);
-				// Removed setLocalMessages - UI reads from Zustand store
-				setAgentStatus('error');
-			} finally {
-				setIsThinking(false);
-				setAgentStatus('idle');
-				setAgentStoreStatus('idle');
-				setCurrentWhimsicalPhrase(null);
+				const steering = steeringRef.current;
+				steeringRef.current = null;
+				if (steering) {
+					void handleSubmit(steering);
+				}
 			}
 		},
 		[
@@ -642,6 +1145,12 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 			appendStreamingContent,
 			clearStreamingContent,
 			setAgentStoreStatus,
+			slashCommands,
+			handleTiming,
+			handleChunk,
+			cancelRun,
+			refreshMentionFiles,
+			offlineReason,
 		],
 	);
 
@@ -679,7 +1188,7 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 	// Use store messages as single source of truth (convert to ChatMessage format)
 	// Memoized to prevent infinite re-render loop in MainLayout
 	const allMessages: ChatMessage[] = useMemo(
-		() => storeMessages.map(toChatMessage),
+		() => numberPrompts(numberToolCalls(storeMessages.map(toChatMessage))),
 		[storeMessages]
 	);
 
@@ -695,8 +1204,19 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 					break;
 				case 'new-task':
 				case 'reset-session':
+					pendingImagesRef.current = [];
+					// Swap in the pre-warmed spare engine so the old history is dropped
+					sessionWarmerRef.current
+						?.take()
+						.then(engine => {
+							engineRef.current = engine;
+						})
+						.catch(error => {
+							getLogger().error('Failed to start new session', {error: String(error)});
+						});
 					setTasks([]);
 					setToolExecutions([]);
+					setLivePane(null);
 					setEvents([]);
 					useFloydStore.getState().clearMessages();
 					useFloydStore.getState().clearStreamingContent();
@@ -711,17 +1231,13 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 					useFloydStore.getState().toggleSafetyMode();
 					break;
 				case 'export-transcript':
-					// Logic to save allMessages to a file
-					const fs = require('node:fs');
-					const transcriptPath = `transcript-${Date.now()}.md`;
-					const transcriptContent = allMessages.map(m => `[${m.role.toUpperCase()}] ${m.content}`).join('\n\n');
-					fs.writeFileSync(transcriptPath, transcriptContent);
-					addMessage({
-						id: `system-${Date.now()}`,
-						role: 'system',
-						content: `[OK] Transcript exported to ${transcriptPath}`,
-						timestamp: Date.now(),
-					});
+					exportConversation('md');
+					break;
+				case 'export-transcript-html':
+					exportConversation('html');
+					break;
+				case 'tool-playground':
+					useFloydStore.getState().setOverlay('showToolPlayground', true);
 					break;
 				case 'help':
 					// Toggle help overlay using store state
@@ -757,6 +1273,9 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 						}
 					}
 					break;
+				case 'continue-plan':
+					void slashCommands.get('continue')?.handler([], {args: []});
+					break;
 				case 'explain':
 					// Request explanation of last response
 					if (allMessages.length > 0) {
@@ -837,9 +1356,454 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 					break;
 			}
 		},
-		[exit, toggleHelp, allMessages, addMessage, handleSubmit],
+		[exit, toggleHelp, allMessages, addMessage, handleSubmit, exportConversation, slashCommands],
 	);
 
+	// ============================================================================
+	// SLASH COMMANDS
+	// ============================================================================
+
+	useEffect(() => {
+		getSkillRegistry()
+			.discover()
+			.then(result => setSkills(result.skills))
+			.catch(error => getLogger().warn('Skill discovery failed', {error: String(error)}));
+		discoverSkillPacks(process.cwd())
+			.then(packs => {
+				skillPacksRef.current = packs;
+				setSkillPacks(packs);
+			})
+			.catch(error => getLogger().warn('Skill pack discovery failed', {error: String(error)}));
+	}, []);
+
+	const addSystemMessage = (content: string) =>
+		addMessage({id: `system-${Date.now()}`, role: 'system', content, timestamp: Date.now()});
+
+	const promptUsage = (command: string) => {
+		const count = storeMessages.filter(message => message.role === 'user').length;
+		return count === 0
+			? '[!] No prompts in this conversation yet.'
+			: `[!] Usage: /${command} <n> with n from 1 to ${count} (the #n after "User")`;
+	};
+
+	// /edit <n> and Ctrl+E put prompt n into the input; sending it replays
+	// the conversation from that point
+	const editPrompt = (number: number) => {
+		const range = findPrompt(storeMessages, number);
+		const prompt = range ? storeMessages[range.start] : undefined;
+		if (!prompt || typeof prompt.content !== 'string') {
+			addSystemMessage(promptUsage('edit'));
+			return;
+		}
+		setEditingPrompt({number, text: prompt.content});
+	};
+
+	// /delete <n> and Ctrl+E, Del drop prompt n and its reply from the
+	// conversation and the session
+	const deletePrompt = async (number: number) => {
+		const range = findPrompt(storeMessages, number);
+		if (!range) {
+			addSystemMessage(promptUsage('delete'));
+			return;
+		}
+		await engineRef.current?.deleteTurn(range.fromEnd);
+		const removeMessage = useFloydStore.getState().removeMessage;
+		for (const message of storeMessages.slice(range.start, range.end)) {
+			removeMessage(message.id);
+		}
+		if (editingPromptRef.current) {
+			setEditingPrompt(null);
+		}
+	};
+
+	slashHandlersRef.current = {
+		print: addSystemMessage,
+		newSession: () => handleCommand('new-task'),
+		cancel: () => {
+			if (!cancelRun()) {
+				addSystemMessage('Nothing to cancel.');
+			}
+		},
+		edit: args => editPrompt(parseInt(args[0] ?? '', 10)),
+		delete: args => deletePrompt(parseInt(args[0] ?? '', 10)),
+		promptCount: () => storeMessages.filter(message => message.role === 'user').length,
+		// /set [setting value] shows or changes the request settings until the next session
+		set: args => {
+			const engine = engineRef.current;
+			if (!engine) {
+				addSystemMessage('[!] The agent is not ready yet.');
+				return;
+			}
+			if (args.length === 0) {
+				addSystemMessage(`Request settings: ${formatRequestParams(engine.getRequestParams())}`);
+				return;
+			}
+			const parsed = parseSetArgs(args);
+			if ('error' in parsed) {
+				addSystemMessage(parsed.error);
+				return;
+			}
+			engine.setRequestParams(parsed.params);
+			addSystemMessage(`Request settings: ${formatRequestParams(engine.getRequestParams())}`);
+		},
+		// /retry [--temp N] [--model M] drops the last reply and sends its prompt again
+		retry: async args => {
+			const parsed = parseRetryArgs(args);
+			if ('error' in parsed) {
+				addSystemMessage(parsed.error);
+				return;
+			}
+			const lastRun = lastRunRef.current;
+			const engine = engineRef.current;
+			if (!lastRun || !engine || !(await engine.rewindLastTurn())) {
+				addSystemMessage('[!] Nothing to retry yet.');
+				return;
+			}
+
+			// The transcript loses the prompt and everything after it; the
+			// re-sent prompt adds itself back
+			const {messages, removeMessage} = useFloydStore.getState();
+			const lastPrompt = messages.map(message => message.role).lastIndexOf('user');
+			for (const message of lastPrompt === -1 ? [] : messages.slice(lastPrompt)) {
+				removeMessage(message.id);
+			}
+
+			pendingImagesRef.current = lastRun.images;
+			await handleSubmit(lastRun.value, parsed.overrides);
+		},
+		monitor: toggleMonitor,
+		// /progress [count] [--date YYYY-MM-DD] [text] opens the progress log table
+		progress: args => setProgressQuery(parseProgressArgs(args)),
+		// /export [md|html] writes the conversation to .floyd/exports/
+		export: args => {
+			const format = parseTranscriptFormat(args[0]);
+			if (format) {
+				exportConversation(format);
+			} else {
+				addSystemMessage(`[!] Unknown export format "${args[0]}". Use md or html.`);
+			}
+		},
+		// /logs [lines] [debug|info|warn|error] shows the tail of .floyd/logs/floyd.log
+		logs: async args => {
+			const filePath = getLogger().getFilePath();
+			const count = args.find(arg => /^\d+$/.test(arg));
+			const level = args.find(arg => ['debug', 'info', 'warn', 'error'].includes(arg)) as
+				| LogRecordLevel
+				| undefined;
+			const records = filePath
+				? await readLogTail(filePath, count ? parseInt(count, 10) : 30, level)
+				: [];
+			addSystemMessage(
+				!filePath
+					? '[!] File logging is off (FLOYD_LOG_FILE=off).'
+					: records.length === 0
+						? `No log records in ${filePath}`
+						: [`Logs (${filePath}):`, ...records.map(formatLogRecord)].join('\n'),
+			);
+		},
+		// /memory [prune] shows the SUPERCACHE tiers of this project
+		memory: async args => {
+			const cache = new CacheManager(process.cwd());
+			const pruned = args[0] === 'prune' ? await cache.prune() : undefined;
+			addSystemMessage(
+				[
+					...(pruned !== undefined ? [`Pruned ${pruned} expired ${pruned === 1 ? 'entry' : 'entries'}`] : []),
+					'SUPERCACHE (.floyd/.cache):',
+					...formatCacheStats(await cache.getStats()),
+					'TTL: reasoning 5m · project 24h · vault 7d',
+				].join('\n'),
+			);
+		},
+		// /stats [reset] shows where this session's tool time went
+		stats: args => {
+			const engine = engineRef.current;
+			if (!engine) {
+				addSystemMessage('[!] Agent engine not initialized');
+				return;
+			}
+			addSystemMessage(['Tool stats (this session):', ...formatToolStats(engine.getToolStats(), {histogram: true})].join('\n'));
+			if (args[0] === 'reset') {
+				engine.resetToolStats();
+			}
+		},
+		// /run <command> runs a shell command without the model
+		run: async args => {
+			const line = args.join(' ').trim();
+			if (!line) {
+				addSystemMessage('[!] Usage: /run <command>');
+				return;
+			}
+			addSystemMessage(await runShellLine(line));
+		},
+		// /read <file> [offset] [limit] shows a file without the model
+		read: async args => {
+			const [filePath, offset, limit] = args;
+			if (!filePath) {
+				addSystemMessage('[!] Usage: /read <file> [offset] [limit]');
+				return;
+			}
+			try {
+				addSystemMessage(
+					await readFileLines(filePath, offset ? parseInt(offset, 10) : 1, limit ? parseInt(limit, 10) : 200),
+				);
+			} catch (error) {
+				addSystemMessage(`[!] Cannot read ${filePath}: ${error instanceof Error ? error.message : String(error)}`);
+			}
+		},
+		// /auth [status | login <provider> <key> | test [provider] | logout <provider>]
+		auth: async args => {
+			const [action = 'status', name, key] = args;
+			const store = getCredentialStore();
+			if (action === 'status') {
+				addSystemMessage(
+					[
+						'API keys:',
+						...store
+							.status()
+							.map(({provider, source, masked}) => `  ${provider.padEnd(10)} ${source ? `${masked}  (${source})` : 'not set'}`),
+						`Stored in: ${store.backend.name}. Environment variables take precedence.`,
+					].join('\n'),
+				);
+				return;
+			}
+			const provider = parseProvider(name ?? (action === 'test' ? inferProviderFromEndpoint(apiEndpointFromEnv()) : undefined));
+			if (!provider) {
+				addSystemMessage(`[!] ${name ? `Unknown provider "${name}"` : 'Name a provider'}. Providers: zai (glm), anthropic, openai, deepseek.`);
+				return;
+			}
+			// Test against the configured endpoint when it belongs to the provider
+			const endpoint = inferProviderFromEndpoint(apiEndpointFromEnv()) === provider ? {endpoint: apiEndpointFromEnv()} : {};
+			if (action === 'login') {
+				if (!key) {
+					addSystemMessage(`[!] Usage: /auth login ${provider} <key>`);
+					return;
+				}
+				const check = await validateApiKey(provider, key, endpoint);
+				if (!check.ok) {
+					addSystemMessage(`[!] ${check.message} - key not stored`);
+					return;
+				}
+				store.set(provider, key);
+				addSystemMessage(`[OK] ${provider} key ${maskKey(key)} stored in ${store.backend.name}. Restart FLOYD to use it.`);
+			} else if (action === 'test') {
+				const stored = provider === inferProviderFromEndpoint(apiEndpointFromEnv()) ? resolveApiKey() : store.resolve(provider);
+				if (!stored) {
+					addSystemMessage(`[!] No ${provider} key. Store one with /auth login ${provider} <key>`);
+					return;
+				}
+				const check = await validateApiKey(provider, stored, endpoint);
+				addSystemMessage(`${check.ok ? '[OK]' : '[!]'} ${check.message}`);
+			} else if (action === 'logout') {
+				addSystemMessage(
+					store.delete(provider) ? `[OK] ${provider} key removed from ${store.backend.name}` : `[!] No stored ${provider} key`,
+				);
+			} else {
+				addSystemMessage(`[!] Unknown action "${action}". Use status, login, test or logout.`);
+			}
+		},
+		// /skill [name] lists skills or describes one; enable/disable pin a
+		// skill pack in the system prompt
+		skill: args => {
+			const [first, second] = args;
+			if (first === 'enable' || first === 'disable') {
+				const pack = skillPacks.find(p => p.id === second || p.name === second);
+				if (!pack) {
+					addSystemMessage(`[!] Unknown skill pack "${second ?? ''}". Type /skill to list skills.`);
+				} else if (first === 'enable') {
+					enabledSkillsRef.current.add(pack.id);
+					addSystemMessage(`[OK] ${pack.name} is in the system prompt until /skill disable ${pack.id}`);
+				} else {
+					enabledSkillsRef.current.delete(pack.id);
+					addSystemMessage(`[OK] ${pack.name} is only added when a request mentions it`);
+				}
+				return;
+			}
+
+			if (!first) {
+				const lines = [
+					...skills.map(skill => `  ${skill.id} - ${skill.description}`),
+					...skillPacks.map(pack => {
+						const state = pack.always ? ' [always]' : enabledSkillsRef.current.has(pack.id) ? ' [enabled]' : '';
+						return `  ${pack.id} - ${pack.description}${state}`;
+					}),
+				];
+				addSystemMessage(
+					lines.length === 0
+						? 'No skills found in .floyd/skills or ~/.floyd/skills'
+						: ['Skills:', ...lines].join('\n'),
+				);
+				return;
+			}
+
+			const pack = skillPacks.find(p => p.id === first || p.name === first);
+			if (pack) {
+				addSystemMessage(
+					[
+						`${pack.name} (${pack.scope} skill pack)${enabledSkillsRef.current.has(pack.id) ? ' [enabled]' : ''}`,
+						pack.description,
+						`Triggers: ${pack.always ? 'always on' : [pack.id, ...pack.triggers].join(', ')}`,
+						`Scripts: ${pack.scripts.length > 0 ? pack.scripts.join(', ') : 'none'}`,
+						`Path: ${pack.path}`,
+					].join('\n'),
+				);
+				return;
+			}
+
+			const skill = skills.find(s => s.id === first || s.name === first);
+			addSystemMessage(
+				skill
+					? [
+							`${skill.name} v${skill.version}${skill.enabled ? '' : ' (disabled)'}`,
+							skill.description,
+							`Path: ${skill.path}`,
+					  ].join('\n')
+					: `[!] Unknown skill "${first}". Type /skill to list skills.`,
+			);
+		},
+		skillNames: () => [...skills.map(skill => skill.id), ...skillPacks.map(pack => pack.id)],
+		// /attach <path...> queues PNG/JPEG images for the next message
+		attach: async args => {
+			if (args.length === 0) {
+				addSystemMessage('[!] Usage: /attach <path> [path...]');
+				return;
+			}
+			for (const filePath of args) {
+				try {
+					const image = await loadImageAttachment(filePath, process.cwd());
+					pendingImagesRef.current.push(image);
+					addSystemMessage(`[image attached] ${image.name} (${formatSize(image.size)}) will be sent with your next message`);
+				} catch (error) {
+					addSystemMessage(`[!] Could not attach ${filePath}: ${error instanceof Error ? error.message : String(error)}`);
+				}
+			}
+		},
+		// /paste-image (or pasting with an image on the clipboard) saves the
+		// image to .floyd/attachments/ and attaches it
+		pasteImage: async () => {
+			const buffer = await readClipboardImage();
+			if (!buffer) {
+				addSystemMessage(
+					'[!] No image on the clipboard (reading it needs pngpaste on macOS, or wl-paste or xclip on Linux).',
+				);
+				return;
+			}
+			try {
+				const filePath = await saveImageAttachment(buffer, process.cwd());
+				const image = await loadImageAttachment(filePath, process.cwd());
+				pendingImagesRef.current.push(image);
+				addSystemMessage(`[image attached] ${filePath} (${formatSize(image.size)}) will be sent with your next message`);
+			} catch (error) {
+				addSystemMessage(`[!] Could not attach the clipboard image: ${error instanceof Error ? error.message : String(error)}`);
+			}
+		},
+		workspaceFiles: () => mentionFiles,
+		// /unpin [path|all] removes pinned context
+		unpin: args => {
+			const before = pinnedPathsRef.current;
+			if (before.length === 0) {
+				addSystemMessage('Nothing is pinned. Ctrl+F opens the file browser to pin files.');
+				return;
+			}
+			const after = unpinPaths(before, args[0]);
+			if (after.length === before.length) {
+				addSystemMessage(`[!] ${args[0]} is not pinned. Pinned: ${before.join(', ')}`);
+				return;
+			}
+			setPinnedPaths(after);
+			addSystemMessage(
+				after.length === 0 ? 'Unpinned everything.' : `Unpinned ${args[0]}. Still pinned: ${after.join(', ')}`,
+			);
+		},
+		pinnedPaths: () => pinnedPathsRef.current,
+		// /continue [instructions] sends the plan's open items and the latest progress
+		continueWork: async args => {
+			const prompt = await readContinuationPrompt(process.cwd(), {note: args.join(' ')});
+			if (prompt) {
+				await handleSubmit(prompt);
+			} else {
+				addSystemMessage('[!] Nothing to continue: .floyd/master_plan.md has no open items and .floyd/progress.md has no entries.');
+			}
+		},
+		// /expand <n> shows the full output of the nth tool call
+		expandTool: args => {
+			const calls = listToolCalls(allMessages);
+			const call = calls[parseInt(args[0] ?? '', 10) - 1];
+			if (!call?.id) {
+				addSystemMessage(
+					calls.length === 0
+						? '[!] No tool calls in this conversation yet.'
+						: `[!] Usage: /expand <n> with n from 1 to ${calls.length}`,
+				);
+				return;
+			}
+			if (!expandedToolIds.has(call.id)) {
+				toggleToolExpanded(call.id);
+			}
+		},
+		toolCallCount: () => listToolCalls(allMessages).length,
+		// /copy [code] puts the last reply (or its last code block) on the clipboard
+		copy: async args => {
+			const wantCode = args[0] === 'code';
+			const replies = allMessages
+				.filter(message => message.role === 'assistant' && typeof message.content === 'string' && message.content.trim())
+				.map(message => message.content as string)
+				.reverse();
+			const text = wantCode
+				? replies.map(lastCodeBlock).find((block): block is string => block !== null)
+				: replies[0];
+			if (text === undefined) {
+				addSystemMessage(wantCode ? '[!] No code block in the replies so far.' : '[!] No reply to copy yet.');
+				return;
+			}
+
+			const result = await copyToClipboard(text);
+			const what = wantCode ? 'code block' : 'reply';
+			const size = `${text.split('\n').length} lines`;
+			addSystemMessage(
+				result.command
+					? `Copied ${what} (${size}) with ${result.command}`
+					: result.osc52
+						? `Sent ${what} (${size}) to the terminal clipboard (OSC 52); if nothing was copied, install pbcopy, wl-copy, xclip or xsel`
+						: `[!] Could not copy the ${what}: it is too large for OSC 52 and no clipboard tool is available`,
+			);
+		},
+		// /theme [name|reload] lists, switches or reloads themes
+		theme: args => {
+			const themes = getThemeManager();
+			if (args[0] === 'reload') {
+				const problems = themes.reload();
+				addSystemMessage(
+					[
+						`Reloaded ${themes.list().length} themes (active: ${themes.current.name})`,
+						...problems.map(problem => `[!] ${problem}`),
+					].join('\n'),
+				);
+				return;
+			}
+			if (args[0]) {
+				try {
+					addSystemMessage(`Theme: ${themes.use(args[0]).name}`);
+				} catch (error) {
+					addSystemMessage(`[!] ${error instanceof Error ? error.message : String(error)}`);
+				}
+				return;
+			}
+			addSystemMessage(
+				[
+					'Themes:',
+					...themes
+						.list()
+						.map(
+							theme =>
+								`${theme.name === themes.current.name ? '●' : ' '} ${theme.name}${theme.description ? ` - ${theme.description}` : ''}`,
+						),
+					`Theme files: ${themes.directory} (*.toml, *.json)`,
+				].join('\n'),
+			);
+		},
+		themeNames: () => getThemeManager().list().map(theme => theme.name),
+	};
+
 	// Handle safety mode changes from MainLayout
 	const handleSafetyModeChange = useCallback((mode: 'yolo' | 'ask' | 'plan') => {
 		useFloydStore.getState().setSafetyMode(mode);
@@ -913,10 +1877,31 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 			{
 				id: 'export-transcript',
 				label: 'Export Transcript',
-				description: 'Save conversation history to a markdown file',
+				description: 'Save conversation to .floyd/exports/ as Markdown',
 				icon: '[E]',
 				action: () => handleCommand('export-transcript'),
 			},
+			{
+				id: 'export-transcript-html',
+				label: 'Export Transcript (HTML)',
+				description: 'Save conversation to .floyd/exports/ as standalone HTML',
+				icon: '[E]',
+				action: () => handleCommand('export-transcript-html'),
+			},
+			{
+				id: 'continue-plan',
+				label: 'Continue Plan',
+				description: 'Resume from the open items in master_plan.md and the latest progress',
+				icon: '[>]',
+				action: () => handleCommand('continue-plan'),
+			},
+			{
+				id: 'tool-playground',
+				label: 'Tool Playground',
+				description: 'Pick a tool, fill its input form and run it manually',
+				icon: '[T]',
+				action: () => handleCommand('tool-playground'),
+			},
 			{
 				id: 'toggle-monitor',
 				label: 'Toggle Monitor',
@@ -950,6 +1935,30 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 		[baseCommands, handleCommand, toggleMonitor, addMessage]
 	);
 
+	// ============================================================================
+	// ASK USER (agent question - takes priority while the run is paused)
+	// ============================================================================
+
+	if (pendingQuestion) {
+		return (
+			<AskUserOverlay
+				question={pendingQuestion.question}
+				options={pendingQuestion.options}
+				onAnswer={answer => {
+					addMessage({
+						id: `system-${Date.now()}`,
+						role: 'system',
+						content: `[?] ${pendingQuestion.question}\n→ ${answer}`,
+						timestamp: Date.now(),
+					});
+					askUserResolveRef.current?.(answer);
+					askUserResolveRef.current = null;
+					setPendingQuestion(null);
+				}}
+			/>
+		);
+	}
+
 	// ============================================================================
 	// MONITOR DASHBOARD
 	// ============================================================================
@@ -958,6 +1967,29 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 		return <MonitorOverlay />;
 	}
 
+	// ============================================================================
+	// STATUS (PROGRESS LOG)
+	// ============================================================================
+
+	if (progressQuery) {
+		return <ProgressOverlay query={progressQuery} onClose={() => setProgressQuery(null)} />;
+	}
+
+	// ============================================================================
+	// TOOL PLAYGROUND
+	// ============================================================================
+
+	if (showToolPlayground) {
+		return (
+			<ToolPlaygroundOverlay
+				onClose={() => useFloydStore.getState().setOverlay('showToolPlayground', false)}
+				listTools={playgroundListTools}
+				callTool={playgroundCallTool}
+				onInject={playgroundInject}
+			/>
+		);
+	}
+
 	// ============================================================================
 	// CONVERSATIONAL LAYOUT (Claude-style chat flow)
 	// ============================================================================
@@ -975,10 +2007,24 @@ export default function App({ name = 'User', chrome = false }: AppProps) {
 				isThinking={isThinking}
 				agentStatus={agentStatus}
 				whimsicalPhrase={currentWhimsicalPhrase}
+				requestTiming={requestTiming}
+				liveTiming={liveTiming}
 				toolExecutions={toolExecutions}
 				onSubmit={handleSubmit}
+				slashCommands={slashCommands}
+				mentionFiles={mentionFiles}
+				expandedToolIds={expandedToolIds}
+				onToggleToolExpanded={toggleToolExpanded}
+				livePane={livePane}
+				pinnedPaths={pinnedPaths}
+				onPinnedChange={setPinnedPaths}
 				onCommand={handleCommand}
 				onExit={exit}
+				onCancel={cancelRun}
+				editingPrompt={editingPrompt}
+				onEditPrompt={editPrompt}
+				onDeletePrompt={number => void deletePrompt(number)}
+				onCancelEdit={() => setEditingPrompt(null)}
 				commands={augmentedCommands}
 				safetyMode={safetyMode}
 				onSafetyModeChange={handleSafetyModeChange}
@@ -8,7 +8,7 @@
  */
 
 import test from 'ava';
-import { rm } from 'fs/promises';
+import { readFile, rm, writeFile } from 'fs/promises';
 import { join } from 'path';
 import { tmpdir } from 'os';
 
@@ -365,6 +365,47 @@ test('CacheManager: getStats returns tier statistics', async t => {
 	await cleanupCacheDir(cacheDir);
 });
 
+test('CacheManager: getStats counts hits, misses and evictions', async t => {
+	const cacheDir = createTempCacheDir();
+	const cacheManager = new CacheManager(cacheDir, { maxSize: { project: 2 } });
+
+	await cacheManager.store('project', 'a', 'value-a');
+	await cacheManager.retrieve('project', 'a');
+	await cacheManager.retrieve('project', 'a');
+	await cacheManager.retrieve('project', 'missing');
+	await cacheManager.store('project', 'b', 'value-b');
+	await cacheManager.store('project', 'c', 'value-c');
+
+	const [stats] = await cacheManager.getStats('project');
+	t.is(stats.count, 2);
+	t.is(stats.hits, 2);
+	t.is(stats.misses, 1);
+	t.is(stats.evictions, 1);
+
+	// Counters are on disk, so another manager sees them
+	const [reopened] = await new CacheManager(cacheDir).getStats('project');
+	t.is(reopened.hits, 2);
+	await cleanupCacheDir(cacheDir);
+});
+
+test('CacheManager: expired entries are evicted and count as misses', async t => {
+	const cacheDir = createTempCacheDir();
+	const cacheManager = new CacheManager(cacheDir);
+
+	await cacheManager.store('reasoning', 'old', 'value');
+	const entryPath = join(cacheDir, '.floyd', '.cache', 'reasoning', 'old.json');
+	const entry = JSON.parse(await readFile(entryPath, 'utf-8'));
+	entry.timestamp -= 6 * 60 * 1000;
+	await writeFile(entryPath, JSON.stringify(entry));
+
+	t.is(await cacheManager.retrieve('reasoning', 'old'), null);
+	const [stats] = await cacheManager.getStats('reasoning');
+	t.is(stats.count, 0);
+	t.is(stats.misses, 1);
+	t.is(stats.evictions, 1);
+	await cleanupCacheDir(cacheDir);
+});
+
 test('CacheManager: search finds entries by key or value', async t => {
 	const cacheDir = createTempCacheDir();
 	const cacheManager = new CacheManager(cacheDir);
@@ -27,6 +27,22 @@ export interface CacheStats {
 	totalSize: number;
 	oldestEntry?: number;
 	newestEntry?: number;
+	/** Lookups that found a live entry */
+	hits: number;
+	/** Lookups that found nothing or an expired entry */
+	misses: number;
+	/** Entries removed because they expired or the tier was full */
+	evictions: number;
+}
+
+/**
+ * Per-tier lookup counters, kept in .floyd/.cache/stats.json so they survive
+ * restarts and are shared by every process using the cache
+ */
+export interface TierCounters {
+	hits: number;
+	misses: number;
+	evictions: number;
 }
 
 // Reasoning Frame Schema (matching blueprint)
@@ -112,6 +128,9 @@ const COMPRESS_THRESHOLD = 1024; // 1KB
 // Cache version
 const CACHE_VERSION = 1;
 
+// Counter file in the cache root
+const STATS_FILE = 'stats.json';
+
 export interface CacheConfig {
 	cacheDir?: string;
 	maxSize?: Partial<Record<CacheTier, number>>;
@@ -122,6 +141,7 @@ export class CacheManager {
 	private cacheRoot: string;
 	private tiers: CacheTier[] = ['reasoning', 'project', 'vault', 'legacy'];
 	private maxSize: Record<CacheTier, number>;
+	private counterWrites: Promise<void> = Promise.resolve();
 	private compressThreshold: number;
 
 	constructor(projectRoot: string, config?: CacheConfig) {
@@ -144,6 +164,41 @@ export class CacheManager {
 		return join(this.getTierPath(tier), `${safeKey}.json`);
 	}
 
+	private getStatsPath(): string {
+		return join(this.cacheRoot, STATS_FILE);
+	}
+
+	private async readCounters(): Promise<Partial<Record<CacheTier, TierCounters>>> {
+		try {
+			return JSON.parse(await fs.readFile(this.getStatsPath(), 'utf-8'));
+		} catch {
+			return {};
+		}
+	}
+
+	/**
+	 * Add to a tier's counters (writes are queued so concurrent lookups don't lose counts)
+	 */
+	private count(tier: CacheTier, counter: keyof TierCounters, amount = 1): Promise<void> {
+		if (amount <= 0) {
+			return this.counterWrites;
+		}
+
+		this.counterWrites = this.counterWrites.then(async () => {
+			try {
+				const counters = await this.readCounters();
+				const current = {hits: 0, misses: 0, evictions: 0, ...counters[tier]};
+				current[counter] += amount;
+				counters[tier] = current;
+				await fs.mkdir(this.cacheRoot, {recursive: true});
+				await fs.writeFile(this.getStatsPath(), JSON.stringify(counters, null, 2));
+			} catch {
+				// Counters are best effort
+			}
+		});
+		return this.counterWrites;
+	}
+
 	private hash(input: string): number {
 		let hash = 0;
 		for (let i = 0; i < input.length; i++) {
@@ -207,6 +262,8 @@ export class CacheManager {
 			if (now - entry.timestamp > entry.ttl) {
 				// Expired - delete and return null
 				await this.delete(tier, key);
+				await this.count(tier, 'evictions');
+				await this.count(tier, 'misses');
 				return null;
 			}
 
@@ -214,13 +271,18 @@ export class CacheManager {
 			entry.lastAccess = now;
 			await fs.writeFile(entryPath, JSON.stringify(entry, null, 2));
 
+			await this.count(tier, 'hits');
 			return entry.value;
 		} catch {
+			await this.count(tier, 'misses');
 			return null;
 		}
 	}
 
 	private async enforceSizeLimit(tier: CacheTier): Promise<void> {
+		// Expired entries go first, then the least recently used
+		await this.prune(tier);
+
 		const entries = await this.list(tier);
 		const maxSize = this.maxSize[tier];
 
@@ -236,6 +298,7 @@ export class CacheManager {
 		for (const entry of toRemove) {
 			await this.delete(tier, entry.key);
 		}
+		await this.count(tier, 'evictions', toRemove.length);
 	}
 
 	async backup(backupPath: string): Promise<void> {
@@ -387,6 +450,8 @@ export class CacheManager {
 	async getStats(tier?: CacheTier): Promise<CacheStats[]> {
 		const stats: CacheStats[] = [];
 		const tiersToCheck = tier ? [tier] : this.tiers;
+		await this.counterWrites;
+		const counters = await this.readCounters();
 
 		for (const t of tiersToCheck) {
 			const tierPath = this.getTierPath(t);
@@ -394,6 +459,9 @@ export class CacheManager {
 				tier: t,
 				count: 0,
 				totalSize: 0,
+				hits: counters[t]?.hits ?? 0,
+				misses: counters[t]?.misses ?? 0,
+				evictions: counters[t]?.evictions ?? 0,
 			};
 
 			try {
@@ -435,6 +503,7 @@ export class CacheManager {
 
 		for (const t of tiersToCheck) {
 			const tierPath = this.getTierPath(t);
+			let prunedInTier = 0;
 			try {
 				const files = await fs.readdir(tierPath);
 				for (const file of files) {
@@ -447,13 +516,15 @@ export class CacheManager {
 						const now = Date.now();
 						if (now - entry.timestamp > entry.ttl) {
 							await fs.unlink(filePath);
-							pruned++;
+							prunedInTier++;
 						}
 					}
 				}
 			} catch {
 				// Directory might not exist
 			}
+			await this.count(t, 'evictions', prunedInTier);
+			pruned += prunedInTier;
 		}
 
 		return pruned;
@@ -598,12 +669,16 @@ export class CacheManager {
 			if (now - entry.timestamp > entry.ttl) {
 				// Expired - delete and return null
 				await fs.unlink(patternPath).catch(() => {});
+				await this.count('vault', 'evictions');
+				await this.count('vault', 'misses');
 				return null;
 			}
 
+			await this.count('vault', 'hits');
 			return entry.value;
 		} catch (error) {
 			// Pattern not found or other error
+			await this.count('vault', 'misses');
 			return null;
 		}
 	}
@@ -631,3 +706,21 @@ export class CacheManager {
 		}
 	}
 }
+
+/**
+ * Cache stats as table rows for /memory
+ */
+export function formatCacheStats(stats: CacheStats[]): string[] {
+	const rows = [`${'Tier'.padEnd(10)} ${'Entries'.padStart(7)} ${'Size'.padStart(9)} ${'Hits'.padStart(6)} ${'Misses'.padStart(6)} ${'Hit rate'.padStart(8)} ${'Evicted'.padStart(7)}`];
+	for (const stat of stats) {
+		const lookups = stat.hits + stat.misses;
+		const hitRate = lookups > 0 ? `${Math.round((stat.hits / lookups) * 100)}%` : '-';
+		const size = stat.totalSize < 1024 * 1024
+			? `${(stat.totalSize / 1024).toFixed(1)} KB`
+			: `${(stat.totalSize / (1024 * 1024)).toFixed(1)} MB`;
+		rows.push(
+			`${stat.tier.padEnd(10)} ${String(stat.count).padStart(7)} ${size.padStart(9)} ${String(stat.hits).padStart(6)} ${String(stat.misses).padStart(6)} ${hitRate.padStart(8)} ${String(stat.evictions).padStart(7)}`,
+		);
+	}
+	return rows;
+}
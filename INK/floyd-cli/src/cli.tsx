@@ -3,6 +3,10 @@ import React from 'react';
 import {render} from 'ink';
 import meow from 'meow';
 import App from './app.js';
+import {setLogger, createFileLogger} from './utils/logger.js';
+import {getThemeManager} from './theme/user-themes.js';
+import {enableMouseMode} from './utils/mouse-terminal.js';
+import {enableBracketedPaste} from './utils/bracketed-paste.js';
 
 // Terminal size requirements
 const MIN_ROWS = 20;
@@ -26,6 +30,12 @@ const cli = meow(
 
 	Options
 		--name  Your name
+		--mouse  Drag to select and copy text, click paths and URLs to open them
+		         (also FLOYD_MOUSE=1; hold Shift for the terminal's own selection)
+
+	Environment
+		FLOYD_VIM=1      Vim-style modal editing in the input (Esc for normal mode)
+		FLOYD_MINIMAL=1  Low-overhead rendering for slow terminals
 
 	Examples
 	  $ floyd-cli --name=Jane
@@ -40,11 +50,31 @@ const cli = meow(
 			chrome: {
 				type: 'boolean',
 			},
+			mouse: {
+				type: 'boolean',
+			},
 		},
 	},
 );
 
-render(<App name={cli.flags.name} chrome={cli.flags.chrome} />);
+// Ink owns stdout, so logs go to .floyd/logs/floyd.log only
+setLogger(createFileLogger());
+
+// Apply the ~/.floyd/themes/ palette before the first frame
+getThemeManager();
+
+// Pastes arrive as one block instead of keystrokes, so their newlines do not
+// send the message
+const paste = process.stdin.isTTY ? enableBracketedPaste() : null;
+
+// Mouse reporting takes over the terminal's own selection, so it is opt-in
+const mouseEnabled = cli.flags.mouse || ['1', 'true'].includes(process.env['FLOYD_MOUSE'] ?? '');
+const mouse = mouseEnabled && paste ? enableMouseMode(paste.stdin) : null;
+
+render(
+	<App name={cli.flags.name} chrome={cli.flags.chrome} />,
+	mouse ? {stdin: mouse.stdin, stdout: mouse.stdout} : paste ? {stdin: paste.stdin} : undefined,
+);
 
 // HARD EXIT: Ctrl+Q (SIGQUIT) immediately terminates the process
 // This is the DEFINITIVE quit key for floyd-cli
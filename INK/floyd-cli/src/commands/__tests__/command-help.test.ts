@@ -0,0 +1,75 @@
+/**
+ * Command Help Tests
+ *
+ * Tests for help cards and the command overview generated from the registry.
+ */
+
+import test from 'ava';
+import {CommandRegistry} from '../command-registry.ts';
+import {findRelatedCommands, formatCommandHelp, formatCommandList} from '../command-help.ts';
+
+function makeRegistry(): CommandRegistry {
+	const registry = new CommandRegistry();
+	registry.register({
+		name: 'export',
+		description: 'Export the conversation',
+		category: 'session',
+		aliases: ['save'],
+		usage: '/export [md|html]',
+		arguments: [{name: 'format', description: 'md or html', optional: true}],
+		examples: ['/export', '/export html'],
+		handler: () => {},
+	});
+	registry.register({name: 'new', description: 'Start a new session', category: 'session', handler: () => {}});
+	registry.register({name: 'wipe', category: 'session', hidden: true, handler: () => {}});
+	registry.register({name: 'logs', description: 'Show logs', category: 'diagnostics', handler: () => {}});
+	return registry;
+}
+
+test('formatCommandHelp: renders usage, arguments, examples and related commands', t => {
+	const registry = makeRegistry();
+	t.is(
+		formatCommandHelp(registry.get('export')!, registry),
+		[
+			'/export - Export the conversation',
+			'',
+			'Usage: /export [md|html]',
+			'Aliases: /save',
+			'',
+			'Arguments:',
+			'  format  md or html (optional)',
+			'',
+			'Examples:',
+			'  /export',
+			'  /export html',
+			'',
+			'Related: /new',
+		].join('\n'),
+	);
+});
+
+test('formatCommandHelp: omits empty sections', t => {
+	const registry = makeRegistry();
+	t.is(formatCommandHelp(registry.get('logs')!, registry), '/logs - Show logs\n\nUsage: /logs');
+});
+
+test('findRelatedCommands: skips the command itself and hidden commands', t => {
+	const registry = makeRegistry();
+	t.deepEqual(findRelatedCommands(registry.get('new')!, registry).map(c => c.name), ['export']);
+});
+
+test('formatCommandList: groups visible commands by category', t => {
+	t.is(
+		formatCommandList(makeRegistry()),
+		[
+			'Commands (/help <command> for details):',
+			'',
+			'diagnostics:',
+			'  /logs    Show logs',
+			'',
+			'session:',
+			'  /export  Export the conversation',
+			'  /new     Start a new session',
+		].join('\n'),
+	);
+});
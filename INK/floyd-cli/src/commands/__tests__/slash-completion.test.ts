@@ -0,0 +1,82 @@
+/**
+ * Slash Completion Tests
+ *
+ * Tests for command and argument suggestions and unknown command hints.
+ */
+
+import test from 'ava';
+import {CommandRegistry} from '../command-registry.ts';
+import {
+	getSlashSuggestions,
+	looksLikeSlashCommand,
+	findSimilarCommand,
+	formatUnknownCommand,
+} from '../slash-completion.ts';
+
+function makeRegistry(): CommandRegistry {
+	const registry = new CommandRegistry();
+	registry.register({name: 'status', description: 'Show the progress log', handler: () => {}});
+	registry.register({name: 'skill', description: 'Show skill details', handler: () => {}, completeArgs: () => ['lint', 'review']});
+	registry.register({
+		name: 'export',
+		description: 'Export the conversation',
+		handler: () => {},
+		completeArgs: previous => (previous.length === 0 ? ['md', 'html'] : []),
+	});
+	registry.register({name: 'debug', description: 'Internal', hidden: true, handler: () => {}});
+	return registry;
+}
+
+test('getSlashSuggestions: ignores input that is not a slash command', t => {
+	t.is(getSlashSuggestions('hello', makeRegistry()), null);
+});
+
+test('getSlashSuggestions: lists visible commands for "/"', t => {
+	const suggestions = getSlashSuggestions('/', makeRegistry());
+	t.is(suggestions?.kind, 'command');
+	t.deepEqual(suggestions?.items.map(item => item.label), ['/export', '/skill', '/status']);
+	t.is(suggestions?.items[0]?.description, 'Export the conversation');
+});
+
+test('getSlashSuggestions: filters by prefix, then by name or description', t => {
+	const registry = makeRegistry();
+	t.deepEqual(getSlashSuggestions('/s', registry)?.items.map(item => item.value), ['/skill ', '/status ']);
+	t.deepEqual(getSlashSuggestions('/log', registry)?.items.map(item => item.label), ['/status']);
+	t.deepEqual(getSlashSuggestions('/zzz', registry)?.items, []);
+});
+
+test('getSlashSuggestions: completes arguments from completeArgs', t => {
+	const registry = makeRegistry();
+	const suggestions = getSlashSuggestions('/skill r', registry);
+	t.is(suggestions?.kind, 'argument');
+	t.is(suggestions?.command?.name, 'skill');
+	t.deepEqual(suggestions?.items, [{value: '/skill review ', label: 'review'}]);
+
+	t.deepEqual(getSlashSuggestions('/export ', registry)?.items.map(item => item.value), ['/export md ', '/export html ']);
+	t.deepEqual(getSlashSuggestions('/export md ', registry)?.items, []);
+});
+
+test('getSlashSuggestions: no argument help for unknown commands', t => {
+	t.is(getSlashSuggestions('/nope arg', makeRegistry()), null);
+});
+
+test('looksLikeSlashCommand: tells commands from paths', t => {
+	t.true(looksLikeSlashCommand('/stauts'));
+	t.true(looksLikeSlashCommand('/export pdf'));
+	t.false(looksLikeSlashCommand('/usr/bin is missing'));
+	t.false(looksLikeSlashCommand('hello /status'));
+});
+
+test('findSimilarCommand: suggests close names only', t => {
+	const registry = makeRegistry();
+	t.is(findSimilarCommand('stauts', registry), 'status');
+	t.is(findSimilarCommand('exp', registry), 'export');
+	t.is(findSimilarCommand('deploy', registry), undefined);
+	t.is(findSimilarCommand('debg', registry), undefined);
+});
+
+test('formatUnknownCommand: includes the suggestion when there is one', t => {
+	const registry = makeRegistry();
+	t.is(formatUnknownCommand('stauts', registry), '[!] Unknown command /stauts. Did you mean /status? Type / to list commands.');
+	t.is(formatUnknownCommand('deploy', registry), '[!] Unknown command /deploy. Type / to list commands.');
+});
@@ -0,0 +1,410 @@
+/**
+ * App Slash Commands
+ *
+ * The slash commands typed into the chat input (/progress, /export, ...).
+ * Definitions live here so the input bar can list and complete them and
+ * /help can describe them; the handlers are supplied by app.tsx because they
+ * act on UI state. They are looked up on every call, so the registry can
+ * stay the same across renders while the callbacks behind it change.
+ *
+ * @module commands/app-commands
+ */
+
+import {PROGRESS_USAGE} from 'floyd-agent-core/utils';
+import type {CommandDefinition} from './command-handler.js';
+import {CommandRegistry, registerCommands} from './command-registry.js';
+import {formatCommandHelp, formatCommandList} from './command-help.js';
+import {formatUnknownCommand} from './slash-completion.js';
+import {isImagePath} from '../utils/image-attachments.js';
+import {REQUEST_PARAM_KEYS} from '../utils/request-params.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Actions behind the app's slash commands
+ */
+export interface AppCommandHandlers {
+	/** Show text in the conversation as a system message */
+	print: (text: string) => void;
+	newSession: () => void;
+	/** Stop the reply being written, keeping the partial text */
+	cancel: () => void;
+	/** Drop the last reply and send its prompt again, with overrides */
+	retry: (args: string[]) => void | Promise<void>;
+	/** Put a prompt into the input; sending it replays from there */
+	edit: (args: string[]) => void;
+	/** Remove a prompt and its reply */
+	delete: (args: string[]) => void | Promise<void>;
+
+	/** Number of prompts in the conversation, for /edit and /delete completion */
+	promptCount: () => number;
+	monitor: () => void;
+	progress: (args: string[]) => void;
+	export: (args: string[]) => void;
+	attach: (args: string[]) => void | Promise<void>;
+	pasteImage: (args: string[]) => void | Promise<void>;
+	logs: (args: string[]) => void | Promise<void>;
+	memory: (args: string[]) => void | Promise<void>;
+	stats: (args: string[]) => void;
+	run: (args: string[]) => void | Promise<void>;
+	read: (args: string[]) => void | Promise<void>;
+	auth: (args: string[]) => void | Promise<void>;
+	skill: (args: string[]) => void;
+	copy: (args: string[]) => void | Promise<void>;
+
+	/** Ids of discovered skills, for /skill completion */
+	skillNames: () => string[];
+
+	/** Workspace files, for /attach completion */
+	workspaceFiles: () => string[];
+
+	/** Remove pinned context: one path, or everything */
+	unpin: (args: string[]) => void;
+
+	/** Pinned paths, for /unpin completion */
+	pinnedPaths: () => string[];
+
+	/** Send the plan-based continuation request */
+	continueWork: (args: string[]) => void | Promise<void>;
+
+	/** Show the full output of a tool call */
+	expandTool: (args: string[]) => void;
+
+	/** Number of tool calls in the conversation, for /expand completion */
+	toolCallCount: () => number;
+
+	/** Show or change the request settings of this session */
+	set: (args: string[]) => void;
+
+	/** List, switch or reload themes */
+	theme: (args: string[]) => void;
+
+	/** Loaded theme names, for /theme completion */
+	themeNames: () => string[];
+}
+
+const LOG_LEVELS = ['debug', 'info', 'warn', 'error'];
+
+/**
+ * "3", "2", "1": newest first, like /expand
+ */
+function promptNumbers(count: number): string[] {
+	return Array.from({length: count}, (_, i) => String(count - i));
+}
+
+// ============================================================================
+// REGISTRY
+// ============================================================================
+
+/**
+ * Build the command definitions for the chat input
+ *
+ * @param registry - Registry the commands will be added to (used by /help)
+ */
+export function createAppCommands(
+	getHandlers: () => AppCommandHandlers,
+	registry: CommandRegistry,
+): CommandDefinition<string[]>[] {
+	return [
+		{
+			name: 'help',
+			description: 'List commands, or show details for one',
+			category: 'general',
+			usage: '/help [command]',
+			arguments: [{name: 'command', description: 'Command to describe, with or without the /', optional: true}],
+			examples: ['/help', '/help export'],
+			handler: args => {
+				const name = args[0]?.replace(/^\//, '');
+				const command = name ? registry.get(name) : undefined;
+				getHandlers().print(
+					!name
+						? formatCommandList(registry)
+						: command
+							? formatCommandHelp(command, registry)
+							: formatUnknownCommand(name, registry),
+				);
+			},
+			completeArgs: previous => (previous.length === 0 ? registry.names().sort() : []),
+		},
+		{
+			name: 'continue',
+			description: "Resume the plan: send its open items and the latest progress, and ask for what's next",
+			category: 'session',
+			aliases: ['next'],
+			usage: '/continue [instructions]',
+			arguments: [{name: 'instructions', description: 'Extra instructions added to the request', optional: true}],
+			examples: ['/continue', '/continue skip the deployment notes for now'],
+			handler: args => getHandlers().continueWork(args),
+		},
+		{
+			name: 'cancel',
+			description: 'Stop the reply being written; the partial text stays in the conversation',
+			category: 'session',
+			usage: '/cancel',
+			examples: ['/cancel'],
+			handler: () => getHandlers().cancel(),
+		},
+		{
+			name: 'retry',
+			description: 'Drop the last reply and send its prompt again, optionally with another temperature or model',
+			category: 'session',
+			aliases: ['regenerate'],
+			usage: '/retry [--temp <0-2>] [--model <name>]',
+			arguments: [
+				{name: '--temp', description: 'Temperature for this reply only', optional: true},
+				{name: '--model', description: 'Model for this reply only', optional: true},
+			],
+			examples: ['/retry', '/retry --temp 0.2', '/retry --model glm-4-plus'],
+			handler: args => getHandlers().retry(args),
+			completeArgs: previous => {
+				const last = previous[previous.length - 1];
+				if (last === '--temp' || last === '--model') {
+					return [];
+				}
+				return ['--temp', '--model'].filter(flag => !previous.includes(flag));
+			},
+		},
+		{
+			name: 'edit',
+			description: 'Edit an earlier prompt; sending it replays the conversation from there',
+			category: 'session',
+			usage: '/edit <n>',
+			arguments: [{name: 'n', description: 'Prompt number, as shown after "User" (#n)'}],
+			examples: ['/edit 2'],
+			handler: args => getHandlers().edit(args),
+			completeArgs: previous => (previous.length === 0 ? promptNumbers(getHandlers().promptCount()) : []),
+		},
+		{
+			name: 'delete',
+			description: 'Remove a prompt and its reply from the conversation',
+			category: 'session',
+			usage: '/delete <n>',
+			arguments: [{name: 'n', description: 'Prompt number, as shown after "User" (#n)'}],
+			examples: ['/delete 3'],
+			handler: args => getHandlers().delete(args),
+			completeArgs: previous => (previous.length === 0 ? promptNumbers(getHandlers().promptCount()) : []),
+		},
+		{
+			name: 'new',
+			description: 'Start a new session',
+			category: 'session',
+			aliases: ['clear'],
+			usage: '/new',
+			handler: () => getHandlers().newSession(),
+		},
+		{
+			name: 'export',
+			description: 'Export the conversation to .floyd/exports/',
+			category: 'session',
+			usage: '/export [md|html]',
+			arguments: [{name: 'format', description: 'md (default) or html', optional: true}],
+			examples: ['/export', '/export html'],
+			handler: args => getHandlers().export(args),
+			completeArgs: previous => (previous.length === 0 ? ['md', 'html'] : []),
+		},
+		{
+			name: 'attach',
+			description: 'Attach PNG/JPEG images to your next message',
+			category: 'session',
+			usage: '/attach <path> [path...]',
+			arguments: [{name: 'path', description: 'Image file, relative to the working directory'}],
+			examples: ['/attach screenshot.png', '/attach docs/before.png docs/after.jpg'],
+			handler: args => getHandlers().attach(args),
+			completeArgs: () => getHandlers().workspaceFiles().filter(isImagePath),
+		},
+		{
+			name: 'unpin',
+			description: 'Stop sending pinned files (pinned with Ctrl+F) with your messages',
+			category: 'session',
+			usage: '/unpin [path|all]',
+			arguments: [{name: 'path', description: 'Pinned file or directory (default: all)', optional: true}],
+			examples: ['/unpin', '/unpin src/app.tsx'],
+			handler: args => getHandlers().unpin(args),
+			completeArgs: previous => (previous.length === 0 ? ['all', ...getHandlers().pinnedPaths()] : []),
+		},
+		{
+			name: 'paste-image',
+			description: 'Attach the image on the clipboard to your next message (saved to .floyd/attachments/)',
+			category: 'session',
+			usage: '/paste-image',
+			handler: args => getHandlers().pasteImage(args),
+		},
+		{
+			name: 'expand',
+			description: 'Show the full output of a tool call',
+			category: 'session',
+			usage: '/expand <n>',
+			arguments: [{name: 'n', description: 'Tool call number, as shown in its block (#n)'}],
+			examples: ['/expand 3'],
+			handler: args => getHandlers().expandTool(args),
+			// Newest first
+			completeArgs: previous =>
+				previous.length === 0
+					? Array.from({length: getHandlers().toolCallCount()}, (_, i) => String(getHandlers().toolCallCount() - i))
+					: [],
+		},
+		{
+			name: 'copy',
+			description: 'Copy the last reply, or its last code block, to the clipboard',
+			category: 'session',
+			usage: '/copy [code]',
+			arguments: [{name: 'code', description: 'Copy the last fenced code block instead of the whole reply', optional: true}],
+			examples: ['/copy', '/copy code'],
+			handler: args => getHandlers().copy(args),
+			completeArgs: previous => (previous.length === 0 ? ['code'] : []),
+		},
+		{
+			name: 'set',
+			description: 'Show or change max_tokens, temperature and stop sequences for this session',
+			category: 'general',
+			usage: '/set [max_tokens <n> | temperature <0-2> | stop <text...>|none]',
+			arguments: [
+				{name: 'setting', description: 'max_tokens, temperature or stop', optional: true},
+				{name: 'value', description: 'New value; stop takes up to 4 sequences ("\\n" is a line break)', optional: true},
+			],
+			examples: ['/set', '/set max_tokens 4096', '/set temperature 0.7', '/set stop END', '/set stop none'],
+			handler: args => getHandlers().set(args),
+			completeArgs: previous =>
+				previous.length === 0 ? [...REQUEST_PARAM_KEYS] : previous.length === 1 && previous[0] === 'stop' ? ['none'] : [],
+		},
+		{
+			name: 'theme',
+			description: 'List themes, switch theme, or reload theme files from ~/.floyd/themes/',
+			category: 'general',
+			usage: '/theme [name|reload]',
+			arguments: [{name: 'name', description: 'Theme to use, or reload to re-read the theme files', optional: true}],
+			examples: ['/theme', '/theme dusk', '/theme reload'],
+			handler: args => getHandlers().theme(args),
+			completeArgs: previous => (previous.length === 0 ? ['reload', ...getHandlers().themeNames()] : []),
+		},
+		{
+			name: 'monitor',
+			description: 'Toggle the monitor dashboard',
+			category: 'diagnostics',
+			usage: '/monitor',
+			handler: () => getHandlers().monitor(),
+		},
+		{
+			name: 'progress',
+			description: 'Show the progress log',
+			category: 'diagnostics',
+			usage: PROGRESS_USAGE,
+			arguments: [
+				{name: 'count', description: 'Only the last N entries', optional: true},
+				{name: '--date', description: 'Only entries from this day (or month)', optional: true},
+				{name: 'text', description: 'Only entries mentioning this text', optional: true},
+			],
+			examples: ['/progress', '/progress 20', '/progress --date 2026-01-31 tests'],
+			handler: args => getHandlers().progress(args),
+			completeArgs: previous => {
+				const last = previous[previous.length - 1];
+				if (last === '--date' || last === '-d') {
+					return [new Date().toISOString().slice(0, 10)];
+				}
+				return previous.includes('--date') ? [] : ['--date'];
+			},
+		},
+		{
+			name: 'logs',
+			description: 'Show the tail of .floyd/logs/floyd.log',
+			category: 'diagnostics',
+			usage: '/logs [lines] [debug|info|warn|error]',
+			arguments: [
+				{name: 'lines', description: 'Number of records to show (default 30)', optional: true},
+				{name: 'level', description: 'Minimum level: debug, info, warn or error', optional: true},
+			],
+			examples: ['/logs', '/logs 100 error'],
+			handler: args => getHandlers().logs(args),
+			completeArgs: previous => (previous.some(arg => LOG_LEVELS.includes(arg)) ? [] : LOG_LEVELS),
+		},
+		{
+			name: 'memory',
+			description: 'Show SUPERCACHE entries, hits and misses per tier',
+			category: 'diagnostics',
+			usage: '/memory [prune]',
+			arguments: [{name: 'prune', description: 'Remove expired entries first', optional: true}],
+			examples: ['/memory', '/memory prune'],
+			handler: args => getHandlers().memory(args),
+			completeArgs: previous => (previous.length === 0 ? ['prune'] : []),
+		},
+		{
+			name: 'stats',
+			description: 'Show calls, failures and latency per tool this session',
+			category: 'diagnostics',
+			usage: '/stats [reset]',
+			arguments: [{name: 'reset', description: 'Clear the numbers after showing them', optional: true}],
+			examples: ['/stats', '/stats reset'],
+			handler: args => getHandlers().stats(args),
+			completeArgs: previous => (previous.length === 0 ? ['reset'] : []),
+		},
+		{
+			name: 'run',
+			description: 'Run a shell command directly (no model call)',
+			category: 'tools',
+			aliases: ['sh'],
+			usage: '/run <command>',
+			arguments: [{name: 'command', description: 'Command line, run with $SHELL -c'}],
+			examples: ['/run npm test', '/run git status'],
+			handler: args => getHandlers().run(args),
+		},
+		{
+			name: 'read',
+			description: 'Show a file directly (no model call)',
+			category: 'tools',
+			usage: '/read <file> [offset] [limit]',
+			arguments: [
+				{name: 'file', description: 'Path relative to the project'},
+				{name: 'offset', description: 'First line to show (1-based)', optional: true},
+				{name: 'limit', description: 'Number of lines to show (default 200)', optional: true},
+			],
+			examples: ['/read package.json', '/read src/app.tsx 500 50'],
+			handler: args => getHandlers().read(args),
+			completeArgs: previous => (previous.length === 0 ? getHandlers().workspaceFiles() : []),
+		},
+		{
+			name: 'auth',
+			description: 'Show, store, test or remove API keys (kept in the OS keychain)',
+			category: 'general',
+			usage: '/auth [status | login <provider> <key> | test [provider] | logout <provider>]',
+			arguments: [
+				{name: 'action', description: 'status (default), login, test or logout', optional: true},
+				{name: 'provider', description: 'zai (glm), anthropic, openai or deepseek', optional: true},
+				{name: 'key', description: 'API key (login only; never saved to input history)', optional: true},
+			],
+			examples: ['/auth', '/auth login glm <key>', '/auth test'],
+			handler: args => getHandlers().auth(args),
+			completeArgs: previous =>
+				previous.length === 0
+					? ['status', 'login', 'test', 'logout']
+					: previous.length === 1 && previous[0] !== 'status'
+						? ['zai', 'anthropic', 'openai', 'deepseek']
+						: [],
+		},
+		{
+			name: 'skill',
+			description: 'List skills, show one, or keep a skill pack in the prompt',
+			category: 'skills',
+			usage: '/skill [name] | /skill enable|disable <name>',
+			arguments: [{name: 'name', description: 'Skill id from .floyd/skills or ~/.floyd/skills', optional: true}],
+			examples: ['/skill', '/skill code-review', '/skill enable release-notes'],
+			handler: args => getHandlers().skill(args),
+			completeArgs: previous =>
+				previous.length === 0
+					? ['enable', 'disable', ...getHandlers().skillNames()]
+					: previous.length === 1 && (previous[0] === 'enable' || previous[0] === 'disable')
+						? getHandlers().skillNames()
+						: [],
+		},
+	];
+}
+
+/**
+ * Registry holding the app's slash commands
+ */
+export function createAppCommandRegistry(getHandlers: () => AppCommandHandlers): CommandRegistry {
+	const registry = new CommandRegistry();
+	registerCommands(registry, createAppCommands(getHandlers, registry) as CommandDefinition[]);
+	return registry;
+}
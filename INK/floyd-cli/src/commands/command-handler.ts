@@ -42,6 +42,20 @@ export type CommandHandlerFn<T = unknown, R = unknown> = (
 	context: CommandContext,
 ) => Promise<R> | R;
 
+/**
+ * Documented command argument (shown by /help <command>)
+ */
+export interface CommandArgument {
+	/** Argument name as written in the usage string */
+	name: string;
+
+	/** What the argument does */
+	description: string;
+
+	/** Whether the argument can be left out */
+	optional?: boolean;
+}
+
 /**
  * Command definition
  */
@@ -64,6 +78,9 @@ export interface CommandDefinition<T = unknown, R = unknown> {
 	/** Examples */
 	examples?: string[];
 
+	/** Arguments, in order */
+	arguments?: CommandArgument[];
+
 	/** Handler function */
 	handler: CommandHandlerFn<T, R>;
 
@@ -81,6 +98,12 @@ export interface CommandDefinition<T = unknown, R = unknown> {
 
 	/** Whether command runs in background */
 	background?: boolean;
+
+	/**
+	 * Candidate values for the argument being typed (Tab completion).
+	 * Receives the arguments already entered before it.
+	 */
+	completeArgs?: (previousArgs: string[]) => string[];
 }
 
 /**
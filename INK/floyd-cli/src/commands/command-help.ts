@@ -0,0 +1,82 @@
+/**
+ * Command Help
+ *
+ * Help text generated from command definitions, so /help stays in step
+ * with the registry: a card per command (usage, arguments, examples,
+ * related commands) and an overview grouped by category.
+ *
+ * @module commands/command-help
+ */
+
+import type {CommandDefinition} from './command-handler.js';
+import type {CommandRegistry} from './command-registry.js';
+
+// ============================================================================
+// FORMATTING
+// ============================================================================
+
+/**
+ * Other visible commands in the same category
+ */
+export function findRelatedCommands(command: CommandDefinition, registry: CommandRegistry): CommandDefinition[] {
+	if (!command.category) {
+		return [];
+	}
+	return registry
+		.byCategory(command.category)
+		.filter(other => other.name !== command.name && !other.hidden)
+		.sort((a, b) => a.name.localeCompare(b.name));
+}
+
+/**
+ * Detailed help card for one command
+ */
+export function formatCommandHelp(command: CommandDefinition, registry: CommandRegistry): string {
+	const lines = [`/${command.name}${command.description ? ` - ${command.description}` : ''}`];
+
+	lines.push('', `Usage: ${command.usage ?? `/${command.name}`}`);
+	if (command.aliases?.length) {
+		lines.push(`Aliases: ${command.aliases.map(alias => `/${alias}`).join(', ')}`);
+	}
+
+	if (command.arguments?.length) {
+		const width = Math.max(...command.arguments.map(arg => arg.name.length)) + 2;
+		lines.push('', 'Arguments:');
+		for (const arg of command.arguments) {
+			lines.push(`  ${arg.name.padEnd(width)}${arg.description}${arg.optional ? ' (optional)' : ''}`);
+		}
+	}
+
+	if (command.examples?.length) {
+		lines.push('', 'Examples:', ...command.examples.map(example => `  ${example}`));
+	}
+
+	const related = findRelatedCommands(command, registry);
+	if (related.length > 0) {
+		lines.push('', `Related: ${related.map(other => `/${other.name}`).join(', ')}`);
+	}
+
+	return lines.join('\n');
+}
+
+/**
+ * One line per visible command, grouped by category
+ */
+export function formatCommandList(registry: CommandRegistry): string {
+	const groups = new Map<string, CommandDefinition[]>();
+	for (const command of registry.all()) {
+		if (command.hidden) continue;
+		const category = command.category ?? 'other';
+		groups.set(category, [...(groups.get(category) ?? []), command]);
+	}
+
+	const width = Math.max(0, ...registry.all().map(command => command.name.length)) + 3;
+	const lines = ['Commands (/help <command> for details):'];
+	for (const [category, commands] of [...groups.entries()].sort(([a], [b]) => a.localeCompare(b))) {
+		lines.push('', `${category}:`);
+		for (const command of commands.sort((a, b) => a.name.localeCompare(b.name))) {
+			lines.push(`  ${`/${command.name}`.padEnd(width)}${command.description ?? ''}`);
+		}
+	}
+	return lines.join('\n');
+}
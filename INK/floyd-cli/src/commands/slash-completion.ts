@@ -0,0 +1,162 @@
+/**
+ * Slash Command Completion
+ *
+ * Suggestions for the input bar while a slash command is being typed:
+ * command names (with descriptions) after "/", then argument values from
+ * the command's completeArgs() once a command name is followed by a space.
+ * Also produces the "did you mean" hint for unknown commands.
+ *
+ * @module commands/slash-completion
+ */
+
+import type {CommandDefinition} from './command-handler.js';
+import type {CommandRegistry} from './command-registry.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * One entry in the completion popup
+ */
+export interface SlashSuggestion {
+	/** Input text after accepting this suggestion */
+	value: string;
+
+	/** Text shown in the popup */
+	label: string;
+
+	/** Command description (command suggestions only) */
+	description?: string;
+}
+
+/**
+ * Suggestions for the current input
+ */
+export interface SlashSuggestions {
+	/** Whether command names or argument values are being completed */
+	kind: 'command' | 'argument';
+
+	/** Matching suggestions, best first */
+	items: SlashSuggestion[];
+
+	/** The command whose arguments are being typed (argument mode) */
+	command?: CommandDefinition;
+}
+
+// ============================================================================
+// SUGGESTIONS
+// ============================================================================
+
+/**
+ * Suggestions for `input`, or null when it is not a slash command
+ */
+export function getSlashSuggestions(input: string, registry: CommandRegistry): SlashSuggestions | null {
+	if (!input.startsWith('/')) {
+		return null;
+	}
+
+	const spaceIndex = input.search(/\s/);
+
+	// Still typing the command name
+	if (spaceIndex === -1) {
+		const partial = input.slice(1).toLowerCase();
+		const commands = registry.all().filter(cmd => !cmd.hidden);
+
+		// Prefix matches first, then names or descriptions containing the text
+		const prefix = commands.filter(cmd => cmd.name.startsWith(partial));
+		const contains = commands.filter(
+			cmd =>
+				!prefix.includes(cmd) &&
+				partial.length > 0 &&
+				(cmd.name.includes(partial) || cmd.description?.toLowerCase().includes(partial)),
+		);
+
+		const byName = (a: CommandDefinition, b: CommandDefinition) => a.name.localeCompare(b.name);
+		return {
+			kind: 'command',
+			items: [...prefix.sort(byName), ...contains.sort(byName)].map(cmd => ({
+				value: `/${cmd.name} `,
+				label: `/${cmd.name}`,
+				description: cmd.description,
+			})),
+		};
+	}
+
+	const command = registry.get(input.slice(1, spaceIndex));
+	if (!command || command.hidden) {
+		return null;
+	}
+
+	// Complete the last (possibly empty) argument
+	const args = input.slice(spaceIndex + 1).split(/\s+/);
+	const partial = args.pop() ?? '';
+	const base = input.slice(0, input.length - partial.length);
+	const candidates = command.completeArgs?.(args.filter(Boolean)) ?? [];
+
+	return {
+		kind: 'argument',
+		command,
+		items: candidates
+			.filter(candidate => candidate.toLowerCase().startsWith(partial.toLowerCase()) && candidate !== partial)
+			.map(candidate => ({value: `${base}${candidate} `, label: candidate})),
+	};
+}
+
+// ============================================================================
+// UNKNOWN COMMANDS
+// ============================================================================
+
+/**
+ * Whether the input looks like a slash command rather than a path
+ * ("/usr/bin is broken" is a message, "/stauts" is a typo)
+ */
+export function looksLikeSlashCommand(input: string): boolean {
+	return /^\/[a-z][\w-]*$/i.test(input.trim().split(/\s+/)[0] ?? '');
+}
+
+/**
+ * Closest registered command name, if any is near enough to be a typo
+ */
+export function findSimilarCommand(name: string, registry: CommandRegistry): string | undefined {
+	const target = name.toLowerCase();
+	let best: {name: string; distance: number} | undefined;
+
+	for (const cmd of registry.all()) {
+		if (cmd.hidden) continue;
+		const distance = cmd.name.startsWith(target) ? 0 : editDistance(target, cmd.name);
+		if (distance <= Math.max(1, Math.floor(cmd.name.length / 3)) && (!best || distance < best.distance)) {
+			best = {name: cmd.name, distance};
+		}
+	}
+
+	return best?.name;
+}
+
+/**
+ * Message shown when an unknown slash command is submitted
+ */
+export function formatUnknownCommand(name: string, registry: CommandRegistry): string {
+	const similar = findSimilarCommand(name, registry);
+	const hint = similar ? ` Did you mean /${similar}?` : '';
+	return `[!] Unknown command /${name}.${hint} Type / to list commands.`;
+}
+
+/**
+ * Levenshtein distance between two strings
+ */
+function editDistance(a: string, b: string): number {
+	let previous = Array.from({length: b.length + 1}, (_, i) => i);
+	for (let i = 1; i <= a.length; i++) {
+		const current = [i];
+		for (let j = 1; j <= b.length; j++) {
+			current[j] = Math.min(
+				previous[j]! + 1,
+				current[j - 1]! + 1,
+				previous[j - 1]! + (a[i - 1] === b[j - 1] ? 0 : 1),
+			);
+		}
+		previous = current;
+	}
+	return previous[b.length]!;
+}
@@ -54,4 +54,10 @@ export const BUILTIN_SERVERS: Record<string, MCPServerConfig> = {
 		description: 'Codebase exploration: project map, smart replace, symbol listing',
 		enabled: true,
 	},
+	skill: {
+		name: 'skill',
+		modulePath: join(serverDir, 'skill-server.ts'),
+		description: 'Skill packs: read SKILL.md instructions, run bundled scripts',
+		enabled: true,
+	},
 };
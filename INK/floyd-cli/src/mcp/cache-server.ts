@@ -26,15 +26,11 @@ import {
 	CallToolRequestSchema,
 	ListToolsRequestSchema,
 } from '@modelcontextprotocol/sdk/types.js';
-import path from 'path';
-import {fileURLToPath} from 'url';
 import {CacheManager, type CacheTier, type ReasoningFrame} from '../cache/cache-manager.js';
 
-const __filename = fileURLToPath(import.meta.url);
-const __dirname = path.dirname(__filename);
-
-// Determine project root (assume we're in INK/floyd-cli/src/mcp/)
-const projectRoot = path.resolve(__dirname, '..', '..');
+// The cache belongs to the project Floyd was started in (the server inherits its cwd),
+// so /memory and the monitor read the same .floyd/.cache
+const projectRoot = process.cwd();
 
 // Global cache manager instance
 let cacheManager: CacheManager | null = null;
@@ -186,7 +182,7 @@ export async function createCacheServer(): Promise<Server> {
 				{
 					name: 'cache_stats',
 					description:
-						'Get statistics for cache tiers (entry count, size, oldest/newest)',
+						'Get statistics for cache tiers (entry count, size, hits, misses, evictions, oldest/newest)',
 					inputSchema: {
 						type: 'object',
 						properties: {
@@ -0,0 +1,186 @@
+/**
+ * MCP Skill Server
+ *
+ * Exposes the skill packs in .floyd/skills and ~/.floyd/skills to the agent.
+ *
+ * Tools:
+ * - skill: Read a skill's instructions, or run one of its bundled scripts
+ */
+
+import {Server} from '@modelcontextprotocol/sdk/server/index.js';
+import {StdioServerTransport} from '@modelcontextprotocol/sdk/server/stdio.js';
+import {
+	CallToolRequestSchema,
+	ListToolsRequestSchema,
+} from '@modelcontextprotocol/sdk/types.js';
+import {extname} from 'path';
+import {execa} from 'execa';
+import {discoverSkillPacks, resolveSkillScript, type SkillPack} from '../skills/skill-packs.js';
+
+const SCRIPT_TIMEOUT_MS = 120_000;
+
+/**
+ * Interpreters for script types that are usually not executable
+ */
+const INTERPRETERS: Record<string, string> = {
+	'.sh': 'bash',
+	'.py': 'python3',
+	'.js': 'node',
+	'.mjs': 'node',
+	'.cjs': 'node',
+};
+
+export interface SkillScriptResult {
+	skill: string;
+	script: string;
+	exitCode: number | null;
+	stdout: string;
+	stderr: string;
+	timedOut: boolean;
+}
+
+/**
+ * Run a script of a pack in the project directory
+ */
+export async function runSkillScript(
+	pack: SkillPack,
+	script: string,
+	args: string[] = [],
+	cwd: string = process.cwd(),
+): Promise<SkillScriptResult> {
+	const scriptPath = await resolveSkillScript(pack, script);
+	const interpreter = INTERPRETERS[extname(scriptPath)];
+	const result = await execa(interpreter ?? scriptPath, interpreter ? [scriptPath, ...args] : args, {
+		cwd,
+		timeout: SCRIPT_TIMEOUT_MS,
+		reject: false,
+		env: {FLOYD_SKILL_DIR: pack.path},
+	});
+
+	return {
+		skill: pack.id,
+		script,
+		exitCode: result.exitCode ?? null,
+		stdout: result.stdout,
+		stderr: result.stderr,
+		timedOut: result.timedOut,
+	};
+}
+
+/**
+ * Create the MCP skill server
+ */
+export async function createSkillServer(): Promise<Server> {
+	const server = new Server(
+		{
+			name: 'floyd-skill-server',
+			version: '0.1.0',
+		},
+		{
+			capabilities: {
+				tools: {},
+			},
+		},
+	);
+
+	server.setRequestHandler(ListToolsRequestSchema, async () => {
+		const packs = await discoverSkillPacks();
+		const available = packs.length > 0 ? packs.map(pack => pack.id).join(', ') : 'none installed';
+
+		return {
+			tools: [
+				{
+					name: 'skill',
+					description:
+						`Use a skill pack from .floyd/skills or ~/.floyd/skills (available: ${available}). ` +
+						'Without `script`, returns the skill\'s instructions and scripts; with `script`, runs that bundled script in the project directory.',
+					inputSchema: {
+						type: 'object',
+						properties: {
+							skill: {
+								type: 'string',
+								description: 'Skill id (its directory name)',
+							},
+							script: {
+								type: 'string',
+								description: 'Script to run, relative to the skill\'s scripts/ directory',
+							},
+							args: {
+								type: 'array',
+								items: {type: 'string'},
+								description: 'Arguments passed to the script',
+							},
+						},
+						required: ['skill'],
+					},
+				},
+			],
+		};
+	});
+
+	server.setRequestHandler(CallToolRequestSchema, async request => {
+		const {name, arguments: args} = request.params;
+
+		try {
+			if (name !== 'skill') {
+				throw new Error(`Unknown tool: ${name}`);
+			}
+
+			const {skill, script, args: scriptArgs} = args as {
+				skill: string;
+				script?: string;
+				args?: string[];
+			};
+			const packs = await discoverSkillPacks();
+			const pack = packs.find(candidate => candidate.id === skill || candidate.name === skill);
+			if (!pack) {
+				throw new Error(`Unknown skill "${skill}"`);
+			}
+
+			const result = script
+				? await runSkillScript(pack, script, scriptArgs)
+				: {skill: pack.id, description: pack.description, instructions: pack.instructions, scripts: pack.scripts};
+
+			return {
+				content: [
+					{
+						type: 'text',
+						text: JSON.stringify(result, null, 2),
+					},
+				],
+				isError: 'exitCode' in result && result.exitCode !== 0,
+			};
+		} catch (error) {
+			return {
+				content: [
+					{
+						type: 'text',
+						text: JSON.stringify({
+							error: (error as Error).message,
+							tool: name,
+						}),
+					},
+				],
+				isError: true,
+			};
+		}
+	});
+
+	return server;
+}
+
+/**
+ * Start the skill server (for standalone execution)
+ */
+export async function startSkillServer(): Promise<void> {
+	const server = await createSkillServer();
+	const transport = new StdioServerTransport();
+	await server.connect(transport);
+
+	console.error('Floyd MCP Skill Server started');
+}
+
+// Run server if executed directly
+if (import.meta.url === `file://${process.argv[1]}`) {
+	startSkillServer().catch(console.error);
+}
@@ -0,0 +1,85 @@
+/**
+ * Code Highlighter Tests
+ *
+ * Tests for tokenizing fenced code and picking its language.
+ */
+
+import test from 'ava';
+import {detectCodeLanguage, resolveLanguage, tokenizeCode, tokenizeLines} from '../code-highlighter.ts';
+
+const colored = (code: string, language: Parameters<typeof tokenizeCode>[1]) =>
+	tokenizeCode(code, language)
+		.filter(token => token.type !== 'text')
+		.map(token => [token.type, token.value]);
+
+test('tokenizeCode: keeps every character', t => {
+	const code = 'const x = foo(1, "a"); // hi\n';
+	t.is(
+		tokenizeCode(code, 'ts')
+			.map(token => token.value)
+			.join(''),
+		code,
+	);
+});
+
+test('tokenizeCode: keywords, calls, literals and comments', t => {
+	t.deepEqual(colored('const x = foo(1, "a"); // hi', 'ts'), [
+		['keywords', 'const'],
+		['operators', '='],
+		['functions', 'foo'],
+		['punctuation', '('],
+		['numbers', '1'],
+		['punctuation', ','],
+		['strings', '"a"'],
+		['punctuation', ');'],
+		['comments', '// hi'],
+	]);
+	t.deepEqual(colored('def f():\n    return None  # done', 'python'), [
+		['keywords', 'def'],
+		['functions', 'f'],
+		['punctuation', '()'],
+		['operators', ':'],
+		['keywords', 'return'],
+		['keywords', 'None'],
+		['comments', '# done'],
+	]);
+	t.deepEqual(colored('git push -u origin $BRANCH', 'bash'), [
+		['operators', '-u'],
+		['classes', '$BRANCH'],
+	]);
+	t.deepEqual(colored('{"a": true}', 'json'), [
+		['punctuation', '{'],
+		['keywords', '"a"'],
+		['operators', ':'],
+		['keywords', 'true'],
+		['punctuation', '}'],
+	]);
+	t.deepEqual(colored('plain text', 'text'), []);
+});
+
+test('tokenizeLines: multi-line comments are split per line', t => {
+	t.deepEqual(tokenizeLines('a\n/* x\ny */', 'js'), [
+		[{type: 'text', value: 'a'}],
+		[{type: 'comments', value: '/* x'}],
+		[{type: 'comments', value: 'y */'}],
+	]);
+});
+
+test('resolveLanguage: fence name first, detection as fallback', t => {
+	t.is(resolveLanguage('Python title="x"', ''), 'python');
+	t.is(resolveLanguage('zsh', ''), 'bash');
+	t.is(resolveLanguage('text', 'const a = 1;'), 'text');
+	t.is(resolveLanguage('', 'const a = 1;'), 'javascript');
+	t.is(resolveLanguage('unknownlang', 'package main\n\nfunc main() {}'), 'go');
+});
+
+test('detectCodeLanguage: common snippets', t => {
+	t.is(detectCodeLanguage('{"a": 1}'), 'json');
+	t.is(detectCodeLanguage('def f():\n    pass'), 'python');
+	t.is(detectCodeLanguage('interface A { b: string }'), 'typescript');
+	t.is(detectCodeLanguage('fn main() { let mut x = 1; }'), 'rust');
+	t.is(detectCodeLanguage('#include <stdio.h>'), 'cpp');
+	t.is(detectCodeLanguage('$ npm install'), 'bash');
+	t.is(detectCodeLanguage('name: floyd\nversion: 1'), 'yaml');
+	t.is(detectCodeLanguage('just some words'), 'text');
+});
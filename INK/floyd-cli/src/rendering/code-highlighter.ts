@@ -11,6 +11,7 @@
  * - Function names
  * - Operators
  * - Language-specific patterns
+ * - Language detection from the code itself
  */
 
 import {roleColors} from '../theme/crush-theme.js';
@@ -40,6 +41,13 @@ export type Language =
 	| 'yaml'
 	| 'text';
 
+export type TokenType = keyof typeof roleColors.syntax | 'text';
+
+export interface Token {
+	type: TokenType;
+	value: string;
+}
+
 export interface HighlightOptions {
 	/** Language for syntax highlighting */
 	language?: Language;
@@ -54,6 +62,41 @@ export interface HighlightOptions {
 	startLine?: number;
 }
 
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Fence names that are not Language values themselves
+ */
+const FENCE_LANGUAGES: Record<string, Language> = {
+	javascript: 'javascript',
+	mjs: 'javascript',
+	cjs: 'javascript',
+	node: 'javascript',
+	typescript: 'typescript',
+	mts: 'typescript',
+	python3: 'python',
+	golang: 'go',
+	'c++': 'cpp',
+	cc: 'cpp',
+	h: 'c',
+	hpp: 'cpp',
+	kotlin: 'java',
+	kt: 'java',
+	csharp: 'java',
+	cs: 'java',
+	shell: 'bash',
+	zsh: 'bash',
+	console: 'bash',
+	shellsession: 'bash',
+	jsonc: 'json',
+	json5: 'json',
+	yml: 'yaml',
+	plaintext: 'text',
+	txt: 'text',
+};
+
 // ============================================================================
 // HIGHLIGHTING FUNCTIONS
 // ============================================================================
@@ -78,16 +121,230 @@ export function highlightCode(
 /**
  * Highlight code and return tokens
  *
+ * Tokens keep every character of the input, so joining their values gives
+ * the code back.
+ *
  * @param code - Source code to tokenize
  * @param language - Programming language
  * @returns Array of tokens
  */
 export function tokenizeCode(
 	code: string,
-	_language: Language = 'text',
-): Array<{type: string; value: string}> {
-	// Simplified tokenization - just return the code as a single token
-	return [{type: 'text', value: code}];
+	language: Language = 'text',
+): Token[] {
+	const grammar = getGrammar(language);
+	if (!grammar) {
+		return code ? [{type: 'text', value: code}] : [];
+	}
+
+	const tokens: Token[] = [];
+	const push = (type: TokenType, value: string) => {
+		const last = tokens[tokens.length - 1];
+		if (last && last.type === type) {
+			last.value += value;
+		} else {
+			tokens.push({type, value});
+		}
+	};
+
+	let index = 0;
+	outer: while (index < code.length) {
+		for (const [type, pattern] of grammar.rules) {
+			pattern.lastIndex = index;
+			const match = pattern.exec(code);
+			if (match && match[0].length > 0) {
+				push(type === 'identifier' ? classifyIdentifier(match[0], code, index + match[0].length, grammar) : type, match[0]);
+				index += match[0].length;
+				continue outer;
+			}
+		}
+		push('text', code[index]!);
+		index++;
+	}
+
+	return tokens;
+}
+
+/**
+ * Tokens split into lines (for rendering one <Text> per line)
+ */
+export function tokenizeLines(code: string, language: Language = 'text'): Token[][] {
+	const lines: Token[][] = [[]];
+	for (const token of tokenizeCode(code, language)) {
+		token.value.split('\n').forEach((part, i) => {
+			if (i > 0) lines.push([]);
+			if (part) lines[lines.length - 1]!.push({type: token.type, value: part});
+		});
+	}
+	return lines;
+}
+
+// ============================================================================
+// GRAMMARS
+// ============================================================================
+
+type Rule = [TokenType | 'identifier', RegExp];
+
+interface Grammar {
+	rules: Rule[];
+	keywords: Set<string>;
+}
+
+const words = (list: string) => new Set(list.split(/\s+/));
+
+const KEYWORDS = {
+	js: words(
+		'break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static super switch this throw try typeof var void while with yield async await interface type enum implements private protected public readonly as satisfies declare true false null undefined',
+	),
+	python: words(
+		'and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield self',
+	),
+	go: words(
+		'break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false',
+	),
+	rust: words(
+		'as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while',
+	),
+	c: words(
+		'abstract auto bool boolean break case catch char class const continue default delete do double else enum extends final float for goto if implements import include define int long namespace new null nullptr package private protected public return short signed sizeof static struct switch template this throw throws try typedef union unsigned using virtual void volatile while true false',
+	),
+	bash: words('if then else elif fi for while until do done case esac function in select return local export readonly unset'),
+	data: words('true false null yes no'),
+};
+
+const STRINGS: Rule[] = [
+	['strings', /"(?:\\.|[^"\\\n])*"?/y],
+	['strings', /'(?:\\.|[^'\\\n])*'?/y],
+];
+const NUMBER: Rule = ['numbers', /\b(?:0[xXbBoO][\da-fA-F_]+|\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?)[a-zA-Z]*\b/y];
+const IDENTIFIER: Rule = ['identifier', /[A-Za-z_$][\w$]*/y];
+const OPERATOR: Rule = ['operators', /[+\-*/%=<>!&|^~?:]+/y];
+const PUNCTUATION: Rule = ['punctuation', /[{}()[\];,.@#]/y];
+
+const C_COMMENTS: Rule[] = [
+	['comments', /\/\/[^\n]*/y],
+	['comments', /\/\*[\s\S]*?(?:\*\/|$)/y],
+];
+const HASH_COMMENT: Rule = ['comments', /#[^\n]*/y];
+
+const cLike = (keywords: Set<string>, extra: Rule[] = []): Grammar => ({
+	rules: [...C_COMMENTS, ...extra, ...STRINGS, NUMBER, IDENTIFIER, OPERATOR, PUNCTUATION],
+	keywords,
+});
+
+const GRAMMARS: Record<string, Grammar> = {
+	js: cLike(KEYWORDS.js, [['strings', /`(?:\\.|[^`\\])*`?/y]]),
+	go: cLike(KEYWORDS.go, [['strings', /`[^`]*`?/y]]),
+	rust: cLike(KEYWORDS.rust, [
+		['strings', /'(?:\\.|[^'\\\n])'/y],
+		// Lifetimes
+		['classes', /'\w+/y],
+	]),
+	c: cLike(KEYWORDS.c, [['keywords', /#\s*\w+/y]]),
+	python: {
+		rules: [
+			HASH_COMMENT,
+			['strings', /[rbfu]{0,2}(?:"""[\s\S]*?(?:"""|$)|'''[\s\S]*?(?:'''|$))/y],
+			...STRINGS,
+			NUMBER,
+			['classes', /@[\w.]+/y],
+			IDENTIFIER,
+			OPERATOR,
+			PUNCTUATION,
+		],
+		keywords: KEYWORDS.python,
+	},
+	bash: {
+		rules: [
+			HASH_COMMENT,
+			...STRINGS,
+			['classes', /\$(?:\{[^}\n]*\}?|\w+|[@*#?$!0-9])/y],
+			// Flags like -f or --force
+			['operators', /(?<![\w-])--?[\w-]+/y],
+			NUMBER,
+			['identifier', /[A-Za-z_][\w-]*/y],
+			['operators', /[|&;<>=!]+/y],
+			PUNCTUATION,
+		],
+		keywords: KEYWORDS.bash,
+	},
+	json: {
+		rules: [
+			// Object keys
+			['keywords', /"(?:\\.|[^"\\\n])*"(?=\s*:)/y],
+			...STRINGS,
+			['numbers', /-?\d+(?:\.\d+)?(?:[eE][+-]?\d+)?/y],
+			IDENTIFIER,
+			PUNCTUATION,
+			OPERATOR,
+		],
+		keywords: KEYWORDS.data,
+	},
+	yaml: {
+		rules: [
+			HASH_COMMENT,
+			// Keys at the start of a line or after "- "
+			['keywords', /(?<=^[ \t]*(?:- )?)[\w."'][\w.\-"' ]*?(?=:(?:\s|$))/my],
+			...STRINGS,
+			NUMBER,
+			IDENTIFIER,
+			['punctuation', /[-:{}[\],|>&*!]/y],
+		],
+		keywords: KEYWORDS.data,
+	},
+};
+
+/**
+ * Grammar for a language, or null for plain text
+ */
+function getGrammar(language: Language): Grammar | null {
+	switch (language) {
+		case 'javascript':
+		case 'typescript':
+		case 'js':
+		case 'ts':
+		case 'jsx':
+		case 'tsx':
+			return GRAMMARS['js']!;
+		case 'python':
+		case 'py':
+			return GRAMMARS['python']!;
+		case 'go':
+			return GRAMMARS['go']!;
+		case 'rust':
+		case 'rs':
+			return GRAMMARS['rust']!;
+		case 'java':
+		case 'c':
+		case 'cpp':
+			return GRAMMARS['c']!;
+		case 'bash':
+		case 'sh':
+			return GRAMMARS['bash']!;
+		case 'json':
+			return GRAMMARS['json']!;
+		case 'yaml':
+			return GRAMMARS['yaml']!;
+		default:
+			return null;
+	}
+}
+
+/**
+ * Keyword, function (called or defined right after), class (capitalized)
+ * or plain name
+ */
+function classifyIdentifier(word: string, code: string, end: number, grammar: Grammar): TokenType {
+	if (grammar.keywords.has(word)) {
+		return 'keywords';
+	}
+	if (/^\s*\(/.test(code.slice(end, end + 40))) {
+		return 'functions';
+	}
+	if (/^[A-Z][a-z0-9]\w*$/.test(word)) {
+		return 'classes';
+	}
+	return 'text';
 }
 
 // ============================================================================
@@ -123,6 +380,58 @@ export function detectLanguage(filename: string): Language {
 	return langMap[ext] ?? 'text';
 }
 
+/**
+ * Language for a code fence's info string ("ts", "Python", "shell title=x"),
+ * detected from the code when the fence names none or an unknown one
+ */
+export function resolveLanguage(info: string, code: string): Language {
+	const name = info.trim().split(/[\s{]/)[0]?.toLowerCase() ?? '';
+	const language = FENCE_LANGUAGES[name];
+	if (language) {
+		return language;
+	}
+	if ((getSupportedLanguages() as string[]).includes(name)) {
+		return name as Language;
+	}
+	return detectCodeLanguage(code);
+}
+
+/**
+ * Guess the language of a snippet from its content
+ */
+export function detectCodeLanguage(code: string): Language {
+	const text = code.trim();
+	if (!text) {
+		return 'text';
+	}
+
+	if (/^[[{]/.test(text)) {
+		try {
+			JSON.parse(text);
+			return 'json';
+		} catch {
+			// Not JSON, keep looking
+		}
+	}
+
+	const shebang = text.match(/^#!.*\b(python3?|node|bash|sh|zsh)\b/);
+	if (shebang) {
+		return shebang[1]!.startsWith('python') ? 'python' : shebang[1] === 'node' ? 'javascript' : 'bash';
+	}
+
+	if (/^\s*package \w+\s*$/m.test(text) || /\bfunc (\(\w+ \*?\w+\) )?\w+\(/.test(text)) return 'go';
+	if (/\bfn \w+[(<]|\blet mut\b|\bimpl\b[^\n]*\{|^use \w+::/m.test(text)) return 'rust';
+	if (/^\s*(def \w+\(.*\):|class \w+(\(.*\))?:|from [\w.]+ import |import \w+\s*$)/m.test(text)) return 'python';
+	if (/#include\s*[<"]/.test(text)) return 'cpp';
+	if (/\bpublic (static )?(final )?(class|void|interface)\b/.test(text)) return 'java';
+	if (/^\s*(interface|type) \w+|: (string|number|boolean|void)\b|\bimport type\b/m.test(text)) return 'typescript';
+	if (/\b(const|let|var) \w+\s*=|\bfunction\b|=>|\brequire\(|\bconsole\./.test(text)) return 'javascript';
+	if (/^\s*(\$ |sudo |npm |npx |pnpm |yarn |git |cd |ls |mkdir |export \w+=|echo )/m.test(text)) return 'bash';
+	if (!/[{};]/.test(text) && /^\s*(- )?[\w.-]+:(\s|$)/m.test(text)) return 'yaml';
+
+	return 'text';
+}
+
 /**
  * Format code with line numbers
  */
@@ -172,7 +481,10 @@ export function getSupportedLanguages(): Language[] {
 export default {
 	highlightCode,
 	tokenizeCode,
+	tokenizeLines,
 	detectLanguage,
+	detectCodeLanguage,
+	resolveLanguage,
 	formatWithLineNumbers,
 	getSupportedLanguages,
 };
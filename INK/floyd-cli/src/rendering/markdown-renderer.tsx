@@ -17,6 +17,7 @@ import {type ReactNode} from 'react';
 import {Box, Text} from 'ink';
 import {textColors, accentColors} from '../theme/crush-theme.js';
 import {highlightCode, type Language} from './code-highlighter.js';
+import {MINIMAL_MODE} from '../utils/minimal-mode.js';
 
 // ============================================================================
 // TYPES
@@ -72,8 +73,8 @@ export function MarkdownRenderer({
 	syntaxHighlight = true,
 	inline = false,
 }: MarkdownRendererProps): ReactNode {
-	// If inline mode, return stripped markdown
-	if (inline) {
+	// If inline or minimal mode, return stripped markdown
+	if (inline || MINIMAL_MODE) {
 		const plainText = stripMarkdownBasic(markdown);
 		return <Text color={textColors.primary}>{plainText}</Text>;
 	}
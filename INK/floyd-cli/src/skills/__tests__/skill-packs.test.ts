@@ -0,0 +1,98 @@
+/**
+ * Skill Pack Tests
+ *
+ * Tests for loading SKILL.md packs and putting the relevant ones into the
+ * system prompt.
+ */
+
+import test from 'ava';
+import {mkdtemp, mkdir, writeFile, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {
+	parseSkillFile,
+	discoverSkillPacks,
+	selectSkillPacks,
+	buildSkillPrompt,
+	applySkillPrompt,
+	resolveSkillScript,
+} from '../skill-packs.ts';
+
+const RELEASE_NOTES = `---
+name: Release Notes
+description: Draft release notes from merged changes
+triggers: [changelog, release]
+---
+# Release Notes
+
+Group changes by feature, fix and chore.
+`;
+
+async function makeSkill(root: string, id: string, content: string, scripts: string[] = []): Promise<void> {
+	await mkdir(join(root, id, 'scripts'), {recursive: true});
+	await writeFile(join(root, id, 'SKILL.md'), content);
+	for (const script of scripts) {
+		await writeFile(join(root, id, 'scripts', script), 'echo ok\n');
+	}
+}
+
+test('parseSkillFile: reads front matter and falls back to the body', t => {
+	const pack = parseSkillFile(RELEASE_NOTES, 'release-notes');
+	t.is(pack.name, 'Release Notes');
+	t.deepEqual(pack.triggers, ['changelog', 'release']);
+	t.false(pack.always);
+	t.true(pack.instructions.startsWith('# Release Notes'));
+
+	const bare = parseSkillFile('# Lint\n\nRun the linter first.\n', 'lint');
+	t.is(bare.name, 'lint');
+	t.is(bare.description, 'Run the linter first.');
+});
+
+test('discoverSkillPacks: project packs replace global ones', async t => {
+	const root = await mkdtemp(join(tmpdir(), 'floyd-skills-'));
+	const globalDir = join(root, 'global');
+	const projectDir = join(root, 'project', '.floyd', 'skills');
+	await makeSkill(globalDir, 'release-notes', RELEASE_NOTES);
+	await makeSkill(globalDir, 'lint', '---\nalways: true\n---\nRun the linter.\n');
+	await makeSkill(projectDir, 'release-notes', RELEASE_NOTES, ['draft.sh']);
+	await mkdir(join(projectDir, 'not-a-skill'));
+
+	const packs = await discoverSkillPacks(join(root, 'project'), globalDir);
+
+	t.deepEqual(packs.map(pack => [pack.id, pack.scope]), [
+		['lint', 'global'],
+		['release-notes', 'project'],
+	]);
+	t.deepEqual(packs[1]!.scripts, ['draft.sh']);
+	await t.throwsAsync(resolveSkillScript(packs[1]!, '../SKILL.md'), {message: /outside the scripts/});
+	t.true((await resolveSkillScript(packs[1]!, 'draft.sh')).endsWith(join('scripts', 'draft.sh')));
+	await rm(root, {recursive: true, force: true});
+});
+
+test('selectSkillPacks: picks always-on, enabled and mentioned packs', t => {
+	const base = {instructions: '', scripts: [], path: '', scope: 'project' as const, description: ''};
+	const packs = [
+		{...base, id: 'release-notes', name: 'Release Notes', triggers: ['changelog'], always: false},
+		{...base, id: 'lint', name: 'lint', triggers: [], always: true},
+		{...base, id: 'deploy', name: 'deploy', triggers: [], always: false},
+	];
+
+	t.deepEqual(selectSkillPacks(packs, 'Update the CHANGELOG').map(pack => pack.id), ['release-notes', 'lint']);
+	t.deepEqual(selectSkillPacks(packs, 'redeployment plan').map(pack => pack.id), ['lint']);
+	t.deepEqual(selectSkillPacks(packs, 'hi', new Set(['deploy'])).map(pack => pack.id), ['lint', 'deploy']);
+});
+
+test('applySkillPrompt: replaces the previous skills section', t => {
+	const pack = {
+		...parseSkillFile(RELEASE_NOTES, 'release-notes'),
+		scripts: ['draft.sh'],
+		path: '',
+		scope: 'project' as const,
+	};
+
+	const withSkill = applySkillPrompt('You are FLOYD.', buildSkillPrompt([pack]));
+	t.true(withSkill.includes('### Skill: Release Notes'));
+	t.true(withSkill.includes('skill "release-notes"): draft.sh'));
+	t.is(applySkillPrompt(withSkill, buildSkillPrompt([pack])), withSkill);
+	t.is(applySkillPrompt(withSkill, ''), 'You are FLOYD.');
+});
@@ -0,0 +1,272 @@
+/**
+ * Skill Packs
+ *
+ * Purpose: Load SKILL.md skill packs (instructions plus optional scripts) and put the relevant ones into the system prompt
+ * Exports: parseSkillFile(), loadSkillPack(), discoverSkillPacks(), selectSkillPacks(), buildSkillPrompt(), applySkillPrompt(), resolveSkillScript()
+ * Related: mcp/skill-server.ts (the `skill` tool), /skill in commands/app-commands.ts
+ *
+ * A skill pack is a directory in .floyd/skills or ~/.floyd/skills:
+ *
+ *   release-notes/
+ *     SKILL.md          front matter (name, description, triggers, always) + instructions
+ *     scripts/draft.sh  optional, run with the `skill` tool
+ *
+ * A pack's instructions only enter the system prompt while it is relevant
+ * (the request mentions its name or one of its triggers) or explicitly
+ * enabled with /skill enable, so unused skills cost no context.
+ */
+
+import {readdir, readFile, stat} from 'node:fs/promises';
+import {join, resolve, sep} from 'node:path';
+import {homedir} from 'node:os';
+import matter from 'gray-matter';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface SkillPack {
+	/**
+	 * Directory name, used to enable the pack and to call its scripts
+	 */
+	id: string;
+
+	name: string;
+
+	description: string;
+
+	/**
+	 * Words or phrases that make the pack relevant to a request
+	 */
+	triggers: string[];
+
+	/**
+	 * Always part of the system prompt
+	 */
+	always: boolean;
+
+	/**
+	 * Body of SKILL.md
+	 */
+	instructions: string;
+
+	/**
+	 * Files in scripts/, relative to it
+	 */
+	scripts: string[];
+
+	path: string;
+
+	scope: 'project' | 'global';
+}
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+export const SKILL_FILE = 'SKILL.md';
+
+export const SKILL_SCRIPTS_DIR = 'scripts';
+
+const PROMPT_START = '<!-- floyd:skills:start -->';
+const PROMPT_END = '<!-- floyd:skills:end -->';
+
+/**
+ * Characters of instructions kept per pack
+ */
+const MAX_INSTRUCTION_CHARS = 12_000;
+
+// ============================================================================
+// LOADING
+// ============================================================================
+
+function toList(value: unknown): string[] {
+	if (Array.isArray(value)) {
+		return value.map(String).map(item => item.trim()).filter(Boolean);
+	}
+	if (typeof value === 'string') {
+		return value.split(',').map(item => item.trim()).filter(Boolean);
+	}
+	return [];
+}
+
+/**
+ * Parse SKILL.md; name and description fall back to the directory name and
+ * the first line of the instructions
+ */
+export function parseSkillFile(
+	content: string,
+	id: string,
+): Omit<SkillPack, 'scripts' | 'path' | 'scope'> {
+	const {data, content: body} = matter(content);
+	const instructions = body.trim();
+	const firstLine = instructions.split('\n').find(line => line.trim() && !line.startsWith('#')) ?? '';
+
+	return {
+		id,
+		name: typeof data['name'] === 'string' ? data['name'] : id,
+		description: typeof data['description'] === 'string' ? data['description'] : firstLine.trim(),
+		triggers: toList(data['triggers']),
+		always: data['always'] === true,
+		instructions: instructions.length > MAX_INSTRUCTION_CHARS
+			? `${instructions.slice(0, MAX_INSTRUCTION_CHARS)}\n\n[... truncated]`
+			: instructions,
+	};
+}
+
+async function listScripts(dir: string, prefix = ''): Promise<string[]> {
+	const entries = await readdir(dir, {withFileTypes: true}).catch(() => []);
+	const scripts: string[] = [];
+	for (const entry of entries) {
+		const relative = prefix ? `${prefix}/${entry.name}` : entry.name;
+		if (entry.isDirectory()) {
+			scripts.push(...(await listScripts(join(dir, entry.name), relative)));
+		} else if (entry.isFile()) {
+			scripts.push(relative);
+		}
+	}
+	return scripts.sort();
+}
+
+/**
+ * Load the pack in a directory, or null when it has no SKILL.md
+ */
+export async function loadSkillPack(
+	path: string,
+	scope: SkillPack['scope'],
+): Promise<SkillPack | null> {
+	const id = path.split(sep).filter(Boolean).pop() ?? path;
+	const content = await readFile(join(path, SKILL_FILE), 'utf-8').catch(() => null);
+	if (content === null) {
+		return null;
+	}
+
+	return {
+		...parseSkillFile(content, id),
+		scripts: await listScripts(join(path, SKILL_SCRIPTS_DIR)),
+		path,
+		scope,
+	};
+}
+
+/**
+ * Packs in ~/.floyd/skills and <cwd>/.floyd/skills; a project pack replaces
+ * a global one with the same id
+ */
+export async function discoverSkillPacks(
+	cwd: string = process.cwd(),
+	globalDir: string = join(homedir(), '.floyd', 'skills'),
+): Promise<SkillPack[]> {
+	const packs = new Map<string, SkillPack>();
+	const sources: Array<[string, SkillPack['scope']]> = [
+		[globalDir, 'global'],
+		[join(cwd, '.floyd', 'skills'), 'project'],
+	];
+
+	for (const [dir, scope] of sources) {
+		const entries = await readdir(dir, {withFileTypes: true}).catch(() => []);
+		for (const entry of entries) {
+			if (!entry.isDirectory()) {
+				continue;
+			}
+			const pack = await loadSkillPack(join(dir, entry.name), scope);
+			if (pack) {
+				packs.set(pack.id, pack);
+			}
+		}
+	}
+
+	return [...packs.values()].sort((a, b) => a.id.localeCompare(b.id));
+}
+
+// ============================================================================
+// SELECTION & PROMPT
+// ============================================================================
+
+function mentions(text: string, phrase: string): boolean {
+	const escaped = phrase.toLowerCase().replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+	return new RegExp(`(^|[^a-z0-9])${escaped}($|[^a-z0-9])`).test(text);
+}
+
+/**
+ * Packs that belong in the prompt for a request: always-on and enabled
+ * packs, and those whose id, name or a trigger the request mentions
+ */
+export function selectSkillPacks(
+	packs: SkillPack[],
+	request: string,
+	enabled: ReadonlySet<string> = new Set(),
+): SkillPack[] {
+	const text = request.toLowerCase();
+	return packs.filter(
+		pack =>
+			pack.always ||
+			enabled.has(pack.id) ||
+			[pack.id, pack.name, ...pack.triggers].some(phrase => mentions(text, phrase)),
+	);
+}
+
+/**
+ * System prompt section for the selected packs ('' when none)
+ */
+export function buildSkillPrompt(packs: SkillPack[]): string {
+	if (packs.length === 0) {
+		return '';
+	}
+
+	const sections = packs.map(pack => {
+		const lines = [`### Skill: ${pack.name}`, '', pack.instructions];
+		if (pack.scripts.length > 0) {
+			lines.push(
+				'',
+				`Scripts (run with the \`skill\` tool, skill "${pack.id}"): ${pack.scripts.join(', ')}`,
+			);
+		}
+		return lines.join('\n');
+	});
+
+	return [
+		PROMPT_START,
+		'## Active Skills',
+		'',
+		'Follow these skill instructions where they apply to the request.',
+		'',
+		sections.join('\n\n'),
+		PROMPT_END,
+	].join('\n');
+}
+
+/**
+ * Replace the skills section of a system prompt (added at the end)
+ */
+export function applySkillPrompt(systemPrompt: string, section: string): string {
+	const start = systemPrompt.indexOf(PROMPT_START);
+	const end = systemPrompt.indexOf(PROMPT_END);
+	const base = start !== -1 && end > start
+		? `${systemPrompt.slice(0, start).trimEnd()}${systemPrompt.slice(end + PROMPT_END.length)}`
+		: systemPrompt;
+
+	return section ? `${base.trimEnd()}\n\n${section}` : base.trimEnd();
+}
+
+// ============================================================================
+// SCRIPTS
+// ============================================================================
+
+/**
+ * Absolute path of a pack's script; refuses paths outside its scripts/
+ */
+export async function resolveSkillScript(pack: SkillPack, script: string): Promise<string> {
+	const scriptsDir = resolve(pack.path, SKILL_SCRIPTS_DIR);
+	const scriptPath = resolve(scriptsDir, script);
+	if (!scriptPath.startsWith(scriptsDir + sep)) {
+		throw new Error(`Script "${script}" is outside the scripts of skill "${pack.id}"`);
+	}
+
+	const info = await stat(scriptPath).catch(() => null);
+	if (!info?.isFile()) {
+		const available = pack.scripts.length > 0 ? pack.scripts.join(', ') : 'none';
+		throw new Error(`Skill "${pack.id}" has no script "${script}" (available: ${available})`);
+	}
+	return scriptPath;
+}
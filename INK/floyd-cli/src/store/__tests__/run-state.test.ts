@@ -0,0 +1,177 @@
+/**
+ * Run State Tests
+ *
+ * Tests for the pure run state transitions and the updates derived from them.
+ */
+
+import test from 'ava';
+import {
+	initialRunState,
+	reduceRunEvent,
+	replayRunEvents,
+	diffRunState,
+	describeRunError,
+	type RunEvent,
+} from '../run-state.ts';
+
+const submitted: RunEvent = {
+	type: 'submitted',
+	text: 'hello',
+	userMessageId: 'user-1',
+	assistantMessageId: 'assistant-1',
+	phrase: 'Pondering...',
+	at: 1,
+};
+
+test('a successful run streams the reply and ends idle', t => {
+	const state = replayRunEvents([
+		submitted,
+		{type: 'text', text: 'Hi'},
+		{type: 'text', text: ' there'},
+		{type: 'completed', at: 5},
+		{type: 'finished'},
+	]);
+
+	t.false(state.busy);
+	t.is(state.status, 'idle');
+	t.is(state.phrase, null);
+	t.deepEqual(state.messages, [
+		{id: 'user-1', role: 'user', content: 'hello', timestamp: 1},
+		{id: 'assistant-1', role: 'assistant', content: 'Hi there', timestamp: 5, streaming: false},
+	]);
+});
+
+test('text inside a thinking block is not part of the reply', t => {
+	const state = replayRunEvents([
+		submitted,
+		{type: 'thinking_started', phrase: 'Scheming...'},
+		{type: 'text', text: 'secret plan'},
+		{type: 'thinking_ended'},
+		{type: 'text', text: 'Done.'},
+	]);
+
+	t.is(state.content, 'Done.');
+	t.is(state.status, 'streaming');
+	t.is(state.phrase, null);
+	t.true(state.busy);
+});
+
+test('thinking is kept on the assistant message, apart from the reply', t => {
+	const state = replayRunEvents([
+		submitted,
+		{type: 'thinking', text: 'The user wants '},
+		{type: 'thinking', text: 'a greeting.'},
+		{type: 'text', text: 'Hi!'},
+		{type: 'completed', at: 5},
+	]);
+
+	t.is(state.messages[1].thinking, 'The user wants a greeting.');
+	t.is(state.messages[1].content, 'Hi!');
+});
+
+test('a failed run keeps the partial reply and adds an error message', t => {
+	const failed = replayRunEvents([
+		submitted,
+		{type: 'text', text: 'Partial'},
+		{type: 'failed', message: '429 Too Many Requests', details: 'Rate limit exceeded', errorMessageId: 'error-9', at: 9},
+	]);
+
+	t.is(failed.status, 'error');
+	t.is(failed.messages[1].content, 'Partial');
+	t.false(failed.messages[1].streaming);
+	t.deepEqual(failed.messages[2], {
+		id: 'error-9',
+		role: 'assistant',
+		content: '[!] Error: 429 Too Many Requests\n\nRate limit exceeded',
+		timestamp: 9,
+	});
+
+	const finished = reduceRunEvent(failed, {type: 'finished'});
+	t.is(finished.status, 'idle');
+	t.false(finished.busy);
+});
+
+test('an interrupted run keeps the partial reply, marked as interrupted', t => {
+	const state = replayRunEvents([
+		submitted,
+		{type: 'text', text: 'Refactoring the'},
+		{type: 'interrupted', at: 7},
+		{type: 'finished'},
+	]);
+
+	t.false(state.busy);
+	t.is(state.status, 'idle');
+	t.deepEqual(state.messages[1], {
+		id: 'assistant-1',
+		role: 'assistant',
+		content: 'Refactoring the\n\n[Interrupted by user]',
+		timestamp: 7,
+		streaming: false,
+		stopReason: 'interrupted',
+	});
+
+	// Interrupted before any text arrived
+	const empty = replayRunEvents([submitted, {type: 'interrupted', at: 2}]);
+	t.is(empty.messages[1].content, '[Interrupted by user]');
+});
+
+test('a cancelled run keeps the partial reply, marked as cancelled', t => {
+	const state = replayRunEvents([
+		submitted,
+		{type: 'text', text: 'Step one is'},
+		{type: 'interrupted', at: 9, reason: 'cancelled'},
+		{type: 'finished'},
+	]);
+
+	t.false(state.busy);
+	t.is(state.messages[1].content, 'Step one is\n\n[Cancelled by user]');
+	t.is(state.messages[1].stopReason, 'cancelled');
+	t.false(state.messages[1].streaming);
+});
+
+test('tool calls are recorded on the assistant message with their full output', t => {
+	const output = 'line\n'.repeat(500);
+	const state = replayRunEvents([
+		submitted,
+		{type: 'text', text: 'Reading'},
+		{type: 'tool_started', id: 'call-1', name: 'read_file', at: 10},
+		{type: 'tool_started', id: 'call-2', name: 'run', at: 11},
+		{type: 'tool_finished', id: 'call-1', output, at: 30},
+		{type: 'tool_finished', id: 'call-2', error: 'exit code 1', at: 41},
+	]);
+
+	t.is(state.messages[1].content, 'Reading');
+	t.deepEqual(state.messages[1].toolCalls, [
+		{id: 'call-1', name: 'read_file', status: 'success', output, error: undefined, startedAt: 10, duration: 20},
+		{id: 'call-2', name: 'run', status: 'error', output: undefined, error: 'exit code 1', startedAt: 11, duration: 30},
+	]);
+});
+
+test('diffRunState lists the updates for each step', t => {
+	const start = initialRunState();
+	const afterSubmit = reduceRunEvent(start, submitted);
+	t.deepEqual(
+		diffRunState(start, afterSubmit).map(effect => effect.type),
+		['set_busy', 'set_status', 'set_phrase', 'clear_streaming', 'add_message', 'add_message'],
+	);
+
+	const afterText = reduceRunEvent(afterSubmit, {type: 'text', text: 'Hi'});
+	t.deepEqual(diffRunState(afterSubmit, afterText), [
+		{type: 'append_streaming', text: 'Hi'},
+		{type: 'update_message', id: 'assistant-1', updates: {role: 'assistant', content: 'Hi', timestamp: 1, streaming: true}},
+	]);
+
+	const afterDone = reduceRunEvent(afterText, {type: 'completed', at: 2});
+	t.deepEqual(
+		diffRunState(afterText, afterDone).map(effect => effect.type),
+		['clear_streaming', 'update_message'],
+	);
+});
+
+test('describeRunError adds hints for common API errors', t => {
+	t.deepEqual(describeRunError(new Error('401 Unauthorized')), {
+		message: '401 Unauthorized',
+		details: 'API authentication failed - check your API key',
+	});
+	t.deepEqual(describeRunError('boom'), {message: 'boom'});
+});
@@ -92,6 +92,10 @@ export interface ResponseTimeMetrics {
 	p50: number;
 	p95: number;
 	p99: number;
+	/** Time until the first token (network + provider queue) */
+	firstTokenTimes: number[];
+	firstTokenAverage: number;
+	firstTokenP95: number;
 }
 
 /**
@@ -147,7 +151,7 @@ export interface DashboardActions {
 	updateStreak: () => void;
 
 	// Response time tracking
-	recordResponseTime: (duration: number) => void;
+	recordResponseTime: (duration: number, firstTokenMs?: number) => void;
 
 	// Cost tracking
 	calculateCost: (inputTokens: number, outputTokens: number) => void;
@@ -214,6 +218,9 @@ const initialDashboardMetrics: DashboardMetrics = {
 		p50: 0,
 		p95: 0,
 		p99: 0,
+		firstTokenTimes: [],
+		firstTokenAverage: 0,
+		firstTokenP95: 0,
 	},
 	costs: {
 		totalCost: 0,
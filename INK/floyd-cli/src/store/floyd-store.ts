@@ -12,6 +12,7 @@ import {useRef, useMemo, useCallback} from 'react';
 import {create} from 'zustand';
 import {persist, createJSONStorage} from 'zustand/middleware';
 import {produce} from 'immer';
+import type {ChatToolCall} from 'floyd-agent-core/ui';
 // Message type matches Anthropic SDK format
 export type Message = {
 	role: 'user' | 'assistant' | 'system' | 'tool';
@@ -70,8 +71,18 @@ export interface ConversationMessage extends Message {
 	duration?: number;
 	/** Whether this message is currently being streamed */
 	streaming?: boolean;
+	/** Tools the assistant called while writing this message */
+	toolCalls?: ConversationToolCall[];
+	/** Reasoning the model wrote before the reply */
+	thinking?: string;
 }
 
+/**
+ * A tool call made during an assistant message, with its full output
+ * (shared with the wrapper CLI through floyd-agent-core/ui)
+ */
+export type ConversationToolCall = ChatToolCall;
+
 /**
  * Tool execution record for tracking usage
  */
@@ -330,6 +341,8 @@ interface OverlaySlice {
 	showFilePicker: boolean;
 	/** Diff preview overlay visibility */
 	showDiffPreview: boolean;
+	/** Tool playground overlay visibility */
+	showToolPlayground: boolean;
 	/** Set overlay visibility by name */
 	setOverlay: (name: keyof OverlayState, value: boolean) => void;
 	/** Toggle overlay by name */
@@ -354,6 +367,7 @@ type OverlayState = Pick<
 	| 'showSessionSwitcher'
 	| 'showFilePicker'
 	| 'showDiffPreview'
+	| 'showToolPlayground'
 >;
 
 /**
@@ -382,8 +396,8 @@ interface DashboardSlice {
 	updateActivityTime: (isActive: boolean) => void;
 	/** Update streak */
 	updateStreak: () => void;
-	/** Record response time */
-	recordResponseTime: (duration: number) => void;
+	/** Record response time (and time to first token, when known) */
+	recordResponseTime: (duration: number, firstTokenMs?: number) => void;
 	/** Set token budget */
 	setTokenBudget: (budget: number) => void;
 	/** Reset all dashboard metrics */
@@ -493,6 +507,7 @@ const initialOverlayState: Omit<
 	showSessionSwitcher: false,
 	showFilePicker: false,
 	showDiffPreview: false,
+	showToolPlayground: false,
 };
 
 const initialDashboardState: Omit<
@@ -625,6 +640,7 @@ export const useFloydStore = create<FloydStore>()(
 					showSessionSwitcher: false,
 					showFilePicker: false,
 					showDiffPreview: false,
+					showToolPlayground: false,
 				}),
 
 			hasOpenOverlay: () => {
@@ -638,7 +654,8 @@ export const useFloydStore = create<FloydStore>()(
 					state.showConfig ||
 					state.showSessionSwitcher ||
 					state.showFilePicker ||
-					state.showDiffPreview
+					state.showDiffPreview ||
+					state.showToolPlayground
 				);
 			},
 
@@ -1113,11 +1130,19 @@ export const useFloydStore = create<FloydStore>()(
 					}
 				})),
 
-			recordResponseTime: (duration) =>
+			recordResponseTime: (duration, firstTokenMs) =>
 				set(produce(state => {
 					const metrics = state.dashboardMetrics.responseTime;
 					metrics.times.push(duration);
 
+					if (firstTokenMs !== undefined) {
+						// Older persisted metrics have no first-token history
+						metrics.firstTokenTimes = [...(metrics.firstTokenTimes ?? []), firstTokenMs].slice(-1000);
+						const sortedFirst = [...metrics.firstTokenTimes].sort((a, b) => a - b);
+						metrics.firstTokenAverage = sortedFirst.reduce((a, b) => a + b, 0) / sortedFirst.length;
+						metrics.firstTokenP95 = sortedFirst[Math.floor(sortedFirst.length * 0.95)];
+					}
+
 					// Keep last 1000 measurements
 					if (metrics.times.length > 1000) {
 						metrics.times = metrics.times.slice(-1000);
@@ -1301,7 +1326,8 @@ export const selectHasOpenOverlay = (state: FloydStore) =>
 	state.showConfig ||
 	state.showSessionSwitcher ||
 	state.showFilePicker ||
-	state.showDiffPreview;
+	state.showDiffPreview ||
+	state.showToolPlayground;
 
 /**
  * Get dashboard metrics
@@ -1360,6 +1386,8 @@ export const selectResponseTimes = (state: FloydStore) => ({
 	p50: state.dashboardMetrics.responseTime.p50,
 	p95: state.dashboardMetrics.responseTime.p95,
 	p99: state.dashboardMetrics.responseTime.p99,
+	firstTokenAverage: state.dashboardMetrics.responseTime.firstTokenAverage ?? 0,
+	firstTokenP95: state.dashboardMetrics.responseTime.firstTokenP95 ?? 0,
 });
 
 /**
@@ -0,0 +1,21 @@
+/**
+ * Run State
+ *
+ * The run reducer (submit → thinking → streaming → done/failed) lives in
+ * floyd-agent-core/ui so the Ink TUI and the wrapper CLI share one set of
+ * transitions. The run loop in app.tsx turns engine output into RunEvents
+ * and applies the effects diffRunState() returns to the store.
+ *
+ * @module store/run-state
+ */
+
+export {
+	initialRunState,
+	reduceRunEvent,
+	replayRunEvents,
+	diffRunState,
+	describeRunError,
+	type RunEvent,
+	type RunState,
+	type RunEffect,
+} from 'floyd-agent-core/ui';
@@ -14,6 +14,7 @@ import {create} from 'zustand';
 import {persist, createJSONStorage} from 'zustand/middleware';
 import {v4 as uuidv4} from 'uuid';
 import type {Message} from 'floyd-agent-core';
+import {getLogger} from '../utils/logger.js';
 
 // ============================================================================
 // TYPES
@@ -183,8 +184,9 @@ export class SessionManager {
 				try {
 					const session = await fs.readJson(path.join(this.sessionsDir, file));
 					sessions.push(session);
-				} catch {
-					// Ignore corrupted files
+				} catch (error) {
+					// Skip corrupted files, but leave a trace
+					getLogger().warn('Skipping corrupted session file', {file, error: String(error)});
 				}
 			}
 		}
@@ -311,8 +313,9 @@ export const useSessionStore = create<SessionState>()(
 								path.join(sessionsDir, file),
 							)) as SessionData;
 							sessions.push(session);
-						} catch {
-							// Ignore corrupted files
+						} catch (error) {
+							// Skip corrupted files, but leave a trace
+							getLogger().warn('Skipping corrupted session file', {file, error: String(error)});
 						}
 					}
 				}
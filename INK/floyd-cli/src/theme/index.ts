@@ -160,6 +160,12 @@ export type {
 	GradientBorderConfig,
 } from './borders';
 
+// ----------------------------------------------------------------------------
+// User Themes (~/.floyd/themes/)
+// ----------------------------------------------------------------------------
+
+export {applyThemePalette, getThemeManager, resetThemeManager} from './user-themes';
+
 // ----------------------------------------------------------------------------
 // Default Export (Complete Theme)
 // ----------------------------------------------------------------------------
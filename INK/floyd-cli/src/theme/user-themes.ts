@@ -0,0 +1,141 @@
+/**
+ * User Themes
+ *
+ * Applies the shared theme palette (floyd-agent-core/ui) to the CRUSH theme
+ * objects. Components read colors from these objects while rendering, so a
+ * theme switch or /theme reload shows up on the next render. The wrapper CLI
+ * loads the same ~/.floyd/themes/ files.
+ *
+ * @module theme/user-themes
+ */
+
+import {
+	ThemeManager,
+	BUILTIN_THEMES,
+	DEFAULT_THEME_NAME,
+	deriveThemeRoles,
+	type ThemeDefinition,
+	type ThemeManagerOptions,
+} from 'floyd-agent-core/ui';
+import {
+	bgColors,
+	textColors,
+	accentColors,
+	statusColors,
+	crushTheme,
+	roleColors,
+	floydTheme,
+	floydRoles,
+} from './crush-theme.js';
+
+// ============================================================================
+// PALETTE
+// ============================================================================
+
+/**
+ * CharmTone syntax colors, kept for the default theme
+ */
+const CRUSH_SYNTAX = {...roleColors.syntax};
+
+/**
+ * Code highlighting colors for a palette
+ */
+export function deriveSyntaxColors(c: ThemeDefinition['colors']): Record<keyof typeof roleColors.syntax, string> {
+	return {
+		keywords: c.info,
+		functions: c.success,
+		strings: c.warning,
+		numbers: c.accent,
+		comments: c.textSubtle,
+		classes: c.textSelected,
+		operators: c.secondary,
+		punctuation: c.highlight,
+	};
+}
+
+/**
+ * Copy a theme's colors into the CRUSH theme objects
+ * (extended CharmTone colors and the diff colors are not themed)
+ */
+export function applyThemePalette(theme: ThemeDefinition): void {
+	const c = theme.colors;
+
+	Object.assign(bgColors, {base: c.bgBase, elevated: c.bgElevated, overlay: c.bgOverlay, modal: c.bgModal});
+	Object.assign(textColors, {
+		primary: c.textPrimary,
+		secondary: c.textSecondary,
+		tertiary: c.textTertiary,
+		subtle: c.textSubtle,
+		selected: c.textSelected,
+		inverse: c.textInverse,
+	});
+	Object.assign(accentColors, {
+		primary: c.primary,
+		secondary: c.secondary,
+		tertiary: c.accent,
+		highlight: c.highlight,
+		info: c.info,
+	});
+	Object.assign(statusColors, {
+		ready: c.success,
+		working: c.primary,
+		warning: c.warning,
+		error: c.error,
+		blocked: c.secondary,
+		online: c.success,
+	});
+
+	const legacy = {
+		primary: c.primary,
+		secondary: c.secondary,
+		tertiary: c.accent,
+		accent: c.highlight,
+		bgBase: c.bgBase,
+		bgSubtle: c.bgOverlay,
+		bgOverlay: c.bgModal,
+		fgBase: c.textPrimary,
+		fgMuted: c.textSecondary,
+		fgSubtle: c.textSubtle,
+		fgSelected: c.textSelected,
+		border: c.bgOverlay,
+		borderFocus: c.primary,
+		success: c.success,
+		error: c.error,
+		warning: c.warning,
+		info: c.info,
+	};
+	Object.assign(crushTheme.legacy, legacy);
+	Object.assign(floydTheme.colors, legacy);
+
+	const roles = deriveThemeRoles(c);
+	Object.assign(roleColors, roles);
+	Object.assign(floydRoles, roles);
+	Object.assign(roleColors.syntax, theme.name === DEFAULT_THEME_NAME ? CRUSH_SYNTAX : deriveSyntaxColors(c));
+}
+
+// ============================================================================
+// SINGLETON
+// ============================================================================
+
+let themeManager: ThemeManager | null = null;
+
+/**
+ * Get or create the theme manager
+ * The active theme is applied immediately and again on every change.
+ */
+export function getThemeManager(options?: ThemeManagerOptions): ThemeManager {
+	if (!themeManager) {
+		themeManager = new ThemeManager(options);
+		applyThemePalette(themeManager.current);
+		themeManager.onChange(applyThemePalette);
+	}
+	return themeManager;
+}
+
+/**
+ * Reset the theme manager and restore the default palette (for testing)
+ */
+export function resetThemeManager(): void {
+	themeManager = null;
+	applyThemePalette(BUILTIN_THEMES[0]!);
+}
@@ -21,6 +21,7 @@ import {
 	thinkingAnimation,
 } from '../../theme/animations.js';
 import {getRandomWhimsicalPhrase} from '../../utils/whimsical-phrases.js';
+import {MINIMAL_MODE} from '../../utils/minimal-mode.js';
 
 // ============================================================================
 // TYPES
@@ -174,7 +175,7 @@ function useStreamingAnimation(
 	const prevContent = useRef(content);
 
 	useEffect(() => {
-		if (!animate || !enabled) {
+		if (!animate || !enabled || MINIMAL_MODE) {
 			setDisplayedContent(content);
 			return;
 		}
@@ -259,7 +260,7 @@ function ThoughtBlockView({
 
 	// Animate color during thinking/streaming
 	useEffect(() => {
-		if (thought.status === 'thinking' || thought.status === 'streaming') {
+		if (!MINIMAL_MODE && (thought.status === 'thinking' || thought.status === 'streaming')) {
 			const colorGen = thinkingColorFrames();
 			const interval = setInterval(() => {
 				setThinkingColor(colorGen.next().value as string);
@@ -507,7 +508,7 @@ export function CompactThinkingStream({
 	);
 
 	useEffect(() => {
-		if (status === 'thinking' || status === 'streaming') {
+		if (!MINIMAL_MODE && (status === 'thinking' || status === 'streaming')) {
 			const colorGen = thinkingColorFrames();
 			const interval = setInterval(() => {
 				setThinkingColor(colorGen.next().value as string);
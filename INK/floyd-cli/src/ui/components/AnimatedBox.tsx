@@ -9,6 +9,7 @@ import {useState, useEffect, type ReactNode} from 'react';
 import {Box, Text} from 'ink';
 import {floydRoles, floydTheme} from '../../theme/crush-theme.js';
 import {type AnimationPreset} from '../../theme/animations.js';
+import {MINIMAL_MODE} from '../../utils/minimal-mode.js';
 
 export interface AnimatedBoxProps {
 	/** Content inside the box */
@@ -58,7 +59,7 @@ function usePulseAnimation(enabled: boolean, interval = 500) {
 	const [opacity, setOpacity] = useState(1);
 
 	useEffect(() => {
-		if (!enabled) return;
+		if (!enabled || MINIMAL_MODE) return;
 
 		const timer = setInterval(() => {
 			setOpacity(prev => (prev === 1 ? 0.5 : 1));
@@ -77,7 +78,7 @@ function useColorAnimation(enabled: boolean, colors: string[], interval = 100) {
 	const [colorIndex, setColorIndex] = useState(0);
 
 	useEffect(() => {
-		if (!enabled) return;
+		if (!enabled || MINIMAL_MODE) return;
 
 		const timer = setInterval(() => {
 			setColorIndex(prev => (prev + 1) % colors.length);
@@ -96,7 +97,7 @@ function useLoadingAnimation(enabled: boolean) {
 	const [frame, setFrame] = useState(0);
 
 	useEffect(() => {
-		if (!enabled) return;
+		if (!enabled || MINIMAL_MODE) return;
 
 		const timer = setInterval(() => {
 			setFrame(prev => (prev + 1) % 4);
@@ -247,6 +248,8 @@ export function ThinkingBox({
 	const [dots, setDots] = useState(0);
 
 	useEffect(() => {
+		if (MINIMAL_MODE) return;
+
 		const frameTimer = setInterval(() => {
 			setFrame(prev => (prev + 1) % 15);
 		}, 67);
@@ -0,0 +1,83 @@
+/**
+ * CompletionPopup Component
+ *
+ * Completion list shown under the input bar: slash commands with their
+ * descriptions (or argument values with the command's usage line), and
+ * workspace files for @mentions.
+ *
+ * Keys (handled by MainLayout): ↑↓ move, Tab completes, Esc dismisses.
+ */
+
+import {Box, Text} from 'ink';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+import type {SlashSuggestion} from '../../commands/slash-completion.js';
+
+export interface CompletionPopupProps {
+	/** Suggestions for the current input */
+	items: SlashSuggestion[];
+
+	/** Highlighted suggestion */
+	selectedIndex: number;
+
+	/** Line shown above the list (e.g. command usage) */
+	header?: string;
+
+	/** Shown when there are no suggestions */
+	emptyText?: string;
+
+	/** Maximum suggestions to show */
+	maxVisible?: number;
+}
+
+export function CompletionPopup({items, selectedIndex, header, emptyText, maxVisible = 6}: CompletionPopupProps) {
+	// Keep the selection inside the visible window
+	const start = Math.max(0, Math.min(selectedIndex - maxVisible + 1, items.length - maxVisible));
+	const visible = items.slice(start, start + maxVisible);
+	const labelWidth = Math.max(0, ...visible.map(item => item.label.length)) + 2;
+
+	return (
+		<Box
+			flexDirection="column"
+			width="100%"
+			borderStyle="single"
+			borderColor={floydTheme.colors.border}
+			paddingX={1}
+		>
+			{header && (
+				<Text color={crushTheme.accent.primary} wrap="truncate-end">
+					{header}
+				</Text>
+			)}
+
+			{items.length === 0 && emptyText && <Text color={floydTheme.colors.fgSubtle}>{emptyText}</Text>}
+
+			{visible.map((item, i) => {
+				const isSelected = start + i === selectedIndex;
+				return (
+					<Text
+						key={item.value}
+						color={isSelected ? floydTheme.colors.fgBase : floydTheme.colors.fgMuted}
+						bold={isSelected}
+						wrap="truncate-end"
+					>
+						{isSelected ? '▶ ' : '  '}
+						{item.label.padEnd(labelWidth)}
+						{item.description && <Text color={floydTheme.colors.fgSubtle}>{item.description}</Text>}
+					</Text>
+				);
+			})}
+
+			{items.length > maxVisible && (
+				<Text color={floydTheme.colors.fgSubtle} dimColor>
+					{items.length - maxVisible} more...
+				</Text>
+			)}
+
+			<Text color={floydTheme.colors.fgMuted} dimColor>
+				↑↓: move • Tab: complete • Enter: send • Esc: dismiss
+			</Text>
+		</Box>
+	);
+}
+
+export default CompletionPopup;
@@ -9,6 +9,7 @@ import {useState, useEffect, type ReactNode} from 'react';
 import {Box, Text} from 'ink';
 import {floydRoles, floydTheme} from '../../theme/crush-theme.js';
 import {getGradient, type GradientName} from '../../theme/gradients.js';
+import {MINIMAL_MODE} from '../../utils/minimal-mode.js';
 
 export interface GradientHeaderProps {
 	/** Text to display with gradient */
@@ -114,7 +115,7 @@ export function GradientHeader({
 
 	// Shimmer animation
 	useEffect(() => {
-		if (!animate) return;
+		if (!animate || MINIMAL_MODE) return;
 
 		const interval = setInterval(() => {
 			setFrame(prev => (prev + 1) % 15);
@@ -0,0 +1,107 @@
+/**
+ * HistorySearch Component
+ *
+ * Ctrl+R fuzzy reverse search over prompts stored in ~/.floyd/history.
+ * Replaces the input area while open; the chosen prompt is placed back
+ * in the input for editing.
+ *
+ * Keys: type to filter, ↑↓ or Ctrl+R to move through matches,
+ * Enter to pick, Esc to cancel.
+ */
+
+import {useState, useMemo} from 'react';
+import {Box, Text, useInput} from 'ink';
+import TextInput from 'ink-text-input';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+import {searchInputHistory} from 'floyd-agent-core/utils';
+
+export interface HistorySearchProps {
+	/** Prompt history, oldest first */
+	history: string[];
+
+	/** Called with the chosen entry */
+	onSelect: (entry: string) => void;
+
+	/** Called when the search is dismissed */
+	onCancel: () => void;
+
+	/** Maximum matches to show */
+	maxResults?: number;
+}
+
+export function HistorySearch({history, onSelect, onCancel, maxResults = 8}: HistorySearchProps) {
+	const [query, setQuery] = useState('');
+	const [selectedIndex, setSelectedIndex] = useState(0);
+
+	const matches = useMemo(
+		() => searchInputHistory(history, query, maxResults),
+		[history, query, maxResults],
+	);
+
+	useInput((input, key) => {
+		if (key.escape) {
+			onCancel();
+		} else if (key.upArrow || (key.ctrl && input === 'r')) {
+			setSelectedIndex(prev => Math.min(matches.length - 1, prev + 1));
+		} else if (key.downArrow) {
+			setSelectedIndex(prev => Math.max(0, prev - 1));
+		}
+	});
+
+	// Matches are listed best-first from the bottom up, like shell reverse search
+	const shown = [...matches].reverse();
+
+	return (
+		<Box
+			flexDirection="column"
+			width="100%"
+			borderStyle="double"
+			borderColor={floydTheme.colors.borderFocus}
+			paddingX={1}
+		>
+			{matches.length === 0 ? (
+				<Text color={floydTheme.colors.fgSubtle}>
+					{history.length === 0 ? 'No history yet' : 'No matching prompts'}
+				</Text>
+			) : (
+				shown.map((entry, i) => {
+					const isSelected = matches.length - 1 - i === selectedIndex;
+					return (
+						<Text
+							key={entry}
+							color={isSelected ? floydTheme.colors.fgBase : floydTheme.colors.fgMuted}
+							bold={isSelected}
+							wrap="truncate-end"
+						>
+							{isSelected ? '▶ ' : '  '}
+							{entry}
+						</Text>
+					);
+				})
+			)}
+
+			<Box marginTop={1}>
+				<Text color={crushTheme.accent.primary}>(reverse-i-search) </Text>
+				<TextInput
+					value={query}
+					onChange={value => {
+						setQuery(value);
+						setSelectedIndex(0);
+					}}
+					onSubmit={() => {
+						const entry = matches[selectedIndex];
+						if (entry) onSelect(entry);
+						else onCancel();
+					}}
+					placeholder="type to search history..."
+					focus
+				/>
+			</Box>
+			<Text color={floydTheme.colors.fgMuted} dimColor>
+				↑↓/Ctrl+R: move • Enter: use • Esc: cancel
+			</Text>
+		</Box>
+	);
+}
+
+export default HistorySearch;
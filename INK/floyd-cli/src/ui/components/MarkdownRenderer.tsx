@@ -1,13 +1,15 @@
 import React, {useMemo} from 'react';
 import {Box, Text} from 'ink';
 import {roleColors, textColors} from '../../theme/crush-theme.js';
+import {MINIMAL_MODE} from '../../utils/minimal-mode.js';
+import {resolveLanguage, tokenizeLines} from '../../rendering/code-highlighter.js';
 
 /**
- * Simple LRU cache for parsed markdown
+ * Simple LRU cache for rendered markdown blocks
  */
 class MarkdownCache {
 	private cache = new Map<string, React.ReactNode>();
-	private maxSize = 50; // Cache up to 50 parsed documents
+	private maxSize = 500; // Cache up to 500 rendered blocks
 
 	get(key: string): React.ReactNode | undefined {
 		return this.cache.get(key);
@@ -32,22 +34,160 @@ class MarkdownCache {
 // Global cache instance
 const markdownCache = new MarkdownCache();
 
+const FENCE = /^\s*```/;
+
+/**
+ * Split markdown into finished blocks and the trailing block still being written
+ *
+ * A block is finished by a blank line, or by the closing fence of a code
+ * block. Blocks keep their lines (blank ones included), so rendering the
+ * blocks and then the tail gives the same output as rendering every line.
+ */
+export function splitMarkdownBlocks(text: string): {blocks: string[]; tail: string} {
+	const lines = text.split('\n');
+	// The last line has no newline yet, so it can still change
+	const partial = lines.pop() ?? '';
+
+	const blocks: string[] = [];
+	let current: string[] = [];
+	let inFence = false;
+	const finish = () => {
+		blocks.push(current.join('\n'));
+		current = [];
+	};
+
+	for (const line of lines) {
+		if (inFence) {
+			current.push(line);
+			if (FENCE.test(line)) {
+				inFence = false;
+				finish();
+			}
+		} else if (FENCE.test(line)) {
+			if (current.length > 0) finish();
+			current.push(line);
+			inFence = true;
+		} else {
+			current.push(line);
+			if (!line.trim()) finish();
+		}
+	}
+
+	return {blocks, tail: [...current, partial].join('\n')};
+}
+
+/**
+ * Split a block's lines into plain markdown and fenced code
+ *
+ * A fence that is still open (while streaming) runs to the end of the block.
+ */
+export function splitCodeFences(
+	source: string,
+): Array<{type: 'markdown'; lines: string[]} | {type: 'code'; info: string; code: string}> {
+	const segments: Array<{type: 'markdown'; lines: string[]} | {type: 'code'; info: string; code: string}> = [];
+	const lines = source.split('\n');
+
+	for (let i = 0; i < lines.length; i++) {
+		const line = lines[i]!;
+		if (FENCE.test(line)) {
+			const code: string[] = [];
+			for (i++; i < lines.length && !FENCE.test(lines[i]!); i++) {
+				code.push(lines[i]!);
+			}
+			segments.push({type: 'code', info: line.trim().replace(/^`+/, ''), code: code.join('\n')});
+			continue;
+		}
+		const last = segments[segments.length - 1];
+		if (last?.type === 'markdown') {
+			last.lines.push(line);
+		} else {
+			segments.push({type: 'markdown', lines: [line]});
+		}
+	}
+
+	return segments;
+}
+
 interface MarkdownRendererProps {
 	children: string;
+
+	/** Content is still streaming in; only finished blocks are cached */
+	streaming?: boolean;
 }
 
-export const MarkdownRenderer: React.FC<MarkdownRendererProps> = ({children}) => {
-	const lines = useMemo(() => children.split('\n'), [children]);
+export const MarkdownRenderer: React.FC<MarkdownRendererProps> = ({children, streaming = false}) => {
+	const {blocks, tail} = useMemo(() => splitMarkdownBlocks(children), [children]);
 
+	// Minimal mode: plain text, no per-line formatting
+	if (MINIMAL_MODE) {
+		return <Text color={textColors.primary}>{children}</Text>;
+	}
+
+	// Finished blocks never change, so their elements are reused and React
+	// skips them; while streaming only the tail is rendered on each update
 	return (
 		<Box flexDirection="column">
-			{lines.map((line, i) => (
-				<LineRenderer key={i} line={line} />
+			{blocks.map((block, i) => (
+				<React.Fragment key={i}>{renderCachedBlock(block)}</React.Fragment>
 			))}
+			<React.Fragment key={blocks.length}>
+				{streaming ? <BlockRenderer source={tail} /> : renderCachedBlock(tail)}
+			</React.Fragment>
 		</Box>
 	);
 };
 
+function renderCachedBlock(source: string): React.ReactNode {
+	let node = markdownCache.get(source);
+	if (node === undefined) {
+		node = <BlockRenderer source={source} />;
+		markdownCache.set(source, node);
+	}
+	return node;
+}
+
+const BlockRenderer = React.memo(({source}: {source: string}) => (
+	<>
+		{splitCodeFences(source).map((segment, i) =>
+			segment.type === 'code' ? (
+				<CodeBlockRenderer key={i} info={segment.info} code={segment.code} />
+			) : (
+				<React.Fragment key={i}>
+					{segment.lines.map((line, j) => (
+						<LineRenderer key={j} line={line} />
+					))}
+				</React.Fragment>
+			),
+		)}
+	</>
+));
+
+/**
+ * Fenced code, highlighted for the fence language (or the detected one)
+ * with the active theme's syntax colors
+ */
+const CodeBlockRenderer = React.memo(({info, code}: {info: string; code: string}) => {
+	const language = resolveLanguage(info, code);
+	const lines = tokenizeLines(code, language);
+
+	return (
+		<Box flexDirection="column" borderStyle="single" borderColor={roleColors.hint} paddingX={1}>
+			{language !== 'text' && <Text color={roleColors.hint}>{language}</Text>}
+			{lines.map((tokens, i) => (
+				<Text key={i}>
+					{tokens.length === 0
+						? ' '
+						: tokens.map((token, j) => (
+								<Text key={j} color={token.type === 'text' ? textColors.primary : roleColors.syntax[token.type]}>
+									{token.value}
+								</Text>
+						  ))}
+				</Text>
+			))}
+		</Box>
+	);
+});
+
 const LineRenderer = React.memo(({line}: {line: string}) => {
 	// Header 1-3
 	if (line.startsWith('#')) {
@@ -85,11 +225,6 @@ const LineRenderer = React.memo(({line}: {line: string}) => {
 		}
 	}
 
-	// Code block (triple backticks) - simplified (just renders line)
-	if (line.startsWith('```')) {
-		return <Box borderStyle="single" borderColor={roleColors.hint} paddingX={1}><Text dimColor>{line}</Text></Box>;
-	}
-
 	// Empty line
 	if (!line.trim()) {
 		return <Box height={1} />;
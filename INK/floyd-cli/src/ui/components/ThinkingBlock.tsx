@@ -0,0 +1,57 @@
+/**
+ * ThinkingBlock Component
+ *
+ * Dimmed, collapsible block for the reasoning a model writes before its
+ * reply (extended thinking blocks, reasoning_content, or <thinking> tags).
+ * Collapsed it shows one line: the newest line while the model is still
+ * thinking, the size of the block afterwards. Ctrl+Y (handled by
+ * MainLayout) expands or collapses every block.
+ */
+
+import {Box, Text} from 'ink';
+import {floydTheme, roleColors} from '../../theme/crush-theme.js';
+
+export interface ThinkingBlockProps {
+	/** Reasoning text */
+	text: string;
+
+	/** Show the whole text */
+	expanded?: boolean;
+
+	/** The reply is still being written */
+	streaming?: boolean;
+}
+
+export function ThinkingBlock({text, expanded = false, streaming = false}: ThinkingBlockProps) {
+	const lines = text.trim().split('\n');
+
+	if (!expanded) {
+		const summary = streaming
+			? lines[lines.length - 1]
+			: `${lines.length} ${lines.length === 1 ? 'line' : 'lines'} · Ctrl+Y shows`;
+		return (
+			<Text color={floydTheme.colors.fgSubtle} dimColor wrap="truncate-end">
+				<Text color={roleColors.thinking}>▸ </Text>
+				<Text italic>thinking…</Text> {summary}
+			</Text>
+		);
+	}
+
+	return (
+		<Box flexDirection="column" width="100%">
+			<Text color={floydTheme.colors.fgSubtle} dimColor>
+				<Text color={roleColors.thinking}>▾ </Text>
+				<Text italic>thinking</Text>
+			</Text>
+			<Box marginLeft={2} flexDirection="column">
+				{lines.map((line, index) => (
+					<Text key={index} color={floydTheme.colors.fgSubtle} dimColor italic>
+						{line}
+					</Text>
+				))}
+			</Box>
+		</Box>
+	);
+}
+
+export default ThinkingBlock;
@@ -0,0 +1,125 @@
+/**
+ * ToolResultBlock Component
+ *
+ * Collapsible block for one tool call in the transcript. Collapsed it shows
+ * the first lines of the output; expanded it shows a scrollable window over
+ * the full output.
+ *
+ * Keys (handled by MainLayout): Ctrl+B focuses a block, Enter expands or
+ * collapses it, ↑↓ scroll it, Esc leaves it. /expand <n> opens block n.
+ */
+
+import {Box, Text} from 'ink';
+import Spinner from 'ink-spinner';
+import {floydTheme, floydRoles} from '../../theme/crush-theme.js';
+import {getToolResultView} from '../../utils/tool-results.js';
+
+export interface ToolResultBlockProps {
+	/** Position in the conversation (what /expand takes) */
+	number: number;
+
+	/** Tool name */
+	toolName: string;
+
+	/** Execution status */
+	status: 'pending' | 'running' | 'success' | 'error';
+
+	/** Full output */
+	result?: string;
+
+	/** Error message */
+	error?: string;
+
+	/** Execution duration in ms */
+	duration?: number;
+
+	/** Show the full output (scrollable) */
+	expanded?: boolean;
+
+	/** Keyboard focus is on this block */
+	focused?: boolean;
+
+	/** First output line shown while expanded */
+	scrollOffset?: number;
+}
+
+function formatDuration(ms: number): string {
+	return ms < 1000 ? `${ms}ms` : `${(ms / 1000).toFixed(1)}s`;
+}
+
+export function ToolResultBlock({
+	number,
+	toolName,
+	status,
+	result,
+	error,
+	duration,
+	expanded = false,
+	focused = false,
+	scrollOffset = 0,
+}: ToolResultBlockProps) {
+	const output = error ? `Error: ${error}` : result ?? '';
+	const view = output ? getToolResultView(output, {expanded, scrollOffset}) : null;
+	const color =
+		status === 'error'
+			? floydTheme.colors.error
+			: status === 'success'
+				? floydTheme.colors.success
+				: floydRoles.thinking;
+
+	return (
+		<Box
+			flexDirection="column"
+			borderStyle={focused ? 'double' : 'round'}
+			borderColor={focused ? floydTheme.colors.fgSelected : color}
+			paddingX={1}
+		>
+			<Box gap={1}>
+				<Text color={color}>
+					{status === 'running' || status === 'pending' ? <Spinner type="dots" /> : expanded ? '▾' : '▸'}
+				</Text>
+				<Text color={floydTheme.colors.fgMuted}>#{number}</Text>
+				<Text bold color={floydTheme.colors.fgSelected}>
+					{toolName}
+				</Text>
+				{duration !== undefined && (
+					<Text color={floydTheme.colors.fgMuted} dimColor>
+						({formatDuration(duration)})
+					</Text>
+				)}
+				{view && view.totalLines > 1 && (
+					<Text color={floydTheme.colors.fgSubtle} dimColor>
+						{view.totalLines} lines
+					</Text>
+				)}
+			</Box>
+
+			{view && view.hiddenAbove > 0 && (
+				<Text color={floydTheme.colors.fgSubtle} dimColor>
+					↑ {view.hiddenAbove} more
+				</Text>
+			)}
+
+			{view?.lines.map((line, i) => (
+				<Text key={i} color={error ? floydTheme.colors.error : floydTheme.colors.fgBase} wrap="truncate-end">
+					{line || ' '}
+				</Text>
+			))}
+
+			{view && view.hiddenBelow > 0 && (
+				<Text color={floydTheme.colors.fgSubtle} dimColor>
+					{expanded ? `↓ ${view.hiddenBelow} more` : `… ${view.hiddenBelow} more lines (Enter or /expand ${number})`}
+				</Text>
+			)}
+
+			{focused && (
+				<Text color={floydTheme.colors.fgMuted} dimColor>
+					Enter: {expanded ? 'collapse' : 'expand'}
+					{expanded ? ' • ↑↓: scroll' : ''} • Ctrl+B: next • Esc: back to input
+				</Text>
+			)}
+		</Box>
+	);
+}
+
+export default ToolResultBlock;
@@ -0,0 +1,92 @@
+/**
+ * VimInput Component
+ *
+ * Drop-in replacement for ink-text-input when FLOYD_VIM=1: the prompt
+ * input gets insert, normal and visual modes (see utils/vim-mode.ts).
+ * The mode is shown after the text; the selection is drawn inverted.
+ */
+
+import {useEffect, useRef, useState} from 'react';
+import {Text, useInput} from 'ink';
+import {floydTheme, roleColors} from '../../theme/crush-theme.js';
+import {initialVimState, vimKeypress, type VimState} from '../../utils/vim-mode.js';
+
+export interface VimInputProps {
+	value: string;
+	onChange: (value: string) => void;
+	onSubmit: (value: string) => void;
+	placeholder?: string;
+	/** Whether keys go to this input */
+	focus?: boolean;
+}
+
+const MODE_LABELS = {
+	insert: '-- INSERT --',
+	normal: '-- NORMAL --',
+	visual: '-- VISUAL --',
+} as const;
+
+export function VimInput({value, onChange, onSubmit, placeholder = '', focus = true}: VimInputProps) {
+	const [state, setState] = useState<VimState>(() => initialVimState(value));
+	const stateRef = useRef(state);
+	stateRef.current = state;
+	// Value this input last produced; anything else came from outside
+	// (history, completion, a sent message) and moves the cursor to the end
+	const ownValueRef = useRef(value);
+
+	useEffect(() => {
+		if (value !== ownValueRef.current) {
+			ownValueRef.current = value;
+			const cursor = stateRef.current.mode === 'insert' ? value.length : Math.max(0, value.length - 1);
+			setState(current => ({...current, cursor, anchor: Math.min(current.anchor, value.length), pending: ''}));
+		}
+	}, [value]);
+
+	useInput(
+		(input, key) => {
+			const result = vimKeypress(ownValueRef.current, stateRef.current, input, key);
+			if (!result.handled) {
+				return;
+			}
+			stateRef.current = result.state;
+			setState(result.state);
+			if (result.value !== ownValueRef.current) {
+				ownValueRef.current = result.value;
+				onChange(result.value);
+			}
+			if (result.submit) {
+				onSubmit(result.value);
+			}
+		},
+		{isActive: focus},
+	);
+
+	const {mode, cursor, anchor} = state;
+	const label = <Text color={roleColors.hint}> {MODE_LABELS[mode]}</Text>;
+
+	if (!value) {
+		return (
+			<Text>
+				<Text inverse> </Text>
+				<Text color={floydTheme.colors.fgSubtle}>{placeholder}</Text>
+				{label}
+			</Text>
+		);
+	}
+
+	// Selected range in visual mode, or the cell under the cursor
+	const from = mode === 'visual' ? Math.min(anchor, cursor) : cursor;
+	const to = mode === 'visual' ? Math.max(anchor, cursor) + 1 : cursor + 1;
+	const shown = cursor >= value.length ? `${value} ` : value;
+
+	return (
+		<Text>
+			<Text>{shown.slice(0, from)}</Text>
+			<Text inverse>{shown.slice(from, to)}</Text>
+			<Text>{shown.slice(to)}</Text>
+			{label}
+		</Text>
+	);
+}
+
+export default VimInput;
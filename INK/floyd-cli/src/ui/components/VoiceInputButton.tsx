@@ -57,7 +57,7 @@ export function VoiceInputButton({
 		if (error) {
 			return { icon: '❌', color: 'red', text: 'Error' };
 		}
-		return { icon: '🎤', color: 'green', text: 'Voice Input (Ctrl+R)' };
+		return { icon: '🎤', color: 'green', text: 'Voice Input (Ctrl+V)' };
 	};
 	
 	const { icon, color, text } = getMicIcon();
@@ -0,0 +1,46 @@
+/**
+ * Markdown Blocks Tests
+ *
+ * Tests for splitting streamed markdown into finished blocks and the tail.
+ */
+
+import test from 'ava';
+import {splitCodeFences, splitMarkdownBlocks} from '../MarkdownRenderer.tsx';
+
+test('splitMarkdownBlocks: blank lines finish blocks, the rest is the tail', t => {
+	t.deepEqual(splitMarkdownBlocks('# Title\n\nSome text\nmore'), {
+		blocks: ['# Title\n'],
+		tail: 'Some text\nmore',
+	});
+});
+
+test('splitMarkdownBlocks: blocks and tail cover every line', t => {
+	const text = 'intro\n\n- a\n- b\n\n\n```ts\nconst x = 1;\n\nconst y = 2;\n```\nafter';
+	const {blocks, tail} = splitMarkdownBlocks(text);
+	t.is([...blocks, tail].join('\n'), text);
+});
+
+test('splitMarkdownBlocks: code blocks stay open until the closing fence', t => {
+	const open = splitMarkdownBlocks('text\n```js\nconst a = 1;\n\nconst b');
+	t.deepEqual(open.blocks, ['text']);
+	t.is(open.tail, '```js\nconst a = 1;\n\nconst b');
+
+	const closed = splitMarkdownBlocks('```js\nconst a = 1;\n```\n');
+	t.deepEqual(closed, {blocks: ['```js\nconst a = 1;\n```'], tail: ''});
+});
+
+test('splitMarkdownBlocks: finished blocks do not change as text is appended', t => {
+	const text = 'one\n\ntwo\n\nthree';
+	const before = splitMarkdownBlocks(text.slice(0, 8)).blocks;
+	const after = splitMarkdownBlocks(text).blocks;
+	t.deepEqual(after.slice(0, before.length), before);
+});
+
+test('splitCodeFences: code between fences, open fences run to the end', t => {
+	t.deepEqual(splitCodeFences('intro\n```ts title=a\nconst a = 1;\n```\nafter'), [
+		{type: 'markdown', lines: ['intro']},
+		{type: 'code', info: 'ts title=a', code: 'const a = 1;'},
+		{type: 'markdown', lines: ['after']},
+	]);
+	t.deepEqual(splitCodeFences('```\nstreaming'), [{type: 'code', info: '', code: 'streaming'}]);
+});
@@ -111,6 +111,9 @@ export interface ResponseTimeData {
 	p50: number;
 	p95: number;
 	p99: number;
+	/** Time until the model's first token */
+	firstTokenAverage?: number;
+	firstTokenP95?: number;
 }
 
 export interface ResponseTimeDashboardProps {
@@ -122,7 +125,7 @@ export function ResponseTimeDashboard({
 	data,
 	compact = false,
 }: ResponseTimeDashboardProps) {
-	const {averageTime, p50, p95, p99} = data;
+	const {averageTime, p50, p95, p99, firstTokenAverage, firstTokenP95} = data;
 
 	const formatTime = (ms: number) => ms < 1000 ? `${ms.toFixed(0)}ms` : `${(ms / 1000).toFixed(2)}s`;
 
@@ -150,6 +153,21 @@ export function ResponseTimeDashboard({
 					</Box>
 				</Box>
 
+				{firstTokenAverage ? (
+					<Box flexDirection="row" gap={4}>
+						<Box flexDirection="column">
+							<Text color={floydTheme.colors.fgMuted}>First token</Text>
+							<Text bold color={crushTheme.accent.secondary}>
+								{formatTime(firstTokenAverage)}
+							</Text>
+						</Box>
+						<Box flexDirection="column">
+							<Text color={floydTheme.colors.fgMuted}>First token P95</Text>
+							<Text bold>{formatTime(firstTokenP95 ?? 0)}</Text>
+						</Box>
+					</Box>
+				) : null}
+
 				{!compact && (
 					<Box flexDirection="column">
 						<Text color={floydTheme.colors.fgMuted}>Percentile Explanation</Text>
@@ -157,6 +175,7 @@ export function ResponseTimeDashboard({
 							<Text>P50: 50% of requests complete in this time</Text>
 							<Text>P95: 95% of requests complete in this time</Text>
 							<Text>P99: 99% of requests complete in this time</Text>
+							<Text>First token: wait before the model starts answering (network + queue)</Text>
 						</Box>
 					</Box>
 				)}
@@ -0,0 +1,192 @@
+/**
+ * ProgressLogTable Component
+ *
+ * Renders the .floyd/progress.md execution log as an aligned, colored,
+ * scrollable table, filtered with the same query as /progress in the
+ * wrapper CLI (parsed by floyd-agent-core).
+ */
+
+import {useState, useMemo} from 'react';
+import {Box, Text, useInput} from 'ink';
+import {Frame} from '../crush/Frame.js';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+import {
+	queryProgress,
+	PROGRESS_COLUMNS,
+	PROGRESS_FILE,
+	type ProgressEntry,
+	type ProgressQuery,
+} from 'floyd-agent-core/utils';
+
+export interface ProgressLogTableProps {
+	entries: ProgressEntry[];
+	query?: ProgressQuery;
+	/** Number of rows visible at once */
+	height?: number;
+	/** Total table width in characters */
+	width?: number;
+	/** Enable keyboard scrolling */
+	focus?: boolean;
+	compact?: boolean;
+}
+
+const MIN_COLUMN_WIDTH = 8;
+
+const COLUMNS: string[] = [...PROGRESS_COLUMNS];
+
+/**
+ * Index of the Result column, colored by status
+ */
+const RESULT_COLUMN = 2;
+
+/**
+ * Pick a color for a status cell based on common keywords
+ */
+function statusColor(value: string): string {
+	const lower = value.toLowerCase();
+	if (/(fail|error|broken|blocked)/.test(lower)) return floydTheme.colors.error;
+	if (/(warn|partial|pending)/.test(lower)) return floydTheme.colors.warning;
+	if (/(pass|success|complete|ready|done|succeeds|fixed)/.test(lower)) return floydTheme.colors.success;
+	return floydTheme.colors.fgBase;
+}
+
+/**
+ * Fit a cell into a fixed width, truncating with an ellipsis
+ */
+function fit(value: string, width: number): string {
+	if (value.length <= width) return value.padEnd(width);
+	return value.slice(0, Math.max(0, width - 1)) + '…';
+}
+
+/**
+ * Distribute the available width across columns, preferring natural widths
+ */
+function computeWidths(columns: string[], rows: string[][], total: number): number[] {
+	const natural = columns.map((column, i) =>
+		Math.max(column.length, ...rows.map(row => (row[i] ?? '').length), MIN_COLUMN_WIDTH),
+	);
+	const gaps = columns.length - 1;
+	const budget = Math.max(columns.length * MIN_COLUMN_WIDTH, total - gaps);
+	const naturalTotal = natural.reduce((sum, w) => sum + w, 0);
+	if (naturalTotal <= budget) return natural;
+
+	// Keep the first column (timestamp) at natural width, shrink the rest proportionally
+	const first = Math.min(natural[0] ?? MIN_COLUMN_WIDTH, 19);
+	const restNatural = naturalTotal - (natural[0] ?? 0);
+	const restBudget = budget - first;
+	return natural.map((w, i) =>
+		i === 0 ? first : Math.max(MIN_COLUMN_WIDTH, Math.floor((w / restNatural) * restBudget)),
+	);
+}
+
+export function ProgressLogTable({
+	entries: allEntries,
+	query = {},
+	height = 15,
+	width = 120,
+	focus = false,
+	compact = false,
+}: ProgressLogTableProps) {
+	const entries = useMemo(
+		() => queryProgress(allEntries, query),
+		[allEntries, query.date, query.search, query.last],
+	);
+	const rows = useMemo(
+		() => entries.map(entry => [entry.timestamp, entry.action, entry.result, entry.next]),
+		[entries],
+	);
+	const widths = useMemo(() => computeWidths(COLUMNS, rows, width - 4), [rows, width]);
+
+	// Start scrolled to the most recent entries
+	const maxOffset = Math.max(0, rows.length - height);
+	const [offset, setOffset] = useState<number | null>(null);
+	const scroll = offset === null ? maxOffset : Math.min(offset, maxOffset);
+
+	useInput(
+		(input, key) => {
+			if (key.upArrow || input === 'k') {
+				setOffset(Math.max(0, scroll - 1));
+			} else if (key.downArrow || input === 'j') {
+				setOffset(Math.min(maxOffset, scroll + 1));
+			} else if (key.pageUp) {
+				setOffset(Math.max(0, scroll - height));
+			} else if (key.pageDown) {
+				setOffset(Math.min(maxOffset, scroll + height));
+			} else if (input === 'g') {
+				setOffset(0);
+			} else if (input === 'G') {
+				setOffset(maxOffset);
+			}
+		},
+		{isActive: focus},
+	);
+
+	const filterLabel = [
+		query.last !== undefined ? `last=${query.last}` : '',
+		query.date ? `date=${query.date}` : '',
+		query.search ? `text=${query.search}` : '',
+	]
+		.filter(Boolean)
+		.join(' ');
+
+	if (allEntries.length === 0) {
+		return (
+			<Frame title=" PROGRESS LOG " padding={1} width={compact ? 40 : width}>
+				<Text color={floydTheme.colors.fgMuted}>No entries in {PROGRESS_FILE} yet</Text>
+			</Frame>
+		);
+	}
+
+	const visible = rows.slice(scroll, scroll + height);
+
+	return (
+		<Frame title=" PROGRESS LOG " padding={1} width={width}>
+			<Box flexDirection="column">
+				<Box flexDirection="row" gap={1}>
+					{COLUMNS.map((column, i) => (
+						<Text key={column} bold color={crushTheme.accent.secondary}>
+							{fit(column, widths[i] ?? MIN_COLUMN_WIDTH)}
+						</Text>
+					))}
+				</Box>
+
+				{visible.length === 0 ? (
+					<Text color={floydTheme.colors.fgSubtle}>No entries match the current filter</Text>
+				) : (
+					visible.map((row, rowIndex) => (
+						<Box key={`${scroll + rowIndex}`} flexDirection="row" gap={1}>
+							{row.map((cell, i) => (
+								<Text
+									key={i}
+									color={
+										i === 0
+											? floydTheme.colors.fgMuted
+											: i === RESULT_COLUMN
+												? statusColor(cell)
+												: i === 1
+													? floydTheme.colors.fgBase
+													: floydTheme.colors.fgSubtle
+									}
+								>
+									{fit(cell, widths[i] ?? MIN_COLUMN_WIDTH)}
+								</Text>
+							))}
+						</Box>
+					))
+				)}
+
+				<Box marginTop={1} justifyContent="space-between">
+					<Text color={floydTheme.colors.fgMuted} dimColor>
+						{rows.length === 0 ? 0 : scroll + 1}-{Math.min(scroll + height, rows.length)} of {rows.length}
+						{rows.length !== allEntries.length ? ` (filtered from ${allEntries.length})` : ''}
+					</Text>
+					<Text color={floydTheme.colors.fgMuted} dimColor>
+						{filterLabel || (focus ? '↑↓ PgUp/PgDn scroll' : '')}
+					</Text>
+				</Box>
+			</Box>
+		</Frame>
+	);
+}
+
+export default ProgressLogTable;
@@ -53,3 +53,6 @@ export type {
 	SessionHistoryDashboardProps,
 	SessionData,
 } from './AdditionalDashboards.js';
+
+export {ProgressLogTable} from './ProgressLogTable.js';
+export type {ProgressLogTableProps} from './ProgressLogTable.js';
@@ -43,6 +43,8 @@ import Spinner from 'ink-spinner';
 
 // Layout constants
 import { LAYOUT } from '../../theme/layout.js';
+import {MINIMAL_MODE} from '../../utils/minimal-mode.js';
+import {VIM_MODE, VIM_KEYMAP} from '../../utils/vim-mode.js';
 
 // Input validation constants
 const MAX_INPUT_LENGTH = 5000; // Maximum characters allowed in input
@@ -56,7 +58,7 @@ import {
 	commonCommands,
 } from '../components/CommandPalette.js';
 import {AgentBuilderOverlay, type AgentConfig} from '../components/AgentBuilder.js';
-import {SessionPanel, ContextPanel, TranscriptPanel} from '../panels/index.js';
+import {SessionPanel, ContextPanel, TranscriptPanel, LivePanePanel} from '../panels/index.js';
 import type {
 	ToolToggle,
 	WorkerState,
@@ -68,7 +70,20 @@ import type {
 import {HelpOverlay, type Hotkey} from '../overlays/HelpOverlay.js';
 import {PromptLibraryOverlay} from '../overlays/PromptLibraryOverlay.js';
 import {FloydSessionSwitcherOverlay} from '../overlays/FloydSessionSwitcherOverlay.js';
+import {FileBrowserOverlay} from '../overlays/FileBrowserOverlay.js';
 import {VoiceInputButton} from '../components/VoiceInputButton.js';
+import {HistorySearch} from '../components/HistorySearch.js';
+import {VimInput} from '../components/VimInput.js';
+import {CompletionPopup, type CompletionPopupProps} from '../components/CompletionPopup.js';
+import {getSlashSuggestions} from '../../commands/slash-completion.js';
+import {getMentionSuggestions} from '../../utils/file-mentions.js';
+import {formatPinned} from '../../utils/pinned-context.js';
+import {getToolResultView, clampToolScroll} from '../../utils/tool-results.js';
+import type {LivePaneContent} from '../../utils/live-pane.js';
+import type {CommandRegistry} from '../../commands/command-registry.js';
+import {loadInputHistory, appendInputHistory} from 'floyd-agent-core/utils';
+import {loadDraft, saveDraft, DRAFT_SAVE_INTERVAL} from '../../utils/draft.js';
+import {isSecretCommand} from 'floyd-agent-core';
 import {useSTT, type UseSTTReturn} from '../../stt/useSTT.js';
 
 // Agent Visualization
@@ -96,7 +111,15 @@ export interface ChatMessage {
 	content: string | ReactNode;
 	timestamp: Date;
 	streaming?: boolean;
+	/** Position among the user's prompts, for /edit and /delete */
+	number?: number;
+	/** Reasoning the model wrote before the reply */
+	thinking?: string;
 	toolCalls?: Array<{
+		/** Tool call id (keys expand/focus state) */
+		id?: string;
+		/** Position in the conversation, for /expand */
+		number?: number;
 		name: string;
 		status: 'pending' | 'running' | 'success' | 'error';
 		result?: string;
@@ -165,18 +188,60 @@ export interface MainLayoutProps {
 	/** Current whimsical thinking phrase */
 	whimsicalPhrase?: string | null;
 
+	/** Timing of the last LLM request (e.g. "first token 1.2s · 4.8s · 42 tok/s") */
+	requestTiming?: string | null;
+
+	/** Timing of the request in flight (e.g. "first token 1.2s · 3.0s · ~40 tok/s"), shown next to the spinner */
+	liveTiming?: string | null;
+
 	/** Current streaming content (for display during generation) */
 	streamingContent?: string;
 
 	/** Callback when user submits a message */
 	onSubmit?: (message: string) => void;
 
+	/** Slash commands offered for completion while typing "/" */
+	slashCommands?: CommandRegistry;
+
+	/** Workspace files offered for completion while typing "@" */
+	mentionFiles?: string[];
+
+	/** Ids of tool calls whose full output is shown */
+	expandedToolIds?: ReadonlySet<string>;
+
+	/** Callback to expand or collapse a tool call's output */
+	onToggleToolExpanded?: (toolCallId: string) => void;
+
+	/** What the live pane (Ctrl+O) shows: the file a tool edits or its output */
+	livePane?: LivePaneContent | null;
+
+	/** Files and directories pinned as context (Ctrl+F, /unpin) */
+	pinnedPaths?: string[];
+
+	/** Callback with the paths marked in the Ctrl+F file browser */
+	onPinnedChange?: (paths: string[]) => void;
+
 	/** Callback when command palette action is triggered */
 	onCommand?: (commandId: string) => void;
 
 	/** Callback when exit is requested */
 	onExit?: () => void;
 
+	/** Callback to cancel the reply being written (Esc while the agent works) */
+	onCancel?: () => void;
+
+	/** Prompt being edited (/edit or Ctrl+E); its text is put into the input */
+	editingPrompt?: {number: number; text: string} | null;
+
+	/** Callback to edit a prompt selected with Ctrl+E */
+	onEditPrompt?: (number: number) => void;
+
+	/** Callback to delete a prompt selected with Ctrl+E, with its reply */
+	onDeletePrompt?: (number: number) => void;
+
+	/** Callback when editing a prompt is abandoned (Esc) */
+	onCancelEdit?: () => void;
+
 	/** Enable compact mode for smaller terminals */
 	compact?: boolean;
 
@@ -329,7 +394,8 @@ const StatusBar = memo(function StatusBar({
 	isThinking,
 	compact,
 	whimsicalPhrase,
-}: StatusBarProps & {whimsicalPhrase?: string | null}) {
+	requestTiming,
+}: StatusBarProps & {whimsicalPhrase?: string | null; requestTiming?: string | null}) {
 	// Connection status
 	const connectionColor =
 		connectionStatus === 'connected'
@@ -437,6 +503,11 @@ const StatusBar = memo(function StatusBar({
 					<Text bold color={modeLabels[mode]}>{modeLabels[mode]}</Text>
 					<Text color={connectionColor}>{connectionLabel}</Text>
 					<Text color={getAgentStatusColor()}>{getAgentStatusLabel()}</Text>
+					{requestTiming && !isThinking && (
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							{requestTiming}
+						</Text>
+					)}
 				</Box>
 			</Box>
 		</Box>
@@ -563,12 +634,16 @@ interface InputAreaProps {
 	onChange: (value: string) => void;
 	onSubmit: (value: string) => void;
 	isThinking?: boolean;
+	liveTiming?: string | null;
+	pinned?: string | null;
 	hint?: string;
 	onVoiceInput?: () => void;
 	isRecording?: boolean;
 	isTranscribing?: boolean;
 	isWideScreen?: boolean;
 	isNarrowScreen?: boolean;
+	completion?: Omit<CompletionPopupProps, 'selectedIndex'> | null;
+	completionIndex?: number;
 }
 
 function InputArea({
@@ -576,12 +651,16 @@ function InputArea({
 	onChange,
 	onSubmit,
 	isThinking,
+	liveTiming,
+	pinned,
 	hint,
 	onVoiceInput,
 	isRecording,
 	isTranscribing = false,
 	isWideScreen = false,
 	isNarrowScreen = false,
+	completion,
+	completionIndex = 0,
 }: InputAreaProps) {
 	return (
 		<Box flexDirection="column" width="100%" minWidth={isNarrowScreen ? 60 : 80} marginTop={0} paddingX={0}>
@@ -595,24 +674,51 @@ function InputArea({
 				height={5}
 			>
 				<Text color={roleColors.inputPrompt}>{'>'} </Text>
-				<TextInput
-					value={value}
-					onChange={onChange}
-					onSubmit={onSubmit}
-					placeholder={isThinking ? 'Please wait...' : 'Type a message...'}
-				/>
+				{VIM_MODE ? (
+					<VimInput
+						value={value}
+						onChange={onChange}
+						onSubmit={onSubmit}
+						placeholder={isThinking ? 'Type to steer - Enter interrupts the reply' : 'Type a message...'}
+					/>
+				) : (
+					<TextInput
+						value={value}
+						onChange={onChange}
+						onSubmit={onSubmit}
+						placeholder={isThinking ? 'Type to steer - Enter interrupts the reply, Esc cancels it' : 'Type a message...'}
+					/>
+				)}
 			</Box>
 
+			{/* Slash command and @file completions */}
+			{completion && <CompletionPopup {...completion} selectedIndex={completionIndex} />}
+
 			{/* Compact hint footer - single line */}
 			<Box marginTop={0} flexDirection="row" justifyContent="space-between" paddingX={1}>
-				<Text color={roleColors.hint} dimColor>
-					{isNarrowScreen ? 'Ctrl+P: Cmds • Ctrl+/: Help • Esc: Exit' : 'Ctrl+P: Commands • Ctrl+/: Help • Esc: Exit'}
-				</Text>
-				{isThinking && (
-					<Text color={roleColors.thinking}>
-						<Spinner type="dots" />
+				{hint ? (
+					<Text color={roleColors.systemLabel}>{hint}</Text>
+				) : (
+					<Text color={roleColors.hint} dimColor>
+						{isNarrowScreen
+							? `Ctrl+P: Cmds • Ctrl+/: Help • ${VIM_MODE ? 'Ctrl+Q' : 'Esc'}: Exit`
+							: `Ctrl+P: Commands • /: Slash commands • Ctrl+/: Help • ${VIM_MODE ? 'Ctrl+Q' : 'Esc'}: Exit`}
 					</Text>
 				)}
+				{pinned && !isThinking && <Text color={roleColors.hint}>{pinned}</Text>}
+				{isThinking && (
+					<Box gap={1}>
+						{pinned && <Text color={roleColors.hint}>{pinned}</Text>}
+						{liveTiming && (
+							<Text color={liveTiming.includes('stalled') ? floydTheme.colors.warning : floydTheme.colors.fgMuted}>
+								{liveTiming}
+							</Text>
+						)}
+						<Text color={roleColors.thinking}>
+							<Spinner type="dots" />
+						</Text>
+					</Box>
+				)}
 			</Box>
 		</Box>
 	);
@@ -653,10 +759,24 @@ export function MainLayout({
 	promptLibraryVaultPath,
 	isThinking = false,
 	whimsicalPhrase,
+	requestTiming,
+	liveTiming,
 	streamingContent = '',
 	onSubmit,
+	slashCommands,
+	mentionFiles,
+	expandedToolIds,
+	onToggleToolExpanded,
+	livePane,
+	pinnedPaths = [],
+	onPinnedChange,
 	onCommand,
 	onExit,
+	onCancel,
+	editingPrompt,
+	onEditPrompt,
+	onDeletePrompt,
+	onCancelEdit,
 	compact = false,
 	showAgentViz = true,
 	customHeader,
@@ -677,6 +797,99 @@ export function MainLayout({
 	quickActions = [],
 }: MainLayoutProps) {
 	const [input, setInput] = useState('');
+
+	// Persistent prompt history (~/.floyd/history) for ↑↓ navigation and Ctrl+R search
+	const [inputHistory, setInputHistory] = useState<string[]>([]);
+	const [showHistorySearch, setShowHistorySearch] = useState(false);
+	const historyIndexRef = useRef(-1);
+	useEffect(() => {
+		void loadInputHistory().then(setInputHistory);
+	}, []);
+
+	// Crash-safe draft: the input is saved to .floyd/.draft every few seconds
+	// and restored on the next launch
+	const [draftNotice, setDraftNotice] = useState<string | null>(null);
+	const inputRef = useRef(input);
+	inputRef.current = input;
+	const savedDraftRef = useRef('');
+	useEffect(() => {
+		void loadDraft().then(draft => {
+			if (draft.trim()) {
+				savedDraftRef.current = draft;
+				setInput(prev => prev || draft);
+				setDraftNotice('Restored draft from last session');
+			}
+		});
+		const timer = setInterval(() => {
+			const text = inputRef.current;
+			if (text !== savedDraftRef.current) {
+				savedDraftRef.current = text;
+				void saveDraft(text).catch(() => {});
+			}
+		}, DRAFT_SAVE_INTERVAL);
+		return () => clearInterval(timer);
+	}, []);
+
+	// Completion popup while a slash command or an @file mention is typed
+	// (Esc hides it until the input changes)
+	const [completionIndex, setCompletionIndex] = useState(0);
+	const [completionDismissed, setCompletionDismissed] = useState(false);
+	const completion = useMemo((): Omit<CompletionPopupProps, 'selectedIndex'> | null => {
+		if (completionDismissed) {
+			return null;
+		}
+		const slash = slashCommands ? getSlashSuggestions(input, slashCommands) : null;
+		if (slash) {
+			const {command} = slash;
+			return {
+				items: slash.items,
+				header: command
+					? `${command.usage ?? `/${command.name}`}${command.description ? ` - ${command.description}` : ''}`
+					: undefined,
+				emptyText: slash.kind === 'command' ? 'No matching commands' : undefined,
+			};
+		}
+		const files = mentionFiles ? getMentionSuggestions(input, mentionFiles) : null;
+		return files ? {items: files, emptyText: 'No matching files'} : null;
+	}, [input, slashCommands, mentionFiles, completionDismissed]);
+	useEffect(() => {
+		setCompletionIndex(0);
+		setCompletionDismissed(false);
+	}, [input]);
+
+	// Tool result blocks: Ctrl+B moves focus through the visible ones (newest
+	// first); each expanded block keeps its own scroll offset
+	const [focusedToolId, setFocusedToolId] = useState<string | null>(null);
+	const [toolScrollOffsets, setToolScrollOffsets] = useState<Record<string, number>>({});
+	const [showLivePane, setShowLivePane] = useState(false);
+	// Thinking blocks are collapsed to one line until Ctrl+Y
+	const [showThinking, setShowThinking] = useState(false);
+	// Ctrl+F file browser for pinning files as context
+	const [showFileBrowser, setShowFileBrowser] = useState(false);
+	const visibleToolCalls = useMemo(
+		() =>
+			propMessages
+				.slice(-20)
+				.flatMap(message => message.toolCalls ?? [])
+				.filter(call => call.id)
+				.reverse(),
+		[propMessages],
+	);
+	// Prompts: Ctrl+E selects the visible ones (newest first) to edit or delete
+	const [focusedMessageId, setFocusedMessageId] = useState<string | null>(null);
+	const visiblePrompts = useMemo(
+		() =>
+			propMessages
+				.slice(-20)
+				.filter(message => message.role === 'user' && message.number !== undefined)
+				.reverse(),
+		[propMessages],
+	);
+	useEffect(() => {
+		if (editingPrompt) {
+			setInput(editingPrompt.text);
+		}
+	}, [editingPrompt]);
 	// showHelp state from centralized store
 	const showHelp = useFloydStore(state => state.showHelp);
 	const setShowHelp = useCallback((value: boolean) => {
@@ -896,10 +1109,8 @@ export function MainLayout({
 				return;
 			}
 
-			// Prevent submission if agent is thinking
-			if (isThinking) {
-				return;
-			}
+			// While the agent is busy, a message steers it (app.tsx interrupts
+			// the current reply and sends this instead)
 
 			// Update last submission time
 			lastSubmitTimeRef.current = now;
@@ -907,11 +1118,23 @@ export function MainLayout({
 			// Record the API call (will be decremented from remaining)
 			useFloydStore.getState().recordCall();
 
+			// Remember the prompt across restarts (but never an API key from /auth login)
+			if (!isSecretCommand(value)) {
+				setInputHistory(prev => [...prev, value.trim()]);
+				void appendInputHistory(value).catch(() => {});
+			}
+			historyIndexRef.current = -1;
+
+			// Sent, so no longer a draft
+			savedDraftRef.current = '';
+			void saveDraft('').catch(() => {});
+			setDraftNotice(null);
+
 			// Clear input and submit
 			setInput('');
 			onSubmit?.(value);
 		},
-		[isThinking, onSubmit],
+		[onSubmit],
 	);
 
 	// Define hotkeys for the help overlay
@@ -949,9 +1172,42 @@ export function MainLayout({
 				setShowHelp(false);
 			},
 		},
+		{
+			keys: 'Ctrl+O',
+			description: 'Show/hide the live file/output pane',
+			category: 'Navigation',
+			action: () => {
+				setShowLivePane(value => !value);
+				setShowHelp(false);
+			},
+		},
+		{
+			keys: 'Ctrl+Y',
+			description: "Expand/collapse the model's thinking blocks",
+			category: 'Navigation',
+		},
+		{
+			keys: 'Ctrl+F',
+			description: 'Browse the workspace and pin files as context (/unpin removes them)',
+			category: 'Navigation',
+			action: () => {
+				setShowFileBrowser(true);
+				setShowHelp(false);
+			},
+		},
+		{
+			keys: 'Ctrl+B',
+			description: 'Focus the next tool result (Enter expands, Esc returns)',
+			category: 'Navigation',
+		},
+		{
+			keys: 'Ctrl+E',
+			description: 'Select the next prompt (Enter edits and replays from it, Del deletes it)',
+			category: 'Navigation',
+		},
 		{
 			keys: 'Esc',
-			description: 'Close overlay / Exit',
+			description: 'Close overlay / Exit (while the agent works: cancel the reply)',
 			category: 'Navigation',
 			action: () => {
 				setShowHelp(false);
@@ -960,7 +1216,26 @@ export function MainLayout({
 		},
 		{
 			keys: 'Enter',
-			description: 'Send message',
+			description: 'Send message (while the agent works: interrupt and steer it)',
+			category: 'Input',
+		},
+		{
+			keys: '↑/↓',
+			description: 'Previous/next prompt from history',
+			category: 'Input',
+		},
+		{
+			keys: 'Ctrl+R',
+			description: 'Search prompt history',
+			category: 'Input',
+			action: () => {
+				setShowHistorySearch(true);
+				setShowHelp(false);
+			},
+		},
+		{
+			keys: 'Ctrl+V',
+			description: 'Start/stop voice input',
 			category: 'Input',
 		},
 		{
@@ -978,6 +1253,7 @@ export function MainLayout({
 			category: 'Navigation',
 			action: () => setShowHelp(false),
 		},
+		...(VIM_MODE ? VIM_KEYMAP.map(binding => ({...binding, category: 'Vim'})) : []),
 		{
 			keys: 'Ctrl+Shift+P',
 			description: 'Open prompt library (Obsidian vault)',
@@ -1050,10 +1326,118 @@ export function MainLayout({
 			return;
 		}
 
+		// History search and the file browser handle their own keys (including Esc)
+		if (showHistorySearch || showFileBrowser) {
+			return;
+		}
 
-		// Esc key exits the CLI when no overlays are open
-		// (Overlays handle their own Esc key in their own useInput handlers)
-		if (key.escape) {
+		// Completion popup: ↑↓ move, Tab completes, Esc dismisses
+		if (completion) {
+			const count = completion.items.length;
+			if (key.escape) {
+				setCompletionDismissed(true);
+				return;
+			}
+			if (key.tab && !key.shift && count > 0) {
+				setInput(completion.items[Math.min(completionIndex, count - 1)]!.value);
+				return;
+			}
+			if ((key.upArrow || key.downArrow) && count > 0) {
+				setCompletionIndex(prev => (prev + (key.upArrow ? count - 1 : 1)) % count);
+				return;
+			}
+		}
+
+		// Ctrl+Y expands/collapses the thinking blocks
+		if (key.ctrl && _inputKey === 'y') {
+			setShowThinking(value => !value);
+			return;
+		}
+
+		// Ctrl+F opens the file browser to pin files as context
+		if (key.ctrl && _inputKey === 'f') {
+			setShowFileBrowser(true);
+			return;
+		}
+
+		// Ctrl+O shows/hides the live file/output pane
+		if (key.ctrl && _inputKey === 'o') {
+			setShowLivePane(value => !value);
+			return;
+		}
+
+		// Ctrl+B focuses the next (older) tool result; after the oldest, back to the input
+		if (key.ctrl && _inputKey === 'b') {
+			const index = visibleToolCalls.findIndex(call => call.id === focusedToolId);
+			setFocusedToolId(visibleToolCalls[index + 1]?.id ?? null);
+			setFocusedMessageId(null);
+			return;
+		}
+
+		// Focused tool result: Enter expands/collapses, ↑↓ scroll, Esc returns to the input
+		if (focusedToolId) {
+			if (key.escape) {
+				setFocusedToolId(null);
+				return;
+			}
+			if (key.return && input.length === 0) {
+				onToggleToolExpanded?.(focusedToolId);
+				return;
+			}
+			if ((key.upArrow || key.downArrow) && expandedToolIds?.has(focusedToolId)) {
+				const call = visibleToolCalls.find(c => c.id === focusedToolId);
+				const output = call?.error ? `Error: ${call.error}` : call?.result ?? '';
+				const totalLines = getToolResultView(output, {expanded: true}).totalLines;
+				setToolScrollOffsets(prev => ({
+					...prev,
+					[focusedToolId]: clampToolScroll((prev[focusedToolId] ?? 0) + (key.upArrow ? -1 : 1), totalLines),
+				}));
+				return;
+			}
+		}
+
+		// Ctrl+E selects the next (older) prompt; after the oldest, back to the input
+		if (key.ctrl && _inputKey === 'e') {
+			const index = visiblePrompts.findIndex(message => message.id === focusedMessageId);
+			setFocusedMessageId(visiblePrompts[index + 1]?.id ?? null);
+			setFocusedToolId(null);
+			return;
+		}
+
+		// Selected prompt: Enter edits it, Del deletes it, Esc returns to the input
+		if (focusedMessageId) {
+			const number = visiblePrompts.find(message => message.id === focusedMessageId)?.number;
+			if (key.escape) {
+				setFocusedMessageId(null);
+				return;
+			}
+			if (number !== undefined && input.length === 0 && (key.return || key.delete || key.backspace)) {
+				setFocusedMessageId(null);
+				if (key.return) {
+					onEditPrompt?.(number);
+				} else {
+					onDeletePrompt?.(number);
+				}
+				return;
+			}
+		}
+
+		// Esc abandons an edited prompt
+		if (key.escape && editingPrompt && onCancelEdit) {
+			onCancelEdit();
+			setInput('');
+			return;
+		}
+
+		// Esc key exits the CLI when no overlays are open, or cancels the
+		// reply while the agent works
+		// (Overlays handle their own Esc key in their own useInput handlers;
+		// in vim mode Esc belongs to the input)
+		if (key.escape && !VIM_MODE && isThinking && onCancel) {
+			onCancel();
+			return;
+		}
+		if (key.escape && !VIM_MODE) {
 			onExit?.();
 			inkExit();
 			return;
@@ -1126,8 +1510,26 @@ export function MainLayout({
 			return;
 		}
 
-		// Ctrl+R to start voice input
+		// Ctrl+R for fuzzy reverse search over prompt history
 		if (key.ctrl && _inputKey === 'r') {
+			setShowHistorySearch(true);
+			return;
+		}
+
+		// ↑↓ walk through prompt history
+		if ((key.upArrow || key.downArrow) && inputHistory.length > 0 && !showSessionSwitcher) {
+			const last = inputHistory.length - 1;
+			const current = historyIndexRef.current;
+			const next = key.upArrow
+				? current === -1 ? last : Math.max(0, current - 1)
+				: current === -1 || current === last ? -1 : current + 1;
+			historyIndexRef.current = next;
+			setInput(next === -1 ? '' : inputHistory[next]!);
+			return;
+		}
+
+		// Ctrl+V to start voice input
+		if (key.ctrl && _inputKey === 'v') {
 			handleVoiceInput();
 			return;
 		}
@@ -1234,6 +1636,20 @@ export function MainLayout({
 		);
 	}
 
+	// Render the file browser (Ctrl+F)
+	if (showFileBrowser) {
+		return (
+			<FileBrowserOverlay
+				pinned={pinnedPaths}
+				height={Math.max(5, terminalHeight - 12)}
+				onClose={paths => {
+					setShowFileBrowser(false);
+					onPinnedChange?.(paths);
+				}}
+			/>
+		);
+	}
+
 	// Render Floyd Session Switcher overlay
 	if (showSessionSwitcher) {
 		return (
@@ -1248,11 +1664,10 @@ export function MainLayout({
 		<CommandPaletteTrigger
 			commands={augmentedCommands}
 			initialOpen={false}
-			openKeys={['/']}  // Add / as a trigger key
 		>
 			<Box flexDirection="column" padding={0} width="100%">
 				{/* ASCII Banner - hide on narrow screens */}
-				{!compact && !isNarrowScreen && <FloydAsciiBanner />}
+				{!compact && !isNarrowScreen && !MINIMAL_MODE && <FloydAsciiBanner />}
 
 				{/* Custom header or Status Bar */}
 				{customHeader || (
@@ -1265,6 +1680,7 @@ export function MainLayout({
 						isThinking={isThinking}
 						compact={compact}
 						whimsicalPhrase={whimsicalPhrase}
+						requestTiming={requestTiming}
 					/>
 				)}
 
@@ -1297,11 +1713,23 @@ export function MainLayout({
 							streamingContent={streamingContent}
 							isThinking={isThinking}
 							height={transcriptHeight}
+							expandedToolIds={expandedToolIds}
+							focusedToolId={focusedToolId}
+							focusedMessageId={focusedMessageId}
+							showThinking={showThinking}
+							toolScrollOffsets={toolScrollOffsets}
 						/>
 					</Box>
 
+					{/* Right: LIVE pane (Ctrl+O) in place of the CONTEXT panel */}
+					{showLivePane && !isVeryNarrowScreen && (
+						<Box width={isNarrowScreen ? '50%' : '40%'} flexShrink={0} marginLeft={1}>
+							<LivePanePanel content={livePane} height={transcriptHeight} />
+						</Box>
+					)}
+
 					{/* Right: CONTEXT Panel - hidden on narrow screens */}
-					{showContextPanel && (
+					{showContextPanel && !showLivePane && (
 						<Box width={contextPanelWidth} flexShrink={0} marginLeft={showSessionPanel && showContextPanel ? 1 : 0}>
 							<ContextPanel
 								currentPlan={currentPlan}
@@ -1316,19 +1744,38 @@ export function MainLayout({
 				</Box>
 
 				{/* Input Area */}
-				{customFooter || (
+				{customFooter || (showHistorySearch ? (
+					<HistorySearch
+						history={inputHistory}
+						onSelect={entry => {
+							setInput(entry);
+							setShowHistorySearch(false);
+						}}
+						onCancel={() => setShowHistorySearch(false)}
+					/>
+				) : (
 					<InputArea
 						value={input}
 						onChange={setInput}
 						onSubmit={handleSubmit}
 						isThinking={isThinking}
+						liveTiming={liveTiming}
+						pinned={formatPinned(pinnedPaths)}
+						hint={
+							draftNotice ??
+							(editingPrompt
+								? `Editing prompt #${editingPrompt.number} - Enter replays the conversation from here, Esc cancels`
+								: undefined)
+						}
 						onVoiceInput={handleVoiceInput}
 						isRecording={isRecording}
 						isTranscribing={isTranscribing}
 						isWideScreen={isWideScreen}
 						isNarrowScreen={isNarrowScreen}
+						completion={completion}
+						completionIndex={completionIndex}
 					/>
-					)}
+					))}
 					</Box>
 				</CommandPaletteTrigger>
 	);
@@ -1386,10 +1833,8 @@ export function CompactMainLayout({
 				return;
 			}
 
-			// Prevent submission if agent is thinking
-			if (isThinking) {
-				return;
-			}
+			// While the agent is busy, a message steers it (app.tsx interrupts
+			// the current reply and sends this instead)
 
 			// Update last submission time
 			lastSubmitTimeRef.current = now;
@@ -1401,7 +1846,7 @@ export function CompactMainLayout({
 			setInput('');
 			onSubmit?.(value);
 		},
-		[isThinking, onSubmit],
+		[onSubmit],
 	);
 
 	useInput((_inputKey, key) => {
@@ -0,0 +1,169 @@
+/**
+ * AskUserOverlay Component
+ *
+ * Shows a clarifying question raised by the agent through the ask_user tool.
+ * The run stays paused until the user answers; the reply is returned to the
+ * model as the tool result.
+ *
+ * Features:
+ * - Multiple-choice answers (↑↓ / number keys + Enter)
+ * - "Other" free-text answer, or free text only when no options are given
+ * - Esc declines to answer so the agent can continue on its own
+ *
+ * Trigger: ask_user tool call during a run
+ */
+
+import {useState} from 'react';
+import {Box, Text, useInput} from 'ink';
+import TextInput from 'ink-text-input';
+import {Frame} from '../crush/Frame.js';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface AskUserOverlayProps {
+	/** Question from the agent */
+	question: string;
+
+	/** Optional multiple-choice answers */
+	options?: string[];
+
+	/** Called with the user's answer */
+	onAnswer: (answer: string) => void;
+
+	/** Custom title */
+	title?: string;
+}
+
+/**
+ * Answer returned when the user dismisses the question
+ */
+export const DECLINED_ANSWER =
+	'The user declined to answer. Proceed with your best judgement and state your assumptions.';
+
+const OTHER_LABEL = 'Other (type an answer)';
+
+// ============================================================================
+// COMPONENT
+// ============================================================================
+
+export function AskUserOverlay({
+	question,
+	options = [],
+	onAnswer,
+	title = ' Floyd needs your input ',
+}: AskUserOverlayProps) {
+	const choices = options.length > 0 ? [...options, OTHER_LABEL] : [];
+	const [selectedIndex, setSelectedIndex] = useState(0);
+	const [typing, setTyping] = useState(choices.length === 0);
+	const [text, setText] = useState('');
+
+	useInput((input, key) => {
+		if (key.escape) {
+			if (typing && choices.length > 0) {
+				setTyping(false);
+			} else {
+				onAnswer(DECLINED_ANSWER);
+			}
+			return;
+		}
+
+		if (typing) return;
+
+		if (key.upArrow) {
+			setSelectedIndex(prev => Math.max(0, prev - 1));
+		} else if (key.downArrow) {
+			setSelectedIndex(prev => Math.min(choices.length - 1, prev + 1));
+		} else if (key.return) {
+			if (selectedIndex === choices.length - 1) {
+				setTyping(true);
+			} else {
+				onAnswer(choices[selectedIndex]!);
+			}
+		} else if (/^[1-9]$/.test(input)) {
+			const index = Number(input) - 1;
+			if (index < options.length) {
+				onAnswer(options[index]!);
+			}
+		}
+	});
+
+	const hint = typing
+		? choices.length > 0
+			? 'Enter to send, Esc to go back to the choices'
+			: 'Enter to send, Esc to skip'
+		: 'Use ↑↓ or 1-9 to choose, Enter to confirm, Esc to skip';
+
+	return (
+		<Box
+			flexDirection="column"
+			width="100%"
+			height="100%"
+			justifyContent="center"
+			alignItems="center"
+		>
+			<Frame
+				title={title}
+				borderStyle="round"
+				borderVariant="focus"
+				padding={1}
+				width={90}
+			>
+				<Box flexDirection="column" gap={1}>
+					<Text bold color={crushTheme.accent.secondary}>
+						{question}
+					</Text>
+
+					{choices.length > 0 && (
+						<Box flexDirection="column">
+							{choices.map((choice, i) => {
+								const isSelected = !typing && i === selectedIndex;
+								return (
+									<Box key={`${i}-${choice}`} flexDirection="row">
+										<Text color={isSelected ? crushTheme.accent.primary : floydTheme.colors.fgMuted}>
+											{isSelected ? '▶ ' : '  '}
+										</Text>
+										<Text bold={isSelected} color={isSelected ? floydTheme.colors.fgBase : floydTheme.colors.fgSubtle}>
+											{i < options.length ? `${i + 1}. ` : '   '}
+											{choice}
+										</Text>
+									</Box>
+								);
+							})}
+						</Box>
+					)}
+
+					{typing && (
+						<Box flexDirection="row">
+							<Text color={crushTheme.accent.primary}>{'> '}</Text>
+							<TextInput
+								value={text}
+								onChange={setText}
+								onSubmit={value => {
+									if (value.trim()) onAnswer(value.trim());
+								}}
+								placeholder="Type your answer..."
+								focus
+							/>
+						</Box>
+					)}
+
+					<Box
+						marginTop={1}
+						paddingTop={1}
+						borderStyle="single"
+						borderColor={floydTheme.colors.border}
+					>
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							{hint}
+						</Text>
+					</Box>
+				</Box>
+			</Frame>
+		</Box>
+	);
+}
+
+export default AskUserOverlay;
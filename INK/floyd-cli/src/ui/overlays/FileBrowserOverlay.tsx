@@ -0,0 +1,189 @@
+/**
+ * FileBrowserOverlay Component
+ *
+ * Workspace tree opened with Ctrl+F for pinning files as context. Marked
+ * files and directories are read and sent with every request until /unpin.
+ *
+ * Features:
+ * - Directories are read when opened; .gitignore'd entries are hidden
+ * - ↑↓ move, → or Enter opens a directory, ← closes it (or goes to its parent)
+ * - Space (or Enter on a file) marks/unmarks
+ * - Esc or Ctrl+F closes and pins the marked entries
+ *
+ * Trigger: Ctrl+F
+ */
+
+import {useEffect, useMemo, useRef, useState} from 'react';
+import {Box, Text, useInput} from 'ink';
+import {Frame} from '../crush/Frame.js';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+import {WorkspaceTree, MAX_PINNED_FILES, type TreeEntry} from '../../utils/pinned-context.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface FileBrowserOverlayProps {
+	/** Paths pinned so far; they start out marked */
+	pinned: string[];
+
+	/** Called with the marked paths when the browser closes */
+	onClose: (pinned: string[]) => void;
+
+	/** Workspace root */
+	cwd?: string;
+
+	/** Rows of the tree shown at once */
+	height?: number;
+
+	/** Custom title */
+	title?: string;
+}
+
+interface Row {
+	entry: TreeEntry;
+	depth: number;
+}
+
+// ============================================================================
+// COMPONENT
+// ============================================================================
+
+export function FileBrowserOverlay({
+	pinned,
+	onClose,
+	cwd = process.cwd(),
+	height = 20,
+	title = ' Pin files as context ',
+}: FileBrowserOverlayProps) {
+	const tree = useMemo(() => new WorkspaceTree(cwd), [cwd]);
+	// Entries of each directory read so far ('' is the workspace root)
+	const [children, setChildren] = useState<Map<string, TreeEntry[]>>(new Map());
+	const [expanded, setExpanded] = useState<Set<string>>(new Set(['']));
+	const [marked, setMarked] = useState<string[]>(pinned);
+	const [selectedIndex, setSelectedIndex] = useState(0);
+	const loadingRef = useRef(new Set<string>());
+
+	const load = (dir: string) => {
+		if (children.has(dir) || loadingRef.current.has(dir)) return;
+		loadingRef.current.add(dir);
+		void tree.list(dir).then(entries => {
+			loadingRef.current.delete(dir);
+			setChildren(prev => new Map(prev).set(dir, entries));
+		});
+	};
+
+	useEffect(() => {
+		load('');
+	}, [tree]);
+
+	// Visible rows: the children of every open directory, depth first
+	const rows = useMemo(() => {
+		const result: Row[] = [];
+		const walk = (dir: string, depth: number) => {
+			for (const entry of children.get(dir) ?? []) {
+				result.push({entry, depth});
+				if (entry.isDirectory && expanded.has(entry.path)) {
+					walk(entry.path, depth + 1);
+				}
+			}
+		};
+		walk('', 0);
+		return result;
+	}, [children, expanded]);
+
+	const selected = rows[Math.min(selectedIndex, rows.length - 1)];
+
+	const toggleMark = (path: string) => {
+		setMarked(prev => (prev.includes(path) ? prev.filter(p => p !== path) : [...prev, path]));
+	};
+
+	const setOpen = (path: string, open: boolean) => {
+		if (open) load(path);
+		setExpanded(prev => {
+			const next = new Set(prev);
+			if (open) next.add(path);
+			else next.delete(path);
+			return next;
+		});
+	};
+
+	useInput((input, key) => {
+		if (key.escape || (key.ctrl && input === 'f')) {
+			onClose(marked);
+			return;
+		}
+		if (!selected) return;
+
+		const {entry} = selected;
+		if (key.upArrow) {
+			setSelectedIndex(prev => Math.max(0, prev - 1));
+		} else if (key.downArrow) {
+			setSelectedIndex(prev => Math.min(rows.length - 1, prev + 1));
+		} else if (input === ' ') {
+			toggleMark(entry.path);
+		} else if (key.rightArrow || (key.return && entry.isDirectory)) {
+			if (entry.isDirectory) setOpen(entry.path, !(key.return && expanded.has(entry.path)));
+		} else if (key.return) {
+			toggleMark(entry.path);
+		} else if (key.leftArrow) {
+			if (entry.isDirectory && expanded.has(entry.path)) {
+				setOpen(entry.path, false);
+			} else if (entry.path.includes('/')) {
+				const parent = entry.path.slice(0, entry.path.lastIndexOf('/'));
+				setOpen(parent, false);
+				setSelectedIndex(Math.max(0, rows.findIndex(row => row.entry.path === parent)));
+			}
+		}
+	});
+
+	// Keep the selection in view
+	const visible = Math.max(1, height);
+	const top = Math.min(Math.max(0, selectedIndex - Math.floor(visible / 2)), Math.max(0, rows.length - visible));
+
+	return (
+		<Box flexDirection="column" width="100%" height="100%" justifyContent="center" alignItems="center">
+			<Frame title={title} borderStyle="round" borderVariant="focus" padding={1} width={90}>
+				<Box flexDirection="column">
+					{rows.length === 0 ? (
+						<Text color={floydTheme.colors.fgMuted}>{children.has('') ? 'No files' : 'Reading the workspace…'}</Text>
+					) : (
+						rows.slice(top, top + visible).map(({entry, depth}, i) => {
+							const isSelected = top + i === selectedIndex;
+							const isMarked = marked.includes(entry.path);
+							const icon = entry.isDirectory ? (expanded.has(entry.path) ? '▾ ' : '▸ ') : '  ';
+							return (
+								<Box key={entry.path} flexDirection="row">
+									<Text color={isSelected ? crushTheme.accent.primary : floydTheme.colors.fgMuted}>
+										{isSelected ? '▶ ' : '  '}
+									</Text>
+									<Text color={isMarked ? floydTheme.colors.success : floydTheme.colors.fgSubtle}>
+										{isMarked ? '[x] ' : '[ ] '}
+									</Text>
+									<Text
+										bold={isSelected}
+										color={entry.isDirectory ? crushTheme.accent.secondary : floydTheme.colors.fgBase}
+										wrap="truncate-end"
+									>
+										{'  '.repeat(depth)}
+										{icon}
+										{entry.name}
+										{entry.isDirectory ? '/' : ''}
+									</Text>
+								</Box>
+							);
+						})
+					)}
+
+					<Box marginTop={1} paddingTop={1} borderStyle="single" borderColor={floydTheme.colors.border}>
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							{`${marked.length} marked (up to ${MAX_PINNED_FILES} files are sent) · ↑↓ move · →/Enter open · ← close · Space mark · Esc pin and close`}
+						</Text>
+					</Box>
+				</Box>
+			</Frame>
+		</Box>
+	);
+}
+
+export default FileBrowserOverlay;
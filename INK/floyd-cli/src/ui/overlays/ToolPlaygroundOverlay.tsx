@@ -0,0 +1,504 @@
+/**
+ * ToolPlaygroundOverlay Component
+ *
+ * Lets the user pick any registered tool, fill a form generated from its
+ * JSON input schema, run it manually and optionally inject the result into
+ * the conversation as context.
+ *
+ * Features:
+ * - Filterable list of every tool exposed by the agent engine
+ * - Schema-driven form (string, number, integer, boolean, object/array as JSON)
+ * - Manual execution with raw result preview
+ * - Inject result into the conversation
+ *
+ * Trigger: Command palette -> "Tool Playground"
+ */
+
+import {useState, useEffect, useMemo, useCallback} from 'react';
+import {Box, Text, useInput} from 'ink';
+import TextInput from 'ink-text-input';
+import {Frame} from '../crush/Frame.js';
+import {floydTheme, crushTheme} from '../../theme/crush-theme.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface PlaygroundTool {
+	name: string;
+	description?: string;
+	inputSchema?: {
+		type?: string;
+		properties?: Record<string, PlaygroundSchemaProperty>;
+		required?: string[];
+	};
+}
+
+export interface PlaygroundSchemaProperty {
+	type?: string | string[];
+	description?: string;
+	default?: unknown;
+	enum?: unknown[];
+}
+
+export interface PlaygroundField {
+	name: string;
+	type: string;
+	description?: string;
+	required: boolean;
+}
+
+export interface ToolPlaygroundOverlayProps {
+	/** Callback when overlay is closed */
+	onClose: () => void;
+
+	/** Loads the registered tools */
+	listTools: () => Promise<PlaygroundTool[]>;
+
+	/** Executes a tool with the given input */
+	callTool: (name: string, input: Record<string, unknown>) => Promise<unknown>;
+
+	/** Injects a tool result into the conversation */
+	onInject: (toolName: string, input: Record<string, unknown>, output: string) => void;
+
+	/** Custom title */
+	title?: string;
+}
+
+type Stage = 'select' | 'form' | 'result';
+
+// ============================================================================
+// SCHEMA HELPERS
+// ============================================================================
+
+/**
+ * Build the ordered form fields for a tool's input schema.
+ * Required fields come first so the form reads top-down.
+ */
+export function getSchemaFields(tool: PlaygroundTool): PlaygroundField[] {
+	const properties = tool.inputSchema?.properties ?? {};
+	const required = new Set(tool.inputSchema?.required ?? []);
+
+	return Object.entries(properties)
+		.map(([name, prop]) => ({
+			name,
+			type: Array.isArray(prop.type) ? prop.type[0] ?? 'string' : prop.type ?? 'string',
+			description: prop.description,
+			required: required.has(name),
+		}))
+		.sort((a, b) => Number(b.required) - Number(a.required));
+}
+
+/**
+ * Convert raw form strings into a tool input object.
+ * Empty optional fields are omitted; type mismatches throw with the field name.
+ */
+export function buildToolInput(
+	fields: PlaygroundField[],
+	values: Record<string, string>,
+): Record<string, unknown> {
+	const input: Record<string, unknown> = {};
+
+	for (const field of fields) {
+		const raw = (values[field.name] ?? '').trim();
+
+		if (raw === '') {
+			if (field.required) {
+				throw new Error(`Field "${field.name}" is required`);
+			}
+			continue;
+		}
+
+		switch (field.type) {
+			case 'number':
+			case 'integer': {
+				const num = Number(raw);
+				if (Number.isNaN(num) || (field.type === 'integer' && !Number.isInteger(num))) {
+					throw new Error(`Field "${field.name}" must be a ${field.type}`);
+				}
+				input[field.name] = num;
+				break;
+			}
+			case 'boolean':
+				if (raw !== 'true' && raw !== 'false') {
+					throw new Error(`Field "${field.name}" must be true or false`);
+				}
+				input[field.name] = raw === 'true';
+				break;
+			case 'object':
+			case 'array':
+				try {
+					input[field.name] = JSON.parse(raw);
+				} catch {
+					throw new Error(`Field "${field.name}" must be valid JSON`);
+				}
+				break;
+			default:
+				input[field.name] = raw;
+		}
+	}
+
+	return input;
+}
+
+/**
+ * Flatten an MCP call result into display text.
+ */
+export function formatToolOutput(result: unknown): string {
+	if (result && typeof result === 'object' && Array.isArray((result as any).content)) {
+		return (result as any).content
+			.map((part: any) => (part?.type === 'text' ? part.text : JSON.stringify(part)))
+			.join('\n');
+	}
+	return typeof result === 'string' ? result : JSON.stringify(result, null, 2);
+}
+
+// ============================================================================
+// COMPONENT
+// ============================================================================
+
+const MAX_VISIBLE_TOOLS = 12;
+const MAX_RESULT_LINES = 20;
+
+/**
+ * ToolPlaygroundOverlay - Run tools by hand
+ */
+export function ToolPlaygroundOverlay({
+	onClose,
+	listTools,
+	callTool,
+	onInject,
+	title = ' TOOL PLAYGROUND ',
+}: ToolPlaygroundOverlayProps) {
+	const [stage, setStage] = useState<Stage>('select');
+	const [tools, setTools] = useState<PlaygroundTool[]>([]);
+	const [loading, setLoading] = useState(true);
+	const [searchQuery, setSearchQuery] = useState('');
+	const [selectedIndex, setSelectedIndex] = useState(0);
+	const [activeTool, setActiveTool] = useState<PlaygroundTool | null>(null);
+	const [values, setValues] = useState<Record<string, string>>({});
+	const [focusedField, setFocusedField] = useState(0);
+	const [running, setRunning] = useState(false);
+	const [output, setOutput] = useState('');
+	const [error, setError] = useState<string | null>(null);
+	const [injected, setInjected] = useState(false);
+
+	// Load tools on mount
+	useEffect(() => {
+		let cancelled = false;
+		listTools()
+			.then(result => {
+				if (!cancelled) setTools(result);
+			})
+			.catch(err => {
+				if (!cancelled) setError(err instanceof Error ? err.message : String(err));
+			})
+			.finally(() => {
+				if (!cancelled) setLoading(false);
+			});
+		return () => {
+			cancelled = true;
+		};
+	}, [listTools]);
+
+	const filteredTools = useMemo(
+		() =>
+			tools.filter(tool =>
+				tool.name.toLowerCase().includes(searchQuery.toLowerCase()),
+			),
+		[tools, searchQuery],
+	);
+
+	const fields = useMemo(
+		() => (activeTool ? getSchemaFields(activeTool) : []),
+		[activeTool],
+	);
+
+	// Reset selection when the filter changes
+	useEffect(() => {
+		setSelectedIndex(0);
+	}, [searchQuery]);
+
+	const openTool = useCallback((tool: PlaygroundTool) => {
+		const initial: Record<string, string> = {};
+		for (const [name, prop] of Object.entries(tool.inputSchema?.properties ?? {})) {
+			if (prop.default !== undefined) {
+				initial[name] = typeof prop.default === 'string' ? prop.default : JSON.stringify(prop.default);
+			}
+		}
+		setActiveTool(tool);
+		setValues(initial);
+		setFocusedField(0);
+		setError(null);
+		setStage('form');
+	}, []);
+
+	const runTool = useCallback(async () => {
+		if (!activeTool || running) return;
+
+		let input: Record<string, unknown>;
+		try {
+			input = buildToolInput(fields, values);
+		} catch (err) {
+			setError(err instanceof Error ? err.message : String(err));
+			return;
+		}
+
+		setRunning(true);
+		setError(null);
+		setInjected(false);
+		try {
+			const result = await callTool(activeTool.name, input);
+			setOutput(formatToolOutput(result));
+			if ((result as any)?.isError) {
+				setError('Tool reported an error');
+			}
+		} catch (err) {
+			setOutput('');
+			setError(err instanceof Error ? err.message : String(err));
+		} finally {
+			setRunning(false);
+			setStage('result');
+		}
+	}, [activeTool, running, fields, values, callTool]);
+
+	const injectResult = useCallback(() => {
+		if (!activeTool || injected) return;
+		try {
+			onInject(activeTool.name, buildToolInput(fields, values), output);
+			setInjected(true);
+		} catch (err) {
+			setError(err instanceof Error ? err.message : String(err));
+		}
+	}, [activeTool, injected, fields, values, output, onInject]);
+
+	// Handle keyboard input
+	useInput((input, key) => {
+		if (key.escape) {
+			if (stage === 'select') {
+				onClose();
+			} else {
+				setStage(stage === 'result' ? 'form' : 'select');
+				setError(null);
+			}
+			return;
+		}
+
+		if (stage === 'select') {
+			if (key.upArrow) {
+				setSelectedIndex(prev => Math.max(0, prev - 1));
+			} else if (key.downArrow) {
+				setSelectedIndex(prev => Math.min(filteredTools.length - 1, prev + 1));
+			} else if (key.return && filteredTools[selectedIndex]) {
+				openTool(filteredTools[selectedIndex]);
+			}
+			return;
+		}
+
+		if (stage === 'form') {
+			if (key.upArrow || (key.shift && key.tab)) {
+				setFocusedField(prev => Math.max(0, prev - 1));
+			} else if (key.downArrow || key.tab) {
+				setFocusedField(prev => Math.min(fields.length - 1, prev + 1));
+			} else if (key.ctrl && input === 'r') {
+				void runTool();
+			}
+			return;
+		}
+
+		if (stage === 'result') {
+			if (input === 'i') {
+				injectResult();
+			} else if (input === 'r') {
+				void runTool();
+			}
+		}
+	});
+
+	const renderSelect = () => {
+		const start = Math.max(0, Math.min(selectedIndex - MAX_VISIBLE_TOOLS + 1, filteredTools.length - MAX_VISIBLE_TOOLS));
+		const visible = filteredTools.slice(start, start + MAX_VISIBLE_TOOLS);
+
+		return (
+			<Box flexDirection="column">
+				<Box flexDirection="column" marginBottom={1}>
+					<Text bold color={crushTheme.accent.primary}>
+						Search tools:
+					</Text>
+					<TextInput
+						value={searchQuery}
+						onChange={setSearchQuery}
+						placeholder="Type to filter tools..."
+						focus
+					/>
+				</Box>
+
+				{loading ? (
+					<Text color={floydTheme.colors.fgSubtle}>Loading tools...</Text>
+				) : filteredTools.length === 0 ? (
+					<Text color={floydTheme.colors.fgSubtle}>
+						{searchQuery ? 'No tools match your search' : 'No tools registered'}
+					</Text>
+				) : (
+					visible.map((tool, i) => {
+						const isSelected = start + i === selectedIndex;
+						return (
+							<Box key={tool.name} flexDirection="row">
+								<Text color={isSelected ? crushTheme.accent.primary : floydTheme.colors.fgMuted}>
+									{isSelected ? '▶ ' : '  '}
+								</Text>
+								<Box width={28}>
+									<Text bold={isSelected} color={isSelected ? floydTheme.colors.fgBase : floydTheme.colors.fgSubtle}>
+										{tool.name}
+									</Text>
+								</Box>
+								<Box flexGrow={1}>
+									<Text color={floydTheme.colors.fgMuted} dimColor wrap="truncate-end">
+										{tool.description ?? ''}
+									</Text>
+								</Box>
+							</Box>
+						);
+					})
+				)}
+			</Box>
+		);
+	};
+
+	const renderForm = () => (
+		<Box flexDirection="column">
+			<Text bold color={crushTheme.accent.secondary}>
+				{activeTool?.name}
+			</Text>
+			{activeTool?.description && (
+				<Text color={floydTheme.colors.fgMuted} dimColor>
+					{activeTool.description}
+				</Text>
+			)}
+
+			<Box flexDirection="column" marginTop={1}>
+				{fields.length === 0 ? (
+					<Text color={floydTheme.colors.fgSubtle}>This tool takes no input.</Text>
+				) : (
+					fields.map((field, index) => (
+						<Box key={field.name} flexDirection="row">
+							<Box width={24}>
+								<Text color={index === focusedField ? crushTheme.accent.primary : floydTheme.colors.fgSubtle}>
+									{field.name}
+									{field.required ? '*' : ''} ({field.type})
+								</Text>
+							</Box>
+							<TextInput
+								value={values[field.name] ?? ''}
+								onChange={value => setValues(prev => ({...prev, [field.name]: value}))}
+								onSubmit={() => {
+									if (index === fields.length - 1) {
+										void runTool();
+									} else {
+										setFocusedField(index + 1);
+									}
+								}}
+								placeholder={field.description ?? ''}
+								focus={index === focusedField}
+							/>
+						</Box>
+					))
+				)}
+			</Box>
+
+			{running && (
+				<Box marginTop={1}>
+					<Text color={floydTheme.colors.info}>Running...</Text>
+				</Box>
+			)}
+		</Box>
+	);
+
+	const renderResult = () => {
+		const lines = output.split('\n');
+		const shown = lines.slice(0, MAX_RESULT_LINES);
+
+		return (
+			<Box flexDirection="column">
+				<Text bold color={crushTheme.accent.secondary}>
+					{activeTool?.name} result
+				</Text>
+				<Box flexDirection="column" marginTop={1}>
+					{shown.map((line, i) => (
+						<Text key={i} color={floydTheme.colors.fgBase}>
+							{line}
+						</Text>
+					))}
+					{lines.length > MAX_RESULT_LINES && (
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							... {lines.length - MAX_RESULT_LINES} more lines
+						</Text>
+					)}
+				</Box>
+				{injected && (
+					<Box marginTop={1}>
+						<Text color={floydTheme.colors.success}>Result injected into conversation</Text>
+					</Box>
+				)}
+			</Box>
+		);
+	};
+
+	const hint =
+		stage === 'select'
+			? 'Use ↑↓ to navigate, Enter to open, Esc to close'
+			: stage === 'form'
+				? 'Tab/↑↓ to move, Enter on last field or Ctrl+R to run, Esc to go back'
+				: 'I to inject into conversation, R to re-run, Esc to edit input';
+
+	return (
+		<Box
+			flexDirection="column"
+			width="100%"
+			height="100%"
+			justifyContent="center"
+			alignItems="center"
+		>
+			<Frame
+				title={title}
+				borderStyle="round"
+				borderVariant="focus"
+				padding={1}
+				width={100}
+			>
+				<Box flexDirection="column" gap={1}>
+					<Box marginBottom={1}>
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							{hint}
+						</Text>
+					</Box>
+
+					{stage === 'select' && renderSelect()}
+					{stage === 'form' && renderForm()}
+					{stage === 'result' && renderResult()}
+
+					{error && (
+						<Text color={floydTheme.colors.error}>[!] {error}</Text>
+					)}
+
+					<Box
+						marginTop={1}
+						paddingTop={1}
+						borderStyle="single"
+						borderColor={floydTheme.colors.border}
+						justifyContent="space-between"
+					>
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							{tools.length} tool{tools.length !== 1 ? 's' : ''} available
+						</Text>
+						<Text color={floydTheme.colors.fgMuted} dimColor>
+							Press Esc to close
+						</Text>
+					</Box>
+				</Box>
+			</Frame>
+		</Box>
+	);
+}
+
+export default ToolPlaygroundOverlay;
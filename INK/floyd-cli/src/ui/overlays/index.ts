@@ -15,3 +15,12 @@ export type {SessionSwitcherOverlayProps} from './SessionSwitcherOverlay.js';
 
 export {FloydSessionSwitcherOverlay} from './FloydSessionSwitcherOverlay.js';
 export type {FloydSessionSwitcherOverlayProps, FloydSessionData} from './FloydSessionSwitcherOverlay.js';
+
+export {ToolPlaygroundOverlay} from './ToolPlaygroundOverlay.js';
+export type {ToolPlaygroundOverlayProps, PlaygroundTool} from './ToolPlaygroundOverlay.js';
+
+export {AskUserOverlay, DECLINED_ANSWER} from './AskUserOverlay.js';
+export type {AskUserOverlayProps} from './AskUserOverlay.js';
+
+export {FileBrowserOverlay} from './FileBrowserOverlay.js';
+export type {FileBrowserOverlayProps} from './FileBrowserOverlay.js';
@@ -0,0 +1,89 @@
+/**
+ * LivePanePanel Component
+ *
+ * Right-hand pane (toggled with Ctrl+O) that follows the running tool:
+ * - File tools: the file being edited with the change highlighted
+ * - Other tools: the tail of their output
+ */
+
+import {Box, Text} from 'ink';
+import React from 'react';
+import {Frame} from '../crush/Frame.js';
+import {floydTheme} from '../../theme/crush-theme.js';
+import type {LivePaneContent, PaneDiffLine} from '../../utils/live-pane.js';
+
+export interface LivePanePanelProps {
+	/** What the pane shows (null before the first tool runs) */
+	content?: LivePaneContent | null;
+
+	/** Lines that fit in the pane */
+	height?: number;
+}
+
+const STATUS_COLORS = {
+	running: floydTheme.colors.warning,
+	done: floydTheme.colors.success,
+	failed: floydTheme.colors.error,
+} as const;
+
+function DiffLineView({line}: {line: PaneDiffLine}) {
+	switch (line.type) {
+		case 'addition':
+			return <Text color={floydTheme.colors.success} wrap="truncate-end">+ {line.text}</Text>;
+		case 'deletion':
+			return <Text color={floydTheme.colors.error} wrap="truncate-end">- {line.text}</Text>;
+		case 'gap':
+			return <Text color={floydTheme.colors.fgSubtle} wrap="truncate-end">{line.text}</Text>;
+		default:
+			return <Text color={floydTheme.colors.fgMuted} wrap="truncate-end">  {line.text}</Text>;
+	}
+}
+
+/**
+ * LivePanePanel - What the agent is doing to the repo right now
+ */
+export function LivePanePanel({content, height = 20}: LivePanePanelProps) {
+	// Frame border, padding and the header line
+	const visible = Math.max(1, height - 5);
+
+	return (
+		<Frame title=" LIVE " borderStyle="round" borderVariant="focus" padding={1} width="100%" height="100%">
+			{!content ? (
+				<Text color={floydTheme.colors.fgMuted}>Waiting for a tool to run… (Ctrl+O hides this pane)</Text>
+			) : (
+				<Box flexDirection="column" width="100%">
+					<Box gap={1}>
+						<Text color={STATUS_COLORS[content.status]}>●</Text>
+						<Text bold color={floydTheme.colors.secondary}>
+							{content.tool}
+						</Text>
+						{content.kind === 'file' && (
+							<Text color={floydTheme.colors.fgBase} wrap="truncate-start">
+								{content.path}
+							</Text>
+						)}
+					</Box>
+					{content.kind === 'file' ? (
+						content.diff.length === 0 ? (
+							<Text color={floydTheme.colors.fgMuted}>No changes to show</Text>
+						) : (
+							// Diffs read from the top, where the first change is
+							content.diff.slice(0, visible).map((line, index) => <DiffLineView key={index} line={line} />)
+						)
+					) : content.lines.length === 0 ? (
+						<Text color={floydTheme.colors.fgMuted}>{content.status === 'running' ? 'Running…' : 'No output'}</Text>
+					) : (
+						// Output reads from the bottom, where the newest lines are
+						content.lines.slice(-visible).map((line, index) => (
+							<Text key={index} color={floydTheme.colors.fgBase} wrap="truncate-end">
+								{line}
+							</Text>
+						))
+					)}
+				</Box>
+			)}
+		</Frame>
+	);
+}
+
+export default LivePanePanel;
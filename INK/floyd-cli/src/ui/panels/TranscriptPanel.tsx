@@ -18,7 +18,9 @@ import React, {type ReactNode} from 'react';
 import Spinner from 'ink-spinner';
 import {Frame} from '../crush/Frame.js';
 import {Viewport} from '../crush/Viewport.js';
-import {ToolCard, ToolCardList} from '../components/ToolCard.js';
+import {ToolCardList} from '../components/ToolCard.js';
+import {ToolResultBlock} from '../components/ToolResultBlock.js';
+import {ThinkingBlock} from '../components/ThinkingBlock.js';
 import {MarkdownRenderer} from '../components/MarkdownRenderer.js';
 import {floydTheme, roleColors} from '../../theme/crush-theme.js';
 import type {ChatMessage, MessageRole} from '../layouts/MainLayout.js';
@@ -39,6 +41,16 @@ export interface TranscriptPanelProps {
 	height?: number;
 	/** Max messages to display */
 	maxMessages?: number;
+	/** Ids of tool calls whose full output is shown */
+	expandedToolIds?: ReadonlySet<string>;
+	/** Tool call with keyboard focus */
+	focusedToolId?: string | null;
+	/** Prompt selected with Ctrl+E */
+	focusedMessageId?: string | null;
+	/** Show thinking blocks in full (Ctrl+Y) */
+	showThinking?: boolean;
+	/** Scroll offset of each expanded tool call */
+	toolScrollOffsets?: Record<string, number>;
 }
 
 /**
@@ -95,6 +107,11 @@ function TranscriptPanelInner({
 	isThinking = false,
 	height = 30,
 	maxMessages = 20,
+	expandedToolIds,
+	focusedToolId = null,
+	focusedMessageId = null,
+	showThinking = false,
+	toolScrollOffsets = {},
 }: TranscriptPanelProps) {
 	// Filter for unique messages by ID to prevent doubling issues
 	const uniqueMessages = Array.from(new Map(messages.map(m => [m.id, m])).values());
@@ -124,9 +141,13 @@ function TranscriptPanelInner({
 						<Box key={msg.id} flexDirection="column" marginBottom={2} width="100%">
 							{/* Message header */}
 							<Box flexDirection="row" gap={1} marginBottom={1}>
-								<Text color={getMessageColor(msg.role)} bold>
+								<Text color={getMessageColor(msg.role)} bold inverse={focusedMessageId === msg.id}>
 									{msg.role === 'user' ? '>' : '*'} {getLabel(msg.role)}
+									{msg.number !== undefined && ` #${msg.number}`}
 								</Text>
+								{focusedMessageId === msg.id && (
+									<Text color={floydTheme.colors.fgMuted}>Enter edits · Del deletes · Esc returns</Text>
+								)}
 								<Text color={floydTheme.colors.fgSubtle} dimColor>
 									{formatTimestamp(msg.timestamp)}
 								</Text>
@@ -139,9 +160,18 @@ function TranscriptPanelInner({
 
 							{/* Message content */}
 							<Box marginLeft={2} flexDirection="column" width="100%">
+								{msg.thinking && (
+									<Box marginBottom={msg.content ? 1 : 0} width="100%">
+										<ThinkingBlock
+											text={msg.thinking}
+											expanded={showThinking}
+											streaming={msg.streaming && !msg.content}
+										/>
+									</Box>
+								)}
 								{typeof msg.content === 'string' ? (
 									<Box flexDirection="column">
-										<MarkdownRenderer>{msg.content}</MarkdownRenderer>
+										<MarkdownRenderer streaming={msg.streaming}>{msg.content}</MarkdownRenderer>
 										{msg.streaming && <Text color={roleColors.thinking}>▋</Text>}
 									</Box>
 								) : (
@@ -151,20 +181,23 @@ function TranscriptPanelInner({
 								{/* Tool calls in message */}
 								{msg.toolCalls && msg.toolCalls.length > 0 && (
 									<Box flexDirection="column" marginTop={1} gap={1} width="100%">
-										{msg.toolCalls.map((tool, idx) => (
-											<ToolCard
-												key={`${msg.id}-tool-${tool.name}-${idx}`}
-												toolName={tool.name}
-												status={tool.status}
-												result={tool.result}
-												error={tool.error}
-												workerName={tool.workerName}
-												duration={tool.duration}
-												timestamp={tool.timestamp}
-												showRequestedPrefix={true}
-												compact
-											/>
-										))}
+										{msg.toolCalls.map((tool, idx) => {
+											const id = tool.id ?? `${msg.id}-tool-${idx}`;
+											return (
+												<ToolResultBlock
+													key={id}
+													number={tool.number ?? idx + 1}
+													toolName={tool.name}
+													status={tool.status}
+													result={tool.result}
+													error={tool.error}
+													duration={tool.duration}
+													expanded={expandedToolIds?.has(id)}
+													focused={focusedToolId === id}
+													scrollOffset={toolScrollOffsets[id]}
+												/>
+											);
+										})}
 									</Box>
 								)}
 							</Box>
@@ -200,6 +233,11 @@ export const TranscriptPanel = React.memo(TranscriptPanelInner, (prevProps, next
 		prevProps.streamingContent === nextProps.streamingContent &&
 		prevProps.isThinking === nextProps.isThinking &&
 		prevProps.maxMessages === nextProps.maxMessages &&
-		prevProps.height === nextProps.height
+		prevProps.height === nextProps.height &&
+		prevProps.expandedToolIds === nextProps.expandedToolIds &&
+		prevProps.focusedToolId === nextProps.focusedToolId &&
+		prevProps.focusedMessageId === nextProps.focusedMessageId &&
+		prevProps.showThinking === nextProps.showThinking &&
+		prevProps.toolScrollOffsets === nextProps.toolScrollOffsets
 	);
 });
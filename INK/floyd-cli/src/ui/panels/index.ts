@@ -22,3 +22,6 @@ export type {
 
 export {TranscriptPanel} from './TranscriptPanel.js';
 export type {TranscriptPanelProps} from './TranscriptPanel.js';
+
+export {LivePanePanel} from './LivePanePanel.js';
+export type {LivePanePanelProps} from './LivePanePanel.js';
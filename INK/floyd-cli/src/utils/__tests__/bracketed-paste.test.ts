@@ -0,0 +1,62 @@
+/**
+ * Bracketed Paste Tests
+ *
+ * Tests for separating pastes from typed input and the paste markers.
+ */
+
+import test from 'ava';
+import {PasteParser, PasteStore, pasteMarker} from '../bracketed-paste.ts';
+
+test('PasteParser: bracketed pastes, also across chunks', t => {
+	const parser = new PasteParser();
+	t.deepEqual(parser.push('ab\x1b[200~line 1\rline 2\x1b[201~c'), [
+		{type: 'input', text: 'ab'},
+		{type: 'paste', text: 'line 1\rline 2'},
+		{type: 'input', text: 'c'},
+	]);
+
+	t.deepEqual(parser.push('\x1b[200~one\n'), []);
+	t.deepEqual(parser.push('two\x1b[201~'), [{type: 'paste', text: 'one\ntwo'}]);
+
+	// A start marker cut in half waits for the rest
+	t.deepEqual(parser.push('x\x1b[20'), [{type: 'input', text: 'x'}]);
+	t.deepEqual(parser.push('0~p\x1b[201~'), [{type: 'paste', text: 'p'}]);
+});
+
+test('PasteParser: keys pass through, multi-line chunks count as pastes', t => {
+	const parser = new PasteParser();
+	t.deepEqual(parser.push('\r'), [{type: 'input', text: '\r'}]);
+	t.deepEqual(parser.push('\x1b'), [{type: 'input', text: '\x1b'}]);
+	t.deepEqual(parser.push('\x1b[A'), [{type: 'input', text: '\x1b[A'}]);
+	t.deepEqual(parser.push('a\rb\r'), [{type: 'paste', text: 'a\rb\r'}]);
+});
+
+test('PasteStore: markers for multi-line pastes, expanded on send', t => {
+	const store = new PasteStore();
+	t.is(store.add('single line\n'), 'single line');
+
+	const first = store.add('a\r\nb\r\nc\r\n');
+	t.is(first, '[pasted 3 lines]');
+	const second = store.add('x\ny');
+	t.is(second, pasteMarker(2, 2));
+	t.is(second, '[pasted 2 lines #2]');
+
+	t.is(store.expand(`fix this:\n${first}\nand ${second}`), 'fix this:\na\nb\nc\nand x\ny');
+	t.true(store.hasPastes(first));
+	t.false(store.hasPastes('[pasted 9 lines #7]'));
+});
+
+test('PasteStore: an empty paste (an image on the clipboard) is reported', t => {
+	const store = new PasteStore();
+	let empty = 0;
+	const unsubscribe = store.onEmptyPaste(() => empty++);
+
+	t.deepEqual(new PasteParser().push('\x1b[200~\x1b[201~'), [{type: 'paste', text: ''}]);
+	t.is(store.add(''), '');
+	t.is(store.add('text'), 'text');
+	t.is(empty, 1);
+
+	unsubscribe();
+	store.add('');
+	t.is(empty, 1);
+});
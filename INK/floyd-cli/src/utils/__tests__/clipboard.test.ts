@@ -0,0 +1,67 @@
+/**
+ * Clipboard Tests
+ *
+ * Tests for copying replies and code blocks with OSC 52 and clipboard tools.
+ */
+
+import test from 'ava';
+import {
+	copyToClipboard,
+	osc52Sequence,
+	clipboardCommands,
+	clipboardImageCommands,
+	readClipboardImage,
+	lastCodeBlock,
+} from '../clipboard.ts';
+
+test('osc52Sequence: base64 payload, wrapped for tmux', t => {
+	t.is(osc52Sequence('hi', {}), '\x1b]52;c;aGk=\x07');
+	t.is(osc52Sequence('hi', {TMUX: '/tmp/tmux-1/default'}), '\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\');
+});
+
+test('clipboardCommands: platform tools, wl-copy first on Wayland', t => {
+	t.deepEqual(clipboardCommands('darwin', {}).map(c => c.command), ['pbcopy']);
+	t.deepEqual(clipboardCommands('linux', {}).map(c => c.command), ['xclip', 'xsel']);
+	t.deepEqual(clipboardCommands('linux', {WAYLAND_DISPLAY: 'wayland-0'}).map(c => c.command), ['wl-copy', 'xclip', 'xsel']);
+});
+
+test('copyToClipboard: writes OSC 52 and uses the first tool that works', async t => {
+	const written: string[] = [];
+	const result = await copyToClipboard('hello', {
+		write: data => written.push(data),
+		commands: [
+			{command: 'floyd-no-such-clipboard-tool', args: []},
+			{command: 'sh', args: ['-c', 'cat > /dev/null']},
+		],
+		env: {},
+	});
+
+	t.deepEqual(result, {command: 'sh', osc52: true});
+	t.deepEqual(written, [osc52Sequence('hello', {})]);
+});
+
+test('clipboardImageCommands: pngpaste on macOS, wl-paste first on Wayland', t => {
+	t.deepEqual(clipboardImageCommands('darwin', {}).map(c => c.command), ['pngpaste']);
+	t.deepEqual(clipboardImageCommands('linux', {}).map(c => c.command), ['xclip']);
+	t.deepEqual(clipboardImageCommands('linux', {WAYLAND_DISPLAY: 'wayland-0'}).map(c => c.command), ['wl-paste', 'xclip']);
+	t.deepEqual(clipboardImageCommands('win32', {}), []);
+});
+
+test('readClipboardImage: output of the first tool that prints something', async t => {
+	const image = await readClipboardImage({
+		commands: [
+			{command: 'floyd-no-such-clipboard-tool', args: []},
+			{command: 'sh', args: ['-c', 'true']},
+			{command: 'sh', args: ['-c', 'printf PNG']},
+		],
+	});
+	t.deepEqual(image, Buffer.from('PNG'));
+	t.is(await readClipboardImage({commands: [{command: 'sh', args: ['-c', 'exit 1']}]}), null);
+});
+
+test('lastCodeBlock: body of the last fenced block', t => {
+	const reply = 'Try this:\n\n```ts\nconst a = 1;\n```\n\nor\n\n~~~\nnpm test\n~~~\n';
+	t.is(lastCodeBlock(reply), 'npm test');
+	t.is(lastCodeBlock('```js\nconsole.log("x")\n\n```'), 'console.log("x")\n');
+	t.is(lastCodeBlock('no code here'), null);
+});
@@ -0,0 +1,71 @@
+/**
+ * Continuation Prompt Tests
+ *
+ * Tests for building the /continue request from the plan and progress log.
+ */
+
+import test from 'ava';
+import {mkdtemp, mkdir, writeFile, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {parseOpenPlanItems, buildContinuationPrompt, readContinuationPrompt} from '../continuation.ts';
+import {parseProgress} from 'floyd-agent-core/utils';
+
+const PLAN = `# Master Plan & Objectives (FLOYD)
+## Definition of Done
+- [x] App compiles/runs end-to-end
+- [ ] Tests added where appropriate
+
+## Strategic Steps
+- [x] Phase 1: Planning
+- [ ] Phase 2: Implementation
+  - [ ] Wire the tool registry
+- [ ]
+`;
+
+const PROGRESS = `# Execution Log (FLOYD)
+| Timestamp | Action Taken | Result/Status | Next Step |
+|-----------|--------------|---------------|-----------|
+| 2026-01-12 04:00:00 | Init | Ready | Plan |
+| 2026-01-12 05:00:00 | Registry | Done | Wire registry |
+`;
+
+test('parseOpenPlanItems: lists unchecked items under their heading', t => {
+	t.deepEqual(parseOpenPlanItems(PLAN), [
+		{section: 'Definition of Done', text: 'Tests added where appropriate'},
+		{section: 'Strategic Steps', text: 'Phase 2: Implementation'},
+		{section: 'Strategic Steps', text: 'Wire the tool registry'},
+	]);
+});
+
+test('buildContinuationPrompt: includes open items, latest progress and the protocol', t => {
+	const prompt = buildContinuationPrompt(parseOpenPlanItems(PLAN), parseProgress(PROGRESS), {
+		note: 'keep it small',
+		progressRows: 1,
+	})!;
+
+	t.true(prompt.includes('Definition of Done:\n- [ ] Tests added where appropriate'));
+	t.true(prompt.includes('Strategic Steps:\n- [ ] Phase 2: Implementation\n- [ ] Wire the tool registry'));
+	t.true(prompt.includes('| Timestamp | Action | Result | Next |'));
+	t.true(prompt.includes('| 2026-01-12 05:00:00 | Registry | Done | Wire registry |'));
+	t.false(prompt.includes('Init'));
+	t.true(prompt.includes('Protocol:'));
+	t.true(prompt.endsWith('Additional instructions: keep it small'));
+});
+
+test('buildContinuationPrompt: returns null when there is nothing to continue', t => {
+	t.is(buildContinuationPrompt([], []), null);
+});
+
+test('readContinuationPrompt: reads the plan and progress log from .floyd', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-continue-'));
+	t.is(await readContinuationPrompt(dir), null);
+
+	await mkdir(join(dir, '.floyd'));
+	await writeFile(join(dir, '.floyd', 'master_plan.md'), PLAN);
+	const prompt = await readContinuationPrompt(dir);
+	t.true(prompt?.includes('- [ ] Phase 2: Implementation'));
+	t.false(prompt?.includes('progress.md (oldest first)'));
+
+	await rm(dir, {recursive: true, force: true});
+});
@@ -0,0 +1,43 @@
+/**
+ * Input Draft Tests
+ *
+ * Tests for saving and restoring the in-progress input.
+ */
+
+import test from 'ava';
+import {mkdtemp, readdir, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {getDraftPath, loadDraft, saveDraft} from '../draft.ts';
+
+test('getDraftPath: lives in the project .floyd directory', t => {
+	t.is(getDraftPath('/work/app'), join('/work/app', '.floyd', '.draft'));
+});
+
+test('saveDraft: round-trips multi-line input and leaves no temp file', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-draft-'));
+	const file = getDraftPath(dir);
+
+	t.is(await loadDraft(file), '');
+
+	await saveDraft('refactor the parser\nand keep the tests', file);
+	t.is(await loadDraft(file), 'refactor the parser\nand keep the tests');
+	t.deepEqual(await readdir(join(dir, '.floyd')), ['.draft']);
+
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('saveDraft: an empty input deletes the draft', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-draft-'));
+	const file = getDraftPath(dir);
+
+	await saveDraft('something', file);
+	await saveDraft('   ', file);
+	t.is(await loadDraft(file), '');
+	t.deepEqual(await readdir(join(dir, '.floyd')), []);
+
+	// Nothing to delete is fine
+	await t.notThrowsAsync(saveDraft('', file));
+
+	await rm(dir, {recursive: true, force: true});
+});
@@ -0,0 +1,53 @@
+/**
+ * File Mentions Tests
+ *
+ * Tests for @path completion and attaching mentioned files.
+ */
+
+import test from 'ava';
+import {mkdtemp, mkdir, writeFile, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {
+	getMentionSuggestions,
+	extractMentions,
+	buildMentionAttachments,
+	listWorkspaceFiles,
+} from '../file-mentions.ts';
+
+const files = ['README.md', 'src/main.ts', 'src/manager.ts', 'src/utils/math.ts', 'test/main.test.ts'];
+
+test('getMentionSuggestions: only completes a trailing @mention', t => {
+	t.is(getMentionSuggestions('hello', files), null);
+	t.is(getMentionSuggestions('email me@example.com', files), null);
+	t.is(getMentionSuggestions('look at @src/main.ts now', files), null);
+});
+
+test('getMentionSuggestions: ranks path matches and keeps the rest of the input', t => {
+	const suggestions = getMentionSuggestions('explain @src/ma', files);
+	t.deepEqual(suggestions?.map(s => s.label), ['src/main.ts', 'src/manager.ts', 'src/utils/math.ts']);
+	t.is(suggestions?.[0]?.value, 'explain @src/main.ts ');
+});
+
+test('getMentionSuggestions: lists files for a bare @', t => {
+	t.is(getMentionSuggestions('@', files, 2)?.length, 2);
+});
+
+test('extractMentions: finds unique paths and drops trailing punctuation', t => {
+	t.deepEqual(extractMentions('compare @a.ts and @b.ts, then @a.ts.'), ['a.ts', 'b.ts']);
+	t.deepEqual(extractMentions('mail me@example.com'), []);
+});
+
+test('buildMentionAttachments: attaches files inside the workspace only', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-mentions-'));
+	await mkdir(join(dir, 'src'));
+	await writeFile(join(dir, 'src', 'main.ts'), 'export const x = 1;\n');
+
+	const result = await buildMentionAttachments('fix @src/main.ts and ping @team or @../secret', dir);
+	t.deepEqual(result.attached, ['src/main.ts']);
+	t.true(result.text.includes('<file path="src/main.ts">\nexport const x = 1;\n\n</file>'));
+
+	t.deepEqual(await buildMentionAttachments('no mentions here', dir), {text: '', attached: []});
+	t.deepEqual(await listWorkspaceFiles(dir), ['src/main.ts']);
+	await rm(dir, {recursive: true, force: true});
+});
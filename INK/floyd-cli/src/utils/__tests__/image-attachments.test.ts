@@ -0,0 +1,72 @@
+/**
+ * Image Attachments Tests
+ *
+ * Tests for loading /attach images and the transcript markers.
+ */
+
+import test from 'ava';
+import {mkdtemp, readFile, writeFile, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {
+	loadImageAttachment,
+	saveImageAttachment,
+	imageMediaType,
+	isImagePath,
+	formatImageMarkers,
+	formatSize,
+	MAX_IMAGE_BYTES,
+} from '../image-attachments.ts';
+
+const PNG = Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00]);
+const JPEG = Buffer.from([0xff, 0xd8, 0xff, 0xe0, 0x00]);
+
+test('loadImageAttachment: detects the type from the file signature', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-images-'));
+	await writeFile(join(dir, 'shot.png'), PNG);
+	await writeFile(join(dir, 'photo.png'), JPEG);
+
+	const png = await loadImageAttachment('shot.png', dir);
+	t.deepEqual(png, {name: 'shot.png', size: PNG.length, mediaType: 'image/png', data: PNG.toString('base64')});
+	t.is((await loadImageAttachment('photo.png', dir)).mediaType, 'image/jpeg');
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('loadImageAttachment: rejects other files and oversized images', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-images-'));
+	await writeFile(join(dir, 'notes.png'), 'not an image');
+	await writeFile(join(dir, 'huge.png'), Buffer.concat([PNG, Buffer.alloc(MAX_IMAGE_BYTES)]));
+
+	await t.throwsAsync(loadImageAttachment('notes.png', dir), {message: /not a PNG or JPEG/});
+	await t.throwsAsync(loadImageAttachment('huge.png', dir), {message: /must be under 5.0 MB/});
+	await t.throwsAsync(loadImageAttachment('missing.png', dir));
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('saveImageAttachment: stores a pasted image under .floyd/attachments', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-images-'));
+	const saved = await saveImageAttachment(JPEG, dir, new Date(2026, 0, 31, 14, 25, 0));
+
+	t.is(saved, join('.floyd', 'attachments', 'clipboard-20260131-142500.jpg'));
+	t.deepEqual(await readFile(join(dir, saved)), JPEG);
+	t.is((await loadImageAttachment(saved, dir)).mediaType, 'image/jpeg');
+	await t.throwsAsync(saveImageAttachment(Buffer.from('text'), dir), {message: /not hold a PNG or JPEG/});
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('imageMediaType: PNG and JPEG signatures', t => {
+	t.is(imageMediaType(PNG), 'image/png');
+	t.is(imageMediaType(JPEG), 'image/jpeg');
+	t.is(imageMediaType(Buffer.from('GIF89a')), null);
+});
+
+test('isImagePath: accepts png and jpeg extensions', t => {
+	t.true(isImagePath('a/b.PNG'));
+	t.true(isImagePath('photo.jpeg'));
+	t.false(isImagePath('anim.gif'));
+});
+
+test('formatImageMarkers: one marker per image', t => {
+	t.is(formatImageMarkers([{name: 'a.png'}, {name: 'b.jpg'}]), '[image attached: a.png]\n[image attached: b.jpg]');
+	t.is(formatSize(2048), '2 KB');
+});
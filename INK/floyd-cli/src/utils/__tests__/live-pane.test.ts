@@ -0,0 +1,64 @@
+/**
+ * Live Pane Tests
+ *
+ * Tests for the live pane's file diffs and output tails.
+ */
+
+import test from 'ava';
+import {mkdtemp, rm, writeFile} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {livePaneForToolEnd, livePaneForToolStart, paneDiff, tailLines} from '../live-pane.ts';
+
+test('paneDiff: changes with a little context, long unchanged runs cut', t => {
+	t.deepEqual(paneDiff('a\nb\nc\nd\ne\nf\ng\n', 'a\nb\nc\nd\nX\nf\ng\n'), [
+		{type: 'gap', text: '… 2 unchanged lines'},
+		{type: 'context', text: 'c'},
+		{type: 'context', text: 'd'},
+		{type: 'deletion', text: 'e'},
+		{type: 'addition', text: 'X'},
+		{type: 'context', text: 'f'},
+		{type: 'context', text: 'g'},
+	]);
+});
+
+test('tailLines: last lines, nothing for empty output', t => {
+	t.deepEqual(tailLines('1\n2\n3\n4\n', 2), ['3', '4']);
+	t.deepEqual(tailLines('  \n'), []);
+});
+
+test('livePaneForToolStart/End: file tools show their change', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-live-pane-'));
+	t.teardown(() => rm(dir, {recursive: true, force: true}));
+	await writeFile(join(dir, 'a.ts'), 'const a = 1;\n');
+
+	const call = {name: 'edit_file', input: {file_path: 'a.ts', old_string: '1', new_string: '2'}};
+	const started = await livePaneForToolStart(call, dir);
+	t.like(started, {kind: 'file', path: 'a.ts', status: 'running', before: 'const a = 1;\n'});
+	t.deepEqual(started.kind === 'file' ? started.diff : [], [
+		{type: 'deletion', text: 'const a = 1;'},
+		{type: 'addition', text: 'const a = 2;'},
+	]);
+
+	// What ends up on disk wins over the proposed change
+	await writeFile(join(dir, 'a.ts'), 'const a = 3;\n');
+	const ended = await livePaneForToolEnd({...call, output: 'ok'}, started, dir);
+	t.like(ended, {kind: 'file', status: 'done'});
+	t.deepEqual(ended.kind === 'file' ? ended.diff.map(line => line.text) : [], ['const a = 1;', 'const a = 3;']);
+});
+
+test('livePaneForToolStart/End: other tools show their output tail', async t => {
+	const started = await livePaneForToolStart({name: 'run_command', input: {command: 'ls'}});
+	t.deepEqual(started, {kind: 'output', tool: 'run_command', status: 'running', lines: []});
+
+	t.deepEqual(await livePaneForToolEnd({name: 'run_command', output: 'a\nb\n'}, started), {
+		kind: 'output',
+		tool: 'run_command',
+		status: 'done',
+		lines: ['a', 'b'],
+	});
+	t.like(await livePaneForToolEnd({name: 'run_command', error: 'boom'}, started), {
+		status: 'failed',
+		lines: ['Error: boom'],
+	});
+});
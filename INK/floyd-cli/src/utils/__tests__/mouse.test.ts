@@ -0,0 +1,67 @@
+/**
+ * Mouse Tests
+ *
+ * Tests for SGR mouse parsing, drag selections and clickable links.
+ */
+
+import test from 'ava';
+import {parseMouseEvents, MouseSelection, selectedText, findLinkAt, openCommand} from '../mouse.ts';
+
+test('parseMouseEvents: strips SGR sequences and classifies them', t => {
+	const {events, rest} = parseMouseEvents('a\x1b[<0;5;3M\x1b[<32;8;3M\x1b[<0;8;3m\x1b[<65;1;1Mb');
+	t.is(rest, 'ab');
+	t.deepEqual(
+		events.map(e => [e.type, e.button, e.x, e.y]),
+		[
+			['press', 0, 5, 3],
+			['drag', 0, 8, 3],
+			['release', 0, 8, 3],
+			['wheel', 1, 1, 1],
+		],
+	);
+});
+
+test('MouseSelection: drag highlights then selects, press in place clicks', t => {
+	const selection = new MouseSelection();
+	t.is(selection.handle({type: 'press', button: 0, x: 9, y: 4}), null);
+	t.deepEqual(selection.handle({type: 'drag', button: 0, x: 2, y: 3}), {
+		type: 'highlight',
+		range: {start: {x: 2, y: 3}, end: {x: 9, y: 4}},
+	});
+	t.deepEqual(selection.handle({type: 'release', button: 0, x: 2, y: 3}), {
+		type: 'select',
+		range: {start: {x: 2, y: 3}, end: {x: 9, y: 4}},
+	});
+
+	// The next press clears the highlight, releasing in place is a click
+	t.deepEqual(selection.handle({type: 'press', button: 0, x: 1, y: 1}), {type: 'clear'});
+	t.deepEqual(selection.handle({type: 'release', button: 0, x: 1, y: 1}), {type: 'click', point: {x: 1, y: 1}});
+	t.is(selection.handle({type: 'press', button: 2, x: 1, y: 1}), null);
+});
+
+test('selectedText: spans lines and trims trailing padding', t => {
+	const lines = ['hello world   ', 'second line', 'third'];
+	t.is(selectedText(lines, {start: {x: 7, y: 1}, end: {x: 6, y: 2}}), 'world\nsecond');
+	t.is(selectedText(lines, {start: {x: 1, y: 3}, end: {x: 3, y: 3}}), 'thi');
+});
+
+test('findLinkAt: URLs and file paths under the cursor', t => {
+	const line = 'See https://example.com/docs. and src/app.tsx:42 e.g. here';
+	t.deepEqual(findLinkAt(line, 10), {kind: 'url', target: 'https://example.com/docs'});
+	t.deepEqual(findLinkAt(line, 38), {kind: 'file', target: 'src/app.tsx', line: 42});
+	t.is(findLinkAt(line, 50), null);
+	t.is(findLinkAt(line, 1), null);
+	t.deepEqual(findLinkAt('edit package.json now', 8), {kind: 'file', target: 'package.json', line: undefined});
+});
+
+test('openCommand: browser for URLs, editor for files', t => {
+	t.deepEqual(openCommand({kind: 'url', target: 'https://x.dev'}, 'darwin', {}), {command: 'open', args: ['https://x.dev']});
+	t.deepEqual(openCommand({kind: 'url', target: 'https://x.dev'}, 'linux', {}), {command: 'xdg-open', args: ['https://x.dev']});
+
+	const file = {kind: 'file' as const, target: 'src/a.ts', line: 7};
+	t.deepEqual(openCommand(file, 'linux', {EDITOR: 'code -r'}), {command: 'code', args: ['-r', '--goto', 'src/a.ts:7']});
+	t.deepEqual(openCommand(file, 'linux', {VISUAL: 'subl'}), {command: 'subl', args: ['+7', 'src/a.ts']});
+	t.is(openCommand(file, 'linux', {EDITOR: 'vim'}), null);
+	t.deepEqual(openCommand(file, 'linux', {EDITOR: 'vim', TMUX: '1'}), {command: 'tmux', args: ['new-window', 'vim', '+7', 'src/a.ts']});
+	t.deepEqual(openCommand(file, 'linux', {}), {command: 'xdg-open', args: ['src/a.ts']});
+});
@@ -0,0 +1,40 @@
+/**
+ * Offline Mode Tests
+ *
+ * Tests for offline detection and the direct /run and /read tools.
+ */
+
+import test from 'ava';
+import {mkdtemp, rm, writeFile} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {getOfflineReason, readFileLines, runShellLine} from '../offline.ts';
+
+test('getOfflineReason: offline without an API key', t => {
+	t.is(getOfflineReason({}), 'no-api-key');
+	t.is(getOfflineReason({GLM_API_KEY: 'key'}), null);
+	t.is(getOfflineReason({FLOYD_GLM_API_KEY: 'key'}), null);
+});
+
+test('getOfflineReason: FLOYD_OFFLINE wins over a key', t => {
+	t.is(getOfflineReason({FLOYD_OFFLINE: 'true', GLM_API_KEY: 'key'}), 'requested');
+	t.is(getOfflineReason({FLOYD_OFFLINE: '0', GLM_API_KEY: 'key'}), null);
+});
+
+test('readFileLines: numbers lines and points at the rest', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-offline-'));
+	await writeFile(join(dir, 'notes.txt'), 'one\ntwo\nthree\nfour');
+
+	const text = await readFileLines('notes.txt', 2, 2, dir);
+	t.deepEqual(text.split('\n'), ['notes.txt (4 lines)', '2  two', '3  three', '... 1 more (use /read notes.txt 4)']);
+
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('runShellLine: reports output and exit status', async t => {
+	const ok = await runShellLine('echo hello');
+	t.true(ok.startsWith('$ echo hello\nhello\n[OK] in'));
+
+	const failed = await runShellLine('exit 3');
+	t.regex(failed, /\[!\] exit 3 in/);
+});
@@ -0,0 +1,93 @@
+/**
+ * Pinned Context Tests
+ *
+ * Tests for the .gitignore-aware workspace tree and pinned files.
+ */
+
+import test from 'ava';
+import {mkdtemp, mkdir, writeFile, rm} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {
+	parseGitignore,
+	isIgnored,
+	WorkspaceTree,
+	unpinPaths,
+	buildPinnedContext,
+	formatPinned,
+} from '../pinned-context.ts';
+
+test('isIgnored: matches names anywhere and anchored paths from the .gitignore', t => {
+	const rules = parseGitignore('# build output\n*.log\n/dist\nbuild/\ndocs/**/*.tmp\n!keep.log\n');
+	t.true(isIgnored('error.log', false, rules));
+	t.true(isIgnored('src/debug.log', false, rules));
+	t.false(isIgnored('keep.log', false, rules));
+	t.true(isIgnored('dist', true, rules));
+	t.false(isIgnored('src/dist', true, rules));
+	t.true(isIgnored('src/build', true, rules));
+	t.false(isIgnored('build', false, rules));
+	t.true(isIgnored('docs/a/b/x.tmp', false, rules));
+	t.false(isIgnored('x.tmp', false, rules));
+});
+
+test('isIgnored: rules of a nested .gitignore only apply below it', t => {
+	const rules = parseGitignore('*.gen.ts', 'packages/api');
+	t.true(isIgnored('packages/api/src/types.gen.ts', false, rules));
+	t.false(isIgnored('src/types.gen.ts', false, rules));
+});
+
+test('WorkspaceTree: lists one directory at a time, honoring .gitignore', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-pinned-'));
+	await mkdir(join(dir, 'src', 'gen'), {recursive: true});
+	await mkdir(join(dir, 'node_modules'));
+	await writeFile(join(dir, '.gitignore'), 'gen/\n*.log\n');
+	await writeFile(join(dir, 'src', '.gitignore'), 'secret.ts\n');
+	await writeFile(join(dir, 'README.md'), '# hi\n');
+	await writeFile(join(dir, 'debug.log'), 'noise');
+	await writeFile(join(dir, 'src', 'main.ts'), 'export const x = 1;\n');
+	await writeFile(join(dir, 'src', 'secret.ts'), 'token');
+	await writeFile(join(dir, 'src', 'gen', 'out.ts'), '');
+
+	const tree = new WorkspaceTree(dir);
+	t.deepEqual(
+		(await tree.list()).map(entry => entry.path),
+		['src', '.gitignore', 'README.md'],
+	);
+	t.deepEqual(
+		(await tree.list('src')).map(entry => entry.path),
+		['src/.gitignore', 'src/main.ts'],
+	);
+	t.deepEqual(await tree.files('src'), ['src/.gitignore', 'src/main.ts']);
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('buildPinnedContext: reads pinned files and directories', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-pinned-'));
+	await mkdir(join(dir, 'src'));
+	await writeFile(join(dir, 'src', 'a.ts'), 'a\n');
+	await writeFile(join(dir, 'src', 'b.ts'), 'b\n');
+
+	const tree = new WorkspaceTree(dir);
+	const result = await buildPinnedContext(['src', 'src/a.ts', 'missing.ts'], tree);
+	t.deepEqual(result.files, ['src/a.ts', 'src/b.ts']);
+	t.false(result.truncated);
+	t.true(result.text.startsWith('Pinned files'));
+	t.true(result.text.includes('<file path="src/b.ts">\nb\n\n</file>'));
+
+	t.deepEqual(await buildPinnedContext([], tree), {text: '', files: [], truncated: false});
+	await rm(dir, {recursive: true, force: true});
+});
+
+test('unpinPaths: removes a pin and the pins below it, or all of them', t => {
+	const pinned = ['src', 'src/a.ts', 'src-old/b.ts', 'README.md'];
+	t.deepEqual(unpinPaths(pinned, 'src/'), ['src-old/b.ts', 'README.md']);
+	t.deepEqual(unpinPaths(pinned, './README.md'), ['src', 'src/a.ts', 'src-old/b.ts']);
+	t.deepEqual(unpinPaths(pinned, 'all'), []);
+	t.deepEqual(unpinPaths(pinned), []);
+});
+
+test('formatPinned: names the first pin and counts the rest', t => {
+	t.is(formatPinned([]), null);
+	t.is(formatPinned(['src/app.tsx']), 'pinned: src/app.tsx');
+	t.is(formatPinned(['src/app.tsx', 'README.md', 'docs']), 'pinned: src/app.tsx +2');
+});
@@ -0,0 +1,36 @@
+/**
+ * Prompt Editing Tests
+ *
+ * Tests for numbering prompts and finding the messages /edit and /delete act on.
+ */
+
+import test from 'ava';
+import {numberPrompts, findPrompt} from '../prompt-edit.ts';
+import type {ChatMessage} from '../../ui/layouts/MainLayout.tsx';
+
+const at = new Date(0);
+const messages: ChatMessage[] = [
+	{id: 'u1', role: 'user', content: 'fix the tset', timestamp: at},
+	{id: 'a1', role: 'assistant', content: 'Fixed', timestamp: at},
+	{id: 's1', role: 'system', content: '2 files changed', timestamp: at},
+	{id: 'u2', role: 'user', content: 'now the docs', timestamp: at},
+	{id: 'a2', role: 'assistant', content: 'Done', timestamp: at},
+];
+
+test('numberPrompts numbers user messages only', t => {
+	t.deepEqual(
+		numberPrompts(messages).map(message => message.number),
+		[1, undefined, undefined, 2, undefined],
+	);
+});
+
+test('findPrompt covers the prompt and the replies up to the next one', t => {
+	t.deepEqual(findPrompt(messages, 1), {start: 0, end: 3, fromEnd: 1});
+	t.deepEqual(findPrompt(messages, 2), {start: 3, end: 5, fromEnd: 0});
+});
+
+test('findPrompt rejects numbers without a prompt', t => {
+	t.is(findPrompt(messages, 0), null);
+	t.is(findPrompt(messages, 3), null);
+	t.is(findPrompt(messages, Number.NaN), null);
+});
@@ -0,0 +1,49 @@
+/**
+ * Request Parameters Tests
+ *
+ * Tests for validating /set and the request defaults in settings.json.
+ */
+
+import test from 'ava';
+import {parseSetArgs, parseRequestSettings, formatRequestParams} from '../request-params.ts';
+
+test('parseSetArgs reads each setting', t => {
+	t.deepEqual(parseSetArgs(['max_tokens', '4096']), {params: {maxTokens: 4096}});
+	t.deepEqual(parseSetArgs(['temperature', '0.3']), {params: {temperature: 0.3}});
+	t.deepEqual(parseSetArgs(['stop', 'END', '\\n\\nUser:']), {params: {stopSequences: ['END', '\n\nUser:']}});
+	t.deepEqual(parseSetArgs(['stop', 'none']), {params: {stopSequences: []}});
+});
+
+test('parseSetArgs rejects values out of range', t => {
+	for (const args of [
+		[],
+		['top_p', '0.9'],
+		['max_tokens'],
+		['max_tokens', '0'],
+		['max_tokens', '12.5'],
+		['temperature', '2.5'],
+		['temperature', 'warm'],
+		['stop', 'a', 'b', 'c', 'd', 'e'],
+	]) {
+		t.true('error' in parseSetArgs(args), args.join(' '));
+	}
+});
+
+test('parseRequestSettings keeps the valid defaults and reports the rest', t => {
+	t.deepEqual(parseRequestSettings({max_tokens: 4096, temperature: 5, stop: ['END'], seed: 1}), {
+		params: {maxTokens: 4096, stopSequences: ['END']},
+		errors: ['temperature takes a number from 0 to 2.', 'Unknown request setting "seed"'],
+	});
+	t.deepEqual(parseRequestSettings(undefined), {params: {}, errors: []});
+});
+
+test('formatRequestParams lists the settings in use', t => {
+	t.is(
+		formatRequestParams({maxTokens: 8192, temperature: 0.2, stopSequences: []}),
+		'max_tokens 8192 · temperature 0.2 · stop none',
+	);
+	t.is(
+		formatRequestParams({maxTokens: 4096, temperature: 0, stopSequences: ['END', '\n']}),
+		'max_tokens 4096 · temperature 0 · stop "END" "\\n"',
+	);
+});
@@ -0,0 +1,61 @@
+/**
+ * Request Timing Tests
+ *
+ * Tests for the status bar summary of LLM request timings.
+ */
+
+import test from 'ava';
+import {
+	formatDuration,
+	getTokensPerSecond,
+	formatRequestTiming,
+	startLiveTiming,
+	estimateTokens,
+	formatLiveTiming,
+	type RequestCompleteEvent,
+} from '../request-timing.ts';
+
+const completed: RequestCompleteEvent = {
+	type: 'request_complete',
+	turn: 1,
+	at: 5000,
+	durationMs: 4200,
+	firstTokenMs: 1200,
+	inputTokens: 900,
+	outputTokens: 120,
+};
+
+test('formatDuration picks a readable unit', t => {
+	t.is(formatDuration(850), '850ms');
+	t.is(formatDuration(1234), '1.2s');
+	t.is(formatDuration(125_000), '2m 05s');
+});
+
+test('getTokensPerSecond measures from the first token', t => {
+	t.is(getTokensPerSecond(completed), 40);
+	t.is(getTokensPerSecond({...completed, outputTokens: undefined}), null);
+	t.is(getTokensPerSecond({...completed, firstTokenMs: undefined}), null);
+});
+
+test('formatRequestTiming summarizes a request', t => {
+	t.is(formatRequestTiming(completed), 'first token 1.2s · 4.2s · 40 tok/s');
+	t.is(
+		formatRequestTiming({type: 'request_complete', turn: 1, at: 0, durationMs: 30_000, error: 'timeout'}),
+		'no output · 30.0s',
+	);
+});
+
+test('formatLiveTiming shows the wait before the first token', t => {
+	t.is(formatLiveTiming(startLiveTiming(1000), 4000), 'waiting 3.0s');
+});
+
+test('formatLiveTiming estimates the rate from streamed text', t => {
+	t.is(estimateTokens(400), 100);
+	const live = {sentAt: 1000, firstTokenAt: 2200, lastTokenAt: 4100, chars: 640};
+	t.is(formatLiveTiming(live, 4200), 'first token 1.2s · 3.2s · ~80 tok/s');
+});
+
+test('formatLiveTiming flags a stream that went quiet', t => {
+	const live = {sentAt: 0, firstTokenAt: 1000, lastTokenAt: 2000, chars: 400};
+	t.is(formatLiveTiming(live, 14_000), 'first token 1.0s · 14.0s · ~8 tok/s · stalled 12.0s');
+});
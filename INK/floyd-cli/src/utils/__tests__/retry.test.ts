@@ -0,0 +1,25 @@
+/**
+ * Retry Tests
+ *
+ * Tests for parsing the /retry overrides.
+ */
+
+import test from 'ava';
+import {parseRetryArgs} from '../retry.ts';
+
+test('parseRetryArgs without arguments keeps the settings', t => {
+	t.deepEqual(parseRetryArgs([]), {overrides: {}});
+});
+
+test('parseRetryArgs reads the temperature and model', t => {
+	t.deepEqual(parseRetryArgs(['--temp', '0.2', '--model', 'glm-4-plus']), {
+		overrides: {temperature: 0.2, model: 'glm-4-plus'},
+	});
+	t.deepEqual(parseRetryArgs(['--temperature', '0']), {overrides: {temperature: 0}});
+});
+
+test('parseRetryArgs rejects bad values and unknown options', t => {
+	for (const args of [['--temp'], ['--temp', 'hot'], ['--temp', '3'], ['--model'], ['--model', '--temp'], ['faster']]) {
+		t.true('error' in parseRetryArgs(args), args.join(' '));
+	}
+});
@@ -0,0 +1,53 @@
+/**
+ * Run Changes Tests
+ *
+ * Tests for working out a run's file changes from working-tree snapshots.
+ */
+
+import test from 'ava';
+import {mkdtemp, readFile, rm, writeFile} from 'node:fs/promises';
+import {tmpdir} from 'node:os';
+import {join} from 'node:path';
+import {execa} from 'execa';
+import {diffSnapshots, snapshotWorkingTree, summarizeRunChanges} from '../run-changes.ts';
+
+test('diffSnapshots: only what changed between the snapshots', t => {
+	const before = new Map([
+		['a.ts', {type: 'modified' as const, added: 3, removed: 1}],
+		['b.ts', {type: 'modified' as const, added: 2, removed: 0}],
+		['c.ts', {type: 'modified' as const, added: 4, removed: 4}],
+	]);
+	const after = new Map([
+		['a.ts', {type: 'modified' as const, added: 3, removed: 1}],
+		['b.ts', {type: 'modified' as const, added: 5, removed: 2}],
+		['new.ts', {type: 'created' as const, added: 10, removed: 0}],
+	]);
+
+	t.deepEqual(diffSnapshots(before, after), [
+		{path: 'b.ts', type: 'modified', added: 3, removed: 2},
+		{path: 'c.ts', type: 'modified', added: 4, removed: 4},
+		{path: 'new.ts', type: 'created', added: 10, removed: 0},
+	]);
+});
+
+test('summarizeRunChanges: diffstat and progress entry for a git repository', async t => {
+	const dir = await mkdtemp(join(tmpdir(), 'floyd-run-changes-'));
+	const run = (...args: string[]) => execa('git', args, {cwd: dir});
+	await run('init', '-q');
+	await writeFile(join(dir, 'app.ts'), 'one\ntwo\n');
+	await run('add', '.');
+	await run('-c', 'user.name=t', '-c', 'user.email=t@example.com', 'commit', '-qm', 'init');
+
+	const before = await snapshotWorkingTree(dir);
+	await writeFile(join(dir, 'app.ts'), 'one\ntwo\nthree\n');
+	await writeFile(join(dir, 'util.ts'), 'export {};\n');
+
+	const summary = await summarizeRunChanges(before, 'Add util module', dir);
+	t.truthy(summary);
+	t.true(summary!.includes('A util.ts'));
+	t.true(summary!.includes('2 files changed, 2 insertions(+)'));
+	t.true((await readFile(join(dir, '.floyd', 'progress.md'), 'utf8')).includes('Add util module'));
+
+	t.is(await summarizeRunChanges(await snapshotWorkingTree(dir), 'Nothing', dir), null);
+	await rm(dir, {recursive: true, force: true});
+});
@@ -0,0 +1,54 @@
+/**
+ * Session Warmer Tests
+ *
+ * Tests for handing out pre-warmed spare sessions.
+ */
+
+import test from 'ava';
+import {SessionWarmer} from '../session-warmer.ts';
+
+test('take returns the pre-warmed spare and warms the next one', async t => {
+	let created = 0;
+	const warmer = new SessionWarmer(async () => ++created);
+
+	warmer.warm();
+	t.is(created, 1);
+
+	t.is(await warmer.take(), 1);
+	t.true(warmer.hasSpare());
+	t.is(created, 2);
+
+	t.is(await warmer.take(), 2);
+});
+
+test('take builds a session on demand when no spare is ready', async t => {
+	let created = 0;
+	const warmer = new SessionWarmer(async () => ++created);
+
+	t.is(await warmer.take(), 1);
+	t.is(created, 2);
+});
+
+test('a failed spare is replaced by a fresh session', async t => {
+	let calls = 0;
+	const warmer = new SessionWarmer(async () => {
+		calls++;
+		if (calls === 1) {
+			throw new Error('boom');
+		}
+		return calls;
+	});
+
+	warmer.warm();
+	t.is(await warmer.take(), 2);
+});
+
+test('dispose stops warming', async t => {
+	let created = 0;
+	const warmer = new SessionWarmer(async () => ++created);
+
+	warmer.dispose();
+	warmer.warm();
+	t.false(warmer.hasSpare());
+	t.is(created, 0);
+});
@@ -0,0 +1,83 @@
+/**
+ * Tool Results Tests
+ *
+ * Tests for numbering tool calls and windowing their output.
+ */
+
+import test from 'ava';
+import {
+	listToolCalls,
+	numberToolCalls,
+	getToolResultView,
+	clampToolScroll,
+	TOOL_PREVIEW_LINES,
+	TOOL_EXPANDED_LINES,
+} from '../tool-results.ts';
+import type {ChatMessage} from '../../ui/layouts/MainLayout.tsx';
+
+const lines = (count: number) => Array.from({length: count}, (_, i) => `line ${i + 1}`).join('\n');
+
+const messages: ChatMessage[] = [
+	{id: 'u1', role: 'user', content: 'hi', timestamp: new Date(0)},
+	{
+		id: 'a1',
+		role: 'assistant',
+		content: 'Reading',
+		timestamp: new Date(0),
+		toolCalls: [
+			{id: 'call-1', name: 'read_file', status: 'success', result: 'a'},
+			{id: 'call-2', name: 'run', status: 'error', error: 'boom'},
+		],
+	},
+	{id: 'a2', role: 'assistant', content: 'More', timestamp: new Date(0), toolCalls: [{id: 'call-3', name: 'grep', status: 'running'}]},
+];
+
+test('numberToolCalls: numbers tool calls across the conversation', t => {
+	const numbered = numberToolCalls(messages);
+	t.is(numbered[0], messages[0]);
+	t.deepEqual(numbered[1]!.toolCalls!.map(call => call.number), [1, 2]);
+	t.deepEqual(numbered[2]!.toolCalls!.map(call => call.number), [3]);
+	t.deepEqual(listToolCalls(messages).map(call => [call.number, call.id]), [
+		[1, 'call-1'],
+		[2, 'call-2'],
+		[3, 'call-3'],
+	]);
+});
+
+test('getToolResultView: collapsed shows a preview and counts the rest', t => {
+	const view = getToolResultView(lines(50), {expanded: false});
+	t.is(view.lines.length, TOOL_PREVIEW_LINES);
+	t.is(view.lines[0], 'line 1');
+	t.is(view.hiddenAbove, 0);
+	t.is(view.hiddenBelow, 50 - TOOL_PREVIEW_LINES);
+	t.is(view.totalLines, 50);
+});
+
+test('getToolResultView: expanded shows a window at the scroll offset', t => {
+	const view = getToolResultView(lines(50), {expanded: true, scrollOffset: 10});
+	t.is(view.lines.length, TOOL_EXPANDED_LINES);
+	t.is(view.lines[0], 'line 11');
+	t.is(view.hiddenAbove, 10);
+	t.is(view.hiddenBelow, 50 - 10 - TOOL_EXPANDED_LINES);
+
+	// Scrolling past the end stops at the last full window
+	const end = getToolResultView(lines(50), {expanded: true, scrollOffset: 1000});
+	t.is(end.lines[end.lines.length - 1], 'line 50');
+	t.is(end.hiddenBelow, 0);
+});
+
+test('getToolResultView: short output fits and trailing newlines are ignored', t => {
+	t.deepEqual(getToolResultView('ok\n\n', {expanded: false}), {
+		lines: ['ok'],
+		hiddenAbove: 0,
+		hiddenBelow: 0,
+		totalLines: 1,
+	});
+});
+
+test('clampToolScroll: keeps the offset inside the output', t => {
+	t.is(clampToolScroll(-3, 100), 0);
+	t.is(clampToolScroll(5, 100), 5);
+	t.is(clampToolScroll(500, 100), 100 - TOOL_EXPANDED_LINES);
+	t.is(clampToolScroll(4, 3), 0);
+});
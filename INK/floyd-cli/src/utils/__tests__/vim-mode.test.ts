@@ -0,0 +1,74 @@
+/**
+ * Vim Mode Tests
+ *
+ * Tests for modal editing of the prompt input.
+ */
+
+import test from 'ava';
+import {initialVimState, isVimMode, vimKeypress, type VimKey} from '../vim-mode.ts';
+
+const ESC = {escape: true};
+
+/**
+ * Type keys (strings are input, objects are special keys) into an input
+ */
+function type(value: string, keys: Array<string | VimKey>) {
+	let state = initialVimState(value);
+	for (const k of keys) {
+		const result = typeof k === 'string' ? vimKeypress(value, state, k, {}) : vimKeypress(value, state, '', k);
+		value = result.value;
+		state = result.state;
+	}
+	return {value, mode: state.mode, cursor: state.cursor, register: state.register};
+}
+
+test('isVimMode: FLOYD_VIM turns it on', t => {
+	t.true(isVimMode({FLOYD_VIM: '1'}));
+	t.true(isVimMode({FLOYD_VIM: 'true'}));
+	t.false(isVimMode({}));
+});
+
+test('vimKeypress: insert mode types at the cursor, Esc goes to normal', t => {
+	t.like(type('', ['h', 'i', {leftArrow: true}, 'X', {backspace: true}]), {value: 'hi', mode: 'insert', cursor: 1});
+	t.like(type('abc', [ESC]), {mode: 'normal', cursor: 2});
+});
+
+test('vimKeypress: motions and counts', t => {
+	t.like(type('one two three', [ESC, '0', 'w']), {cursor: 4});
+	t.like(type('one two three', [ESC, '0', '2', 'w']), {cursor: 8});
+	t.like(type('one two three', [ESC, 'b']), {cursor: 8});
+	t.like(type('one two three', [ESC, '0', 'e']), {cursor: 2});
+	t.like(type('  indented', [ESC, '^']), {cursor: 2});
+	t.like(type('one two', [ESC, '0', '$']), {cursor: 6});
+});
+
+test('vimKeypress: operators with motions, doubled for the whole input', t => {
+	t.like(type('hello world foo', [ESC, '0', 'w', 'd', 'w']), {value: 'hello foo', cursor: 6, register: 'world '});
+	t.like(type('one two three', [ESC, '0', 'd', '2', 'w']), {value: 'three'});
+	t.like(type('hello world foo', [ESC, '0', 'c', 'w', 'X']), {value: 'X world foo', mode: 'insert'});
+	t.like(type('hello world', [ESC, 'd', 'd']), {value: '', register: 'hello world'});
+	t.like(type('hello world', [ESC, 'y', 'y']), {value: 'hello world', register: 'hello world'});
+	t.like(type('hello world', [ESC, 'b', 'D']), {value: 'hello '});
+});
+
+test('vimKeypress: x, r, ~, put and undo', t => {
+	t.like(type('hello', [ESC, '0', '2', 'x']), {value: 'llo', register: 'he'});
+	t.like(type('abc', [ESC, '0', 'r', 'z']), {value: 'zbc', mode: 'normal'});
+	t.like(type('abc', [ESC, '0', '~']), {value: 'Abc', cursor: 1});
+	t.like(type('ab', [ESC, '0', 'x', 'p']), {value: 'ba'});
+	t.like(type('hello', [ESC, '0', 'x', 'u']), {value: 'hello'});
+	t.like(type('hi', [ESC, 'A', '!', ESC, 'u']), {value: 'hi'});
+});
+
+test('vimKeypress: visual selection', t => {
+	t.like(type('hello world', [ESC, '0', 'v', 'e', 'd']), {value: ' world', mode: 'normal', register: 'hello'});
+	t.like(type('hello world', [ESC, '0', 'v', 'l', 'y']), {value: 'hello world', register: 'he'});
+	t.like(type('hello world', [ESC, 'v', 'b', 'c']), {value: 'hello ', mode: 'insert'});
+});
+
+test('vimKeypress: Enter submits, layout keys are not handled', t => {
+	const state = {...initialVimState('hi'), mode: 'normal' as const};
+	t.like(vimKeypress('hi', state, '', {return: true}), {submit: true, handled: true});
+	t.like(vimKeypress('hi', state, 'p', {ctrl: true}), {handled: false});
+	t.like(vimKeypress('hi', state, '', {upArrow: true}), {handled: false});
+});
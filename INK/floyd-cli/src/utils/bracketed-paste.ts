@@ -0,0 +1,196 @@
+/**
+ * Bracketed Paste
+ *
+ * Purpose: Take pastes out of the keystroke stream so their newlines do not send the message; multi-line pastes go into the input as a "[pasted 42 lines]" marker and are expanded when the message is sent; an empty paste (what terminals send for an image) is reported to listeners
+ * Exports: PasteParser, PasteStore, getPasteStore(), enableBracketedPaste(), pasteMarker(), PASTE_ON, PASTE_OFF
+ * Related: utils/stdin-filter.ts, cli.tsx, app.tsx (expands markers on submit)
+ *
+ * Terminals without bracketed paste are covered by a fallback: a single
+ * chunk of input that contains a newline and other text is a paste too
+ * (a typed Enter arrives on its own).
+ */
+
+import {createFilteredStdin} from './stdin-filter.js';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+export const PASTE_ON = '\x1b[?2004h';
+export const PASTE_OFF = '\x1b[?2004l';
+
+const PASTE_START = '\x1b[200~';
+const PASTE_END = '\x1b[201~';
+
+const MARKER = /\[pasted (\d+) lines?(?: #(\d+))?\]/g;
+
+// ============================================================================
+// PARSING
+// ============================================================================
+
+export type PasteChunk = {type: 'input' | 'paste'; text: string};
+
+/**
+ * Splits terminal input into typed input and pasted text; a paste may span
+ * several chunks
+ */
+export class PasteParser {
+	/** Text of a paste whose end marker has not arrived yet */
+	private pasting: string | null = null;
+	/** Start of a start marker cut off at the end of the last chunk */
+	private held = '';
+
+	push(data: string): PasteChunk[] {
+		const chunks: PasteChunk[] = [];
+		let rest = this.held + data;
+		this.held = '';
+
+		while (rest) {
+			if (this.pasting !== null) {
+				const end = rest.indexOf(PASTE_END);
+				if (end === -1) {
+					this.pasting += rest;
+					return chunks;
+				}
+				chunks.push({type: 'paste', text: this.pasting + rest.slice(0, end)});
+				this.pasting = null;
+				rest = rest.slice(end + PASTE_END.length);
+				continue;
+			}
+
+			const start = rest.indexOf(PASTE_START);
+			if (start === -1) {
+				// "\x1b[2" could be the start of a paste; a lone Esc is a keypress
+				const partial = [3, 4, 5].find(length => rest.endsWith(PASTE_START.slice(0, length)));
+				if (partial) {
+					this.held = rest.slice(-partial);
+					rest = rest.slice(0, -partial);
+				}
+				if (rest) chunks.push(typedOrPasted(rest));
+				return chunks;
+			}
+			if (start > 0) {
+				chunks.push(typedOrPasted(rest.slice(0, start)));
+			}
+			this.pasting = '';
+			rest = rest.slice(start + PASTE_START.length);
+		}
+
+		return chunks;
+	}
+}
+
+/**
+ * Input outside paste markers: text with a newline arriving in one chunk was
+ * pasted in a terminal without bracketed paste
+ */
+function typedOrPasted(text: string): PasteChunk {
+	const multiline = /[\r\n]/.test(text) && text.replace(/[\r\n]+$/, '').length > 0 && !text.startsWith('\x1b');
+	return {type: multiline ? 'paste' : 'input', text};
+}
+
+// ============================================================================
+// STORE
+// ============================================================================
+
+/**
+ * Placeholder shown in the input for a multi-line paste
+ */
+export function pasteMarker(lines: number, id: number): string {
+	return `[pasted ${lines} ${lines === 1 ? 'line' : 'lines'}${id > 1 ? ` #${id}` : ''}]`;
+}
+
+/**
+ * Pasted text behind the markers in the input
+ */
+export class PasteStore {
+	private pastes = new Map<number, string>();
+	private nextId = 1;
+	private emptyPasteListeners = new Set<() => void>();
+
+	/**
+	 * Call `listener` when a paste has no text, which is what terminals send
+	 * when the clipboard holds an image; returns an unsubscribe function
+	 */
+	onEmptyPaste(listener: () => void): () => void {
+		this.emptyPasteListeners.add(listener);
+		return () => this.emptyPasteListeners.delete(listener);
+	}
+
+	/**
+	 * Text to put into the input for a paste: single lines as they are,
+	 * anything longer as a marker
+	 */
+	add(text: string): string {
+		const content = text.replace(/\r\n?/g, '\n').replace(/\n+$/, '');
+		if (!content) {
+			for (const listener of this.emptyPasteListeners) {
+				listener();
+			}
+			return '';
+		}
+		if (!content.includes('\n')) {
+			return content;
+		}
+		const id = this.nextId++;
+		this.pastes.set(id, content);
+		return pasteMarker(content.split('\n').length, id);
+	}
+
+	/**
+	 * Text with every known marker replaced by its paste
+	 */
+	expand(text: string): string {
+		return text.replace(MARKER, (marker, _lines: string, id: string | undefined) => this.pastes.get(Number(id ?? 1)) ?? marker);
+	}
+
+	/**
+	 * Whether a text contains markers of stored pastes
+	 */
+	hasPastes(text: string): boolean {
+		return this.expand(text) !== text;
+	}
+}
+
+let pasteStore: PasteStore | null = null;
+
+/**
+ * Pastes of this session (markers stay expandable, e.g. from input history)
+ */
+export function getPasteStore(): PasteStore {
+	pasteStore ??= new PasteStore();
+	return pasteStore;
+}
+
+// ============================================================================
+// WIRING
+// ============================================================================
+
+/**
+ * Turn on bracketed paste and return the stdin Ink should use
+ */
+export function enableBracketedPaste(
+	input: NodeJS.ReadStream = process.stdin,
+	output: NodeJS.WriteStream = process.stdout,
+	store: PasteStore = getPasteStore(),
+): {stdin: NodeJS.ReadStream; dispose: () => void} {
+	const parser = new PasteParser();
+	const filtered = createFilteredStdin(input, data =>
+		parser
+			.push(data)
+			.map(chunk => (chunk.type === 'paste' ? store.add(chunk.text) : chunk.text))
+			.join(''),
+	);
+
+	output.write(PASTE_ON);
+	let disposed = false;
+	const dispose = () => {
+		if (disposed) return;
+		disposed = true;
+		filtered.dispose();
+		output.write(PASTE_OFF);
+	};
+	process.on('exit', dispose);
+
+	return {stdin: filtered.stdin, dispose};
+}
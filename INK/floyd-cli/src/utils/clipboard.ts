@@ -0,0 +1,154 @@
+/**
+ * Clipboard
+ *
+ * Purpose: Copy replies and code blocks to the system clipboard, since selecting text in the alternate screen is painful; read an image from it for /paste-image
+ * Exports: copyToClipboard(), osc52Sequence(), clipboardCommands(), clipboardImageCommands(), readClipboardImage(), lastCodeBlock(), ClipboardResult
+ * Related: app.tsx (/copy, /paste-image), commands/app-commands.ts, utils/image-attachments.ts
+ */
+
+import {execa} from 'execa';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface ClipboardCommand {
+	command: string;
+	args: string[];
+}
+
+export interface ClipboardResult {
+	/** Copy tool that succeeded, if any */
+	command?: string;
+	/** Whether the OSC 52 sequence was written (the terminal may still ignore it) */
+	osc52: boolean;
+}
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Largest payload sent with OSC 52; several terminals drop longer sequences
+ */
+const MAX_OSC52_BYTES = 100_000;
+
+// ============================================================================
+// COPYING
+// ============================================================================
+
+/**
+ * OSC 52 "set clipboard" sequence, wrapped for tmux when running inside it
+ */
+export function osc52Sequence(text: string, env: NodeJS.ProcessEnv = process.env): string {
+	const sequence = `\x1b]52;c;${Buffer.from(text, 'utf8').toString('base64')}\x07`;
+	return env['TMUX'] ? `\x1bPtmux;${sequence.replace(/\x1b/g, '\x1b\x1b')}\x1b\\` : sequence;
+}
+
+/**
+ * Clipboard tools to try, in order, for a platform
+ */
+export function clipboardCommands(
+	platform: NodeJS.Platform = process.platform,
+	env: NodeJS.ProcessEnv = process.env,
+): ClipboardCommand[] {
+	if (platform === 'darwin') {
+		return [{command: 'pbcopy', args: []}];
+	}
+	if (platform === 'win32') {
+		return [{command: 'clip', args: []}];
+	}
+	return [
+		...(env['WAYLAND_DISPLAY'] ? [{command: 'wl-copy', args: []}] : []),
+		{command: 'xclip', args: ['-selection', 'clipboard']},
+		{command: 'xsel', args: ['--clipboard', '--input']},
+	];
+}
+
+/**
+ * Put text on the clipboard: OSC 52 (works over SSH in most terminals) and
+ * the first local clipboard tool that succeeds
+ *
+ * @param write - Where the OSC 52 sequence goes (default: stdout)
+ */
+export async function copyToClipboard(
+	text: string,
+	options: {
+		write?: (data: string) => void;
+		commands?: ClipboardCommand[];
+		env?: NodeJS.ProcessEnv;
+	} = {},
+): Promise<ClipboardResult> {
+	const env = options.env ?? process.env;
+	const write = options.write ?? ((data: string) => process.stdout.write(data));
+
+	const osc52 = Buffer.byteLength(text, 'utf8') <= MAX_OSC52_BYTES;
+	if (osc52) {
+		write(osc52Sequence(text, env));
+	}
+
+	for (const {command, args} of options.commands ?? clipboardCommands(process.platform, env)) {
+		const result = await execa(command, args, {input: text, reject: false, timeout: 5000}).catch(() => null);
+		if (result && !result.failed && result.exitCode === 0) {
+			return {command, osc52};
+		}
+	}
+
+	return {osc52};
+}
+
+// ============================================================================
+// PASTING IMAGES
+// ============================================================================
+
+/**
+ * Tools that print the clipboard's image as PNG, in order, for a platform
+ * (none on Windows)
+ */
+export function clipboardImageCommands(
+	platform: NodeJS.Platform = process.platform,
+	env: NodeJS.ProcessEnv = process.env,
+): ClipboardCommand[] {
+	if (platform === 'darwin') {
+		return [{command: 'pngpaste', args: ['-']}];
+	}
+	if (platform === 'win32') {
+		return [];
+	}
+	return [
+		...(env['WAYLAND_DISPLAY'] ? [{command: 'wl-paste', args: ['--no-newline', '--type', 'image/png']}] : []),
+		{command: 'xclip', args: ['-selection', 'clipboard', '-target', 'image/png', '-out']},
+	];
+}
+
+/**
+ * The clipboard's image, from the first tool that prints one, or null when
+ * the clipboard holds no image (or no tool is installed)
+ */
+export async function readClipboardImage(
+	options: {commands?: ClipboardCommand[]; env?: NodeJS.ProcessEnv} = {},
+): Promise<Buffer | null> {
+	const env = options.env ?? process.env;
+	for (const {command, args} of options.commands ?? clipboardImageCommands(process.platform, env)) {
+		const result = await execa(command, args, {encoding: 'buffer', reject: false, timeout: 5000}).catch(() => null);
+		if (result && !result.failed && result.exitCode === 0 && result.stdout.length > 0) {
+			return Buffer.from(result.stdout);
+		}
+	}
+	return null;
+}
+
+// ============================================================================
+// CONTENT
+// ============================================================================
+
+/**
+ * Body of the last fenced code block in a text, or null when there is none
+ */
+export function lastCodeBlock(text: string): string | null {
+	let last: string | null = null;
+	for (const match of text.matchAll(/^ {0,3}(`{3,}|~{3,})[^\n]*\n([\s\S]*?)^ {0,3}\1[ \t]*$/gm)) {
+		last = match[2]!.replace(/\n$/, '');
+	}
+	return last;
+}
@@ -1,11 +1,15 @@
 import fs from 'fs-extra';
 import path from 'path';
+import type { RequestParams } from 'floyd-agent-core';
 import { buildHardenedSystemPrompt } from '../prompts/hardened-prompt.js';
+import { parseRequestSettings } from './request-params.js';
 
 export interface Config {
 	systemPrompt: string;
 	allowedTools: string[];
 	mcpServers: Record<string, any>;
+	/** Request defaults ("request" in settings.json), changed per session with /set */
+	request: Partial<RequestParams>;
 }
 
 export class ConfigLoader {
@@ -26,6 +30,7 @@ export class ConfigLoader {
 			systemPrompt: basePrompt,
 			allowedTools: [],
 			mcpServers: {},
+			request: {},
 		};
 
 		const claudeMdPath = path.join(cwd, 'CLAUDE.md');
@@ -41,6 +46,13 @@ export class ConfigLoader {
 				if (settings.systemPrompt) config.systemPrompt += `\n${settings.systemPrompt}`;
 				if (settings.allowedTools) config.allowedTools = settings.allowedTools;
 				if (settings.mcpServers) config.mcpServers = settings.mcpServers;
+				if (settings.request) {
+					const request = parseRequestSettings(settings.request);
+					config.request = request.params;
+					for (const error of request.errors) {
+						console.error(`settings.json: ${error}`);
+					}
+				}
 			} catch (e) {
 				console.error('Failed to parse settings.json', e);
 			}
@@ -0,0 +1,134 @@
+/**
+ * Continuation Prompt
+ *
+ * Purpose: Build the /continue request from the master plan's open items and the latest progress log rows
+ * Exports: parseOpenPlanItems(), buildContinuationPrompt(), readContinuationPrompt(), PlanItem types
+ * Related: floyd-agent-core/utils progress.ts, .floyd/master_plan.md, /continue in commands/app-commands.ts
+ */
+
+import {readFile} from 'node:fs/promises';
+import {join} from 'node:path';
+import {readProgress, formatProgressRow, PROGRESS_COLUMNS, type ProgressEntry} from 'floyd-agent-core/utils';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface OpenPlanItem {
+	/**
+	 * Heading the item is listed under (e.g. "Strategic Steps"), empty if none
+	 */
+	section: string;
+
+	/**
+	 * Item text without the checkbox
+	 */
+	text: string;
+}
+
+export interface ContinuationOptions {
+	/**
+	 * Extra instructions typed after /continue
+	 */
+	note?: string;
+
+	/**
+	 * Number of progress rows to include (default 5)
+	 */
+	progressRows?: number;
+}
+
+// ============================================================================
+// PARSING
+// ============================================================================
+
+/**
+ * Unchecked "- [ ]" items in the plan, with the heading they are under
+ */
+export function parseOpenPlanItems(markdown: string): OpenPlanItem[] {
+	const items: OpenPlanItem[] = [];
+	let section = '';
+
+	for (const line of markdown.split('\n')) {
+		const heading = line.match(/^#{2,6}\s+(.*)$/);
+		if (heading) {
+			section = heading[1]!.trim();
+			continue;
+		}
+
+		const item = line.match(/^\s*[-*]\s+\[ \]\s+(.*)$/);
+		if (item && item[1]!.trim()) {
+			items.push({section, text: item[1]!.trim()});
+		}
+	}
+
+	return items;
+}
+
+// ============================================================================
+// PROMPT
+// ============================================================================
+
+/**
+ * Assemble the continuation request
+ * Returns null when there is neither an open plan item nor a progress entry
+ */
+export function buildContinuationPrompt(
+	planItems: OpenPlanItem[],
+	progress: ProgressEntry[],
+	{note, progressRows = 5}: ContinuationOptions = {},
+): string | null {
+	const recent = progress.slice(-progressRows);
+	if (planItems.length === 0 && recent.length === 0) {
+		return null;
+	}
+
+	const lines = ['Continue the work in progress. Follow the plan; do not start unrelated work.'];
+
+	if (planItems.length > 0) {
+		lines.push('', 'Open items in .floyd/master_plan.md:');
+		let section: string | null = null;
+		for (const item of planItems) {
+			if (item.section !== section) {
+				section = item.section;
+				if (section) lines.push(`${section}:`);
+			}
+			lines.push(`- [ ] ${item.text}`);
+		}
+	}
+
+	if (recent.length > 0) {
+		lines.push('', 'Latest entries in .floyd/progress.md (oldest first):');
+		lines.push(`| ${PROGRESS_COLUMNS.join(' | ')} |`);
+		for (const entry of recent) {
+			lines.push(formatProgressRow(entry));
+		}
+	}
+
+	lines.push(
+		'',
+		'Protocol:',
+		'1. Pick the first open item the latest progress does not show as done or blocked, and say which one.',
+		'2. Work on that item only.',
+		'3. When it is done, tick it in .floyd/master_plan.md and append a row to .floyd/progress.md.',
+		"4. Stop and report what was done and what's next.",
+	);
+
+	if (note?.trim()) {
+		lines.push('', `Additional instructions: ${note.trim()}`);
+	}
+
+	return lines.join('\n');
+}
+
+/**
+ * Read .floyd/master_plan.md and .floyd/progress.md and build the request
+ * Missing files count as empty.
+ */
+export async function readContinuationPrompt(
+	cwd: string = process.cwd(),
+	options: ContinuationOptions = {},
+): Promise<string | null> {
+	const plan = await readFile(join(cwd, '.floyd', 'master_plan.md'), 'utf-8').catch(() => '');
+	return buildContinuationPrompt(parseOpenPlanItems(plan), readProgress(cwd), options);
+}
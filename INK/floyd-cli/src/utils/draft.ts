@@ -0,0 +1,58 @@
+/**
+ * Input Draft
+ *
+ * Purpose: Keep the text being typed in .floyd/.draft so a crash or a closed terminal does not lose it
+ * Exports: getDraftPath(), loadDraft(), saveDraft(), DRAFT_SAVE_INTERVAL
+ * Related: MainLayout.tsx (saves on an interval, restores on launch)
+ */
+
+import {mkdir, readFile, rename, rm, writeFile} from 'node:fs/promises';
+import {dirname, join} from 'node:path';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * How often the input is saved (ms)
+ */
+export const DRAFT_SAVE_INTERVAL = 3000;
+
+// ============================================================================
+// PERSISTENCE
+// ============================================================================
+
+/**
+ * Location of the draft for a project
+ */
+export function getDraftPath(cwd: string = process.cwd()): string {
+	return join(cwd, '.floyd', '.draft');
+}
+
+/**
+ * The saved draft, or an empty string if there is none
+ */
+export async function loadDraft(filePath: string = getDraftPath()): Promise<string> {
+	try {
+		return await readFile(filePath, 'utf-8');
+	} catch {
+		return '';
+	}
+}
+
+/**
+ * Save the draft, or delete it when the input is empty
+ * Written to a temporary file and renamed, so a crash mid-write leaves the
+ * previous draft intact.
+ */
+export async function saveDraft(text: string, filePath: string = getDraftPath()): Promise<void> {
+	if (!text.trim()) {
+		await rm(filePath, {force: true});
+		return;
+	}
+
+	await mkdir(dirname(filePath), {recursive: true});
+	const tmpPath = `${filePath}.tmp`;
+	await writeFile(tmpPath, text);
+	await rename(tmpPath, filePath);
+}
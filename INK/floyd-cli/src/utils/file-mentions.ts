@@ -0,0 +1,162 @@
+/**
+ * File Mentions
+ *
+ * Purpose: @path completion in the chat input and attaching mentioned files to the
+ *          request, so "explain @src/main.ts" sends the file without a read_file round trip
+ * Exports: listWorkspaceFiles(), getMentionSuggestions(), extractMentions(), readFileBlock(), buildMentionAttachments()
+ * Related: MainLayout.tsx (completion popup), app.tsx (attachments), floyd-agent-core/utils (fuzzyScore),
+ *          pinned-context.ts (same file blocks)
+ */
+
+import {readFile, stat} from 'node:fs/promises';
+import {relative, resolve, sep} from 'node:path';
+import * as fg from 'fast-glob';
+import {fuzzyScore} from 'floyd-agent-core/utils';
+import type {SlashSuggestion} from '../commands/slash-completion.js';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Files listed for completion; larger workspaces are truncated
+ */
+export const MAX_WORKSPACE_FILES = 5000;
+
+/**
+ * Bytes attached per file; larger files are cut with a note
+ */
+export const MAX_ATTACHMENT_BYTES = 100 * 1024;
+
+/**
+ * Files attached per message
+ */
+export const MAX_ATTACHMENTS = 10;
+
+const IGNORE_PATTERNS = [
+	'**/node_modules/**',
+	'**/dist/**',
+	'**/build/**',
+	'**/.git/**',
+	'**/coverage/**',
+	'**/.next/**',
+	'**/.cache/**',
+	'**/.DS_Store',
+];
+
+/**
+ * An @mention: "@" at the start or after whitespace, up to the next whitespace
+ */
+const MENTION_PATTERN = /(^|\s)@([^\s@]+)/g;
+
+// ============================================================================
+// WORKSPACE FILES
+// ============================================================================
+
+/**
+ * Workspace files relative to `cwd`, with forward slashes, sorted
+ */
+export async function listWorkspaceFiles(cwd: string = process.cwd()): Promise<string[]> {
+	const files = await fg.glob('**/*', {
+		cwd,
+		ignore: IGNORE_PATTERNS,
+		onlyFiles: true,
+		dot: true,
+		suppressErrors: true,
+	});
+	return files.sort().slice(0, MAX_WORKSPACE_FILES);
+}
+
+// ============================================================================
+// COMPLETION
+// ============================================================================
+
+/**
+ * Completions for an @mention being typed at the end of the input, or null
+ * when the input does not end in one
+ */
+export function getMentionSuggestions(input: string, files: string[], limit = 8): SlashSuggestion[] | null {
+	const match = /(?:^|\s)@([^\s@]*)$/.exec(input);
+	if (!match) {
+		return null;
+	}
+
+	const query = match[1]!;
+	const base = input.slice(0, input.length - query.length);
+
+	return files
+		.map(file => ({file, score: fuzzyScore(query, file)}))
+		.filter((entry): entry is {file: string; score: number} => entry.score !== null && entry.file !== query)
+		.sort((a, b) => b.score - a.score || a.file.length - b.file.length)
+		.slice(0, limit)
+		.map(({file}) => ({value: `${base}${file} `, label: file}));
+}
+
+// ============================================================================
+// ATTACHMENTS
+// ============================================================================
+
+/**
+ * Paths mentioned in a message, in order, without duplicates
+ */
+export function extractMentions(input: string): string[] {
+	const paths = [...input.matchAll(MENTION_PATTERN)].map(match => match[2]!.replace(/[.,;:!?)]+$/, ''));
+	return [...new Set(paths)];
+}
+
+/**
+ * A file's contents in a `<file path="…">` block as read_file would return
+ * them (binary files as a note, large ones truncated), or null when `fullPath`
+ * is not a readable file
+ */
+export async function readFileBlock(fullPath: string, path: string): Promise<string | null> {
+	try {
+		const info = await stat(fullPath);
+		if (!info.isFile()) return null;
+
+		const buffer = await readFile(fullPath);
+		if (buffer.subarray(0, 8000).includes(0)) {
+			return `<file path="${path}">\n[Binary file, ${info.size} bytes; contents not attached]\n</file>`;
+		}
+		const truncated = buffer.length > MAX_ATTACHMENT_BYTES;
+		const content = buffer.subarray(0, MAX_ATTACHMENT_BYTES).toString('utf-8');
+		const note = truncated
+			? `\n[Truncated at ${MAX_ATTACHMENT_BYTES} of ${buffer.length} bytes; use read_file for the rest]`
+			: '';
+		return `<file path="${path}">\n${content}${note}\n</file>`;
+	} catch {
+		return null;
+	}
+}
+
+/**
+ * Read the files mentioned in a message and format them as context for the
+ * model. Mentions that are not readable files inside `cwd` (e.g. "@team")
+ * are left alone.
+ */
+export async function buildMentionAttachments(
+	input: string,
+	cwd: string = process.cwd(),
+): Promise<{text: string; attached: string[]}> {
+	const blocks: string[] = [];
+	const attached: string[] = [];
+
+	for (const mention of extractMentions(input)) {
+		if (attached.length >= MAX_ATTACHMENTS) break;
+
+		const fullPath = resolve(cwd, mention);
+		const relativePath = relative(cwd, fullPath);
+		if (relativePath.startsWith('..') || relativePath.startsWith(sep)) continue;
+
+		const block = await readFileBlock(fullPath, mention);
+		if (block) {
+			blocks.push(block);
+			attached.push(mention);
+		}
+	}
+
+	return {
+		text: blocks.length > 0 ? `Attached files (as read_file would return them):\n\n${blocks.join('\n\n')}` : '',
+		attached,
+	};
+}
@@ -0,0 +1,129 @@
+/**
+ * Image Attachments
+ *
+ * Purpose: Load PNG/JPEG files for /attach, save pasted clipboard images, and describe them in the transcript
+ * Exports: loadImageAttachment(), saveImageAttachment(), imageMediaType(), isImagePath(), formatImageMarkers(), formatSize(), MAX_IMAGE_BYTES, ATTACHMENTS_DIR
+ * Related: app.tsx (/attach, /paste-image, sending images with the next message), commands/app-commands.ts, utils/clipboard.ts
+ */
+
+import {mkdir, readFile, writeFile} from 'node:fs/promises';
+import {basename, join, resolve} from 'node:path';
+import type {LLMImage} from 'floyd-agent-core';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Largest image accepted (the Anthropic API limit per image)
+ */
+export const MAX_IMAGE_BYTES = 5 * 1024 * 1024;
+
+/**
+ * Where pasted images are saved, relative to the working directory
+ */
+export const ATTACHMENTS_DIR = join('.floyd', 'attachments');
+
+const PNG_SIGNATURE = [0x89, 0x50, 0x4e, 0x47];
+const JPEG_SIGNATURE = [0xff, 0xd8, 0xff];
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * An image queued for the next message
+ */
+export interface ImageAttachment extends LLMImage {
+	/** File name shown in the transcript */
+	name: string;
+
+	/** Size in bytes before encoding */
+	size: number;
+}
+
+// ============================================================================
+// LOADING
+// ============================================================================
+
+/**
+ * Whether a path has an image extension /attach accepts
+ */
+export function isImagePath(filePath: string): boolean {
+	return /\.(png|jpe?g)$/i.test(filePath);
+}
+
+/**
+ * Media type from an image's signature, or null when it is not a PNG or JPEG
+ */
+export function imageMediaType(buffer: Uint8Array): 'image/png' | 'image/jpeg' | null {
+	const matches = (signature: number[]) => signature.every((byte, i) => buffer[i] === byte);
+	return matches(PNG_SIGNATURE) ? 'image/png' : matches(JPEG_SIGNATURE) ? 'image/jpeg' : null;
+}
+
+/**
+ * Read a PNG or JPEG file and base64-encode it
+ *
+ * The type comes from the file's signature, not its extension, so a
+ * misnamed file is still sent with the right media type.
+ */
+export async function loadImageAttachment(filePath: string, cwd: string = process.cwd()): Promise<ImageAttachment> {
+	const buffer = await readFile(resolve(cwd, filePath));
+
+	const mediaType = imageMediaType(buffer);
+	if (!mediaType) {
+		throw new Error(`${filePath} is not a PNG or JPEG image`);
+	}
+	if (buffer.length > MAX_IMAGE_BYTES) {
+		throw new Error(`${filePath} is ${formatSize(buffer.length)}; images must be under ${formatSize(MAX_IMAGE_BYTES)}`);
+	}
+
+	return {
+		name: basename(filePath),
+		size: buffer.length,
+		mediaType,
+		data: buffer.toString('base64'),
+	};
+}
+
+/**
+ * Save a pasted image under .floyd/attachments/ (e.g. clipboard-20260131-142500.png)
+ * so it can be attached like a file; returns its path relative to `cwd`
+ */
+export async function saveImageAttachment(
+	buffer: Uint8Array,
+	cwd: string = process.cwd(),
+	now: Date = new Date(),
+): Promise<string> {
+	const mediaType = imageMediaType(buffer);
+	if (!mediaType) {
+		throw new Error('the clipboard does not hold a PNG or JPEG image');
+	}
+	const pad = (n: number) => String(n).padStart(2, '0');
+	const stamp = `${now.getFullYear()}${pad(now.getMonth() + 1)}${pad(now.getDate())}-${pad(now.getHours())}${pad(now.getMinutes())}${pad(now.getSeconds())}`;
+	const filePath = join(ATTACHMENTS_DIR, `clipboard-${stamp}.${mediaType === 'image/png' ? 'png' : 'jpg'}`);
+
+	await mkdir(resolve(cwd, ATTACHMENTS_DIR), {recursive: true});
+	await writeFile(resolve(cwd, filePath), buffer);
+	return filePath;
+}
+
+// ============================================================================
+// FORMATTING
+// ============================================================================
+
+/**
+ * One "[image attached: name]" line per image, shown above the message text
+ */
+export function formatImageMarkers(images: Array<Pick<ImageAttachment, 'name'>>): string {
+	return images.map(image => `[image attached: ${image.name}]`).join('\n');
+}
+
+/**
+ * Human-readable size, e.g. "120 KB"
+ */
+export function formatSize(bytes: number): string {
+	if (bytes < 1024) return `${bytes} B`;
+	if (bytes < 1024 * 1024) return `${Math.round(bytes / 1024)} KB`;
+	return `${(bytes / (1024 * 1024)).toFixed(1)} MB`;
+}
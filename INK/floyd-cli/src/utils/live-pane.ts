@@ -0,0 +1,196 @@
+/**
+ * Live Pane
+ *
+ * Purpose: Decide what the live side pane shows for a running tool: the file it edits with the change as a diff, or the tail of its output
+ * Exports: livePaneForToolStart(), livePaneForToolEnd(), paneDiff(), tailLines(), LivePaneContent, LIVE_PANE_LINES
+ * Related: ui/panels/LivePanePanel.tsx, ui/layouts/MainLayout.tsx (Ctrl+O), app.tsx (tool callbacks)
+ */
+
+import {readFile} from 'node:fs/promises';
+import {isAbsolute, relative, resolve} from 'node:path';
+import {diffLines} from './diff-parser.js';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Most lines the pane keeps (diff or output tail)
+ */
+export const LIVE_PANE_LINES = 200;
+
+/**
+ * Unchanged lines kept around each change in a diff
+ */
+const CONTEXT_LINES = 2;
+
+/**
+ * Tools that write files, and the input keys holding the path
+ */
+const FILE_TOOLS = new Set(['write', 'write_file', 'edit_file', 'search_replace', 'edit', 'multi_edit']);
+const PATH_KEYS = ['file_path', 'path', 'filePath', 'file'];
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface PaneDiffLine {
+	type: 'addition' | 'deletion' | 'context' | 'gap';
+	text: string;
+}
+
+export type LivePaneContent =
+	| {
+			kind: 'file';
+			tool: string;
+			/** Path as shown, relative to the project when inside it */
+			path: string;
+			status: 'running' | 'done' | 'failed';
+			diff: PaneDiffLine[];
+			/** File content before the tool ran (null for a new file) */
+			before: string | null;
+	  }
+	| {
+			kind: 'output';
+			tool: string;
+			status: 'running' | 'done' | 'failed';
+			lines: string[];
+	  };
+
+interface PaneToolCall {
+	name: string;
+	input?: Record<string, unknown>;
+	output?: string;
+	error?: string;
+}
+
+// ============================================================================
+// DIFF AND OUTPUT
+// ============================================================================
+
+/**
+ * Line diff of a change with unchanged runs cut down to a little context
+ */
+export function paneDiff(before: string, after: string): PaneDiffLine[] {
+	const lines: PaneDiffLine[] = [];
+	const parts = diffLines(before, after);
+
+	parts.forEach((part, index) => {
+		const text = part.value.endsWith('\n') ? part.value.slice(0, -1) : part.value;
+		const partLines = text.split('\n');
+		if (part.added || part.removed) {
+			const type = part.added ? 'addition' : 'deletion';
+			lines.push(...partLines.map(line => ({type, text: line}) as PaneDiffLine));
+			return;
+		}
+
+		// Unchanged: keep the lines next to the changes on either side
+		const keepHead = index > 0 ? CONTEXT_LINES : 0;
+		const keepTail = index < parts.length - 1 ? CONTEXT_LINES : 0;
+		if (partLines.length <= keepHead + keepTail) {
+			lines.push(...partLines.map(line => ({type: 'context', text: line}) as PaneDiffLine));
+			return;
+		}
+		lines.push(...partLines.slice(0, keepHead).map(line => ({type: 'context', text: line}) as PaneDiffLine));
+		lines.push({type: 'gap', text: `… ${partLines.length - keepHead - keepTail} unchanged lines`});
+		lines.push(
+			...partLines.slice(partLines.length - keepTail).map(line => ({type: 'context', text: line}) as PaneDiffLine),
+		);
+	});
+
+	return lines.slice(0, LIVE_PANE_LINES);
+}
+
+/**
+ * Last lines of a tool's output
+ */
+export function tailLines(output: string, count: number = LIVE_PANE_LINES): string[] {
+	const lines = output.replace(/\n+$/, '').split('\n');
+	return output.trim() ? lines.slice(-count) : [];
+}
+
+// ============================================================================
+// TOOL CALLS
+// ============================================================================
+
+function filePathOf(input: Record<string, unknown> | undefined): string | null {
+	for (const key of PATH_KEYS) {
+		const value = input?.[key];
+		if (typeof value === 'string' && value.trim()) return value.trim();
+	}
+	return null;
+}
+
+function displayPath(filePath: string, cwd: string): string {
+	const relativePath = relative(cwd, resolve(cwd, filePath));
+	return relativePath.startsWith('..') || isAbsolute(relativePath) ? filePath : relativePath;
+}
+
+async function readOrNull(filePath: string): Promise<string | null> {
+	return readFile(filePath, 'utf8').catch(() => null);
+}
+
+/**
+ * The content a file tool is about to write, when its input says so
+ */
+function proposedContent(before: string | null, input: Record<string, unknown>): string | null {
+	if (typeof input['content'] === 'string') {
+		return input['content'];
+	}
+	const search = input['old_string'] ?? input['oldString'] ?? input['search'];
+	const replace = input['new_string'] ?? input['newString'] ?? input['replace'];
+	if (before !== null && typeof search === 'string' && typeof replace === 'string' && search) {
+		return input['replace_all'] || input['replaceAll']
+			? before.split(search).join(replace)
+			: before.replace(search, () => replace);
+	}
+	return null;
+}
+
+/**
+ * Pane content when a tool starts: file tools show the change they are
+ * about to make, everything else waits for output
+ */
+export async function livePaneForToolStart(call: PaneToolCall, cwd: string = process.cwd()): Promise<LivePaneContent> {
+	const filePath = FILE_TOOLS.has(call.name) ? filePathOf(call.input) : null;
+	if (!filePath) {
+		return {kind: 'output', tool: call.name, status: 'running', lines: []};
+	}
+
+	const before = await readOrNull(resolve(cwd, filePath));
+	const after = proposedContent(before, call.input ?? {});
+	return {
+		kind: 'file',
+		tool: call.name,
+		path: displayPath(filePath, cwd),
+		status: 'running',
+		diff: after === null ? [] : paneDiff(before ?? '', after),
+		before,
+	};
+}
+
+/**
+ * Pane content when a tool finishes: file tools diff what is on disk now
+ * against the content before the run, everything else shows its output tail
+ */
+export async function livePaneForToolEnd(
+	call: PaneToolCall,
+	current: LivePaneContent | null,
+	cwd: string = process.cwd(),
+): Promise<LivePaneContent> {
+	const status = call.error ? 'failed' : 'done';
+	if (current?.kind === 'file' && current.tool === call.name) {
+		const after = await readOrNull(resolve(cwd, current.path));
+		return {
+			...current,
+			status,
+			diff: after === null || call.error ? current.diff : paneDiff(current.before ?? '', after),
+		};
+	}
+	return {
+		kind: 'output',
+		tool: call.name,
+		status,
+		lines: tailLines(call.error ? `Error: ${call.error}` : (call.output ?? '')),
+	};
+}
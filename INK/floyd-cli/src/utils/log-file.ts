@@ -0,0 +1,151 @@
+/**
+ * Log File
+ *
+ * Purpose: Rotating JSON-lines log file in .floyd/logs/ (same format as the
+ *          readline CLI) so the TUI can log without writing over Ink's output,
+ *          plus tail reading for the /logs command
+ * Exports: RotatingLogFile, getLogFilePath(), readLogTail(), formatLogRecord()
+ * Related: logger.ts, app.tsx
+ */
+
+import {appendFileSync, existsSync, mkdirSync, renameSync, rmSync, statSync} from 'node:fs';
+import {readFile} from 'node:fs/promises';
+import {dirname, join} from 'node:path';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export type LogRecordLevel = 'debug' | 'info' | 'warn' | 'error';
+
+export interface LogRecord {
+	time: string;
+	level: LogRecordLevel;
+	msg: string;
+	data?: unknown;
+}
+
+const LEVEL_PRIORITY: Record<LogRecordLevel, number> = {debug: 0, info: 1, warn: 2, error: 3};
+
+// ============================================================================
+// HELPERS
+// ============================================================================
+
+/**
+ * Location of the log file (FLOYD_LOG_FILE overrides, "off" disables)
+ */
+export function getLogFilePath(cwd: string = process.cwd()): string | null {
+	const configured = process.env['FLOYD_LOG_FILE'];
+	if (configured === 'off' || configured === 'false') {
+		return null;
+	}
+	return configured || join(cwd, '.floyd', 'logs', 'floyd.log');
+}
+
+/**
+ * Read the last records, optionally at or above a level
+ */
+export async function readLogTail(
+	filePath: string,
+	count = 50,
+	minLevel: LogRecordLevel = 'debug',
+): Promise<LogRecord[]> {
+	const content = await readFile(filePath, 'utf-8').catch(() => '');
+	const records: LogRecord[] = [];
+
+	for (const line of content.split('\n')) {
+		if (!line.trim()) continue;
+		try {
+			const record = JSON.parse(line) as LogRecord;
+			if (LEVEL_PRIORITY[record.level] >= LEVEL_PRIORITY[minLevel]) {
+				records.push(record);
+			}
+		} catch {
+			// Partial line from a concurrent writer
+		}
+	}
+
+	return records.slice(-count);
+}
+
+/**
+ * Format a record as one display line
+ */
+export function formatLogRecord(record: LogRecord): string {
+	const time = record.time.slice(11, 19);
+	let data = '';
+	if (record.data !== undefined) {
+		try {
+			data = ` ${JSON.stringify(record.data)}`;
+		} catch {
+			data = ' [unserializable]';
+		}
+	}
+	return `${time} ${record.level.toUpperCase().padEnd(5)} ${record.msg}${data}`;
+}
+
+// ============================================================================
+// ROTATING LOG FILE
+// ============================================================================
+
+/**
+ * Appends records and rotates floyd.log -> floyd.log.1 -> ... by size
+ */
+export class RotatingLogFile {
+	private size = -1;
+	private broken = false;
+
+	constructor(
+		private readonly filePath: string,
+		private readonly maxBytes: number = parseInt(process.env['FLOYD_LOG_MAX_SIZE'] || '', 10) || 5 * 1024 * 1024,
+		private readonly maxFiles: number = parseInt(process.env['FLOYD_LOG_MAX_FILES'] || '', 10) || 5,
+	) {}
+
+	getPath(): string {
+		return this.filePath;
+	}
+
+	/**
+	 * Append a record, rotating first if the file is full
+	 */
+	write(record: LogRecord): void {
+		if (this.broken) return;
+
+		let line: string;
+		try {
+			line = JSON.stringify(record) + '\n';
+		} catch {
+			line = JSON.stringify({...record, data: '[unserializable]'}) + '\n';
+		}
+
+		try {
+			if (this.size < 0) {
+				mkdirSync(dirname(this.filePath), {recursive: true});
+				this.size = existsSync(this.filePath) ? statSync(this.filePath).size : 0;
+			}
+
+			if (this.size > 0 && this.size + line.length > this.maxBytes) {
+				this.rotate();
+			}
+
+			appendFileSync(this.filePath, line);
+			this.size += Buffer.byteLength(line);
+		} catch {
+			// Logging must never take the TUI down
+			this.broken = true;
+		}
+	}
+
+	private rotate(): void {
+		for (let i = this.maxFiles - 1; i >= 1; i--) {
+			const from = i === 1 ? this.filePath : `${this.filePath}.${i - 1}`;
+			if (existsSync(from)) {
+				renameSync(from, `${this.filePath}.${i}`);
+			}
+		}
+		if (this.maxFiles <= 1) {
+			rmSync(this.filePath, {force: true});
+		}
+		this.size = 0;
+	}
+}
@@ -1,12 +1,14 @@
 /**
  * Logger Utility
  *
- * Purpose: Structured logging with levels and formatting for CLI output
- * Exports: Logger class, createLogger(), log levels
- * Related: audit-logger.ts
+ * Purpose: Structured logging with levels and formatting for CLI output,
+ *          optionally mirrored to a rotating file in .floyd/logs/
+ * Exports: Logger class, createLogger(), createFileLogger(), log levels
+ * Related: audit-logger.ts, log-file.ts
  */
 
 import chalk from 'chalk';
+import {RotatingLogFile, getLogFilePath, type LogRecordLevel} from './log-file.js';
 
 // ============================================================================
 // LOG LEVELS
@@ -26,6 +28,10 @@ export interface LoggerOptions {
 	timestamp?: boolean;
 	color?: boolean;
 	output?: 'stdout' | 'stderr';
+	/** Also write JSON-lines records to this (rotating) file */
+	file?: string;
+	/** Suppress terminal output, e.g. while Ink owns the screen */
+	quiet?: boolean;
 }
 
 export interface LogEntry {
@@ -55,6 +61,8 @@ export class Logger {
 	private timestamp: boolean;
 	private color: boolean;
 	private output: NodeJS.WriteStream;
+	private file: RotatingLogFile | null;
+	private quiet: boolean;
 
 	constructor(options: LoggerOptions = {}) {
 		this.level = options.level ?? LogLevel.Info;
@@ -62,6 +70,15 @@ export class Logger {
 		this.timestamp = options.timestamp ?? false;
 		this.color = options.color ?? true;
 		this.output = options.output === 'stderr' ? process.stderr : process.stdout;
+		this.file = options.file ? new RotatingLogFile(options.file) : null;
+		this.quiet = options.quiet ?? false;
+	}
+
+	/**
+	 * Path of the log file, if file logging is enabled
+	 */
+	getFilePath(): string | null {
+		return this.file?.getPath() ?? null;
 	}
 
 	/**
@@ -139,6 +156,8 @@ export class Logger {
 			timestamp: this.timestamp,
 			color: this.color,
 			output: this.output === process.stderr ? 'stderr' : 'stdout',
+			file: this.file?.getPath(),
+			quiet: this.quiet,
 		});
 	}
 
@@ -155,6 +174,19 @@ export class Logger {
 			return;
 		}
 
+		if (this.file) {
+			this.file.write({
+				time: new Date().toISOString(),
+				level: LogLevel[level].toLowerCase() as LogRecordLevel,
+				msg: this.prefix ? `[${this.prefix}] ${message}` : message,
+				data: context,
+			});
+		}
+
+		if (this.quiet) {
+			return;
+		}
+
 		const parts: string[] = [];
 
 		// Add timestamp if enabled
@@ -255,6 +287,24 @@ export function createModuleLogger(
 	});
 }
 
+/**
+ * Create a logger that only writes to .floyd/logs/floyd.log, for the TUI.
+ * Level comes from FLOYD_LOG_LEVEL (default info).
+ */
+export function createFileLogger(cwd: string = process.cwd()): Logger {
+	const levels: Record<string, LogLevel> = {
+		debug: LogLevel.Debug,
+		info: LogLevel.Info,
+		warn: LogLevel.Warn,
+		error: LogLevel.Error,
+	};
+	return new Logger({
+		level: levels[process.env['FLOYD_LOG_LEVEL'] ?? ''] ?? LogLevel.Info,
+		file: getLogFilePath(cwd) ?? undefined,
+		quiet: true,
+	});
+}
+
 /**
  * Create a silent logger (no output)
  */
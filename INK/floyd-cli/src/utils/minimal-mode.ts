@@ -0,0 +1,30 @@
+/**
+ * Minimal Mode
+ *
+ * Purpose: Low-overhead rendering profile for slow terminals and SSH sessions
+ * Exports: isMinimalMode(), MINIMAL_MODE
+ * Related: GradientHeader.tsx, AnimatedBox.tsx, ThinkingStream.tsx, MarkdownRenderer.tsx
+ *
+ * Enabled with FLOYD_MINIMAL=1. Disables the ASCII banner, shimmer and other
+ * timer-driven animations, and renders markdown as plain text. Agent
+ * functionality is unchanged.
+ */
+
+/**
+ * Check whether minimal mode is requested in the environment
+ */
+export function isMinimalMode(env: NodeJS.ProcessEnv = process.env): boolean {
+	const value = env['FLOYD_MINIMAL']?.toLowerCase();
+	return value === '1' || value === 'true' || value === 'yes';
+}
+
+/**
+ * Minimal mode flag, resolved once at startup
+ */
+export const MINIMAL_MODE = isMinimalMode();
+
+/**
+ * Stream flush interval for the current profile (ms)
+ * Minimal mode batches tokens into fewer, larger terminal writes.
+ */
+export const STREAM_FLUSH_INTERVAL = MINIMAL_MODE ? 150 : 33;
@@ -0,0 +1,188 @@
+/**
+ * Mouse Terminal
+ *
+ * Purpose: Wire SGR mouse reporting into Ink: strip mouse events from stdin, keep the last rendered frame, highlight and copy drag selections, open clicked links
+ * Exports: enableMouseMode(), FrameRecorder, MouseMode
+ * Related: utils/mouse.ts (parsing, selection, links), utils/clipboard.ts, utils/stdin-filter.ts, cli.tsx (--mouse)
+ */
+
+import {execa} from 'execa';
+import {copyToClipboard} from './clipboard.js';
+import {getLogger} from './logger.js';
+import {createFilteredStdin} from './stdin-filter.js';
+import {
+	MOUSE_OFF,
+	MOUSE_ON,
+	MouseSelection,
+	findLinkAt,
+	openCommand,
+	parseMouseEvents,
+	selectedText,
+	stripAnsi,
+	type SelectionRange,
+} from './mouse.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface MouseMode {
+	/** Streams to render Ink with */
+	stdin: NodeJS.ReadStream;
+	stdout: NodeJS.WriteStream;
+	/** Turn mouse reporting off again */
+	dispose: () => void;
+}
+
+// ============================================================================
+// FRAME RECORDER
+// ============================================================================
+
+/**
+ * Start of a new Ink frame: eraseLines ends with "cursor to column 1",
+ * clearTerminal with "cursor home"
+ */
+// eslint-disable-next-line no-control-regex
+const FRAME_START = /\x1b\[(?:G|H|2J|3J)/g;
+
+/**
+ * Keeps the plain text of the last frame Ink wrote
+ */
+export class FrameRecorder {
+	private frame: string[] = [];
+
+	record(chunk: string): void {
+		let start = -1;
+		for (const match of chunk.matchAll(FRAME_START)) {
+			start = match.index! + match[0].length;
+		}
+		// Cursor show/hide and other small writes are not frames
+		if (start === -1) {
+			return;
+		}
+		this.frame = stripAnsi(chunk.slice(start)).split('\n');
+	}
+
+	/**
+	 * Screen rows as plain text (index 0 = row 1), assuming the frame ends at
+	 * the bottom of a screen `rows` tall
+	 */
+	screen(rows: number): string[] {
+		return this.frame.slice(Math.max(0, this.frame.length - rows));
+	}
+}
+
+// ============================================================================
+// HIGHLIGHT
+// ============================================================================
+
+/**
+ * Repaint the selected cells over the current frame, reversed or plain
+ */
+function paintSelection(write: (data: string) => void, lines: string[], range: SelectionRange, reverse: boolean): void {
+	let out = '\x1b7';
+	for (let y = range.start.y; y <= range.end.y; y++) {
+		const chars = Array.from(lines[y - 1] ?? '');
+		const from = y === range.start.y ? range.start.x - 1 : 0;
+		const to = y === range.end.y ? range.end.x : chars.length;
+		const text = chars.slice(from, to).join('');
+		if (text) {
+			out += `\x1b[${y};${from + 1}H${reverse ? `\x1b[7m${text}\x1b[27m` : text}`;
+		}
+	}
+	write(`${out}\x1b8`);
+}
+
+// ============================================================================
+// WIRING
+// ============================================================================
+
+/**
+ * Turn on mouse reporting and return the streams Ink should use
+ */
+export function enableMouseMode(
+	input: NodeJS.ReadStream = process.stdin,
+	output: NodeJS.WriteStream = process.stdout,
+): MouseMode {
+	const write = (data: string) => output.write(data);
+	const recorder = new FrameRecorder();
+	const selection = new MouseSelection();
+	let highlighted: SelectionRange | null = null;
+
+	const clearHighlight = (lines: string[]) => {
+		if (highlighted) {
+			paintSelection(write, lines, highlighted, false);
+			highlighted = null;
+		}
+	};
+
+	const onEvents = (events: ReturnType<typeof parseMouseEvents>['events']) => {
+		const lines = recorder.screen(output.rows || 24);
+		for (const event of events) {
+			const action = selection.handle(event);
+			if (!action) continue;
+
+			if (action.type === 'clear') {
+				clearHighlight(lines);
+			} else if (action.type === 'highlight') {
+				clearHighlight(lines);
+				paintSelection(write, lines, action.range, true);
+				highlighted = action.range;
+			} else if (action.type === 'select') {
+				const text = selectedText(lines, action.range);
+				if (text.trim()) {
+					void copyToClipboard(text, {write}).then(result =>
+						getLogger().info('Copied mouse selection', {chars: text.length, command: result.command}),
+					);
+				}
+			} else {
+				clearHighlight(lines);
+				const link = findLinkAt(lines[action.point.y - 1] ?? '', action.point.x);
+				const opener = link ? openCommand(link) : null;
+				if (link && opener) {
+					getLogger().info('Opening link', {link});
+					execa(opener.command, opener.args, {detached: true, stdio: 'ignore', reject: false})
+						.then(result => {
+							if (result.exitCode !== 0) {
+								getLogger().warn('Opening link failed', {command: opener.command, exitCode: result.exitCode});
+							}
+						})
+						.catch(error => getLogger().warn('Opening link failed', {error: String(error)}));
+				}
+			}
+		}
+	};
+
+	// stdin for Ink without the mouse sequences
+	const filtered = createFilteredStdin(input, data => {
+		const {events, rest} = parseMouseEvents(data);
+		if (events.length > 0) onEvents(events);
+		return rest;
+	});
+
+	// stdout for Ink that remembers what it drew
+	const stdout = new Proxy(output, {
+		get(target, property, receiver) {
+			if (property === 'write') {
+				return (chunk: string | Uint8Array, ...rest: unknown[]) => {
+					recorder.record(typeof chunk === 'string' ? chunk : Buffer.from(chunk).toString());
+					return (target.write as (...args: unknown[]) => boolean)(chunk, ...rest);
+				};
+			}
+			const value = Reflect.get(target, property, receiver);
+			return typeof value === 'function' ? value.bind(target) : value;
+		},
+	});
+
+	write(MOUSE_ON);
+	let disposed = false;
+	const dispose = () => {
+		if (disposed) return;
+		disposed = true;
+		filtered.dispose();
+		write(MOUSE_OFF);
+	};
+	process.on('exit', dispose);
+
+	return {stdin: filtered.stdin, stdout, dispose};
+}
@@ -0,0 +1,239 @@
+/**
+ * Mouse
+ *
+ * Purpose: SGR mouse reporting for the TUI: parse cell-motion events, turn drags into text selections and clicks into links to open
+ * Exports: parseMouseEvents(), MouseSelection, selectedText(), findLinkAt(), openCommand(), stripAnsi(), MOUSE_ON, MOUSE_OFF
+ * Related: utils/mouse-terminal.ts (stdin/stdout wiring), cli.tsx (--mouse)
+ */
+
+import {basename} from 'node:path';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export interface MouseEvent {
+	type: 'press' | 'drag' | 'release' | 'wheel';
+	/** 0 left, 1 middle, 2 right; for wheel 0 up, 1 down */
+	button: number;
+	/** 1-based screen column */
+	x: number;
+	/** 1-based screen row */
+	y: number;
+}
+
+export interface ScreenPoint {
+	x: number;
+	y: number;
+}
+
+/**
+ * Selected cells, start before end in reading order
+ */
+export interface SelectionRange {
+	start: ScreenPoint;
+	end: ScreenPoint;
+}
+
+export type SelectionAction =
+	| {type: 'highlight'; range: SelectionRange}
+	| {type: 'select'; range: SelectionRange}
+	| {type: 'click'; point: ScreenPoint}
+	| {type: 'clear'};
+
+export interface Link {
+	kind: 'url' | 'file';
+	target: string;
+	/** Line number for file:line references */
+	line?: number;
+}
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Button-event (cell motion) tracking with SGR coordinates
+ */
+export const MOUSE_ON = '\x1b[?1002h\x1b[?1006h';
+export const MOUSE_OFF = '\x1b[?1002l\x1b[?1006l';
+
+// eslint-disable-next-line no-control-regex
+const SGR_MOUSE = /\x1b\[<(\d+);(\d+);(\d+)([Mm])/g;
+
+// eslint-disable-next-line no-control-regex
+const ANSI = /\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]/g;
+
+const URL_PATTERN = /\bhttps?:\/\/[^\s<>"'`]+/g;
+const FILE_PATTERN = /(?:\.{0,2}\/)?(?:[\w@.-]+\/)*[\w@.-]+\.[A-Za-z0-9]{1,8}(?::(\d+))?(?::\d+)?/g;
+
+/**
+ * Editors that need a terminal of their own
+ */
+const TERMINAL_EDITORS = ['vi', 'vim', 'nvim', 'nano', 'emacs', 'hx', 'helix', 'micro', 'kak'];
+
+/**
+ * Editors that take --goto file:line
+ */
+const GOTO_EDITORS = ['code', 'code-insiders', 'cursor', 'codium', 'windsurf'];
+
+// ============================================================================
+// PARSING
+// ============================================================================
+
+/**
+ * Text without ANSI escape sequences
+ */
+export function stripAnsi(text: string): string {
+	return text.replace(ANSI, '');
+}
+
+/**
+ * Mouse events in a chunk of terminal input, and the input without them
+ */
+export function parseMouseEvents(data: string): {events: MouseEvent[]; rest: string} {
+	const events: MouseEvent[] = [];
+	const rest = data.replace(SGR_MOUSE, (_match, code: string, x: string, y: string, final: string) => {
+		const value = Number(code);
+		const button = value & 3;
+		const point = {x: Number(x), y: Number(y)};
+		if (value & 64) {
+			events.push({type: 'wheel', button, ...point});
+		} else if (final === 'm') {
+			events.push({type: 'release', button, ...point});
+		} else {
+			events.push({type: value & 32 ? 'drag' : 'press', button, ...point});
+		}
+		return '';
+	});
+	return {events, rest};
+}
+
+// ============================================================================
+// SELECTION
+// ============================================================================
+
+function ordered(a: ScreenPoint, b: ScreenPoint): SelectionRange {
+	return a.y < b.y || (a.y === b.y && a.x <= b.x) ? {start: a, end: b} : {start: b, end: a};
+}
+
+/**
+ * Turns left-button presses, drags and releases into selections and clicks
+ */
+export class MouseSelection {
+	private anchor: ScreenPoint | null = null;
+	private range: SelectionRange | null = null;
+
+	handle(event: MouseEvent): SelectionAction | null {
+		if (event.type === 'wheel' || event.button !== 0) {
+			return null;
+		}
+		const point = {x: event.x, y: event.y};
+
+		if (event.type === 'press') {
+			const hadSelection = this.range !== null;
+			this.anchor = point;
+			this.range = null;
+			return hadSelection ? {type: 'clear'} : null;
+		}
+
+		if (!this.anchor) {
+			return null;
+		}
+
+		if (event.type === 'drag') {
+			this.range = ordered(this.anchor, point);
+			return {type: 'highlight', range: this.range};
+		}
+
+		// Release: a drag ends in a selection, a press in place is a click
+		const anchor = this.anchor;
+		this.anchor = null;
+		if (this.range && (anchor.x !== point.x || anchor.y !== point.y)) {
+			return {type: 'select', range: ordered(anchor, point)};
+		}
+		this.range = null;
+		return {type: 'click', point};
+	}
+}
+
+/**
+ * Text of a selection on screen lines (plain text, index 0 = row 1)
+ */
+export function selectedText(lines: string[], range: SelectionRange): string {
+	const selected: string[] = [];
+	for (let y = range.start.y; y <= range.end.y; y++) {
+		const chars = Array.from(lines[y - 1] ?? '');
+		const from = y === range.start.y ? range.start.x - 1 : 0;
+		const to = y === range.end.y ? range.end.x : chars.length;
+		selected.push(chars.slice(from, to).join('').trimEnd());
+	}
+	return selected.join('\n');
+}
+
+// ============================================================================
+// LINKS
+// ============================================================================
+
+/**
+ * URL or file path under a 1-based column of a screen line
+ */
+export function findLinkAt(line: string, column: number): Link | null {
+	const index = column - 1;
+	const within = (match: RegExpMatchArray) =>
+		match.index !== undefined && index >= match.index && index < match.index + match[0].length;
+
+	for (const match of line.matchAll(URL_PATTERN)) {
+		if (within(match)) {
+			return {kind: 'url', target: match[0].replace(/[.,;:!?)\]}]+$/, '')};
+		}
+	}
+	for (const match of line.matchAll(FILE_PATTERN)) {
+		const target = match[0].replace(/(?::\d+)+$/, '');
+		// A bare word with a dot ("e.g.", "v1.2") is not worth opening
+		if (within(match) && (target.includes('/') || /[\w@-]{2,}\.[A-Za-z]\w*$/.test(target))) {
+			return {kind: 'file', target, line: match[1] ? Number(match[1]) : undefined};
+		}
+	}
+	return null;
+}
+
+/**
+ * Command that opens a link: URLs in the browser, files in $VISUAL/$EDITOR
+ * (terminal editors in a new tmux window) or the system opener
+ */
+export function openCommand(
+	link: Link,
+	platform: NodeJS.Platform = process.platform,
+	env: NodeJS.ProcessEnv = process.env,
+): {command: string; args: string[]} | null {
+	const systemOpen = (target: string) =>
+		platform === 'darwin'
+			? {command: 'open', args: [target]}
+			: platform === 'win32'
+				? {command: 'cmd', args: ['/c', 'start', '', target]}
+				: {command: 'xdg-open', args: [target]};
+
+	if (link.kind === 'url') {
+		return systemOpen(link.target);
+	}
+
+	const editor = (env['FLOYD_EDITOR'] || env['VISUAL'] || env['EDITOR'] || '').trim();
+	if (!editor) {
+		return systemOpen(link.target);
+	}
+
+	const [command, ...editorArgs] = editor.split(/\s+/) as [string, ...string[]];
+	const name = basename(command);
+	const fileArgs = GOTO_EDITORS.includes(name)
+		? ['--goto', link.line ? `${link.target}:${link.line}` : link.target]
+		: link.line
+			? [`+${link.line}`, link.target]
+			: [link.target];
+
+	if (TERMINAL_EDITORS.includes(name)) {
+		// The TUI owns this terminal
+		return env['TMUX'] ? {command: 'tmux', args: ['new-window', command, ...editorArgs, ...fileArgs]} : null;
+	}
+	return {command, args: [...editorArgs, ...fileArgs]};
+}
@@ -0,0 +1,112 @@
+/**
+ * Offline Mode
+ *
+ * Purpose: Keep the TUI useful without an API key: detect offline mode and run /run and /read without the model
+ * Exports: getOfflineReason(), describeOffline(), runShellLine(), readFileLines(), OFFLINE_HINT
+ * Related: app.tsx (startup notice, blocking model calls), commands/app-commands.ts
+ */
+
+import {readFile} from 'node:fs/promises';
+import {resolve} from 'node:path';
+import {execa} from 'execa';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export type OfflineReason = 'requested' | 'no-api-key';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * What still works offline
+ */
+export const OFFLINE_HINT = 'Use /run <command> and /read <file> [offset] [limit] to work without the model.';
+
+/**
+ * Longest output shown for /run, so a noisy command can't flood the transcript
+ */
+const MAX_OUTPUT_CHARS = 20_000;
+
+// ============================================================================
+// DETECTION
+// ============================================================================
+
+/**
+ * Why the model can't be called, or null when it can
+ *
+ * @param apiKey - Key that will be used, e.g. one stored with /auth (default: from env)
+ */
+export function getOfflineReason(
+	env: NodeJS.ProcessEnv = process.env,
+	apiKey: string | null | undefined = env['FLOYD_GLM_API_KEY'] || env['GLM_API_KEY'],
+): OfflineReason | null {
+	if (['1', 'true', 'yes'].includes((env['FLOYD_OFFLINE'] ?? '').toLowerCase())) {
+		return 'requested';
+	}
+	if (!apiKey) {
+		return 'no-api-key';
+	}
+	return null;
+}
+
+/**
+ * One-line notice for the conversation
+ */
+export function describeOffline(reason: OfflineReason): string {
+	return reason === 'requested'
+		? '[!] Offline mode (FLOYD_OFFLINE): model calls are disabled.'
+		: '[!] Offline mode: no API key (set FLOYD_GLM_API_KEY, or store one with /auth login glm <key>), so model calls are disabled.';
+}
+
+// ============================================================================
+// DIRECT TOOLS
+// ============================================================================
+
+/**
+ * Run a command line in the user's shell and format its output
+ */
+export async function runShellLine(line: string, cwd: string = process.cwd()): Promise<string> {
+	const started = Date.now();
+	const result = await execa(process.env['SHELL'] || 'sh', ['-c', line], {
+		cwd,
+		all: true,
+		reject: false,
+		stdin: 'ignore',
+	});
+	const seconds = ((Date.now() - started) / 1000).toFixed(1);
+	const output = truncate(String(result.all ?? '').trimEnd());
+	const status = result.exitCode === 0 ? '[OK]' : `[!] exit ${result.exitCode ?? 'killed'}`;
+	return [`$ ${line}`, ...(output ? [output] : []), `${status} in ${seconds}s`].join('\n');
+}
+
+/**
+ * Numbered lines of a file, from a 1-based offset
+ */
+export async function readFileLines(
+	filePath: string,
+	offset = 1,
+	limit = 200,
+	cwd: string = process.cwd(),
+): Promise<string> {
+	const absolute = resolve(cwd, filePath);
+	const lines = (await readFile(absolute, 'utf8')).split('\n');
+	const start = Math.max(offset, 1) - 1;
+	const shown = lines.slice(start, start + Math.max(limit, 1));
+	const width = String(start + shown.length).length;
+	const body = shown.map((text, index) => `${String(start + index + 1).padStart(width)}  ${text}`);
+	const rest = lines.length - start - shown.length;
+	return [
+		`${filePath} (${lines.length} lines)`,
+		...body,
+		...(rest > 0 ? [`... ${rest} more (use /read ${filePath} ${start + shown.length + 1})`] : []),
+	].join('\n');
+}
+
+function truncate(text: string): string {
+	return text.length > MAX_OUTPUT_CHARS
+		? `${text.slice(0, MAX_OUTPUT_CHARS)}\n... output truncated (${text.length - MAX_OUTPUT_CHARS} more characters)`
+		: text;
+}
@@ -0,0 +1,240 @@
+/**
+ * Pinned Context
+ *
+ * Purpose: The workspace tree behind the Ctrl+F file browser (listed one directory at a time, honoring
+ *          .gitignore) and the pinned files sent along with every request until /unpin
+ * Exports: parseGitignore(), isIgnored(), WorkspaceTree, unpinPaths(), buildPinnedContext(), formatPinned(),
+ *          MAX_PINNED_FILES, IgnoreRule, TreeEntry
+ * Related: ui/overlays/FileBrowserOverlay.tsx, file-mentions.ts (readFileBlock), app.tsx (sends the context)
+ */
+
+import {readdir, readFile} from 'node:fs/promises';
+import {join} from 'node:path';
+import {readFileBlock} from './file-mentions.js';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Files sent per request; a pinned directory counts each file in it
+ */
+export const MAX_PINNED_FILES = 20;
+
+/**
+ * Never listed, whatever .gitignore says
+ */
+const ALWAYS_IGNORED = new Set(['.git', 'node_modules', '.DS_Store']);
+
+// ============================================================================
+// .GITIGNORE
+// ============================================================================
+
+export interface IgnoreRule {
+	/** Directory of the .gitignore, relative to the workspace ('' for the root) */
+	base: string;
+	pattern: RegExp;
+	/** "!pattern" re-includes what an earlier rule ignored */
+	negate: boolean;
+	/** "pattern/" only matches directories */
+	dirOnly: boolean;
+}
+
+/**
+ * Rules of a .gitignore file in the directory `base`
+ */
+export function parseGitignore(text: string, base = ''): IgnoreRule[] {
+	const rules: IgnoreRule[] = [];
+	for (const raw of text.split(/\r?\n/)) {
+		let line = raw.replace(/(?<!\\)\s+$/, '');
+		if (!line || line.startsWith('#')) continue;
+
+		const negate = line.startsWith('!');
+		if (negate) line = line.slice(1);
+		line = line.replace(/^\\([#!])/, '$1');
+		const dirOnly = line.endsWith('/');
+		if (dirOnly) line = line.slice(0, -1);
+		// A slash before the end ties the pattern to the .gitignore's directory
+		const anchored = line.includes('/');
+		if (line.startsWith('/')) line = line.slice(1);
+		if (!line) continue;
+
+		rules.push({base, pattern: globToRegExp(line, anchored), negate, dirOnly});
+	}
+	return rules;
+}
+
+function globToRegExp(glob: string, anchored: boolean): RegExp {
+	let source = '';
+	for (let i = 0; i < glob.length; i++) {
+		const ch = glob[i]!;
+		if (ch === '*' && glob[i + 1] === '*') {
+			// "**/" is any number of directories, a trailing "**" everything below
+			if (glob[i + 2] === '/') {
+				source += '(?:.*/)?';
+				i += 2;
+			} else {
+				source += '.*';
+				i += 1;
+			}
+		} else if (ch === '*') {
+			source += '[^/]*';
+		} else if (ch === '?') {
+			source += '[^/]';
+		} else if (ch === '[' && glob.indexOf(']', i + 2) !== -1) {
+			const end = glob.indexOf(']', i + 2);
+			source += `[${glob.slice(i + 1, end).replace(/^!/, '^').replace(/\\/g, '\\\\')}]`;
+			i = end;
+		} else if (ch === '\\' && i + 1 < glob.length) {
+			source += glob[++i]!.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+		} else {
+			source += ch.replace(/[.+^${}()|[\]\\]/g, '\\$&');
+		}
+	}
+	return new RegExp(`^${anchored ? '' : '(?:.*/)?'}${source}$`);
+}
+
+/**
+ * Whether a workspace path (forward slashes) is ignored; the last matching
+ * rule wins
+ */
+export function isIgnored(path: string, isDirectory: boolean, rules: IgnoreRule[]): boolean {
+	let ignored = false;
+	for (const rule of rules) {
+		if (rule.dirOnly && !isDirectory) continue;
+		if (rule.base && !path.startsWith(`${rule.base}/`)) continue;
+		const local = rule.base ? path.slice(rule.base.length + 1) : path;
+		if (rule.pattern.test(local)) {
+			ignored = !rule.negate;
+		}
+	}
+	return ignored;
+}
+
+// ============================================================================
+// WORKSPACE TREE
+// ============================================================================
+
+export interface TreeEntry {
+	/** Path relative to the workspace, with forward slashes */
+	path: string;
+	name: string;
+	isDirectory: boolean;
+}
+
+/**
+ * The workspace read one directory at a time (nothing is walked until it is
+ * opened); each directory's .gitignore applies below it
+ */
+export class WorkspaceTree {
+	private rules = new Map<string, Promise<IgnoreRule[]>>();
+
+	constructor(readonly cwd: string = process.cwd()) {}
+
+	/**
+	 * Entries of a directory ('' for the workspace root): directories first,
+	 * then files, by name
+	 */
+	async list(dir = ''): Promise<TreeEntry[]> {
+		const rules = await this.rulesFor(dir);
+		const dirents = await readdir(join(this.cwd, dir), {withFileTypes: true}).catch(() => []);
+		return dirents
+			.filter(dirent => (dirent.isDirectory() || dirent.isFile()) && !ALWAYS_IGNORED.has(dirent.name))
+			.map(dirent => ({
+				path: dir ? `${dir}/${dirent.name}` : dirent.name,
+				name: dirent.name,
+				isDirectory: dirent.isDirectory(),
+			}))
+			.filter(entry => !isIgnored(entry.path, entry.isDirectory, rules))
+			.sort((a, b) => Number(b.isDirectory) - Number(a.isDirectory) || a.name.localeCompare(b.name));
+	}
+
+	/**
+	 * Files under a directory that are not ignored, up to `limit`
+	 */
+	async files(dir: string, limit = MAX_PINNED_FILES): Promise<string[]> {
+		const files: string[] = [];
+		for (const entry of await this.list(dir)) {
+			if (files.length >= limit) break;
+			if (entry.isDirectory) {
+				files.push(...(await this.files(entry.path, limit - files.length)));
+			} else {
+				files.push(entry.path);
+			}
+		}
+		return files;
+	}
+
+	private rulesFor(dir: string): Promise<IgnoreRule[]> {
+		let rules = this.rules.get(dir);
+		if (!rules) {
+			const parent = dir.includes('/') ? dir.slice(0, dir.lastIndexOf('/')) : '';
+			rules = Promise.all([
+				dir ? this.rulesFor(parent) : Promise.resolve([]),
+				readFile(join(this.cwd, dir, '.gitignore'), 'utf-8').catch(() => ''),
+			]).then(([inherited, text]) => [...inherited, ...parseGitignore(text, dir)]);
+			this.rules.set(dir, rules);
+		}
+		return rules;
+	}
+}
+
+// ============================================================================
+// PINNED CONTEXT
+// ============================================================================
+
+/**
+ * Pins left after /unpin: no argument or "all" removes every pin, a path
+ * removes that pin and any pin below it
+ */
+export function unpinPaths(pinned: string[], target?: string): string[] {
+	const path = target?.replace(/^\.\//, '').replace(/\/+$/, '');
+	if (!path || path === 'all') {
+		return [];
+	}
+	return pinned.filter(pin => pin !== path && !pin.startsWith(`${path}/`));
+}
+
+/**
+ * Read the pinned files and directories (expanded to their files) and format
+ * them as context for the model; re-read for each request so edits show up
+ */
+export async function buildPinnedContext(
+	pinned: string[],
+	tree: WorkspaceTree = new WorkspaceTree(),
+): Promise<{text: string; files: string[]; truncated: boolean}> {
+	const paths: string[] = [];
+	for (const pin of pinned) {
+		// A file lists as an empty directory
+		const below = await tree.files(pin, MAX_PINNED_FILES + 1);
+		for (const path of below.length > 0 ? below : [pin]) {
+			if (!paths.includes(path)) paths.push(path);
+		}
+	}
+
+	const blocks: string[] = [];
+	const files: string[] = [];
+	for (const path of paths.slice(0, MAX_PINNED_FILES)) {
+		const block = await readFileBlock(join(tree.cwd, path), path);
+		if (block) {
+			blocks.push(block);
+			files.push(path);
+		}
+	}
+
+	return {
+		text: blocks.length > 0 ? `Pinned files (kept in context by the user, current contents):\n\n${blocks.join('\n\n')}` : '',
+		files,
+		truncated: paths.length > MAX_PINNED_FILES,
+	};
+}
+
+/**
+ * Short pinned-context indicator, e.g. "pinned: src/app.tsx +2"
+ */
+export function formatPinned(pinned: string[]): string | null {
+	if (pinned.length === 0) {
+		return null;
+	}
+	return `pinned: ${pinned[0]}${pinned.length > 1 ? ` +${pinned.length - 1}` : ''}`;
+}
@@ -0,0 +1,55 @@
+/**
+ * Prompt Editing
+ *
+ * Purpose: Number the user's prompts and find the messages /edit and /delete act on
+ * Exports: numberPrompts(), findPrompt(), PromptRange
+ * Related: /edit and /delete in commands/app-commands.ts, app.tsx, ui/layouts/MainLayout.tsx (Ctrl+E selection), AgentEngine.rewindTo() / deleteTurn()
+ */
+
+import type {ChatMessage} from '../ui/layouts/MainLayout.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Where a prompt and its exchange sit in a message list
+ */
+export interface PromptRange {
+	/** Index of the prompt */
+	start: number;
+
+	/** Index after the exchange (the next prompt, or the end of the list) */
+	end: number;
+
+	/** Prompts after this one (as counted by AgentEngine.rewindTo()) */
+	fromEnd: number;
+}
+
+// ============================================================================
+// FUNCTIONS
+// ============================================================================
+
+/**
+ * Set each user message's number (its position among the prompts, from 1)
+ */
+export function numberPrompts(messages: ChatMessage[]): ChatMessage[] {
+	let count = 0;
+	return messages.map(message => (message.role === 'user' ? {...message, number: ++count} : message));
+}
+
+/**
+ * The nth prompt (from 1) and the replies up to the next one, or null when
+ * there is no such prompt
+ */
+export function findPrompt(messages: ReadonlyArray<{role: string}>, number: number): PromptRange | null {
+	const prompts = messages.flatMap((message, index) => (message.role === 'user' ? [index] : []));
+	if (!Number.isInteger(number) || number < 1 || number > prompts.length) {
+		return null;
+	}
+	return {
+		start: prompts[number - 1]!,
+		end: prompts[number] ?? messages.length,
+		fromEnd: prompts.length - number,
+	};
+}
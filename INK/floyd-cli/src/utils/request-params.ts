@@ -0,0 +1,120 @@
+/**
+ * Request Parameters
+ *
+ * Purpose: Validate the request settings changed with /set (max_tokens, temperature, stop) and read their defaults from .floyd/settings.json
+ * Exports: parseSetArgs(), parseRequestSettings(), formatRequestParams(), REQUEST_PARAM_KEYS, MAX_STOP_SEQUENCES
+ * Related: /set in commands/app-commands.ts, utils/config.ts ("request" in settings.json), AgentEngine.setRequestParams()
+ */
+
+import type {RequestParams} from 'floyd-agent-core';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+export const REQUEST_PARAM_KEYS = ['max_tokens', 'temperature', 'stop'] as const;
+
+export type RequestParamKey = (typeof REQUEST_PARAM_KEYS)[number];
+
+/** OpenAI-compatible APIs accept up to four */
+export const MAX_STOP_SEQUENCES = 4;
+
+const MAX_TOKENS_LIMIT = 200_000;
+
+const USAGE = 'Usage: /set max_tokens <n> | /set temperature <0-2> | /set stop <text...>|none';
+
+export type SetArgs = {params: Partial<RequestParams>} | {error: string};
+
+// ============================================================================
+// PARSING
+// ============================================================================
+
+/**
+ * One setting from its text values, e.g. ('max_tokens', ['4096'])
+ */
+function parseParam(key: RequestParamKey, values: string[]): SetArgs {
+	switch (key) {
+		case 'max_tokens': {
+			const maxTokens = Number(values[0]);
+			if (values.length !== 1 || !Number.isInteger(maxTokens) || maxTokens < 1 || maxTokens > MAX_TOKENS_LIMIT) {
+				return {error: `[!] max_tokens takes a whole number from 1 to ${MAX_TOKENS_LIMIT}.`};
+			}
+			return {params: {maxTokens}};
+		}
+		case 'temperature': {
+			const temperature = Number(values[0]);
+			if (values.length !== 1 || values[0] === '' || !Number.isFinite(temperature) || temperature < 0 || temperature > 2) {
+				return {error: '[!] temperature takes a number from 0 to 2.'};
+			}
+			return {params: {temperature}};
+		}
+		case 'stop': {
+			if (values.length === 1 && values[0] === 'none') {
+				return {params: {stopSequences: []}};
+			}
+			// "\n" in a sequence stands for a line break
+			const stopSequences = values.map(value => value.replace(/\\n/g, '\n')).filter(Boolean);
+			if (stopSequences.length === 0 || stopSequences.length > MAX_STOP_SEQUENCES) {
+				return {error: `[!] stop takes 1 to ${MAX_STOP_SEQUENCES} sequences, or none to clear them.`};
+			}
+			return {params: {stopSequences}};
+		}
+	}
+}
+
+/**
+ * "/set temperature 0.3" → {params: {temperature: 0.3}}
+ */
+export function parseSetArgs(args: string[]): SetArgs {
+	const [key, ...values] = args;
+	if (!REQUEST_PARAM_KEYS.includes(key as RequestParamKey)) {
+		return {error: key ? `[!] Unknown setting "${key}". ${USAGE}` : `[!] ${USAGE}`};
+	}
+	if (values.length === 0) {
+		return {error: `[!] ${USAGE}`};
+	}
+	return parseParam(key as RequestParamKey, values);
+}
+
+/**
+ * Defaults from the "request" object in settings.json, e.g.
+ * {"max_tokens": 4096, "temperature": 0.3, "stop": ["END"]}; invalid
+ * entries are reported and left out
+ */
+export function parseRequestSettings(settings: unknown): {params: Partial<RequestParams>; errors: string[]} {
+	const params: Partial<RequestParams> = {};
+	const errors: string[] = [];
+	if (!settings || typeof settings !== 'object') {
+		return {params, errors};
+	}
+
+	for (const [key, value] of Object.entries(settings)) {
+		if (!REQUEST_PARAM_KEYS.includes(key as RequestParamKey)) {
+			errors.push(`Unknown request setting "${key}"`);
+			continue;
+		}
+		const values = (Array.isArray(value) ? value : [value]).map(String);
+		const parsed = parseParam(key as RequestParamKey, values);
+		if ('error' in parsed) {
+			errors.push(parsed.error.replace(/^\[!\] /, ''));
+		} else {
+			Object.assign(params, parsed.params);
+		}
+	}
+	return {params, errors};
+}
+
+// ============================================================================
+// FORMATTING
+// ============================================================================
+
+/**
+ * "max_tokens 8192 · temperature 0.2 · stop none"
+ */
+export function formatRequestParams(params: RequestParams): string {
+	const stop =
+		params.stopSequences.length > 0
+			? params.stopSequences.map(sequence => JSON.stringify(sequence)).join(' ')
+			: 'none';
+	return `max_tokens ${params.maxTokens} · temperature ${params.temperature} · stop ${stop}`;
+}
@@ -0,0 +1,112 @@
+/**
+ * Request Timing
+ *
+ * Purpose: Summarize the engine's timing events for the status bar and logs, and the request in flight for the input footer
+ * Exports: formatDuration(), getTokensPerSecond(), formatRequestTiming(), startLiveTiming(), estimateTokens(), formatLiveTiming(), LiveTiming, STALL_MS
+ * Related: app.tsx (onTiming), MainLayout.tsx (status bar), floyd-store.ts (response time metrics)
+ */
+
+import type {TimingEvent} from 'floyd-agent-core';
+
+export type RequestCompleteEvent = Extract<TimingEvent, {type: 'request_complete'}>;
+
+// ============================================================================
+// FORMATTING
+// ============================================================================
+
+/**
+ * "850ms", "1.2s", "2m 05s"
+ */
+export function formatDuration(ms: number): string {
+	if (ms < 1000) {
+		return `${Math.round(ms)}ms`;
+	}
+	if (ms < 60_000) {
+		return `${(ms / 1000).toFixed(1)}s`;
+	}
+	const minutes = Math.floor(ms / 60_000);
+	const seconds = Math.round((ms % 60_000) / 1000);
+	return `${minutes}m ${String(seconds).padStart(2, '0')}s`;
+}
+
+/**
+ * Generation speed after the first token (null when unknown)
+ *
+ * Measured from the first token on, so network and queue time before the
+ * model starts answering don't drag the rate down.
+ */
+export function getTokensPerSecond(event: RequestCompleteEvent): number | null {
+	if (!event.outputTokens || event.firstTokenMs === undefined) {
+		return null;
+	}
+	const generationMs = event.durationMs - event.firstTokenMs;
+	return generationMs > 0 ? event.outputTokens / (generationMs / 1000) : null;
+}
+
+/**
+ * Status bar summary, e.g. "first token 1.2s · 4.8s · 42 tok/s"
+ */
+export function formatRequestTiming(event: RequestCompleteEvent): string {
+	const parts = [
+		event.firstTokenMs !== undefined ? `first token ${formatDuration(event.firstTokenMs)}` : 'no output',
+		formatDuration(event.durationMs),
+	];
+	const rate = getTokensPerSecond(event);
+	if (rate !== null) {
+		parts.push(`${Math.round(rate)} tok/s`);
+	}
+	return parts.join(' · ');
+}
+
+// ============================================================================
+// LIVE TIMING
+// ============================================================================
+
+/** Silence after which a streaming response is shown as stalled */
+export const STALL_MS = 10_000;
+
+/**
+ * The request in flight, fed by request_sent, first_token and streamed text
+ */
+export interface LiveTiming {
+	sentAt: number;
+	firstTokenAt?: number;
+	/** Last time text arrived (first token included) */
+	lastTokenAt?: number;
+	/** Characters of text streamed so far */
+	chars: number;
+}
+
+export function startLiveTiming(sentAt: number): LiveTiming {
+	return {sentAt, chars: 0};
+}
+
+/**
+ * Rough token count of streamed text; usage only arrives with the last chunk
+ */
+export function estimateTokens(chars: number): number {
+	return Math.round(chars / 4);
+}
+
+/**
+ * Footer summary while streaming, e.g. "first token 1.2s · 4.8s · ~42 tok/s",
+ * "waiting 3.0s" before the first token, "… · stalled 12.0s" when text stops
+ */
+export function formatLiveTiming(live: LiveTiming, now: number = Date.now()): string {
+	const elapsed = formatDuration(now - live.sentAt);
+	if (live.firstTokenAt === undefined) {
+		return `waiting ${elapsed}`;
+	}
+
+	const parts = [`first token ${formatDuration(live.firstTokenAt - live.sentAt)}`, elapsed];
+	const generationMs = now - live.firstTokenAt;
+	const tokens = estimateTokens(live.chars);
+	if (tokens > 0 && generationMs > 0) {
+		parts.push(`~${Math.round(tokens / (generationMs / 1000))} tok/s`);
+	}
+	const silentMs = now - (live.lastTokenAt ?? live.firstTokenAt);
+	if (silentMs >= STALL_MS) {
+		parts.push(`stalled ${formatDuration(silentMs)}`);
+	}
+	return parts.join(' · ');
+}
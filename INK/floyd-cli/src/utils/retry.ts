@@ -0,0 +1,43 @@
+/**
+ * Retry
+ *
+ * Purpose: Parse /retry arguments into the settings that change for the re-sent message
+ * Exports: parseRetryArgs(), RetryArgs
+ * Related: /retry in commands/app-commands.ts, app.tsx (rewinds the engine and re-sends), AgentEngine.rewindLastTurn()
+ */
+
+import type {RequestOverrides} from 'floyd-agent-core';
+
+export type RetryArgs = {overrides: RequestOverrides} | {error: string};
+
+const USAGE = 'Usage: /retry [--temp <0-2>] [--model <name>]';
+
+/**
+ * "/retry --temp 0.2 --model glm-4-plus" → {overrides: {temperature: 0.2, model: 'glm-4-plus'}}
+ */
+export function parseRetryArgs(args: string[]): RetryArgs {
+	const overrides: RequestOverrides = {};
+
+	for (let i = 0; i < args.length; i++) {
+		const arg = args[i];
+		const value = args[i + 1];
+		if (arg === '--temp' || arg === '--temperature') {
+			const temperature = Number(value);
+			if (value === undefined || value === '' || !Number.isFinite(temperature) || temperature < 0 || temperature > 2) {
+				return {error: `[!] --temp takes a number from 0 to 2. ${USAGE}`};
+			}
+			overrides.temperature = temperature;
+			i++;
+		} else if (arg === '--model') {
+			if (!value || value.startsWith('--')) {
+				return {error: `[!] --model takes a model name. ${USAGE}`};
+			}
+			overrides.model = value;
+			i++;
+		} else {
+			return {error: `[!] Unknown option "${arg}". ${USAGE}`};
+		}
+	}
+
+	return {overrides};
+}
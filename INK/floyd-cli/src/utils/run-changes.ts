@@ -0,0 +1,138 @@
+/**
+ * Run Changes
+ *
+ * Purpose: Work out which files a run created, modified or deleted by comparing git working-tree snapshots taken before and after it
+ * Exports: snapshotWorkingTree(), diffSnapshots(), summarizeRunChanges(), WorkingTreeSnapshot
+ * Related: app.tsx (diffstat after each run), floyd-agent-core/utils diffstat (formatting, progress.md)
+ */
+
+import {execa} from 'execa';
+import {readFile} from 'node:fs/promises';
+import {join} from 'node:path';
+import {formatDiffStat, logRunChanges, type FileChange, type FileChangeType} from 'floyd-agent-core/utils';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Uncommitted state of one file relative to HEAD
+ */
+export interface WorkingTreeFile {
+	type: FileChangeType;
+	added: number;
+	removed: number;
+}
+
+/**
+ * Uncommitted changes of a working tree, keyed by repository-relative path
+ */
+export type WorkingTreeSnapshot = Map<string, WorkingTreeFile>;
+
+// ============================================================================
+// SNAPSHOTS
+// ============================================================================
+
+async function git(cwd: string, args: string[]): Promise<string | null> {
+	const result = await execa('git', args, {cwd, reject: false, stdin: 'ignore'});
+	return result.exitCode === 0 ? result.stdout : null;
+}
+
+async function countLines(filePath: string): Promise<number> {
+	try {
+		const content = await readFile(filePath, 'utf8');
+		if (content.length === 0) return 0;
+		return content.split('\n').length - (content.endsWith('\n') ? 1 : 0);
+	} catch {
+		return 0;
+	}
+}
+
+/**
+ * Uncommitted changes of the repository at cwd, or null outside a git
+ * repository (or one without commits)
+ */
+export async function snapshotWorkingTree(cwd: string = process.cwd()): Promise<WorkingTreeSnapshot | null> {
+	const root = await git(cwd, ['rev-parse', '--show-toplevel']);
+	const numstat = root ? await git(root, ['diff', '--numstat', 'HEAD']) : null;
+	const nameStatus = root ? await git(root, ['diff', '--name-status', '--no-renames', 'HEAD']) : null;
+	const untracked = root ? await git(root, ['ls-files', '--others', '--exclude-standard']) : null;
+	if (!root || numstat === null || nameStatus === null || untracked === null) {
+		return null;
+	}
+
+	const types = new Map<string, FileChangeType>();
+	for (const line of nameStatus.split('\n').filter(Boolean)) {
+		const [status, path] = line.split('\t');
+		if (path) types.set(path, status === 'A' ? 'created' : status === 'D' ? 'deleted' : 'modified');
+	}
+
+	const snapshot: WorkingTreeSnapshot = new Map();
+	for (const line of numstat.split('\n').filter(Boolean)) {
+		const [added, removed, path] = line.split('\t');
+		if (!path) continue;
+		// Binary files show "-" for both counts
+		snapshot.set(path, {
+			type: types.get(path) ?? 'modified',
+			added: Number.parseInt(added!, 10) || 0,
+			removed: Number.parseInt(removed!, 10) || 0,
+		});
+	}
+	for (const path of untracked.split('\n').filter(Boolean)) {
+		snapshot.set(path, {type: 'created', added: await countLines(join(root, path)), removed: 0});
+	}
+
+	return snapshot;
+}
+
+/**
+ * Files whose uncommitted state changed between two snapshots, with the
+ * lines added and removed in between
+ */
+export function diffSnapshots(before: WorkingTreeSnapshot, after: WorkingTreeSnapshot): FileChange[] {
+	const changes: FileChange[] = [];
+	const paths = new Set([...before.keys(), ...after.keys()]);
+
+	for (const path of [...paths].sort()) {
+		const old = before.get(path) ?? {type: 'modified' as const, added: 0, removed: 0};
+		const now = after.get(path);
+		if (now && now.type === old.type && now.added === old.added && now.removed === old.removed) {
+			continue;
+		}
+
+		// A file back to its HEAD state had its earlier changes undone
+		const current = now ?? {type: 'modified' as const, added: 0, removed: 0};
+		const addedDelta = current.added - old.added;
+		const removedDelta = current.removed - old.removed;
+		changes.push({
+			path,
+			type: now ? current.type : 'modified',
+			added: Math.max(0, addedDelta) + Math.max(0, -removedDelta),
+			removed: Math.max(0, removedDelta) + Math.max(0, -addedDelta),
+		});
+	}
+
+	return changes;
+}
+
+/**
+ * Compare against the snapshot taken before a run, log the run's changes to
+ * .floyd/progress.md and return the diffstat to show (null when nothing changed)
+ *
+ * @param task - The request the run worked on
+ */
+export async function summarizeRunChanges(
+	before: WorkingTreeSnapshot | null,
+	task: string,
+	cwd: string = process.cwd(),
+): Promise<string | null> {
+	if (!before) return null;
+	const after = await snapshotWorkingTree(cwd);
+	if (!after) return null;
+
+	const changes = diffSnapshots(before, after);
+	if (changes.length === 0) return null;
+
+	logRunChanges(task, changes, cwd);
+	return ['Changes this run:', ...formatDiffStat(changes)].join('\n');
+}
@@ -0,0 +1,76 @@
+/**
+ * Session Warmer
+ *
+ * Purpose: Keep one initialized spare session ready so "New Session" is instant
+ * Exports: SessionWarmer class
+ * Related: app.tsx (new-task / reset-session palette commands)
+ *
+ * The expensive pieces (MCP servers, session store, permission manager,
+ * project config) are created once and shared; the factory only builds a
+ * fresh engine on top of them and initializes its session. The next spare
+ * starts warming in the background as soon as one is taken.
+ */
+
+import {getLogger} from './logger.js';
+
+// ============================================================================
+// SESSION WARMER
+// ============================================================================
+
+export class SessionWarmer<T> {
+	private spare: Promise<T> | null = null;
+	private disposed = false;
+
+	constructor(private readonly create: () => Promise<T>) {}
+
+	/**
+	 * Start preparing a spare in the background (no-op if one is pending)
+	 */
+	warm(): void {
+		if (this.spare || this.disposed) {
+			return;
+		}
+
+		const spare = this.create();
+		this.spare = spare;
+		// A failed spare is discarded; take() will build one on demand
+		spare.catch(error => {
+			getLogger().warn('Failed to pre-warm spare session', {error: String(error)});
+			if (this.spare === spare) {
+				this.spare = null;
+			}
+		});
+	}
+
+	/**
+	 * Whether a spare is being prepared or ready
+	 */
+	hasSpare(): boolean {
+		return this.spare !== null;
+	}
+
+	/**
+	 * Hand out the spare (or build one if none is available) and start
+	 * warming the next
+	 */
+	async take(): Promise<T> {
+		const spare = this.spare;
+		this.spare = null;
+
+		// If the pre-warmed spare failed, build one directly
+		const session = spare
+			? await spare.catch(() => this.create())
+			: await this.create();
+
+		this.warm();
+		return session;
+	}
+
+	/**
+	 * Stop warming new spares
+	 */
+	dispose(): void {
+		this.disposed = true;
+		this.spare = null;
+	}
+}
@@ -0,0 +1,52 @@
+/**
+ * Stdin Filter
+ *
+ * Purpose: A stdin stand-in for Ink that sees terminal input first, so sequences Ink does not understand (mouse reports, bracketed paste) can be taken out or rewritten
+ * Exports: createFilteredStdin(), FilteredStdin
+ * Related: utils/mouse-terminal.ts, utils/bracketed-paste.ts, cli.tsx
+ */
+
+import {PassThrough} from 'node:stream';
+
+export interface FilteredStdin {
+	/** Stream to render Ink with */
+	stdin: NodeJS.ReadStream;
+	/** Stop reading from the real input */
+	dispose: () => void;
+}
+
+/**
+ * Forward input to a new stream after passing each chunk through `filter`
+ * (return '' to drop a chunk). Raw mode and ref/unref go to the real input.
+ */
+export function createFilteredStdin(input: NodeJS.ReadStream, filter: (data: string) => string): FilteredStdin {
+	const stdin = new PassThrough() as PassThrough & Partial<NodeJS.ReadStream>;
+	Object.assign(stdin, {
+		isTTY: input.isTTY,
+		setRawMode: (mode: boolean) => {
+			input.setRawMode?.(mode);
+			return stdin;
+		},
+		ref: () => {
+			input.ref();
+			return stdin;
+		},
+		unref: () => {
+			input.unref();
+			return stdin;
+		},
+	});
+
+	const onData = (data: Buffer | string) => {
+		const rest = filter(data.toString());
+		if (rest) stdin.write(rest);
+	};
+	input.on('data', onData);
+
+	return {
+		stdin: stdin as unknown as NodeJS.ReadStream,
+		dispose: () => {
+			input.off('data', onData);
+		},
+	};
+}
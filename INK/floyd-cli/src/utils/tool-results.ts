@@ -0,0 +1,107 @@
+/**
+ * Tool Results
+ *
+ * Purpose: Number tool calls across the conversation and window their output for collapsible blocks
+ * Exports: numberToolCalls(), listToolCalls(), getToolResultView(), clampToolScroll(), TOOL_PREVIEW_LINES, TOOL_EXPANDED_LINES
+ * Related: ui/components/ToolResultBlock.tsx, ui/layouts/MainLayout.tsx (focus/scroll keys), /expand in app.tsx
+ */
+
+import type {ChatMessage} from '../ui/layouts/MainLayout.js';
+
+// ============================================================================
+// CONSTANTS
+// ============================================================================
+
+/**
+ * Lines of output shown while a block is collapsed
+ */
+export const TOOL_PREVIEW_LINES = 3;
+
+/**
+ * Lines of output shown at once while a block is expanded (the rest scrolls)
+ */
+export const TOOL_EXPANDED_LINES = 15;
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+type ChatToolCall = NonNullable<ChatMessage['toolCalls']>[number];
+
+/**
+ * A tool call with its 1-based position in the conversation (as used by /expand)
+ */
+export interface NumberedToolCall extends ChatToolCall {
+	number: number;
+}
+
+/**
+ * The visible part of a tool result
+ */
+export interface ToolResultView {
+	/** Lines to show */
+	lines: string[];
+
+	/** Lines scrolled out above the window */
+	hiddenAbove: number;
+
+	/** Lines below the window (or cut from the preview) */
+	hiddenBelow: number;
+
+	/** Total lines in the output */
+	totalLines: number;
+}
+
+// ============================================================================
+// FUNCTIONS
+// ============================================================================
+
+/**
+ * All tool calls in the conversation, oldest first, numbered from 1
+ */
+export function listToolCalls(messages: ChatMessage[]): NumberedToolCall[] {
+	return messages
+		.flatMap(message => message.toolCalls ?? [])
+		.map((call, i) => ({...call, number: i + 1}));
+}
+
+/**
+ * Set each tool call's number (its position in the conversation)
+ * Messages without tool calls are returned unchanged
+ */
+export function numberToolCalls(messages: ChatMessage[]): ChatMessage[] {
+	let count = 0;
+	return messages.map(message =>
+		message.toolCalls?.length
+			? {...message, toolCalls: message.toolCalls.map(call => ({...call, number: ++count}))}
+			: message,
+	);
+}
+
+/**
+ * Keep a scroll offset inside the output
+ */
+export function clampToolScroll(offset: number, totalLines: number, windowLines = TOOL_EXPANDED_LINES): number {
+	return Math.max(0, Math.min(offset, totalLines - windowLines));
+}
+
+/**
+ * Lines to show for a tool result: a short preview when collapsed, a
+ * scrollable window when expanded
+ */
+export function getToolResultView(
+	output: string,
+	{expanded, scrollOffset = 0}: {expanded: boolean; scrollOffset?: number},
+): ToolResultView {
+	const all = output.replace(/\n+$/, '').split('\n');
+	const windowLines = expanded ? TOOL_EXPANDED_LINES : TOOL_PREVIEW_LINES;
+	const start = expanded ? clampToolScroll(scrollOffset, all.length, windowLines) : 0;
+	const lines = all.slice(start, start + windowLines);
+
+	return {
+		lines,
+		hiddenAbove: start,
+		hiddenBelow: all.length - start - lines.length,
+		totalLines: all.length,
+	};
+}
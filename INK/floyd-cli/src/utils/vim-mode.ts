@@ -0,0 +1,379 @@
+/**
+ * Vim Mode
+ *
+ * Purpose: Modal (vi-style) editing for the prompt input: insert, normal and visual modes with the common motions and operators
+ * Exports: isVimMode(), VIM_MODE, vimKeypress(), initialVimState(), VIM_KEYMAP, VimState, VimKey
+ * Related: ui/components/VimInput.tsx, ui/layouts/MainLayout.tsx (input area, help overlay)
+ *
+ * Enabled with FLOYD_VIM=1. The input starts in insert mode, so typing works
+ * as usual until Esc is pressed.
+ */
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+export type VimModeName = 'insert' | 'normal' | 'visual';
+
+export interface VimState {
+	mode: VimModeName;
+	/** Cursor offset in the value */
+	cursor: number;
+	/** Other end of the selection in visual mode */
+	anchor: number;
+	/** Keys typed so far of an unfinished command ("2", "d", "r") */
+	pending: string;
+	/** Last yanked or deleted text */
+	register: string;
+	/** Value and cursor before the last change, for u */
+	undo: {value: string; cursor: number} | null;
+}
+
+/**
+ * The parts of Ink's key object the editor looks at
+ */
+export interface VimKey {
+	escape?: boolean;
+	return?: boolean;
+	backspace?: boolean;
+	delete?: boolean;
+	leftArrow?: boolean;
+	rightArrow?: boolean;
+	upArrow?: boolean;
+	downArrow?: boolean;
+	tab?: boolean;
+	ctrl?: boolean;
+	meta?: boolean;
+}
+
+export interface VimResult {
+	value: string;
+	state: VimState;
+	/** Enter was pressed */
+	submit?: boolean;
+	/** False for keys the editor leaves to other handlers */
+	handled: boolean;
+}
+
+// ============================================================================
+// CONFIGURATION
+// ============================================================================
+
+/**
+ * Check whether vim mode is requested in the environment
+ */
+export function isVimMode(env: NodeJS.ProcessEnv = process.env): boolean {
+	const value = env['FLOYD_VIM']?.toLowerCase();
+	return value === '1' || value === 'true' || value === 'yes';
+}
+
+/**
+ * Vim mode flag, resolved once at startup
+ */
+export const VIM_MODE = isVimMode();
+
+/**
+ * Normal and visual mode bindings, as listed in the help overlay
+ */
+export const VIM_KEYMAP: Array<{keys: string; description: string}> = [
+	{keys: 'Esc', description: 'Normal mode (from insert or visual)'},
+	{keys: 'i a I A', description: 'Insert before/after cursor, at start/end'},
+	{keys: 'h l w b e 0 ^ $', description: 'Move (with a count, e.g. 3w)'},
+	{keys: 'x X s r~', description: 'Delete/substitute/replace/toggle case of a char'},
+	{keys: 'd c y + motion', description: 'Delete/change/yank (dd cc yy: whole input)'},
+	{keys: 'D C', description: 'Delete/change to the end'},
+	{keys: 'p P', description: 'Put after/before the cursor'},
+	{keys: 'v', description: 'Visual selection (y d c x act on it)'},
+	{keys: 'u', description: 'Undo the last change'},
+	{keys: 'Enter', description: 'Send the message (any mode)'},
+];
+
+// ============================================================================
+// MOTIONS
+// ============================================================================
+
+type CharClass = 'space' | 'word' | 'punct';
+
+function charClass(char: string | undefined): CharClass {
+	if (char === undefined || /\s/.test(char)) return 'space';
+	return /\w/.test(char) ? 'word' : 'punct';
+}
+
+function nextWordStart(value: string, from: number): number {
+	let index = from;
+	const start = charClass(value[index]);
+	if (start !== 'space') {
+		while (index < value.length && charClass(value[index]) === start) index++;
+	}
+	while (index < value.length && charClass(value[index]) === 'space') index++;
+	return index;
+}
+
+function previousWordStart(value: string, from: number): number {
+	let index = from - 1;
+	while (index > 0 && charClass(value[index]) === 'space') index--;
+	const cls = charClass(value[index]);
+	while (index > 0 && charClass(value[index - 1]) === cls) index--;
+	return Math.max(0, index);
+}
+
+function wordEnd(value: string, from: number): number {
+	let index = from + 1;
+	while (index < value.length && charClass(value[index]) === 'space') index++;
+	const cls = charClass(value[index]);
+	while (index + 1 < value.length && charClass(value[index + 1]) === cls) index++;
+	return Math.min(index, Math.max(0, value.length - 1));
+}
+
+/**
+ * Target of a motion key, and whether an operator on it includes the
+ * character at the target; null for keys that are not motions
+ */
+function motion(value: string, cursor: number, key: string, count: number): {target: number; inclusive: boolean} | null {
+	let target = cursor;
+	switch (key) {
+		case 'h':
+			return {target: Math.max(0, cursor - count), inclusive: false};
+		case 'l':
+			return {target: Math.min(value.length, cursor + count), inclusive: false};
+		case '0':
+			return {target: 0, inclusive: false};
+		case '^':
+			return {target: value.length - value.trimStart().length, inclusive: false};
+		case '$':
+			return {target: Math.max(0, value.length - 1), inclusive: true};
+		case 'w':
+			for (let i = 0; i < count; i++) target = nextWordStart(value, target);
+			return {target, inclusive: false};
+		case 'b':
+			for (let i = 0; i < count; i++) target = previousWordStart(value, target);
+			return {target, inclusive: false};
+		case 'e':
+			for (let i = 0; i < count; i++) target = wordEnd(value, target);
+			return {target, inclusive: true};
+		default:
+			return null;
+	}
+}
+
+// ============================================================================
+// EDITING
+// ============================================================================
+
+/**
+ * Editor state for a value: insert mode with the cursor at the end
+ */
+export function initialVimState(value = ''): VimState {
+	return {mode: 'insert', cursor: value.length, anchor: 0, pending: '', register: '', undo: null};
+}
+
+/**
+ * Normal mode keeps the cursor on a character
+ */
+function clampNormal(value: string, cursor: number): number {
+	return Math.max(0, Math.min(cursor, value.length - 1));
+}
+
+/**
+ * Apply one keypress to the value and editor state
+ */
+export function vimKeypress(value: string, state: VimState, input: string, key: VimKey): VimResult {
+	// Ctrl/Meta shortcuts, Tab (completion) and ↑↓ (history) belong to the layout
+	if (key.ctrl || key.meta || key.tab || key.upArrow || key.downArrow) {
+		return {value, state, handled: false};
+	}
+	if (key.return) {
+		return {value, state: {...state, mode: 'insert', pending: ''}, submit: true, handled: true};
+	}
+	return state.mode === 'insert' ? insertKey(value, state, input, key) : commandKey(value, state, input, key);
+}
+
+function insertKey(value: string, state: VimState, input: string, key: VimKey): VimResult {
+	const {cursor} = state;
+	if (key.escape) {
+		return {value, state: {...state, mode: 'normal', cursor: clampNormal(value, cursor - 1)}, handled: true};
+	}
+	if (key.backspace || key.delete) {
+		if (cursor === 0) return {value, state, handled: true};
+		return {
+			value: value.slice(0, cursor - 1) + value.slice(cursor),
+			state: {...state, cursor: cursor - 1},
+			handled: true,
+		};
+	}
+	if (key.leftArrow || key.rightArrow) {
+		const next = Math.max(0, Math.min(value.length, cursor + (key.leftArrow ? -1 : 1)));
+		return {value, state: {...state, cursor: next}, handled: true};
+	}
+	if (!input) {
+		return {value, state, handled: false};
+	}
+	return {
+		value: value.slice(0, cursor) + input + value.slice(cursor),
+		state: {...state, cursor: cursor + input.length},
+		handled: true,
+	};
+}
+
+function commandKey(value: string, state: VimState, input: string, key: VimKey): VimResult {
+	const visual = state.mode === 'visual';
+	const toNormal = (cursor: number): VimState => ({...state, mode: 'normal', pending: '', cursor: clampNormal(value, cursor)});
+
+	if (key.escape) {
+		return {value, state: toNormal(state.cursor), handled: true};
+	}
+
+	// Arrows and backspace move like h/l
+	const typed = key.leftArrow || key.backspace || key.delete ? 'h' : key.rightArrow ? 'l' : input;
+	if (!typed) {
+		return {value, state, handled: false};
+	}
+
+	const pending = state.pending + typed;
+
+	// r<char> replaces the character under the cursor
+	if (state.pending === 'r') {
+		const changed = value ? value.slice(0, state.cursor) + typed + value.slice(state.cursor + 1) : value;
+		return {value: changed, state: {...toNormal(state.cursor), undo: {value, cursor: state.cursor}}, handled: true};
+	}
+
+	// [count][operator][count]key; a 0 that does not continue a count is the
+	// motion. In visual mode d/c/y act at once, so there is no operator part.
+	const [, countBefore = '', operator = '', countAfter = '', rest = ''] = pending.match(
+		visual ? /^([1-9]\d*)?()()(.*)$/ : /^([1-9]\d*)?([dcy])?([1-9]\d*)?(.*)$/,
+	)!;
+	if (!rest) {
+		return {value, state: {...state, pending}, handled: true};
+	}
+	const count = Math.max(1, Number(countBefore || 1) * Number(countAfter || 1));
+
+	// Operator applied to a range [from, to)
+	const apply = (op: string, from: number, to: number): VimResult => {
+		const start = Math.max(0, Math.min(from, to));
+		const end = Math.min(value.length, Math.max(from, to));
+		const text = value.slice(start, end);
+		if (op === 'y') {
+			return {value, state: {...toNormal(start), register: text}, handled: true};
+		}
+		const changed = value.slice(0, start) + value.slice(end);
+		const undo = {value, cursor: state.cursor};
+		if (op === 'c') {
+			return {
+				value: changed,
+				state: {...state, mode: 'insert', pending: '', cursor: start, register: text, undo},
+				handled: true,
+			};
+		}
+		return {
+			value: changed,
+			state: {...state, mode: 'normal', pending: '', cursor: clampNormal(changed, start), register: text, undo},
+			handled: true,
+		};
+	};
+
+	// Visual mode: motions move the cursor, operators act on the selection
+	if (visual) {
+		const from = Math.min(state.anchor, state.cursor);
+		const to = Math.max(state.anchor, state.cursor) + 1;
+		if (rest === 'y' || rest === 'd' || rest === 'x' || rest === 'c' || rest === 's') {
+			return apply(rest === 'x' ? 'd' : rest === 's' ? 'c' : rest, from, to);
+		}
+		if (rest === 'v') {
+			return {value, state: toNormal(state.cursor), handled: true};
+		}
+		const moved = motion(value, state.cursor, rest, count);
+		if (moved) {
+			return {value, state: {...state, pending: '', cursor: clampNormal(value, moved.target)}, handled: true};
+		}
+		return {value, state: {...state, pending: ''}, handled: true};
+	}
+
+	if (operator) {
+		// dd, cc, yy: the whole input
+		if (rest === operator) {
+			return apply(operator, 0, value.length);
+		}
+		// cw changes to the end of the word, like ce
+		const moved = motion(value, state.cursor, operator === 'c' && rest === 'w' ? 'e' : rest, count);
+		if (!moved) {
+			return {value, state: {...state, pending: ''}, handled: true};
+		}
+		const inclusive = moved.inclusive || (operator === 'c' && rest === 'w');
+		return moved.target >= state.cursor
+			? apply(operator, state.cursor, moved.target + (inclusive ? 1 : 0))
+			: apply(operator, moved.target, state.cursor);
+	}
+
+	const cursor = state.cursor;
+	// Undo goes back to before the insert
+	const insertAt = (at: number): VimResult => ({
+		value,
+		state: {...state, mode: 'insert', pending: '', cursor: at, undo: {value, cursor}},
+		handled: true,
+	});
+
+	switch (rest) {
+		case 'i':
+			return insertAt(cursor);
+		case 'a':
+			return insertAt(Math.min(value.length, cursor + 1));
+		case 'I':
+			return insertAt(value.length - value.trimStart().length);
+		case 'A':
+			return insertAt(value.length);
+		case 'v':
+			return {value, state: {...state, mode: 'visual', pending: '', anchor: cursor}, handled: true};
+		case 'x':
+			return value ? apply('d', cursor, cursor + count) : {value, state: toNormal(cursor), handled: true};
+		case 'X':
+			return apply('d', cursor - count, cursor);
+		case 's':
+			return apply('c', cursor, cursor + count);
+		case 'D':
+			return apply('d', cursor, value.length);
+		case 'C':
+			return apply('c', cursor, value.length);
+		case 'r':
+			return {value, state: {...state, pending: 'r'}, handled: true};
+		case '~': {
+			const char = value[cursor];
+			if (char === undefined) return {value, state: toNormal(cursor), handled: true};
+			const toggled = char === char.toUpperCase() ? char.toLowerCase() : char.toUpperCase();
+			const changed = value.slice(0, cursor) + toggled + value.slice(cursor + 1);
+			return {
+				value: changed,
+				state: {...state, pending: '', cursor: Math.min(cursor + 1, changed.length - 1), undo: {value, cursor}},
+				handled: true,
+			};
+		}
+		case 'p':
+		case 'P': {
+			if (!state.register) return {value, state: toNormal(cursor), handled: true};
+			const at = rest === 'p' && value ? cursor + 1 : cursor;
+			const text = state.register.repeat(count);
+			const changed = value.slice(0, at) + text + value.slice(at);
+			return {
+				value: changed,
+				state: {...state, pending: '', cursor: at + text.length - 1, undo: {value, cursor}},
+				handled: true,
+			};
+		}
+		case 'u': {
+			if (!state.undo) return {value, state: toNormal(cursor), handled: true};
+			const {undo} = state;
+			return {
+				value: undo.value,
+				state: {...state, pending: '', cursor: clampNormal(undo.value, undo.cursor), undo: {value, cursor}},
+				handled: true,
+			};
+		}
+		default: {
+			const moved = motion(value, cursor, rest, count);
+			return {
+				value,
+				state: {...state, pending: '', cursor: moved ? clampNormal(value, moved.target) : cursor},
+				handled: true,
+			};
+		}
+	}
+}
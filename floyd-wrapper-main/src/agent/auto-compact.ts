@@ -0,0 +1,177 @@
+/**
+ * Auto Compact - Floyd Wrapper
+ *
+ * Keeps long conversations under the model's context window. Before each
+ * model call the engine estimates the prompt size; past the threshold
+ * (FLOYD_AUTO_COMPACT_TOKENS) the older turns are summarized by the model
+ * and replaced with the summary, keeping the system prompt and the most
+ * recent turns verbatim. The full messages are archived in the cache's
+ * vault tier so nothing is lost.
+ */
+
+import type { FloydMessage, StreamEvent } from '../types.js';
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Default prompt size (estimated tokens) that triggers compaction
+ */
+export const DEFAULT_AUTO_COMPACT_TOKENS = 120_000;
+
+/**
+ * Messages at the end of the conversation that are never summarized
+ */
+export const COMPACT_KEEP_RECENT = 10;
+
+/**
+ * Rough chars-per-token ratio used for prompt size estimates
+ */
+const CHARS_PER_TOKEN = 4;
+
+/**
+ * Longest slice of a single message sent to the summarizer
+ */
+const MAX_MESSAGE_CHARS = 4000;
+
+const SUMMARY_INSTRUCTIONS = `You compress coding-agent conversations. Summarize the conversation below so the agent can continue the task without it.
+Keep: the user's goals and constraints, decisions made and why, files read or changed (with paths), commands run and their outcomes, errors still open, and the next steps.
+Drop: pleasantries, repeated tool output, and file contents that can be read again.
+Answer with the summary only, as concise bullet points under short headings.`;
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Result of a compaction
+ */
+export interface CompactionResult {
+  /** The conversation with older turns replaced by the summary */
+  messages: FloydMessage[];
+  summary: string;
+  /** Messages that were summarized */
+  compactedCount: number;
+  tokensBefore: number;
+  tokensAfter: number;
+}
+
+/**
+ * Streams a chat completion (the engine's model client)
+ */
+export type SummaryClient = {
+  streamChat(options: { messages: FloydMessage[]; abortSignal?: AbortSignal }): AsyncGenerator<StreamEvent>;
+};
+
+// ============================================================================
+// Estimation
+// ============================================================================
+
+/**
+ * Estimated prompt tokens for a list of messages
+ */
+export function estimateMessageTokens(messages: FloydMessage[]): number {
+  return Math.ceil(messages.reduce((sum, message) => sum + message.content.length, 0) / CHARS_PER_TOKEN);
+}
+
+/**
+ * Split a conversation into the leading system prompt, the older messages to
+ * summarize and the recent ones to keep. The kept part starts at a user
+ * message so no tool result is separated from the call that produced it.
+ *
+ * @returns null when there is nothing worth compacting
+ */
+export function splitForCompaction(
+  messages: FloydMessage[],
+  keepRecent: number = COMPACT_KEEP_RECENT,
+): { head: FloydMessage[]; older: FloydMessage[]; recent: FloydMessage[] } | null {
+  const headLength = messages[0]?.role === 'system' ? 1 : 0;
+  let boundary = Math.max(messages.length - keepRecent, headLength);
+  while (boundary > headLength && messages[boundary]?.role !== 'user') {
+    boundary--;
+  }
+
+  // Fewer than two messages to summarize would not save anything
+  if (boundary - headLength < 2) {
+    return null;
+  }
+
+  return {
+    head: messages.slice(0, headLength),
+    older: messages.slice(headLength, boundary),
+    recent: messages.slice(boundary),
+  };
+}
+
+/**
+ * The request that asks the model for a summary of older messages
+ */
+export function buildSummaryRequest(older: FloydMessage[]): FloydMessage[] {
+  const transcript = older.map(message => {
+    const content = message.content.length > MAX_MESSAGE_CHARS
+      ? `${message.content.slice(0, MAX_MESSAGE_CHARS)}... [${message.content.length - MAX_MESSAGE_CHARS} more characters]`
+      : message.content;
+    return `[${message.role}${message.toolName ? ` ${message.toolName}` : ''}]\n${content}`;
+  }).join('\n\n');
+
+  const now = Date.now();
+  return [
+    { role: 'system', content: SUMMARY_INSTRUCTIONS, timestamp: now },
+    { role: 'user', content: `<conversation>\n${transcript}\n</conversation>`, timestamp: now },
+  ];
+}
+
+// ============================================================================
+// Compaction
+// ============================================================================
+
+/**
+ * Summarize the older part of a conversation with the model
+ *
+ * @returns The compacted conversation, or null when there was nothing to
+ *          compact or the model returned no summary (the history is kept)
+ */
+export async function compactConversation(
+  messages: FloydMessage[],
+  client: SummaryClient,
+  options: { keepRecent?: number; archiveNote?: string; abortSignal?: AbortSignal } = {},
+): Promise<CompactionResult | null> {
+  const split = splitForCompaction(messages, options.keepRecent);
+  if (!split) {
+    return null;
+  }
+
+  let summary = '';
+  for await (const event of client.streamChat({ messages: buildSummaryRequest(split.older), abortSignal: options.abortSignal })) {
+    if (event.type === 'token') {
+      summary += event.content;
+    } else if (event.type === 'error') {
+      throw new Error(event.error || event.content || 'Summary request failed');
+    }
+  }
+  summary = summary.trim();
+  if (!summary) {
+    return null;
+  }
+
+  const summaryMessage: FloydMessage = {
+    role: 'system',
+    content: [
+      `## Conversation Summary`,
+      `${split.older.length} earlier messages were compacted to save context.${options.archiveNote ? ` ${options.archiveNote}` : ''}`,
+      '',
+      summary,
+    ].join('\n'),
+    timestamp: split.older[split.older.length - 1].timestamp,
+  };
+  const compacted = [...split.head, summaryMessage, ...split.recent];
+
+  return {
+    messages: compacted,
+    summary,
+    compactedCount: split.older.length,
+    tokensBefore: estimateMessageTokens(messages),
+    tokensAfter: estimateMessageTokens(compacted),
+  };
+}
@@ -0,0 +1,325 @@
+/**
+ * Batch Runner - Floyd Wrapper
+ *
+ * Headless `floyd run <tasks.yaml>`: works through a list of goals, each in
+ * its own FLOYD process and session (piped mode), one at a time or with
+ * bounded parallelism. Every task's output is kept as a transcript and the
+ * run ends with a summary report in .floyd/batch/<timestamp>/.
+ *
+ *   - goal: Fix the failing date tests
+ *     cwd: packages/api
+ *     model: glm-4.7
+ *     tools: editor
+ *     budget: { tokens: 200000, cost: 1.5 }
+ *   - Update the README badges
+ *
+ * The file may also be JSON, and the list may sit under a `tasks` key.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import yaml from 'js-yaml';
+import { execa } from 'execa';
+import { z } from 'zod';
+import { getShutdownController } from '../interrupts/index.js';
+import type { RunBudget } from '../utils/config.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A goal in a task file
+ */
+export interface BatchTask {
+  /** 1-based position in the file */
+  index: number;
+  goal: string;
+  /** Absolute working directory */
+  cwd: string;
+  model?: string;
+  /** Tool profile the task's session is locked to (reader, editor, ...) */
+  tools?: string;
+  budget?: RunBudget;
+}
+
+export type BatchTaskStatus = 'done' | 'failed' | 'timeout';
+
+/**
+ * Outcome of one task
+ */
+export interface BatchTaskResult {
+  task: BatchTask;
+  status: BatchTaskStatus;
+  exitCode: number;
+  durationMs: number;
+  /** Transcript file, relative to the batch directory */
+  transcript: string;
+  /** Last non-empty line of output, for the summary */
+  lastLine: string;
+}
+
+/**
+ * Runs a task and returns its output
+ */
+export type BatchTaskExecutor = (
+  task: BatchTask
+) => Promise<{ exitCode: number; output: string; timedOut?: boolean }>;
+
+export interface BatchOptions {
+  /** Tasks run at the same time (default 1) */
+  parallel?: number;
+  /** Where transcripts and the summary go (default .floyd/batch/<timestamp>) */
+  outDir?: string;
+  /** Called as each task starts and finishes */
+  onTaskStart?: (task: BatchTask) => void;
+  onTaskEnd?: (result: BatchTaskResult) => void;
+}
+
+// ============================================================================
+// Task Files
+// ============================================================================
+
+const budgetSchema = z.union([
+  // A bare number is a cost cap in USD
+  z.number().positive(),
+  z.object({
+    turns: z.number().positive().optional(),
+    tokens: z.number().positive().optional(),
+    tool_calls: z.number().positive().optional(),
+    seconds: z.number().positive().optional(),
+    cost: z.number().positive().optional(),
+  }).strict(),
+]);
+
+const taskSchema = z.union([
+  z.string().min(1),
+  z.object({
+    goal: z.string().min(1),
+    cwd: z.string().optional(),
+    model: z.string().optional(),
+    tools: z.string().min(1).optional(),
+    budget: budgetSchema.optional(),
+  }).strict(),
+]);
+
+function toRunBudget(budget: z.infer<typeof budgetSchema>): RunBudget {
+  if (typeof budget === 'number') {
+    return { maxCostUsd: budget };
+  }
+  const result: RunBudget = {};
+  if (budget.turns) result.maxTurns = budget.turns;
+  if (budget.tokens) result.maxTokens = budget.tokens;
+  if (budget.tool_calls) result.maxToolCalls = budget.tool_calls;
+  if (budget.seconds) result.maxDurationMs = budget.seconds * 1000;
+  if (budget.cost) result.maxCostUsd = budget.cost;
+  return result;
+}
+
+/**
+ * Tasks in a YAML or JSON task file
+ *
+ * @param baseDir - Directory relative task cwds resolve against
+ * @throws Error naming the first invalid task
+ */
+export function parseTaskFile(source: string, baseDir: string): BatchTask[] {
+  const raw = yaml.load(source) as unknown;
+  const list = raw && typeof raw === 'object' && !Array.isArray(raw) && 'tasks' in raw
+    ? (raw as { tasks: unknown }).tasks
+    : raw;
+
+  if (!Array.isArray(list) || list.length === 0) {
+    throw new Error('task file must contain a non-empty list of tasks');
+  }
+
+  return list.map((item, i) => {
+    const result = taskSchema.safeParse(item);
+    if (!result.success) {
+      const issue = result.error.issues[0];
+      throw new Error(`task ${i + 1}: ${issue.path.join('.') || 'task'} ${issue.message}`);
+    }
+
+    const task = typeof result.data === 'string' ? { goal: result.data } : result.data;
+    return {
+      index: i + 1,
+      goal: task.goal.trim(),
+      cwd: path.resolve(baseDir, task.cwd ?? '.'),
+      model: task.model,
+      tools: task.tools,
+      budget: task.budget !== undefined ? toRunBudget(task.budget) : undefined,
+    };
+  });
+}
+
+/**
+ * Read a task file; relative cwds resolve against the file's directory
+ */
+export async function loadTaskFile(filePath: string): Promise<BatchTask[]> {
+  const source = await fs.readFile(filePath, 'utf-8');
+  return parseTaskFile(source, path.dirname(path.resolve(filePath)));
+}
+
+// ============================================================================
+// Execution
+// ============================================================================
+
+/**
+ * Environment that applies a task's model, tool profile and budget to a
+ * FLOYD process
+ */
+export function taskEnv(task: BatchTask): Record<string, string> {
+  const env: Record<string, string> = {};
+  if (task.model) env.FLOYD_GLM_MODEL = task.model;
+  if (task.tools) env.FLOYD_TOOL_PROFILE = task.tools;
+  if (task.budget?.maxTurns) env.FLOYD_MAX_TURNS = String(task.budget.maxTurns);
+  if (task.budget?.maxTokens) env.FLOYD_MAX_RUN_TOKENS = String(task.budget.maxTokens);
+  if (task.budget?.maxToolCalls) env.FLOYD_MAX_RUN_TOOL_CALLS = String(task.budget.maxToolCalls);
+  if (task.budget?.maxDurationMs) env.FLOYD_MAX_RUN_SECONDS = String(task.budget.maxDurationMs / 1000);
+  if (task.budget?.maxCostUsd) env.FLOYD_MAX_RUN_COST = String(task.budget.maxCostUsd);
+  return env;
+}
+
+/**
+ * Executor that pipes the goal into a separate FLOYD process
+ *
+ * @param mode - Execution mode of the task processes (default yolo; nobody is there to approve)
+ */
+export function processExecutor(mode: string = 'yolo'): BatchTaskExecutor {
+  const entry = process.argv[1];
+
+  return async (task) => {
+    const subprocess = execa(process.execPath, [entry, '--force', '--mode', mode], {
+      cwd: task.cwd,
+      input: `${task.goal}\n`,
+      env: { ...taskEnv(task), FLOYD_MODE: mode, FORCE_COLOR: '0' },
+      all: true,
+      reject: false,
+      // Budgets stop runs from the inside; this only catches hung processes
+      timeout: task.budget?.maxDurationMs ? task.budget.maxDurationMs * 2 : undefined,
+    });
+    getShutdownController().trackProcess(subprocess);
+
+    const result = await subprocess;
+    return { exitCode: result.exitCode ?? 1, output: result.all ?? '', timedOut: result.timedOut };
+  };
+}
+
+/**
+ * Directory for a batch started at the given time
+ */
+export function batchDir(cwd: string, startedAt: Date = new Date()): string {
+  const stamp = startedAt.toISOString().replace(/[:.]/g, '-').replace(/Z$/, '');
+  return path.join(cwd, '.floyd', 'batch', stamp);
+}
+
+function transcriptName(task: BatchTask): string {
+  const slug = task.goal.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-|-$/g, '').slice(0, 40);
+  return `${String(task.index).padStart(2, '0')}-${slug || 'task'}.md`;
+}
+
+function formatTranscript(task: BatchTask, output: string, result: Omit<BatchTaskResult, 'task' | 'transcript' | 'lastLine'>): string {
+  return [
+    `# Task ${task.index}: ${task.goal}`,
+    '',
+    `- Directory: ${task.cwd}`,
+    ...(task.model ? [`- Model: ${task.model}`] : []),
+    ...(task.tools ? [`- Tool profile: ${task.tools}`] : []),
+    `- Status: ${result.status} (exit ${result.exitCode})`,
+    `- Duration: ${(result.durationMs / 1000).toFixed(1)}s`,
+    '',
+    '```',
+    output.trimEnd(),
+    '```',
+    '',
+  ].join('\n');
+}
+
+/**
+ * Run tasks, at most `parallel` at a time, writing a transcript per task
+ * and summary.md / summary.json
+ *
+ * Results are in task order, whatever order the tasks finished in.
+ */
+export async function runBatch(
+  tasks: BatchTask[],
+  execute: BatchTaskExecutor,
+  options: BatchOptions = {}
+): Promise<{ dir: string; results: BatchTaskResult[] }> {
+  const dir = options.outDir ?? batchDir(process.cwd());
+  const parallel = Math.max(1, Math.floor(options.parallel ?? 1));
+  await fs.ensureDir(dir);
+
+  const results: BatchTaskResult[] = new Array(tasks.length);
+  let next = 0;
+
+  const worker = async (): Promise<void> => {
+    while (next < tasks.length) {
+      const i = next++;
+      const task = tasks[i];
+      options.onTaskStart?.(task);
+
+      const started = Date.now();
+      let outcome: Awaited<ReturnType<BatchTaskExecutor>>;
+      try {
+        outcome = await execute(task);
+      } catch (error) {
+        outcome = { exitCode: 1, output: error instanceof Error ? error.message : String(error) };
+      }
+
+      const status: BatchTaskStatus = outcome.timedOut ? 'timeout' : outcome.exitCode === 0 ? 'done' : 'failed';
+      const durationMs = Date.now() - started;
+      const transcript = transcriptName(task);
+      await fs.writeFile(
+        path.join(dir, transcript),
+        formatTranscript(task, outcome.output, { status, exitCode: outcome.exitCode, durationMs })
+      );
+
+      const lines = outcome.output.split('\n').map(line => line.trim()).filter(Boolean);
+      results[i] = { task, status, exitCode: outcome.exitCode, durationMs, transcript, lastLine: lines[lines.length - 1] ?? '' };
+      options.onTaskEnd?.(results[i]);
+    }
+  };
+
+  await Promise.all(Array.from({ length: Math.min(parallel, tasks.length) }, worker));
+
+  await fs.writeFile(path.join(dir, 'summary.md'), formatBatchSummary(results));
+  await fs.writeJson(path.join(dir, 'summary.json'), results.map(result => ({
+    index: result.task.index,
+    goal: result.task.goal,
+    cwd: result.task.cwd,
+    model: result.task.model,
+    status: result.status,
+    exitCode: result.exitCode,
+    durationMs: result.durationMs,
+    transcript: result.transcript,
+    lastLine: result.lastLine,
+  })), { spaces: 2 });
+
+  return { dir, results };
+}
+
+// ============================================================================
+// Report
+// ============================================================================
+
+/**
+ * Markdown summary of a batch
+ */
+export function formatBatchSummary(results: BatchTaskResult[]): string {
+  const done = results.filter(result => result.status === 'done').length;
+  const totalMs = results.reduce((sum, result) => sum + result.durationMs, 0);
+  const cell = (text: string) => text.replace(/\|/g, '\\|').replace(/\n/g, ' ');
+
+  return [
+    '# Batch Summary',
+    '',
+    `${done}/${results.length} tasks done, ${(totalMs / 1000).toFixed(1)}s of task time.`,
+    '',
+    '| # | Goal | Status | Time | Transcript |',
+    '|---|------|--------|------|------------|',
+    ...results.map(result =>
+      `| ${result.task.index} | ${cell(result.task.goal)} | ${result.status} | ${(result.durationMs / 1000).toFixed(1)}s | [${result.transcript}](${result.transcript}) |`
+    ),
+    '',
+  ].join('\n');
+}
@@ -0,0 +1,239 @@
+/**
+ * Budget Manager - Floyd Wrapper
+ *
+ * Guardrails for a single run: turns, tokens, tool calls, wall-clock time
+ * and estimated cost. The engine records usage as the run goes and checks
+ * the budget before each turn. When a limit is reached the run pauses and
+ * the user decides: continue past this limit, raise it, or abort.
+ *
+ * Limits come from the active profile and/or FLOYD_MAX_RUN_* variables
+ * (see utils/config.ts); an unset limit never stops a run.
+ */
+
+import type { RunBudget } from '../utils/config.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A limit in a run budget
+ */
+export type BudgetLimit = keyof RunBudget;
+
+/**
+ * What a run has used so far
+ */
+export interface BudgetUsage {
+  turns: number;
+  inputTokens: number;
+  outputTokens: number;
+  toolCalls: number;
+  elapsedMs: number;
+  /** Estimated cost in USD, from the model's list prices */
+  costUsd: number;
+}
+
+/**
+ * A limit the run has reached
+ */
+export interface BudgetExceeded {
+  limit: BudgetLimit;
+  /** The limit's current value */
+  cap: number;
+  /** How much of it was used */
+  used: number;
+  /** Human-readable description, e.g. "token budget (50,000)" */
+  message: string;
+  usage: BudgetUsage;
+}
+
+/**
+ * What to do when a limit is reached
+ * - continue: ignore this limit for the rest of the run
+ * - raise: allow as much again as the limit allowed so far
+ * - abort: stop the run
+ */
+export type BudgetDecision = 'continue' | 'raise' | 'abort';
+
+/**
+ * Prices in USD per million tokens
+ */
+export interface ModelPricing {
+  input: number;
+  output: number;
+}
+
+// ============================================================================
+// Pricing
+// ============================================================================
+
+/**
+ * List prices used for cost estimates (longest matching prefix wins)
+ */
+export const MODEL_PRICING: Record<string, ModelPricing> = {
+  'glm-4.7': { input: 0.6, output: 2.2 },
+  'glm-4.6': { input: 0.6, output: 2.2 },
+  'glm-4.5-air': { input: 0.2, output: 1.1 },
+  'glm-4.5': { input: 0.6, output: 2.2 },
+  'glm-4-flash': { input: 0, output: 0 },
+};
+
+const DEFAULT_PRICING: ModelPricing = { input: 0.6, output: 2.2 };
+
+/**
+ * Prices for a model
+ */
+export function getModelPricing(model?: string): ModelPricing {
+  const key = Object.keys(MODEL_PRICING)
+    .filter(prefix => model?.toLowerCase().startsWith(prefix))
+    .sort((a, b) => b.length - a.length)[0];
+  return key ? MODEL_PRICING[key] : DEFAULT_PRICING;
+}
+
+/**
+ * Estimated cost of a number of tokens, in USD
+ */
+export function estimateCost(inputTokens: number, outputTokens: number, pricing: ModelPricing): number {
+  return (inputTokens * pricing.input + outputTokens * pricing.output) / 1_000_000;
+}
+
+// ============================================================================
+// Budget Manager Class
+// ============================================================================
+
+/**
+ * Limits checked in this order
+ */
+const LIMITS: BudgetLimit[] = ['maxTurns', 'maxTokens', 'maxToolCalls', 'maxDurationMs', 'maxCostUsd'];
+
+/**
+ * BudgetManager - Usage tracking and limits for one run
+ */
+export class BudgetManager {
+  private readonly budget: RunBudget;
+  private readonly pricing: ModelPricing;
+  private readonly now: () => number;
+  private readonly waived = new Set<BudgetLimit>();
+  private startedAt: number;
+  private usage = { turns: 0, inputTokens: 0, outputTokens: 0, toolCalls: 0 };
+
+  constructor(budget: RunBudget = {}, model?: string, now: () => number = Date.now) {
+    this.budget = { ...budget };
+    this.pricing = getModelPricing(model);
+    this.now = now;
+    this.startedAt = now();
+  }
+
+  /**
+   * Record the start of a turn (one model request)
+   */
+  recordTurn(): void {
+    this.usage.turns++;
+  }
+
+  /**
+   * Record the token usage of a model request
+   */
+  recordUsage(inputTokens: number, outputTokens: number): void {
+    this.usage.inputTokens += inputTokens;
+    this.usage.outputTokens += outputTokens;
+  }
+
+  /**
+   * Record a tool call
+   */
+  recordToolCall(): void {
+    this.usage.toolCalls++;
+  }
+
+  /**
+   * Usage so far
+   */
+  getUsage(): BudgetUsage {
+    return {
+      ...this.usage,
+      elapsedMs: this.now() - this.startedAt,
+      costUsd: estimateCost(this.usage.inputTokens, this.usage.outputTokens, this.pricing),
+    };
+  }
+
+  /**
+   * Current limits (raised ones included)
+   */
+  getBudget(): RunBudget {
+    return { ...this.budget };
+  }
+
+  /**
+   * The first limit reached, if any
+   */
+  check(): BudgetExceeded | null {
+    const usage = this.getUsage();
+    for (const limit of LIMITS) {
+      const cap = this.budget[limit];
+      if (!cap || this.waived.has(limit)) {
+        continue;
+      }
+      const used = usedFor(limit, usage);
+      if (used >= cap) {
+        return { limit, cap, used, message: describeLimit(limit, cap), usage };
+      }
+    }
+    return null;
+  }
+
+  /**
+   * Apply the user's decision about a reached limit
+   *
+   * @returns Whether the run may go on
+   */
+  resolve(exceeded: BudgetExceeded, decision: BudgetDecision): boolean {
+    switch (decision) {
+      case 'continue':
+        this.waived.add(exceeded.limit);
+        return true;
+      case 'raise':
+        this.budget[exceeded.limit] = exceeded.used + exceeded.cap;
+        return true;
+      case 'abort':
+        return false;
+    }
+  }
+}
+
+/**
+ * How much of a limit a run has used
+ */
+function usedFor(limit: BudgetLimit, usage: BudgetUsage): number {
+  switch (limit) {
+    case 'maxTurns':
+      return usage.turns;
+    case 'maxTokens':
+      return usage.inputTokens + usage.outputTokens;
+    case 'maxToolCalls':
+      return usage.toolCalls;
+    case 'maxDurationMs':
+      return usage.elapsedMs;
+    case 'maxCostUsd':
+      return usage.costUsd;
+  }
+}
+
+/**
+ * Describe a limit, e.g. "token budget (50,000)"
+ */
+export function describeLimit(limit: BudgetLimit, cap: number): string {
+  switch (limit) {
+    case 'maxTurns':
+      return `turn limit (${cap})`;
+    case 'maxTokens':
+      return `token budget (${cap.toLocaleString('en-US')})`;
+    case 'maxToolCalls':
+      return `tool call limit (${cap})`;
+    case 'maxDurationMs':
+      return `time limit (${Math.round(cap / 1000)}s)`;
+    case 'maxCostUsd':
+      return `cost limit ($${cap.toFixed(2)})`;
+  }
+}
@@ -5,12 +5,23 @@
  * tool execution, and turn limiting.
  */
 
-import type { FloydConfig, ConversationHistory, StreamEvent } from '../types.js';
-import { GLMClient } from '../llm/glm-client.js';
+import type { FloydConfig, FloydMessage, ConversationHistory, StreamEvent } from '../types.js';
+import { ProviderRace } from '../llm/provider-race.js';
 import { StreamHandler } from '../streaming/stream-handler.js';
+import { getEventBroadcaster } from '../streaming/event-broadcaster.js';
+import { getRunStatusReporter } from '../utils/run-status.js';
 import { toolRegistry, registerCoreTools } from '../tools/index.js';
+import { ToolResultCache } from '../tools/result-cache.js';
+import { getMemoryStore, getMemoryTopK, isMemoryEnabled, formatMemorySection, stripMemorySection } from '../memory/index.js';
+import { isDryRun, formatDryRunSection, stripDryRunSection } from '../permissions/dry-run.js';
+import { RepoMapIndex, isRepoMapEnabled, getRepoMapChars, stripRepoMapSection } from '../utils/repo-map.js';
 // import { permissionManager } from '../permissions/permission-manager.js'; // DISABLED - restrictions removed
 import { logger } from '../utils/logger.js';
+import { getTracer, type Span } from '../utils/tracing.js';
+import { isToolAllowed } from '../utils/profiles.js';
+import { createToolProfileFilter } from '../utils/tool-profiles.js';
+import { redactSecrets, auditRedactions } from '../utils/redaction.js';
+import { NestedInstructions, formatInstructionFiles, loadProjectInstructions } from '../utils/project-instructions.js';
 import { buildSystemPrompt } from '../prompts/system/index.js';
 import { buildHardenedSystemPrompt } from '../prompts/hardened/index.js';
 import { buildClaudeStyleSystemPrompt } from '../prompts/claude-style/index.js';
@@ -18,7 +29,15 @@ import { buildFloyd47SystemPrompt, buildFlashSystemPrompt } from '../prompts/flo
 import { buildSuggestedSystemPrompt } from '../prompts/suggested/index.js';
 import type { SessionManager } from '../persistence/session-manager.js';
 import { getSandboxManager } from '../sandbox/index.js';
-// import * as path from 'node:path'; // DISABLED - not used after removing validateWorkingDirectory
+import { getChangeJournal, formatChangeSummary } from '../rewind/index.js';
+import { logRunChanges, type FileChange } from 'floyd-agent-core/utils';
+import { BudgetManager, type BudgetDecision, type BudgetExceeded } from './budget-manager.js';
+import { compactConversation, estimateMessageTokens, DEFAULT_AUTO_COMPACT_TOKENS, type CompactionResult } from './auto-compact.js';
+import { getCacheManager } from '../tools/cache/index.js';
+import { recordToolCall, getPostMortemReason, renderPostMortem, writePostMortem, type RunToolRecord } from './post-mortem.js';
+import { readScratchpad, clusterErrors, findErrorCluster, formatErrorCluster, type ErrorCluster } from './scratchpad.js';
+import { HookRunner, loadHooks, type HookEvent } from './hooks.js';
+import path from 'node:path';
 
 // ============================================================================
 // Engine Callbacks
@@ -42,8 +61,22 @@ export interface EngineCallbacks {
   onCheckpointCreated?: (checkpointId: string, fileCount: number, toolName: string) => void;
   /** FIX #2: Called when AUTO mode adapts its behavior */
   onModeAdapt?: (fromMode: string, toMode: string, toolName: string) => void;
+  /** Called at the end of a run with the compact file change summary and the per-file changes */
+  onChangeSummary?: (summary: string, changes: FileChange[]) => void;
+  /** Called when a failed or aborted run's post-mortem was written */
+  onPostMortem?: (scratchpadPath: string) => void;
+  /** Called when the run reaches a budget limit; the run waits for the decision (aborts when unset) */
+  onBudgetExceeded?: (exceeded: BudgetExceeded) => BudgetDecision | Promise<BudgetDecision>;
+  /** Called after older turns were summarized to free context */
+  onCompaction?: (result: CompactionResult, archiveKey: string) => void;
 }
 
+/**
+ * What the engine needs from a model client (ProviderRace in production,
+ * a replay of recorded responses in golden-run tests)
+ */
+export type ChatStreamClient = Pick<ProviderRace, 'streamChat' | 'getTokenUsage'>;
+
 // ============================================================================
 // Floyd Agent Engine
 // ============================================================================
@@ -60,7 +93,9 @@ export interface EngineCallbacks {
  */
 export class FloydAgentEngine {
   private history: ConversationHistory;
-  private glmClient: GLMClient;
+  private glmClient: ChatStreamClient;
+  /** Client passed to the constructor, kept across config changes */
+  private fixedClient?: ChatStreamClient;
   private streamHandler: StreamHandler;
   private maxTurns: number;
   private callbacks: EngineCallbacks;
@@ -68,23 +103,44 @@ export class FloydAgentEngine {
   private executionLock: Promise<unknown> = Promise.resolve(); // Mutex for concurrent execution prevention
   private config: FloydConfig; // Store config to rebuild system prompt later
   private sandboxManager = getSandboxManager();
+  /** Tool calls of the current run, for the post-mortem */
+  private runTools: RunToolRecord[] = [];
+  /** Failures recorded in the scratchpad, loaded on the first failed tool of a run */
+  private errorClusters?: ErrorCluster[];
+  /** Span of the current loop iteration; parent of tool spans */
+  private turnSpan?: Span;
+  /** Usage and limits of the current run */
+  private budget?: BudgetManager;
+  /** AGENTS.md / CLAUDE.md files below the project root, shown once each */
+  private nestedInstructions: NestedInstructions;
+  /** Results the model has already seen this session */
+  private resultCache = new ToolResultCache();
+  /** Commands from .floyd/hooks.toml run on lifecycle events */
+  private hooks: HookRunner;
+  /** Outline of the project, built before the first request */
+  private repoMap?: RepoMapIndex;
   // Public abort controller for interrupt handling
   public abortController: AbortController | null = null;
 
   constructor(
     config: FloydConfig,
     callbacks?: EngineCallbacks,
-    sessionManager?: SessionManager
+    sessionManager?: SessionManager,
+    llmClient?: ChatStreamClient
   ) {
     // Register all core tools
     registerCoreTools();
 
     this.config = config; // Store config
     this.sessionManager = sessionManager;
-    this.glmClient = new GLMClient(config);
+    this.fixedClient = llmClient;
+    this.glmClient = llmClient ?? new ProviderRace(config);
     this.streamHandler = new StreamHandler();
     this.maxTurns = config.maxTurns;
-    this.callbacks = callbacks || {};
+    this.nestedInstructions = new NestedInstructions(config.cwd);
+    this.hooks = new HookRunner(loadHooks(config.cwd), config.cwd);
+    // Mirror callbacks to the optional dashboard event stream (FLOYD_EVENTS_PORT)
+    this.callbacks = getEventBroadcaster().wrapCallbacks(callbacks || {});
 
     // Set ignore patterns if provided - DISABLED
     /*
@@ -187,6 +243,96 @@ export class FloydAgentEngine {
     });
   }
 
+  /**
+   * Replace secrets in text bound for the LLM and the session database,
+   * recording each redaction in .floyd/logs/redactions.log
+   *
+   * @param text - Prompt or serialized tool result
+   * @param source - Where the text came from, for the audit log
+   */
+  private redact(text: string, source: string): string {
+    if (this.config.redactSecrets === false) {
+      return text;
+    }
+    const { text: redacted, redactions } = redactSecrets(text);
+    if (redactions.length > 0) {
+      auditRedactions(redactions, source, this.config.cwd);
+      logger.info('Redacted secrets', { source, count: redactions.length, kinds: [...new Set(redactions.map(r => r.kind))] });
+    }
+    return redacted;
+  }
+
+  /**
+   * Append a system message listing files changed since the journal mark,
+   * and log the changes to .floyd/progress.md
+   *
+   * @param journalMark - Change journal position at the start of the run
+   * @param task - The request the run worked on
+   * @returns The summary ('' when nothing changed)
+   */
+  private async appendChangeSummary(journalMark: number, task: string): Promise<string> {
+    const fileChanges = getChangeJournal().summarizeSince(journalMark);
+    const summary = formatChangeSummary(fileChanges, this.config.cwd);
+
+    if (!summary) {
+      return '';
+    }
+
+    const changes: FileChange[] = fileChanges.map(change => ({
+      ...change,
+      path: path.relative(this.config.cwd, change.path) || change.path,
+    }));
+    try {
+      logRunChanges(task, changes, this.config.cwd);
+    } catch (error) {
+      logger.warn('Failed to log run changes to progress.md', { error });
+    }
+
+    this.history.messages.push({
+      role: 'system',
+      content: summary,
+      timestamp: Date.now(),
+    });
+
+    if (this.sessionManager) {
+      await this.sessionManager.saveMessage('system', summary);
+    }
+
+    this.callbacks.onChangeSummary?.(summary, changes);
+    return summary;
+  }
+
+  /**
+   * Write a post-mortem to the scratchpad if the run was aborted, errored,
+   * or ended with a failing verification
+   */
+  private async writePostMortemIfNeeded(
+    task: string,
+    aborted: boolean,
+    changeSummary: string,
+    runError?: string
+  ): Promise<void> {
+    const reason = getPostMortemReason(aborted, this.runTools, runError);
+    if (!reason) {
+      return;
+    }
+
+    try {
+      const markdown = renderPostMortem({
+        reason,
+        task,
+        turns: this.history.turnCount,
+        tools: this.runTools,
+        changeSummary,
+        runError,
+      });
+      const scratchpadPath = await writePostMortem(this.config.cwd, markdown);
+      this.callbacks.onPostMortem?.(scratchpadPath);
+    } catch (error) {
+      logger.warn('Failed to write post-mortem', { error });
+    }
+  }
+
   /**
    * Execute a user message and run until completion
    *
@@ -202,27 +348,50 @@ export class FloydAgentEngine {
 
       // Reset turn count for new execution
       this.history.turnCount = 0;
+      this.runTools = [];
+      this.errorClusters = undefined;
+
+      // Remember where this run starts in the change journal
+      const journalMark = getChangeJournal().mark();
+      const tracer = getTracer();
+      const runSpan = tracer.startSpan('agent.run', undefined, {
+        'floyd.mode': process.env.FLOYD_MODE || 'ask',
+        'floyd.message_length': userMessage.length,
+      });
+      const events = getEventBroadcaster();
+      events.emit('run_start', { message: userMessage, messageLength: userMessage.length });
+      getRunStatusReporter().set('running');
 
       logger.info('Starting execution', {
         turnCount: this.history.turnCount,
         messageLength: userMessage.length,
       });
 
-      // Add user message to history
+      // The repository map and long-term memories relevant to this request go into the system prompt
+      await this.refreshRequestContext(userMessage);
+
+      // Add user message to history (pasted keys and file contents are redacted)
+      const prompt = this.redact(userMessage, 'prompt');
       this.history.messages.push({
         role: 'user',
-        content: userMessage,
+        content: prompt,
         timestamp: Date.now(),
       });
 
       // Persist user message
       if (this.sessionManager) {
-        await this.sessionManager.saveMessage('user', userMessage);
+        await this.sessionManager.saveMessage('user', prompt);
       }
 
       // Main agentic loop
       let finalResponse = '';
       let aborted = false;
+      let runError: string | undefined;
+      let budgetStop: string | undefined;
+      const budget = new BudgetManager(this.config.runBudget, this.config.glmModel);
+      this.budget = budget;
+      // Messages already included in a recorded request this run
+      let recordedMessages = 0;
 
       // Max turns limit DISABLED - restrictions removed
       // while (this.history.turnCount < this.maxTurns) {
@@ -234,12 +403,29 @@ export class FloydAgentEngine {
           break;
         }
 
+        // Run budgets (unlimited unless a profile or FLOYD_MAX_RUN_* sets them)
+        budgetStop = await this.resolveBudget(budget);
+        if (budgetStop) {
+          break;
+        }
+
+        // Summarize older turns when the conversation nears the context window
+        await this.autoCompact(signal);
+
         logger.debug('Starting turn', {
           turn: this.history.turnCount + 1,
           maxTurns: this.maxTurns,
         });
 
         this.history.turnCount++;
+        budget.recordTurn();
+        events.emit('iteration', { turn: this.history.turnCount });
+        const turnSpan = tracer.startSpan('agent.iteration', runSpan, { 'floyd.turn': this.history.turnCount });
+        this.turnSpan = turnSpan;
+        const apiSpan = tracer.startSpan('llm.stream_chat', turnSpan, {
+          'gen_ai.request.model': this.config.glmModel,
+          'floyd.history_messages': this.history.messages.length,
+        }, 'client');
 
         // Notify that thinking is starting (for spinner)
         this.callbacks.onThinkingStart?.();
@@ -250,15 +436,40 @@ export class FloydAgentEngine {
         };
 
         try {
+          const tools = this.buildToolDefinitions();
+
+          // Recordings get the first request in full, then only new messages
+          if (events.hasReceivers()) {
+            events.emit('request', {
+              turn: this.history.turnCount,
+              model: this.config.glmModel,
+              tools: tools.map(tool => tool.function.name),
+              previousMessages: recordedMessages,
+              messages: this.history.messages.slice(recordedMessages),
+            });
+            recordedMessages = this.history.messages.length;
+          }
+
           // Call GLM-4.7 with streaming
           const stream = this.glmClient.streamChat({
             messages: this.history.messages,
-            tools: this.buildToolDefinitions(),
+            tools,
             abortSignal: signal,
             onToken: this.callbacks.onToken,
             onComplete: (usage) => {
+              apiSpan.setAttributes({
+                'gen_ai.usage.input_tokens': usage.inputTokens,
+                'gen_ai.usage.output_tokens': usage.outputTokens,
+              });
               // Update token count in history
               this.history.tokenCount += usage.totalTokens;
+              budget.recordUsage(usage.inputTokens, usage.outputTokens);
+              events.emit('usage', {
+                inputTokens: usage.inputTokens,
+                outputTokens: usage.outputTokens,
+                totalTokens: usage.totalTokens,
+                sessionTokens: this.history.tokenCount,
+              });
               logger.debug('Updated token count', {
                 tokenCount: this.history.tokenCount,
                 inputTokens: usage.inputTokens,
@@ -273,10 +484,13 @@ export class FloydAgentEngine {
           // Check if this is an abort error
           if (error instanceof Error && error.name === 'AbortError') {
             logger.info('Stream aborted by user');
+            apiSpan.setAttributes({ 'floyd.aborted': true });
             aborted = true;
             break;
           }
           logger.error('Error during stream processing', error);
+          apiSpan.recordError(error);
+          runError = error instanceof Error ? error.message : String(error);
           // Return empty result on error to allow graceful recovery
           result = {
             assistantMessage: `I encountered an error: ${error instanceof Error ? error.message : String(error)}`,
@@ -286,8 +500,14 @@ export class FloydAgentEngine {
           // Always notify that thinking is complete (stop spinner)
           // This ensures we don't get stuck in "thinking" state
           this.callbacks.onThinkingComplete?.();
+          apiSpan.end();
+          if (aborted) {
+            turnSpan.end();
+          }
         }
 
+        turnSpan.setAttributes({ 'floyd.tool_calls': result.toolResults.length });
+
         logger.debug('Stream result received', {
           assistantMessageLength: result.assistantMessage.length,
           toolResultsCount: result.toolResults.length,
@@ -309,14 +529,16 @@ export class FloydAgentEngine {
         if (result.toolResults.length === 0) {
           logger.info('No tool use detected - execution complete');
           finalResponse = result.assistantMessage;
+          turnSpan.end();
           break;
         }
 
         // Add tool results to history
         for (const toolResult of result.toolResults) {
+          const content = this.redact(JSON.stringify(toolResult.result), `tool_result:${toolResult.toolUseId}`);
           this.history.messages.push({
             role: 'tool',
-            content: JSON.stringify(toolResult.result),
+            content,
             timestamp: Date.now(),
             toolUseId: toolResult.toolUseId,
           });
@@ -325,12 +547,14 @@ export class FloydAgentEngine {
           if (this.sessionManager) {
             await this.sessionManager.saveMessage(
               'tool',
-              JSON.stringify(toolResult.result),
+              content,
               toolResult.toolUseId
             );
           }
         }
 
+        turnSpan.end();
+
         // Continue to next turn
         logger.debug('Continuing to next turn', {
           toolCount: result.toolResults.length,
@@ -347,6 +571,29 @@ export class FloydAgentEngine {
       // Clean up abort controller
       this.abortController = null;
 
+      // Append a compact summary of files touched during this run
+      const changeSummary = await this.appendChangeSummary(journalMark, userMessage);
+      await this.writePostMortemIfNeeded(userMessage, aborted, changeSummary, runError);
+
+      events.emit('run_complete', {
+        aborted,
+        turns: this.history.turnCount,
+        tokenCount: this.history.tokenCount,
+      });
+
+      runSpan.setAttributes({
+        'floyd.aborted': aborted,
+        'floyd.turns': this.history.turnCount,
+        'floyd.session_tokens': this.history.tokenCount,
+      });
+      if (runError) {
+        runSpan.recordError(runError);
+      }
+      runSpan.end();
+      this.turnSpan = undefined;
+      void tracer.flush();
+      getRunStatusReporter().set('done');
+
       // Handle abort - return incomplete response
       if (aborted) {
         logger.info('Returning incomplete response due to abort');
@@ -368,7 +615,11 @@ export class FloydAgentEngine {
         .filter(m => m.role === 'assistant')
         .pop();
 
-      return lastAssistantMessage?.content || finalResponse;
+      const response = lastAssistantMessage?.content || finalResponse;
+      void this.runHooks('response_complete', { response, turns: this.history.turnCount });
+      return budgetStop
+        ? `${response}\n\n[Stopped: ${budgetStop} reached]`
+        : response;
     });
 
     // Update the execution lock for next call (chaining)
@@ -407,6 +658,7 @@ export class FloydAgentEngine {
           logger.info('Executing tool', { toolName, input, mode });
 
           // Notify callback
+          this.budget?.recordToolCall();
           this.callbacks.onToolStart?.(toolName, input);
 
           // Get tool definition to check permission level - DISABLED
@@ -517,6 +769,27 @@ export class FloydAgentEngine {
           }
           */
 
+          // The active run profile or tool profile may restrict the tool set
+          const toolDef = toolRegistry.get(toolName);
+          const inToolProfile = !toolDef || createToolProfileFilter(this.config.toolProfile, this.config.cwd)(toolDef);
+          if (toolDef && (!isToolAllowed(toolDef, this.config.allowedTools) || !inToolProfile)) {
+            const errorResult = {
+              success: false,
+              error: {
+                code: 'TOOL_NOT_ALLOWED',
+                message: inToolProfile
+                  ? `Tool "${toolName}" is not enabled in profile "${this.config.profile}"`
+                  : `Tool "${toolName}" is not available in tool profile "${this.config.toolProfile}"`,
+              },
+            };
+            const pendingToolUse = this.streamHandler.getPendingToolUse();
+            if (pendingToolUse) {
+              toolResults.push({ toolUseId: pendingToolUse.id as string, result: errorResult });
+            }
+            this.callbacks.onToolComplete?.(toolName, errorResult);
+            return;
+          }
+
           // Auto-approve ALL tools - restrictions disabled
           // const permissionGranted = true;
           logger.info(`[PERMIT] ${toolName}:${target} - AUTO-APPROVED (RESTRICTIONS DISABLED)`);
@@ -548,11 +821,45 @@ export class FloydAgentEngine {
           }
           */
 
+          // Hooks may block the call or rewrite its input
+          const decision = await this.hooks.preToolCall(toolName, input);
+          if (decision.blocked) {
+            logger.warn(`Tool call blocked by hook: ${toolName}`, { reason: decision.blocked });
+            const errorResult = {
+              success: false,
+              error: { code: 'BLOCKED_BY_HOOK', message: decision.blocked },
+            };
+            const pendingToolUse = this.streamHandler.getPendingToolUse();
+            if (pendingToolUse) {
+              toolResults.push({ toolUseId: pendingToolUse.id as string, result: errorResult });
+            }
+            this.callbacks.onToolComplete?.(toolName, errorResult);
+            return;
+          }
+          input = decision.input;
+
+          // The model already has this result; don't repeat it
+          const cached = await this.resultCache.lookup(toolName, input);
+          if (cached) {
+            logger.debug('Repeated tool call, returning unchanged stub', { toolName });
+            const pendingToolUse = this.streamHandler.getPendingToolUse();
+            if (pendingToolUse) {
+              toolResults.push({ toolUseId: pendingToolUse.id as string, result: cached });
+            }
+            this.runTools.push(recordToolCall(toolName, input, cached));
+            this.callbacks.onToolComplete?.(toolName, cached);
+            return;
+          }
+
           // FIX #3: Execute tool through registry with auto-checkpoint
           // This creates a checkpoint before dangerous operations - CHECKPOINTS DISABLED
+          const toolSpan = getTracer().startSpan('tool.execute', this.turnSpan, { 'tool.name': toolName });
           const resultWithCheckpoint = await toolRegistry.executeWithAutoCheckpoint(toolName, input, {
             permissionGranted: true,
             sessionId: this.sessionManager?.getCurrentSessionId() ?? undefined,
+          }).catch((error: unknown) => {
+            toolSpan.recordError(error).end();
+            throw error;
           });
 
           // FIX #3: Show checkpoint notification to user - DISABLED
@@ -569,7 +876,18 @@ export class FloydAgentEngine {
           */
 
           // Extract the base result (without checkpoint) for compatibility
-          const { checkpoint, ...result } = resultWithCheckpoint;
+          const { checkpoint, ...toolResult } = resultWithCheckpoint;
+          await this.resultCache.store(toolName, input, toolResult);
+
+          // Instructions for the part of the tree this tool touched
+          const instructions = this.collectNestedInstructions(input);
+          const toolRecord = recordToolCall(toolName, input, toolResult);
+          const pastFailures = toolRecord.success ? undefined : await this.findPastFailures(toolRecord);
+          const result = {
+            ...toolResult,
+            ...(instructions ? { project_instructions: instructions } : {}),
+            ...(pastFailures ? { past_failures: pastFailures } : {}),
+          };
 
           // Store result for later
           const pendingToolUse = this.streamHandler.getPendingToolUse();
@@ -580,8 +898,17 @@ export class FloydAgentEngine {
             });
           }
 
+          this.runTools.push(toolRecord);
+
+          toolSpan.setAttributes({ 'tool.success': toolRecord.success, 'error.code': toolRecord.code });
+          if (!toolRecord.success) {
+            toolSpan.recordError(toolRecord.error ?? 'failed');
+          }
+          toolSpan.end();
+
           // Notify callback
           this.callbacks.onToolComplete?.(toolName, result);
+          void this.hooks.run('post_tool_call', { tool: toolName, input, result }, toolName);
         },
         onError: (error: string) => {
           logger.error('Stream error', new Error(error));
@@ -620,7 +947,8 @@ export class FloydAgentEngine {
       parameters: Record<string, unknown>;
     };
   }> {
-    const tools = toolRegistry.getAll();
+    const inToolProfile = createToolProfileFilter(this.config.toolProfile, this.config.cwd);
+    const tools = toolRegistry.getAll().filter(tool => isToolAllowed(tool, this.config.allowedTools) && inToolProfile(tool));
 
     logger.debug('Building tool definitions', {
       toolCount: tools.length,
@@ -915,6 +1243,203 @@ export class FloydAgentEngine {
     }
   }
 
+  /**
+   * The system prompt as it will be sent with the next request
+   */
+  getSystemPrompt(): string {
+    const system = this.history.messages[0];
+    return system?.role === 'system' ? system.content : '';
+  }
+
+  /**
+   * Tool definitions offered to the model with each request
+   */
+  getToolDefinitions(): Array<{ type: 'function'; function: { name: string; description: string; parameters: Record<string, unknown> } }> {
+    return this.buildToolDefinitions();
+  }
+
+  /**
+   * Re-read the project instruction files and rebuild the system prompt
+   * (e.g. after /prompt edit, or when the context watcher sees a change)
+   *
+   * @returns Whether the project context changed
+   */
+  reloadProjectContext(): boolean {
+    const projectContext = loadProjectInstructions(this.config.cwd);
+    if (projectContext === this.config.projectContext) {
+      return false;
+    }
+    this.config = { ...this.config, projectContext };
+    this.updateSystemPrompt();
+    return true;
+  }
+
+  /**
+   * Re-read the hooks, e.g. after the user trusted the project's hooks.toml
+   */
+  reloadHooks(): void {
+    this.hooks = new HookRunner(loadHooks(this.config.cwd), this.config.cwd);
+  }
+
+  /**
+   * Run the hooks.toml hooks for an event (e.g. session_start from the CLI)
+   */
+  async runHooks(event: HookEvent, payload: Record<string, unknown> = {}): Promise<void> {
+    await this.hooks.run(event, {
+      sessionId: this.sessionManager?.getCurrentSessionId() ?? undefined,
+      ...payload,
+    });
+  }
+
+  /**
+   * Get the active configuration
+   */
+  getConfig(): FloydConfig {
+    return this.config;
+  }
+
+  /**
+   * Switch to a new configuration (e.g. after /profile)
+   *
+   * Rebuilds the provider client so model changes apply to the next turn.
+   */
+  updateConfig(config: FloydConfig): void {
+    this.config = config;
+    this.maxTurns = config.maxTurns;
+    this.glmClient = this.fixedClient ?? new ProviderRace(config);
+    this.updateSystemPrompt();
+  }
+
+  /**
+   * Put the dry run notice, the repository map slice and the memories most
+   * relevant to a request into the system prompt, replacing the previous
+   * request's. None of them ever blocks a run.
+   */
+  private async refreshRequestContext(userMessage: string): Promise<void> {
+    const system = this.history.messages[0];
+    if (system?.role !== 'system') {
+      return;
+    }
+
+    const sections = [
+      isDryRun() ? formatDryRunSection() : '',
+      await this.getRepoMapSlice(userMessage),
+      await this.recallMemories(userMessage),
+    ];
+    const base = stripDryRunSection(stripMemorySection(stripRepoMapSection(system.content)));
+    system.content = [base, ...sections.filter(Boolean)].join('\n\n');
+  }
+
+  /**
+   * Repository map slice for a request; the map is refreshed from changed files first
+   */
+  private async getRepoMapSlice(userMessage: string): Promise<string> {
+    if (!isRepoMapEnabled()) {
+      return '';
+    }
+
+    try {
+      this.repoMap ??= new RepoMapIndex(this.config.cwd);
+      const started = Date.now();
+      if (await this.repoMap.refresh()) {
+        logger.debug('Repository map updated', { files: this.repoMap.getMap().files.length, ms: Date.now() - started });
+      }
+      return this.repoMap.getSlice(userMessage, getRepoMapChars());
+    } catch (error) {
+      logger.warn('Repository map failed', { error: error instanceof Error ? error.message : String(error) });
+      return '';
+    }
+  }
+
+  /**
+   * System prompt section with the memories most relevant to a request
+   */
+  private async recallMemories(userMessage: string): Promise<string> {
+    let section = '';
+    if (isMemoryEnabled()) {
+      try {
+        const matches = await getMemoryStore(this.config.cwd).search(userMessage, getMemoryTopK());
+        if (matches.length > 0) {
+          section = formatMemorySection(matches);
+          logger.debug('Recalled memories', { ids: matches.map(match => match.entry.id) });
+        }
+      } catch (error) {
+        logger.warn('Memory recall failed', { error: error instanceof Error ? error.message : String(error) });
+      }
+    }
+    return section;
+  }
+
+  /**
+   * Check the run budget, asking the user how to go on for each limit reached
+   *
+   * @returns Which limit stopped the run, if any
+   */
+  private async resolveBudget(budget: BudgetManager): Promise<string | undefined> {
+    for (let exceeded = budget.check(); exceeded; exceeded = budget.check()) {
+      logger.warn('Run budget reached', { limit: exceeded.limit, cap: exceeded.cap, used: exceeded.used, profile: this.config.profile });
+      getEventBroadcaster().emit('budget_exceeded', {
+        limit: exceeded.limit,
+        cap: exceeded.cap,
+        used: exceeded.used,
+        message: exceeded.message,
+        usage: exceeded.usage,
+      });
+
+      let decision: BudgetDecision = 'abort';
+      try {
+        decision = (await this.callbacks.onBudgetExceeded?.(exceeded)) ?? 'abort';
+      } catch (error) {
+        logger.warn('Budget prompt failed, stopping the run', { error });
+      }
+      logger.info('Run budget decision', { limit: exceeded.limit, decision });
+
+      if (!budget.resolve(exceeded, decision)) {
+        return exceeded.message;
+      }
+    }
+    return undefined;
+  }
+
+  /**
+   * Replace older turns with a model-written summary once the estimated
+   * prompt passes autoCompactTokens; the full messages go to the vault tier
+   */
+  private async autoCompact(signal: AbortSignal): Promise<void> {
+    const threshold = this.config.autoCompactTokens ?? DEFAULT_AUTO_COMPACT_TOKENS;
+    if (threshold <= 0 || estimateMessageTokens(this.history.messages) <= threshold) {
+      return;
+    }
+
+    const archiveKey = `conversation:${this.sessionManager?.getCurrentSessionId() ?? 'session'}:${Date.now()}`;
+    try {
+      const result = await compactConversation(this.history.messages, this.glmClient, {
+        archiveNote: `The full messages are archived in the vault cache tier under "${archiveKey}".`,
+        abortSignal: signal,
+      });
+      if (!result) {
+        return;
+      }
+
+      await getCacheManager().store('vault', archiveKey, JSON.stringify(this.history.messages), {
+        source: 'auto-compact',
+        messages: this.history.messages.length,
+      });
+      this.history.messages = result.messages;
+      this.resultCache.reset();
+
+      logger.info('Compacted conversation', {
+        compacted: result.compactedCount,
+        tokensBefore: result.tokensBefore,
+        tokensAfter: result.tokensAfter,
+      });
+      this.callbacks.onCompaction?.(result, archiveKey);
+    } catch (error) {
+      // Keep the full history; the request may still fit
+      logger.warn('Auto-compaction failed', { error });
+    }
+  }
+
   /**
    * Get current conversation history
    *
@@ -924,6 +1449,23 @@ export class FloydAgentEngine {
     return this.history;
   }
 
+  /**
+   * Replace the conversation with saved messages (/rewind, /branch)
+   *
+   * @param messages - Messages from a conversation checkpoint or session
+   */
+  restoreHistory(messages: FloydMessage[]): void {
+    this.history = {
+      messages: messages.map(message => ({ ...message })),
+      turnCount: messages.filter(message => message.role === 'user').length,
+      tokenCount: 0,
+    };
+    this.nestedInstructions.reset();
+    this.resultCache.reset();
+
+    logger.debug('Conversation history restored', { messageCount: messages.length });
+  }
+
   /**
    * Get token usage statistics
    */
@@ -949,6 +1491,44 @@ export class FloydAgentEngine {
     };
   }
 
+  /**
+   * Nested instruction files for the paths in a tool's input, if not shown yet
+   */
+  private collectNestedInstructions(input: Record<string, unknown>): string | undefined {
+    const files = ['file_path', 'filePath', 'path', 'directory', 'source', 'destination']
+      .filter(field => typeof input[field] === 'string')
+      .flatMap(field => this.nestedInstructions.collect(input[field] as string));
+
+    if (files.length === 0) {
+      return undefined;
+    }
+    logger.debug('Attaching nested project instructions', { files: files.map(f => f.relativePath) });
+    return formatInstructionFiles(files);
+  }
+
+  /**
+   * Earlier occurrences of a failure from the scratchpad, as a note for the
+   * model; only the matching cluster is sent, never the whole file
+   */
+  private async findPastFailures(record: RunToolRecord): Promise<string | undefined> {
+    if (!record.error) {
+      return undefined;
+    }
+    try {
+      this.errorClusters ??= clusterErrors(await readScratchpad(this.config.cwd));
+    } catch (error) {
+      logger.debug('Could not read the scratchpad', { error: (error as Error).message });
+      this.errorClusters = [];
+    }
+
+    const cluster = findErrorCluster(this.errorClusters, record.tool, record.error);
+    if (!cluster) {
+      return undefined;
+    }
+    logger.debug('Attaching past failures', { tool: record.tool, count: cluster.count });
+    return formatErrorCluster(cluster);
+  }
+
   /**
    * Reset conversation history
    */
@@ -958,6 +1538,8 @@ export class FloydAgentEngine {
       turnCount: 0,
       tokenCount: 0,
     };
+    this.nestedInstructions.reset();
+    this.resultCache.reset();
 
     logger.debug('Conversation history reset');
   }
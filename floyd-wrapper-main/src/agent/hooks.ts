@@ -0,0 +1,326 @@
+/**
+ * Hooks - Floyd Wrapper
+ *
+ * Runs user commands on engine lifecycle events, configured in
+ * ~/.floyd/hooks.toml (user) and .floyd/hooks.toml (project) with one table
+ * per hook:
+ *
+ *   [pre_tool_call.lint]
+ *   command = "scripts/check-edit.sh"
+ *   match = "edit_file|write"     # regex on the tool name (tool events only)
+ *   timeout = 10                  # seconds, default 30
+ *
+ *   [response_complete.notify]
+ *   command = "notify-send FLOYD done"
+ *
+ * Events: session_start, pre_tool_call, post_tool_call, response_complete.
+ * A hook gets the event as JSON on stdin and FLOYD_HOOK_EVENT / FLOYD_TOOL_NAME
+ * in its environment. A pre_tool_call hook blocks the call by exiting with
+ * code 2 (stderr is the reason), or prints {"decision": "block", "reason": ...}
+ * or {"input": {...}} to block or rewrite it. A pre_tool_call hook that times
+ * out or can't be run blocks the call too, so a broken guard fails closed.
+ *
+ * User hooks always run. A project's hooks only run once the user has
+ * trusted them (the CLI asks at startup); trust is remembered per file in
+ * ~/.floyd/trusted-hooks.json until the file changes.
+ */
+
+import crypto from 'node:crypto';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { execa } from 'execa';
+import { parseToml } from 'floyd-agent-core/ui';
+import { logger } from '../utils/logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export const HOOK_EVENTS = ['session_start', 'pre_tool_call', 'post_tool_call', 'response_complete'] as const;
+
+export type HookEvent = typeof HOOK_EVENTS[number];
+
+/**
+ * A configured hook
+ */
+export interface HookDefinition {
+  event: HookEvent;
+  name: string;
+  command: string;
+  /** Tool names the hook applies to; all when unset */
+  match?: RegExp;
+  timeoutMs: number;
+}
+
+/**
+ * Outcome of the pre_tool_call hooks for one call
+ */
+export interface PreToolDecision {
+  /** Reason the call was blocked, if it was */
+  blocked?: string;
+  /** Input to run the tool with (rewritten by a hook, or unchanged) */
+  input: Record<string, unknown>;
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+export const HOOKS_FILE = path.join('.floyd', 'hooks.toml');
+
+const DEFAULT_TIMEOUT_SECONDS = 30;
+
+/**
+ * Exit code with which a pre_tool_call hook blocks the call
+ */
+export const BLOCK_EXIT_CODE = 2;
+
+/**
+ * What running a hook produced; error is set when it timed out or could not run
+ */
+interface HookResult {
+  exitCode: number;
+  stdout: string;
+  stderr: string;
+  error?: string;
+}
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+/**
+ * Hooks in a hooks.toml document
+ *
+ * @throws Error for unknown events or hooks without a command
+ */
+export function parseHooksConfig(source: string): HookDefinition[] {
+  const hooks: HookDefinition[] = [];
+
+  for (const [table, entries] of Object.entries(parseToml(source))) {
+    const event = table.replace(/-/g, '_') as HookEvent;
+    if (!HOOK_EVENTS.includes(event)) {
+      throw new Error(`unknown hook event "${table}" (expected ${HOOK_EVENTS.join(', ')})`);
+    }
+    if (typeof entries !== 'object') {
+      throw new Error(`"${table}" must be a table of hooks, e.g. [${table}.name]`);
+    }
+
+    for (const [name, hook] of Object.entries(entries)) {
+      if (typeof hook !== 'object' || typeof hook.command !== 'string' || !hook.command.trim()) {
+        throw new Error(`hook "${table}.${name}" needs a command`);
+      }
+      hooks.push({
+        event,
+        name,
+        command: hook.command,
+        match: typeof hook.match === 'string' ? new RegExp(`^(?:${hook.match})$`) : undefined,
+        timeoutMs: (typeof hook.timeout === 'number' ? hook.timeout : DEFAULT_TIMEOUT_SECONDS) * 1000,
+      });
+    }
+  }
+
+  return hooks;
+}
+
+/**
+ * Hooks in a hooks.toml file; none when it is missing or invalid
+ */
+function readHooksFile(filePath: string): HookDefinition[] {
+  if (!fs.existsSync(filePath)) {
+    return [];
+  }
+
+  try {
+    return parseHooksConfig(fs.readFileSync(filePath, 'utf-8'));
+  } catch (error) {
+    logger.warn(`Ignoring ${filePath}: ${(error as Error).message}`);
+    return [];
+  }
+}
+
+function getUserHooksPath(): string {
+  return path.join(os.homedir(), HOOKS_FILE);
+}
+
+function getTrustFilePath(): string {
+  return path.join(os.homedir(), '.floyd', 'trusted-hooks.json');
+}
+
+/**
+ * Fingerprint of a project's hooks.toml (null when there is none)
+ */
+function projectHooksHash(cwd: string): string | null {
+  try {
+    return crypto.createHash('sha256').update(fs.readFileSync(path.join(cwd, HOOKS_FILE))).digest('hex');
+  } catch {
+    return null;
+  }
+}
+
+function readTrusted(): Record<string, string> {
+  try {
+    return fs.readJsonSync(getTrustFilePath()) as Record<string, string>;
+  } catch {
+    return {};
+  }
+}
+
+/**
+ * Whether the user trusted the current contents of a project's hooks.toml
+ */
+export function isProjectHooksTrusted(cwd: string = process.cwd()): boolean {
+  const hash = projectHooksHash(cwd);
+  return hash !== null && readTrusted()[path.resolve(cwd, HOOKS_FILE)] === hash;
+}
+
+/**
+ * Remember that the user trusts the current contents of a project's hooks.toml
+ */
+export function trustProjectHooks(cwd: string = process.cwd()): void {
+  const hash = projectHooksHash(cwd);
+  if (hash === null) {
+    return;
+  }
+  fs.outputJsonSync(getTrustFilePath(), { ...readTrusted(), [path.resolve(cwd, HOOKS_FILE)]: hash }, { spaces: 2 });
+}
+
+/**
+ * A project's hooks that wait for the user's trust (empty when there are
+ * none or they are trusted already)
+ */
+export function getUntrustedProjectHooks(cwd: string = process.cwd()): HookDefinition[] {
+  return isProjectHooksTrusted(cwd) ? [] : readHooksFile(path.join(cwd, HOOKS_FILE));
+}
+
+/**
+ * Hooks to run: the user's, plus the project's once trusted
+ */
+export function loadHooks(cwd: string = process.cwd()): HookDefinition[] {
+  const hooks = readHooksFile(getUserHooksPath());
+
+  if (isProjectHooksTrusted(cwd)) {
+    hooks.push(...readHooksFile(path.join(cwd, HOOKS_FILE)));
+  } else if (fs.existsSync(path.join(cwd, HOOKS_FILE))) {
+    logger.warn(`Not running ${HOOKS_FILE}: the project's hooks are not trusted`);
+  }
+
+  if (hooks.length > 0) {
+    logger.info('Loaded hooks', { count: hooks.length });
+  }
+  return hooks;
+}
+
+// ============================================================================
+// Hook Runner
+// ============================================================================
+
+/**
+ * Runs the hooks of a project for engine events
+ */
+export class HookRunner {
+  private readonly hooks: HookDefinition[];
+  private readonly cwd: string;
+
+  constructor(hooks: HookDefinition[], cwd: string = process.cwd()) {
+    this.hooks = hooks;
+    this.cwd = cwd;
+  }
+
+  /**
+   * Hooks for an event (and tool, for tool events)
+   */
+  hooksFor(event: HookEvent, tool?: string): HookDefinition[] {
+    return this.hooks.filter(hook =>
+      hook.event === event && (!hook.match || (tool !== undefined && hook.match.test(tool)))
+    );
+  }
+
+  /**
+   * Run the hooks for an event one after another; failures are logged
+   */
+  async run(event: HookEvent, payload: Record<string, unknown> = {}, tool?: string): Promise<void> {
+    for (const hook of this.hooksFor(event, tool)) {
+      await this.exec(hook, payload, tool);
+    }
+  }
+
+  /**
+   * Run the pre_tool_call hooks; each sees the input left by the previous one
+   */
+  async preToolCall(tool: string, input: Record<string, unknown>): Promise<PreToolDecision> {
+    let current = input;
+
+    for (const hook of this.hooksFor('pre_tool_call', tool)) {
+      const result = await this.exec(hook, { tool, input: current }, tool);
+      if (result.error) {
+        return { blocked: `Hook "${hook.name}" ${result.error}`, input: current };
+      }
+      if (result.exitCode === BLOCK_EXIT_CODE) {
+        return { blocked: result.stderr.trim() || `Blocked by hook "${hook.name}"`, input: current };
+      }
+
+      const reply = parseReply(result.stdout);
+      if (reply?.decision === 'block') {
+        return { blocked: typeof reply.reason === 'string' ? reply.reason : `Blocked by hook "${hook.name}"`, input: current };
+      }
+      if (reply?.input && typeof reply.input === 'object' && !Array.isArray(reply.input)) {
+        logger.info('Hook rewrote tool input', { hook: hook.name, tool });
+        current = reply.input as Record<string, unknown>;
+      }
+    }
+
+    return { input: current };
+  }
+
+  private async exec(
+    hook: HookDefinition,
+    payload: Record<string, unknown>,
+    tool?: string
+  ): Promise<HookResult> {
+    const label = `Hook "${hook.event}.${hook.name}"`;
+    try {
+      const result = await execa(hook.command, {
+        shell: true,
+        cwd: this.cwd,
+        input: JSON.stringify({ event: hook.event, cwd: this.cwd, ...payload }),
+        timeout: hook.timeoutMs,
+        reject: false,
+        env: { FLOYD_HOOK_EVENT: hook.event, FLOYD_TOOL_NAME: tool ?? '' },
+      });
+
+      const failed = (error: string): HookResult => {
+        logger.warn(`${label} ${error}`);
+        return { exitCode: result.exitCode ?? 1, stdout: result.stdout, stderr: result.stderr, error };
+      };
+
+      if (result.timedOut) {
+        return failed(`timed out after ${hook.timeoutMs}ms`);
+      }
+      if (result.exitCode === undefined) {
+        return failed(result.signal ? `was killed by ${result.signal}` : 'could not be started');
+      }
+      if (result.exitCode !== 0 && result.exitCode !== BLOCK_EXIT_CODE) {
+        logger.warn(`${label} exited with code ${result.exitCode}`, { stderr: result.stderr });
+      }
+      return { exitCode: result.exitCode, stdout: result.stdout, stderr: result.stderr };
+    } catch (error) {
+      const message = `failed: ${(error as Error).message}`;
+      logger.warn(`${label} ${message}`);
+      return { exitCode: 1, stdout: '', stderr: '', error: message };
+    }
+  }
+}
+
+function parseReply(stdout: string): Record<string, unknown> | null {
+  const text = stdout.trim();
+  if (!text.startsWith('{')) {
+    return null;
+  }
+  try {
+    return JSON.parse(text) as Record<string, unknown>;
+  } catch {
+    return null;
+  }
+}
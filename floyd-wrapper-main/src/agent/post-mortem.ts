@@ -0,0 +1,211 @@
+/**
+ * Post-Mortem - Floyd Wrapper
+ *
+ * When a run is aborted, hits an error, or ends with a failing verification
+ * (verify tool, test or lint command), a structured post-mortem is appended
+ * to .floyd/scratchpad.md: what was attempted, which tools failed and why,
+ * what changed, and a suggested next approach. The next run (or the human)
+ * starts from a diagnosis instead of raw error output.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { classifyVerification } from '../persistence/branch-notes.js';
+import { logger } from '../utils/logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One tool call made during a run
+ */
+export interface RunToolRecord {
+  tool: string;
+  /** Short description of the input (path, command, ...) */
+  target: string;
+  success: boolean;
+  /** Error code, when the tool failed */
+  code?: string;
+  /** Error message, when the tool failed */
+  error?: string;
+  /** Whether this call verified the work (verify tool, tests, lint) */
+  verification: boolean;
+}
+
+/**
+ * Why the post-mortem was written
+ */
+export type PostMortemReason = 'aborted' | 'verification-failed' | 'error';
+
+/**
+ * Everything known about a failed run
+ */
+export interface PostMortemInput {
+  reason: PostMortemReason;
+  /** The user's request */
+  task: string;
+  turns: number;
+  tools: RunToolRecord[];
+  /** Compact change summary for the run ('' when nothing changed) */
+  changeSummary: string;
+  /** Error that ended the run, if any */
+  runError?: string;
+}
+
+// ============================================================================
+// Recording
+// ============================================================================
+
+/**
+ * Describe a tool call's target in a few words
+ */
+function describeTarget(input: Record<string, unknown>): string {
+  for (const field of ['file_path', 'filePath', 'path', 'target', 'query', 'pattern', 'url']) {
+    if (typeof input[field] === 'string') {
+      return input[field] as string;
+    }
+  }
+  if (typeof input.command === 'string') {
+    return [input.command, ...((input.args as string[] | undefined) ?? [])].join(' ');
+  }
+  return '';
+}
+
+/**
+ * Build a record from a tool call and its result
+ */
+export function recordToolCall(tool: string, input: Record<string, unknown>, result: unknown): RunToolRecord {
+  const target = describeTarget(input);
+  const outcome = (result ?? {}) as { success?: boolean; error?: { code?: string; message?: string }; data?: { stderr?: string } };
+  const success = outcome.success !== false;
+
+  const verification = tool === 'verify'
+    || (tool === 'run' && classifyVerification(target) !== null);
+
+  const error = success
+    ? undefined
+    : outcome.error?.message || outcome.data?.stderr?.split('\n').find(line => line.trim()) || 'failed';
+
+  return { tool, target, success, code: outcome.error?.code, error, verification };
+}
+
+/**
+ * Decide whether a finished run needs a post-mortem
+ */
+export function getPostMortemReason(
+  aborted: boolean,
+  tools: RunToolRecord[],
+  runError?: string
+): PostMortemReason | null {
+  if (runError) {
+    return 'error';
+  }
+  if (aborted) {
+    return 'aborted';
+  }
+  const lastVerification = [...tools].reverse().find(record => record.verification);
+  return lastVerification && !lastVerification.success ? 'verification-failed' : null;
+}
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+/**
+ * Suggest what to try next based on how the run failed
+ */
+export function suggestNextApproach(input: PostMortemInput): string[] {
+  const failures = input.tools.filter(record => !record.success);
+  const suggestions: string[] = [];
+
+  if (failures.some(f => f.code === 'PERMISSION_DENIED' || f.code === 'PERMISSION_REQUIRED')) {
+    suggestions.push('Some tools were denied: confirm the approach with the user or switch mode before retrying.');
+  }
+  if (failures.some(f => /not found|no such file|ENOENT/i.test(f.error ?? ''))) {
+    suggestions.push('Paths or strings were not found: re-read the files and search before editing again.');
+  }
+
+  const counts = new Map<string, number>();
+  for (const failure of failures) {
+    const key = `${failure.tool} ${failure.target}`;
+    counts.set(key, (counts.get(key) ?? 0) + 1);
+  }
+  const repeated = [...counts].filter(([, count]) => count > 1).map(([key]) => key);
+  if (repeated.length > 0) {
+    suggestions.push(`Repeated failures (${repeated.join('; ')}): change the approach instead of retrying the same call.`);
+  }
+
+  if (input.reason === 'verification-failed') {
+    suggestions.push('Start from the failing verification output above, fix the smallest cause, and re-run it before anything else.');
+  } else if (input.reason === 'aborted') {
+    suggestions.push('The run was interrupted: review the changed files, then resume from the last successful step.');
+  } else if (input.reason === 'error') {
+    suggestions.push('The run ended on an error: check connectivity/configuration, then retry with a narrower request.');
+  }
+
+  return suggestions;
+}
+
+/**
+ * Render a post-mortem as a scratchpad section
+ */
+export function renderPostMortem(input: PostMortemInput, now: Date = new Date()): string {
+  const title: Record<PostMortemReason, string> = {
+    'aborted': 'run aborted',
+    'verification-failed': 'verification failed',
+    'error': 'run failed',
+  };
+
+  const attempted = input.tools.map(record =>
+    `${record.success ? '✓' : '✗'} ${record.tool}${record.target ? ` \`${record.target}\`` : ''}`
+  );
+  const failures = input.tools.filter(record => !record.success);
+
+  const lines = [
+    `### Post-mortem: ${title[input.reason]} (${now.toISOString()})`,
+    '',
+    `**Task:** ${input.task.split('\n')[0]}`,
+    `**Turns:** ${input.turns}`,
+  ];
+  if (input.runError) {
+    lines.push(`**Error:** ${input.runError}`);
+  }
+
+  lines.push('', '**Attempted:**');
+  lines.push(...(attempted.length > 0 ? attempted.slice(-15).map(a => `- ${a}`) : ['- (no tool calls)']));
+
+  lines.push('', '**Failed tools:**');
+  lines.push(...(failures.length > 0
+    ? failures.map(f => `- ${f.tool}${f.target ? ` \`${f.target}\`` : ''}: ${f.code ? `[${f.code}] ` : ''}${f.error}`)
+    : ['- (none)']));
+
+  if (input.changeSummary) {
+    lines.push('', '**Changes:**', '```', input.changeSummary, '```');
+  }
+
+  lines.push('', '**Suggested next approach:**');
+  lines.push(...suggestNextApproach(input).map(s => `- ${s}`));
+
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Append a post-mortem to .floyd/scratchpad.md under "## Post-mortems"
+ *
+ * @returns The scratchpad path
+ */
+export async function writePostMortem(cwd: string, markdown: string): Promise<string> {
+  const scratchpad = path.join(cwd, '.floyd', 'scratchpad.md');
+  let content = await fs.readFile(scratchpad, 'utf-8').catch(() => '# Current Thinking & Scratchpad (FLOYD)\n');
+
+  if (!content.includes('## Post-mortems')) {
+    content = `${content.trimEnd()}\n\n## Post-mortems\n`;
+  }
+  content = `${content.trimEnd()}\n\n${markdown}`;
+
+  await fs.ensureDir(path.dirname(scratchpad));
+  await fs.writeFile(scratchpad, content, 'utf-8');
+  logger.info('Post-mortem written', { scratchpad });
+  return scratchpad;
+}
@@ -0,0 +1,253 @@
+/**
+ * Replay Harness - Floyd Wrapper
+ *
+ * Golden-run regression testing for the agent loop. A run recorded with
+ * `floyd --record` is split back into the model responses it was made of;
+ * a fake client streams those into a real FloydAgentEngine, and the events
+ * the engine emits can be compared with the recording. Loop changes are
+ * validated this way without API keys.
+ *
+ * Tool calls are answered from the recording by default, so a replay has no
+ * side effects; pass `liveTools` to run them for real.
+ */
+
+import type { FloydConfig, ToolReceipt } from '../types.js';
+import type { GLMStreamOptions, TokenUsage } from '../llm/glm-client.js';
+import { FakeClient, type FakeResponse } from '../llm/fake-client.js';
+import { getEventBroadcaster, type BroadcastEvent } from '../streaming/event-broadcaster.js';
+import { toolRegistry } from '../tools/index.js';
+import { FloydAgentEngine } from './execution-engine.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One model response, rebuilt from a turn of a recording
+ */
+export interface RecordedResponse {
+  /** Streamed text, in the chunks it arrived in */
+  tokens: string[];
+  /** Tool calls the model made, in order */
+  toolUses: Array<{ name: string; input: Record<string, unknown> }>;
+  /** Usage reported for the request, if recorded */
+  usage?: TokenUsage;
+}
+
+/**
+ * Outcome of replaying a recording
+ */
+export interface ReplayResult {
+  /** Final response returned by the engine */
+  response: string;
+  /** Events the engine emitted during the replay */
+  events: BroadcastEvent[];
+  /** Message lists the engine sent, one per request */
+  requests: GLMStreamOptions['messages'][];
+  /** Recorded responses the engine never asked for */
+  unusedResponses: number;
+}
+
+// ============================================================================
+// Recording → Responses
+// ============================================================================
+
+/**
+ * Split a recorded run into the model responses of each turn
+ */
+export function responsesFromRecording(events: BroadcastEvent[]): RecordedResponse[] {
+  const responses: RecordedResponse[] = [];
+  let current: RecordedResponse | null = null;
+
+  for (const event of events) {
+    const { data } = event;
+    if (event.type === 'iteration') {
+      current = { tokens: [], toolUses: [] };
+      responses.push(current);
+      continue;
+    }
+    if (!current) {
+      continue;
+    }
+    if (event.type === 'token') {
+      current.tokens.push(String(data.token ?? ''));
+    } else if (event.type === 'tool_start') {
+      current.toolUses.push({ name: String(data.tool), input: (data.input ?? {}) as Record<string, unknown> });
+    } else if (event.type === 'usage') {
+      current.usage = {
+        inputTokens: Number(data.inputTokens) || 0,
+        outputTokens: Number(data.outputTokens) || 0,
+        totalTokens: Number(data.totalTokens) || 0,
+      };
+    }
+  }
+  return responses;
+}
+
+/**
+ * Recorded tool results, queued per tool call (name and input)
+ */
+function toolResultsFromRecording(events: BroadcastEvent[]): Map<string, unknown[]> {
+  const results = new Map<string, unknown[]>();
+  let pending: string[] = [];
+
+  for (const event of events) {
+    if (event.type === 'tool_start') {
+      pending.push(toolCallKey(String(event.data.tool), event.data.input));
+    } else if (event.type === 'tool_complete') {
+      const index = pending.findIndex(key => key.startsWith(`${event.data.tool}\u0000`));
+      if (index === -1) {
+        continue;
+      }
+      const [key] = pending.splice(index, 1);
+      results.set(key!, [...(results.get(key!) ?? []), event.data.result]);
+    } else if (event.type === 'run_start') {
+      pending = [];
+    }
+  }
+  return results;
+}
+
+function toolCallKey(tool: string, input: unknown): string {
+  return `${tool}\u0000${JSON.stringify(input ?? {})}`;
+}
+
+// ============================================================================
+// Replay Client
+// ============================================================================
+
+/**
+ * Model client that streams recorded responses instead of calling an API
+ */
+export class ReplayClient extends FakeClient {
+  constructor(responses: RecordedResponse[]) {
+    // Recorded usage is the client's running total; replay it per response
+    let previous: TokenUsage = { inputTokens: 0, outputTokens: 0, totalTokens: 0 };
+    super(responses.map((response, turn) => {
+      const usage = response.usage ?? previous;
+      const fake: FakeResponse = {
+        chunks: response.tokens,
+        toolCalls: response.toolUses.map((toolUse, index) => ({ ...toolUse, id: `replay_${turn}_${index}` })),
+        usage: {
+          inputTokens: Math.max(usage.inputTokens - previous.inputTokens, 0),
+          outputTokens: Math.max(usage.outputTokens - previous.outputTokens, 0),
+        },
+      };
+      previous = usage;
+      return fake;
+    }));
+  }
+
+  protected override exhausted(request: number): Error {
+    return new Error(`Replay has no recorded response for request ${request}`);
+  }
+}
+
+// ============================================================================
+// Harness
+// ============================================================================
+
+/**
+ * Run a recording through a fresh engine and collect what it emits
+ *
+ * @param recording - Events of one recorded run (see readRecording)
+ * @param config - Engine configuration; the API settings are never used
+ * @param options.message - Prompt to send (default: the recorded one)
+ * @param options.liveTools - Execute tools instead of replaying their results
+ */
+export async function replayRun(
+  recording: BroadcastEvent[],
+  config: FloydConfig,
+  options: { message?: string; liveTools?: boolean } = {}
+): Promise<ReplayResult> {
+  const message = options.message
+    ?? String(recording.find(event => event.type === 'run_start')?.data.message ?? '');
+  const client = new ReplayClient(responsesFromRecording(recording));
+  const engine = new FloydAgentEngine(config, {}, undefined, client);
+
+  const events: BroadcastEvent[] = [];
+  const stopListening = getEventBroadcaster().addListener(event => events.push(event));
+
+  // Answer tool calls with the recorded results
+  const recordedResults = toolResultsFromRecording(recording);
+  const restoreTools = options.liveTools ? () => {} : stubToolExecution(recordedResults);
+
+  try {
+    const response = await engine.execute(message);
+    return { response, events, requests: client.requests, unusedResponses: client.remaining() };
+  } finally {
+    restoreTools();
+    stopListening();
+  }
+}
+
+/**
+ * Serve tool calls from recorded results until the returned function is called
+ */
+function stubToolExecution(recorded: Map<string, unknown[]>): () => void {
+  const registry = toolRegistry as { executeWithAutoCheckpoint: typeof toolRegistry.executeWithAutoCheckpoint };
+
+  registry.executeWithAutoCheckpoint = async (name: string, input: unknown) => {
+    const now = Date.now();
+    const queue = recorded.get(toolCallKey(name, input));
+    const result = queue?.shift();
+    if (result !== undefined) {
+      return result as ToolReceipt;
+    }
+    return {
+      success: false,
+      error: { code: 'REPLAY_MISSING_RESULT', message: `No recorded result for ${name}` },
+      status: 'error',
+      warnings: [],
+      receipts: [],
+      duration_ms: 0,
+      started_at: now,
+      completed_at: now,
+    };
+  };
+
+  return () => {
+    delete (registry as Partial<typeof registry>).executeWithAutoCheckpoint;
+  };
+}
+
+/**
+ * Compact event sequence for golden comparisons: consecutive tokens are
+ * joined, tools are named, and timing-dependent events are left out
+ */
+export function summarizeEvents(events: BroadcastEvent[]): string[] {
+  const summary: string[] = [];
+  for (const event of events) {
+    switch (event.type) {
+      case 'token': {
+        const token = String(event.data.token ?? '');
+        const last = summary[summary.length - 1];
+        if (last?.startsWith('token:')) {
+          summary[summary.length - 1] = last + token;
+        } else {
+          summary.push(`token:${token}`);
+        }
+        break;
+      }
+      case 'tool_start':
+        summary.push(`tool_start:${event.data.tool}`);
+        break;
+      case 'tool_complete': {
+        const result = event.data.result as { success?: boolean } | undefined;
+        summary.push(`tool_complete:${event.data.tool}:${result?.success === false ? 'error' : 'ok'}`);
+        break;
+      }
+      case 'iteration':
+        summary.push(`iteration:${event.data.turn}`);
+        break;
+      case 'run_start':
+      case 'run_complete':
+      case 'request':
+        summary.push(event.type);
+        break;
+      default:
+        break;
+    }
+  }
+  return summary;
+}
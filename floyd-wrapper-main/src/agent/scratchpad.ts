@@ -0,0 +1,237 @@
+/**
+ * Scratchpad - Floyd Wrapper
+ *
+ * Reading side of .floyd/scratchpad.md: splits it into entries (one per
+ * "###" section, e.g. each post-mortem), searches them, and clusters the
+ * failed tool calls they record by tool and message shape. When a tool
+ * fails, the engine attaches the matching cluster to the tool result so the
+ * model sees how that same error went before - without reading the whole,
+ * ever-growing file.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A failed tool call recorded in a scratchpad entry
+ */
+export interface ScratchpadFailure {
+  tool: string;
+  target: string;
+  code?: string;
+  message: string;
+}
+
+/**
+ * One "###" section of the scratchpad
+ */
+export interface ScratchpadEntry {
+  title: string;
+  /** ISO timestamp from the title, when there is one */
+  timestamp?: string;
+  body: string;
+  failures: ScratchpadFailure[];
+  /** Lines under "Suggested next approach" */
+  suggestions: string[];
+}
+
+/**
+ * Failures of one tool with similar messages
+ */
+export interface ErrorCluster {
+  tool: string;
+  /** Normalized message shared by the failures */
+  signature: string;
+  /** Most recent message as written */
+  example: string;
+  count: number;
+  targets: string[];
+  lastSeen?: string;
+  /** Suggestions from the most recent entry with this failure */
+  suggestions: string[];
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+export const SCRATCHPAD_FILE = path.join('.floyd', 'scratchpad.md');
+
+/**
+ * Share of message words two failures need in common to be clustered
+ */
+const SIMILARITY_THRESHOLD = 0.6;
+
+// ============================================================================
+// Parsing & Search
+// ============================================================================
+
+/**
+ * Split scratchpad markdown into entries
+ */
+export function parseScratchpad(markdown: string): ScratchpadEntry[] {
+  const entries: ScratchpadEntry[] = [];
+
+  for (const section of markdown.split(/^(?=### )/m).filter(part => part.startsWith('### '))) {
+    const [heading, ...rest] = section.split('\n');
+    const body = rest.join('\n').split(/^## /m)[0].trim();
+    const title = heading.replace(/^###\s+/, '').trim();
+    const timestamp = title.match(/\((\d{4}-\d{2}-\d{2}T[\d:.]+Z)\)/)?.[1];
+
+    const failures: ScratchpadFailure[] = [];
+    const suggestions: string[] = [];
+    let block = '';
+    for (const line of body.split('\n')) {
+      const label = line.match(/^\*\*(.+?):\*\*\s*$/);
+      if (label) {
+        block = label[1].toLowerCase();
+        continue;
+      }
+      if (block === 'failed tools') {
+        const failure = line.match(/^- (\S+)(?: `([^`]*)`)?: (?:\[([A-Z0-9_]+)\] )?(.+)$/);
+        if (failure) {
+          failures.push({ tool: failure[1], target: failure[2] ?? '', code: failure[3], message: failure[4].trim() });
+        }
+      } else if (block === 'suggested next approach' && line.startsWith('- ')) {
+        suggestions.push(line.slice(2).trim());
+      }
+    }
+
+    entries.push({ title, timestamp, body, failures, suggestions });
+  }
+
+  return entries;
+}
+
+/**
+ * Read the scratchpad of a project; empty when there is none
+ */
+export async function readScratchpad(cwd: string = process.cwd()): Promise<ScratchpadEntry[]> {
+  const content = await fs.readFile(path.join(cwd, SCRATCHPAD_FILE), 'utf-8').catch(() => '');
+  return parseScratchpad(content);
+}
+
+/**
+ * Entries mentioning every word of the query (case-insensitive), newest first
+ */
+export function searchScratchpad(entries: ScratchpadEntry[], query: string): ScratchpadEntry[] {
+  const words = query.toLowerCase().split(/\s+/).filter(Boolean);
+  return entries
+    .filter(entry => {
+      const text = `${entry.title}\n${entry.body}`.toLowerCase();
+      return words.every(word => text.includes(word));
+    })
+    .reverse();
+}
+
+// ============================================================================
+// Error Clustering
+// ============================================================================
+
+/**
+ * Reduce an error message to its shape: quoted text, paths and numbers are
+ * replaced so the same error on different inputs compares equal
+ */
+export function errorSignature(message: string): string {
+  return message
+    .toLowerCase()
+    .replace(/(["'`]).*?\1/g, '<str>')
+    .replace(/(?:[a-z]:)?[\w.-]*[\\/][\w./\\-]+/g, '<path>')
+    .replace(/\b[\w-]+\.[a-z]{1,5}\b/g, '<path>')
+    .replace(/\b0x[0-9a-f]+\b/g, '<hex>')
+    .replace(/\d+/g, '<n>')
+    .replace(/\s+/g, ' ')
+    .trim()
+    .slice(0, 160);
+}
+
+function similarity(a: string, b: string): number {
+  if (a === b) {
+    return 1;
+  }
+  const wordsA = new Set(a.split(' '));
+  const wordsB = new Set(b.split(' '));
+  const shared = [...wordsA].filter(word => wordsB.has(word)).length;
+  return shared / new Set([...wordsA, ...wordsB]).size;
+}
+
+/**
+ * Group the failures of all entries by tool and similar message, most
+ * frequent first
+ */
+export function clusterErrors(entries: ScratchpadEntry[]): ErrorCluster[] {
+  const clusters: ErrorCluster[] = [];
+
+  for (const entry of entries) {
+    for (const failure of entry.failures) {
+      const signature = errorSignature(failure.message);
+      const cluster = clusters.find(candidate =>
+        candidate.tool === failure.tool && similarity(candidate.signature, signature) >= SIMILARITY_THRESHOLD
+      );
+
+      if (cluster) {
+        cluster.count++;
+        cluster.example = failure.message;
+        cluster.lastSeen = entry.timestamp ?? cluster.lastSeen;
+        cluster.suggestions = entry.suggestions.length > 0 ? entry.suggestions : cluster.suggestions;
+        if (failure.target && !cluster.targets.includes(failure.target)) {
+          cluster.targets.push(failure.target);
+        }
+      } else {
+        clusters.push({
+          tool: failure.tool,
+          signature,
+          example: failure.message,
+          count: 1,
+          targets: failure.target ? [failure.target] : [],
+          lastSeen: entry.timestamp,
+          suggestions: entry.suggestions,
+        });
+      }
+    }
+  }
+
+  return clusters.sort((a, b) => b.count - a.count);
+}
+
+/**
+ * The cluster a new failure belongs to, if it happened before
+ */
+export function findErrorCluster(clusters: ErrorCluster[], tool: string, message: string): ErrorCluster | null {
+  const signature = errorSignature(message);
+  let best: ErrorCluster | null = null;
+  let bestScore = SIMILARITY_THRESHOLD;
+
+  for (const cluster of clusters) {
+    if (cluster.tool !== tool) {
+      continue;
+    }
+    const score = similarity(cluster.signature, signature);
+    if (score >= bestScore) {
+      best = cluster;
+      bestScore = score;
+    }
+  }
+  return best;
+}
+
+/**
+ * Short note for the model about earlier occurrences of an error
+ */
+export function formatErrorCluster(cluster: ErrorCluster): string {
+  const lines = [
+    `This ${cluster.tool} error has failed ${cluster.count} time(s) in earlier runs (see ${SCRATCHPAD_FILE}): ${cluster.example}`,
+  ];
+  if (cluster.targets.length > 0) {
+    lines.push(`Targets: ${cluster.targets.slice(-5).join(', ')}`);
+  }
+  if (cluster.suggestions.length > 0) {
+    lines.push('What the last post-mortem suggested:', ...cluster.suggestions.map(suggestion => `- ${suggestion}`));
+  }
+  lines.push('Change the approach rather than repeating the same call.');
+  return lines.join('\n');
+}
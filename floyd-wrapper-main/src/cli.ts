@@ -8,11 +8,17 @@
 
 import meow from 'meow';
 import readline from 'node:readline';
+import os from 'node:os';
+import path from 'node:path';
+import fs from 'fs-extra';
 import { onExit } from 'signal-exit';
 import { config as dotenvConfig } from 'dotenv';
 import { FloydAgentEngine } from './agent/execution-engine.js';
+import { getUntrustedProjectHooks, trustProjectHooks, HOOKS_FILE } from './agent/hooks.js';
 import { loadConfig, loadProjectContext /*, loadFloydIgnore */ } from './utils/config.js';
-import { logger } from './utils/logger.js';
+import { loadUserConfig } from './utils/user-config.js';
+import { needsSetup, runSetupWizard } from './ui/setup-wizard.js';
+import { logger, initLogger } from './utils/logger.js';
 import { FloydTerminal } from './ui/terminal.js';
 import { SessionManager } from './persistence/session-manager.js';
 import chalk from 'chalk';
@@ -23,8 +29,48 @@ import { StreamingDisplay, ConversationHistory } from './ui/rendering.js';
 import { renderMarkdown } from './ui/formatters.js';
 import { getMessageQueue } from './ui/message-queue.js';
 import { getMonitoringModule } from './ui/monitoring-module.js';
-import { getInterruptManager, type InterruptEvent } from './interrupts/index.js';
+import { getInterruptManager, getShutdownController, type InterruptEvent } from './interrupts/index.js';
 import { createInstanceLock } from './utils/instance-lock.js';
+import { getEventBroadcaster } from './streaming/event-broadcaster.js';
+import { watchSession } from './streaming/session-viewer.js';
+import { RunRecorder, getRunsDir, isRecordingEnabled, resolveRecording, readRecording, replayRecording } from './streaming/run-recorder.js';
+import { AuditLog, getAuditLogPath, isAuditLogEnabled } from './streaming/audit-log.js';
+import { getBranchNotes } from './persistence/branch-notes.js';
+import { TranscriptExporter, parseTranscriptFormat } from './persistence/transcript-exporter.js';
+import { setAskUserHandler } from './tools/system/index.js';
+import { closeAllShellSessions } from './tools/system/shell-session.js';
+import { setEnvValueHandler } from './tools/system/env.js';
+import { setAutoFormat } from './tools/system/format.js';
+import { toolRegistry } from './tools/tool-registry.js';
+import { setToolOutputHandler, formatLineCount, type ToolOutputBatch } from './streaming/tool-output.js';
+import { getRunStatusReporter, readRunStatus, formatRunStatus, formatRunStatusPorcelain } from './utils/run-status.js';
+import { getTracer } from './utils/tracing.js';
+import { getProfile, loadProfiles, applyProfile, formatProfile } from './utils/profiles.js';
+import { getToolProfile, loadToolProfiles, formatToolProfile } from './utils/tool-profiles.js';
+import { getDiffPreviewer, type DiffPreview, type DiffPreviewDecision } from './permissions/diff-preview.js';
+import { setDryRun } from './permissions/dry-run.js';
+import { initialRunState, reduceRunEvent, describeRunError, type RunEvent, type RunState } from 'floyd-agent-core/ui';
+import {
+  formatToolStats,
+  isSecretCommand,
+  loadInputHistory,
+  appendInputHistory,
+  searchInputHistory,
+  type FileChange,
+} from 'floyd-agent-core/utils';
+import { StreamTagParser, type TagEvent } from './streaming/tag-parser.js';
+import { getThemeManager } from './ui/theme.js';
+import { detectOffline, describeOffline, OFFLINE_HINT, type OfflineReason } from './utils/offline.js';
+import { OfflineClient } from './llm/offline-client.js';
+import type { BudgetDecision, BudgetExceeded } from './agent/budget-manager.js';
+import type { CompactionResult } from './agent/auto-compact.js';
+import { getTodoList } from './tools/todo/todo-core.js';
+import { renderTodoPanel } from './ui/todo-panel.js';
+import { ContextWatcher } from './utils/context-watcher.js';
+import { openInEditor } from './utils/editor.js';
+import { Notifier, notifierSettings } from './ui/notifier.js';
+import { renderChangeSummaryPanel } from './ui/change-summary-panel.js';
+import { loadTaskFile, processExecutor, runBatch, type BatchTask } from './agent/batch-runner.js';
 
 // Load environment variables from multiple possible locations
 const envPaths = [
@@ -54,19 +100,42 @@ const cli = meow(
   `
   Usage
     $ floyd [options]
+    $ floyd status [--porcelain]
+    $ floyd watch [url]
+    $ floyd auth [status|login|test|logout] [provider]
+    $ floyd setup
+    $ floyd run <tasks.yaml> [--parallel n]
+
+  Commands
+    status        Show the current run state (idle, running, awaiting-approval, done)
+    watch         Follow a running session read-only (needs FLOYD_EVENTS_PORT on that session)
+    auth          Store API keys in the OS keychain (providers: zai/glm, anthropic, openai, deepseek)
+    setup         Pick provider, key, model and theme (runs by itself on the first launch)
+    run           Work through a YAML/JSON list of goals headlessly, one session each
 
   Options
     --debug       Enable debug logging
     --tui         Launch full TUI mode (Ink-based UI)
     --bridge      Start mobile bridge server
     --resume      Resume specific session (id or name)
+    --export      Export a session transcript (md or html) to .floyd/exports/ and exit
+    --record      Record each run's events to .floyd/runs/<id>.jsonl
+    --replay      Play back a recorded run (id or file, latest by default) without calling the API
+    --speed       Replay speed multiplier (0 = instant, default 1)
     --mode        Set initial execution mode (ask, yolo, plan, auto, dialogue)
+    --profile     Use a run profile (quick-answer, deep-refactor, ci-safe, glm-coding, anthropic, or .floyd/profiles.json)
+    --tool-profile Lock the session to a tool subset (reader, editor, admin, or .floyd/tool-profiles.json)
     --flash       Use Flash mode (glm-4-flash - fast & cheap)
     --floyd47     Use Floyd 4.7 GLM-optimized prompt
     --claude      Use Claude-style prompt
     --hardened    Use hardened prompt system
     --no-reasoning Disable reasoning for simple tasks (GLM-4.7 optimization)
     --force       Override instance lock (use with caution)
+    --offline     Start without model calls; tools stay available via /run, /read and /tool
+    --dry-run     Simulate tool calls that would change something (toggle with /dryrun)
+    --porcelain   Machine-readable status output (with "status")
+    --no-verify   Store a key without a test request (with "auth login")
+    --parallel    Tasks to run at once (with "run", default 1)
     --version     Show version number
 
   Examples
@@ -75,13 +144,26 @@ const cli = meow(
     $ floyd --bridge         # Start mobile bridge server
     $ floyd --debug
     $ floyd --mode yolo      # Start in YOLO mode
+    $ floyd --profile deep-refactor  # Model, tools, budgets and verbosity in one go
+    $ floyd --profile anthropic      # Switch provider, endpoint and key (see /profile)
+    $ floyd --tool-profile reader    # Read-only: no writes, shell commands or network
     $ floyd --flash          # Use Flash mode (fast & cheap)
     $ floyd --floyd47        # Use Floyd 4.7 GLM-optimized prompt
     $ floyd --claude         # Use Claude-style prompt
     $ floyd --hardened       # Use hardened prompt system
     $ floyd --no-reasoning   # Disable reasoning (faster simple tasks)
     $ floyd --force          # Override existing instance lock
+    $ floyd --offline        # No API key or network needed
+    $ floyd --dry-run        # Audit a plan: commands and edits are only reported
+    $ floyd --export html    # Export the latest session as HTML
+    $ floyd --export md --resume my-session
+    $ floyd --record         # Keep a replayable recording of every run
+    $ floyd --replay --speed 4
     $ floyd-tui              # Alternative way to launch TUI
+    $ floyd status --porcelain  # For tmux/starship: state<TAB>seconds<TAB>pid<TAB>cwd
+    $ floyd watch               # Pair-programming: watch the running session live
+    $ floyd auth login glm      # Prompt for a key, test it and keep it in the keychain
+    $ floyd run tasks.yaml --parallel 3  # Transcripts and summary in .floyd/batch/
 `,
   {
     importMeta: import.meta,
@@ -101,9 +183,29 @@ const cli = meow(
       resume: {
         type: 'string',
       },
+      export: {
+        type: 'string',
+      },
+      record: {
+        type: 'boolean',
+        default: false,
+      },
+      replay: {
+        type: 'string',
+      },
+      speed: {
+        type: 'number',
+        default: 1,
+      },
       mode: {
         type: 'string',
       },
+      profile: {
+        type: 'string',
+      },
+      toolProfile: {
+        type: 'string',
+      },
       suggested: {
         type: 'boolean',
         default: false,
@@ -132,6 +234,26 @@ const cli = meow(
         type: 'boolean',
         default: false,
       },
+      offline: {
+        type: 'boolean',
+        default: false,
+      },
+      dryRun: {
+        type: 'boolean',
+        default: false,
+      },
+      porcelain: {
+        type: 'boolean',
+        default: false,
+      },
+      verify: {
+        type: 'boolean',
+        default: true,
+      },
+      parallel: {
+        type: 'number',
+        default: 1,
+      },
     },
   }
 );
@@ -145,18 +267,33 @@ const cli = meow(
  */
 export class FloydCLI {
   private engine?: FloydAgentEngine;
+  private contextWatcher?: ContextWatcher;
+  /** Bell / OS notification when long runs end or wait for approval */
+  private notifier = new Notifier(notifierSettings(loadUserConfig()));
   private sessionManager?: SessionManager;
   private rl?: readline.Interface;
   private config?: Awaited<ReturnType<typeof loadConfig>>;
   private terminal: FloydTerminal;
   private streamingDisplay: StreamingDisplay;
   private conversationHistory = new ConversationHistory();
+  // Current turn, tracked with the same reducer as the Ink TUI
+  private runState: RunState = initialRunState();
+  private tagParser = new StreamTagParser(['thinking', 'think']);
+  private activeToolId?: string;
+  private inputHistory: string[] = [];
+  private isSearchingHistory = false;
   private messageQueue = getMessageQueue();
   private isRunning: boolean = false;
   private sigintHandler?: () => void;
   private onExitCleanup?: () => void;
+  private shutdownSteps: Array<() => void> = [];
   private testMode: boolean = false;
   private instanceLock?: ReturnType<typeof createInstanceLock>;
+  private toolOutputCounterShown = false;
+  private recorder?: RunRecorder;
+  private auditLog?: AuditLog;
+  /** Why model calls are disabled, when starting offline */
+  private offlineReason: OfflineReason | null = null;
 
   constructor(options?: { testMode?: boolean }) {
     this.terminal = FloydTerminal.getInstance();
@@ -169,6 +306,11 @@ export class FloydCLI {
    */
   async initialize(): Promise<void> {
     try {
+      // First launch: ask for provider, key, model and theme before loading them
+      if (!this.testMode && process.stdin.isTTY && !cli.flags.offline && needsSetup()) {
+        await runSetupWizard();
+      }
+
       // Load configuration
       this.config = await loadConfig();
 
@@ -199,6 +341,21 @@ export class FloydCLI {
         logger.debug('Loaded project context');
       }
 
+      // Keep .floyd/branch.md pointed at the working branch
+      await getBranchNotes().recordBranch();
+
+      // FLOYD_SANDBOX=overlay isolates every file write of this run
+      if (process.env.FLOYD_SANDBOX === 'overlay') {
+        const session = await getSandboxManager().start(projectRoot, 'overlay');
+        this.terminal.muted(`Overlay sandbox active (${session.id}); use /sandbox commit or /sandbox patch when done`);
+      }
+
+      // --dry-run simulates every tool call that would change something
+      if (cli.flags.dryRun) {
+        setDryRun(true);
+        this.terminal.warning('Dry run: writes, edits and commands are simulated, not executed (/dryrun to toggle)');
+      }
+
       // FloydIgnorePatterns - DISABLED
       /*
       if (ignorePatterns.length > 0) {
@@ -208,6 +365,28 @@ export class FloydCLI {
       */
 
 
+      // Structured log file (.floyd/logs/floyd.log) for /logs
+      initLogger(projectRoot);
+
+      // Replayable recordings of each run (--record or FLOYD_RECORD_RUNS)
+      if (cli.flags.record || isRecordingEnabled()) {
+        this.recorder = new RunRecorder(getRunsDir(projectRoot));
+        this.recorder.attach(getEventBroadcaster());
+        this.terminal.muted(`Recording runs to ${path.relative(projectRoot, getRunsDir(projectRoot))}/`);
+      }
+
+      // Append-only audit trail of requests, tool calls and file changes (FLOYD_AUDIT_LOG)
+      if (isAuditLogEnabled()) {
+        this.auditLog = new AuditLog(getAuditLogPath(projectRoot));
+        this.auditLog.attach(getEventBroadcaster());
+        this.terminal.muted(`Audit log: ${path.relative(projectRoot, this.auditLog.getPath())}`);
+      }
+
+      // Theme from ~/.floyd/themes/ (FLOYD_THEME or config.toml picks one; /theme switches)
+      for (const problem of getThemeManager({ theme: process.env.FLOYD_THEME || loadUserConfig()?.theme }).errors) {
+        logger.warn(`Theme: ${problem}`);
+      }
+
       // Set log level based on flags (default to 'warn' for clean startup)
       if (cli.flags.debug) {
         this.config.logLevel = 'debug';
@@ -216,6 +395,37 @@ export class FloydCLI {
         logger.setLevel('warn'); // Only show warnings and errors by default
       }
 
+      // A profile bundles connection, model, tools, budgets, mode and verbosity;
+      // explicit flags below still override it
+      if (cli.flags.profile) {
+        const profile = getProfile(cli.flags.profile, projectRoot);
+        if (profile) {
+          this.config = applyProfile(this.config, profile);
+          if (profile.verbosity && !cli.flags.debug) {
+            logger.setLevel(profile.verbosity);
+          }
+          this.terminal.muted(`Profile: ${profile.name} (${formatProfile(profile)})`);
+        } else {
+          const names = Object.keys(loadProfiles(projectRoot)).join(', ');
+          this.terminal.warning(`Unknown profile: ${cli.flags.profile}. Available: ${names}`);
+        }
+      }
+
+      // A tool profile locks the session to a subset of the tools; an unknown
+      // name stops here rather than running with every tool
+      if (cli.flags.toolProfile) {
+        this.config.toolProfile = cli.flags.toolProfile;
+      }
+      if (this.config.toolProfile) {
+        const toolProfile = getToolProfile(this.config.toolProfile, projectRoot);
+        if (!toolProfile) {
+          const names = Object.keys(loadToolProfiles(projectRoot)).join(', ');
+          this.terminal.error(`Unknown tool profile: ${this.config.toolProfile}. Available: ${names}`);
+          throw new Error(`Unknown tool profile: ${this.config.toolProfile}`);
+        }
+        this.terminal.muted(`Tool profile: ${toolProfile.name} (${formatToolProfile(toolProfile)})`);
+      }
+
       // Set execution mode from CLI flag if provided
       if (cli.flags.mode) {
         const validModes = ['ask', 'yolo', 'plan', 'auto', 'dialogue', 'fuckit'];
@@ -260,6 +470,17 @@ export class FloydCLI {
         mode: process.env.FLOYD_MODE || 'ask',
       });
 
+      // Without an API key or network, start with model calls disabled
+      // instead of failing (tests never probe the network)
+      this.offlineReason = await detectOffline(
+        this.config,
+        cli.flags.offline,
+        this.testMode ? async () => true : undefined
+      );
+      if (this.offlineReason) {
+        logger.warn('Starting offline', { reason: this.offlineReason });
+      }
+
       // Initialize Session Manager
       this.sessionManager = new SessionManager(process.cwd());
       let currentSession;
@@ -308,6 +529,60 @@ export class FloydCLI {
         slashCommands.register(cmd);
       }
 
+      // Register /progress
+      const { progressCommands } = await import('./commands/progress-commands.js');
+      for (const cmd of progressCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /scratchpad
+      const { scratchpadCommands } = await import('./commands/scratchpad-commands.js');
+      for (const cmd of scratchpadCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /todo
+      const { todoCommands } = await import('./commands/todo-commands.js');
+      for (const cmd of todoCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /branch (conversation branches; /checkpoint save and /rewind use them too)
+      const { conversationCommands } = await import('./commands/conversation-commands.js');
+      for (const cmd of conversationCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /status and /pr
+      const { branchCommands } = await import('./commands/branch-commands.js');
+      for (const cmd of branchCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /profile
+      const { profileCommands } = await import('./commands/profile-commands.js');
+      for (const cmd of profileCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /auth
+      const { authCommands } = await import('./commands/auth-commands.js');
+      for (const cmd of authCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /prompt
+      const { promptCommands } = await import('./commands/prompt-commands.js');
+      for (const cmd of promptCommands) {
+        slashCommands.register(cmd);
+      }
+
+      // Register /run, /read and /tool (direct tool calls, also offline)
+      const { directCommands } = await import('./commands/direct-commands.js');
+      for (const cmd of directCommands) {
+        slashCommands.register(cmd);
+      }
+
       // FIX #4: Register tools visibility commands
       const { toolsCommands } = await import('./commands/tools-commands.js');
       for (const cmd of toolsCommands) {
@@ -402,7 +677,8 @@ export class FloydCLI {
         // Do NOT wait for start() to attach handlers
         this.rl.on('line', async (line: string) => {
           // Save to history file
-          if (line.trim()) {
+          // Lines carrying an API key (/auth login) are never saved
+          if (line.trim() && !isSecretCommand(line)) {
             await this.appendToHistory(line.trim());
           }
 
@@ -424,11 +700,11 @@ export class FloydCLI {
           // But we need to wait for async processing to complete
           if (process.stdin.isTTY) {
             // TTY mode: shutdown immediately
-            this.shutdown();
+            await this.shutdown();
           } else {
             // Piped mode: wait for message queue to flush before shutdown
             await this.messageQueue.waitForQueue();
-            this.shutdown();
+            await this.shutdown();
           }
         });
 
@@ -446,11 +722,10 @@ export class FloydCLI {
         onToken: (token: string) => {
           // Update state to streaming when receiving tokens
           interruptMgr.setState('streaming');
-          // Pass token to streaming display - it handles styling logic internally
-          // Note: Ideally StreamingDisplay should handle <think> parsing,
-          // but for now we'll just stream it.
-          // FUTURE: We should detect <think> here if we want to change color *before* printing.
-          this.streamingDisplay.appendToken(token);
+          // Thinking blocks go to the monitoring module, the reply to the display
+          for (const event of this.tagParser.process(token)) {
+            this.handleTagEvent(event);
+          }
         },
         onToolStart: (tool: string, input: Record<string, unknown>) => {
           // Update state to tool executing
@@ -462,10 +737,17 @@ export class FloydCLI {
           // Update monitoring module with tool info
           const monitoring = getMonitoringModule();
           monitoring.setTool(tool);
+          // Tools run one at a time, so the next completion belongs to this call
+          this.activeToolId = `${tool}-${Date.now()}`;
+          this.dispatchRun({ type: 'tool_started', id: this.activeToolId, name: tool, at: Date.now() });
           logger.debug('Tool started', { tool, input });
         },
         onToolComplete: (tool: string, result: unknown) => {
           logger.debug('Tool completed', { tool, result });
+          if (this.activeToolId) {
+            this.dispatchRun({ type: 'tool_finished', id: this.activeToolId, ...describeToolResult(result), at: Date.now() });
+            this.activeToolId = undefined;
+          }
           // Return to thinking state (waiting for next LLM response)
           interruptMgr.setState('thinking');
           // Clear tool from monitoring module
@@ -503,7 +785,81 @@ export class FloydCLI {
           this.terminal.info(`🔄 AUTO mode: Switching to ${toMode.toUpperCase()} behavior for ${toolName}`);
           this.terminal.muted(`  Complex task detected - write operations will be blocked`);
         },
-      }, this.sessionManager);
+        onChangeSummary: (_summary: string, changes: FileChange[]) => {
+          if (this.streamingDisplay.isActive()) {
+            this.streamingDisplay.finish();
+          }
+          console.log(renderChangeSummaryPanel(changes));
+        },
+        onCompaction: (result: CompactionResult, archiveKey: string) => {
+          this.terminal.info(
+            `Context compacted: ${result.compactedCount} older messages summarized (~${result.tokensBefore.toLocaleString('en-US')} → ~${result.tokensAfter.toLocaleString('en-US')} tokens)`
+          );
+          this.terminal.muted(`  Full history archived in the vault cache tier as ${archiveKey}`);
+        },
+        onPostMortem: (scratchpadPath: string) => {
+          this.terminal.warning(`Post-mortem written to ${path.relative(process.cwd(), scratchpadPath)}`);
+        },
+        onBudgetExceeded: async (exceeded: BudgetExceeded) => {
+          // Nobody to ask: stop at the limit
+          if (!process.stdin.isTTY) {
+            this.terminal.warning(`Run stopped: ${exceeded.message} reached`);
+            return 'abort';
+          }
+
+          if (this.streamingDisplay.isActive()) {
+            this.streamingDisplay.finish();
+          }
+          if (this.rl) {
+            this.rl.pause();
+          }
+
+          this.notifier.notify('approval', 'The run reached its budget and is waiting for your decision');
+          getRunStatusReporter().set('awaiting-approval', 'budget');
+          const decision = await this.promptForBudgetDecision(exceeded);
+          getRunStatusReporter().set('running');
+
+          if (this.rl) {
+            try {
+              this.rl.resume();
+            } catch (error) {
+              logger.debug('Failed to resume readline (likely closed)', { error });
+            }
+          }
+
+          return decision;
+        },
+      }, this.sessionManager, this.offlineReason ? new OfflineClient(describeOffline(this.offlineReason, this.config)) : undefined);
+
+      // Show the model's task list whenever the todo tool changes it
+      getTodoList().subscribe(snapshot => {
+        if (snapshot.items.length === 0) {
+          return;
+        }
+        if (this.streamingDisplay.isActive()) {
+          this.streamingDisplay.finish();
+        }
+        // Start on a clean line if a spinner is showing
+        process.stdout.write('\r' + ' '.repeat(100) + '\r');
+        console.log(renderTodoPanel(snapshot));
+      });
+
+      // Pick up edits to the instruction files and .floyd/ plan made outside FLOYD
+      this.contextWatcher = new ContextWatcher(this.config.cwd, (files) => {
+        if (!this.engine?.reloadProjectContext()) {
+          return;
+        }
+        getEventBroadcaster().emit('context_updated', { files });
+        // Mid-run changes are usually FLOYD's own (e.g. the todo tool's plan mirror)
+        if (interruptMgr.getState() === 'idle') {
+          this.terminal.muted(`Context updated: ${files.join(', ')}`);
+        }
+      });
+      this.contextWatcher.start();
+
+      // ~/.floyd/hooks.toml, plus .floyd/hooks.toml once the user trusts it
+      await this.confirmProjectHooks();
+      void this.engine.runHooks('session_start', { mode: process.env.FLOYD_MODE || 'ask' });
 
       // Import permission manager and set up proper permission prompting
       const { permissionManager } = await import('./permissions/permission-manager.js');
@@ -525,9 +881,12 @@ export class FloydCLI {
 
         // Show permission prompt
         console.log(prompt);
+        this.notifier.notify('approval', `A ${permissionLevel} tool call is waiting for your approval`);
 
         // Create temporary readline for permission input
+        getRunStatusReporter().set('awaiting-approval', permissionLevel);
         const response = await this.promptForPermission(permissionLevel);
+        getRunStatusReporter().set('running');
 
         // Resume main readline
         if (this.rl) {
@@ -543,6 +902,92 @@ export class FloydCLI {
         return response;
       });
 
+      // Let the ask_user tool pause the run and collect an answer
+      setAskUserHandler(async (question: string, options?: string[]) => {
+        if (!process.stdin.isTTY) {
+          return 'User input not available in non-interactive mode';
+        }
+
+        if (this.rl) {
+          this.rl.pause();
+        }
+
+        this.notifier.notify('approval', 'FLOYD is waiting for your answer');
+        getRunStatusReporter().set('awaiting-approval', 'question');
+        const answer = await this.promptForAnswer(question, options);
+        getRunStatusReporter().set('running');
+
+        if (this.rl) {
+          try {
+            this.rl.resume();
+          } catch (error) {
+            logger.debug('Failed to resume readline (likely closed)', { error });
+          }
+        }
+
+        return answer;
+      });
+
+      // Let the env tool ask for missing variables without seeing secret values
+      setEnvValueHandler(async (name: string, reason: string | undefined, sensitive: boolean) => {
+        if (!process.stdin.isTTY) {
+          return null;
+        }
+
+        if (this.rl) {
+          this.rl.pause();
+        }
+
+        this.notifier.notify('approval', `FLOYD is waiting for a value for ${name}`);
+        getRunStatusReporter().set('awaiting-approval', 'question');
+        const value = await this.promptForEnvValue(name, reason, sensitive);
+        getRunStatusReporter().set('running');
+
+        if (this.rl) {
+          try {
+            this.rl.resume();
+          } catch (error) {
+            logger.debug('Failed to resume readline (likely closed)', { error });
+          }
+        }
+
+        return value;
+      });
+
+      // Show streamed tool output as dimmed lines with a live line counter
+      setToolOutputHandler((batch: ToolOutputBatch) => this.renderToolOutput(batch));
+
+      // Run the project formatter after write/edit tools (FLOYD_AUTO_FORMAT)
+      setAutoFormat(this.config.autoFormat);
+
+      // Review write/edit changes as diffs before they reach the disk
+      getDiffPreviewer().setEnabled(this.config.diffPreview);
+      getDiffPreviewer().setHandler(async (preview: DiffPreview) => {
+        // Nobody to ask: permissions already decided whether the tool may run
+        if (!process.stdin.isTTY) {
+          return { action: 'accept' };
+        }
+
+        if (this.rl) {
+          this.rl.pause();
+        }
+
+        this.notifier.notify('approval', `A change to ${preview.filePath} is waiting for your review`);
+        getRunStatusReporter().set('awaiting-approval', 'diff');
+        const decision = await this.promptForDiffDecision(preview);
+        getRunStatusReporter().set('running');
+
+        if (this.rl) {
+          try {
+            this.rl.resume();
+          } catch (error) {
+            logger.debug('Failed to resume readline (likely closed)', { error });
+          }
+        }
+
+        return decision;
+      });
+
       // Handle Ctrl+C gracefully (skip in test mode)
       if (!this.testMode) {
         this.setupSignalHandlers();
@@ -573,6 +1018,7 @@ export class FloydCLI {
       switch (event.action) {
         case 'force_exit':
           console.log('\n\n⚠️  Force exit requested. Shutting down immediately...');
+          getShutdownController().killProcessesSync();
           this.cleanup();
           process.exit(0);
           break;
@@ -606,6 +1052,9 @@ export class FloydCLI {
             (this.engine as any).abortController.abort('User interrupt via CTRL-C');
           }
 
+          // Commands run in their own process group, so Ctrl+C does not reach them
+          void getShutdownController().terminateProcesses();
+
           // Finish streaming display
           if (this.streamingDisplay.isActive()) {
             this.streamingDisplay.finish();
@@ -640,8 +1089,55 @@ export class FloydCLI {
     // Store the dispose function returned by onExit
     this.onExitCleanup = onExit(() => {
       interruptManager.cleanup();
+      // Exit handlers cannot wait, so child processes get SIGKILL
+      getShutdownController().killProcessesSync();
       this.cleanup();
     });
+
+    this.registerShutdownSteps();
+  }
+
+  /**
+   * Register this CLI's steps in the shutdown pipeline
+   * (child processes are stopped by the controller after 'cancel')
+   */
+  private registerShutdownSteps(): void {
+    const controller = getShutdownController();
+
+    this.shutdownSteps = [
+      controller.register('cancel', 'agent turn', () => {
+        if (this.engine && (this.engine as any).abortController) {
+          (this.engine as any).abortController.abort('Shutting down');
+        }
+      }),
+      controller.register('cancel', 'shell sessions', () => closeAllShellSessions()),
+      controller.register('cancel', 'mcp servers', async () => {
+        const { mcpManager } = await import('./mcp/mcp-manager.js');
+        await mcpManager.disconnectAll();
+      }),
+      controller.register('persist', 'traces', () => getTracer().flush()),
+      controller.register('persist', 'context watcher', () => this.contextWatcher?.stop()),
+      controller.register('persist', 'event stream', () => getEventBroadcaster().stop()),
+      controller.register('persist', 'run recording', () => this.recorder?.detach()),
+      controller.register('persist', 'audit log', () => this.auditLog?.detach()),
+      controller.register('persist', 'run status', () => getRunStatusReporter().clear()),
+      // FIX #6: Release instance lock on shutdown
+      controller.register('persist', 'instance lock', () => this.instanceLock?.release()),
+      controller.register('restore', 'terminal', () => {
+        // Finish any active streaming
+        if (this.streamingDisplay.isActive()) {
+          this.streamingDisplay.finish();
+        }
+
+        // Clean up terminal elements (includes cursor restoration)
+        this.terminal.cleanup();
+
+        // Ensure cursor is visible before closing
+        this.showCursor();
+
+        this.rl?.close();
+      }),
+    ];
   }
 
   /**
@@ -657,6 +1153,10 @@ export class FloydCLI {
       this.onExitCleanup();
       this.onExitCleanup = undefined;
     }
+    for (const unregister of this.shutdownSteps) {
+      unregister();
+    }
+    this.shutdownSteps = [];
   }
 
   /**
@@ -759,6 +1259,12 @@ export class FloydCLI {
       // Add our keypress listener WITHOUT removing readline's listener
       // This allows normal typing to continue working
       input.on('keypress', (_str: string, key: any) => {
+        // Ctrl+R: fuzzy search prompt history
+        if (key && key.ctrl && key.name === 'r') {
+          void this.reverseSearchHistory();
+          return;
+        }
+
         // Detect Shift+Tab: key.name === 'tab' && key.shift
         if (key && key.name === 'tab' && key.shift) {
           // Get current mode
@@ -938,6 +1444,43 @@ export class FloydCLI {
   /**
    * Prompt user to continue in dialogue mode
    */
+  /**
+   * Ask before running a project's .floyd/hooks.toml, so a checked-out
+   * repository can't run commands just by being opened. The answer is
+   * remembered until the file changes.
+   */
+  private async confirmProjectHooks(): Promise<void> {
+    const pending = getUntrustedProjectHooks(this.config.cwd);
+    if (pending.length === 0) {
+      return;
+    }
+    if (!process.stdin.isTTY) {
+      this.terminal.warning(`Not running ${HOOKS_FILE}: its hooks are not trusted yet (start floyd interactively to review them)`);
+      return;
+    }
+
+    console.log('');
+    console.log(chalk.hex(CRUSH_THEME.colors.warning).bold(`This project's ${HOOKS_FILE} runs commands:`));
+    for (const hook of pending) {
+      console.log(chalk.hex(CRUSH_THEME.colors.muted)(`  ${hook.event}.${hook.name}: `) + hook.command);
+    }
+
+    if (this.rl) {
+      this.rl.pause();
+    }
+    const answer = await this.promptContinue('Trust and run these hooks? [y/N] ');
+    if (this.rl) {
+      this.rl.resume();
+    }
+
+    if (answer === 'y' || answer === 'yes') {
+      trustProjectHooks(this.config.cwd);
+      this.engine?.reloadHooks();
+    } else {
+      this.terminal.muted(`${HOOKS_FILE} skipped; you will be asked again next time`);
+    }
+  }
+
   private async promptContinue(prompt: string): Promise<string> {
     return new Promise<string>((resolve) => {
       const tempRl = readline.createInterface({
@@ -952,6 +1495,202 @@ export class FloydCLI {
     });
   }
 
+  /**
+   * Print a batch of streamed tool output
+   *
+   * On a TTY the line counter is redrawn in place below the output.
+   */
+  private renderToolOutput(batch: ToolOutputBatch): void {
+    const live = Boolean(process.stdout.isTTY);
+
+    if (live && this.toolOutputCounterShown) {
+      process.stdout.write('\r\x1b[K');
+      this.toolOutputCounterShown = false;
+    }
+
+    for (const line of batch.lines) {
+      console.log(chalk.dim(`  │ ${line}`));
+    }
+
+    const counter = formatLineCount(batch.totalLines);
+    if (!batch.done) {
+      if (live) {
+        process.stdout.write(chalk.dim(`  ${counter}`));
+        this.toolOutputCounterShown = true;
+      }
+    } else if (batch.skippedLines > 0) {
+      console.log(chalk.dim(`  ${counter} (${batch.skippedLines.toLocaleString('en-US')} not shown)`));
+    }
+  }
+
+  /**
+   * Ask the user a question from the ask_user tool
+   *
+   * Numbered options can be picked by number; any other reply is returned as typed.
+   */
+  private async promptForAnswer(question: string, options?: string[]): Promise<string> {
+    console.log('');
+    console.log(chalk.hex(CRUSH_THEME.colors.info)('? ') + chalk.bold(question));
+    options?.forEach((option, index) => {
+      console.log(chalk.hex(CRUSH_THEME.colors.muted)(`  ${index + 1}) `) + option);
+    });
+
+    const answer = await new Promise<string>((resolve) => {
+      const tempRl = readline.createInterface({
+        input: process.stdin,
+        output: process.stdout,
+      });
+
+      tempRl.question(options?.length ? 'Choose a number or type an answer: ' : '> ', (reply) => {
+        tempRl.close();
+        resolve(reply.trim());
+      });
+    });
+
+    const choice = parseInt(answer, 10);
+    if (options && String(choice) === answer && choice >= 1 && choice <= options.length) {
+      return options[choice - 1];
+    }
+    return answer;
+  }
+
+  /**
+   * Ask for an environment variable's value, hiding the input for secrets;
+   * null when skipped
+   */
+  private async promptForEnvValue(name: string, reason: string | undefined, sensitive: boolean): Promise<string | null> {
+    console.log('');
+    console.log(chalk.hex(CRUSH_THEME.colors.info)('? ') + chalk.bold(`Set ${name} for this session`));
+    if (reason) {
+      console.log(chalk.hex(CRUSH_THEME.colors.muted)(`  ${reason}`));
+    }
+    console.log(chalk.hex(CRUSH_THEME.colors.muted)('  The value is not shown to the agent. Leave empty to skip.'));
+
+    const value = await new Promise<string>((resolve) => {
+      const tempRl = readline.createInterface({
+        input: process.stdin,
+        output: process.stdout,
+      });
+
+      let muted = false;
+      if (sensitive) {
+        // Echo the prompt, then nothing while the secret is typed
+        const output = tempRl as unknown as { _writeToOutput: (text: string) => void };
+        output._writeToOutput = (text: string) => {
+          if (!muted) {
+            process.stdout.write(text);
+          }
+        };
+      }
+
+      tempRl.question(`${name}=`, (reply) => {
+        tempRl.close();
+        if (muted) {
+          process.stdout.write('\n');
+        }
+        resolve(reply.trim());
+      });
+      muted = sensitive;
+    });
+
+    return value || null;
+  }
+
+  /**
+   * Show a proposed file change and ask to accept, reject or edit it
+   */
+  private async promptForDiffDecision(preview: DiffPreview): Promise<DiffPreviewDecision> {
+    console.log('');
+    console.log(chalk.bold(`${preview.isNewFile ? 'Create' : 'Modify'} ${preview.filePath} (${preview.toolName})`));
+    for (const line of preview.diff.split('\n').slice(2)) {
+      if (line.startsWith('+')) {
+        console.log(chalk.hex(CRUSH_THEME.colors.success)(line));
+      } else if (line.startsWith('-')) {
+        console.log(chalk.hex(CRUSH_THEME.colors.error)(line));
+      } else if (line.startsWith('@@')) {
+        console.log(chalk.hex(CRUSH_THEME.colors.info)(line));
+      } else {
+        console.log(chalk.hex(CRUSH_THEME.colors.muted)(line));
+      }
+    }
+
+    const answer = await new Promise<string>((resolve) => {
+      const tempRl = readline.createInterface({
+        input: process.stdin,
+        output: process.stdout,
+      });
+
+      tempRl.question('Apply change? [a]ccept / [r]eject / [e]dit: ', (reply) => {
+        tempRl.close();
+        resolve(reply.trim().toLowerCase());
+      });
+    });
+
+    if (answer === 'e' || answer === 'edit') {
+      return this.editProposedContent(preview);
+    }
+    if (answer === 'a' || answer === 'accept' || answer === 'y' || answer === 'yes') {
+      return { action: 'accept' };
+    }
+    return { action: 'reject' };
+  }
+
+  /**
+   * Show what the run has used and ask whether to continue, raise the limit or abort
+   */
+  private async promptForBudgetDecision(exceeded: BudgetExceeded): Promise<BudgetDecision> {
+    const { usage } = exceeded;
+    console.log('');
+    console.log(chalk.hex(CRUSH_THEME.colors.warning).bold(`Run paused: ${exceeded.message} reached`));
+    console.log(chalk.hex(CRUSH_THEME.colors.muted)(
+      `  ${usage.turns} turns · ${(usage.inputTokens + usage.outputTokens).toLocaleString('en-US')} tokens · ` +
+      `${usage.toolCalls} tool calls · ${Math.round(usage.elapsedMs / 1000)}s · ~$${usage.costUsd.toFixed(2)}`
+    ));
+
+    const answer = await new Promise<string>((resolve) => {
+      const tempRl = readline.createInterface({
+        input: process.stdin,
+        output: process.stdout,
+      });
+
+      tempRl.question('[c]ontinue without this limit / [r]aise it / [a]bort: ', (reply) => {
+        tempRl.close();
+        resolve(reply.trim().toLowerCase());
+      });
+    });
+
+    if (answer === 'c' || answer === 'continue') {
+      return 'continue';
+    }
+    if (answer === 'r' || answer === 'raise') {
+      return 'raise';
+    }
+    return 'abort';
+  }
+
+  /**
+   * Open the proposed content in $EDITOR and apply what the user saves
+   */
+  private async editProposedContent(preview: DiffPreview): Promise<DiffPreviewDecision> {
+    // Fixed name: the proposed path comes from the model. The extension is
+    // kept so the editor picks the right syntax.
+    const extension = path.extname(preview.filePath).replace(/[^\w.]/g, '');
+    const tempDir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-edit-'));
+    const tempFile = path.join(tempDir, `proposed${extension}`);
+
+    try {
+      await fs.writeFile(tempFile, preview.proposedContent, 'utf-8');
+      const status = openInEditor(tempFile);
+      if (status !== 0) {
+        this.terminal.warning(`Editor exited with status ${status}; change rejected`);
+        return { action: 'reject', reason: 'editor exited with an error' };
+      }
+      return { action: 'edit', content: await fs.readFile(tempFile, 'utf-8') };
+    } finally {
+      await fs.remove(tempDir);
+    }
+  }
+
   /**
    * Display welcome message
    */
@@ -973,7 +1712,12 @@ export class FloydCLI {
     this.terminal.primary(`  ${greeting}`);
 
     this.terminal.blank();
-    this.terminal.muted('Type your message below. Press Ctrl+C to exit.');
+    if (this.offlineReason) {
+      this.terminal.warning(describeOffline(this.offlineReason, this.config));
+      this.terminal.muted(OFFLINE_HINT);
+    } else {
+      this.terminal.muted('Type your message below. Press Ctrl+C to exit.');
+    }
     this.terminal.blank();
 
     // Add Floyd's greeting to conversation history
@@ -996,7 +1740,7 @@ export class FloydCLI {
 
     // Handle exit commands
     if (input.trim().toLowerCase() === 'exit' || input.trim().toLowerCase() === 'quit') {
-      this.shutdown();
+      await this.shutdown();
       return;
     }
 
@@ -1006,17 +1750,40 @@ export class FloydCLI {
       return;
     }
 
+    // Offline: say so instead of failing a model call
+    if (this.offlineReason) {
+      this.terminal.warning(describeOffline(this.offlineReason, this.config));
+      this.terminal.muted(OFFLINE_HINT);
+      return;
+    }
+
     // Check if in dialogue mode
     const inDialogueMode = this.isDialogueMode();
 
     // Add user message to conversation history (appears at top)
     this.conversationHistory.addMessage(`You: ${input}`);
 
-    try {
-      const startTime = Date.now();
+    const startTime = Date.now();
+    this.tagParser = new StreamTagParser(['thinking', 'think']);
+    this.dispatchRun({
+      type: 'submitted',
+      text: input,
+      userMessageId: `user-${startTime}`,
+      assistantMessageId: `assistant-${startTime}`,
+      phrase: '',
+      at: startTime,
+    });
 
+    try {
       // Execute user message through agent engine
       await this.engine.execute(input);
+      this.notifier.runEnded('complete', Date.now() - startTime, `Done in ${Math.round((Date.now() - startTime) / 1000)}s: ${input.slice(0, 80)}`);
+
+      // Release text held back as a possible partial tag
+      for (const event of this.tagParser.flush()) {
+        this.handleTagEvent(event);
+      }
+      this.dispatchRun({ type: 'completed', at: Date.now() });
 
       // Finish streaming display after execution completes
       if (this.streamingDisplay.isActive()) {
@@ -1054,7 +1821,9 @@ export class FloydCLI {
           // Simple single-cell success message
           const successColor = CRUSH_THEME.colors.success; // Green/Guac
           const title = chalk.hex(successColor).bold('  ✓ Execution Complete  ');
-          const time = chalk.hex(CRUSH_THEME.colors.muted)(`(${duration}s)`);
+          const toolCount = this.runState.messages.find(m => m.role === 'assistant')?.toolCalls?.length ?? 0;
+          const tools = toolCount > 0 ? ` · ${toolCount} tool${toolCount === 1 ? '' : 's'}` : '';
+          const time = chalk.hex(CRUSH_THEME.colors.muted)(`(${duration}s${tools})`);
 
           table.push([`${title} ${time}`]);
           console.log(table.toString());
@@ -1069,7 +1838,43 @@ export class FloydCLI {
       }
 
       logger.error('Failed to process input', error);
-      this.terminal.error(error instanceof Error ? error.message : String(error));
+      const { message, details } = describeRunError(error);
+      this.dispatchRun({ type: 'failed', message, details, errorMessageId: `error-${Date.now()}`, at: Date.now() });
+      this.notifier.runEnded('error', Date.now() - startTime, message);
+      this.terminal.error(message);
+      // Stacks go to the log; only the hints for known API errors are shown
+      if (details && !details.includes('\n    at ')) {
+        this.terminal.muted(details);
+      }
+      getRunStatusReporter().set('idle', 'last run failed');
+    } finally {
+      this.dispatchRun({ type: 'finished' });
+      this.activeToolId = undefined;
+    }
+  }
+
+  /**
+   * Apply an event to the current turn's state
+   */
+  private dispatchRun(event: RunEvent): void {
+    this.runState = reduceRunEvent(this.runState, event);
+  }
+
+  /**
+   * Route parsed stream output: <thinking> blocks drive the monitoring
+   * module's thinking indicator, everything else is the reply
+   */
+  private handleTagEvent(event: TagEvent): void {
+    const monitoring = getMonitoringModule();
+    if (event.type === 'tag_open') {
+      this.dispatchRun({ type: 'thinking_started', phrase: '' });
+      monitoring.startThinking();
+    } else if (event.type === 'tag_close') {
+      this.dispatchRun({ type: 'thinking_ended' });
+      monitoring.stopThinking();
+    } else if (event.content && !this.runState.inThinkingBlock) {
+      this.dispatchRun({ type: 'text', text: event.content });
+      this.streamingDisplay.appendToken(event.content);
     }
   }
 
@@ -1141,6 +1946,7 @@ export class FloydCLI {
       this.displayWelcome();
 
       this.isRunning = true;
+      getRunStatusReporter().set('idle');
 
       // Initialize persistent history
       await this.initializeHistory();
@@ -1165,41 +1971,17 @@ export class FloydCLI {
   }
 
   /**
-   * Initialize readline history from file
+   * Initialize readline history from ~/.floyd/history
    */
   private async initializeHistory(): Promise<void> {
     if (this.testMode || !this.rl) return;
 
     try {
-      const { default: fs } = await import('fs-extra');
-      const { default: path } = await import('node:path');
-
-      const historyFile = path.join(process.cwd(), '.floyd', 'history');
-
-      if (await fs.pathExists(historyFile)) {
-        const content = await fs.readFile(historyFile, 'utf-8');
-        const lines = content.split('\n').filter(line => line.trim());
-
-        // Basic history loading (Note: Node's readline interface history API is limited
-        // in older versions, but we can try to inject it if supported or just manually handle)
-        // Actually, standard Node `readline` doesn't have a simple public API to load history
-        // array directly without creating a new instance with history option, but `createInterface`
-        // doesn't support passing an array in types easily.
-        // However, we can push to the internal history array if we are careful.
-
-        // Safe approach: just let new session accumulate history, or verify if we can set it.
-        // Since we already created `rl` in initialize(), we might need to recreate it OR
-        // just manual append to file for NOW and maybe in future use a better CLI lib.
-
-        // Wait, we CAN pass history to createInterface if we reconstruct it.
-        // But let's just use the file for persistent storage across sessions.
-        // For current session up-arrow, readline handles it in-memory.
-        // If we want up-arrow to work for previous sessions, we need to load it.
-
-        if ((this.rl as any).history instanceof Array) {
-          // Reverse because readline stores history latest-first (stack)
-          (this.rl as any).history.push(...lines.reverse());
-        }
+      this.inputHistory = await loadInputHistory();
+
+      // readline stores history latest-first
+      if ((this.rl as any).history instanceof Array) {
+        (this.rl as any).history.push(...[...this.inputHistory].reverse());
       }
     } catch (error) {
       logger.warn('Failed to load command history', error);
@@ -1207,20 +1989,14 @@ export class FloydCLI {
   }
 
   /**
-   * Append command to history file
+   * Append command to ~/.floyd/history
    */
   private async appendToHistory(command: string): Promise<void> {
     if (this.testMode) return;
 
+    this.inputHistory.push(command);
     try {
-      const { default: fs } = await import('fs-extra');
-      const { default: path } = await import('node:path');
-
-      const historyDir = path.join(process.cwd(), '.floyd');
-      const historyFile = path.join(historyDir, 'history');
-
-      await fs.ensureDir(historyDir);
-      await fs.appendFile(historyFile, command + '\n');
+      await appendInputHistory(command);
     } catch (error) {
       // Fail silently for history save errors
       logger.debug('Failed to save history', { error });
@@ -1228,26 +2004,89 @@ export class FloydCLI {
   }
 
   /**
-   * Shutdown the CLI application
+   * Ctrl+R fuzzy reverse search over prompt history
+   *
+   * The chosen entry is placed on the prompt line for editing.
    */
-  shutdown(): void {
-    this.isRunning = false;
+  private async reverseSearchHistory(): Promise<void> {
+    if (!this.rl || this.isSearchingHistory) return;
+    this.isSearchingHistory = true;
 
-    // Finish any active streaming
-    if (this.streamingDisplay.isActive()) {
-      this.streamingDisplay.finish();
-    }
+    const ask = (text: string) => new Promise<string>((resolve) => {
+      const tempRl = readline.createInterface({
+        input: process.stdin,
+        output: process.stdout,
+      });
+      tempRl.question(text, (answer) => {
+        tempRl.close();
+        resolve(answer.trim());
+      });
+    });
 
-    // Clean up terminal elements (includes cursor restoration)
-    this.terminal.cleanup();
+    this.rl.pause();
+    readline.clearLine(process.stdout, 0);
+    readline.cursorTo(process.stdout, 0);
 
-    // Ensure cursor is visible before closing
-    this.showCursor();
+    let selected: string | undefined;
+    try {
+      const query = await ask(chalk.hex(CRUSH_THEME.colors.info)('(reverse-i-search): '));
+      const matches = query ? searchInputHistory(this.inputHistory, query, 9) : [];
+
+      if (query && matches.length === 0) {
+        this.terminal.muted('No matching history');
+      } else if (matches.length === 1) {
+        selected = matches[0];
+      } else if (matches.length > 1) {
+        matches.forEach((match, index) => {
+          console.log(chalk.hex(CRUSH_THEME.colors.muted)(`  ${index + 1}) `) + match);
+        });
+        const choice = parseInt(await ask('Pick [1]: ') || '1', 10);
+        selected = matches[choice - 1];
+      }
+    } finally {
+      this.isSearchingHistory = false;
+      try {
+        this.rl.resume();
+        this.safePrompt();
+        if (selected) {
+          this.rl.write(selected);
+        }
+      } catch (error) {
+        logger.debug('Failed to resume readline (likely closed)', { error });
+      }
+    }
+  }
 
-    // FIX #6: Release instance lock on shutdown
-    this.instanceLock?.release();
+  /**
+   * Shutdown the CLI application
+   *
+   * Runs the shutdown pipeline: cancel the running turn, stop child
+   * processes, persist state, restore the terminal. Safe to call again
+   * (readline's close event does) - later calls do nothing.
+   */
+  async shutdown(): Promise<void> {
+    const controller = getShutdownController();
+    if (controller.isShuttingDown()) {
+      return;
+    }
+    this.isRunning = false;
 
-    this.rl?.close();
+    const report = await controller.shutdown('quit');
+    if (report.killed > 0) {
+      this.terminal.warning(`Killed ${report.killed} process(es) that did not stop`);
+    }
+
+    // Where the session's tool time went (full table: /stats)
+    const toolStats = toolRegistry.getStats();
+    if (toolStats.length > 0) {
+      const [summary, , header, ...rows] = formatToolStats(toolStats, { limit: 5 });
+      this.terminal.section('Tool Summary');
+      this.terminal.info(summary);
+      this.terminal.muted(header);
+      for (const row of rows) {
+        this.terminal.info(row);
+      }
+    }
 
     // Floyd's goodbye - grateful and looking forward to next time
     const goodbyes = [
@@ -1285,13 +2124,149 @@ export class FloydCLI {
     this.showCursor();
 
     this.rl?.close();
+
+    // Close the dashboard event stream if it was started
+    void getEventBroadcaster().stop();
+
+    // Export any spans still buffered
+    void getTracer().flush();
+
+    getRunStatusReporter().clear();
+  }
+}
+
+/**
+ * Output or error of a tool call, for the run state
+ */
+function describeToolResult(result: unknown): { output?: string; error?: string } {
+  const record = result as { success?: boolean; data?: unknown; error?: { message?: string } | string } | null;
+  if (record && typeof record === 'object' && record.success === false) {
+    const error = typeof record.error === 'string' ? record.error : record.error?.message;
+    return { error: error ?? 'Tool failed' };
   }
+  const data = record && typeof record === 'object' && 'data' in record ? record.data : result;
+  return { output: typeof data === 'string' ? data : JSON.stringify(data, null, 2) };
 }
 
 // ============================================================================
 // Main Entry Point
 // ============================================================================
 
+/**
+ * Print the run state published by the running FLOYD instance
+ */
+async function printRunStatus(porcelain: boolean): Promise<void> {
+  const status = await readRunStatus();
+  console.log(porcelain ? formatRunStatusPorcelain(status) : formatRunStatus(status));
+}
+
+/**
+ * Follow a live session. Without a URL, the session advertised in the
+ * status file is used, then FLOYD_EVENTS_PORT.
+ */
+async function watchRunningSession(urlArg?: string): Promise<void> {
+  const status = await readRunStatus();
+  const port = process.env.FLOYD_EVENTS_PORT;
+  const url = urlArg
+    || (status?.state !== 'idle' ? status?.eventsUrl : undefined)
+    || (port ? `ws://${process.env.FLOYD_EVENTS_HOST || '127.0.0.1'}:${port}` : undefined);
+
+  if (!url) {
+    console.error('No live session to watch. Start FLOYD with FLOYD_EVENTS_PORT set, or pass a ws:// URL.');
+    process.exitCode = 1;
+    return;
+  }
+
+  try {
+    await watchSession(url);
+  } catch (error) {
+    console.error(`Could not attach to ${url}: ${error instanceof Error ? error.message : String(error)}`);
+    process.exitCode = 1;
+  }
+}
+
+/**
+ * Play back a recorded run (by id or file, or the most recent)
+ */
+async function replayRun(idOrPath: string, speed: number): Promise<void> {
+  const file = await resolveRecording(idOrPath || undefined);
+  if (!file) {
+    console.error(idOrPath ? `Recording "${idOrPath}" not found.` : `No recordings in ${getRunsDir()}. Record runs with --record.`);
+    process.exitCode = 1;
+    return;
+  }
+
+  const events = await readRecording(file);
+  console.log(chalk.hex(CRUSH_THEME.colors.muted)(`Replaying ${path.basename(file)} (${events.length} events)`));
+  await replayRecording(events, { speed });
+  console.log();
+}
+
+/**
+ * Export a saved session (by id/name, or the most recent) as a transcript
+ */
+async function exportTranscript(formatArg: string, sessionIdOrName?: string): Promise<void> {
+  const format = parseTranscriptFormat(formatArg || undefined);
+  if (!format) {
+    console.error(`Unknown export format "${formatArg}". Use md or html.`);
+    process.exitCode = 1;
+    return;
+  }
+
+  const sessionManager = new SessionManager(process.cwd());
+  const latest = sessionManager.listSessions()[0];
+  const session = sessionIdOrName
+    ? sessionManager.loadSession(sessionIdOrName)
+    : latest ? sessionManager.loadSession(latest.id) : null;
+
+  if (!session) {
+    console.error(sessionIdOrName ? `Session "${sessionIdOrName}" not found.` : 'No sessions to export.');
+    process.exitCode = 1;
+    return;
+  }
+
+  const filepath = await TranscriptExporter.exportSession(session, sessionManager.getHistory(), { format });
+  console.log(`Transcript exported: ${filepath}`);
+}
+
+/**
+ * Run the goals in a task file, each in its own FLOYD process
+ */
+async function runTaskFile(filePath: string | undefined, parallel: number, mode?: string): Promise<void> {
+  if (!filePath) {
+    console.error('Usage: floyd run <tasks.yaml> [--parallel n] [--mode yolo]');
+    process.exitCode = 1;
+    return;
+  }
+
+  let tasks: BatchTask[];
+  try {
+    tasks = await loadTaskFile(filePath);
+  } catch (error) {
+    console.error(`Could not read ${filePath}: ${error instanceof Error ? error.message : String(error)}`);
+    process.exitCode = 1;
+    return;
+  }
+
+  const muted = chalk.hex(CRUSH_THEME.colors.muted);
+  console.log(muted(`Running ${tasks.length} task(s), ${Math.max(1, parallel)} at a time`));
+
+  const { dir, results } = await runBatch(tasks, processExecutor(mode), {
+    parallel,
+    onTaskStart: task => console.log(muted(`▶ ${task.index}. ${task.goal}`)),
+    onTaskEnd: result => {
+      const mark = result.status === 'done' ? chalk.green('✓') : chalk.red('✗');
+      console.log(`${mark} ${result.task.index}. ${result.task.goal} ${muted(`(${result.status}, ${(result.durationMs / 1000).toFixed(1)}s)`)}`);
+    },
+  });
+
+  const failed = results.filter(result => result.status !== 'done').length;
+  console.log(`\n${results.length - failed}/${results.length} done. Summary: ${path.join(dir, 'summary.md')}`);
+  if (failed > 0) {
+    process.exitCode = 1;
+  }
+}
+
 /**
  * Main function to start the CLI
  */
@@ -1316,6 +2291,49 @@ export async function main(options?: { testMode?: boolean }): Promise<void> {
     return;
   }
 
+  // `floyd status` reports the state of a running instance and exits
+  if (cli.input[0] === 'status') {
+    await printRunStatus(cli.flags.porcelain);
+    return;
+  }
+
+  // `floyd auth` manages stored API keys and exits
+  if (cli.input[0] === 'auth') {
+    const { runAuthCommand } = await import('./commands/auth-commands.js');
+    process.exitCode = await runAuthCommand(cli.input.slice(1), { verify: cli.flags.verify });
+    return;
+  }
+
+  // `floyd setup` re-runs the first-launch wizard
+  if (cli.input[0] === 'setup') {
+    await runSetupWizard();
+    return;
+  }
+
+  // `floyd watch` attaches read-only to a running session's event stream
+  if (cli.input[0] === 'watch') {
+    await watchRunningSession(cli.input[1]);
+    return;
+  }
+
+  // `floyd run` works through a task file headlessly
+  if (cli.input[0] === 'run') {
+    await runTaskFile(cli.input[1], cli.flags.parallel, cli.flags.mode);
+    return;
+  }
+
+  // Play back a recorded run and exit
+  if (cli.flags.replay !== undefined) {
+    await replayRun(cli.flags.replay, cli.flags.speed);
+    return;
+  }
+
+  // Export a session transcript and exit
+  if (cli.flags.export !== undefined) {
+    await exportTranscript(cli.flags.export, cli.flags.resume);
+    return;
+  }
+
   try {
     const cliApp = new FloydCLI(options);
     await cliApp.start();
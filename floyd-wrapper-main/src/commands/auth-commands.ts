@@ -0,0 +1,224 @@
+/**
+ * Auth Commands - Floyd Wrapper
+ *
+ * `floyd auth` (shell) and /auth (in a session) store API keys per provider
+ * in the OS keychain, or an encrypted file where there is none, after
+ * checking them with a test request. Stored keys are picked up by
+ * loadConfig(), so no key has to live in env vars or source files.
+ */
+
+import readline from 'node:readline';
+import {
+  getCredentialStore,
+  parseProvider,
+  maskKey,
+  validateApiKey,
+  PROVIDER_ENV_VARS,
+  type KeyCheck,
+} from 'floyd-agent-core/utils';
+import type { Provider } from 'floyd-agent-core';
+import type { SlashCommand } from './slash-commands.js';
+import { loadConfig, type FloydConfig } from '../utils/config.js';
+
+const PROVIDERS = Object.keys(PROVIDER_ENV_VARS).join(', ');
+
+/**
+ * Test a key against the endpoint FLOYD would use for the provider
+ */
+function checkKey(provider: Provider, key: string, config: Pick<FloydConfig, 'glmApiEndpoint' | 'glmModel'>): Promise<KeyCheck> {
+  // The wrapper talks to the GLM coding endpoint, not the core default
+  return validateApiKey(provider, key, provider === 'zai' ? { endpoint: config.glmApiEndpoint, model: config.glmModel } : {});
+}
+
+/**
+ * One line per provider: where its key comes from
+ */
+function statusLines(): string[] {
+  return getCredentialStore().status().map(({ provider, source, masked }) =>
+    source ? `${provider.padEnd(10)} ${masked}  (${source})` : `${provider.padEnd(10)} not set`
+  );
+}
+
+// ============================================================================
+// /auth
+// ============================================================================
+
+// Command: /auth
+export const authCommand: SlashCommand = {
+  name: 'auth',
+  description: 'Show, store, test or remove API keys (kept in the OS keychain)',
+  usage: '/auth [status | login <provider> <key> | test [provider] | logout <provider>]',
+  handler: async (ctx) => {
+    const [action = 'status', name, key] = ctx.args;
+    const store = getCredentialStore();
+    const config: FloydConfig = ctx.engine?.getConfig?.() ?? loadConfig();
+
+    if (action === 'status') {
+      ctx.terminal.section('API Keys');
+      for (const line of statusLines()) {
+        ctx.terminal.info(line);
+      }
+      ctx.terminal.muted(`Stored in: ${store.backend.name}. Environment variables take precedence.`);
+      return;
+    }
+
+    const provider = parseProvider(name ?? (action === 'test' ? 'zai' : undefined));
+    if (!provider) {
+      ctx.terminal.error(name ? `Unknown provider: ${name}` : `Usage: ${authCommand.usage}`);
+      ctx.terminal.muted(`Providers: ${PROVIDERS} (glm = zai)`);
+      return;
+    }
+
+    switch (action) {
+      case 'login':
+      case 'set': {
+        if (!key) {
+          ctx.terminal.error(`Usage: /auth login ${provider} <key>  (or run \`floyd auth login ${provider}\` for a hidden prompt)`);
+          return;
+        }
+        ctx.terminal.muted('Checking the key...');
+        const check = await checkKey(provider, key, config);
+        if (!check.ok) {
+          ctx.terminal.error(`${check.message} - key not stored`);
+          return;
+        }
+        store.set(provider, key);
+        ctx.terminal.success(`${provider} key ${maskKey(key)} stored in ${store.backend.name}`);
+
+        // Apply to the running session where it is the active provider
+        if (provider === 'zai' && !process.env.FLOYD_GLM_API_KEY && typeof ctx.engine?.updateConfig === 'function') {
+          ctx.engine.updateConfig({ ...config, glmApiKey: key });
+        }
+        return;
+      }
+
+      case 'test': {
+        const stored = store.resolve(provider);
+        if (!stored) {
+          ctx.terminal.error(`No ${provider} key. Store one with /auth login ${provider} <key>`);
+          return;
+        }
+        const check = await checkKey(provider, stored, config);
+        if (check.ok) {
+          ctx.terminal.success(check.message);
+        } else {
+          ctx.terminal.error(check.message);
+        }
+        return;
+      }
+
+      case 'logout':
+      case 'remove':
+        if (store.delete(provider)) {
+          ctx.terminal.success(`${provider} key removed from ${store.backend.name}`);
+        } else {
+          ctx.terminal.warning(`No stored ${provider} key`);
+        }
+        return;
+
+      default:
+        ctx.terminal.error(`Unknown action: ${action}`);
+        ctx.terminal.muted(`Usage: ${authCommand.usage}`);
+    }
+  },
+};
+
+export const authCommands: SlashCommand[] = [
+  authCommand,
+];
+
+// ============================================================================
+// floyd auth
+// ============================================================================
+
+/**
+ * Read a key without echoing it; piped input is read whole
+ */
+export async function promptSecret(question: string): Promise<string> {
+  if (!process.stdin.isTTY) {
+    let input = '';
+    for await (const chunk of process.stdin) {
+      input += chunk;
+    }
+    return input.trim();
+  }
+
+  const rl = readline.createInterface({ input: process.stdin, output: process.stdout, terminal: true });
+  const output = rl as unknown as { _writeToOutput: (text: string) => void };
+  process.stdout.write(question);
+  // Swallow the echo of typed characters
+  output._writeToOutput = () => {};
+  try {
+    return await new Promise<string>(resolve => rl.question('', answer => resolve(answer.trim())));
+  } finally {
+    rl.close();
+    process.stdout.write('\n');
+  }
+}
+
+/**
+ * `floyd auth [status|login|test|logout] [provider]`
+ *
+ * @returns Exit code
+ */
+export async function runAuthCommand(args: string[], options: { verify?: boolean } = {}): Promise<number> {
+  const [action = 'status', name] = args;
+  const store = getCredentialStore();
+  const config = loadConfig();
+
+  if (action === 'status') {
+    console.log(statusLines().join('\n'));
+    console.log(`\nStored in: ${store.backend.name}. Environment variables take precedence.`);
+    return 0;
+  }
+
+  const provider = parseProvider(name ?? 'zai');
+  if (!provider) {
+    console.error(`Unknown provider: ${name}. Providers: ${PROVIDERS} (glm = zai)`);
+    return 1;
+  }
+
+  switch (action) {
+    case 'login': {
+      const key = await promptSecret(`${provider} API key: `);
+      if (!key) {
+        console.error('No key entered');
+        return 1;
+      }
+      if (options.verify !== false) {
+        const check = await checkKey(provider, key, config);
+        if (!check.ok) {
+          console.error(`${check.message} - key not stored (use --no-verify to store it anyway)`);
+          return 1;
+        }
+        console.log(check.message);
+      }
+      store.set(provider, key);
+      console.log(`${provider} key ${maskKey(key)} stored in ${store.backend.name}`);
+      return 0;
+    }
+
+    case 'test': {
+      const key = store.resolve(provider);
+      if (!key) {
+        console.error(`No ${provider} key. Run \`floyd auth login ${provider}\``);
+        return 1;
+      }
+      const check = await checkKey(provider, key, config);
+      (check.ok ? console.log : console.error)(check.message);
+      return check.ok ? 0 : 1;
+    }
+
+    case 'logout':
+      if (!store.delete(provider)) {
+        console.error(`No stored ${provider} key`);
+        return 1;
+      }
+      console.log(`${provider} key removed from ${store.backend.name}`);
+      return 0;
+
+    default:
+      console.error(`Unknown action: ${action}. Use status, login, test or logout.`);
+      return 1;
+  }
+}
@@ -0,0 +1,102 @@
+/**
+ * Branch & PR Slash Commands - Floyd Wrapper
+ *
+ * /status shows the session and the branch state kept in .floyd/branch.md;
+ * /pr drafts a pull request description from it.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { execa } from 'execa';
+import type { SlashCommand } from './slash-commands.js';
+import { getBranchNotes, formatBranchState, renderPrDraft } from '../persistence/branch-notes.js';
+import { getRunStatusReporter } from '../utils/run-status.js';
+
+/**
+ * Run git and return trimmed stdout ('' on failure)
+ */
+async function git(cwd: string, args: string[]): Promise<string> {
+  try {
+    const { stdout } = await execa('git', args, { cwd });
+    return stdout.trim();
+  } catch {
+    return '';
+  }
+}
+
+// Command: /status
+export const statusCommand: SlashCommand = {
+  name: 'status',
+  description: 'Show session, run and branch/PR-checklist status',
+  usage: '/status',
+  handler: async (ctx) => {
+    ctx.terminal.section('Status');
+    ctx.terminal.info(`Mode: ${process.env.FLOYD_MODE || 'ask'}`);
+    ctx.terminal.info(`Run: ${getRunStatusReporter().getState() ?? 'idle'}`);
+
+    const sessionId = ctx.sessionManager?.getCurrentSessionId();
+    if (sessionId) {
+      ctx.terminal.info(`Session: ${sessionId}`);
+    }
+
+    if (typeof ctx.engine?.getTokenStatistics === 'function') {
+      const usage = ctx.engine.getTokenStatistics();
+      ctx.terminal.info(`Tokens: ${usage.totalTokens.toLocaleString()}`);
+    }
+
+    const branchNotes = getBranchNotes();
+    await branchNotes.recordBranch();
+    const state = await branchNotes.read();
+
+    ctx.terminal.blank();
+    if (!state) {
+      ctx.terminal.muted('No .floyd/branch.md in this project.');
+      return;
+    }
+    for (const line of formatBranchState(state)) {
+      ctx.terminal.muted(line);
+    }
+  },
+};
+
+// Command: /pr
+export const prCommand: SlashCommand = {
+  name: 'pr',
+  description: 'Draft a pull request description from commits and .floyd/branch.md',
+  usage: '/pr',
+  handler: async (ctx) => {
+    const branchNotes = getBranchNotes();
+    await branchNotes.recordBranch();
+    const state = await branchNotes.read() ?? { checklist: [] };
+
+    const branch = state.name ?? await git(ctx.cwd, ['rev-parse', '--abbrev-ref', 'HEAD']);
+    const base = state.base ?? 'main';
+    if (!branch) {
+      ctx.terminal.error('Not a git repository');
+      return;
+    }
+
+    const range = `${base}..${branch}`;
+    const log = await git(ctx.cwd, ['log', '--reverse', '--format=%s', range]);
+    const diffStat = await git(ctx.cwd, ['diff', '--stat', `${base}...${branch}`]);
+    const draft = renderPrDraft({ ...state, name: branch }, log ? log.split('\n') : [], diffStat);
+
+    const draftPath = path.join(ctx.cwd, '.floyd', 'pr-draft.md');
+    await fs.ensureDir(path.dirname(draftPath));
+    await fs.writeFile(draftPath, draft, 'utf-8');
+
+    ctx.terminal.section(`PR draft (${range})`);
+    console.log(draft);
+    ctx.terminal.success(`Saved to ${path.relative(ctx.cwd, draftPath)}`);
+
+    const open = state.checklist.filter(item => !item.done);
+    if (open.length > 0) {
+      ctx.terminal.warning(`${open.length} checklist item(s) still open`);
+    }
+  },
+};
+
+export const branchCommands: SlashCommand[] = [
+  statusCommand,
+  prCommand,
+];
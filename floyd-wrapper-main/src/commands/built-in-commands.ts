@@ -8,6 +8,15 @@ import readline from 'node:readline';
 import path from 'node:path';
 import fs from 'fs-extra';
 import type { SlashCommand } from './slash-commands.js';
+import { logger } from '../utils/logger.js';
+import { readLogTail, formatLogRecord } from '../utils/log-file.js';
+import { getThemeManager } from '../ui/theme.js';
+import { getCacheManager } from '../tools/cache/index.js';
+import { formatCacheStats } from '../tools/cache/cache-core.js';
+import { getMemoryStore, isMemoryEnabled } from '../memory/index.js';
+import { toolRegistry } from '../tools/tool-registry.js';
+import { formatToolStats } from 'floyd-agent-core/utils';
+import type { LogLevel } from '../types.js';
 
 /**
  * Helper function to prompt user for input when commands need arguments
@@ -88,8 +97,8 @@ export const historyCommand: SlashCommand = {
 // Command: /sessions
 export const sessionsCommand: SlashCommand = {
     name: 'sessions',
-    description: 'List all available sessions',
-    usage: '/sessions',
+    description: 'List all available sessions, optionally only those with a tag',
+    usage: '/sessions [#tag]',
     aliases: ['ls'],
     handler: async (ctx) => {
         if (!ctx.sessionManager) {
@@ -97,8 +106,9 @@ export const sessionsCommand: SlashCommand = {
             return;
         }
 
-        const sessions = ctx.sessionManager.listSessions();
-        ctx.terminal.section('Available Sessions');
+        const tag = ctx.args[0];
+        const sessions = ctx.sessionManager.listSessions({ tag });
+        ctx.terminal.section(tag ? `Sessions tagged #${tag.replace(/^#/, '')}` : 'Available Sessions');
 
         if (sessions.length === 0) {
             ctx.terminal.muted('No sessions found.');
@@ -108,11 +118,102 @@ export const sessionsCommand: SlashCommand = {
                 ctx.terminal.info(`${session.name}${current}`);
                 ctx.terminal.muted(`  ID: ${session.id}`);
                 ctx.terminal.muted(`  Updated: ${new Date(session.updatedAt).toLocaleString()}`);
+                if (session.metadata.tags?.length) {
+                    ctx.terminal.muted(`  Tags: ${session.metadata.tags.map(t => `#${t}`).join(' ')}`);
+                }
             }
         }
     },
 };
 
+// Command: /tag
+export const tagCommand: SlashCommand = {
+    name: 'tag',
+    description: 'Tag the current session (no arguments: show its tags)',
+    usage: '/tag [#tag...]',
+    handler: async (ctx) => {
+        if (!ctx.sessionManager) {
+            ctx.terminal.error('Session manager not initialized');
+            return;
+        }
+
+        try {
+            const tags = ctx.args.length > 0
+                ? ctx.sessionManager.updateSessionTags({ add: ctx.args })
+                : ctx.sessionManager.getSessionTags();
+            if (tags.length === 0) {
+                ctx.terminal.muted('No tags. Add some with /tag #name');
+            } else {
+                ctx.terminal.success(`Tags: ${tags.map(t => `#${t}`).join(' ')}`);
+            }
+        } catch (error) {
+            ctx.terminal.error(error instanceof Error ? error.message : String(error));
+        }
+    },
+};
+
+// Command: /untag
+export const untagCommand: SlashCommand = {
+    name: 'untag',
+    description: 'Remove tags from the current session',
+    usage: '/untag <#tag...>',
+    handler: async (ctx) => {
+        if (!ctx.sessionManager) {
+            ctx.terminal.error('Session manager not initialized');
+            return;
+        }
+        if (ctx.args.length === 0) {
+            ctx.terminal.error('Usage: /untag <#tag...>');
+            return;
+        }
+
+        try {
+            const tags = ctx.sessionManager.updateSessionTags({ remove: ctx.args });
+            ctx.terminal.success(tags.length > 0 ? `Tags: ${tags.map(t => `#${t}`).join(' ')}` : 'No tags left.');
+        } catch (error) {
+            ctx.terminal.error(error instanceof Error ? error.message : String(error));
+        }
+    },
+};
+
+// Command: /search
+export const searchCommand: SlashCommand = {
+    name: 'search',
+    description: 'Search transcripts of all sessions, optionally only those with a tag',
+    usage: '/search <text> [#tag]',
+    handler: async (ctx) => {
+        if (!ctx.sessionManager) {
+            ctx.terminal.error('Session manager not initialized');
+            return;
+        }
+
+        // A trailing #word is the tag filter; everything else is the query
+        const args = [...ctx.args];
+        const tag = args.length > 1 && args[args.length - 1].startsWith('#') ? args.pop() : undefined;
+        const query = args.join(' ');
+        if (!query) {
+            ctx.terminal.error('Usage: /search <text> [#tag]');
+            return;
+        }
+
+        const results = ctx.sessionManager.searchHistory(query, { tag });
+        ctx.terminal.section(`Search: "${query}"${tag ? ` in ${tag}` : ''}`);
+        if (results.length === 0) {
+            ctx.terminal.muted('No matches.');
+            return;
+        }
+
+        for (const result of results) {
+            // Show the match with some context on one line
+            const index = result.content.toLowerCase().indexOf(query.toLowerCase());
+            const start = Math.max(0, index - 40);
+            const snippet = result.content.slice(start, index + query.length + 40).replace(/\s+/g, ' ');
+            ctx.terminal.info(`${result.sessionName} · ${result.role} · ${new Date(result.timestamp).toLocaleString()}`);
+            ctx.terminal.muted(`  ${start > 0 ? '...' : ''}${snippet}`);
+        }
+    },
+};
+
 // Command: /checkpoints
 export const checkpointsCommand: SlashCommand = {
     name: 'checkpoints',
@@ -144,27 +245,67 @@ export const checkpointsCommand: SlashCommand = {
 // Command: /restore
 export const restoreCommand: SlashCommand = {
     name: 'restore',
-    description: 'Restore a file from checkpoint',
-    usage: '/restore <checkpoint-id>',
+    description: 'Roll the workspace back to a /snapshot, or restore a file from checkpoint',
+    usage: '/restore <snapshot|checkpoint-id>',
     handler: async (ctx) => {
-        if (!ctx.sessionManager) {
-            ctx.terminal.error('Session manager not initialized');
+        let target = ctx.args[0];
+        if (!target) {
+            target = await promptForInput('Enter snapshot name/ID or checkpoint ID: ');
+        }
+
+        if (!target) {
+            ctx.terminal.error('No snapshot or checkpoint ID provided');
             return;
         }
 
-        let checkpointId = ctx.args[0];
-        if (!checkpointId) {
-            checkpointId = await promptForInput('Enter checkpoint ID: ');
+        // Workspace snapshots take precedence over single-file checkpoints
+        const { getWorkspaceSnapshotManager, SnapshotConflictError } = await import('../rewind/index.js');
+        const snapshot = await getWorkspaceSnapshotManager().find(target);
+        if (snapshot) {
+            const confirm = await promptForInput(
+                `Roll the workspace back to "${snapshot.name}" (${new Date(snapshot.createdAt).toLocaleString()})? (yes/no): `
+            );
+            if (confirm.toLowerCase() !== 'yes' && confirm.toLowerCase() !== 'y') {
+                ctx.terminal.muted('Cancelled.');
+                return;
+            }
+
+            let result;
+            try {
+                result = await getWorkspaceSnapshotManager().restore(snapshot);
+            } catch (error) {
+                if (!(error instanceof SnapshotConflictError)) {
+                    throw error;
+                }
+                ctx.terminal.warning('These files changed since the snapshot, but not by FLOYD:');
+                error.paths.forEach(p => ctx.terminal.muted(`    - ${path.relative(process.cwd(), p)}`));
+                const overwrite = await promptForInput('Overwrite them with the snapshot too? (yes/no): ');
+                if (overwrite.toLowerCase() !== 'yes' && overwrite.toLowerCase() !== 'y') {
+                    ctx.terminal.muted('Cancelled. Commit or stash those changes first.');
+                    return;
+                }
+                result = await getWorkspaceSnapshotManager().restore(snapshot, { force: true });
+            }
+            ctx.terminal.success(`Workspace restored to snapshot: ${snapshot.name}`);
+            if (result.gitRestored) {
+                ctx.terminal.muted(`  Git tree restored from ${snapshot.gitCommit?.slice(0, 12)}`);
+            }
+            ctx.terminal.muted(`  Files restored: ${result.restored.length} | Removed: ${result.deleted.length}`);
+            if (result.failed.length > 0) {
+                ctx.terminal.warning(`  Failed: ${result.failed.length}`);
+                result.failed.forEach(f => ctx.terminal.muted(`    - ${f}`));
+            }
+            return;
         }
 
-        if (!checkpointId) {
-            ctx.terminal.error('No checkpoint ID provided');
+        if (!ctx.sessionManager) {
+            ctx.terminal.error('Session manager not initialized');
             return;
         }
 
-        const checkpoint = ctx.sessionManager.getCheckpointContent(checkpointId);
+        const checkpoint = ctx.sessionManager.getCheckpointContent(target);
         if (!checkpoint) {
-            ctx.terminal.error(`Checkpoint not found: ${checkpointId}`);
+            ctx.terminal.error(`No snapshot or checkpoint found: ${target}`);
             return;
         }
 
@@ -320,8 +461,8 @@ export const helpCommand: SlashCommand = {
 // Command: /stats
 export const statsCommand: SlashCommand = {
     name: 'stats',
-    description: 'Show session token usage statistics',
-    usage: '/stats',
+    description: 'Show session token usage and per-tool calls, failures and latency (reset clears the tool numbers)',
+    usage: '/stats [reset]',
     handler: async (ctx) => {
         if (!ctx.engine) {
             ctx.terminal.error('Engine not initialized');
@@ -340,6 +481,181 @@ export const statsCommand: SlashCommand = {
         ctx.terminal.info(`Output Tokens: ${usage.outputTokens.toLocaleString()}`);
         ctx.terminal.muted('─'.repeat(30));
         ctx.terminal.success(`Total Tokens:  ${usage.totalTokens.toLocaleString()}`);
+
+        const [summary, , header, ...rows] = formatToolStats(toolRegistry.getStats(), { histogram: true });
+        ctx.terminal.section('Tools');
+        ctx.terminal.info(summary);
+        if (header) {
+            ctx.terminal.muted(header);
+            for (const row of rows) {
+                ctx.terminal.info(row);
+            }
+        }
+        if (ctx.args[0] === 'reset') {
+            toolRegistry.resetStats();
+            ctx.terminal.muted('Tool stats reset');
+        }
+    },
+};
+
+// Command: /logs
+export const logsCommand: SlashCommand = {
+    name: 'logs',
+    description: 'Show the tail of .floyd/logs/floyd.log (optionally at or above a level)',
+    usage: '/logs [lines] [debug|info|warn|error]',
+    handler: async (ctx) => {
+        const file = logger.getFile();
+        if (!file) {
+            ctx.terminal.warning('File logging is off (FLOYD_LOG_FILE=off)');
+            return;
+        }
+
+        let count = 30;
+        let level: LogLevel = 'debug';
+        for (const arg of ctx.args) {
+            if (/^\d+$/.test(arg)) {
+                count = parseInt(arg, 10);
+            } else if (['debug', 'info', 'warn', 'error'].includes(arg)) {
+                level = arg as LogLevel;
+            }
+        }
+
+        const records = await readLogTail(file.getPath(), count, level);
+        ctx.terminal.section(`Logs (${path.relative(ctx.cwd, file.getPath())})`);
+        if (records.length === 0) {
+            ctx.terminal.muted('No log records yet.');
+            return;
+        }
+
+        for (const record of records) {
+            const line = formatLogRecord(record);
+            if (record.level === 'error') {
+                ctx.terminal.error(line);
+            } else if (record.level === 'warn') {
+                ctx.terminal.warning(line);
+            } else {
+                ctx.terminal.muted(line);
+            }
+        }
+    },
+};
+
+// Command: /theme
+export const themeCommand: SlashCommand = {
+    name: 'theme',
+    description: 'List themes, switch theme, or reload theme files from ~/.floyd/themes/',
+    usage: '/theme [name|reload]',
+    handler: async (ctx) => {
+        const themes = getThemeManager();
+        const arg = ctx.args[0];
+
+        if (arg === 'reload') {
+            const problems = themes.reload();
+            for (const problem of problems) {
+                ctx.terminal.warning(problem);
+            }
+            ctx.terminal.success(`Reloaded ${themes.list().length} themes (active: ${themes.current.name})`);
+            return;
+        }
+
+        if (arg) {
+            try {
+                const theme = themes.use(arg);
+                ctx.terminal.success(`Theme: ${theme.name}`);
+            } catch (error) {
+                ctx.terminal.error(error instanceof Error ? error.message : String(error));
+            }
+            return;
+        }
+
+        ctx.terminal.section('Themes');
+        for (const theme of themes.list()) {
+            const marker = theme.name === themes.current.name ? '●' : ' ';
+            const about = theme.description ? ` - ${theme.description}` : '';
+            ctx.terminal.info(`${marker} ${theme.name}${about}`);
+        }
+        ctx.terminal.muted(`Theme files: ${themes.directory} (*.toml, *.json)`);
+    },
+};
+
+// Command: /memory
+export const memoryCommand: SlashCommand = {
+    name: 'memory',
+    description: 'Show SUPERCACHE entries, hits and misses per tier (prune removes expired entries)',
+    usage: '/memory [prune]',
+    handler: async (ctx) => {
+        const cache = getCacheManager();
+
+        if (ctx.args[0] === 'prune') {
+            const pruned = await cache.prune();
+            ctx.terminal.success(`Pruned ${pruned} expired ${pruned === 1 ? 'entry' : 'entries'}`);
+        }
+
+        const stats = await cache.getStats();
+        const [header, ...rows] = formatCacheStats(stats);
+        ctx.terminal.section('SUPERCACHE');
+        ctx.terminal.muted(header);
+        for (const row of rows) {
+            ctx.terminal.info(row);
+        }
+        ctx.terminal.muted('TTL: reasoning 5m · project 24h · vault 7d (.floyd/.cache)');
+    },
+};
+
+// Command: /remember
+export const rememberCommand: SlashCommand = {
+    name: 'remember',
+    description: 'Save a fact to long-term memory, or list saved memories',
+    usage: '/remember [fact]',
+    handler: async (ctx) => {
+        if (!isMemoryEnabled()) {
+            ctx.terminal.warning('Long-term memory is off (FLOYD_MEMORY=off)');
+            return;
+        }
+
+        const store = getMemoryStore();
+        const text = ctx.args.join(' ').trim();
+        if (text) {
+            try {
+                const entry = await store.add('fact', text);
+                ctx.terminal.success(`Remembered (${entry.id})`);
+            } catch (error) {
+                ctx.terminal.error(`Failed to save memory: ${error instanceof Error ? error.message : String(error)}`);
+            }
+            return;
+        }
+
+        const entries = await store.list();
+        if (entries.length === 0) {
+            ctx.terminal.info('No memories yet. Use /remember <fact>, or let the agent save them with the remember tool.');
+            return;
+        }
+        ctx.terminal.section('Memories');
+        for (const entry of entries) {
+            const source = entry.source ? ` (${entry.source})` : '';
+            ctx.terminal.info(`${entry.id}  [${entry.kind}]${source} ${entry.text}`);
+        }
+        ctx.terminal.muted('Remove one with /forget <id>');
+    },
+};
+
+// Command: /forget
+export const forgetCommand: SlashCommand = {
+    name: 'forget',
+    description: 'Remove a memory from long-term memory',
+    usage: '/forget <id>',
+    handler: async (ctx) => {
+        const id = ctx.args[0] || await promptForInput('Memory id: ');
+        if (!id) {
+            ctx.terminal.error('Usage: /forget <id>');
+            return;
+        }
+
+        if (await getMemoryStore().remove(id)) {
+            ctx.terminal.success(`Forgot ${id}`);
+        } else {
+            ctx.terminal.error(`No memory with id "${id}" (see /remember)`);
+        }
     },
 };
 
@@ -348,9 +664,17 @@ export const builtInCommands: SlashCommand[] = [
     compactCommand,
     historyCommand,
     sessionsCommand,
+    tagCommand,
+    untagCommand,
+    searchCommand,
     checkpointsCommand,
     restoreCommand,
     exportCommand,
     helpCommand,
     statsCommand,
+    logsCommand,
+    themeCommand,
+    memoryCommand,
+    rememberCommand,
+    forgetCommand,
 ];
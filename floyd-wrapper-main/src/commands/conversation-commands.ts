@@ -0,0 +1,158 @@
+/**
+ * Conversation Commands - Floyd Wrapper
+ *
+ * Conversation checkpoints and branches. /checkpoint save snapshots the
+ * agent's messages; /rewind <checkpoint> and /branch fork continue from a
+ * snapshot in a new child session, so the path left behind stays in the
+ * session store as a sibling branch instead of being overwritten.
+ */
+
+import type { SlashCommand, SlashCommandContext } from './slash-commands.js';
+import type { BranchNode, ConversationCheckpoint } from '../persistence/session-manager.js';
+
+/**
+ * Render a branch tree, one line per session with its checkpoints
+ */
+export function renderBranchTree(
+  node: BranchNode,
+  currentSessionId: string | null,
+  checkpointsOf: (sessionId: string) => ConversationCheckpoint[] = () => [],
+): string[] {
+  const lines: string[] = [];
+
+  const walk = (branch: BranchNode, prefix: string, connector: string, childPrefix: string): void => {
+    const current = branch.session.id === currentSessionId ? ' (current)' : '';
+    lines.push(`${prefix}${connector}${branch.session.name} [${branch.session.id.slice(0, 8)}]${current}`);
+
+    const checkpoints = checkpointsOf(branch.session.id);
+    if (checkpoints.length > 0) {
+      lines.push(`${prefix}${childPrefix}  checkpoints: ${checkpoints.map(cp => cp.name).join(', ')}`);
+    }
+
+    branch.children.forEach((child, index) => {
+      const last = index === branch.children.length - 1;
+      walk(child, prefix + childPrefix, last ? '└─ ' : '├─ ', last ? '   ' : '│  ');
+    });
+  };
+
+  walk(node, '', '', '');
+  return lines;
+}
+
+/**
+ * Snapshot the engine's conversation in the current session
+ */
+export function saveConversationCheckpoint(ctx: SlashCommandContext, name?: string): void {
+  if (!ctx.sessionManager || typeof ctx.engine?.getHistory !== 'function') {
+    ctx.terminal.error('No active session');
+    return;
+  }
+
+  const existing = ctx.sessionManager.listConversationCheckpoints();
+  const checkpointName = name || `checkpoint-${existing.length + 1}`;
+
+  try {
+    const checkpoint = ctx.sessionManager.createConversationCheckpoint(checkpointName, ctx.engine.getHistory().messages);
+    ctx.terminal.success(`Checkpoint "${checkpoint.name}" saved (${checkpoint.messageCount} messages)`);
+    ctx.terminal.muted(`Return to it with /rewind ${checkpoint.name}, or try another path with /branch fork ${checkpoint.name}`);
+  } catch (error) {
+    ctx.terminal.error(error instanceof Error ? error.message : String(error));
+  }
+}
+
+/**
+ * Continue from a conversation checkpoint in a new branch and load it into
+ * the engine
+ *
+ * @returns Whether a conversation checkpoint matched
+ */
+export function forkConversation(ctx: SlashCommandContext, checkpointIdOrName: string, branchName?: string): boolean {
+  const checkpoint = ctx.sessionManager?.getConversationCheckpoint(checkpointIdOrName);
+  if (!ctx.sessionManager || !checkpoint) {
+    return false;
+  }
+
+  const session = ctx.sessionManager.forkSession(checkpoint.id, branchName);
+  ctx.engine?.restoreHistory?.(checkpoint.messages);
+  ctx.terminal.success(`Now on branch "${session.name}" (${checkpoint.messageCount} messages from checkpoint "${checkpoint.name}")`);
+  ctx.terminal.muted('The previous path is kept - see /branch list');
+  return true;
+}
+
+// Command: /branch
+export const branchCommand: SlashCommand = {
+  name: 'branch',
+  description: 'Show the conversation branch tree, fork from a checkpoint, or switch branches',
+  usage: '/branch [list | fork [checkpoint|here] [name] | switch <branch>]',
+  aliases: ['br'],
+  handler: async (ctx) => {
+    const sessionManager = ctx.sessionManager;
+    if (!sessionManager) {
+      ctx.terminal.error('Session manager not initialized');
+      return;
+    }
+    const [action = 'list', target, ...rest] = ctx.args;
+
+    switch (action) {
+      case 'list':
+      case 'ls': {
+        const tree = sessionManager.getBranchTree();
+        if (!tree) {
+          ctx.terminal.muted('No active session.');
+          return;
+        }
+        ctx.terminal.section('Conversation Branches');
+        for (const line of renderBranchTree(tree, sessionManager.getCurrentSessionId(), id => sessionManager.listConversationCheckpoints(id))) {
+          ctx.terminal.info(line);
+        }
+        return;
+      }
+
+      case 'fork': {
+        if ((!target || target === 'here') && typeof ctx.engine?.getHistory !== 'function') {
+          ctx.terminal.error('No active engine');
+          return;
+        }
+        try {
+          // Fork from the current point unless a checkpoint is named
+          const checkpoint = target && target !== 'here'
+            ? target
+            : sessionManager.createConversationCheckpoint(`fork-${Date.now().toString(36)}`, ctx.engine.getHistory().messages).id;
+          if (!forkConversation(ctx, checkpoint, rest.join(' ') || undefined)) {
+            ctx.terminal.error(`Checkpoint not found: ${checkpoint}`);
+            ctx.terminal.muted('Save one with /checkpoint save [name]');
+          }
+        } catch (error) {
+          ctx.terminal.error(`Failed to fork: ${error instanceof Error ? error.message : String(error)}`);
+        }
+        return;
+      }
+
+      case 'switch':
+      case 'checkout': {
+        const name = [target, ...rest].filter(Boolean).join(' ');
+        if (!name) {
+          ctx.terminal.error('Usage: /branch switch <branch name or id>');
+          return;
+        }
+        const match = sessionManager.listSessions().find(session => session.id.startsWith(name) || session.name === name);
+        const session = match ? sessionManager.loadSession(match.id) : null;
+        if (!session) {
+          ctx.terminal.error(`Branch not found: ${name}`);
+          return;
+        }
+        ctx.engine?.restoreHistory?.(sessionManager.getHistory());
+        ctx.terminal.success(`Switched to branch "${session.name}"`);
+        return;
+      }
+
+      default:
+        ctx.terminal.error(`Unknown action: ${action}`);
+        ctx.terminal.muted(`Usage: ${branchCommand.usage}`);
+    }
+  },
+};
+
+export const conversationCommands: SlashCommand[] = [
+  branchCommand,
+];
@@ -0,0 +1,124 @@
+/**
+ * Direct Tool Commands - Floyd Wrapper
+ *
+ * /run, /read and /tool invoke tools without the model, so FLOYD stays
+ * useful in offline mode (and saves a round trip when you know what you
+ * want). The user typed the call, so no permission prompt is shown; safety
+ * rules and diff preview still apply.
+ */
+
+import type { SlashCommand, SlashCommandContext } from './slash-commands.js';
+import { toolRegistry } from '../tools/tool-registry.js';
+import type { ToolResult } from '../types.js';
+import { loadShellConfig } from '../tools/system/shell-config.js';
+
+/**
+ * Run a tool and report failures; returns the result for rendering
+ */
+async function invoke(ctx: SlashCommandContext, name: string, input: Record<string, unknown>): Promise<ToolResult | null> {
+  const result = await toolRegistry.execute(name, input, { permissionGranted: true });
+  if (!result.success && result.error) {
+    ctx.terminal.toolError(name, result.error.message);
+    return null;
+  }
+  return result;
+}
+
+// Command: /run
+export const runCommand: SlashCommand = {
+  name: 'run',
+  description: 'Run a shell command directly (no model call)',
+  usage: '/run <command>',
+  aliases: ['sh'],
+  handler: async (ctx) => {
+    const commandLine = ctx.args.join(' ').trim();
+    if (!commandLine) {
+      ctx.terminal.error('Usage: /run <command>');
+      return;
+    }
+
+    // Output streams to the terminal while the command runs; the run tool
+    // hands the line to the shell from .floyd/shell.toml when one is set
+    const result = await invoke(ctx, 'run', loadShellConfig(ctx.cwd).shell
+      ? { command: commandLine }
+      : { command: process.env.SHELL || 'sh', args: ['-c', commandLine] });
+    const data = result?.data as { exitCode?: number | null; duration?: number } | undefined;
+    if (!result || !data) {
+      return;
+    }
+    const seconds = ((data.duration ?? 0) / 1000).toFixed(1);
+    if (result.success) {
+      ctx.terminal.toolSuccess(`run (exit 0 in ${seconds}s)`);
+    } else {
+      ctx.terminal.toolError('run', `exit ${data.exitCode ?? 'killed'} after ${seconds}s`);
+    }
+  },
+};
+
+// Command: /read
+export const readCommand: SlashCommand = {
+  name: 'read',
+  description: 'Show a file directly (no model call)',
+  usage: '/read <file> [offset] [limit]',
+  handler: async (ctx) => {
+    const [filePath, offset, limit] = ctx.args;
+    if (!filePath) {
+      ctx.terminal.error('Usage: /read <file> [offset] [limit]');
+      return;
+    }
+
+    const result = await invoke(ctx, 'read_file', {
+      file_path: filePath,
+      offset: offset ? parseInt(offset, 10) : undefined,
+      limit: limit ? parseInt(limit, 10) : undefined,
+      fresh: true,
+    });
+    const content = (result?.data as { content?: string } | undefined)?.content;
+    if (content !== undefined) {
+      console.log(content);
+    }
+  },
+};
+
+// Command: /tool
+export const toolCommand: SlashCommand = {
+  name: 'tool',
+  description: 'Call any tool directly with JSON input (no model call)',
+  usage: '/tool <name> [json input]',
+  handler: async (ctx) => {
+    const [name, ...rest] = ctx.args;
+    if (!name) {
+      ctx.terminal.error('Usage: /tool <name> [json input]');
+      ctx.terminal.muted(`Tools: ${toolRegistry.getAll().map(tool => tool.name).sort().join(', ')}`);
+      return;
+    }
+    if (!toolRegistry.get(name)) {
+      ctx.terminal.error(`Unknown tool: ${name}`);
+      return;
+    }
+
+    let input: Record<string, unknown> = {};
+    const json = rest.join(' ').trim();
+    if (json) {
+      try {
+        input = JSON.parse(json) as Record<string, unknown>;
+      } catch (error) {
+        ctx.terminal.error(`Input is not valid JSON: ${error instanceof Error ? error.message : String(error)}`);
+        return;
+      }
+    }
+
+    const result = await invoke(ctx, name, input);
+    if (result) {
+      ctx.terminal.toolSuccess(name);
+      const data = result.data;
+      console.log(typeof data === 'string' ? data : JSON.stringify(data, null, 2));
+    }
+  },
+};
+
+export const directCommands: SlashCommand[] = [
+  runCommand,
+  readCommand,
+  toolCommand,
+];
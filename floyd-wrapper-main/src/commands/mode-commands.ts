@@ -10,6 +10,7 @@
 import type { SlashCommand } from './slash-commands.js';
 import type { ExecutionMode } from '../types.js';
 import { fuckitState } from '../utils/fuckit-state.js';
+import { isDryRun, setDryRun } from '../permissions/dry-run.js';
 
 // Valid modes
 const VALID_MODES: ExecutionMode[] = ['ask', 'yolo', 'plan', 'auto', 'dialogue', 'fuckit'];
@@ -148,4 +149,30 @@ export const confirmFuckitCommand: SlashCommand = {
     },
 };
 
-export const modeCommands = [modeCommand, confirmFuckitCommand];
+// Command: /dryrun
+export const dryRunCommand: SlashCommand = {
+    name: 'dryrun',
+    description: 'Toggle dry run: simulate tool calls that would change something',
+    usage: '/dryrun [on|off]',
+    aliases: ['dry-run'],
+    handler: async (ctx) => {
+        const arg = ctx.args[0]?.toLowerCase();
+        if (arg && arg !== 'on' && arg !== 'off') {
+            ctx.terminal.error('Usage: /dryrun [on|off]');
+            return;
+        }
+
+        const enabled = arg ? arg === 'on' : !isDryRun();
+        setDryRun(enabled);
+
+        if (enabled) {
+            ctx.terminal.warning('🧪 Dry run ON: writes, edits and commands are simulated, not executed.');
+            ctx.terminal.muted('Read-only tools still run. Simulated calls show the command, target files and diff.');
+        } else {
+            ctx.terminal.success('Dry run OFF: tool calls execute normally.');
+        }
+        ctx.terminal.muted('The model is told on its next request.');
+    },
+};
+
+export const modeCommands = [modeCommand, confirmFuckitCommand, dryRunCommand];
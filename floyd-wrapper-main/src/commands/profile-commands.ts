@@ -0,0 +1,117 @@
+/**
+ * Profile Commands - Floyd Wrapper
+ *
+ * /profile lists run profiles and switches the active one mid-session
+ * (model, tool set, budgets, mode and verbosity at once, and the provider
+ * connection for profiles that set one). /toolprofile does the same for
+ * tool profiles (reader, editor, admin).
+ */
+
+import type { SlashCommand } from './slash-commands.js';
+import { loadProfiles, applyProfile, formatProfile } from '../utils/profiles.js';
+import { loadToolProfiles, formatToolProfile } from '../utils/tool-profiles.js';
+import { getDiffPreviewer } from '../permissions/diff-preview.js';
+import { logger } from '../utils/logger.js';
+
+// Command: /profile
+export const profileCommand: SlashCommand = {
+    name: 'profile',
+    description: 'List run profiles or switch to one',
+    usage: '/profile [name]',
+    handler: async (ctx) => {
+        const profiles = loadProfiles(ctx.cwd);
+        const current = ctx.engine?.getConfig?.().profile as string | undefined;
+        const name = ctx.args[0];
+
+        if (!name) {
+            ctx.terminal.section('Run Profiles');
+            for (const profile of Object.values(profiles)) {
+                const marker = profile.name === current ? '●' : '○';
+                ctx.terminal.info(`${marker} ${profile.name}${profile.description ? ` - ${profile.description}` : ''}`);
+                ctx.terminal.muted(`    ${formatProfile(profile)}`);
+            }
+            ctx.terminal.blank();
+            ctx.terminal.muted('Define your own in .floyd/profiles.json or ~/.floyd/profiles.json');
+            return;
+        }
+
+        const profile = profiles[name];
+        if (!profile) {
+            ctx.terminal.error(`Unknown profile: ${name}`);
+            ctx.terminal.info(`Available: ${Object.keys(profiles).join(', ')}`);
+            return;
+        }
+
+        if (typeof ctx.engine?.updateConfig !== 'function') {
+            ctx.terminal.error('No active engine to apply the profile to');
+            return;
+        }
+
+        const config = applyProfile(ctx.engine.getConfig(), profile);
+        ctx.engine.updateConfig(config);
+        if (profile.verbosity) {
+            logger.setLevel(profile.verbosity);
+        }
+        if (profile.diffPreview !== undefined) {
+            getDiffPreviewer().setEnabled(profile.diffPreview);
+        }
+
+        ctx.terminal.success(`Profile: ${profile.name}`);
+        ctx.terminal.muted(formatProfile(profile));
+        if (!config.glmApiKey) {
+            ctx.terminal.warning(`No API key for this profile. Run \`floyd auth login ${profile.provider ?? '<provider>'}\`${profile.apiKeyEnv ? ` or set ${profile.apiKeyEnv}` : ''}.`);
+        }
+    },
+};
+
+// Command: /toolprofile
+export const toolProfileCommand: SlashCommand = {
+    name: 'toolprofile',
+    description: 'List tool profiles or lock the session to one',
+    usage: '/toolprofile [name|off]',
+    aliases: ['tool-profile'],
+    handler: async (ctx) => {
+        const profiles = loadToolProfiles(ctx.cwd);
+        const current = ctx.engine?.getConfig?.().toolProfile as string | undefined;
+        const name = ctx.args[0];
+
+        if (!name) {
+            ctx.terminal.section('Tool Profiles');
+            for (const profile of Object.values(profiles)) {
+                const marker = profile.name === current ? '●' : '○';
+                ctx.terminal.info(`${marker} ${profile.name}${profile.description ? ` - ${profile.description}` : ''}`);
+                ctx.terminal.muted(`    ${formatToolProfile(profile)}`);
+            }
+            ctx.terminal.blank();
+            ctx.terminal.muted(current ? `Locked to ${current}; /toolprofile off lifts the lock` : 'No tool profile: every tool is available');
+            ctx.terminal.muted('Define your own in .floyd/tool-profiles.json or ~/.floyd/tool-profiles.json');
+            return;
+        }
+
+        if (name !== 'off' && !profiles[name]) {
+            ctx.terminal.error(`Unknown tool profile: ${name}`);
+            ctx.terminal.info(`Available: ${Object.keys(profiles).join(', ')}`);
+            return;
+        }
+
+        if (typeof ctx.engine?.updateConfig !== 'function') {
+            ctx.terminal.error('No active engine to apply the tool profile to');
+            return;
+        }
+
+        const toolProfile = name === 'off' ? undefined : name;
+        ctx.engine.updateConfig({ ...ctx.engine.getConfig(), toolProfile });
+
+        if (toolProfile) {
+            ctx.terminal.success(`Tool profile: ${toolProfile}`);
+            ctx.terminal.muted(formatToolProfile(profiles[toolProfile]));
+        } else {
+            ctx.terminal.success('Tool profile off: every tool is available');
+        }
+    },
+};
+
+export const profileCommands: SlashCommand[] = [
+    profileCommand,
+    toolProfileCommand,
+];
@@ -0,0 +1,83 @@
+/**
+ * Progress Commands - Floyd Wrapper
+ *
+ * /progress shows the latest entries of .floyd/progress.md as a table,
+ * optionally filtered by day or text.
+ */
+
+import chalk from 'chalk';
+import {
+  readProgress,
+  queryProgress,
+  parseProgressArgs,
+  PROGRESS_FILE,
+  PROGRESS_USAGE,
+  type ProgressEntry,
+} from 'floyd-agent-core/utils';
+import type { SlashCommand } from './slash-commands.js';
+import { CRUSH_THEME } from '../constants.js';
+
+const DEFAULT_ENTRIES = 10;
+
+const HEADERS = ['Timestamp', 'Action', 'Result', 'Next'];
+
+function fit(text: string, width: number): string {
+  return text.length > width ? `${text.slice(0, Math.max(width - 1, 0))}…` : text.padEnd(width);
+}
+
+/**
+ * Entries as an aligned table no wider than `width`; long cells are cut
+ */
+export function renderProgressTable(entries: ProgressEntry[], width: number = process.stdout.columns || 100): string[] {
+  const rows = entries.map(entry => [entry.timestamp, entry.action, entry.result, entry.next]);
+  const natural = HEADERS.map((header, column) =>
+    Math.max(header.length, ...rows.map(row => row[column].length))
+  );
+
+  // Timestamps stay whole; the other columns share what is left
+  const available = Math.max(width - natural[0] - 3 * 3, 30);
+  const wanted = natural.slice(1).reduce((sum, value) => sum + value, 0);
+  const widths = wanted <= available
+    ? natural
+    : [natural[0], ...natural.slice(1).map(value => Math.max(6, Math.floor((value / wanted) * available)))];
+
+  const line = (cells: string[]) => cells.map((cell, column) => fit(cell, widths[column])).join('   ').trimEnd();
+  return [
+    line(HEADERS),
+    widths.map(value => '─'.repeat(value)).join('   '),
+    ...rows.map(line),
+  ];
+}
+
+// Command: /progress
+export const progressCommand: SlashCommand = {
+  name: 'progress',
+  description: 'Show the latest entries of .floyd/progress.md',
+  usage: PROGRESS_USAGE,
+  handler: async (ctx) => {
+    const query = parseProgressArgs(ctx.args);
+    const all = readProgress(ctx.cwd);
+    if (all.length === 0) {
+      ctx.terminal.muted(`No entries in ${PROGRESS_FILE} yet.`);
+      return;
+    }
+
+    const entries = queryProgress(all, { ...query, last: query.last ?? DEFAULT_ENTRIES });
+    ctx.terminal.section(`Progress (${entries.length} of ${all.length})`);
+    if (entries.length === 0) {
+      ctx.terminal.muted('No matching entries.');
+      return;
+    }
+
+    const [header, rule, ...rows] = renderProgressTable(entries);
+    console.log(chalk.hex(CRUSH_THEME.colors.primary).bold(header));
+    console.log(chalk.hex(CRUSH_THEME.colors.muted)(rule));
+    for (const row of rows) {
+      console.log(row);
+    }
+  },
+};
+
+export const progressCommands: SlashCommand[] = [
+  progressCommand,
+];
@@ -0,0 +1,115 @@
+/**
+ * Prompt Commands - Floyd Wrapper
+ *
+ * /prompt shows the system prompt exactly as the next request will send it
+ * (protocol, working context, mode, project instructions, capabilities and
+ * rules), with token estimates per section and for the tool definitions.
+ * /prompt edit opens .floyd/AGENT_INSTRUCTIONS.md in $EDITOR and reloads
+ * the prompt as soon as the editor closes.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import type { SlashCommand } from './slash-commands.js';
+import { AGENT_INSTRUCTIONS_FILE } from '../utils/project-instructions.js';
+import { openInEditor } from '../utils/editor.js';
+
+/**
+ * Rough chars-per-token ratio, as used for prompt size estimates elsewhere
+ */
+const CHARS_PER_TOKEN = 4;
+
+const AGENT_INSTRUCTIONS_TEMPLATE = `# Agent Instructions
+
+Instructions for FLOYD in this project, added to the system prompt.
+Save and close the editor to apply them.
+`;
+
+export function estimateTokens(text: string): number {
+  return Math.ceil(text.length / CHARS_PER_TOKEN);
+}
+
+/**
+ * Split a prompt into its top-level sections (## headings); text before the
+ * first heading is the preamble
+ */
+export function splitPromptSections(prompt: string): Array<{ title: string; text: string }> {
+  const sections: Array<{ title: string; text: string }> = [];
+  let current = { title: 'Preamble', lines: [] as string[] };
+
+  for (const line of prompt.split('\n')) {
+    const heading = line.match(/^##\s+(.+)$/);
+    if (heading) {
+      if (current.lines.join('').trim()) {
+        sections.push({ title: current.title, text: current.lines.join('\n') });
+      }
+      current = { title: heading[1].trim(), lines: [] };
+    }
+    current.lines.push(line);
+  }
+  if (current.lines.join('').trim()) {
+    sections.push({ title: current.title, text: current.lines.join('\n') });
+  }
+  return sections;
+}
+
+// Command: /prompt
+export const promptCommand: SlashCommand = {
+  name: 'prompt',
+  description: 'Show the assembled system prompt, or edit and reload project instructions',
+  usage: '/prompt [summary | edit | reload]',
+  handler: async (ctx) => {
+    const engine = ctx.engine;
+    if (typeof engine?.getSystemPrompt !== 'function') {
+      ctx.terminal.error('No active engine');
+      return;
+    }
+    const action = ctx.args[0] ?? 'show';
+
+    if (action === 'edit') {
+      const file = path.join(ctx.cwd, AGENT_INSTRUCTIONS_FILE);
+      if (!(await fs.pathExists(file))) {
+        await fs.outputFile(file, AGENT_INSTRUCTIONS_TEMPLATE);
+      }
+      const status = openInEditor(file);
+      if (status !== 0) {
+        ctx.terminal.warning(`Editor exited with status ${status}`);
+      }
+    }
+
+    if (action === 'edit' || action === 'reload') {
+      const before = estimateTokens(engine.getSystemPrompt());
+      engine.reloadProjectContext();
+      const after = estimateTokens(engine.getSystemPrompt());
+      ctx.terminal.success(`System prompt reloaded (~${after.toLocaleString('en-US')} tokens, ${after >= before ? '+' : ''}${after - before})`);
+      return;
+    }
+
+    if (action !== 'show' && action !== 'summary') {
+      ctx.terminal.error(`Unknown action: ${action}`);
+      ctx.terminal.muted(`Usage: ${promptCommand.usage}`);
+      return;
+    }
+
+    const prompt: string = engine.getSystemPrompt();
+    if (action === 'show') {
+      ctx.terminal.section('System Prompt');
+      console.log(prompt);
+      ctx.terminal.blank();
+    }
+
+    ctx.terminal.section('Prompt Size (estimated)');
+    for (const section of splitPromptSections(prompt)) {
+      ctx.terminal.info(`${String(estimateTokens(section.text)).padStart(7)}  ${section.title}`);
+    }
+    const tools = engine.getToolDefinitions() as unknown[];
+    const toolTokens = estimateTokens(JSON.stringify(tools));
+    ctx.terminal.info(`${String(toolTokens).padStart(7)}  Tool definitions (${tools.length} tools)`);
+    ctx.terminal.primary(`${String(estimateTokens(prompt) + toolTokens).padStart(7)}  Total per request, before the conversation`);
+    ctx.terminal.muted(`Edit ${AGENT_INSTRUCTIONS_FILE} with /prompt edit`);
+  },
+};
+
+export const promptCommands: SlashCommand[] = [
+  promptCommand,
+];
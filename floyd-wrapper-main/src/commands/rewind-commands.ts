@@ -6,8 +6,9 @@
 
 import readline from 'node:readline';
 import type { SlashCommand } from './slash-commands.js';
-import { getCheckpointManager, formatBytes } from '../rewind/index.js';
+import { getCheckpointManager, getWorkspaceSnapshotManager, formatBytes } from '../rewind/index.js';
 import { getSandboxManager } from '../sandbox/index.js';
+import { saveConversationCheckpoint, forkConversation } from './conversation-commands.js';
 
 /**
  * Helper function to prompt user for input
@@ -33,8 +34,8 @@ async function promptForInput(question: string): Promise<string> {
 // Command: /checkpoint
 export const checkpointCommand: SlashCommand = {
   name: 'checkpoint',
-  description: 'Manage file checkpoints (list, create, restore, clear)',
-  usage: '/checkpoint [list|create|restore|clear] [args]',
+  description: 'Save conversation checkpoints and manage file checkpoints (list, create, restore, clear)',
+  usage: '/checkpoint [save [name]|list|create|restore|clear] [args]',
   aliases: ['cp'],
   handler: async (ctx) => {
     const subcommand = ctx.args[0]?.toLowerCase() || 'list';
@@ -42,8 +43,22 @@ export const checkpointCommand: SlashCommand = {
     await checkpointManager.initialize();
 
     switch (subcommand) {
+      case 'save': {
+        saveConversationCheckpoint(ctx, ctx.args.slice(1).join(' ') || undefined);
+        break;
+      }
+
       case 'list':
       case 'ls': {
+        const conversation = ctx.sessionManager?.listConversationCheckpoints() ?? [];
+        if (conversation.length > 0) {
+          ctx.terminal.section('💬 Conversation Checkpoints');
+          for (const cp of conversation) {
+            ctx.terminal.info(cp.name);
+            ctx.terminal.muted(`  ID: ${cp.id.slice(0, 8)} | Messages: ${cp.messageCount} | ${new Date(cp.timestamp).toLocaleString()}`);
+          }
+        }
+
         const checkpoints = checkpointManager.getAllCheckpoints();
         ctx.terminal.section('📁 File Checkpoints');
 
@@ -170,23 +185,79 @@ export const checkpointCommand: SlashCommand = {
 
       default:
         ctx.terminal.error(`Unknown subcommand: ${subcommand}`);
-        ctx.terminal.muted('Available: list, create, restore, clear, stats');
+        ctx.terminal.muted('Available: save, list, create, restore, clear, stats');
     }
   },
 };
 
-// Command: /rewind (alias for checkpoint restore)
+// Command: /rewind (conversation checkpoint, else alias for checkpoint restore)
 export const rewindCommand: SlashCommand = {
   name: 'rewind',
-  description: 'Restore to a previous checkpoint (shortcut for /checkpoint restore)',
-  usage: '/rewind [checkpoint-id]',
+  description: 'Go back to a conversation checkpoint (on a new branch) or restore a file checkpoint',
+  usage: '/rewind [checkpoint]',
   handler: async (ctx) => {
+    if (ctx.args[0]) {
+      try {
+        if (forkConversation(ctx, ctx.args.join(' '))) {
+          return;
+        }
+      } catch (error) {
+        ctx.terminal.error(`Failed to rewind: ${error instanceof Error ? error.message : String(error)}`);
+        return;
+      }
+    }
+
     // Delegate to checkpoint command with restore subcommand
     ctx.args = ['restore', ...ctx.args];
     await checkpointCommand.handler(ctx);
   },
 };
 
+// ============================================================================
+// WORKSPACE SNAPSHOT COMMANDS
+// ============================================================================
+
+// Command: /snapshot
+export const snapshotCommand: SlashCommand = {
+  name: 'snapshot',
+  description: 'Snapshot the whole workspace before risky multi-file work (restore with /restore)',
+  usage: '/snapshot [name] | /snapshot list',
+  aliases: ['snap'],
+  handler: async (ctx) => {
+    const snapshotManager = getWorkspaceSnapshotManager();
+
+    if (ctx.args[0]?.toLowerCase() === 'list' || ctx.args[0]?.toLowerCase() === 'ls') {
+      const snapshots = await snapshotManager.list();
+      ctx.terminal.section('📸 Workspace Snapshots');
+
+      if (snapshots.length === 0) {
+        ctx.terminal.muted('No snapshots yet. Use /snapshot [name] to create one.');
+        return;
+      }
+
+      for (const snapshot of snapshots.slice(0, 10)) {
+        ctx.terminal.info(snapshot.name === snapshot.id ? snapshot.id : `${snapshot.name} (${snapshot.id})`);
+        ctx.terminal.muted(`  Time: ${new Date(snapshot.createdAt).toLocaleString()}`);
+        ctx.terminal.muted(`  Git: ${snapshot.gitCommit ? snapshot.gitCommit.slice(0, 12) : 'n/a'} | Agent-tracked files: ${snapshot.files.length}`);
+      }
+      return;
+    }
+
+    try {
+      const snapshot = await snapshotManager.create(ctx.args.join(' ') || undefined);
+      ctx.terminal.success(`✓ Workspace snapshot created: ${snapshot.name}`);
+      ctx.terminal.muted(`  ID: ${snapshot.id}`);
+      if (snapshot.gitCommit) {
+        ctx.terminal.muted(`  Git tree: ${snapshot.gitCommit.slice(0, 12)}`);
+      }
+      ctx.terminal.muted(`  Agent-tracked files: ${snapshot.files.length}`);
+      ctx.terminal.muted(`  Roll back with: /restore ${snapshot.name}`);
+    } catch (error) {
+      ctx.terminal.error(`Failed to create snapshot: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  },
+};
+
 // ============================================================================
 // SANDBOX COMMANDS
 // ============================================================================
@@ -195,7 +266,7 @@ export const rewindCommand: SlashCommand = {
 export const sandboxCommand: SlashCommand = {
   name: 'sandbox',
   description: 'Manage sandbox mode for safe YOLO execution',
-  usage: '/sandbox [start|status|commit|discard|diff]',
+  usage: '/sandbox [start [overlay]|status|diff|patch|commit|discard]',
   aliases: ['sb'],
   handler: async (ctx) => {
     const subcommand = ctx.args[0]?.toLowerCase() || 'status';
@@ -214,13 +285,20 @@ export const sandboxCommand: SlashCommand = {
           return;
         }
 
+        const overlay = ctx.args[1]?.toLowerCase() === 'overlay';
+
         try {
-          const session = await sandboxManager.start(ctx.cwd);
-          ctx.terminal.success('🔒 Sandbox mode activated');
+          const session = await sandboxManager.start(ctx.cwd, overlay ? 'overlay' : undefined);
+          ctx.terminal.success(overlay ? '🔒 Overlay sandbox activated' : '🔒 Sandbox mode activated');
           ctx.terminal.muted(`  ID: ${session.id}`);
           ctx.terminal.muted(`  Root: ${session.sandboxRoot}`);
           ctx.terminal.blank();
-          ctx.terminal.info('All file operations will now be isolated.');
+          if (overlay) {
+            ctx.terminal.info('File writes now go to the overlay in every mode; the live repo is untouched.');
+            ctx.terminal.muted('Shell commands (run) still execute in the live workspace.');
+          } else {
+            ctx.terminal.info('All file operations will now be isolated.');
+          }
           ctx.terminal.info('Use /sandbox commit to apply changes, or /sandbox discard to cancel.');
         } catch (error) {
           ctx.terminal.error(`Failed to start sandbox: ${error instanceof Error ? error.message : String(error)}`);
@@ -239,7 +317,7 @@ export const sandboxCommand: SlashCommand = {
         const summary = sandboxManager.getChangesSummary();
 
         ctx.terminal.section('🔒 Sandbox Status');
-        ctx.terminal.info(`State: ${session?.state}`);
+        ctx.terminal.info(`State: ${session?.state} (${session?.type})`);
         ctx.terminal.muted(`ID: ${session?.id}`);
         ctx.terminal.muted(`Started: ${new Date(session?.createdAt || 0).toLocaleString()}`);
 
@@ -274,6 +352,22 @@ export const sandboxCommand: SlashCommand = {
         break;
       }
 
+      case 'patch': {
+        if (!sandboxManager.isActive()) {
+          ctx.terminal.error('Sandbox is not active.');
+          return;
+        }
+
+        const patchPath = await sandboxManager.writePatch();
+        if (!patchPath) {
+          ctx.terminal.info('No changes in sandbox.');
+          return;
+        }
+        ctx.terminal.success(`✓ Patch written to ${patchPath}`);
+        ctx.terminal.muted('  Review it, or apply it elsewhere with: git apply <patch>');
+        break;
+      }
+
       case 'commit':
       case 'apply': {
         if (!sandboxManager.isActive()) {
@@ -307,6 +401,9 @@ export const sandboxCommand: SlashCommand = {
             ctx.terminal.warning(`Committed with errors: ${result.failed.length} failed`);
             result.failed.forEach(f => ctx.terminal.muted(`  Failed: ${f}`));
           }
+          if (result.patchPath) {
+            ctx.terminal.muted(`  Patch: ${result.patchPath}`);
+          }
         } catch (error) {
           ctx.terminal.error(`Commit failed: ${error instanceof Error ? error.message : String(error)}`);
         }
@@ -337,7 +434,7 @@ export const sandboxCommand: SlashCommand = {
 
       default:
         ctx.terminal.error(`Unknown subcommand: ${subcommand}`);
-        ctx.terminal.muted('Available: start, status, diff, commit, discard');
+        ctx.terminal.muted('Available: start [overlay], status, diff, patch, commit, discard');
     }
   },
 };
@@ -349,5 +446,6 @@ export const sandboxCommand: SlashCommand = {
 export const rewindCommands: SlashCommand[] = [
   checkpointCommand,
   rewindCommand,
+  snapshotCommand,
   sandboxCommand,
 ];
@@ -0,0 +1,66 @@
+/**
+ * Scratchpad Commands - Floyd Wrapper
+ *
+ * /scratchpad searches the entries of .floyd/scratchpad.md; /scratchpad
+ * errors lists the failures recorded there, clustered by tool and message.
+ */
+
+import chalk from 'chalk';
+import type { SlashCommand } from './slash-commands.js';
+import { readScratchpad, searchScratchpad, clusterErrors, SCRATCHPAD_FILE } from '../agent/scratchpad.js';
+import { CRUSH_THEME } from '../constants.js';
+
+const MAX_RESULTS = 10;
+
+// Command: /scratchpad
+export const scratchpadCommand: SlashCommand = {
+  name: 'scratchpad',
+  description: 'Search the scratchpad or list its recurring errors',
+  usage: '/scratchpad [text] | /scratchpad errors',
+  aliases: ['sp'],
+  handler: async (ctx) => {
+    const entries = await readScratchpad(ctx.cwd);
+    if (entries.length === 0) {
+      ctx.terminal.muted(`No entries in ${SCRATCHPAD_FILE} yet.`);
+      return;
+    }
+
+    if (ctx.args[0] === 'errors') {
+      const clusters = clusterErrors(entries);
+      ctx.terminal.section(`Recorded Errors (${clusters.length})`);
+      if (clusters.length === 0) {
+        ctx.terminal.muted('No failed tool calls recorded.');
+        return;
+      }
+      for (const cluster of clusters.slice(0, MAX_RESULTS)) {
+        console.log(`${chalk.hex(CRUSH_THEME.colors.primary).bold(`${cluster.count}×`)} ${cluster.tool}: ${cluster.example}`);
+        if (cluster.targets.length > 0) {
+          console.log(chalk.hex(CRUSH_THEME.colors.muted)(`   ${cluster.targets.slice(-3).join(', ')}`));
+        }
+      }
+      return;
+    }
+
+    const query = ctx.args.join(' ');
+    const matches = query ? searchScratchpad(entries, query) : [...entries].reverse();
+    ctx.terminal.section(query ? `Scratchpad: "${query}" (${matches.length})` : `Scratchpad (${entries.length})`);
+    if (matches.length === 0) {
+      ctx.terminal.muted('No matching entries.');
+      return;
+    }
+    for (const entry of matches.slice(0, MAX_RESULTS)) {
+      console.log(chalk.hex(CRUSH_THEME.colors.primary).bold(entry.title));
+      const error = entry.body.split('\n').find(line => line.startsWith('**Error:**'));
+      if (error) {
+        console.log(chalk.hex(CRUSH_THEME.colors.muted)(`   ${error.replace('**Error:**', '').trim()}`));
+      }
+    }
+    if (matches.length > MAX_RESULTS) {
+      ctx.terminal.muted(`…and ${matches.length - MAX_RESULTS} more`);
+    }
+  },
+};
+
+export const scratchpadCommands: SlashCommand[] = [
+  scratchpadCommand,
+];
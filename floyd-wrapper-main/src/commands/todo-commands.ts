@@ -0,0 +1,38 @@
+/**
+ * Todo Commands - Floyd Wrapper
+ *
+ * /todo shows the task list the model keeps with the `todo` tool (also
+ * mirrored to .floyd/master_plan.md); /todo clear empties it.
+ */
+
+import type { SlashCommand } from './slash-commands.js';
+import { getTodoList } from '../tools/todo/todo-core.js';
+import { renderTodoPanel } from '../ui/todo-panel.js';
+
+// Command: /todo
+export const todoCommand: SlashCommand = {
+  name: 'todo',
+  description: 'Show the task list for the current goal',
+  usage: '/todo [clear]',
+  aliases: ['tasks'],
+  handler: async (ctx) => {
+    const list = getTodoList();
+
+    if (ctx.args[0] === 'clear') {
+      list.clear();
+      ctx.terminal.success('Task list cleared');
+      return;
+    }
+
+    const snapshot = list.snapshot();
+    if (snapshot.items.length === 0) {
+      ctx.terminal.muted('No tasks yet. FLOYD keeps a task list with the todo tool during multi-step work.');
+      return;
+    }
+    console.log(renderTodoPanel(snapshot));
+  },
+};
+
+export const todoCommands: SlashCommand[] = [
+  todoCommand,
+];
@@ -58,6 +58,7 @@ export const TOOL_CATEGORIES = [
   'cache',
   'patch',
   'special',
+  'custom',
 ] as const;
 
 // ============================================================================
@@ -18,3 +18,16 @@ export type {
   InterruptEvent,
   InterruptManagerOptions,
 } from './interrupt-manager.js';
+
+export {
+  ShutdownController,
+  getShutdownController,
+  resetShutdownController,
+} from './shutdown-controller.js';
+
+export type {
+  ShutdownPhase,
+  ShutdownHandler,
+  ShutdownControllerOptions,
+  ShutdownReport,
+} from './shutdown-controller.js';
@@ -0,0 +1,287 @@
+/**
+ * Shutdown Controller - Floyd Wrapper
+ *
+ * Orderly shutdown for quitting while work is in flight:
+ * cancel running operations, stop child process groups, persist state,
+ * then restore the terminal. Each step is bounded by a timeout so a hung
+ * handler cannot keep the process alive.
+ *
+ * @module interrupts/shutdown-controller
+ */
+
+import type { ChildProcess } from 'node:child_process';
+import { logger } from '../utils/logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Shutdown phases, run in this order
+ * (child processes are stopped between 'cancel' and 'persist')
+ */
+export type ShutdownPhase =
+  | 'cancel'   // Abort LLM streams and running turns
+  | 'persist'  // Save sessions, flush traces, release locks
+  | 'restore'; // Restore the terminal
+
+/**
+ * Shutdown step handler
+ */
+export type ShutdownHandler = () => void | Promise<void>;
+
+/**
+ * Shutdown controller options
+ */
+export interface ShutdownControllerOptions {
+  /** Maximum time for each step (ms) */
+  stepTimeout?: number;
+  /** Time child processes get after SIGTERM before SIGKILL (ms) */
+  killGracePeriod?: number;
+}
+
+/**
+ * What happened during shutdown
+ */
+export interface ShutdownReport {
+  /** Why shutdown was requested */
+  reason: string;
+  /** Steps that did not finish within the step timeout */
+  timedOut: string[];
+  /** Steps that threw, as "name: message" */
+  failed: string[];
+  /** Child processes that needed SIGKILL */
+  killed: number;
+}
+
+interface ShutdownStep {
+  phase: ShutdownPhase;
+  name: string;
+  handler: ShutdownHandler;
+}
+
+const PHASES: ShutdownPhase[] = ['cancel', 'persist', 'restore'];
+
+// ============================================================================
+// Shutdown Controller Class
+// ============================================================================
+
+/**
+ * ShutdownController - Ordered, bounded shutdown
+ *
+ * Child processes are expected to be spawned with `detached: true` so each
+ * one leads its own process group; signalling the group also stops anything
+ * the command started (e.g. `npm test` spawning node).
+ */
+export class ShutdownController {
+  private readonly abortController = new AbortController();
+  private readonly steps: ShutdownStep[] = [];
+  private readonly processes = new Set<ChildProcess>();
+  private readonly options: Required<ShutdownControllerOptions>;
+  private shutdownPromise: Promise<ShutdownReport> | null = null;
+
+  constructor(options: ShutdownControllerOptions = {}) {
+    this.options = {
+      stepTimeout: options.stepTimeout ?? 3000,
+      killGracePeriod: options.killGracePeriod ?? 2000,
+    };
+  }
+
+  /**
+   * Aborted when shutdown starts; pass to long-running work
+   */
+  get signal(): AbortSignal {
+    return this.abortController.signal;
+  }
+
+  /**
+   * Whether shutdown has started
+   */
+  isShuttingDown(): boolean {
+    return this.shutdownPromise !== null;
+  }
+
+  /**
+   * Register a step to run during shutdown
+   * Returns a function that unregisters it
+   */
+  register(phase: ShutdownPhase, name: string, handler: ShutdownHandler): () => void {
+    const step = { phase, name, handler };
+    this.steps.push(step);
+    return () => {
+      const index = this.steps.indexOf(step);
+      if (index !== -1) {
+        this.steps.splice(index, 1);
+      }
+    };
+  }
+
+  /**
+   * Track a child process until it exits
+   */
+  trackProcess(child: ChildProcess): void {
+    if (child.exitCode !== null || child.signalCode !== null) {
+      return;
+    }
+
+    // A process started after shutdown began would be orphaned
+    if (this.isShuttingDown()) {
+      this.signalProcess(child, 'SIGKILL');
+      return;
+    }
+
+    this.processes.add(child);
+    child.once('exit', () => this.processes.delete(child));
+  }
+
+  /**
+   * Number of tracked processes still running
+   */
+  getProcessCount(): number {
+    return this.processes.size;
+  }
+
+  /**
+   * Stop all tracked processes: SIGTERM their groups, wait for the grace
+   * period, then SIGKILL whatever is left
+   * Returns the number of processes that had to be killed
+   */
+  async terminateProcesses(): Promise<number> {
+    const running = [...this.processes];
+    if (running.length === 0) {
+      return 0;
+    }
+
+    for (const child of running) {
+      this.signalProcess(child, 'SIGTERM');
+    }
+
+    const exited = (child: ChildProcess) =>
+      child.exitCode !== null || child.signalCode !== null
+        ? Promise.resolve()
+        : new Promise<void>(resolve => child.once('exit', () => resolve()));
+    await withTimeout(Promise.all(running.map(exited)), this.options.killGracePeriod);
+
+    const remaining = running.filter(child => this.processes.has(child));
+    for (const child of remaining) {
+      this.signalProcess(child, 'SIGKILL');
+    }
+    return remaining.length;
+  }
+
+  /**
+   * SIGKILL all tracked process groups immediately
+   * For exit handlers, which cannot wait
+   */
+  killProcessesSync(): void {
+    for (const child of this.processes) {
+      this.signalProcess(child, 'SIGKILL');
+    }
+  }
+
+  /**
+   * Run the shutdown pipeline
+   * Safe to call more than once: later calls get the first call's result
+   */
+  shutdown(reason: string = 'shutdown'): Promise<ShutdownReport> {
+    if (!this.shutdownPromise) {
+      this.shutdownPromise = this.run(reason);
+    }
+    return this.shutdownPromise;
+  }
+
+  private async run(reason: string): Promise<ShutdownReport> {
+    logger.info('Shutting down', { reason, processes: this.processes.size });
+    const report: ShutdownReport = { reason, timedOut: [], failed: [], killed: 0 };
+
+    this.abortController.abort(reason);
+
+    for (const phase of PHASES) {
+      // Steps within a phase are independent, so they run together
+      const steps = this.steps.filter(step => step.phase === phase);
+      await Promise.all(steps.map(step => this.runStep(step, report)));
+
+      if (phase === 'cancel') {
+        report.killed = await this.terminateProcesses();
+      }
+    }
+
+    logger.info('Shutdown complete', { ...report });
+    return report;
+  }
+
+  private async runStep(step: ShutdownStep, report: ShutdownReport): Promise<void> {
+    try {
+      const finished = await withTimeout(Promise.resolve().then(step.handler), this.options.stepTimeout);
+      if (!finished) {
+        report.timedOut.push(step.name);
+        logger.warn('Shutdown step timed out', { step: step.name });
+      }
+    } catch (error) {
+      const message = error instanceof Error ? error.message : String(error);
+      report.failed.push(`${step.name}: ${message}`);
+      logger.warn('Shutdown step failed', { step: step.name, error: message });
+    }
+  }
+
+  private signalProcess(child: ChildProcess, signal: NodeJS.Signals): void {
+    if (child.pid === undefined) {
+      return;
+    }
+
+    try {
+      // Negative pid signals the whole process group (POSIX only)
+      process.kill(process.platform === 'win32' ? child.pid : -child.pid, signal);
+    } catch {
+      // Not a group leader (not detached) or already gone
+      try {
+        child.kill(signal);
+      } catch {
+        // Already exited
+      }
+    }
+  }
+}
+
+/**
+ * Resolve to true if the promise settles in time, false on timeout
+ * Rejections are passed through
+ */
+async function withTimeout(promise: Promise<unknown>, ms: number): Promise<boolean> {
+  let timer: NodeJS.Timeout | undefined;
+  const timeout = new Promise<boolean>(resolve => {
+    timer = setTimeout(() => resolve(false), ms);
+    timer.unref();
+  });
+
+  try {
+    return await Promise.race([promise.then(() => true), timeout]);
+  } finally {
+    clearTimeout(timer);
+  }
+}
+
+// ============================================================================
+// Singleton Instance
+// ============================================================================
+
+let shutdownController: ShutdownController | null = null;
+
+/**
+ * Get or create the global shutdown controller
+ */
+export function getShutdownController(options?: ShutdownControllerOptions): ShutdownController {
+  if (!shutdownController) {
+    shutdownController = new ShutdownController(options);
+  }
+  return shutdownController;
+}
+
+/**
+ * Reset the global shutdown controller (for testing)
+ */
+export function resetShutdownController(): void {
+  shutdownController = null;
+}
+
+export default ShutdownController;
@@ -0,0 +1,197 @@
+/**
+ * Fake Client - Floyd Wrapper
+ *
+ * Scriptable in-memory stand-in for the GLM client, for deterministic tests
+ * of the engine loop and the CLI. Each request takes the next scripted
+ * response: streamed text chunks, tool calls, a stream error or a thrown
+ * error, with optional latency. Requests are kept for assertions.
+ *
+ * Pass it as the engine's client:
+ *   new FloydAgentEngine(config, callbacks, undefined, new FakeClient(['Hello']))
+ */
+
+import type { StreamEvent } from '../types.js';
+import type { GLMStreamOptions, TokenUsage } from './glm-client.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One scripted model response
+ */
+export interface FakeResponse {
+  /** Text streamed as-is, one chunk per entry */
+  chunks?: string[];
+  /** Text streamed in chunks of `chunkSize` characters (after `chunks`) */
+  text?: string;
+  /** Tool calls made after the text; ids default to fake_<request>_<n> */
+  toolCalls?: Array<{ name: string; input?: Record<string, unknown>; id?: string }>;
+  /** Stream error event sent after the content, ending the response */
+  error?: string;
+  /** Error thrown instead of streaming anything */
+  throws?: string | Error;
+  /** Latency before the first event (ms) */
+  delayMs?: number;
+  /** Latency between events (ms) */
+  chunkDelayMs?: number;
+  /** Usage of this response (default: estimated from the text) */
+  usage?: { inputTokens: number; outputTokens: number };
+}
+
+/**
+ * Options for a fake client
+ */
+export interface FakeClientOptions {
+  /** Characters per chunk when streaming `text` (default 8) */
+  chunkSize?: number;
+  /** Response used once the script runs out (default: throw) */
+  fallback?: FakeResponse | string;
+}
+
+// ============================================================================
+// Fake Client
+// ============================================================================
+
+/**
+ * In-memory model client driven by a script of responses
+ */
+export class FakeClient {
+  /** Messages of every request, in order */
+  readonly requests: GLMStreamOptions['messages'][] = [];
+  /** Tool names offered with every request */
+  readonly toolsOffered: string[][] = [];
+  private script: FakeResponse[];
+  private usage: TokenUsage = { inputTokens: 0, outputTokens: 0, totalTokens: 0 };
+
+  constructor(responses: Array<FakeResponse | string> = [], private options: FakeClientOptions = {}) {
+    this.script = responses.map(toResponse);
+  }
+
+  /**
+   * Add responses to the end of the script
+   */
+  enqueue(...responses: Array<FakeResponse | string>): this {
+    this.script.push(...responses.map(toResponse));
+    return this;
+  }
+
+  /**
+   * Scripted responses not used yet
+   */
+  remaining(): number {
+    return this.script.length;
+  }
+
+  getTokenUsage(): TokenUsage {
+    return { ...this.usage };
+  }
+
+  resetTokenUsage(): void {
+    this.usage = { inputTokens: 0, outputTokens: 0, totalTokens: 0 };
+  }
+
+  async *streamChat(options: GLMStreamOptions): AsyncGenerator<StreamEvent> {
+    this.requests.push([...options.messages]);
+    this.toolsOffered.push((options.tools ?? []).map(tool => tool.function.name));
+    const request = this.requests.length;
+
+    const next = this.script.shift() ?? (this.options.fallback !== undefined ? toResponse(this.options.fallback) : undefined);
+    if (!next) {
+      throw this.exhausted(request);
+    }
+
+    await pause(next.delayMs, options.abortSignal);
+    if (next.throws !== undefined) {
+      throw typeof next.throws === 'string' ? new Error(next.throws) : next.throws;
+    }
+
+    const chunkSize = Math.max(1, this.options.chunkSize ?? 8);
+    const chunks = [...(next.chunks ?? [])];
+    for (let i = 0; next.text && i < next.text.length; i += chunkSize) {
+      chunks.push(next.text.slice(i, i + chunkSize));
+    }
+
+    let first = true;
+    for (const chunk of chunks) {
+      if (!first) {
+        await pause(next.chunkDelayMs, options.abortSignal);
+      }
+      first = false;
+      yield { type: 'token', content: chunk };
+    }
+
+    for (const [index, call] of (next.toolCalls ?? []).entries()) {
+      await pause(first ? 0 : next.chunkDelayMs, options.abortSignal);
+      first = false;
+      yield {
+        type: 'tool_use',
+        content: '',
+        toolUse: { id: call.id ?? `fake_${request}_${index}`, name: call.name, input: call.input ?? {} },
+      };
+    }
+
+    if (next.error !== undefined) {
+      yield { type: 'error', content: '', error: next.error };
+      return;
+    }
+
+    const outputChars = chunks.join('').length;
+    const usage = next.usage ?? {
+      inputTokens: Math.ceil(options.messages.reduce((sum, m) => sum + m.content.length, 0) / 4),
+      outputTokens: Math.ceil(outputChars / 4),
+    };
+    this.usage = {
+      inputTokens: this.usage.inputTokens + usage.inputTokens,
+      outputTokens: this.usage.outputTokens + usage.outputTokens,
+      totalTokens: this.usage.totalTokens + usage.inputTokens + usage.outputTokens,
+    };
+    // Like GLMClient, completion reports the client's running total
+    options.onComplete?.(this.getTokenUsage());
+    yield { type: 'done', content: '' };
+  }
+
+  /**
+   * Error thrown when a request arrives after the script ran out
+   */
+  protected exhausted(request: number): Error {
+    return new Error(`FakeClient has no scripted response for request ${request}`);
+  }
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+function toResponse(response: FakeResponse | string): FakeResponse {
+  return typeof response === 'string' ? { text: response } : response;
+}
+
+/**
+ * Wait, rejecting with an AbortError like fetch does when the run is cancelled
+ */
+function pause(ms: number | undefined, signal?: AbortSignal): Promise<void> {
+  if (signal?.aborted) {
+    return Promise.reject(abortError());
+  }
+  if (!ms || ms <= 0) {
+    return Promise.resolve();
+  }
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(() => {
+      signal?.removeEventListener('abort', onAbort);
+      resolve();
+    }, ms);
+    const onAbort = () => {
+      clearTimeout(timer);
+      reject(abortError());
+    };
+    signal?.addEventListener('abort', onAbort, { once: true });
+  });
+}
+
+function abortError(): Error {
+  const error = new Error('The operation was aborted');
+  error.name = 'AbortError';
+  return error;
+}
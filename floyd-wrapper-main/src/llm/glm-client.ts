@@ -59,6 +59,8 @@ export interface GLMStreamOptions {
   maxRetries?: number;
   /** Initial retry delay in ms (default: 1000) */
   retryDelay?: number;
+  /** Cancels the request and any retries */
+  abortSignal?: AbortSignal;
 }
 
 // ============================================================================
@@ -162,6 +164,7 @@ export class GLMClient {
       onComplete,
       maxRetries = 2,  // Reduced from 3 to save ~4.5s
       retryDelay = 500,  // Reduced from 1000ms to fail faster
+      abortSignal,
     } = options;
 
     logger.debug('Starting GLM stream', {
@@ -207,6 +210,7 @@ export class GLMClient {
             'Authorization': `Bearer ${this.apiKey}`,
           },
           body: JSON.stringify(body),
+          signal: abortSignal,
         });
 
         // Handle HTTP errors with retry
@@ -223,7 +227,7 @@ export class GLMClient {
 
           // Retry on rate limit (429) or server errors (5xx)
           if (response.status === 429 || response.status >= 500) {
-            if (attempt < maxRetries) {
+            if (attempt < maxRetries && !abortSignal?.aborted) {
               const backoffDelay = this.calculateBackoff(attempt, retryDelay);
               logger.warn(`Retrying after ${backoffDelay}ms...`, {
                 attempt: attempt + 1,
@@ -349,8 +353,8 @@ export class GLMClient {
           attempt: attempt + 1,
         });
 
-        // Retry on network errors or rate limits
-        if (attempt < maxRetries) {
+        // Retry on network errors or rate limits, unless cancelled
+        if (attempt < maxRetries && !abortSignal?.aborted) {
           const shouldRetry =
             lastError.message.includes('fetch failed') ||
             lastError.message.includes('ECONNREFUSED') ||
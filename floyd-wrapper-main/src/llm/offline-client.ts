@@ -0,0 +1,34 @@
+/**
+ * Offline Client - Floyd Wrapper
+ *
+ * Stands in for the GLM client in offline mode so the engine can be built
+ * without an API key; any model call fails with the offline explanation.
+ */
+
+import type { StreamEvent } from '../types.js';
+import type { TokenUsage } from './glm-client.js';
+
+/**
+ * Raised when a model call is attempted in offline mode
+ */
+export class OfflineError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'OfflineError';
+  }
+}
+
+/**
+ * Model client that never reaches a model
+ */
+export class OfflineClient {
+  constructor(private reason: string) {}
+
+  getTokenUsage(): TokenUsage {
+    return { inputTokens: 0, outputTokens: 0, totalTokens: 0 };
+  }
+
+  async *streamChat(): AsyncGenerator<StreamEvent> {
+    throw new OfflineError(this.reason);
+  }
+}
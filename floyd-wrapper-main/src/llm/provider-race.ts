@@ -0,0 +1,200 @@
+/**
+ * Provider Race - Floyd Wrapper
+ *
+ * Sends the same request to two configured GLM-compatible providers and streams
+ * from whichever produces the first event, aborting the slower one's request.
+ *
+ * Racing doubles input token spend, so it only kicks in for requests whose
+ * estimated prompt size is under raceMaxInputTokens. Larger requests go to the
+ * primary provider alone.
+ */
+
+import type { FloydConfig, StreamEvent } from '../types.js';
+import { GLMClient, type GLMStreamOptions, type TokenUsage } from './glm-client.js';
+import { logger } from '../utils/logger.js';
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Default prompt size limit (estimated tokens) for racing
+ */
+export const DEFAULT_RACE_MAX_INPUT_TOKENS = 8000;
+
+/**
+ * Rough chars-per-token ratio used for prompt size estimates
+ */
+const CHARS_PER_TOKEN = 4;
+
+// ============================================================================
+// Provider Race Class
+// ============================================================================
+
+/**
+ * A contestant stream in a race
+ */
+interface Contestant {
+  label: string;
+  stream: AsyncGenerator<StreamEvent>;
+  next: Promise<{ index: number; result: IteratorResult<StreamEvent> | null; error?: unknown }>;
+}
+
+/**
+ * Streams from the fastest of two providers
+ */
+export class ProviderRace {
+  private primary: GLMClient;
+  private secondary: GLMClient | null;
+  private maxInputTokens: number;
+
+  constructor(config: FloydConfig) {
+    this.primary = new GLMClient(config);
+    this.secondary = config.raceApiEndpoint
+      ? new GLMClient({
+          ...config,
+          glmApiKey: config.raceApiKey || config.glmApiKey,
+          glmApiEndpoint: config.raceApiEndpoint,
+          glmModel: config.raceModel || config.glmModel,
+        })
+      : null;
+    this.maxInputTokens = config.raceMaxInputTokens ?? DEFAULT_RACE_MAX_INPUT_TOKENS;
+
+    logger.debug('ProviderRace initialized', {
+      racing: this.secondary !== null,
+      secondaryEndpoint: config.raceApiEndpoint,
+      maxInputTokens: this.maxInputTokens,
+    });
+  }
+
+  /**
+   * Whether a secondary provider is configured
+   */
+  isRacingEnabled(): boolean {
+    return this.secondary !== null;
+  }
+
+  /**
+   * Combined token usage across both providers
+   */
+  getTokenUsage(): TokenUsage {
+    const primary = this.primary.getTokenUsage();
+    const secondary = this.secondary?.getTokenUsage();
+    return {
+      inputTokens: primary.inputTokens + (secondary?.inputTokens ?? 0),
+      outputTokens: primary.outputTokens + (secondary?.outputTokens ?? 0),
+      totalTokens: primary.totalTokens + (secondary?.totalTokens ?? 0),
+    };
+  }
+
+  /**
+   * Stream a chat completion, racing providers when within the cost bound
+   */
+  async *streamChat(options: GLMStreamOptions): AsyncGenerator<StreamEvent> {
+    const estimatedTokens = Math.ceil(
+      options.messages.reduce((sum, m) => sum + m.content.length, 0) / CHARS_PER_TOKEN
+    );
+
+    if (!this.secondary || estimatedTokens > this.maxInputTokens) {
+      yield* this.primary.streamChat(options);
+      return;
+    }
+
+    // Each contestant gets its own signal so the loser's request can be cancelled;
+    // the caller's signal cancels both
+    const controllers = [new AbortController(), new AbortController()];
+    const abortAll = () => controllers.forEach(controller => controller.abort());
+    if (options.abortSignal?.aborted) {
+      abortAll();
+    }
+    options.abortSignal?.addEventListener('abort', abortAll, { once: true });
+
+    // Only the winner reports completion; the aborted loser reports no errors
+    let winner = -1;
+    const optionsFor = (index: number): GLMStreamOptions => ({
+      ...options,
+      abortSignal: controllers[index].signal,
+      onError: (error) => {
+        if (winner === -1 || index === winner) {
+          options.onError?.(error);
+        }
+      },
+      onComplete: (usage) => {
+        if (index === winner) {
+          options.onComplete?.(usage);
+        }
+      },
+    });
+
+    const contestants: Contestant[] = [
+      { label: 'primary', stream: this.primary.streamChat(optionsFor(0)) },
+      { label: 'secondary', stream: this.secondary.streamChat(optionsFor(1)) },
+    ].map((c, index) => ({ ...c, next: this.pull(c.stream, index) }));
+
+    const startedAt = Date.now();
+    const active = new Set([0, 1]);
+    let first: StreamEvent | null = null;
+
+    try {
+      // Wait for the first useful event; a contestant that errors drops out
+      while (active.size > 0 && first === null) {
+        const { index, result, error } = await Promise.race(
+          [...active].map(i => contestants[i].next)
+        );
+
+        if (error || !result || result.done || result.value.type === 'error') {
+          logger.warn(`Provider race: ${contestants[index].label} dropped out`, {
+            error: error instanceof Error ? error.message : result?.value?.error,
+          });
+          active.delete(index);
+          if (active.size === 0) {
+            // Both failed - surface the primary's behavior
+            if (result && !result.done) {
+              yield result.value;
+            } else if (error) {
+              throw error;
+            }
+          }
+          continue;
+        }
+
+        winner = index;
+        first = result.value;
+      }
+
+      if (first === null) {
+        return;
+      }
+
+      // Cancel the loser's request and stop consuming its stream
+      for (const index of active) {
+        if (index !== winner) {
+          controllers[index].abort();
+          contestants[index].stream.return(undefined).catch(() => {});
+        }
+      }
+
+      logger.info(`Provider race won by ${contestants[winner].label}`, {
+        firstEventMs: Date.now() - startedAt,
+      });
+
+      yield first;
+      yield* contestants[winner].stream;
+    } finally {
+      options.abortSignal?.removeEventListener('abort', abortAll);
+    }
+  }
+
+  /**
+   * Pull the next event from a stream, tagging it with the contestant index
+   */
+  private pull(
+    stream: AsyncGenerator<StreamEvent>,
+    index: number
+  ): Promise<{ index: number; result: IteratorResult<StreamEvent> | null; error?: unknown }> {
+    return stream.next().then(
+      result => ({ index, result }),
+      error => ({ index, result: null, error })
+    );
+  }
+}
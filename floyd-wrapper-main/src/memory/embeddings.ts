@@ -0,0 +1,99 @@
+/**
+ * Embeddings Client - Floyd Wrapper
+ *
+ * Turns text into vectors for long-term memory. Any OpenAI-compatible
+ * /embeddings endpoint works; by default the GLM endpoint and key are used
+ * with its embedding-3 model.
+ */
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Anything that can embed text (the HTTP client, or a fake in tests)
+ */
+export interface Embedder {
+  /** Model name, stored with each vector so a model change is noticed */
+  readonly model: string;
+  embed(texts: string[]): Promise<number[][]>;
+}
+
+/**
+ * Embeddings endpoint settings
+ */
+export interface EmbeddingsConfig {
+  endpoint: string;
+  apiKey: string;
+  model: string;
+  /** Request timeout in ms */
+  timeoutMs: number;
+}
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+/**
+ * Settings from FLOYD_EMBEDDINGS_* variables, falling back to the GLM ones
+ */
+export function getEmbeddingsConfig(): EmbeddingsConfig {
+  return {
+    endpoint: process.env.FLOYD_EMBEDDINGS_ENDPOINT
+      || process.env.FLOYD_GLM_ENDPOINT
+      || 'https://api.z.ai/api/coding/paas/v4',
+    apiKey: process.env.FLOYD_EMBEDDINGS_API_KEY || process.env.FLOYD_GLM_API_KEY || '',
+    model: process.env.FLOYD_EMBEDDINGS_MODEL || 'embedding-3',
+    timeoutMs: parseInt(process.env.FLOYD_EMBEDDINGS_TIMEOUT_MS || '', 10) || 15000,
+  };
+}
+
+// ============================================================================
+// HTTP Client
+// ============================================================================
+
+/**
+ * Client for an OpenAI-compatible /embeddings endpoint
+ */
+export class EmbeddingsClient implements Embedder {
+  readonly model: string;
+  private readonly config: EmbeddingsConfig;
+
+  constructor(config: EmbeddingsConfig = getEmbeddingsConfig()) {
+    this.config = config;
+    this.model = config.model;
+  }
+
+  async embed(texts: string[]): Promise<number[][]> {
+    if (texts.length === 0) {
+      return [];
+    }
+    if (!this.config.apiKey) {
+      throw new Error('No embeddings API key (set FLOYD_EMBEDDINGS_API_KEY or FLOYD_GLM_API_KEY)');
+    }
+
+    const response = await fetch(`${this.config.endpoint.replace(/\/+$/, '')}/embeddings`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'Authorization': `Bearer ${this.config.apiKey}`,
+      },
+      body: JSON.stringify({ model: this.config.model, input: texts }),
+      signal: AbortSignal.timeout(this.config.timeoutMs),
+    });
+
+    if (!response.ok) {
+      const detail = await response.text().catch(() => '');
+      throw new Error(`Embeddings request failed: ${response.status} ${response.statusText}${detail ? ` - ${detail.slice(0, 200)}` : ''}`);
+    }
+
+    const body = await response.json() as { data?: Array<{ index?: number; embedding: number[] }> };
+    if (!Array.isArray(body.data) || body.data.length !== texts.length) {
+      throw new Error('Embeddings response does not match the request');
+    }
+
+    return [...body.data]
+      .sort((a, b) => (a.index ?? 0) - (b.index ?? 0))
+      .map(item => item.embedding);
+  }
+}
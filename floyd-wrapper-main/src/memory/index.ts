@@ -0,0 +1,47 @@
+/**
+ * Long-Term Memory - Floyd Wrapper
+ *
+ * Embedded project memories under .floyd/memory/ (FLOYD_MEMORY=off disables).
+ */
+
+import path from 'node:path';
+import { MemoryStore } from './memory-store.js';
+import { EmbeddingsClient, type Embedder } from './embeddings.js';
+
+export * from './memory-store.js';
+export * from './embeddings.js';
+
+/**
+ * Whether long-term memory is on
+ */
+export function isMemoryEnabled(): boolean {
+  const value = process.env.FLOYD_MEMORY?.toLowerCase();
+  return value !== 'off' && value !== 'false' && value !== '0';
+}
+
+/**
+ * Memories recalled per request (FLOYD_MEMORY_TOP_K)
+ */
+export function getMemoryTopK(): number {
+  const value = parseInt(process.env.FLOYD_MEMORY_TOP_K || '', 10);
+  return Number.isNaN(value) ? 5 : Math.max(0, value);
+}
+
+let memoryStore: MemoryStore | null = null;
+
+/**
+ * Get or create the memory store for the current project
+ */
+export function getMemoryStore(cwd: string = process.cwd(), embedder?: Embedder): MemoryStore {
+  if (!memoryStore) {
+    memoryStore = new MemoryStore(path.join(cwd, '.floyd', 'memory'), embedder ?? new EmbeddingsClient());
+  }
+  return memoryStore;
+}
+
+/**
+ * Reset the memory store (for testing)
+ */
+export function resetMemoryStore(): void {
+  memoryStore = null;
+}
@@ -0,0 +1,237 @@
+/**
+ * Memory Store - Floyd Wrapper
+ *
+ * Long-term memory for a project: facts, decisions and file summaries worth
+ * keeping across sessions. Each memory is embedded when it is saved and kept
+ * in .floyd/memory/index.json; before each request the most similar ones
+ * are added to the system prompt.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import crypto from 'node:crypto';
+import type { Embedder } from './embeddings.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * What a memory records
+ */
+export type MemoryKind = 'fact' | 'decision' | 'file_summary';
+
+export const MEMORY_KINDS: MemoryKind[] = ['fact', 'decision', 'file_summary'];
+
+/**
+ * A stored memory
+ */
+export interface MemoryEntry {
+  id: string;
+  kind: MemoryKind;
+  text: string;
+  /** File or other origin, e.g. for a file summary */
+  source?: string;
+  createdAt: number;
+  updatedAt: number;
+  /** Embedding model the vector came from */
+  model: string;
+  vector: number[];
+}
+
+/**
+ * A memory found for a query
+ */
+export interface MemoryMatch {
+  entry: MemoryEntry;
+  score: number;
+}
+
+/**
+ * On-disk index format
+ */
+interface MemoryIndex {
+  version: 1;
+  entries: MemoryEntry[];
+}
+
+/**
+ * Similarity above which a new memory replaces an existing one
+ */
+const DUPLICATE_SCORE = 0.95;
+
+/**
+ * Similarity below which a memory is not worth recalling
+ */
+export const DEFAULT_MIN_SCORE = 0.35;
+
+/**
+ * Heading of the system prompt section with recalled memories
+ */
+export const MEMORY_SECTION_HEADING = '## Relevant Memories';
+
+// ============================================================================
+// Memory Store Class
+// ============================================================================
+
+/**
+ * Embedded memories for one project
+ */
+export class MemoryStore {
+  private readonly indexPath: string;
+  private readonly embedder: Embedder;
+  private entries: MemoryEntry[] | null = null;
+
+  constructor(dir: string, embedder: Embedder) {
+    this.indexPath = path.join(dir, 'index.json');
+    this.embedder = embedder;
+  }
+
+  /**
+   * Embed and save a memory; a near-duplicate of an existing one replaces it
+   */
+  async add(kind: MemoryKind, text: string, source?: string): Promise<MemoryEntry> {
+    const trimmed = text.trim();
+    if (!trimmed) {
+      throw new Error('Memory text cannot be empty');
+    }
+
+    const [vector] = await this.embedder.embed([trimmed]);
+    const entries = await this.load();
+    const now = Date.now();
+
+    const duplicate = this.rank(vector, entries).find(match =>
+      match.score >= DUPLICATE_SCORE && match.entry.kind === kind);
+    if (duplicate) {
+      Object.assign(duplicate.entry, { text: trimmed, source, vector, updatedAt: now });
+      await this.save();
+      return duplicate.entry;
+    }
+
+    const entry: MemoryEntry = {
+      id: crypto.randomUUID().slice(0, 8),
+      kind,
+      text: trimmed,
+      ...(source && { source }),
+      createdAt: now,
+      updatedAt: now,
+      model: this.embedder.model,
+      vector,
+    };
+    entries.push(entry);
+    await this.save();
+    return entry;
+  }
+
+  /**
+   * The memories most similar to a query, best first
+   */
+  async search(query: string, topK: number = 5, minScore: number = DEFAULT_MIN_SCORE): Promise<MemoryMatch[]> {
+    const entries = await this.load();
+    if (entries.length === 0 || !query.trim() || topK <= 0) {
+      return [];
+    }
+
+    const [vector] = await this.embedder.embed([query]);
+    return this.rank(vector, entries)
+      .filter(match => match.score >= minScore)
+      .slice(0, topK);
+  }
+
+  /**
+   * All memories, newest first
+   */
+  async list(): Promise<MemoryEntry[]> {
+    return [...await this.load()].sort((a, b) => b.updatedAt - a.updatedAt);
+  }
+
+  /**
+   * Delete a memory by id
+   *
+   * @returns Whether it existed
+   */
+  async remove(id: string): Promise<boolean> {
+    const entries = await this.load();
+    const index = entries.findIndex(entry => entry.id === id);
+    if (index === -1) {
+      return false;
+    }
+    entries.splice(index, 1);
+    await this.save();
+    return true;
+  }
+
+  /**
+   * Entries scored against a vector; vectors from another model are skipped
+   */
+  private rank(vector: number[], entries: MemoryEntry[]): MemoryMatch[] {
+    return entries
+      .filter(entry => entry.model === this.embedder.model && entry.vector.length === vector.length)
+      .map(entry => ({ entry, score: cosineSimilarity(vector, entry.vector) }))
+      .sort((a, b) => b.score - a.score);
+  }
+
+  private async load(): Promise<MemoryEntry[]> {
+    if (!this.entries) {
+      try {
+        const index = await fs.readJson(this.indexPath) as MemoryIndex;
+        this.entries = Array.isArray(index.entries) ? index.entries : [];
+      } catch {
+        this.entries = [];
+      }
+    }
+    return this.entries;
+  }
+
+  private async save(): Promise<void> {
+    const index: MemoryIndex = { version: 1, entries: this.entries ?? [] };
+    await fs.ensureDir(path.dirname(this.indexPath));
+    const tmpPath = `${this.indexPath}.tmp`;
+    await fs.writeFile(tmpPath, JSON.stringify(index));
+    await fs.rename(tmpPath, this.indexPath);
+  }
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Cosine similarity of two vectors (0 when either is all zeros)
+ */
+export function cosineSimilarity(a: number[], b: number[]): number {
+  let dot = 0;
+  let normA = 0;
+  let normB = 0;
+  for (let i = 0; i < Math.min(a.length, b.length); i++) {
+    dot += a[i] * b[i];
+    normA += a[i] * a[i];
+    normB += b[i] * b[i];
+  }
+  return normA === 0 || normB === 0 ? 0 : dot / Math.sqrt(normA * normB);
+}
+
+/**
+ * A system prompt without its recalled memories section
+ */
+export function stripMemorySection(prompt: string): string {
+  const index = prompt.indexOf(`\n\n${MEMORY_SECTION_HEADING}\n`);
+  return index === -1 ? prompt : prompt.slice(0, index);
+}
+
+/**
+ * System prompt section listing recalled memories
+ */
+export function formatMemorySection(matches: MemoryMatch[]): string {
+  const lines = matches.map(({ entry }) => {
+    const label = entry.kind === 'file_summary' ? `file summary${entry.source ? ` (${entry.source})` : ''}` : entry.kind;
+    return `- [${label}] ${entry.text}`;
+  });
+  return [
+    MEMORY_SECTION_HEADING,
+    '',
+    'Saved in earlier sessions and possibly relevant to this request. Verify against the code before relying on them.',
+    '',
+    ...lines,
+  ].join('\n');
+}
@@ -0,0 +1,241 @@
+/**
+ * Diff Preview - Floyd Wrapper
+ *
+ * Computes a unified diff for write/edit tool calls before they touch the
+ * disk and asks the registered handler (the CLI) to accept, reject or edit
+ * the change. Runs inside ToolRegistry.execute after permission checks.
+ *
+ * Disabled with FLOYD_DIFF_PREVIEW=false for autonomous runs. Without a
+ * handler (headless/embedded use) changes are applied as before.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { createTwoFilesPatch } from 'diff';
+import { getEventBroadcaster } from '../streaming/event-broadcaster.js';
+import { applyStructuredEdits, type EditOperation } from '../tools/file/structured-edit.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A proposed file change awaiting review
+ */
+export interface DiffPreview {
+  /** Tool that proposed the change */
+  toolName: string;
+  /** Absolute file path */
+  filePath: string;
+  /** Whether the file is being created */
+  isNewFile: boolean;
+  /** Content after the change */
+  proposedContent: string;
+  /** Unified diff of the change */
+  diff: string;
+}
+
+/**
+ * Reviewer decision
+ */
+export type DiffPreviewDecision =
+  | { action: 'accept' }
+  | { action: 'reject'; reason?: string }
+  | { action: 'edit'; content: string };
+
+/**
+ * Presents a preview to the user and resolves with their decision
+ */
+export type DiffPreviewHandler = (preview: DiffPreview) => Promise<DiffPreviewDecision>;
+
+/**
+ * Outcome of a review
+ */
+export interface DiffReviewResult {
+  approved: boolean;
+  /** Replacement file content when the user edited the change */
+  editedContent?: string;
+  /** Reviewed file, for rewriting the call when edited */
+  filePath?: string;
+  reason?: string;
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Tools whose changes are previewed
+ */
+const PREVIEW_TOOLS = new Set(['write', 'write_file', 'edit_file', 'search_replace', 'json_edit']);
+
+/**
+ * Work out the file content a write/edit call would produce
+ *
+ * @returns null when the tool is not previewable or the edit would fail anyway
+ */
+export async function computeProposedChange(
+  toolName: string,
+  input: Record<string, unknown>
+): Promise<{ filePath: string; before: string | null; after: string } | null> {
+  if (!PREVIEW_TOOLS.has(toolName) || typeof input.file_path !== 'string') {
+    return null;
+  }
+
+  const filePath = path.resolve(input.file_path);
+  const before = await fs.readFile(filePath, 'utf-8').catch(() => null);
+
+  if (toolName === 'write' || toolName === 'write_file') {
+    // write refuses existing files without overwrite, so there is nothing to preview
+    if (before !== null && toolName === 'write' && input.overwrite !== true) {
+      return null;
+    }
+    return typeof input.content === 'string' ? { filePath, before, after: input.content } : null;
+  }
+
+  if (before === null) {
+    return null;
+  }
+
+  if (toolName === 'json_edit') {
+    try {
+      const format = input.format === 'json' || input.format === 'yaml' ? input.format : undefined;
+      return { filePath, before, after: applyStructuredEdits(filePath, before, input.operations as EditOperation[], format).text };
+    } catch {
+      return null;
+    }
+  }
+
+  if (toolName === 'edit_file') {
+    const oldString = String(input.old_string ?? '');
+    if (!oldString || before.split(oldString).length !== 2) {
+      return null;
+    }
+    return { filePath, before, after: before.replace(oldString, () => String(input.new_string ?? '')) };
+  }
+
+  const search = String(input.search_string ?? '');
+  const replacement = String(input.replace_string ?? '');
+  if (!search || !before.includes(search)) {
+    return null;
+  }
+  const after = input.replace_all
+    ? before.split(search).join(replacement)
+    : before.replace(search, () => replacement);
+  return { filePath, before, after };
+}
+
+/**
+ * Build a unified diff between two versions of a file
+ */
+export function createPreviewDiff(filePath: string, before: string | null, after: string): string {
+  const relative = path.relative(process.cwd(), filePath) || filePath;
+  return createTwoFilesPatch(
+    before === null ? '/dev/null' : `a/${relative}`,
+    `b/${relative}`,
+    before ?? '',
+    after,
+    undefined,
+    undefined,
+    { context: 3 }
+  );
+}
+
+// ============================================================================
+// Diff Previewer Class
+// ============================================================================
+
+/**
+ * Gatekeeper that routes file changes through a review handler
+ */
+export class DiffPreviewer {
+  private enabled = process.env.FLOYD_DIFF_PREVIEW !== 'false';
+  private handler: DiffPreviewHandler | null = null;
+
+  /**
+   * Enable or disable previews
+   */
+  setEnabled(enabled: boolean): void {
+    this.enabled = enabled;
+  }
+
+  /**
+   * Whether previews are active (enabled and a handler is registered)
+   */
+  isActive(): boolean {
+    return this.enabled && this.handler !== null;
+  }
+
+  /**
+   * Register (or clear) the handler that reviews previews
+   */
+  setHandler(handler: DiffPreviewHandler | null): void {
+    this.handler = handler;
+  }
+
+  /**
+   * Review a tool call's file change
+   *
+   * @param format - Formats the proposed content the way it will be written
+   * (auto-format), so the user approves what ends up on disk
+   */
+  async review(
+    toolName: string,
+    input: unknown,
+    format?: (filePath: string, content: string) => Promise<string>
+  ): Promise<DiffReviewResult> {
+    if (!this.isActive() || !input || typeof input !== 'object') {
+      return { approved: true };
+    }
+
+    const change = await computeProposedChange(toolName, input as Record<string, unknown>);
+    if (change && format) {
+      change.after = await format(change.filePath, change.after);
+    }
+    if (!change || change.before === change.after) {
+      return { approved: true };
+    }
+
+    const preview: DiffPreview = {
+      toolName,
+      filePath: change.filePath,
+      isNewFile: change.before === null,
+      proposedContent: change.after,
+      diff: createPreviewDiff(change.filePath, change.before, change.after),
+    };
+
+    getEventBroadcaster().emit('diff_preview', {
+      tool: toolName,
+      filePath: preview.filePath,
+      isNewFile: preview.isNewFile,
+      diff: preview.diff,
+    });
+
+    const decision = await this.handler!(preview);
+
+    switch (decision.action) {
+      case 'accept':
+        return { approved: true };
+      case 'edit':
+        return { approved: true, editedContent: decision.content, filePath: change.filePath };
+      case 'reject':
+        return { approved: false, reason: decision.reason };
+    }
+  }
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultDiffPreviewer: DiffPreviewer | null = null;
+
+/**
+ * Get the shared diff previewer
+ */
+export function getDiffPreviewer(): DiffPreviewer {
+  if (!defaultDiffPreviewer) {
+    defaultDiffPreviewer = new DiffPreviewer();
+  }
+  return defaultDiffPreviewer;
+}
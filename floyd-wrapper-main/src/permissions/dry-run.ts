@@ -0,0 +1,149 @@
+/**
+ * Dry Run - Floyd Wrapper
+ *
+ * Simulation mode for auditing what the agent would do on a sensitive repo.
+ * ToolRegistry.execute hands every tool call that could change something
+ * (anything not in READ_ONLY_TOOLS) to simulateToolCall instead of running
+ * it, and the model gets back a result marked "simulated" describing the
+ * command, target files and diff. Read-only tools still run so the plan is
+ * grounded in the actual code. Permission 'none' is not enough: todo,
+ * remember and the cache tools write files without asking.
+ *
+ * Turned on with `floyd --dry-run` or toggled with /dryrun. The system prompt
+ * carries a section telling the model it is in simulation mode.
+ */
+
+import { computeProposedChange, createPreviewDiff } from './diff-preview.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * What a simulated tool call would have done
+ */
+export interface SimulatedCall {
+  simulated: true;
+  tool: string;
+  /** One-line description, e.g. "Would run: npm test" */
+  summary: string;
+  /** Shell command for command tools */
+  command?: string;
+  /** Files the call would touch */
+  targets?: string[];
+  /** Unified diff for write/edit tools */
+  diff?: string;
+  input: Record<string, unknown>;
+  note: string;
+}
+
+// ============================================================================
+// State
+// ============================================================================
+
+let dryRun = false;
+
+/**
+ * Whether tool calls are simulated
+ */
+export function isDryRun(): boolean {
+  return dryRun;
+}
+
+/**
+ * Turn simulation mode on or off
+ */
+export function setDryRun(enabled: boolean): void {
+  dryRun = enabled;
+}
+
+// ============================================================================
+// Simulation
+// ============================================================================
+
+/**
+ * Tools that run for real in a dry run: they read files, git state or the
+ * environment and write nothing (ask_user, and env's request action, only
+ * ask the user)
+ */
+export const READ_ONLY_TOOLS: ReadonlySet<string> = new Set([
+  'read_file', 'list_directory', 'grep', 'codebase_search', 'symbols',
+  'git_status', 'git_diff', 'git_log', 'is_protected_branch',
+  'ps_ports', 'env', 'ask_user', 'browser_status',
+  'impact_simulate', 'assess_patch_risk',
+]);
+
+/**
+ * Whether a dry run lets a tool run instead of simulating it
+ */
+export function isReadOnlyTool(toolName: string): boolean {
+  return READ_ONLY_TOOLS.has(toolName);
+}
+
+/**
+ * Input fields holding file paths
+ */
+const TARGET_FIELDS = ['file_path', 'filePath', 'path', 'source', 'destination'];
+
+const SIMULATED_NOTE =
+  'DRY RUN: nothing was executed. Treat this call as if it succeeded and continue the plan; ' +
+  'later reads will still show the files unchanged.';
+
+/**
+ * Describe a tool call without executing it
+ */
+export async function simulateToolCall(toolName: string, input: unknown): Promise<SimulatedCall> {
+  const args = input && typeof input === 'object' ? input as Record<string, unknown> : {};
+  const result: SimulatedCall = { simulated: true, tool: toolName, summary: '', input: args, note: SIMULATED_NOTE };
+
+  if (typeof args.command === 'string') {
+    const argv = Array.isArray(args.args) ? args.args.map(String) : [];
+    result.command = [args.command, ...argv].join(' ');
+    result.summary = `Would run: ${result.command}${typeof args.cwd === 'string' ? ` (in ${args.cwd})` : ''}`;
+    return result;
+  }
+
+  const targets = TARGET_FIELDS.filter(field => typeof args[field] === 'string').map(field => String(args[field]));
+  if (targets.length > 0) {
+    result.targets = targets;
+  }
+
+  const change = await computeProposedChange(toolName, args);
+  if (change) {
+    result.diff = createPreviewDiff(change.filePath, change.before, change.after);
+    result.summary = `Would ${change.before === null ? 'create' : 'modify'} ${targets[0] ?? change.filePath}`;
+    return result;
+  }
+
+  result.summary = targets.length > 0
+    ? `Would call ${toolName} on ${targets.join(', ')}`
+    : `Would call ${toolName}`;
+  return result;
+}
+
+// ============================================================================
+// System Prompt
+// ============================================================================
+
+export const DRY_RUN_SECTION_HEADING = '## Simulation Mode (Dry Run)';
+
+/**
+ * System prompt section telling the model its tool calls are simulated
+ */
+export function formatDryRunSection(): string {
+  return [
+    DRY_RUN_SECTION_HEADING,
+    'You are in DRY RUN mode. Read-only tools run normally, but every tool call that would change',
+    'something (writes, edits, commands, git operations) is NOT executed: its result is marked',
+    '"simulated" and describes what would have happened. Plan and call tools exactly as you would',
+    'for real so the user can audit the plan; do not retry simulated calls or treat them as failures.',
+  ].join('\n');
+}
+
+/**
+ * A system prompt without its dry run section (and anything after it)
+ */
+export function stripDryRunSection(prompt: string): string {
+  const index = prompt.indexOf(`\n\n${DRY_RUN_SECTION_HEADING}\n`);
+  return index === -1 ? prompt : prompt.slice(0, index);
+}
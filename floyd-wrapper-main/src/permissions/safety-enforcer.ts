@@ -0,0 +1,305 @@
+/**
+ * Safety Enforcer - Floyd Wrapper
+ *
+ * Rule-based safety checks applied to tool inputs before execution.
+ *
+ * Permission prompts only cover what the user approves interactively; tool calls
+ * generated by the model (and anything running with permissionGranted) used to
+ * bypass safety entirely. The enforcer runs inside ToolRegistry.execute so every
+ * shell command, file write/edit and web fetch goes through the same rules.
+ */
+
+import path from 'node:path';
+import os from 'node:os';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A blocked action and the rule that blocked it
+ */
+export interface SafetyViolation {
+  /** Rule identifier */
+  rule: string;
+  /** Human-readable reason */
+  reason: string;
+  /** Offending command or path */
+  target: string;
+}
+
+/**
+ * Shell command rule
+ */
+interface CommandRule {
+  rule: string;
+  pattern: RegExp;
+  reason: string;
+}
+
+// ============================================================================
+// Rules
+// ============================================================================
+
+/**
+ * Tools whose input is a shell command (shell_session also takes shell input
+ * to answer prompts, which the shell runs just the same)
+ */
+const COMMAND_TOOLS = new Set(['run', 'bash', 'shell', 'shell_session']);
+
+/**
+ * Tools that write or edit files
+ */
+const WRITE_TOOLS = new Set([
+  'write', 'write_file', 'edit_file', 'search_replace', 'json_edit',
+  'apply_unified_diff', 'edit_range', 'insert_at', 'delete_range',
+  'delete_file', 'move_file', 'format',
+]);
+
+/**
+ * Tools that download from a URL (browser pages included)
+ */
+const FETCH_TOOLS = new Set(['fetch', 'http_request', 'browser_navigate', 'browser_create_tab']);
+
+/**
+ * Destructive shell command patterns
+ */
+const COMMAND_RULES: CommandRule[] = [
+  {
+    rule: 'rm-root',
+    pattern: /\brm\s+(-[a-zA-Z]*[rf][a-zA-Z]*\s+)+(\/|~|\$HOME|\/\*)(\s|$)/,
+    reason: 'Recursive delete of root or home directory',
+  },
+  {
+    rule: 'disk-format',
+    pattern: /\b(mkfs(\.\w+)?|fdisk|diskutil\s+erase\w*)\b/,
+    reason: 'Disk formatting command',
+  },
+  {
+    rule: 'raw-device-write',
+    pattern: /\bdd\b.*\bof=\/dev\/(sd|disk|nvme|hd)/,
+    reason: 'Raw write to block device',
+  },
+  {
+    rule: 'fork-bomb',
+    pattern: /:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:/,
+    reason: 'Fork bomb',
+  },
+  {
+    rule: 'chmod-root',
+    pattern: /\bchmod\s+(-R\s+)?777\s+\/(\s|$)/,
+    reason: 'World-writable permissions on root',
+  },
+  {
+    rule: 'pipe-to-shell',
+    pattern: /\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z)?sh\b/,
+    reason: 'Piping a remote script into a shell',
+  },
+  {
+    rule: 'force-push-protected',
+    pattern: /\bgit\s+push\b.*(--force|-f)\b.*\b(main|master)\b/,
+    reason: 'Force push to a protected branch',
+  },
+];
+
+/**
+ * Paths that must never be written by the agent
+ */
+function protectedPaths(): string[] {
+  const home = os.homedir();
+  return [
+    '/etc',
+    '/usr',
+    '/bin',
+    '/sbin',
+    '/System',
+    path.join(home, '.ssh'),
+    path.join(home, '.aws'),
+    path.join(home, '.gnupg'),
+  ];
+}
+
+/**
+ * Domains the fetch, http_request and browser tools may reach
+ * (FLOYD_FETCH_ALLOWED_DOMAINS, comma-separated; empty allows any domain)
+ */
+function loadAllowedDomains(): string[] {
+  return (process.env.FLOYD_FETCH_ALLOWED_DOMAINS || '')
+    .split(',')
+    .map(domain => domain.trim().toLowerCase().replace(/^\*\./, ''))
+    .filter(Boolean);
+}
+
+// ============================================================================
+// Safety Enforcer Class
+// ============================================================================
+
+/**
+ * Checks tool inputs against the safety rules
+ */
+export class SafetyEnforcer {
+  /**
+   * Whether enforcement is active
+   */
+  private enabled = true;
+
+  /**
+   * Domains fetches are limited to, subdomains included; empty allows any
+   */
+  private allowedDomains: string[] = loadAllowedDomains();
+
+  /**
+   * Enable or disable enforcement
+   */
+  setEnabled(enabled: boolean): void {
+    this.enabled = enabled;
+  }
+
+  /**
+   * Whether enforcement is active
+   */
+  isEnabled(): boolean {
+    return this.enabled;
+  }
+
+  /**
+   * Limit fetches to these domains and their subdomains (empty allows any)
+   */
+  setAllowedDomains(domains: string[]): void {
+    this.allowedDomains = domains.map(domain => domain.trim().toLowerCase().replace(/^\*\./, '')).filter(Boolean);
+  }
+
+  /**
+   * Domains fetches are limited to
+   */
+  getAllowedDomains(): string[] {
+    return [...this.allowedDomains];
+  }
+
+  /**
+   * Check a tool call against the safety rules
+   *
+   * @param toolName - Tool being executed
+   * @param input - Validated tool input
+   * @returns The violation, or null when the action is allowed
+   */
+  checkAction(toolName: string, input: unknown): SafetyViolation | null {
+    if (!this.enabled || !input || typeof input !== 'object') {
+      return null;
+    }
+
+    const fields = input as Record<string, unknown>;
+
+    if (COMMAND_TOOLS.has(toolName)) {
+      const command = [fields.command, ...(Array.isArray(fields.args) ? fields.args : [])]
+        .filter((part): part is string => typeof part === 'string')
+        .join(' ');
+      const violation = this.checkCommand(command);
+      if (violation || typeof fields.input !== 'string') {
+        return violation;
+      }
+      return this.checkCommand(fields.input);
+    }
+
+    if (WRITE_TOOLS.has(toolName)) {
+      const paths = ['file_path', 'filePath', 'path', 'source', 'destination'].map(field => fields[field]);
+      if (Array.isArray(fields.files)) {
+        paths.push(...fields.files);
+      }
+      for (const value of paths) {
+        if (typeof value === 'string') {
+          const violation = this.checkWritePath(value);
+          if (violation) {
+            return violation;
+          }
+        }
+      }
+    }
+
+    if (FETCH_TOOLS.has(toolName) && typeof fields.url === 'string') {
+      return this.checkUrl(fields.url);
+    }
+
+    return null;
+  }
+
+  /**
+   * Check that a URL is on the domain allowlist
+   */
+  checkUrl(url: string): SafetyViolation | null {
+    if (this.allowedDomains.length === 0) {
+      return null;
+    }
+
+    let host: string;
+    try {
+      host = new URL(url).hostname.toLowerCase();
+    } catch {
+      return { rule: 'invalid-url', reason: 'URL could not be parsed', target: url };
+    }
+
+    const allowed = this.allowedDomains.some(domain => host === domain || host.endsWith(`.${domain}`));
+    return allowed ? null : {
+      rule: 'domain-not-allowed',
+      reason: `${host} is not in FLOYD_FETCH_ALLOWED_DOMAINS`,
+      target: url,
+    };
+  }
+
+  /**
+   * Check a shell command against the command rules
+   */
+  checkCommand(command: string): SafetyViolation | null {
+    for (const { rule, pattern, reason } of COMMAND_RULES) {
+      if (pattern.test(command)) {
+        return { rule, reason, target: command };
+      }
+    }
+    return null;
+  }
+
+  /**
+   * Check that a write target is outside protected locations
+   */
+  checkWritePath(filePath: string): SafetyViolation | null {
+    const resolved = path.resolve(filePath);
+
+    for (const protectedPath of protectedPaths()) {
+      if (resolved === protectedPath || resolved.startsWith(protectedPath + path.sep)) {
+        return {
+          rule: 'protected-path',
+          reason: `Write to protected location ${protectedPath}`,
+          target: filePath,
+        };
+      }
+    }
+
+    if (resolved.split(path.sep).includes('.git')) {
+      return {
+        rule: 'git-internals',
+        reason: 'Direct write inside .git directory',
+        target: filePath,
+      };
+    }
+
+    return null;
+  }
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultSafetyEnforcer: SafetyEnforcer | null = null;
+
+/**
+ * Get the shared safety enforcer
+ */
+export function getSafetyEnforcer(): SafetyEnforcer {
+  if (!defaultSafetyEnforcer) {
+    defaultSafetyEnforcer = new SafetyEnforcer();
+  }
+  return defaultSafetyEnforcer;
+}
+
+export default SafetyEnforcer;
@@ -0,0 +1,295 @@
+/**
+ * Branch Notes - Floyd Wrapper
+ *
+ * Keeps .floyd/branch.md current instead of leaving it as a static template:
+ * the working branch is recorded when FLOYD starts or the agent switches
+ * branches, and PR-checklist items are ticked as verifications pass (tests
+ * run, lint/typecheck clean). Shown in /status and used for the /pr draft.
+ *
+ * Only maintained in projects that already have a .floyd/ directory.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { execa } from 'execa';
+import { logger } from '../utils/logger.js';
+
+export interface ChecklistItem {
+    label: string;
+    done: boolean;
+}
+
+export interface BranchState {
+    name?: string;
+    base?: string;
+    status?: string;
+    checklist: ChecklistItem[];
+}
+
+/**
+ * Kinds of verification that map onto checklist items
+ */
+export type VerificationKind = 'tests' | 'lint';
+
+const DEFAULT_TEMPLATE = `# Working Branch & PR Checklist (FLOYD)
+
+## Working Branch
+- Name: [e.g., feat/feature-name]
+- Base: main
+- Status: [in progress | ready for review]
+
+## PR Checklist (Non-Negotiable)
+- [ ] No direct commits to main/master
+- [ ] PR branch created and used
+- [ ] Lint/typecheck pass
+- [ ] Tests added
+- [ ] Migration steps documented (if any)
+- [ ] Env vars documented
+- [ ] How to run locally documented
+- [ ] Known issues / TODOs captured
+- [ ] PR summary written
+`;
+
+/** Checklist lines ticked by each kind of verification */
+const VERIFICATION_ITEMS: Record<VerificationKind, RegExp> = {
+    tests: /^tests\b/i,
+    lint: /^lint|typecheck/i,
+};
+
+const TEST_COMMAND = /\b(npm|pnpm|yarn|bun)\s+(run\s+)?test\b|\bgo\s+test\b|\bcargo\s+test\b|\b(pytest|vitest|jest|ava|mocha)\b/;
+const LINT_COMMAND = /\b(npm|pnpm|yarn|bun)\s+(run\s+)?(lint|typecheck|type-check)\b|\b(eslint|tsc|golangci-lint|ruff|xo)\b|\bgo\s+vet\b/;
+const BRANCH_SWITCH = /\bgit\s+(checkout|switch)\b/;
+
+const PROTECTED_BRANCHES = ['main', 'master'];
+
+/**
+ * Classify a shell command as a verification, if it is one
+ */
+export function classifyVerification(command: string): VerificationKind | null {
+    if (TEST_COMMAND.test(command)) {
+        return 'tests';
+    }
+    if (LINT_COMMAND.test(command)) {
+        return 'lint';
+    }
+    return null;
+}
+
+/**
+ * Set a "- Field: value" line in the Working Branch section
+ */
+export function setBranchField(markdown: string, field: string, value: string): string {
+    const pattern = new RegExp(`^- ${field}:.*$`, 'm');
+    return pattern.test(markdown)
+        ? markdown.replace(pattern, `- ${field}: ${value}`)
+        : markdown;
+}
+
+/**
+ * Tick or untick every checklist item whose label matches
+ */
+export function setChecklistItem(markdown: string, label: RegExp, done: boolean): string {
+    return markdown.replace(/^- \[( |x|X)\] (.+)$/gm, (line, _mark: string, text: string) =>
+        label.test(text) ? `- [${done ? 'x' : ' '}] ${text}` : line
+    );
+}
+
+/**
+ * Read branch fields and checklist items from branch.md content
+ */
+export function parseBranchNotes(markdown: string): BranchState {
+    const field = (name: string): string | undefined => {
+        const value = markdown.match(new RegExp(`^- ${name}:\\s*(.*)$`, 'm'))?.[1]?.trim();
+        return value && !value.startsWith('[') ? value : undefined;
+    };
+
+    const checklist: ChecklistItem[] = [];
+    for (const match of markdown.matchAll(/^- \[( |x|X)\] (.+)$/gm)) {
+        checklist.push({ label: match[2].trim(), done: match[1] !== ' ' });
+    }
+
+    return { name: field('Name'), base: field('Base'), status: field('Status'), checklist };
+}
+
+/**
+ * Format branch state as display lines
+ */
+export function formatBranchState(state: BranchState): string[] {
+    const done = state.checklist.filter(item => item.done).length;
+    const lines = [
+        `Branch: ${state.name ?? 'unrecorded'}${state.base ? ` (base ${state.base})` : ''}`,
+    ];
+    if (state.status) {
+        lines.push(`Status: ${state.status}`);
+    }
+    lines.push(`Checklist: ${done}/${state.checklist.length}`);
+    for (const item of state.checklist) {
+        lines.push(`  ${item.done ? '✓' : '○'} ${item.label}`);
+    }
+    return lines;
+}
+
+/**
+ * Render a pull request description from branch state and git history
+ */
+export function renderPrDraft(state: BranchState, commits: string[], diffStat: string): string {
+    const title = (state.name ?? 'Untitled change')
+        .replace(/^[a-z]+\//i, '')
+        .replace(/[-_]+/g, ' ')
+        .replace(/^./, c => c.toUpperCase());
+
+    const lines = [`# ${title}`, ''];
+    lines.push('## Summary', '');
+    lines.push(...(commits.length > 0 ? commits.map(c => `- ${c}`) : ['- (no commits yet)']));
+
+    if (diffStat) {
+        lines.push('', '## Changes', '', '```', diffStat, '```');
+    }
+
+    if (state.checklist.length > 0) {
+        lines.push('', '## Checklist', '');
+        lines.push(...state.checklist.map(item => `- [${item.done ? 'x' : ' '}] ${item.label}`));
+    }
+
+    return lines.join('\n') + '\n';
+}
+
+/**
+ * Maintains .floyd/branch.md for a workspace
+ */
+export class BranchNotes {
+    private readonly cwd: string;
+    private readonly filePath: string;
+
+    constructor(cwd: string = process.cwd()) {
+        this.cwd = cwd;
+        this.filePath = path.join(cwd, '.floyd', 'branch.md');
+    }
+
+    getFilePath(): string {
+        return this.filePath;
+    }
+
+    /**
+     * Current branch state, or null when there is no branch.md
+     */
+    async read(): Promise<BranchState | null> {
+        try {
+            return parseBranchNotes(await fs.readFile(this.filePath, 'utf-8'));
+        } catch {
+            return null;
+        }
+    }
+
+    /**
+     * Record the checked-out branch, creating branch.md from the template if needed
+     */
+    async recordBranch(): Promise<void> {
+        const branch = await this.git(['rev-parse', '--abbrev-ref', 'HEAD']);
+        if (!branch || branch === 'HEAD') {
+            return;
+        }
+
+        await this.update((markdown) => {
+            const onProtected = PROTECTED_BRANCHES.includes(branch);
+            let next = setBranchField(markdown, 'Name', branch);
+            if (!parseBranchNotes(next).status) {
+                next = setBranchField(next, 'Status', 'in progress');
+            }
+            next = setChecklistItem(next, /^no direct commits/i, !onProtected);
+            next = setChecklistItem(next, /^PR branch created/i, !onProtected);
+            return next;
+        }, true);
+
+        const base = await this.detectBase(branch);
+        if (base) {
+            await this.update(markdown => setBranchField(markdown, 'Base', base));
+        }
+    }
+
+    /**
+     * Note the outcome of a command the agent ran
+     */
+    async recordCommand(command: string, passed: boolean): Promise<void> {
+        if (passed && BRANCH_SWITCH.test(command)) {
+            await this.recordBranch();
+            return;
+        }
+
+        const kind = classifyVerification(command);
+        if (kind) {
+            await this.update(markdown => setChecklistItem(markdown, VERIFICATION_ITEMS[kind], passed));
+        }
+    }
+
+    /**
+     * Set the Status line (e.g. "ready for review")
+     */
+    async setStatus(status: string): Promise<void> {
+        await this.update(markdown => setBranchField(markdown, 'Status', status));
+    }
+
+    /**
+     * Rewrite branch.md; skipped outside FLOYD-initialized projects
+     */
+    private async update(transform: (markdown: string) => string, create = false): Promise<void> {
+        try {
+            if (!(await fs.pathExists(path.dirname(this.filePath)))) {
+                return;
+            }
+
+            const exists = await fs.pathExists(this.filePath);
+            let markdown: string;
+            if (exists) {
+                markdown = await fs.readFile(this.filePath, 'utf-8');
+            } else if (create) {
+                const template = path.join(this.cwd, '.floyd', 'templates', 'branch.md');
+                markdown = await fs.readFile(template, 'utf-8').catch(() => DEFAULT_TEMPLATE);
+            } else {
+                return;
+            }
+
+            const next = transform(markdown);
+            if (next !== markdown || !exists) {
+                await fs.writeFile(this.filePath, next, 'utf-8');
+            }
+        } catch (error) {
+            logger.debug('Failed to update branch notes', { error });
+        }
+    }
+
+    /**
+     * Base branch: the remote default branch, else main/master if present
+     */
+    private async detectBase(branch: string): Promise<string | undefined> {
+        const remoteHead = await this.git(['symbolic-ref', '--short', 'refs/remotes/origin/HEAD']);
+        const candidates = [remoteHead?.replace(/^origin\//, ''), ...PROTECTED_BRANCHES];
+        for (const candidate of candidates) {
+            if (candidate && candidate !== branch && await this.git(['rev-parse', '--verify', '--quiet', candidate])) {
+                return candidate;
+            }
+        }
+        return undefined;
+    }
+
+    private async git(args: string[]): Promise<string | null> {
+        try {
+            const { stdout } = await execa('git', args, { cwd: this.cwd });
+            return stdout.trim();
+        } catch {
+            return null;
+        }
+    }
+}
+
+let defaultBranchNotes: BranchNotes | null = null;
+
+/**
+ * Get the shared branch notes for the current workspace
+ */
+export function getBranchNotes(): BranchNotes {
+    if (!defaultBranchNotes) {
+        defaultBranchNotes = new BranchNotes();
+    }
+    return defaultBranchNotes;
+}
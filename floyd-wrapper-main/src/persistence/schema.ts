@@ -55,10 +55,25 @@ export function initializeSchema(db: Database.Database): void {
     )
   `);
 
+    // Create conversation checkpoints table (snapshots of the agent's messages;
+    // branches are sessions forked from one, see SessionMetadata.parentSessionId)
+    db.exec(`
+    CREATE TABLE IF NOT EXISTS conversation_checkpoints (
+      id TEXT PRIMARY KEY,
+      session_id TEXT NOT NULL,
+      name TEXT NOT NULL,
+      message_count INTEGER NOT NULL,
+      messages_json TEXT NOT NULL,
+      timestamp INTEGER NOT NULL,
+      FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
+    )
+  `);
+
     // Create indexes for performance
     db.exec(`CREATE INDEX IF NOT EXISTS idx_history_session_id ON history(session_id)`);
     db.exec(`CREATE INDEX IF NOT EXISTS idx_history_timestamp ON history(timestamp)`);
     db.exec(`CREATE INDEX IF NOT EXISTS idx_checkpoints_session_id ON checkpoints(session_id)`);
+    db.exec(`CREATE INDEX IF NOT EXISTS idx_conversation_checkpoints_session_id ON conversation_checkpoints(session_id)`);
 
     logger.debug('Database schema initialized');
 }
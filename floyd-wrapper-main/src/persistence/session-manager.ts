@@ -11,8 +11,48 @@ import path from 'node:path';
 import fs from 'fs-extra';
 import { initializeSchema } from './schema.js';
 import { logger } from '../utils/logger.js';
+import { isRedactionEnabled, redactSecrets, auditRedactions } from '../utils/redaction.js';
 import type { Session, SessionMetadata, FloydMessage } from '../types.js';
 
+/**
+ * A history message matched by searchHistory()
+ */
+export interface HistorySearchResult {
+    sessionId: string;
+    sessionName: string;
+    role: string;
+    content: string;
+    timestamp: number;
+}
+
+/**
+ * A saved point in a conversation that can be rewound to or branched from
+ */
+export interface ConversationCheckpoint {
+    id: string;
+    sessionId: string;
+    name: string;
+    messageCount: number;
+    timestamp: number;
+}
+
+/**
+ * A session and the branches forked from it
+ */
+export interface BranchNode {
+    session: Session;
+    children: BranchNode[];
+}
+
+/**
+ * Normalize user-entered tags: "#Auth-Refactor" -> "auth-refactor"
+ * Returns null for tags with characters other than letters, digits, - _ . /
+ */
+export function normalizeTag(tag: string): string | null {
+    const normalized = tag.trim().replace(/^#/, '').toLowerCase();
+    return /^[a-z0-9][a-z0-9_.\/-]*$/.test(normalized) ? normalized : null;
+}
+
 export class SessionManager {
     private db: Database.Database;
     private currentSessionId: string | null = null;
@@ -61,22 +101,109 @@ export class SessionManager {
     }
 
     /**
-     * List all sessions
+     * List all sessions, optionally only those with a tag
      */
-    listSessions(): Session[] {
+    listSessions(filter: { tag?: string } = {}): Session[] {
         const stmt = this.db.prepare(`
       SELECT * FROM sessions ORDER BY updated_at DESC
     `);
 
         const rows = stmt.all() as any[];
 
-        return rows.map(row => ({
+        const sessions: Session[] = rows.map(row => ({
             id: row.id,
             name: row.name,
             createdAt: row.created_at,
             updatedAt: row.updated_at,
             metadata: JSON.parse(row.metadata_json || '{}')
         }));
+
+        const tag = filter.tag ? normalizeTag(filter.tag) : undefined;
+        return tag === undefined
+            ? sessions
+            : sessions.filter(session => tag !== null && (session.metadata.tags ?? []).includes(tag));
+    }
+
+    /**
+     * Tags of a session (the current one by default)
+     */
+    getSessionTags(sessionId: string | null = this.currentSessionId): string[] {
+        if (!sessionId) {
+            return [];
+        }
+
+        const row = this.db.prepare('SELECT metadata_json FROM sessions WHERE id = ?').get(sessionId) as any;
+        const metadata: SessionMetadata = JSON.parse(row?.metadata_json || '{}');
+        return metadata.tags ?? [];
+    }
+
+    /**
+     * Add and remove tags on a session (the current one by default)
+     * Returns the resulting tags, sorted
+     */
+    updateSessionTags(
+        changes: { add?: string[]; remove?: string[] },
+        sessionId: string | null = this.currentSessionId
+    ): string[] {
+        if (!sessionId) {
+            throw new Error('No active session');
+        }
+
+        const row = this.db.prepare('SELECT metadata_json FROM sessions WHERE id = ?').get(sessionId) as any;
+        if (!row) {
+            throw new Error(`Session not found: ${sessionId}`);
+        }
+
+        const normalize = (tags: string[] = []) => tags.map(tag => {
+            const normalized = normalizeTag(tag);
+            if (!normalized) {
+                throw new Error(`Invalid tag: ${tag}`);
+            }
+            return normalized;
+        });
+
+        const metadata: SessionMetadata = JSON.parse(row.metadata_json || '{}');
+        const remove = new Set(normalize(changes.remove));
+        const tags = new Set([...(metadata.tags ?? []), ...normalize(changes.add)].filter(tag => !remove.has(tag)));
+        metadata.tags = [...tags].sort();
+
+        this.db.prepare('UPDATE sessions SET metadata_json = ? WHERE id = ?').run(JSON.stringify(metadata), sessionId);
+        logger.debug('Updated session tags', { sessionId, tags: metadata.tags });
+
+        return metadata.tags;
+    }
+
+    /**
+     * Search message history across sessions (case-insensitive substring),
+     * newest first, optionally only in sessions with a tag
+     */
+    searchHistory(query: string, options: { tag?: string; limit?: number } = {}): HistorySearchResult[] {
+        const tagged = options.tag ? new Set(this.listSessions({ tag: options.tag }).map(session => session.id)) : null;
+
+        const stmt = this.db.prepare(`
+      SELECT history.session_id, sessions.name, history.role, history.content, history.timestamp
+      FROM history JOIN sessions ON sessions.id = history.session_id
+      WHERE history.role != 'system' AND instr(lower(history.content), lower(?)) > 0
+      ORDER BY history.timestamp DESC
+    `);
+
+        const results: HistorySearchResult[] = [];
+        for (const row of stmt.iterate(query) as Iterable<any>) {
+            if (tagged && !tagged.has(row.session_id)) {
+                continue;
+            }
+            results.push({
+                sessionId: row.session_id,
+                sessionName: row.name,
+                role: row.role,
+                content: row.content,
+                timestamp: row.timestamp
+            });
+            if (results.length >= (options.limit ?? 20)) {
+                break;
+            }
+        }
+        return results;
     }
 
     /**
@@ -120,8 +247,8 @@ export class SessionManager {
         const id = uuidv4();
         const now = Date.now();
 
-        // Redaction logic would go here
-        const redactedContent = this.redactSecrets(content);
+        // Prompts and tool results arrive redacted; this catches the rest
+        const redactedContent = this.redactSecrets(content, role);
 
         const stmt = this.db.prepare(`
       INSERT INTO history (id, session_id, role, content, timestamp, tool_use_id)
@@ -181,11 +308,15 @@ export class SessionManager {
     }
 
     /**
-     * Redact secrets from content (Placeholder)
+     * Redact secrets from content (FLOYD_REDACT_SECRETS=false keeps it as is)
      */
-    private redactSecrets(content: string): string {
-        // TODO: Implement actual regex replacement for API keys
-        return content;
+    private redactSecrets(content: string, role: string): string {
+        if (!isRedactionEnabled()) {
+            return content;
+        }
+        const { text, redactions } = redactSecrets(content);
+        auditRedactions(redactions, `session:${role}`, path.dirname(path.dirname(this.dbPath)));
+        return text;
     }
 
     /**
@@ -406,4 +537,149 @@ export class SessionManager {
 
         return totalRemoved;
     }
+
+    /**
+     * Snapshot the agent's messages under a name in the current session
+     */
+    createConversationCheckpoint(name: string, messages: FloydMessage[]): ConversationCheckpoint {
+        if (!this.currentSessionId) {
+            throw new Error('No active session for checkpoint');
+        }
+        if (this.listConversationCheckpoints().some(cp => cp.name === name)) {
+            throw new Error(`Checkpoint already exists: ${name}`);
+        }
+
+        const checkpoint: ConversationCheckpoint = {
+            id: uuidv4(),
+            sessionId: this.currentSessionId,
+            name,
+            messageCount: messages.length,
+            timestamp: Date.now()
+        };
+
+        this.db.prepare(`
+            INSERT INTO conversation_checkpoints (id, session_id, name, message_count, messages_json, timestamp)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `).run(checkpoint.id, checkpoint.sessionId, name, checkpoint.messageCount, JSON.stringify(messages), checkpoint.timestamp);
+        logger.info('Created conversation checkpoint', { id: checkpoint.id, name });
+
+        return checkpoint;
+    }
+
+    /**
+     * Conversation checkpoints of a session (the current one by default), oldest first
+     */
+    listConversationCheckpoints(sessionId: string | null = this.currentSessionId): ConversationCheckpoint[] {
+        if (!sessionId) {
+            return [];
+        }
+
+        const rows = this.db.prepare(`
+            SELECT id, session_id, name, message_count, timestamp
+            FROM conversation_checkpoints
+            WHERE session_id = ?
+            ORDER BY timestamp ASC
+        `).all(sessionId) as any[];
+
+        return rows.map(row => ({
+            id: row.id,
+            sessionId: row.session_id,
+            name: row.name,
+            messageCount: row.message_count,
+            timestamp: row.timestamp
+        }));
+    }
+
+    /**
+     * Find a conversation checkpoint by name (current session) or ID prefix
+     * (any session), with its messages
+     */
+    getConversationCheckpoint(idOrName: string): (ConversationCheckpoint & { messages: FloydMessage[] }) | null {
+        const byName = this.db.prepare(`
+            SELECT * FROM conversation_checkpoints WHERE session_id = ? AND name = ?
+        `).get(this.currentSessionId, idOrName) as any;
+        const row = byName ?? this.db.prepare(`
+            SELECT * FROM conversation_checkpoints WHERE id LIKE ? ORDER BY timestamp DESC
+        `).get(`${idOrName.replace(/[%_]/g, '')}%`) as any;
+
+        if (!row) {
+            return null;
+        }
+
+        return {
+            id: row.id,
+            sessionId: row.session_id,
+            name: row.name,
+            messageCount: row.message_count,
+            timestamp: row.timestamp,
+            messages: JSON.parse(row.messages_json)
+        };
+    }
+
+    /**
+     * Start a new branch from a conversation checkpoint: a child session whose
+     * history is the checkpoint's messages. The branch becomes the current
+     * session; the session it came from is left untouched.
+     */
+    forkSession(checkpointIdOrName: string, name?: string): Session {
+        const checkpoint = this.getConversationCheckpoint(checkpointIdOrName);
+        if (!checkpoint) {
+            throw new Error(`Checkpoint not found: ${checkpointIdOrName}`);
+        }
+
+        const parent = this.db.prepare('SELECT name, metadata_json FROM sessions WHERE id = ?').get(checkpoint.sessionId) as any;
+        const parentMetadata: SessionMetadata = JSON.parse(parent?.metadata_json || '{}');
+
+        const fork = this.db.transaction(() => {
+            const session = this.createSession(name || `${parent?.name ?? 'Session'} @ ${checkpoint.name}`, {
+                tags: parentMetadata.tags,
+                parentSessionId: checkpoint.sessionId,
+                forkCheckpointId: checkpoint.id
+            });
+
+            // Copy the messages, keeping them strictly ordered by timestamp
+            const insert = this.db.prepare(`
+                INSERT INTO history (id, session_id, role, content, timestamp, tool_use_id)
+                VALUES (?, ?, ?, ?, ?, ?)
+            `);
+            let timestamp = 0;
+            for (const message of checkpoint.messages) {
+                timestamp = Math.max(message.timestamp, timestamp + 1);
+                insert.run(uuidv4(), session.id, message.role, message.content, timestamp, message.toolUseId);
+            }
+            return session;
+        });
+
+        const session = fork();
+        logger.info('Forked session', { sessionId: session.id, from: checkpoint.sessionId, checkpoint: checkpoint.name });
+        return session;
+    }
+
+    /**
+     * The branch tree that contains a session (the current one by default)
+     */
+    getBranchTree(sessionId: string | null = this.currentSessionId): BranchNode | null {
+        const sessions = this.listSessions();
+        const byId = new Map(sessions.map(session => [session.id, session]));
+        let root = sessionId ? byId.get(sessionId) : undefined;
+        if (!root) {
+            return null;
+        }
+
+        // Walk up to the root, guarding against cycles in hand-edited metadata
+        const seen = new Set<string>([root.id]);
+        while (root.metadata.parentSessionId && byId.has(root.metadata.parentSessionId) && !seen.has(root.metadata.parentSessionId)) {
+            root = byId.get(root.metadata.parentSessionId)!;
+            seen.add(root.id);
+        }
+
+        const build = (session: Session, visited: Set<string>): BranchNode => ({
+            session,
+            children: sessions
+                .filter(child => child.metadata.parentSessionId === session.id && !visited.has(child.id))
+                .sort((a, b) => a.createdAt - b.createdAt)
+                .map(child => build(child, new Set([...visited, child.id])))
+        });
+        return build(root, new Set([root.id]));
+    }
 }
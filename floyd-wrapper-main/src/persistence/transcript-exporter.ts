@@ -0,0 +1,61 @@
+/**
+ * Transcript Exporter - Floyd Wrapper
+ *
+ * Saves a session's messages as a Markdown or HTML transcript under
+ * .floyd/exports/, using the renderer in floyd-agent-core that the Ink TUI
+ * shares.
+ */
+
+import { exportTranscript, type TranscriptFormat, type TranscriptMessage } from 'floyd-agent-core/utils';
+import { logger } from '../utils/logger.js';
+import type { FloydMessage, Session } from '../types.js';
+
+export { parseTranscriptFormat, type TranscriptFormat } from 'floyd-agent-core/utils';
+
+export interface TranscriptExportOptions {
+    /** Output format */
+    format: TranscriptFormat;
+    /** Output directory (default: <cwd>/.floyd/exports) */
+    outputDir?: string;
+    /** Include the system prompt */
+    includeSystem?: boolean;
+}
+
+/**
+ * Convert a wrapper message to the shared transcript shape
+ */
+export function toTranscriptMessage(message: FloydMessage): TranscriptMessage {
+    return {
+        role: message.role,
+        content: message.content,
+        timestamp: message.timestamp,
+        name: message.toolName,
+        input: message.toolInput,
+    };
+}
+
+/**
+ * Save conversation transcripts
+ */
+export class TranscriptExporter {
+    /**
+     * Export messages to a transcript file
+     *
+     * @returns Path of the written file
+     */
+    static async exportSession(
+        session: Session | null,
+        messages: FloydMessage[],
+        options: TranscriptExportOptions
+    ): Promise<string> {
+        const visible = messages.filter(m => options.includeSystem || m.role !== 'system');
+        const filepath = await exportTranscript(visible.map(toTranscriptMessage), {
+            format: options.format,
+            title: session?.name,
+            outputDir: options.outputDir,
+        });
+
+        logger.info('Exported transcript', { filepath, messages: visible.length });
+        return filepath;
+    }
+}
@@ -56,6 +56,7 @@ For merges: git_merge (detects conflicts, provides resolution hints)
 EFFICIENCY:
 - Use codebase_search for discovery (semantic understanding)
 - Use grep for precise pattern matching (TODO, error codes, identifiers)
+- Use symbols for where a function/type is defined, where it is used, or what a file/package declares (file:line results)
 
 ## SUPERCACHE - 3-TIER INTELLIGENT MEMORY (12 tools)
 
@@ -118,7 +119,7 @@ SAFETY:
 - Check exit codes after running commands
 - Use ask_user for ambiguous requirements
 
-## BROWSER AUTOMATION (9 tools)
+## BROWSER AUTOMATION (11 tools)
 34. **browser_status** - Check browser connection (MUST USE FIRST)
 35. **browser_navigate** - Navigate to URL
 36. **browser_read_page** - Read page content as text
@@ -128,11 +129,13 @@ SAFETY:
 40. **browser_find** - Find elements by selector
 41. **browser_get_tabs** - List open tabs
 42. **browser_create_tab** - Create new tab for parallel browsing
+- **browser_javascript_exec** - Run JavaScript in the page and return the value (Chrome DevTools only)
+- **browser_network_log** - Requests the page made, with status and timing (Chrome DevTools only)
 
 REQUIREMENTS:
-- FloydChrome extension must be running (ws://localhost:3005)
-- Always check browser_status first
-- Gracefully handle if extension not available
+- FloydChrome extension running (ws://localhost:3005), or Chrome started with --remote-debugging-port=9222
+- Always check browser_status first (it reports which backend is in use)
+- Gracefully handle if neither is available
 
 ## PATCH OPERATIONS (5 tools)
 43. **apply_unified_diff** - Apply unified diffs (SAFEST for multi-file)
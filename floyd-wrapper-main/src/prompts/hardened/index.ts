@@ -306,8 +306,9 @@ Time: ${new Date().toISOString()}
 
 	const projectSection = projectContext
 		? `
-## PROJECT MEMORY (FLOYD.md)
-The following project-specific instructions and context have been provided:
+## PROJECT MEMORY (FLOYD.md / AGENTS.md / CLAUDE.md)
+The following project-specific instructions and context have been provided.
+Instruction files in subdirectories are attached to tool results as project_instructions when you first work there; they refine these for that part of the tree:
 
 ${projectContext}
 `
@@ -130,6 +130,7 @@ Use this exact routing. Do not improvise.
 
 ## DISCOVERY
 - Concept search => ${BACKTICK}codebase_search${BACKTICK}
+- Definitions/references/outlines of code symbols => ${BACKTICK}symbols${BACKTICK}
 - Exact identifiers/literals => ${BACKTICK}grep${BACKTICK}
 - After any hit => ${BACKTICK}read_file${BACKTICK} the owning file(s)
 
@@ -45,10 +45,11 @@ Time: ${new Date().toISOString()}
 - Write production-ready, elegant code
 `;
 
-    // Project Context from FLOYD.md
+    // Project Context from FLOYD.md / AGENTS.md / CLAUDE.md
     const projectSection = projectContext ? `
-## Project Memory (FLOYD.md)
-The following project-specific instructions and context have been provided:
+## Project Memory (FLOYD.md / AGENTS.md / CLAUDE.md)
+The following project-specific instructions and context have been provided.
+Instruction files in subdirectories are attached to tool results as project_instructions when you first work there:
 
 ${projectContext}
 ` : '';
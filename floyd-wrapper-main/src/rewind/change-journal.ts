@@ -0,0 +1,215 @@
+/**
+ * Change Journal - Floyd Wrapper
+ *
+ * Records file changes made by tools (created/modified/deleted with line counts)
+ * so the engine can summarize what a run touched.
+ *
+ * @module rewind/change-journal
+ */
+
+import crypto from 'node:crypto';
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Kind of change applied to a file
+ */
+export type ChangeType = 'created' | 'modified' | 'deleted';
+
+/**
+ * Single journal entry recorded after a tool touched a file
+ */
+export interface ChangeEntry {
+  /** Absolute file path */
+  path: string;
+  /** Tool that made the change */
+  toolName: string;
+  /** Line count before the change (null if file did not exist) */
+  linesBefore: number | null;
+  /** Line count after the change (null if file no longer exists) */
+  linesAfter: number | null;
+  /** When the change was recorded */
+  timestamp: number;
+}
+
+/**
+ * Net change for one file across a range of entries
+ */
+export interface FileChangeSummary {
+  path: string;
+  type: ChangeType;
+  added: number;
+  removed: number;
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Count lines in a file, or null if it does not exist or is not a file
+ */
+export async function countFileLines(filePath: string): Promise<number | null> {
+  try {
+    const stat = await fs.stat(filePath);
+    if (!stat.isFile()) {
+      return null;
+    }
+    const content = await fs.readFile(filePath, 'utf-8');
+    if (content.length === 0) {
+      return 0;
+    }
+    return content.split('\n').length - (content.endsWith('\n') ? 1 : 0);
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * SHA-256 of a file's contents, or null if it does not exist or is not a file
+ */
+export async function hashFile(filePath: string): Promise<string | null> {
+  try {
+    const stat = await fs.stat(filePath);
+    if (!stat.isFile()) {
+      return null;
+    }
+    return crypto.createHash('sha256').update(await fs.readFile(filePath)).digest('hex');
+  } catch {
+    return null;
+  }
+}
+
+// ============================================================================
+// Change Journal Class
+// ============================================================================
+
+/**
+ * Append-only journal of tool file changes
+ */
+export class ChangeJournal {
+  private entries: ChangeEntry[] = [];
+
+  /**
+   * Record a change
+   */
+  record(entry: Omit<ChangeEntry, 'timestamp'> & { timestamp?: number }): void {
+    if (entry.linesBefore === null && entry.linesAfter === null) {
+      return;
+    }
+    this.entries.push({
+      ...entry,
+      path: path.resolve(entry.path),
+      timestamp: entry.timestamp ?? Date.now(),
+    });
+  }
+
+  /**
+   * Current position in the journal, used to summarize a single run
+   */
+  mark(): number {
+    return this.entries.length;
+  }
+
+  /**
+   * All entries recorded since a mark
+   */
+  getEntriesSince(mark: number): ChangeEntry[] {
+    return this.entries.slice(mark);
+  }
+
+  /**
+   * Net per-file changes since a mark
+   */
+  summarizeSince(mark: number): FileChangeSummary[] {
+    const byPath = new Map<string, { first: ChangeEntry; last: ChangeEntry }>();
+
+    for (const entry of this.getEntriesSince(mark)) {
+      const existing = byPath.get(entry.path);
+      if (existing) {
+        existing.last = entry;
+      } else {
+        byPath.set(entry.path, { first: entry, last: entry });
+      }
+    }
+
+    const summaries: FileChangeSummary[] = [];
+    for (const [filePath, { first, last }] of byPath) {
+      const before = first.linesBefore;
+      const after = last.linesAfter;
+
+      // Created and deleted within the same run - nothing left to report
+      if (before === null && after === null) {
+        continue;
+      }
+
+      const type: ChangeType = before === null ? 'created' : after === null ? 'deleted' : 'modified';
+      const delta = (after ?? 0) - (before ?? 0);
+
+      summaries.push({
+        path: filePath,
+        type,
+        added: Math.max(0, delta),
+        removed: Math.max(0, -delta),
+      });
+    }
+
+    return summaries;
+  }
+
+  /**
+   * Clear all entries
+   */
+  clear(): void {
+    this.entries = [];
+  }
+}
+
+/**
+ * Format a compact, human-readable summary of file changes
+ *
+ * @param changes - Per-file summaries
+ * @param cwd - Directory paths are shown relative to
+ * @returns Summary text, or empty string when nothing changed
+ */
+export function formatChangeSummary(changes: FileChangeSummary[], cwd: string = process.cwd()): string {
+  if (changes.length === 0) {
+    return '';
+  }
+
+  const marker: Record<ChangeType, string> = { created: 'A', modified: 'M', deleted: 'D' };
+  const lines = changes.map(change => {
+    const relative = path.relative(cwd, change.path) || change.path;
+    return `  ${marker[change.type]} ${relative} (+${change.added} -${change.removed})`;
+  });
+
+  const counts = (['created', 'modified', 'deleted'] as ChangeType[])
+    .map(type => {
+      const count = changes.filter(c => c.type === type).length;
+      return count > 0 ? `${count} ${type}` : '';
+    })
+    .filter(Boolean)
+    .join(', ');
+
+  return `Files changed this run: ${counts}\n${lines.join('\n')}`;
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultChangeJournal: ChangeJournal | null = null;
+
+/**
+ * Get the shared change journal
+ */
+export function getChangeJournal(): ChangeJournal {
+  if (!defaultChangeJournal) {
+    defaultChangeJournal = new ChangeJournal();
+  }
+  return defaultChangeJournal;
+}
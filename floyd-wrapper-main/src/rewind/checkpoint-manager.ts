@@ -110,6 +110,7 @@ export const DANGEROUS_TOOLS = [
   'git_rebase',
   'execute_command',
   'bash',
+  'format',
 ];
 
 // ============================================================================
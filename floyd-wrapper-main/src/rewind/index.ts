@@ -30,3 +30,30 @@ export type {
   FileSnapshot,
   SnapshotOptions,
 } from './file-snapshot.js';
+
+export {
+  ChangeJournal,
+  getChangeJournal,
+  countFileLines,
+  hashFile,
+  formatChangeSummary,
+} from './change-journal.js';
+
+export type {
+  ChangeType,
+  ChangeEntry,
+  FileChangeSummary,
+} from './change-journal.js';
+
+export {
+  WorkspaceSnapshotManager,
+  SnapshotConflictError,
+  getWorkspaceSnapshotManager,
+} from './workspace-snapshot.js';
+
+export type {
+  SnapshotFile,
+  WorkspaceSnapshot,
+  SnapshotRestoreResult,
+  SnapshotRestoreOptions,
+} from './workspace-snapshot.js';
@@ -0,0 +1,318 @@
+/**
+ * Workspace Snapshot - Floyd Wrapper
+ *
+ * Whole-workspace snapshots for risky multi-file operations. Coarser than
+ * per-edit checkpoints: /snapshot records everything at once and /restore
+ * rolls the workspace back wholesale.
+ *
+ * In a git repository the tracked tree is captured with `git stash create`
+ * (the working tree and index are left untouched) and pinned under
+ * refs/floyd/snapshots/. Every file the agent has touched this session
+ * (from the change journal) is also stored by content, so files the agent
+ * created are covered even when untracked. Outside git only the
+ * agent-tracked files are recorded.
+ *
+ * Restoring the git tree rewrites every tracked file that differs from the
+ * snapshot, so it is refused while files the agent never touched have
+ * changed since (the user's own edits) unless the caller forces it.
+ *
+ * @module rewind/workspace-snapshot
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { execa } from 'execa';
+import { getChangeJournal } from './change-journal.js';
+import { logger } from '../utils/logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Recorded state of a single file
+ */
+export interface SnapshotFile {
+  /** Absolute path */
+  path: string;
+  /** Whether the file existed when the snapshot was taken */
+  exists: boolean;
+  /** Base64 file content (absent when the file did not exist) */
+  content?: string;
+}
+
+/**
+ * Workspace snapshot manifest
+ */
+export interface WorkspaceSnapshot {
+  id: string;
+  name: string;
+  createdAt: number;
+  /** Workspace root */
+  cwd: string;
+  /** Commit capturing the tracked tree (git workspaces only) */
+  gitCommit?: string;
+  /** Agent-tracked files */
+  files: SnapshotFile[];
+}
+
+/**
+ * Result of restoring a snapshot
+ */
+export interface SnapshotRestoreResult {
+  /** Whether the git tree was restored */
+  gitRestored: boolean;
+  restored: string[];
+  deleted: string[];
+  failed: string[];
+}
+
+/**
+ * Options for restoring a snapshot
+ */
+export interface SnapshotRestoreOptions {
+  /** Restore even though files the agent did not touch have changed */
+  force?: boolean;
+}
+
+/**
+ * Restore refused because files outside the agent's changes differ from
+ * the snapshot
+ */
+export class SnapshotConflictError extends Error {
+  constructor(readonly paths: string[]) {
+    super(`${paths.length} file(s) changed since the snapshot were not changed by FLOYD: ${paths.slice(0, 5).join(', ')}${paths.length > 5 ? ', ...' : ''}`);
+    this.name = 'SnapshotConflictError';
+  }
+}
+
+// ============================================================================
+// Workspace Snapshot Manager Class
+// ============================================================================
+
+/**
+ * Creates, lists and restores workspace snapshots stored in .floyd/snapshots/
+ */
+export class WorkspaceSnapshotManager {
+  private readonly cwd: string;
+  private readonly storageDir: string;
+
+  /** Journal positions at snapshot time, for files first touched afterwards */
+  private readonly journalMarks = new Map<string, number>();
+
+  constructor(cwd: string = process.cwd()) {
+    this.cwd = path.resolve(cwd);
+    this.storageDir = path.join(this.cwd, '.floyd', 'snapshots');
+  }
+
+  /**
+   * Snapshot the workspace
+   */
+  async create(name?: string): Promise<WorkspaceSnapshot> {
+    const id = `snap-${Date.now().toString(36)}`;
+    const journal = getChangeJournal();
+
+    const gitCommit = await this.captureGitTree(id);
+
+    const trackedPaths = [...new Set(journal.getEntriesSince(0).map(e => e.path))];
+    const files: SnapshotFile[] = [];
+    for (const filePath of trackedPaths) {
+      files.push(await this.captureFile(filePath));
+    }
+
+    if (!gitCommit && files.length === 0) {
+      throw new Error('Nothing to snapshot: not a git repository and no agent-tracked files yet');
+    }
+
+    const snapshot: WorkspaceSnapshot = {
+      id,
+      name: name || id,
+      createdAt: Date.now(),
+      cwd: this.cwd,
+      gitCommit,
+      files,
+    };
+
+    await fs.ensureDir(this.storageDir);
+    await fs.writeJson(path.join(this.storageDir, `${id}.json`), snapshot, { spaces: 2 });
+    this.journalMarks.set(id, journal.mark());
+
+    logger.info('Workspace snapshot created', { id, gitCommit, files: files.length });
+    return snapshot;
+  }
+
+  /**
+   * List snapshots, newest first
+   */
+  async list(): Promise<WorkspaceSnapshot[]> {
+    if (!(await fs.pathExists(this.storageDir))) {
+      return [];
+    }
+
+    const snapshots: WorkspaceSnapshot[] = [];
+    for (const file of await fs.readdir(this.storageDir)) {
+      if (!file.endsWith('.json')) {
+        continue;
+      }
+      try {
+        snapshots.push(await fs.readJson(path.join(this.storageDir, file)));
+      } catch (error) {
+        logger.warn(`Failed to read snapshot ${file}`, { error });
+      }
+    }
+
+    return snapshots.sort((a, b) => b.createdAt - a.createdAt);
+  }
+
+  /**
+   * Find a snapshot by ID, ID prefix or name
+   */
+  async find(idOrName: string): Promise<WorkspaceSnapshot | null> {
+    const snapshots = await this.list();
+    return snapshots.find(s => s.id === idOrName || s.name === idOrName)
+      ?? snapshots.find(s => s.id.startsWith(idOrName))
+      ?? null;
+  }
+
+  /**
+   * Files differing from the snapshot's git tree that the agent has not
+   * touched (absolute paths; empty outside git)
+   */
+  async unrelatedChanges(snapshot: WorkspaceSnapshot): Promise<string[]> {
+    if (!snapshot.gitCommit) {
+      return [];
+    }
+
+    const { stdout } = await execa('git', ['diff', '--name-only', '-z', '--relative', snapshot.gitCommit, '--', '.'], { cwd: this.cwd });
+    const agentPaths = new Set([
+      ...snapshot.files.map(f => f.path),
+      ...getChangeJournal().getEntriesSince(this.journalMarks.get(snapshot.id) ?? 0).map(e => e.path),
+    ]);
+    return stdout.split('\0')
+      .filter(Boolean)
+      .map(file => path.resolve(this.cwd, file))
+      .filter(file => !agentPaths.has(file));
+  }
+
+  /**
+   * Roll the workspace back to a snapshot
+   *
+   * @throws SnapshotConflictError when files the agent did not touch have
+   * changed since the snapshot and options.force is not set
+   */
+  async restore(snapshot: WorkspaceSnapshot, options: SnapshotRestoreOptions = {}): Promise<SnapshotRestoreResult> {
+    const result: SnapshotRestoreResult = { gitRestored: false, restored: [], deleted: [], failed: [] };
+
+    if (snapshot.gitCommit && !options.force) {
+      const unrelated = await this.unrelatedChanges(snapshot);
+      if (unrelated.length > 0) {
+        throw new SnapshotConflictError(unrelated);
+      }
+    }
+
+    if (snapshot.gitCommit) {
+      try {
+        await execa('git', ['restore', `--source=${snapshot.gitCommit}`, '--worktree', '--', '.'], { cwd: this.cwd });
+        result.gitRestored = true;
+      } catch (error) {
+        logger.error('Failed to restore git tree from snapshot', error);
+        result.failed.push(`git tree (${snapshot.gitCommit.slice(0, 12)})`);
+      }
+    }
+
+    for (const file of snapshot.files) {
+      await this.restoreFile(file, result);
+    }
+
+    // Files the agent created after the snapshot (same session only)
+    const mark = this.journalMarks.get(snapshot.id);
+    if (mark !== undefined) {
+      const known = new Set(snapshot.files.map(f => f.path));
+      for (const entry of getChangeJournal().getEntriesSince(mark)) {
+        if (!known.has(entry.path) && entry.linesBefore === null) {
+          known.add(entry.path);
+          await this.restoreFile({ path: entry.path, exists: false }, result);
+        }
+      }
+    }
+
+    logger.info('Workspace snapshot restored', {
+      id: snapshot.id,
+      restored: result.restored.length,
+      deleted: result.deleted.length,
+      failed: result.failed.length,
+    });
+    return result;
+  }
+
+  /**
+   * Capture the tracked tree as a commit (undefined outside git)
+   */
+  private async captureGitTree(id: string): Promise<string | undefined> {
+    try {
+      await execa('git', ['rev-parse', '--is-inside-work-tree'], { cwd: this.cwd });
+    } catch {
+      return undefined;
+    }
+
+    try {
+      // `git stash create` prints nothing when the tree is clean
+      const { stdout } = await execa('git', ['stash', 'create', `floyd snapshot ${id}`], { cwd: this.cwd });
+      const commit = stdout.trim() || (await execa('git', ['rev-parse', 'HEAD'], { cwd: this.cwd })).stdout.trim();
+
+      // Pin it so git gc does not collect it
+      await execa('git', ['update-ref', `refs/floyd/snapshots/${id}`, commit], { cwd: this.cwd });
+      return commit;
+    } catch (error) {
+      logger.warn('Git snapshot failed, falling back to agent-tracked files', { error });
+      return undefined;
+    }
+  }
+
+  /**
+   * Record a file's current state
+   */
+  private async captureFile(filePath: string): Promise<SnapshotFile> {
+    try {
+      const content = await fs.readFile(filePath);
+      return { path: filePath, exists: true, content: content.toString('base64') };
+    } catch {
+      return { path: filePath, exists: false };
+    }
+  }
+
+  /**
+   * Put a file back in its recorded state
+   */
+  private async restoreFile(file: SnapshotFile, result: SnapshotRestoreResult): Promise<void> {
+    try {
+      if (file.exists) {
+        await fs.ensureDir(path.dirname(file.path));
+        await fs.writeFile(file.path, Buffer.from(file.content ?? '', 'base64'));
+        result.restored.push(file.path);
+      } else if (await fs.pathExists(file.path)) {
+        await fs.remove(file.path);
+        result.deleted.push(file.path);
+      }
+    } catch (error) {
+      logger.error(`Failed to restore ${file.path}`, error);
+      result.failed.push(file.path);
+    }
+  }
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultWorkspaceSnapshotManager: WorkspaceSnapshotManager | null = null;
+
+/**
+ * Get the shared workspace snapshot manager
+ */
+export function getWorkspaceSnapshotManager(): WorkspaceSnapshotManager {
+  if (!defaultWorkspaceSnapshotManager) {
+    defaultWorkspaceSnapshotManager = new WorkspaceSnapshotManager();
+  }
+  return defaultWorkspaceSnapshotManager;
+}
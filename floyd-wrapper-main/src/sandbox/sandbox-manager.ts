@@ -5,11 +5,19 @@
  * Supports directory-based sandboxing (copy project to temp) and
  * optional Docker container sandboxing.
  *
+ * Overlay sandboxes work in any mode: nothing is copied upfront, files are
+ * copied into .floyd/sandbox/<id>/ the first time a tool writes them, and
+ * reads of those files are redirected to the overlay. Each session gets its
+ * own overlay, so parallel runs never touch the live repo; applying writes
+ * a patch of everything changed next to the overlay.
+ *
  * @module sandbox/sandbox-manager
  */
 
-import { mkdir, cp, rm, readdir, readFile } from 'node:fs/promises';
+import { mkdir, cp, rm, readdir, readFile, writeFile } from 'node:fs/promises';
+import { existsSync, statSync, mkdirSync, copyFileSync } from 'node:fs';
 import { join, dirname, relative } from 'node:path';
+import { createTwoFilesPatch } from 'diff';
 import { tmpdir } from 'node:os';
 import { randomBytes } from 'node:crypto';
 import { logger } from '../utils/logger.js';
@@ -21,7 +29,7 @@ import { logger } from '../utils/logger.js';
 /**
  * Sandbox type
  */
-export type SandboxType = 'directory' | 'docker' | 'dryrun';
+export type SandboxType = 'directory' | 'docker' | 'dryrun' | 'overlay';
 
 /**
  * Sandbox state
@@ -88,6 +96,8 @@ export interface CommitResult {
   failed: string[];
   /** Whether commit was successful */
   success: boolean;
+  /** Patch of the applied changes (overlay sandboxes) */
+  patchPath?: string;
 }
 
 // ============================================================================
@@ -151,7 +161,7 @@ export class SandboxManager {
   /**
    * Start a new sandbox session
    */
-  async start(projectRoot: string): Promise<SandboxSession> {
+  async start(projectRoot: string, type: SandboxType = this.options.type): Promise<SandboxSession> {
     if (this.session?.state === 'active') {
       throw new Error('Sandbox already active. Commit or discard first.');
     }
@@ -159,17 +169,21 @@ export class SandboxManager {
     // Generate unique sandbox ID
     const id = `sandbox-${Date.now().toString(36)}-${randomBytes(4).toString('hex')}`;
 
-    // Create sandbox directory
-    const sandboxRoot = join(this.options.sandboxRoot, id);
+    // Create sandbox directory (overlays live inside the project)
+    const sandboxRoot = type === 'overlay'
+      ? join(projectRoot, '.floyd', 'sandbox', id)
+      : join(this.options.sandboxRoot, id);
     await mkdir(sandboxRoot, { recursive: true });
 
-    // Copy project to sandbox (excluding patterns)
-    await this.copyProjectToSandbox(projectRoot, sandboxRoot);
+    // Copy project to sandbox (excluding patterns); overlays copy on write
+    if (type !== 'overlay') {
+      await this.copyProjectToSandbox(projectRoot, sandboxRoot);
+    }
 
     // Create session
     this.session = {
       id,
-      type: this.options.type,
+      type,
       projectRoot,
       sandboxRoot,
       state: 'active',
@@ -202,7 +216,30 @@ export class SandboxManager {
       return realPath;
     }
 
-    return join(this.session.sandboxRoot, relativePath);
+    const sandboxPath = join(this.session.sandboxRoot, relativePath);
+    if (this.session.type === 'overlay') {
+      this.copyOnWrite(realPath, sandboxPath);
+    }
+    return sandboxPath;
+  }
+
+  /**
+   * Path a read should use: the overlay copy once a file has been written
+   * (or deleted) in an overlay sandbox, otherwise the real path
+   */
+  resolveReadPath(realPath: string): string {
+    if (!this.session || this.session.state !== 'active' || this.session.type !== 'overlay') {
+      return realPath;
+    }
+
+    const relativePath = relative(this.session.projectRoot, realPath);
+    if (relativePath.startsWith('..') || relativePath.startsWith('/')) {
+      return realPath;
+    }
+
+    const sandboxPath = join(this.session.sandboxRoot, relativePath);
+    const deleted = this.session.changes.some(c => c.sandboxPath === sandboxPath && c.changeType === 'deleted');
+    return deleted || this.isFile(sandboxPath) ? sandboxPath : realPath;
   }
 
   /**
@@ -236,6 +273,13 @@ export class SandboxManager {
 
     const originalPath = this.translateToReal(sandboxPath);
 
+    // Overlays know the real outcome from what is on disk
+    if (this.session.type === 'overlay') {
+      changeType = !existsSync(sandboxPath) ? 'deleted'
+        : existsSync(originalPath) ? 'modified'
+        : 'created';
+    }
+
     // Check if change already tracked
     const existingIndex = this.session.changes.findIndex(
       c => c.sandboxPath === sandboxPath
@@ -295,6 +339,9 @@ export class SandboxManager {
     const committed: string[] = [];
     const failed: string[] = [];
 
+    // Keep a reviewable record of what overlays applied
+    const patchPath = this.session.type === 'overlay' ? await this.writePatch() ?? undefined : undefined;
+
     for (const change of this.session.changes) {
       try {
         switch (change.changeType) {
@@ -331,9 +378,62 @@ export class SandboxManager {
     logger.info('Sandbox committed', {
       committed: committed.length,
       failed: failed.length,
+      patchPath,
     });
 
-    return { committed, failed, success };
+    return { committed, failed, success, patchPath };
+  }
+
+  /**
+   * Unified diff of every tracked change against the real project
+   */
+  async createPatch(): Promise<string> {
+    if (!this.session) {
+      return '';
+    }
+
+    const patches: string[] = [];
+    for (const change of this.session.changes) {
+      const file = relative(this.session.projectRoot, change.originalPath);
+      const original = await readFile(change.originalPath, 'utf-8').catch(() => null);
+      const modified = change.changeType === 'deleted'
+        ? null
+        : await readFile(change.sandboxPath, 'utf-8').catch(() => null);
+
+      if (original === modified) {
+        continue;
+      }
+
+      patches.push(createTwoFilesPatch(
+        original === null ? '/dev/null' : `a/${file}`,
+        modified === null ? '/dev/null' : `b/${file}`,
+        original ?? '',
+        modified ?? ''
+      ));
+    }
+
+    return patches.join('');
+  }
+
+  /**
+   * Write the patch to .floyd/sandbox/<id>.patch
+   *
+   * @returns The patch path, or null when there is nothing to write
+   */
+  async writePatch(): Promise<string | null> {
+    if (!this.session) {
+      return null;
+    }
+
+    const patch = await this.createPatch();
+    if (!patch) {
+      return null;
+    }
+
+    const patchPath = join(this.session.projectRoot, '.floyd', 'sandbox', `${this.session.id}.patch`);
+    await mkdir(dirname(patchPath), { recursive: true });
+    await writeFile(patchPath, patch, 'utf-8');
+    return patchPath;
   }
 
   /**
@@ -448,6 +548,27 @@ export class SandboxManager {
     });
   }
 
+  /**
+   * Copy a real file into the overlay before its first write
+   */
+  private copyOnWrite(realPath: string, sandboxPath: string): void {
+    const deleted = this.session?.changes.some(c => c.sandboxPath === sandboxPath && c.changeType === 'deleted');
+    if (deleted || existsSync(sandboxPath) || !this.isFile(realPath)) {
+      return;
+    }
+
+    mkdirSync(dirname(sandboxPath), { recursive: true });
+    copyFileSync(realPath, sandboxPath);
+  }
+
+  private isFile(filePath: string): boolean {
+    try {
+      return statSync(filePath).isFile();
+    } catch {
+      return false;
+    }
+  }
+
   /**
    * Copy a single file
    */
@@ -0,0 +1,176 @@
+/**
+ * Audit Log - Floyd Wrapper
+ *
+ * Append-only record of what the agent did on this machine, for teams that
+ * need to review autonomous runs: every request sent to the API (model,
+ * tools and the new messages, with secrets redacted), every tool execution
+ * with its input and outcome, and every file modification with SHA-256
+ * hashes of the contents before and after.
+ *
+ * Enabled with FLOYD_AUDIT_LOG=1; written to .floyd/audit.jsonl, one JSON
+ * object per line. Lines are only ever appended, one write per entry, so an
+ * interrupted run keeps everything up to the interruption.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { logger } from '../utils/logger.js';
+import { redactSecrets } from '../utils/redaction.js';
+import type { BroadcastEvent, EventBroadcaster } from './event-broadcaster.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One line of the audit log
+ */
+export interface AuditEntry {
+  time: string;
+  /** Start of the run the entry belongs to (ISO time), null outside a run */
+  run: string | null;
+  type: 'run_start' | 'request' | 'tool_start' | 'tool_complete' | 'file_change' | 'run_complete';
+  [key: string]: unknown;
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Location of the audit log
+ */
+export function getAuditLogPath(projectRoot: string = process.cwd()): string {
+  return path.join(projectRoot, '.floyd', 'audit.jsonl');
+}
+
+/**
+ * Whether FLOYD_AUDIT_LOG asks for the audit log
+ */
+export function isAuditLogEnabled(): boolean {
+  return ['1', 'true', 'yes'].includes((process.env.FLOYD_AUDIT_LOG || '').toLowerCase());
+}
+
+/**
+ * A value with the secrets in its strings replaced by placeholders
+ */
+function redact<T>(value: T): T {
+  if (value === undefined) {
+    return value;
+  }
+  return JSON.parse(redactSecrets(JSON.stringify(value)).text) as T;
+}
+
+/**
+ * The audit entry for a broadcast event, or null for events that are not
+ * audited (tokens, thinking, UI updates)
+ */
+export function toAuditEntry(event: BroadcastEvent, run: string | null): AuditEntry | null {
+  const base = { time: new Date(event.timestamp).toISOString(), run };
+  const { data } = event;
+
+  switch (event.type) {
+    case 'run_start':
+      return { ...base, type: 'run_start', message: redact(data.message) };
+    case 'request':
+      return {
+        ...base,
+        type: 'request',
+        turn: data.turn,
+        model: data.model,
+        tools: data.tools,
+        // Messages already in an earlier request of the run are not repeated
+        previousMessages: data.previousMessages,
+        messages: redact(data.messages),
+      };
+    case 'tool_start':
+      return { ...base, type: 'tool_start', tool: data.tool, input: redact(data.input) };
+    case 'tool_complete': {
+      const result = data.result as { success?: boolean; error?: { code?: string; message?: string } } | undefined;
+      return {
+        ...base,
+        type: 'tool_complete',
+        tool: data.tool,
+        success: Boolean(result?.success),
+        ...(result?.error ? { error: redact(result.error.message ?? result.error.code) } : {}),
+      };
+    }
+    case 'file_change':
+      return {
+        ...base,
+        type: 'file_change',
+        tool: data.tool,
+        path: data.path,
+        sha256Before: data.sha256Before,
+        sha256After: data.sha256After,
+      };
+    case 'run_complete':
+      return { ...base, type: 'run_complete', aborted: data.aborted, turns: data.turns, tokens: data.tokenCount };
+    default:
+      return null;
+  }
+}
+
+// ============================================================================
+// Audit Log
+// ============================================================================
+
+/**
+ * Appends the audited events seen by an event broadcaster to the audit log
+ */
+export class AuditLog {
+  private unsubscribe: (() => void) | null = null;
+  private run: string | null = null;
+  private failed = false;
+
+  constructor(private file: string = getAuditLogPath()) {}
+
+  /**
+   * Start auditing the runs that go through a broadcaster
+   */
+  attach(broadcaster: EventBroadcaster): void {
+    if (this.unsubscribe) {
+      return;
+    }
+    fs.ensureDirSync(path.dirname(this.file));
+    this.unsubscribe = broadcaster.addListener(event => this.record(event));
+  }
+
+  /**
+   * Stop auditing
+   */
+  detach(): void {
+    this.unsubscribe?.();
+    this.unsubscribe = null;
+  }
+
+  /**
+   * File entries are appended to
+   */
+  getPath(): string {
+    return this.file;
+  }
+
+  private record(event: BroadcastEvent): void {
+    if (event.type === 'run_start') {
+      this.run = new Date(event.timestamp).toISOString();
+    }
+    const entry = toAuditEntry(event, this.run);
+    if (event.type === 'run_complete') {
+      this.run = null;
+    }
+    if (!entry) {
+      return;
+    }
+
+    try {
+      fs.appendFileSync(this.file, `${JSON.stringify(entry)}\n`);
+    } catch (error) {
+      // Warn once; a full disk should not stop the run
+      if (!this.failed) {
+        this.failed = true;
+        logger.warn('Writing the audit log failed', { path: this.file, error: (error as Error).message });
+      }
+    }
+  }
+}
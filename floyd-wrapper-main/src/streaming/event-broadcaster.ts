@@ -0,0 +1,290 @@
+/**
+ * Event Broadcaster - Floyd Wrapper
+ *
+ * Optional WebSocket endpoint that mirrors engine events (iterations, tokens,
+ * tool executions, token usage) so external dashboards can follow long
+ * autonomous runs in real time.
+ *
+ * Enabled by setting FLOYD_EVENTS_PORT. Binds to FLOYD_EVENTS_HOST
+ * (default 127.0.0.1). Each message is a JSON-encoded BroadcastEvent.
+ *
+ * The stream is read-only: messages from clients are ignored. Clients that
+ * connect mid-run are first sent a session_info event and a replay of the
+ * current run, which is what `floyd watch` uses for pair-programming.
+ *
+ * In-process listeners (the run recorder) receive every event whether or
+ * not the WebSocket endpoint is enabled.
+ */
+
+import { WebSocketServer, WebSocket } from 'ws';
+import { logger } from '../utils/logger.js';
+import { redactSerialized } from '../utils/redaction.js';
+import type { EngineCallbacks } from '../agent/execution-engine.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Event types sent to dashboard clients
+ */
+export type BroadcastEventType =
+  | 'run_start'
+  | 'run_complete'
+  | 'iteration'
+  | 'request'
+  | 'token'
+  | 'thinking_start'
+  | 'thinking_complete'
+  | 'tool_start'
+  | 'tool_complete'
+  | 'tool_output'
+  | 'usage'
+  | 'checkpoint'
+  | 'mode_adapt'
+  | 'change_summary'
+  | 'file_change'
+  | 'diff_preview'
+  | 'post_mortem'
+  | 'budget_exceeded'
+  | 'context_updated'
+  | 'session_info';
+
+/**
+ * Event envelope sent over the WebSocket
+ */
+export interface BroadcastEvent {
+  type: BroadcastEventType;
+  timestamp: number;
+  data: Record<string, unknown>;
+}
+
+/**
+ * In-process receiver of broadcast events
+ */
+export type BroadcastListener = (event: BroadcastEvent) => void;
+
+/**
+ * Maximum events kept for replay to late-joining clients
+ */
+const MAX_REPLAY_EVENTS = 5000;
+
+// ============================================================================
+// Event Broadcaster Class
+// ============================================================================
+
+/**
+ * Broadcasts engine events to connected WebSocket clients
+ */
+export class EventBroadcaster {
+  private wss: WebSocketServer | null = null;
+  private url: string | null = null;
+
+  /** Events of the current run, replayed to clients that join late */
+  private replay: string[] = [];
+
+  private listeners = new Set<BroadcastListener>();
+
+  /**
+   * Start listening for dashboard clients
+   */
+  start(port: number, host = '127.0.0.1'): void {
+    if (this.wss) {
+      return;
+    }
+
+    this.wss = new WebSocketServer({ port, host });
+
+    this.wss.on('listening', () => {
+      // Report the bound port (port 0 picks a free one)
+      const address = this.wss?.address();
+      this.url = `ws://${host}:${typeof address === 'object' && address ? address.port : port}`;
+      logger.info(`Event stream listening on ${this.url}`);
+    });
+
+    this.wss.on('connection', (socket) => {
+      logger.debug('Event stream client connected', { clients: this.wss?.clients.size });
+      socket.on('error', (error) => logger.debug('Event stream client error', { error }));
+
+      // Viewers observe only; nothing they send reaches the session
+      socket.on('message', () => logger.debug('Ignoring message from read-only event stream client'));
+
+      socket.send(this.serialize('session_info', {
+        pid: process.pid,
+        cwd: process.cwd(),
+        mode: process.env.FLOYD_MODE || 'ask',
+        readOnly: true,
+      }));
+      for (const payload of this.replay) {
+        socket.send(payload);
+      }
+    });
+
+    this.wss.on('error', (error) => {
+      logger.warn('Event stream server error', { error: error.message });
+    });
+  }
+
+  /**
+   * Stop the server and disconnect all clients
+   */
+  async stop(): Promise<void> {
+    const wss = this.wss;
+    if (!wss) {
+      return;
+    }
+    this.wss = null;
+    this.url = null;
+    this.replay = [];
+
+    for (const client of wss.clients) {
+      client.terminate();
+    }
+    await new Promise<void>(resolve => wss.close(() => resolve()));
+  }
+
+  /**
+   * Whether the server is running
+   */
+  isActive(): boolean {
+    return this.wss !== null;
+  }
+
+  /**
+   * WebSocket URL clients can attach to, or null when not listening
+   */
+  getUrl(): string | null {
+    return this.url;
+  }
+
+  /**
+   * Receive every event in-process
+   *
+   * @returns Function that removes the listener
+   */
+  addListener(listener: BroadcastListener): () => void {
+    this.listeners.add(listener);
+    return () => this.listeners.delete(listener);
+  }
+
+  /**
+   * Whether anything receives events (clients may connect while listening)
+   */
+  hasReceivers(): boolean {
+    return this.wss !== null || this.listeners.size > 0;
+  }
+
+  /**
+   * Send an event to every listener and connected client
+   */
+  emit(type: BroadcastEventType, data: Record<string, unknown> = {}): void {
+    if (this.listeners.size > 0) {
+      const event: BroadcastEvent = { type, timestamp: Date.now(), data };
+      for (const listener of this.listeners) {
+        try {
+          listener(event);
+        } catch (error) {
+          logger.debug('Event listener failed', { type, error });
+        }
+      }
+    }
+
+    if (!this.wss) {
+      return;
+    }
+
+    const payload = this.serialize(type, data);
+
+    // Keep the current run for viewers that attach later
+    if (type === 'run_start') {
+      this.replay = [];
+    }
+    this.replay.push(payload);
+    if (this.replay.length > MAX_REPLAY_EVENTS) {
+      this.replay.shift();
+    }
+
+    for (const client of this.wss.clients) {
+      if (client.readyState === WebSocket.OPEN) {
+        client.send(payload);
+      }
+    }
+  }
+
+  /**
+   * Encode an event for the wire, secrets redacted
+   */
+  private serialize(type: BroadcastEventType, data: Record<string, unknown>): string {
+    try {
+      return redactSerialized(JSON.stringify({ type, timestamp: Date.now(), data } satisfies BroadcastEvent));
+    } catch {
+      return JSON.stringify({ type, timestamp: Date.now(), data: { unserializable: true } });
+    }
+  }
+
+  /**
+   * Wrap engine callbacks so every callback is also broadcast
+   */
+  wrapCallbacks(callbacks: EngineCallbacks): EngineCallbacks {
+    return {
+      ...callbacks,
+      onToken: (token) => {
+        this.emit('token', { token });
+        callbacks.onToken?.(token);
+      },
+      onToolStart: (tool, input) => {
+        this.emit('tool_start', { tool, input });
+        callbacks.onToolStart?.(tool, input);
+      },
+      onToolComplete: (tool, result) => {
+        this.emit('tool_complete', { tool, result });
+        callbacks.onToolComplete?.(tool, result);
+      },
+      onThinkingStart: () => {
+        this.emit('thinking_start');
+        callbacks.onThinkingStart?.();
+      },
+      onThinkingComplete: () => {
+        this.emit('thinking_complete');
+        callbacks.onThinkingComplete?.();
+      },
+      onCheckpointCreated: (checkpointId, fileCount, toolName) => {
+        this.emit('checkpoint', { checkpointId, fileCount, toolName });
+        callbacks.onCheckpointCreated?.(checkpointId, fileCount, toolName);
+      },
+      onModeAdapt: (fromMode, toMode, toolName) => {
+        this.emit('mode_adapt', { fromMode, toMode, toolName });
+        callbacks.onModeAdapt?.(fromMode, toMode, toolName);
+      },
+      onChangeSummary: (summary, changes) => {
+        this.emit('change_summary', { summary, changes });
+        callbacks.onChangeSummary?.(summary, changes);
+      },
+      onPostMortem: (scratchpadPath) => {
+        this.emit('post_mortem', { scratchpadPath });
+        callbacks.onPostMortem?.(scratchpadPath);
+      },
+    };
+  }
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultEventBroadcaster: EventBroadcaster | null = null;
+
+/**
+ * Get the shared event broadcaster, starting it if FLOYD_EVENTS_PORT is set
+ */
+export function getEventBroadcaster(): EventBroadcaster {
+  if (!defaultEventBroadcaster) {
+    defaultEventBroadcaster = new EventBroadcaster();
+
+    const port = parseInt(process.env.FLOYD_EVENTS_PORT || '', 10);
+    if (!isNaN(port) && port > 0) {
+      defaultEventBroadcaster.start(port, process.env.FLOYD_EVENTS_HOST || '127.0.0.1');
+    }
+  }
+  return defaultEventBroadcaster;
+}
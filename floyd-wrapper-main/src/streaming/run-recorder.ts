@@ -0,0 +1,219 @@
+/**
+ * Run Recorder - Floyd Wrapper
+ *
+ * Records every engine event of a run (requests, tokens, tool calls and
+ * their results) to .floyd/runs/<id>.jsonl, one BroadcastEvent per line,
+ * and replays a recording in the terminal without calling the API. Secrets
+ * are redacted before anything is written.
+ *
+ * Recording is enabled with `floyd --record` (or FLOYD_RECORD_RUNS=1);
+ * `floyd --replay [id|file]` plays a run back, the latest by default.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { logger } from '../utils/logger.js';
+import { redactSerialized } from '../utils/redaction.js';
+import { formatViewerEvent } from './session-viewer.js';
+import type { BroadcastEvent, EventBroadcaster } from './event-broadcaster.js';
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Longest pause between replayed events, so idle gaps don't stall playback
+ */
+const MAX_REPLAY_GAP_MS = 2000;
+
+/**
+ * Directory recordings are written to
+ */
+export function getRunsDir(projectRoot: string = process.cwd()): string {
+  return path.join(projectRoot, '.floyd', 'runs');
+}
+
+/**
+ * Whether FLOYD_RECORD_RUNS asks for every run to be recorded
+ */
+export function isRecordingEnabled(): boolean {
+  return ['1', 'true', 'yes'].includes((process.env.FLOYD_RECORD_RUNS || '').toLowerCase());
+}
+
+// ============================================================================
+// Recorder
+// ============================================================================
+
+/**
+ * Writes each run seen by an event broadcaster to its own file
+ */
+export class RunRecorder {
+  private unsubscribe: (() => void) | null = null;
+  private stream: fs.WriteStream | null = null;
+  private currentPath: string | null = null;
+  private recorded: string[] = [];
+
+  constructor(private runsDir: string = getRunsDir()) {}
+
+  /**
+   * Start recording the runs that go through a broadcaster
+   */
+  attach(broadcaster: EventBroadcaster): void {
+    if (this.unsubscribe) {
+      return;
+    }
+    fs.ensureDirSync(this.runsDir);
+    this.unsubscribe = broadcaster.addListener(event => this.record(event));
+  }
+
+  /**
+   * Stop recording and close the current file
+   */
+  async detach(): Promise<void> {
+    this.unsubscribe?.();
+    this.unsubscribe = null;
+    await this.closeRun();
+  }
+
+  /**
+   * File of the run being recorded, if any
+   */
+  getCurrentPath(): string | null {
+    return this.currentPath;
+  }
+
+  /**
+   * Files written since the recorder was attached
+   */
+  getRecorded(): string[] {
+    return [...this.recorded];
+  }
+
+  private record(event: BroadcastEvent): void {
+    if (event.type === 'run_start') {
+      void this.closeRun();
+      this.openRun(event.timestamp);
+    }
+    if (!this.stream) {
+      return;
+    }
+
+    try {
+      this.stream.write(`${redactSerialized(JSON.stringify(event))}\n`);
+    } catch {
+      this.stream.write(`${JSON.stringify({ ...event, data: { unserializable: true } })}\n`);
+    }
+
+    if (event.type === 'run_complete') {
+      void this.closeRun();
+    }
+  }
+
+  private openRun(startedAt: number): void {
+    const id = new Date(startedAt).toISOString().replace(/[:.]/g, '-');
+    this.currentPath = path.join(this.runsDir, `${id}.jsonl`);
+    this.recorded.push(this.currentPath);
+    this.stream = fs.createWriteStream(this.currentPath, { flags: 'a' });
+    this.stream.on('error', (error) => {
+      logger.warn('Run recording failed', { path: this.currentPath, error: error.message });
+      this.stream = null;
+    });
+    logger.debug('Recording run', { path: this.currentPath });
+  }
+
+  private closeRun(): Promise<void> {
+    const stream = this.stream;
+    this.stream = null;
+    this.currentPath = null;
+    if (!stream) {
+      return Promise.resolve();
+    }
+    return new Promise(resolve => stream.end(() => resolve()));
+  }
+}
+
+// ============================================================================
+// Replay
+// ============================================================================
+
+/**
+ * Recordings in the runs directory, newest first
+ */
+export async function listRecordings(runsDir: string = getRunsDir()): Promise<string[]> {
+  if (!(await fs.pathExists(runsDir))) {
+    return [];
+  }
+  const files = (await fs.readdir(runsDir)).filter(file => file.endsWith('.jsonl'));
+  return files.sort().reverse().map(file => path.join(runsDir, file));
+}
+
+/**
+ * Find a recording by path or id; without one, the latest recording
+ */
+export async function resolveRecording(idOrPath?: string, runsDir: string = getRunsDir()): Promise<string | null> {
+  if (!idOrPath) {
+    return (await listRecordings(runsDir))[0] ?? null;
+  }
+  const candidates = [
+    idOrPath,
+    path.join(runsDir, idOrPath),
+    path.join(runsDir, `${idOrPath}.jsonl`),
+  ];
+  for (const candidate of candidates) {
+    if (await fs.pathExists(candidate) && (await fs.stat(candidate)).isFile()) {
+      return candidate;
+    }
+  }
+  return null;
+}
+
+/**
+ * Read a recording, skipping lines that aren't events
+ */
+export async function readRecording(file: string): Promise<BroadcastEvent[]> {
+  const lines = (await fs.readFile(file, 'utf-8')).split('\n');
+  const events: BroadcastEvent[] = [];
+  for (const line of lines) {
+    if (!line.trim()) {
+      continue;
+    }
+    try {
+      const event = JSON.parse(line) as BroadcastEvent;
+      if (event && typeof event.type === 'string') {
+        events.push({ ...event, data: event.data ?? {} });
+      }
+    } catch {
+      // Skip a truncated last line from an interrupted run
+    }
+  }
+  return events;
+}
+
+/**
+ * Render recorded events as `floyd watch` would have shown them live
+ *
+ * @param speed - Playback speed; 0 renders everything at once
+ */
+export async function replayRecording(
+  events: BroadcastEvent[],
+  options: { output?: NodeJS.WritableStream; speed?: number } = {}
+): Promise<void> {
+  const output = options.output ?? process.stdout;
+  const speed = options.speed ?? 1;
+  let previous: number | undefined;
+
+  for (const event of events) {
+    if (speed > 0 && previous !== undefined) {
+      const gap = Math.min(Math.max(event.timestamp - previous, 0), MAX_REPLAY_GAP_MS) / speed;
+      if (gap >= 1) {
+        await new Promise(resolve => setTimeout(resolve, gap));
+      }
+    }
+    previous = event.timestamp;
+
+    const text = formatViewerEvent(event);
+    if (text) {
+      output.write(text);
+    }
+  }
+}
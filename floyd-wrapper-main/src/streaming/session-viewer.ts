@@ -0,0 +1,110 @@
+/**
+ * Session Viewer - Floyd Wrapper
+ *
+ * Read-only client for the event stream, used by `floyd watch` so a
+ * teammate can follow a live session from a second terminal. Tokens and
+ * tool activity are rendered as they arrive; the viewer never reads stdin
+ * and the stream ignores anything clients send.
+ */
+
+import chalk from 'chalk';
+import { WebSocket } from 'ws';
+import { CRUSH_THEME } from '../constants.js';
+import type { BroadcastEvent } from './event-broadcaster.js';
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+/**
+ * Shorten a value for a one-line summary
+ */
+function summarize(value: unknown, max = 80): string {
+  const text = typeof value === 'string' ? value : JSON.stringify(value) ?? '';
+  const line = text.replace(/\s+/g, ' ').trim();
+  return line.length > max ? `${line.slice(0, max - 1)}…` : line;
+}
+
+/**
+ * Render an event for the viewer terminal
+ *
+ * @returns Text to write (tokens are not newline-terminated), or null to skip
+ */
+export function formatViewerEvent(event: BroadcastEvent): string | null {
+  const { data } = event;
+  const muted = chalk.hex(CRUSH_THEME.colors.muted);
+
+  switch (event.type) {
+    case 'session_info':
+      return muted(`Watching FLOYD (pid ${data.pid}, ${data.mode} mode) in ${data.cwd} — read-only, Ctrl+C to detach\n`);
+    case 'run_start':
+      return `\n${chalk.hex(CRUSH_THEME.colors.info).bold('❯ ')}${chalk.bold(String(data.message ?? ''))}\n\n`;
+    case 'token':
+      return String(data.token ?? '');
+    case 'thinking_start':
+      return muted('\n[thinking…]\n');
+    case 'tool_start':
+      return `\n${chalk.hex(CRUSH_THEME.colors.warning)('⚙ ')}${data.tool} ${muted(summarize(data.input))}\n`;
+    case 'tool_complete': {
+      const result = data.result as { success?: boolean; error?: { message?: string } } | undefined;
+      if (result?.success === false) {
+        return `${chalk.hex(CRUSH_THEME.colors.error)('✗ ')}${data.tool} ${muted(summarize(result.error?.message))}\n`;
+      }
+      return `${chalk.hex(CRUSH_THEME.colors.success)('✓ ')}${data.tool}\n`;
+    }
+    case 'diff_preview':
+      return muted(`\n[awaiting review of ${data.filePath}]\n`);
+    case 'checkpoint':
+      return muted(`[checkpoint ${data.checkpointId}]\n`);
+    case 'mode_adapt':
+      return muted(`[mode ${data.fromMode} → ${data.toMode}]\n`);
+    case 'change_summary':
+      return `\n${muted(String(data.summary ?? ''))}\n`;
+    case 'run_complete':
+      return muted(`\n\n[run ${data.aborted ? 'interrupted' : 'complete'} after ${data.turns} turns]\n`);
+    default:
+      return null;
+  }
+}
+
+// ============================================================================
+// Viewer
+// ============================================================================
+
+/**
+ * Attach to a session's event stream and render it until the stream closes
+ */
+export function watchSession(url: string, output: NodeJS.WritableStream = process.stdout): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const socket = new WebSocket(url);
+    let connected = false;
+
+    socket.on('open', () => {
+      connected = true;
+    });
+
+    socket.on('message', (raw) => {
+      try {
+        const text = formatViewerEvent(JSON.parse(raw.toString()) as BroadcastEvent);
+        if (text) {
+          output.write(text);
+        }
+      } catch {
+        // Skip malformed events
+      }
+    });
+
+    socket.on('close', () => {
+      if (connected) {
+        output.write(chalk.hex(CRUSH_THEME.colors.muted)('\n[session ended]\n'));
+      }
+      resolve();
+    });
+
+    socket.on('error', (error) => {
+      if (!connected) {
+        reject(error);
+      }
+    });
+  });
+}
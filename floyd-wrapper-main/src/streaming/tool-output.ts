@@ -0,0 +1,225 @@
+/**
+ * Tool Output Streaming - Floyd Wrapper
+ *
+ * Long-running tools (the `run` tool) stream their output to the UI while
+ * they execute. Output is line-buffered and rate-limited with a token bucket,
+ * so a command printing tens of thousands of lines shows a readable sample
+ * plus a live line counter ("… 1,245 lines") instead of flooding the
+ * terminal or the event stream.
+ *
+ * What the model sees is separate: budgetToolOutput() keeps the head and
+ * tail of the full output within FLOYD_TOOL_OUTPUT_MAX_CHARS.
+ */
+
+import { getEventBroadcaster } from './event-broadcaster.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A batch of output lines delivered to the UI
+ */
+export interface ToolOutputBatch {
+  tool: string;
+  /** Lines to display (a sample when output is faster than the rate limit) */
+  lines: string[];
+  /** Complete lines seen so far */
+  totalLines: number;
+  /** Lines not displayed because of the rate limit */
+  skippedLines: number;
+  /** Whether the tool has finished writing */
+  done: boolean;
+}
+
+/**
+ * Receives output batches (set by the CLI)
+ */
+export type ToolOutputHandler = (batch: ToolOutputBatch) => void;
+
+/**
+ * Rate limiting settings
+ */
+export interface ToolOutputStreamOptions {
+  /** Lines added to the bucket per second */
+  linesPerSecond?: number;
+  /** Bucket capacity (largest burst shown at once) */
+  burst?: number;
+  /** Delay between batches (ms) */
+  intervalMs?: number;
+}
+
+const DEFAULT_MAX_CHARS = 30_000;
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Live counter text, e.g. "… 1,245 lines"
+ */
+export function formatLineCount(lines: number): string {
+  return `… ${lines.toLocaleString('en-US')} line${lines === 1 ? '' : 's'}`;
+}
+
+/**
+ * Fit output into a character budget, keeping the head and the tail
+ *
+ * The tail gets the larger share since errors and summaries usually come last.
+ */
+export function budgetToolOutput(
+  text: string,
+  maxChars: number = parseInt(process.env.FLOYD_TOOL_OUTPUT_MAX_CHARS || '', 10) || DEFAULT_MAX_CHARS
+): string {
+  if (text.length <= maxChars) {
+    return text;
+  }
+
+  const headBudget = Math.floor(maxChars * 0.4);
+  const tailBudget = maxChars - headBudget;
+
+  // Cut on line boundaries where possible
+  let head = text.slice(0, headBudget);
+  const headBreak = head.lastIndexOf('\n');
+  if (headBreak > 0) {
+    head = head.slice(0, headBreak);
+  }
+  let tail = text.slice(text.length - tailBudget);
+  const tailBreak = tail.indexOf('\n');
+  if (tailBreak >= 0 && tailBreak < tail.length - 1) {
+    tail = tail.slice(tailBreak + 1);
+  }
+
+  const omitted = text.slice(head.length, text.length - tail.length);
+  const omittedLines = omitted.split('\n').length - 1;
+  return `${head}\n… [${omittedLines.toLocaleString('en-US')} lines, ${omitted.length.toLocaleString('en-US')} chars omitted] …\n${tail}`;
+}
+
+// ============================================================================
+// Tool Output Stream Class
+// ============================================================================
+
+/**
+ * Line-buffered, token-bucket rate-limited output stream for one tool call
+ */
+export class ToolOutputStream {
+  private readonly tool: string;
+  private readonly onBatch: ToolOutputHandler;
+  private readonly linesPerSecond: number;
+  private readonly burst: number;
+  private readonly intervalMs: number;
+
+  private partial = '';
+  private pending: string[] = [];
+  private tokens: number;
+  private lastRefill = Date.now();
+  private totalLines = 0;
+  private skippedLines = 0;
+  private timer: NodeJS.Timeout | null = null;
+  private ended = false;
+
+  constructor(tool: string, onBatch: ToolOutputHandler, options: ToolOutputStreamOptions = {}) {
+    this.tool = tool;
+    this.onBatch = onBatch;
+    this.linesPerSecond = options.linesPerSecond ?? 50;
+    this.burst = options.burst ?? 20;
+    this.intervalMs = options.intervalMs ?? 100;
+    this.tokens = this.burst;
+  }
+
+  /**
+   * Add a chunk of output; complete lines are queued for the next batch
+   */
+  write(chunk: string): void {
+    if (this.ended || chunk.length === 0) {
+      return;
+    }
+
+    const parts = (this.partial + chunk).split(/\r?\n/);
+    this.partial = parts.pop() ?? '';
+    this.totalLines += parts.length;
+    this.pending.push(...parts);
+
+    // Only the newest lines can still be shown; the rest just count
+    if (this.pending.length > this.burst) {
+      this.skippedLines += this.pending.length - this.burst;
+      this.pending = this.pending.slice(-this.burst);
+    }
+
+    if (!this.timer && parts.length > 0) {
+      this.timer = setTimeout(() => this.flush(false), this.intervalMs);
+      this.timer.unref();
+    }
+  }
+
+  /**
+   * Flush what is left and send the final batch
+   */
+  end(): void {
+    if (this.ended) {
+      return;
+    }
+    if (this.partial) {
+      this.pending.push(this.partial);
+      this.totalLines++;
+      this.partial = '';
+    }
+    this.flush(true);
+    this.ended = true;
+  }
+
+  /**
+   * Lines written so far (including an unterminated last line)
+   */
+  getTotalLines(): number {
+    return this.totalLines + (this.partial ? 1 : 0);
+  }
+
+  /**
+   * Send queued lines allowed by the bucket; the rest are counted as skipped
+   */
+  private flush(done: boolean): void {
+    if (this.timer) {
+      clearTimeout(this.timer);
+      this.timer = null;
+    }
+
+    const now = Date.now();
+    this.tokens = Math.min(this.burst, this.tokens + ((now - this.lastRefill) / 1000) * this.linesPerSecond);
+    this.lastRefill = now;
+
+    const allowed = Math.min(this.pending.length, Math.floor(this.tokens));
+    const lines = allowed > 0 ? this.pending.slice(-allowed) : [];
+    this.skippedLines += this.pending.length - lines.length;
+    this.tokens -= lines.length;
+    this.pending = [];
+
+    // Sent even without lines so the counter keeps moving
+    this.onBatch({ tool: this.tool, lines, totalLines: this.totalLines, skippedLines: this.skippedLines, done });
+  }
+}
+
+// ============================================================================
+// Output Handler Registration
+// ============================================================================
+
+let outputHandler: ToolOutputHandler | null = null;
+
+/**
+ * Register the UI handler for streamed tool output (set by the CLI)
+ */
+export function setToolOutputHandler(handler: ToolOutputHandler | null): void {
+  outputHandler = handler;
+}
+
+/**
+ * Create an output stream for a tool call
+ *
+ * Batches go to the registered UI handler and to event stream clients.
+ */
+export function createToolOutputStream(tool: string, options?: ToolOutputStreamOptions): ToolOutputStream {
+  return new ToolOutputStream(tool, (batch) => {
+    outputHandler?.(batch);
+    getEventBroadcaster().emit('tool_output', { ...batch });
+  }, options);
+}
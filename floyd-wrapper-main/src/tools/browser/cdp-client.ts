@@ -0,0 +1,448 @@
+/**
+ * CDP Client - Floyd Wrapper
+ *
+ * Drives Chrome directly over the Chrome DevTools Protocol, for when the
+ * FloydChrome extension is not running. Chrome must be started with a
+ * debugging port, e.g. `google-chrome --remote-debugging-port=9222`
+ * (FLOYD_CDP_URL overrides http://localhost:9222).
+ *
+ * Tabs are page targets, numbered in the order browser_get_tabs lists them.
+ * Network activity of each attached tab is kept in a short ring buffer.
+ */
+
+import { WebSocket } from 'ws';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A page target from /json/list
+ */
+export interface CdpTarget {
+  id: string;
+  type: string;
+  title: string;
+  url: string;
+  webSocketDebuggerUrl?: string;
+}
+
+/**
+ * One request seen on an attached tab
+ */
+export interface NetworkEntry {
+  requestId: string;
+  method: string;
+  url: string;
+  resourceType?: string;
+  status?: number;
+  mimeType?: string;
+  failed?: string;
+  startedAt: number;
+  durationMs?: number;
+}
+
+interface CdpMessage {
+  id?: number;
+  method?: string;
+  params?: any;
+  result?: any;
+  error?: { code: number; message: string };
+}
+
+/**
+ * Requests kept per tab
+ */
+const NETWORK_LOG_SIZE = 200;
+
+/**
+ * Time allowed for one protocol command
+ */
+const COMMAND_TIMEOUT_MS = 30000;
+
+/**
+ * Time allowed for a page load after navigating
+ */
+const LOAD_TIMEOUT_MS = 15000;
+
+/**
+ * Collects the page text and interactive elements with selectors to use them by
+ */
+const READ_PAGE_SCRIPT = `(() => {
+  const selectorFor = (el) => {
+    if (el.id) return '#' + CSS.escape(el.id);
+    const name = el.getAttribute('name');
+    if (name) return el.tagName.toLowerCase() + '[name="' + name + '"]';
+    const parts = [];
+    for (let node = el; node && node.nodeType === 1 && parts.length < 4; node = node.parentElement) {
+      const siblings = node.parentElement ? [...node.parentElement.children].filter(s => s.tagName === node.tagName) : [];
+      parts.unshift(node.tagName.toLowerCase() + (siblings.length > 1 ? ':nth-of-type(' + (siblings.indexOf(node) + 1) + ')' : ''));
+      if (node.id) { parts[0] = '#' + CSS.escape(node.id); break; }
+    }
+    return parts.join(' > ');
+  };
+  const visible = (el) => { const r = el.getBoundingClientRect(); return r.width > 0 && r.height > 0; };
+  const elements = [...document.querySelectorAll('a[href], button, input, textarea, select, [role="button"], [role="link"], [contenteditable="true"]')]
+    .filter(visible)
+    .slice(0, 150)
+    .map(el => ({
+      tag: el.tagName.toLowerCase(),
+      role: el.getAttribute('role') || undefined,
+      text: (el.innerText || el.value || el.getAttribute('aria-label') || el.getAttribute('placeholder') || '').trim().slice(0, 80),
+      href: el.href || undefined,
+      selector: selectorFor(el),
+    }));
+  return {
+    url: location.href,
+    title: document.title,
+    text: (document.body ? document.body.innerText : '').replace(/\\n{3,}/g, '\\n\\n').slice(0, 20000),
+    elements,
+  };
+})()`;
+
+// ============================================================================
+// Tab Session
+// ============================================================================
+
+/**
+ * Protocol connection to one tab
+ */
+class CdpSession {
+  private ws: WebSocket;
+  private messageId = 0;
+  private pending = new Map<number, { resolve: (value: any) => void; reject: (error: Error) => void }>();
+  private listeners = new Map<string, Set<(params: any) => void>>();
+  readonly network: NetworkEntry[] = [];
+
+  private constructor(ws: WebSocket) {
+    this.ws = ws;
+    ws.on('message', (data: Buffer) => this.handleMessage(data.toString()));
+    ws.on('close', () => {
+      for (const { reject } of this.pending.values()) {
+        reject(new Error('Tab connection closed'));
+      }
+      this.pending.clear();
+    });
+    this.trackNetwork();
+  }
+
+  static async open(webSocketUrl: string): Promise<CdpSession> {
+    const ws = new WebSocket(webSocketUrl, { perMessageDeflate: false });
+    await new Promise<void>((resolve, reject) => {
+      ws.once('open', () => resolve());
+      ws.once('error', reject);
+    });
+    const session = new CdpSession(ws);
+    await Promise.all([
+      session.send('Page.enable'),
+      session.send('Runtime.enable'),
+      session.send('Network.enable'),
+    ]);
+    return session;
+  }
+
+  get isOpen(): boolean {
+    return this.ws.readyState === WebSocket.OPEN;
+  }
+
+  send(method: string, params: Record<string, unknown> = {}): Promise<any> {
+    const id = ++this.messageId;
+    return new Promise((resolve, reject) => {
+      const timeout = setTimeout(() => {
+        this.pending.delete(id);
+        reject(new Error(`CDP command timed out: ${method}`));
+      }, COMMAND_TIMEOUT_MS);
+      this.pending.set(id, {
+        resolve: (value) => { clearTimeout(timeout); resolve(value); },
+        reject: (error) => { clearTimeout(timeout); reject(error); },
+      });
+      this.ws.send(JSON.stringify({ id, method, params }));
+    });
+  }
+
+  /**
+   * Resolve on the next event of a kind, or after a timeout
+   */
+  waitFor(event: string, timeoutMs: number): Promise<boolean> {
+    return new Promise((resolve) => {
+      const handler = () => { clearTimeout(timeout); this.listeners.get(event)?.delete(handler); resolve(true); };
+      const timeout = setTimeout(() => { this.listeners.get(event)?.delete(handler); resolve(false); }, timeoutMs);
+      this.on(event, handler);
+    });
+  }
+
+  close(): void {
+    this.ws.close();
+  }
+
+  private on(event: string, handler: (params: any) => void): void {
+    if (!this.listeners.has(event)) {
+      this.listeners.set(event, new Set());
+    }
+    this.listeners.get(event)!.add(handler);
+  }
+
+  private handleMessage(data: string): void {
+    let message: CdpMessage;
+    try {
+      message = JSON.parse(data);
+    } catch {
+      return;
+    }
+
+    if (message.id !== undefined) {
+      const request = this.pending.get(message.id);
+      if (request) {
+        this.pending.delete(message.id);
+        if (message.error) {
+          request.reject(new Error(message.error.message));
+        } else {
+          request.resolve(message.result);
+        }
+      }
+      return;
+    }
+
+    if (message.method) {
+      for (const handler of this.listeners.get(message.method) ?? []) {
+        handler(message.params);
+      }
+    }
+  }
+
+  private trackNetwork(): void {
+    const byId = new Map<string, NetworkEntry>();
+
+    this.on('Network.requestWillBeSent', (params) => {
+      const entry: NetworkEntry = {
+        requestId: params.requestId,
+        method: params.request?.method ?? 'GET',
+        url: params.request?.url ?? '',
+        resourceType: params.type,
+        startedAt: Date.now(),
+      };
+      byId.set(entry.requestId, entry);
+      this.network.push(entry);
+      if (this.network.length > NETWORK_LOG_SIZE) {
+        byId.delete(this.network.shift()!.requestId);
+      }
+    });
+    this.on('Network.responseReceived', (params) => {
+      const entry = byId.get(params.requestId);
+      if (entry) {
+        entry.status = params.response?.status;
+        entry.mimeType = params.response?.mimeType;
+      }
+    });
+    this.on('Network.loadingFinished', (params) => {
+      const entry = byId.get(params.requestId);
+      if (entry) {
+        entry.durationMs = Date.now() - entry.startedAt;
+      }
+    });
+    this.on('Network.loadingFailed', (params) => {
+      const entry = byId.get(params.requestId);
+      if (entry) {
+        entry.failed = params.errorText || 'failed';
+        entry.durationMs = Date.now() - entry.startedAt;
+      }
+    });
+  }
+}
+
+// ============================================================================
+// CDP Client Class
+// ============================================================================
+
+/**
+ * Browser automation over a Chrome remote debugging port
+ */
+export class CdpClient {
+  private readonly endpoint: string;
+  private sessions = new Map<string, CdpSession>();
+
+  constructor(endpoint: string = process.env.FLOYD_CDP_URL || 'http://localhost:9222') {
+    this.endpoint = endpoint.replace(/\/$/, '');
+  }
+
+  getEndpoint(): string {
+    return this.endpoint;
+  }
+
+  /**
+   * Whether Chrome answers on the debugging port
+   */
+  async isAvailable(): Promise<boolean> {
+    try {
+      const response = await fetch(`${this.endpoint}/json/version`, { signal: AbortSignal.timeout(2000) });
+      return response.ok;
+    } catch {
+      return false;
+    }
+  }
+
+  /**
+   * Open page targets, in tab number order
+   */
+  async listTabs(): Promise<CdpTarget[]> {
+    const response = await fetch(`${this.endpoint}/json/list`, { signal: AbortSignal.timeout(5000) });
+    if (!response.ok) {
+      throw new Error(`Chrome debugging endpoint returned HTTP ${response.status}`);
+    }
+    const targets = await response.json() as CdpTarget[];
+    return targets.filter(target => target.type === 'page');
+  }
+
+  /**
+   * Open a new tab
+   */
+  async createTab(url: string = 'about:blank'): Promise<CdpTarget> {
+    const response = await fetch(`${this.endpoint}/json/new?${encodeURIComponent(url)}`, { method: 'PUT', signal: AbortSignal.timeout(5000) });
+    if (!response.ok) {
+      throw new Error(`Could not open a tab: HTTP ${response.status}`);
+    }
+    return response.json() as Promise<CdpTarget>;
+  }
+
+  async navigate(url: string, tabId?: number): Promise<{ url: string; title: string; loaded: boolean }> {
+    const session = await this.session(tabId);
+    const loaded = session.waitFor('Page.loadEventFired', LOAD_TIMEOUT_MS);
+    const result = await session.send('Page.navigate', { url });
+    if (result?.errorText) {
+      throw new Error(`Navigation failed: ${result.errorText}`);
+    }
+    const didLoad = await loaded;
+    const page = await this.evaluate('({ url: location.href, title: document.title })', tabId);
+    return { ...(page as { url: string; title: string }), loaded: didLoad };
+  }
+
+  /**
+   * Page text and interactive elements with CSS selectors
+   */
+  async readPage(tabId?: number): Promise<unknown> {
+    return this.evaluate(READ_PAGE_SCRIPT, tabId);
+  }
+
+  /**
+   * PNG screenshot of the viewport, the full page or one element, base64-encoded
+   */
+  async screenshot(options: { fullPage?: boolean; selector?: string; tabId?: number } = {}): Promise<{ format: 'png'; data: string }> {
+    const session = await this.session(options.tabId);
+    const params: Record<string, unknown> = { format: 'png' };
+
+    if (options.selector) {
+      const rect = await this.evaluate(
+        `(() => { const el = document.querySelector(${JSON.stringify(options.selector)}); if (!el) return null; const r = el.getBoundingClientRect(); return { x: r.x + scrollX, y: r.y + scrollY, width: r.width, height: r.height }; })()`,
+        options.tabId,
+      ) as { x: number; y: number; width: number; height: number } | null;
+      if (!rect) {
+        throw new Error(`No element matches ${options.selector}`);
+      }
+      params.clip = { ...rect, scale: 1 };
+      params.captureBeyondViewport = true;
+    } else if (options.fullPage) {
+      const metrics = await session.send('Page.getLayoutMetrics');
+      const size = metrics.cssContentSize ?? metrics.contentSize;
+      params.clip = { x: 0, y: 0, width: size.width, height: size.height, scale: 1 };
+      params.captureBeyondViewport = true;
+    }
+
+    const { data } = await session.send('Page.captureScreenshot', params);
+    return { format: 'png', data };
+  }
+
+  /**
+   * Run JavaScript in the page and return its (awaited) value
+   */
+  async evaluate(expression: string, tabId?: number): Promise<unknown> {
+    const session = await this.session(tabId);
+    const { result, exceptionDetails } = await session.send('Runtime.evaluate', {
+      expression,
+      returnByValue: true,
+      awaitPromise: true,
+      userGesture: true,
+    });
+    if (exceptionDetails) {
+      throw new Error(exceptionDetails.exception?.description || exceptionDetails.text || 'Script threw an exception');
+    }
+    return result?.value ?? (result?.type === 'undefined' ? undefined : result?.description);
+  }
+
+  /**
+   * Click an element (by selector) or a point in the viewport
+   */
+  async click(options: { selector?: string; x?: number; y?: number; tabId?: number }): Promise<{ x: number; y: number }> {
+    let { x, y } = options;
+    if (options.selector) {
+      const point = await this.evaluate(
+        `(() => { const el = document.querySelector(${JSON.stringify(options.selector)}); if (!el) return null; el.scrollIntoView({ block: 'center' }); const r = el.getBoundingClientRect(); return { x: r.x + r.width / 2, y: r.y + r.height / 2 }; })()`,
+        options.tabId,
+      ) as { x: number; y: number } | null;
+      if (!point) {
+        throw new Error(`No element matches ${options.selector}`);
+      }
+      ({ x, y } = point);
+    }
+    if (x === undefined || y === undefined) {
+      throw new Error('Give a selector or x and y');
+    }
+
+    const session = await this.session(options.tabId);
+    for (const type of ['mousePressed', 'mouseReleased']) {
+      await session.send('Input.dispatchMouseEvent', { type, x, y, button: 'left', clickCount: 1 });
+    }
+    return { x, y };
+  }
+
+  /**
+   * Type text into the focused element
+   */
+  async type(text: string, tabId?: number): Promise<void> {
+    const session = await this.session(tabId);
+    await session.send('Input.insertText', { text });
+  }
+
+  /**
+   * Requests seen on a tab since it was attached, newest last
+   */
+  async networkLog(options: { tabId?: number; filter?: string; limit?: number } = {}): Promise<NetworkEntry[]> {
+    const session = await this.session(options.tabId);
+    const entries = options.filter
+      ? session.network.filter(entry => entry.url.includes(options.filter!))
+      : session.network;
+    return entries.slice(-(options.limit ?? 50));
+  }
+
+  /**
+   * Close all tab connections (tabs stay open)
+   */
+  close(): void {
+    for (const session of this.sessions.values()) {
+      session.close();
+    }
+    this.sessions.clear();
+  }
+
+  /**
+   * Connection to a tab by number (default: the first tab), opened on first use
+   */
+  private async session(tabId?: number): Promise<CdpSession> {
+    const tabs = await this.listTabs();
+    const target = tabs.length === 0 ? await this.createTab() : tabs[tabId ?? 0];
+    if (!target) {
+      throw new Error(`No tab ${tabId}; browser_get_tabs lists ${tabs.length}`);
+    }
+
+    const existing = this.sessions.get(target.id);
+    if (existing?.isOpen) {
+      return existing;
+    }
+    if (!target.webSocketDebuggerUrl) {
+      throw new Error(`Tab ${tabId ?? 0} is already being debugged by another client`);
+    }
+
+    const session = await CdpSession.open(target.webSocketDebuggerUrl);
+    this.sessions.set(target.id, session);
+    return session;
+  }
+}
@@ -2,12 +2,17 @@
  * Browser Tools - Floyd Wrapper
  *
  * Browser automation tools copied from FLOYD_CLI browser-server.ts
- * Note: Requires FloydChrome extension running at ws://localhost:3005
+ * Note: Requires FloydChrome extension running at ws://localhost:3005, or
+ * Chrome with a remote debugging port (see cdp-client.ts).
+ *
+ * FLOYD_BROWSER_BACKEND picks the backend: extension, cdp, or auto (the
+ * extension when it answers, otherwise Chrome DevTools).
  */
 
 import { z } from 'zod';
 import type { ToolDefinition } from '../../types.js';
 import { WebSocket } from 'ws';
+import { CdpClient } from './cdp-client.js';
 
 interface JSONRPCMessage {
   jsonrpc: '2.0';
@@ -174,26 +179,97 @@ class BrowserClient {
 }
 
 const browserClient = new BrowserClient();
+const cdpClient = new CdpClient();
+
+type BrowserBackend = 'extension' | 'cdp';
+
+/**
+ * The backend to use, per FLOYD_BROWSER_BACKEND and what is reachable
+ */
+async function selectBackend(): Promise<{ backend?: BrowserBackend; message: string }> {
+  const preference = (process.env.FLOYD_BROWSER_BACKEND || 'auto').toLowerCase();
+
+  let extensionMessage = '';
+  if (preference !== 'cdp') {
+    const health = await browserClient.healthCheck();
+    if (health.healthy || preference === 'extension') {
+      return { backend: health.healthy ? 'extension' : undefined, message: health.message };
+    }
+    extensionMessage = `${health.message} `;
+  }
+
+  if (await cdpClient.isAvailable()) {
+    return { backend: 'cdp', message: `Connected to Chrome DevTools at ${cdpClient.getEndpoint()}` };
+  }
+  return {
+    message: `${extensionMessage}Chrome DevTools is not reachable at ${cdpClient.getEndpoint()}; start Chrome with --remote-debugging-port=9222 or set FLOYD_CDP_URL.`,
+  };
+}
 
 /**
  * Wrapper for browser tool execute functions that includes health check
+ *
+ * @param toolName - Extension tool name, or null when only Chrome DevTools can do it
+ * @param cdp - The same action over Chrome DevTools
  */
 async function withBrowserHealthCheck(
-  toolName: string,
-  input: Record<string, any>
+  toolName: string | null,
+  input: Record<string, any>,
+  cdp: (input: Record<string, any>) => Promise<unknown>
 ): Promise<{ success: boolean; data?: any; error?: { code: string; message: string } }> {
-  const health = await browserClient.healthCheck();
-  if (!health.healthy) {
+  const { backend, message } = await selectBackend();
+  if (!backend) {
     return {
       success: false,
       error: {
         code: 'BROWSER_EXTENSION_UNAVAILABLE' as const,
-        message: health.message
+        message
+      }
+    };
+  }
+
+  if (backend === 'extension') {
+    if (!toolName) {
+      return {
+        success: false,
+        error: {
+          code: 'BROWSER_BACKEND_UNSUPPORTED',
+          message: 'Only available over Chrome DevTools; set FLOYD_BROWSER_BACKEND=cdp and start Chrome with --remote-debugging-port=9222'
+        }
+      };
+    }
+    const result = await browserClient.callTool(toolName, input);
+    return { success: true, data: result };
+  }
+
+  try {
+    return { success: true, data: await cdp(input) };
+  } catch (error) {
+    return {
+      success: false,
+      error: {
+        code: 'BROWSER_ERROR',
+        message: (error as Error).message
       }
     };
   }
-  const result = await browserClient.callTool(toolName, input);
-  return { success: true, data: result };
+}
+
+/**
+ * Interactive elements of the page whose text, link or selector mentions the query
+ */
+async function findElements(query: string, tabId?: number): Promise<unknown[]> {
+  const page = await cdpClient.readPage(tabId) as { elements: Array<{ text: string; href?: string; selector: string }> };
+  const words = query.toLowerCase().split(/\s+/).filter(Boolean);
+  return page.elements
+    .map(element => ({
+      element,
+      score: words.filter(word => `${element.text} ${element.href ?? ''} ${element.selector}`.toLowerCase().includes(word)).length,
+    }))
+    .filter(match => match.score > 0)
+    .sort((a, b) => b.score - a.score)
+    .slice(0, 10)
+    .map(match => match.element);
 }
 
 // ============================================================================
@@ -202,18 +278,20 @@ async function withBrowserHealthCheck(
 
 export const browserStatusTool: ToolDefinition = {
   name: 'browser_status',
-  description: 'Check connection status to FloydChrome extension',
+  description: 'Check connection status to the FloydChrome extension or Chrome DevTools',
   category: 'browser',
   inputSchema: z.object({}),
   permission: 'none',
   execute: async () => {
-    const health = await browserClient.healthCheck();
+    const { backend, message } = await selectBackend();
     return {
       success: true,
       data: {
-        healthy: health.healthy,
+        healthy: backend !== undefined,
+        backend: backend ?? null,
         extension_url: process.env.FLOYD_EXTENSION_URL || 'ws://localhost:3005',
-        message: health.message
+        cdp_url: cdpClient.getEndpoint(),
+        message
       }
     };
   }
@@ -232,7 +310,7 @@ export const browserNavigateTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'moderate',
-  execute: async (input) => withBrowserHealthCheck('navigate', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('navigate', input as Record<string, any>, ({ url, tabId }) => cdpClient.navigate(url, tabId))
 } as ToolDefinition;
 
 // ============================================================================
@@ -247,7 +325,7 @@ export const browserReadPageTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'moderate',
-  execute: async (input) => withBrowserHealthCheck('read_page', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('read_page', input as Record<string, any>, ({ tabId }) => cdpClient.readPage(tabId))
 } as ToolDefinition;
 
 // ============================================================================
@@ -264,7 +342,7 @@ export const browserScreenshotTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'moderate',
-  execute: async (input) => withBrowserHealthCheck('screenshot', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('screenshot', input as Record<string, any>, (args) => cdpClient.screenshot(args))
 } as ToolDefinition;
 
 // ============================================================================
@@ -282,7 +360,7 @@ export const browserClickTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'dangerous',
-  execute: async (input) => withBrowserHealthCheck('click', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('click', input as Record<string, any>, (args) => cdpClient.click(args))
 } as ToolDefinition;
 
 // ============================================================================
@@ -298,7 +376,10 @@ export const browserTypeTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'dangerous',
-  execute: async (input) => withBrowserHealthCheck('type', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('type', input as Record<string, any>, async ({ text, tabId }) => {
+    await cdpClient.type(text, tabId);
+    return { typed: text.length };
+  })
 } as ToolDefinition;
 
 // ============================================================================
@@ -314,7 +395,7 @@ export const browserFindTool: ToolDefinition = {
     tabId: z.number().optional(),
   }),
   permission: 'moderate',
-  execute: async (input) => withBrowserHealthCheck('find', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('find', input as Record<string, any>, ({ query, tabId }) => findElements(query, tabId))
 } as ToolDefinition;
 
 // ============================================================================
@@ -327,7 +408,8 @@ export const browserGetTabsTool: ToolDefinition = {
   category: 'browser',
   inputSchema: z.object({}),
   permission: 'moderate',
-  execute: async () => withBrowserHealthCheck('get_tabs', {})
+  execute: async () => withBrowserHealthCheck('get_tabs', {}, async () =>
+    (await cdpClient.listTabs()).map((tab, index) => ({ tabId: index, title: tab.title, url: tab.url })))
 } as ToolDefinition;
 
 // ============================================================================
@@ -342,5 +424,45 @@ export const browserCreateTabTool: ToolDefinition = {
     url: z.string().optional(),
   }),
   permission: 'moderate',
-  execute: async (input) => withBrowserHealthCheck('tabs_create', input as Record<string, any>)
+  execute: async (input) => withBrowserHealthCheck('tabs_create', input as Record<string, any>, async ({ url }) => {
+    const tab = await cdpClient.createTab(url);
+    return { tabId: (await cdpClient.listTabs()).findIndex(t => t.id === tab.id), url: tab.url };
+  })
+} as ToolDefinition;
+
+// ============================================================================
+// Browser JavaScript Exec Tool
+// ============================================================================
+
+export const browserJavascriptExecTool: ToolDefinition = {
+  name: 'browser_javascript_exec',
+  description: 'Run a JavaScript expression in the page and return its value (promises are awaited; return JSON-serializable data). Chrome DevTools backend only.',
+  category: 'browser',
+  inputSchema: z.object({
+    expression: z.string(),
+    tabId: z.number().optional(),
+  }),
+  permission: 'dangerous',
+  execute: async (input) => withBrowserHealthCheck(null, input as Record<string, any>, async ({ expression, tabId }) =>
+    ({ value: await cdpClient.evaluate(expression, tabId) }))
+} as ToolDefinition;
+
+// ============================================================================
+// Browser Network Log Tool
+// ============================================================================
+
+export const browserNetworkLogTool: ToolDefinition = {
+  name: 'browser_network_log',
+  description: 'List requests the page made since Floyd attached to the tab (method, URL, status, type, duration, failures), newest last. Filter matches part of the URL. Chrome DevTools backend only.',
+  category: 'browser',
+  inputSchema: z.object({
+    filter: z.string().optional(),
+    limit: z.number().optional().default(50),
+    tabId: z.number().optional(),
+  }),
+  permission: 'moderate',
+  execute: async (input) => withBrowserHealthCheck(null, input as Record<string, any>, async (args) => {
+    const requests = await cdpClient.networkLog(args);
+    return { requests, total: requests.length };
+  })
 } as ToolDefinition;
@@ -23,7 +23,7 @@ export function setProjectRoot(root: string): void {
 /**
  * Get cache manager instance
  */
-function getCacheManager(): CacheManager {
+export function getCacheManager(): CacheManager {
 	if (!cacheManager) {
 		cacheManager = new CacheManager(projectRoot);
 	}
@@ -222,7 +222,7 @@ export const cacheSearchTool: ToolDefinition = {
 
 export const cacheStatsTool: ToolDefinition = {
 	name: 'cache_stats',
-	description: 'Get statistics for cache tiers (entry count, size, oldest/newest)',
+	description: 'Get statistics for cache tiers (entry count, size, hits, misses, evictions, oldest/newest)',
 	category: 'cache',
 	inputSchema: z.object({
 		tier: z.enum(['reasoning', 'project', 'vault']).optional(),
@@ -234,18 +234,25 @@ export const cacheStatsTool: ToolDefinition = {
 		const stats = await cache.getStats(tier);
 		const result: Record<string, {
 			entries: number;
-			active: number;
 			sizeBytes: number;
-			oldest?: { key: string; timestamp: number };
-			newest?: { key: string; timestamp: number };
+			hits: number;
+			misses: number;
+			hitRate: number | null;
+			evictions: number;
+			oldest?: number;
+			newest?: number;
 		}> = {};
 		for (const stat of stats) {
+			const lookups = stat.hits + stat.misses;
 			result[stat.tier] = {
 				entries: stat.count,
-				active: stat.count,
 				sizeBytes: stat.totalSize,
-				oldest: stat.oldestEntry ? { key: '', timestamp: stat.oldestEntry } : undefined,
-				newest: stat.newestEntry ? { key: '', timestamp: stat.newestEntry } : undefined
+				hits: stat.hits,
+				misses: stat.misses,
+				hitRate: lookups > 0 ? stat.hits / lookups : null,
+				evictions: stat.evictions,
+				oldest: stat.oldestEntry,
+				newest: stat.newestEntry
 			};
 		}
 		return {
@@ -0,0 +1,210 @@
+/**
+ * Custom Tools Core - Floyd Wrapper
+ *
+ * User-defined tools described by YAML manifests in ~/.floyd/tools/. Each
+ * manifest names a shell command template; the model's input is validated
+ * against the manifest's JSON schema and substituted into the template,
+ * shell-quoted, so teams can expose make targets or deploy scripts as tools.
+ *
+ *   name: deploy_preview
+ *   description: Deploy the current branch to a preview environment
+ *   parameters:
+ *     type: object
+ *     properties:
+ *       service: { type: string, enum: [api, web] }
+ *     required: [service]
+ *   command: make deploy-preview SERVICE={{service}}
+ */
+
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import yaml from 'js-yaml';
+import { z } from 'zod';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Subset of JSON Schema understood in manifests
+ */
+export interface JsonSchema {
+	type?: string;
+	description?: string;
+	properties?: Record<string, JsonSchema>;
+	required?: string[];
+	items?: JsonSchema;
+	enum?: Array<string | number>;
+	default?: unknown;
+}
+
+/**
+ * A validated tool manifest
+ */
+export interface CustomToolManifest {
+	name: string;
+	description: string;
+	parameters: JsonSchema;
+	command: string;
+	permission: 'none' | 'moderate' | 'dangerous';
+	/** Working directory of the command (default: the current directory) */
+	cwd?: string;
+	timeout: number;
+	/** Manifest file the tool came from */
+	source: string;
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Directory scanned for manifests
+ */
+export function getCustomToolsDir(): string {
+	return process.env.FLOYD_TOOLS_DIR || path.join(os.homedir(), '.floyd', 'tools');
+}
+
+const DEFAULT_TIMEOUT = 120_000;
+
+const manifestSchema = z.object({
+	name: z.string().regex(/^[a-zA-Z][a-zA-Z0-9_-]{0,63}$/, 'must start with a letter and contain only letters, digits, _ or -'),
+	description: z.string().min(1),
+	parameters: z.record(z.unknown()).optional(),
+	command: z.string().min(1),
+	permission: z.enum(['none', 'moderate', 'dangerous']).optional(),
+	cwd: z.string().optional(),
+	timeout: z.number().positive().optional(),
+});
+
+// ============================================================================
+// Manifests
+// ============================================================================
+
+/**
+ * Validate a parsed manifest
+ *
+ * @throws Error naming the manifest and the invalid field
+ */
+export function parseManifest(raw: unknown, source: string): CustomToolManifest {
+	const result = manifestSchema.safeParse(raw);
+	if (!result.success) {
+		const issue = result.error.issues[0];
+		throw new Error(`${source}: ${issue.path.join('.') || 'manifest'} ${issue.message}`);
+	}
+
+	const manifest = result.data;
+	const parameters = (manifest.parameters ?? { type: 'object', properties: {} }) as JsonSchema;
+	if (parameters.type !== undefined && parameters.type !== 'object') {
+		throw new Error(`${source}: parameters must be an object schema`);
+	}
+
+	return {
+		name: manifest.name,
+		description: manifest.description,
+		parameters,
+		command: manifest.command,
+		// Commands can do anything; ask unless the manifest says otherwise
+		permission: manifest.permission ?? 'dangerous',
+		cwd: manifest.cwd,
+		timeout: manifest.timeout ?? DEFAULT_TIMEOUT,
+		source,
+	};
+}
+
+/**
+ * Read every *.yaml / *.yml manifest in a directory
+ *
+ * Invalid manifests are reported in `errors` and skipped.
+ */
+export function loadManifests(dir: string = getCustomToolsDir()): { manifests: CustomToolManifest[]; errors: string[] } {
+	const manifests: CustomToolManifest[] = [];
+	const errors: string[] = [];
+
+	if (!fs.existsSync(dir)) {
+		return { manifests, errors };
+	}
+
+	for (const file of fs.readdirSync(dir).filter(name => /\.ya?ml$/.test(name)).sort()) {
+		const source = path.join(dir, file);
+		try {
+			manifests.push(parseManifest(yaml.load(fs.readFileSync(source, 'utf-8')), source));
+		} catch (error) {
+			errors.push((error as Error).message);
+		}
+	}
+
+	return { manifests, errors };
+}
+
+// ============================================================================
+// Schema & Command
+// ============================================================================
+
+/**
+ * Zod schema for a JSON schema, as far as the tool definitions need it
+ */
+export function jsonSchemaToZod(schema: JsonSchema): z.ZodTypeAny {
+	let type: z.ZodTypeAny;
+
+	if (schema.enum && schema.enum.length > 0 && schema.enum.every(value => typeof value === 'string')) {
+		type = z.enum(schema.enum as [string, ...string[]]);
+	} else {
+		switch (schema.type) {
+			case 'string':
+				type = z.string();
+				break;
+			case 'number':
+				type = z.number();
+				break;
+			case 'integer':
+				type = z.number().int();
+				break;
+			case 'boolean':
+				type = z.boolean();
+				break;
+			case 'array':
+				type = z.array(schema.items ? jsonSchemaToZod(schema.items) : z.string());
+				break;
+			case 'object': {
+				const required = new Set(schema.required ?? []);
+				const shape: Record<string, z.ZodTypeAny> = {};
+				for (const [key, property] of Object.entries(schema.properties ?? {})) {
+					const field = jsonSchemaToZod(property);
+					shape[key] = required.has(key) ? field : field.optional();
+				}
+				type = z.object(shape);
+				break;
+			}
+			default:
+				type = z.string();
+		}
+	}
+
+	return schema.description ? type.describe(schema.description) : type;
+}
+
+/**
+ * Quote a value for a POSIX shell
+ */
+export function shellQuote(value: string): string {
+	return /^[\w@%+=:,./-]+$/.test(value) ? value : `'${value.replace(/'/g, `'\\''`)}'`;
+}
+
+/**
+ * Fill `{{param}}` placeholders with shell-quoted input values; arrays become
+ * several arguments and missing values nothing
+ */
+export function renderCommand(template: string, input: Record<string, unknown>): string {
+	return template.replace(/\{\{\s*([\w-]+)\s*\}\}/g, (_match, key: string) => {
+		const value = input[key];
+		if (value === undefined || value === null) {
+			return '';
+		}
+		if (Array.isArray(value)) {
+			return value.map(item => shellQuote(String(item))).join(' ');
+		}
+		return shellQuote(typeof value === 'object' ? JSON.stringify(value) : String(value));
+	});
+}
@@ -0,0 +1,91 @@
+/**
+ * Custom Tools - Floyd Wrapper
+ *
+ * Turns the YAML manifests in ~/.floyd/tools/ into tool definitions that
+ * run their command template through the shell. They share the "custom"
+ * category, so tool profiles can leave them all out.
+ */
+
+import { execa } from 'execa';
+import type { ToolDefinition } from '../../types.js';
+import { createToolOutputStream, budgetToolOutput } from '../../streaming/tool-output.js';
+import { getShutdownController } from '../../interrupts/shutdown-controller.js';
+import { loadManifests, jsonSchemaToZod, renderCommand, getCustomToolsDir, type CustomToolManifest } from './custom-core.js';
+
+// ============================================================================
+// Custom Tool
+// ============================================================================
+
+/**
+ * Tool definition for a manifest
+ */
+export function createCustomTool(manifest: CustomToolManifest): ToolDefinition {
+	return {
+		name: manifest.name,
+		description: manifest.description,
+		category: 'custom',
+		inputSchema: jsonSchemaToZod({ ...manifest.parameters, type: 'object' }),
+		permission: manifest.permission,
+		execute: async (input) => {
+			const command = renderCommand(manifest.command, (input ?? {}) as Record<string, unknown>);
+			const output = createToolOutputStream(manifest.name);
+			const startedAt = Date.now();
+
+			try {
+				const subprocess = execa(command, {
+					shell: true,
+					cwd: manifest.cwd ?? process.cwd(),
+					timeout: manifest.timeout,
+					reject: false,
+					detached: process.platform !== 'win32',
+				});
+				getShutdownController().trackProcess(subprocess);
+				subprocess.stdout?.on('data', (chunk: Buffer) => output.write(chunk.toString()));
+				subprocess.stderr?.on('data', (chunk: Buffer) => output.write(chunk.toString()));
+
+				const result = await subprocess;
+				const data = {
+					command,
+					exitCode: result.exitCode ?? null,
+					stdout: budgetToolOutput(result.stdout || ''),
+					stderr: budgetToolOutput(result.stderr || ''),
+					lines: output.getTotalLines(),
+					duration: Date.now() - startedAt,
+				};
+
+				if (result.exitCode !== 0) {
+					return {
+						success: false,
+						data,
+						error: {
+							code: result.timedOut ? 'TIMEOUT' : 'TOOL_EXECUTION_FAILED',
+							message: result.timedOut
+								? `${manifest.name} timed out after ${manifest.timeout}ms`
+								: `${manifest.name} exited with code ${result.exitCode}`,
+						},
+					};
+				}
+				return { success: true, data };
+			} catch (error) {
+				return {
+					success: false,
+					error: {
+						code: 'TOOL_EXECUTION_FAILED',
+						message: (error as Error).message,
+						details: { command },
+					},
+				};
+			} finally {
+				output.end();
+			}
+		},
+	} as ToolDefinition;
+}
+
+/**
+ * Tool definitions for the manifests in a directory (default ~/.floyd/tools)
+ */
+export function loadCustomTools(dir: string = getCustomToolsDir()): { tools: ToolDefinition[]; errors: string[] } {
+	const { manifests, errors } = loadManifests(dir);
+	return { tools: manifests.map(createCustomTool), errors };
+}
@@ -0,0 +1,409 @@
+/**
+ * Database Core - Floyd Wrapper
+ *
+ * Connections for the db_query tool, declared per project in
+ * .floyd/config.toml; the model only ever picks a connection by name, it
+ * never supplies a DSN:
+ *
+ *   [databases.app]
+ *   dsn = "sqlite:data/app.db"       # relative to the project
+ *
+ *   [databases.staging]
+ *   dsn_env = "STAGING_DATABASE_URL" # read the DSN from a variable
+ *   max_rows = 50                    # rows returned per query (default 100)
+ *   read_only = true                 # the default; false allows writes
+ *
+ * SQLite databases are opened with better-sqlite3 (read-only unless
+ * read_only = false). PostgreSQL is queried through `psql`, which must be
+ * installed; read-only connections run in read-only transactions, and the
+ * password is handed over in the environment rather than on the command line.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import Database from 'better-sqlite3';
+import { execa } from 'execa';
+import { parseToml } from 'floyd-agent-core/ui';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export type DatabaseDriver = 'sqlite' | 'postgres';
+
+/**
+ * A database the agent may query
+ */
+export interface DatabaseConnection {
+	name: string;
+	driver: DatabaseDriver;
+	/** File path (sqlite) or connection URI (postgres) */
+	dsn: string;
+	readOnly: boolean;
+	maxRows: number;
+}
+
+/**
+ * Rows returned by a query
+ */
+export interface QueryResult {
+	columns: string[];
+	rows: unknown[][];
+	/** Whether more rows than maxRows were available */
+	truncated: boolean;
+	/** Rows changed by a write (sqlite) */
+	changes?: number;
+}
+
+export const DATABASE_CONFIG_FILE = path.join('.floyd', 'config.toml');
+
+const DEFAULT_MAX_ROWS = 100;
+
+// ============================================================================
+// Configuration
+// ============================================================================
+
+/**
+ * Driver and DSN for a configured connection string
+ *
+ * @throws Error for unsupported schemes
+ */
+export function parseDsn(dsn: string, projectRoot: string): { driver: DatabaseDriver; dsn: string } {
+	if (/^postgres(ql)?:\/\//i.test(dsn)) {
+		return { driver: 'postgres', dsn };
+	}
+	const sqlitePath = dsn.match(/^sqlite:(?:\/\/)?(.+)$/i)?.[1] ?? (/\.(db|sqlite3?)$/i.test(dsn) ? dsn : null);
+	if (sqlitePath) {
+		return { driver: 'sqlite', dsn: path.resolve(projectRoot, sqlitePath) };
+	}
+	throw new Error('unsupported DSN; use sqlite:<path> or postgres://...');
+}
+
+/**
+ * Connections in a config.toml document; connections whose DSN variable is
+ * unset are left out
+ *
+ * @throws Error for invalid entries
+ */
+export function parseDatabaseConfig(
+	source: string,
+	projectRoot: string,
+	env: NodeJS.ProcessEnv = process.env
+): Record<string, DatabaseConnection> {
+	const raw = parseToml(source).databases;
+	if (raw === undefined) {
+		return {};
+	}
+	if (typeof raw !== 'object') {
+		throw new Error('"databases" must be a table, e.g. [databases.app]');
+	}
+
+	const connections: Record<string, DatabaseConnection> = {};
+	for (const [name, entry] of Object.entries(raw)) {
+		if (typeof entry !== 'object') {
+			throw new Error(`databases.${name} must be a table`);
+		}
+
+		let dsn: string | undefined;
+		if (typeof entry.dsn === 'string') {
+			dsn = entry.dsn;
+		} else if (typeof entry.dsn_env === 'string') {
+			dsn = env[entry.dsn_env];
+			if (!dsn) {
+				continue;
+			}
+		} else {
+			throw new Error(`databases.${name} needs a dsn or dsn_env`);
+		}
+		if (entry.max_rows !== undefined && (typeof entry.max_rows !== 'number' || entry.max_rows < 1)) {
+			throw new Error(`databases.${name}.max_rows must be a positive number`);
+		}
+		if (entry.read_only !== undefined && typeof entry.read_only !== 'boolean') {
+			throw new Error(`databases.${name}.read_only must be true or false`);
+		}
+
+		let parsed: { driver: DatabaseDriver; dsn: string };
+		try {
+			parsed = parseDsn(dsn, projectRoot);
+		} catch (error) {
+			throw new Error(`databases.${name}: ${(error as Error).message}`);
+		}
+
+		connections[name] = {
+			name,
+			...parsed,
+			readOnly: entry.read_only !== false,
+			maxRows: typeof entry.max_rows === 'number' ? Math.floor(entry.max_rows) : DEFAULT_MAX_ROWS,
+		};
+	}
+
+	return connections;
+}
+
+/**
+ * Database connections of a project; none when .floyd/config.toml is
+ * missing or invalid
+ */
+export function loadDatabaseConfig(projectRoot: string = process.cwd()): Record<string, DatabaseConnection> {
+	const filePath = path.join(projectRoot, DATABASE_CONFIG_FILE);
+	if (!fs.existsSync(filePath)) {
+		return {};
+	}
+
+	try {
+		return parseDatabaseConfig(fs.readFileSync(filePath, 'utf-8'), projectRoot);
+	} catch (error) {
+		console.warn(`Ignoring databases in ${DATABASE_CONFIG_FILE}: ${(error as Error).message}`);
+		return {};
+	}
+}
+
+// ============================================================================
+// Queries
+// ============================================================================
+
+/**
+ * Run a query against a SQLite database
+ */
+export function runSqliteQuery(connection: DatabaseConnection, sql: string, maxRows: number): QueryResult {
+	const db = new Database(connection.dsn, { readonly: connection.readOnly, fileMustExist: true });
+	try {
+		const statement = db.prepare(sql);
+		if (!statement.reader) {
+			const { changes } = statement.run();
+			return { columns: [], rows: [], truncated: false, changes };
+		}
+
+		const columns = statement.columns().map(column => column.name);
+		const rows: unknown[][] = [];
+		let truncated = false;
+		for (const row of statement.raw(true).iterate() as Iterable<unknown[]>) {
+			if (rows.length === maxRows) {
+				truncated = true;
+				break;
+			}
+			rows.push(row);
+		}
+		return { columns, rows, truncated };
+	} finally {
+		db.close();
+	}
+}
+
+/**
+ * Split CSV output into records (quoted fields may hold commas, quotes and
+ * newlines)
+ */
+export function parseCsv(text: string): string[][] {
+	const records: string[][] = [];
+	let record: string[] = [];
+	let field = '';
+	let quoted = false;
+
+	for (let i = 0; i < text.length; i++) {
+		const char = text[i];
+		if (quoted) {
+			if (char === '"' && text[i + 1] === '"') {
+				field += '"';
+				i++;
+			} else if (char === '"') {
+				quoted = false;
+			} else {
+				field += char;
+			}
+		} else if (char === '"') {
+			quoted = true;
+		} else if (char === ',') {
+			record.push(field);
+			field = '';
+		} else if (char === '\n') {
+			record.push(field.replace(/\r$/, ''));
+			records.push(record);
+			record = [];
+			field = '';
+		} else {
+			field += char;
+		}
+	}
+	if (field || record.length > 0) {
+		record.push(field);
+		records.push(record);
+	}
+
+	return records;
+}
+
+/**
+ * Split SQL into statements on top-level semicolons; semicolons in quotes,
+ * dollar-quoted bodies and comments don't count, and empty or comment-only
+ * statements are dropped
+ */
+export function splitSqlStatements(sql: string): string[] {
+	const statements: string[] = [];
+	let start = 0;
+	let hasCode = false;
+	let i = 0;
+
+	const push = (end: number) => {
+		if (hasCode) {
+			statements.push(sql.slice(start, end).trim());
+		}
+		start = end + 1;
+		hasCode = false;
+	};
+
+	while (i < sql.length) {
+		const char = sql[i];
+		const next = sql[i + 1];
+
+		if (char === '-' && next === '-') {
+			const end = sql.indexOf('\n', i);
+			i = end === -1 ? sql.length : end + 1;
+		} else if (char === '/' && next === '*') {
+			// PostgreSQL block comments nest
+			let depth = 1;
+			i += 2;
+			while (i < sql.length && depth > 0) {
+				if (sql[i] === '/' && sql[i + 1] === '*') {
+					depth++;
+					i += 2;
+				} else if (sql[i] === '*' && sql[i + 1] === '/') {
+					depth--;
+					i += 2;
+				} else {
+					i++;
+				}
+			}
+		} else if (char === "'" || char === '"') {
+			// E'...' strings also allow backslash escapes
+			const escapes = char === "'" && /e/i.test(sql[i - 1] ?? '') && !/\w/.test(sql[i - 2] ?? '');
+			hasCode = true;
+			i++;
+			while (i < sql.length) {
+				if (escapes && sql[i] === '\\') {
+					i += 2;
+				} else if (sql[i] === char && sql[i + 1] === char) {
+					i += 2;
+				} else if (sql[i] === char) {
+					i++;
+					break;
+				} else {
+					i++;
+				}
+			}
+		} else if (char === '$' && !/\w/.test(sql[i - 1] ?? '')) {
+			const tag = /^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$/.exec(sql.slice(i))?.[0];
+			hasCode = true;
+			if (tag) {
+				const end = sql.indexOf(tag, i + tag.length);
+				i = end === -1 ? sql.length : end + tag.length;
+			} else {
+				i++;
+			}
+		} else if (char === ';') {
+			push(i);
+			i++;
+		} else {
+			if (!/\s/.test(char)) {
+				hasCode = true;
+			}
+			i++;
+		}
+	}
+	push(sql.length);
+
+	return statements;
+}
+
+/**
+ * psql connection argument and environment for a DSN; the password goes in
+ * PGPASSWORD so it never shows up in the process list
+ */
+export function postgresConnection(dsn: string): { dbname: string; env: Record<string, string> } {
+	let url: URL;
+	try {
+		url = new URL(dsn);
+	} catch {
+		return { dbname: dsn, env: {} };
+	}
+
+	const env: Record<string, string> = {};
+	const password = url.password ? decodeURIComponent(url.password) : url.searchParams.get('password');
+	if (password) {
+		env.PGPASSWORD = password;
+	}
+	url.password = '';
+	url.searchParams.delete('password');
+
+	return { dbname: url.toString(), env };
+}
+
+/**
+ * Run a query against PostgreSQL with psql
+ *
+ * Only one statement is accepted. On read-only connections it runs inside
+ * BEGIN READ ONLY ... ROLLBACK, so it can neither write nor switch the
+ * transaction back to read-write (PGOPTIONS alone could be undone by a
+ * SET in the same input). Row-returning queries are wrapped in a LIMIT so
+ * large tables aren't downloaded just to be cut off.
+ */
+export async function runPostgresQuery(connection: DatabaseConnection, sql: string, maxRows: number): Promise<QueryResult> {
+	const statements = splitSqlStatements(sql);
+	if (statements.length !== 1) {
+		throw new Error(statements.length === 0 ? 'no SQL statement given' : 'run one SQL statement per query');
+	}
+	const statement = statements[0];
+	if (statement.startsWith('\\')) {
+		throw new Error('psql meta-commands are not allowed');
+	}
+	const limited = /^(select|with|values|table)\b/i.test(statement)
+		? `SELECT * FROM (\n${statement}\n) AS floyd_query LIMIT ${maxRows + 1}`
+		: statement;
+	// Each -c is sent to the server as it is, in the same session
+	const commands = connection.readOnly ? ['BEGIN READ ONLY', limited, 'ROLLBACK'] : [limited];
+
+	const { dbname, env } = postgresConnection(connection.dsn);
+	const result = await execa('psql', [
+		'-X', '-q', '--csv', '-v', 'ON_ERROR_STOP=1',
+		...commands.flatMap(command => ['-c', command]),
+		'--dbname', dbname,
+	], {
+		reject: false,
+		env: connection.readOnly ? { ...env, PGOPTIONS: '-c default_transaction_read_only=on' } : env,
+	});
+	if (result.exitCode !== 0) {
+		throw new Error(result.stderr.replace(/^psql:\s*/gm, '').trim() || `psql exited with code ${result.exitCode}`);
+	}
+
+	const [columns = [], ...rows] = parseCsv(result.stdout);
+	return { columns, rows: rows.slice(0, maxRows), truncated: rows.length > maxRows };
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+function formatCell(value: unknown): string {
+	if (value === null || value === undefined) {
+		return 'NULL';
+	}
+	if (Buffer.isBuffer(value)) {
+		return `<blob ${value.length} bytes>`;
+	}
+	const text = typeof value === 'object' ? JSON.stringify(value) : String(value);
+	return text.replace(/\|/g, '\\|').replace(/\r?\n/g, ' ');
+}
+
+/**
+ * Query rows as a markdown table
+ */
+export function formatMarkdownTable(columns: string[], rows: unknown[][]): string {
+	if (columns.length === 0) {
+		return '';
+	}
+	return [
+		`| ${columns.map(formatCell).join(' | ')} |`,
+		`|${columns.map(() => '---').join('|')}|`,
+		...rows.map(row => `| ${columns.map((_, i) => formatCell(row[i])).join(' | ')} |`),
+	].join('\n');
+}
@@ -0,0 +1,89 @@
+/**
+ * Database Tools - Floyd Wrapper
+ *
+ * db_query runs SQL against the SQLite and PostgreSQL connections declared
+ * in .floyd/config.toml (see db-core.ts) and returns the rows as a markdown
+ * table, for schema exploration and checking data.
+ */
+
+import { z } from 'zod';
+import type { ToolDefinition, ToolResult } from '../../types.js';
+import {
+	loadDatabaseConfig,
+	runSqliteQuery,
+	runPostgresQuery,
+	formatMarkdownTable,
+	DATABASE_CONFIG_FILE,
+} from './db-core.js';
+
+function failure(code: string, message: string, details?: unknown): ToolResult {
+	return { success: false, error: { code, message, details } };
+}
+
+// ============================================================================
+// Zod Schema
+// ============================================================================
+
+const inputSchema = z.object({
+	sql: z.string().min(1),
+	database: z.string().optional(),
+	max_rows: z.number().int().positive().optional(),
+});
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+export const dbQueryTool: ToolDefinition = {
+	name: 'db_query',
+	description: `Run SQL against a project database declared in ${DATABASE_CONFIG_FILE} ([databases.<name>]) and get the rows as a markdown table. ` +
+		'Pick the connection by name with database (optional when only one is configured); connection strings cannot be passed. ' +
+		'One statement per query. Connections are read-only unless configured otherwise. Results are capped at max_rows (default and maximum set per connection). ' +
+		'For the schema: SQLite "SELECT name, sql FROM sqlite_master", PostgreSQL "SELECT table_name, column_name, data_type FROM information_schema.columns WHERE table_schema = \'public\'".',
+	category: 'build',
+	inputSchema,
+	permission: 'moderate',
+	execute: async (input) => {
+		const { sql, database, max_rows } = input as z.infer<typeof inputSchema>;
+		const connections = loadDatabaseConfig();
+		const names = Object.keys(connections);
+
+		if (names.length === 0) {
+			return failure('NOT_FOUND', `No databases configured. Ask the user to add a [databases.<name>] table with a dsn or dsn_env to ${DATABASE_CONFIG_FILE}`);
+		}
+		if (!database && names.length > 1) {
+			return failure('INVALID_INPUT', `Several databases are configured; pick one with database: ${names.join(', ')}`);
+		}
+		const connection = connections[database ?? names[0]];
+		if (!connection) {
+			return failure('NOT_FOUND', `Unknown database "${database}". Configured: ${names.join(', ')}`);
+		}
+
+		const maxRows = Math.min(max_rows ?? connection.maxRows, connection.maxRows);
+		try {
+			const result = connection.driver === 'sqlite'
+				? runSqliteQuery(connection, sql, maxRows)
+				: await runPostgresQuery(connection, sql, maxRows);
+
+			return {
+				success: true,
+				data: {
+					database: connection.name,
+					driver: connection.driver,
+					readOnly: connection.readOnly,
+					columns: result.columns,
+					rowCount: result.rows.length,
+					truncated: result.truncated,
+					...(result.changes !== undefined && { changes: result.changes }),
+					table: formatMarkdownTable(result.columns, result.rows),
+				},
+			};
+		} catch (error) {
+			const message = (error as Error).message;
+			const readOnlyHint = connection.readOnly && /read.?only|readonly/i.test(message)
+				? ` (connection "${connection.name}" is read-only)`
+				: '';
+			return failure('TOOL_EXECUTION_FAILED', `${message}${readOnlyHint}`, { database: connection.name });
+		}
+	},
+} as ToolDefinition;
@@ -0,0 +1,138 @@
+/**
+ * File Content Limits - Floyd Wrapper
+ *
+ * Keeps read_file from dumping huge or binary files into the conversation:
+ * reads are paged by line and capped in bytes, with a footer telling the
+ * model how to continue, and binary files are summarized instead of decoded.
+ */
+
+import path from 'path';
+import { formatBytes } from '../../rewind/file-snapshot.js';
+
+// ============================================================================
+// Limits
+// ============================================================================
+
+/** Lines returned when the caller gives no limit (FLOYD_READ_MAX_LINES) */
+export const DEFAULT_READ_LINES = 2000;
+
+/** Bytes returned per read (FLOYD_READ_MAX_BYTES) */
+export const DEFAULT_READ_BYTES = 256 * 1024;
+
+/** Bytes inspected for binary detection */
+const BINARY_SNIFF_BYTES = 8000;
+
+export function getReadLimits(): { maxLines: number; maxBytes: number } {
+	return {
+		maxLines: parseInt(process.env.FLOYD_READ_MAX_LINES || '', 10) || DEFAULT_READ_LINES,
+		maxBytes: parseInt(process.env.FLOYD_READ_MAX_BYTES || '', 10) || DEFAULT_READ_BYTES,
+	};
+}
+
+// ============================================================================
+// Binary Detection
+// ============================================================================
+
+const SIGNATURES: Array<{ bytes: number[]; type: string }> = [
+	{ bytes: [0x89, 0x50, 0x4e, 0x47], type: 'PNG image' },
+	{ bytes: [0xff, 0xd8, 0xff], type: 'JPEG image' },
+	{ bytes: [0x47, 0x49, 0x46, 0x38], type: 'GIF image' },
+	{ bytes: [0x25, 0x50, 0x44, 0x46], type: 'PDF document' },
+	{ bytes: [0x50, 0x4b, 0x03, 0x04], type: 'ZIP archive' },
+	{ bytes: [0x1f, 0x8b], type: 'gzip archive' },
+	{ bytes: [0x7f, 0x45, 0x4c, 0x46], type: 'ELF executable' },
+	{ bytes: [0xcf, 0xfa, 0xed, 0xfe], type: 'Mach-O executable' },
+	{ bytes: [0x4d, 0x5a], type: 'Windows executable' },
+	{ bytes: [0x00, 0x61, 0x73, 0x6d], type: 'WebAssembly module' },
+	{ bytes: [0x53, 0x51, 0x4c, 0x69, 0x74, 0x65], type: 'SQLite database' },
+];
+
+/**
+ * Whether a buffer looks binary: a NUL byte, or mostly control characters
+ * near the start (the same heuristic git and grep use)
+ */
+export function isBinaryContent(buffer: Buffer): boolean {
+	const sample = buffer.subarray(0, BINARY_SNIFF_BYTES);
+	if (sample.length === 0) {
+		return false;
+	}
+
+	let control = 0;
+	for (const byte of sample) {
+		if (byte === 0) {
+			return true;
+		}
+		// Control characters other than tab, newline, form feed and carriage return
+		if (byte < 0x20 && byte !== 0x09 && byte !== 0x0a && byte !== 0x0c && byte !== 0x0d) {
+			control++;
+		}
+	}
+	return control / sample.length > 0.1;
+}
+
+/**
+ * One-line description of a binary file, e.g. "PNG image, 1.2 MB"
+ */
+export function describeBinary(filePath: string, buffer: Buffer, size: number): string {
+	const match = SIGNATURES.find(sig => sig.bytes.every((byte, i) => buffer[i] === byte));
+	const ext = path.extname(filePath).slice(1).toLowerCase();
+	const type = match?.type ?? (ext ? `${ext} binary file` : 'binary file');
+	const head = buffer.subarray(0, 16).toString('hex').replace(/(..)/g, '$1 ').trim();
+	return `[Binary file: ${type}, ${formatBytes(size)}; contents not shown. First bytes: ${head}]`;
+}
+
+// ============================================================================
+// Pagination
+// ============================================================================
+
+export interface LinePage {
+	/** Selected lines joined with "\n" */
+	content: string;
+	/** 1-based number of the first line returned (0 when none) */
+	startLine: number;
+	/** 1-based number of the last line returned (0 when none) */
+	endLine: number;
+	/** Whether lines after endLine were left out */
+	truncated: boolean;
+}
+
+/**
+ * Select lines [offset, offset + limit), then cut at the byte budget
+ *
+ * Cuts happen on line boundaries; a single line longer than the budget is
+ * cut mid-line so at least something is returned.
+ */
+export function paginateLines(lines: string[], offset: number, limit: number, maxBytes: number): LinePage {
+	const selected = lines.slice(offset, offset + limit);
+
+	let bytes = 0;
+	let count = 0;
+	let content = '';
+	for (const line of selected) {
+		const lineBytes = Buffer.byteLength(line, 'utf-8') + (count > 0 ? 1 : 0);
+		if (bytes + lineBytes > maxBytes) {
+			if (count === 0) {
+				content = Buffer.from(line, 'utf-8').subarray(0, maxBytes).toString('utf-8');
+				count = 1;
+			}
+			break;
+		}
+		content += (count > 0 ? '\n' : '') + line;
+		bytes += lineBytes;
+		count++;
+	}
+
+	return {
+		content,
+		startLine: count > 0 ? offset + 1 : 0,
+		endLine: count > 0 ? offset + count : 0,
+		truncated: offset + count < lines.length,
+	};
+}
+
+/**
+ * Footer appended when a read was cut short
+ */
+export function formatReadFooter(page: LinePage, totalLines: number): string {
+	return `[Showing lines ${page.startLine}-${page.endLine} of ${totalLines.toLocaleString('en-US')}. Use offset ${page.endLine} to read more.]`;
+}
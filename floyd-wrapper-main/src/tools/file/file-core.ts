@@ -17,6 +17,10 @@ export async function readFile(filePath: string): Promise<{ success: boolean; co
 	}
 }
 
+export async function fileExists(filePath: string): Promise<boolean> {
+	return fs.pathExists(path.resolve(filePath));
+}
+
 export async function writeFile(filePath: string, content: string): Promise<{ success: boolean; bytesWritten?: number; error?: string }> {
 	try {
 		const resolved = path.resolve(filePath);
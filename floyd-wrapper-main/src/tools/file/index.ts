@@ -8,6 +8,7 @@ import { z } from 'zod';
 import type { ToolDefinition } from '../../types.js';
 import * as fileCore from './file-core.js';
 import { sanitizeFilePath } from '../../utils/security.js';
+import { getReadLimits, isBinaryContent, describeBinary, paginateLines, formatReadFooter } from './file-content.js';
 
 // ============================================================================
 // Read File Tool
@@ -15,12 +16,13 @@ import { sanitizeFilePath } from '../../utils/security.js';
 
 export const readFileTool: ToolDefinition = {
 	name: 'read_file',
-	description: 'Read file contents from disk',
+	description: 'Read file contents from disk. Long files are returned a page at a time (up to 2000 lines by default); use offset and limit to read further. Binary files are summarized instead of returned. Reading an unchanged file again returns a short stub; pass fresh: true to get the content again.',
 	category: 'file',
 	inputSchema: z.object({
 		file_path: z.string().min(1, 'File path is required'),
 		offset: z.number().int().min(0, 'Offset must be non-negative').optional(),
 		limit: z.number().int().positive('Limit must be positive').optional(),
+		fresh: z.boolean().optional(),
 	}),
 	permission: 'none',
 	execute: async (input) => {
@@ -86,28 +88,42 @@ export const readFileTool: ToolDefinition = {
 				};
 			}
 
-			// Read file content
-			const content = await fs.readFile(resolvedPath, 'utf-8');
-			const lines = content.split('\n');
+			// Binary files get a summary instead of raw bytes
+			const buffer = await fs.readFile(resolvedPath);
+			if (isBinaryContent(buffer)) {
+				return {
+					success: true,
+					data: {
+						content: describeBinary(resolvedPath, buffer, stat.size),
+						binary: true,
+						size: stat.size,
+						file_path
+					}
+				};
+			}
+
+			const lines = buffer.toString('utf-8').split('\n');
 			const lineCount = lines.length;
 
-			// Apply offset and limit
-			let resultLines = lines;
-			if (offset !== undefined) {
-				resultLines = resultLines.slice(offset);
-			}
-			if (limit !== undefined) {
-				resultLines = resultLines.slice(0, limit);
-			}
+			// Apply offset and limit, then the byte budget
+			const { maxLines, maxBytes } = getReadLimits();
+			const page = paginateLines(lines, offset ?? 0, limit ?? maxLines, maxBytes);
 
-			const resultContent = resultLines.join('\n');
+			// An explicit limit that ends the page is what the caller asked for;
+			// anything else cut short gets a footer saying how to continue
+			const requestedEnd = limit !== undefined ? (offset ?? 0) + limit : Infinity;
+			const cutShort = page.truncated && page.endLine < Math.min(requestedEnd, lineCount);
+			const resultContent = cutShort
+				? `${page.content}\n\n${formatReadFooter(page, lineCount)}`
+				: page.content;
 
 			return {
 				success: true,
 				data: {
 					content: resultContent,
 					lineCount,
-					file_path
+					file_path,
+					...(cutShort && { startLine: page.startLine, endLine: page.endLine, truncated: true })
 				}
 			};
 		} catch (error) {
@@ -129,11 +145,12 @@ export const readFileTool: ToolDefinition = {
 
 export const writeTool: ToolDefinition = {
 	name: 'write',
-	description: 'Create or overwrite files',
+	description: 'Create a file. Refuses to replace an existing file unless overwrite is true; prefer edit_file for changes to existing files.',
 	category: 'file',
 	inputSchema: z.object({
 		file_path: z.string().min(1, 'File path is required'),
 		content: z.string(),
+		overwrite: z.boolean().optional().default(false),
 	}),
 	permission: 'dangerous',
 	execute: async (input) => {
@@ -151,7 +168,7 @@ export const writeTool: ToolDefinition = {
 			};
 		}
 
-		const { file_path, content } = validationResult.data;
+		const { file_path, content, overwrite } = validationResult.data;
 
 		// Sanitize file path to prevent path traversal attacks
 		let sanitizedPath: string;
@@ -168,6 +185,19 @@ export const writeTool: ToolDefinition = {
 			};
 		}
 
+		// Replacing an existing file must be asked for explicitly
+		const existed = await fileCore.fileExists(sanitizedPath);
+		if (existed && !overwrite) {
+			return {
+				success: false,
+				error: {
+					code: 'FILE_EXISTS',
+					message: `File already exists: ${file_path}. Pass overwrite: true to replace it, or use edit_file to change it.`,
+					details: { file_path }
+				}
+			};
+		}
+
 		const result = await fileCore.writeFile(sanitizedPath, content);
 
 		if (result.success) {
@@ -175,7 +205,8 @@ export const writeTool: ToolDefinition = {
 				success: true,
 				data: {
 					file_path,
-					bytes_written: result.bytesWritten
+					bytes_written: result.bytesWritten,
+					action: existed ? 'modified' : 'created'
 				}
 			};
 		}
@@ -0,0 +1,88 @@
+/**
+ * JSON Edit Tool - Floyd Wrapper
+ *
+ * Structured edits of JSON and YAML files (package.json, tsconfig.json, CI
+ * pipelines, compose files) by path instead of string replacement, keeping
+ * the file's formatting (see structured-edit.ts)
+ */
+
+import { z } from 'zod';
+import fs from 'fs-extra';
+import type { ToolDefinition, ToolResult } from '../../types.js';
+import { applyStructuredEdits, type EditOperation } from './structured-edit.js';
+
+// ============================================================================
+// Zod Schema
+// ============================================================================
+
+const inputSchema = z.object({
+	file_path: z.string().min(1, 'File path is required'),
+	operations: z.array(z.object({
+		op: z.enum(['set', 'delete', 'append']),
+		path: z.string(),
+		value: z.unknown().optional(),
+	})).min(1, 'At least one operation is required'),
+	format: z.enum(['json', 'yaml']).optional(),
+});
+
+// ============================================================================
+// Tool Execution
+// ============================================================================
+
+async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult> {
+	const { file_path, operations, format } = input;
+
+	if (!await fs.pathExists(file_path)) {
+		return {
+			success: false,
+			error: {
+				code: 'FILE_NOT_FOUND',
+				message: `File not found: ${file_path}`,
+				details: { file_path },
+			},
+		};
+	}
+
+	try {
+		const before = await fs.readFile(file_path, 'utf-8');
+		const result = applyStructuredEdits(file_path, before, operations as EditOperation[], format);
+		await fs.writeFile(file_path, result.text, 'utf-8');
+
+		return {
+			success: true,
+			data: {
+				file_path,
+				format: result.format,
+				operations: operations.length,
+				formatPreserved: result.formatPreserved,
+				...(!result.formatPreserved && {
+					note: 'The edit could not be made in place; the YAML was rewritten and its comments and layout were lost',
+				}),
+			},
+		};
+	} catch (error) {
+		return {
+			success: false,
+			error: {
+				code: 'JSON_EDIT_ERROR',
+				message: (error as Error).message,
+				details: { file_path },
+			},
+		};
+	}
+}
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+export const jsonEditTool: ToolDefinition = {
+	name: 'json_edit',
+	description: 'Edit JSON and YAML files by path instead of string replacement. operations: [{op: "set"|"delete"|"append", path, value}] ' +
+		'with JSON Pointer ("/scripts/test", "/items/-") or JSONPath ("$.scripts.test", "$.items[0]") paths, applied in order. ' +
+		'set creates missing parent objects; append adds to an array. Indentation, key order and YAML comments are kept.',
+	category: 'file',
+	inputSchema,
+	permission: 'dangerous',
+	execute,
+} as ToolDefinition;
@@ -7,6 +7,7 @@
 
 import { z } from 'zod';
 import type { ToolDefinition, ToolResult } from '../../types.js';
+import { createIgnoreFilter } from '../../utils/ignore-files.js';
 
 // ============================================================================
 // Zod Schema
@@ -16,7 +17,9 @@ const inputSchema = z.object({
 	path: z.string().default('.'),
 	recursive: z.boolean().optional().default(false),
 	include_hidden: z.boolean().optional().default(false),
+	include_ignored: z.boolean().optional().default(false),
 	file_pattern: z.string().optional(),
+	fresh: z.boolean().optional(),
 });
 
 // ============================================================================
@@ -24,7 +27,7 @@ const inputSchema = z.object({
 // ============================================================================
 
 async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult> {
-	const { path, recursive, include_hidden, file_pattern } = input;
+	const { path, recursive, include_hidden, include_ignored, file_pattern } = input;
 
 	try {
 		const fsModule = await import('fs-extra');
@@ -57,6 +60,9 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 			};
 		}
 
+		// .gitignore / .floydignore rules, unless ignored files are wanted
+		const isIgnored = include_ignored ? null : await createIgnoreFilter(path);
+
 		// List directory contents
 		const entries: Array<{ name: string; type: 'file' | 'directory'; path: string; size?: number }> = [];
 
@@ -81,6 +87,11 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 				const itemStat = await fs.stat(itemPath);
 				const relativePath = pathModule.relative(path, itemPath);
 
+				// Skip ignored entries (and everything below an ignored directory)
+				if (isIgnored?.(relativePath, itemStat.isDirectory())) {
+					continue;
+				}
+
 				if (itemStat.isDirectory()) {
 					entries.push({
 						name: item,
@@ -131,7 +142,7 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 
 export const listDirectoryTool: ToolDefinition = {
 	name: 'list_directory',
-	description: 'List files and directories at a given path. Supports recursive listing and pattern filtering.',
+	description: 'List files and directories at a given path. Supports recursive listing and pattern filtering. Entries matched by .gitignore or .floydignore (and node_modules, .git) are left out unless include_ignored: true. Listing an unchanged directory again returns a short stub; pass fresh: true to list it again.',
 	category: 'file',
 	inputSchema,
 	permission: 'none',
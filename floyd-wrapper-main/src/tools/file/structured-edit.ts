@@ -0,0 +1,587 @@
+/**
+ * Structured Edit - Floyd Wrapper
+ *
+ * Path-based edits (set, delete, append) of JSON and YAML documents for the
+ * json_edit tool. Paths are JSON Pointers ("/scripts/test", "/items/-") or
+ * simple JSONPath expressions ("$.scripts.test", "$.items[0]",
+ * "$['key.with.dots']").
+ *
+ * JSON is re-serialized with the file's own indentation, line endings and
+ * final newline. YAML is edited in place, line by line, so comments and
+ * layout elsewhere in the file survive; every edit is checked by parsing the
+ * result, and when an edit can't be made in place (flow style, anchors,
+ * unusual layout) the whole document is dumped again instead and the result
+ * says the formatting was not preserved.
+ */
+
+import path from 'node:path';
+import { isDeepStrictEqual } from 'node:util';
+import yaml from 'js-yaml';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One change to a document
+ */
+export interface EditOperation {
+	op: 'set' | 'delete' | 'append';
+	path: string;
+	value?: unknown;
+}
+
+export type StructuredFormat = 'json' | 'yaml';
+
+/**
+ * An edited document
+ */
+export interface StructuredEditResult {
+	text: string;
+	format: StructuredFormat;
+	/** False when YAML had to be re-dumped, losing comments and layout */
+	formatPreserved: boolean;
+}
+
+type Container = Record<string, unknown> | unknown[];
+
+// ============================================================================
+// Paths
+// ============================================================================
+
+/**
+ * Segments of a JSON Pointer or simple JSONPath expression
+ *
+ * @throws Error for unsupported syntax (wildcards, filters, recursion)
+ */
+export function parsePath(expression: string): string[] {
+	if (expression === '' || expression === '$') {
+		return [];
+	}
+	if (expression.startsWith('/')) {
+		return expression.slice(1).split('/').map(segment => segment.replace(/~1/g, '/').replace(/~0/g, '~'));
+	}
+	if (!expression.startsWith('$')) {
+		throw new Error(`Invalid path "${expression}": use a JSON Pointer (/a/b/0) or JSONPath ($.a.b[0])`);
+	}
+
+	const segments: string[] = [];
+	const token = /\.([A-Za-z_$][\w$-]*)|\[(\d+|-)\]|\[(['"])((?:(?!\3).)*)\3\]/y;
+	let index = 1;
+	while (index < expression.length) {
+		token.lastIndex = index;
+		const match = token.exec(expression);
+		if (!match) {
+			throw new Error(`Unsupported JSONPath "${expression}": only $.key, $[0] and $['key'] steps are supported`);
+		}
+		segments.push(match[1] ?? match[2] ?? match[4]);
+		index = token.lastIndex;
+	}
+	return segments;
+}
+
+function isContainer(value: unknown): value is Container {
+	return typeof value === 'object' && value !== null;
+}
+
+function arrayIndex(segment: string, array: unknown[], allowEnd: boolean): number | null {
+	if (segment === '-') {
+		return allowEnd ? array.length : null;
+	}
+	if (!/^\d+$/.test(segment)) {
+		return null;
+	}
+	const index = Number(segment);
+	return index < array.length || (allowEnd && index === array.length) ? index : null;
+}
+
+function getChild(container: Container, segment: string): unknown {
+	if (Array.isArray(container)) {
+		const index = arrayIndex(segment, container, false);
+		return index === null ? undefined : container[index];
+	}
+	return Object.prototype.hasOwnProperty.call(container, segment) ? container[segment] : undefined;
+}
+
+/**
+ * Value at a path, or undefined
+ */
+export function getAtPath(document: unknown, segments: string[]): unknown {
+	let current = document;
+	for (const segment of segments) {
+		if (!isContainer(current)) {
+			return undefined;
+		}
+		current = getChild(current, segment);
+	}
+	return current;
+}
+
+// ============================================================================
+// Operations
+// ============================================================================
+
+/**
+ * Apply an operation to a copy of a document
+ *
+ * set creates missing parent objects; append creates a missing array.
+ *
+ * @throws Error when the path can't be edited as asked
+ */
+export function applyOperation(document: unknown, operation: EditOperation): unknown {
+	const segments = parsePath(operation.path);
+	const value = structuredClone(operation.value);
+	// An empty document (e.g. an empty YAML file) becomes the container
+	const root = document === undefined || document === null
+		? (segments[0] === '-' || /^\d+$/.test(segments[0] ?? '') ? [] : {})
+		: structuredClone(document);
+
+	if (operation.op === 'append') {
+		const target = getAtPath(root, segments);
+		if (target === undefined) {
+			return applyOperation(root, { op: 'set', path: operation.path, value: [value] });
+		}
+		if (!Array.isArray(target)) {
+			throw new Error(`Cannot append to ${operation.path}: not an array`);
+		}
+		target.push(value);
+		return root;
+	}
+
+	if (segments.length === 0) {
+		if (operation.op === 'delete') {
+			throw new Error('Cannot delete the whole document');
+		}
+		return value;
+	}
+
+	let container: unknown = root;
+	for (const [depth, segment] of segments.slice(0, -1).entries()) {
+		if (!isContainer(container)) {
+			throw new Error(`Cannot edit ${operation.path}: /${segments.slice(0, depth).join('/')} is not an object or array`);
+		}
+		let child = getChild(container, segment);
+		if (child === undefined) {
+			if (operation.op === 'delete') {
+				throw new Error(`${operation.path} does not exist`);
+			}
+			child = segments[depth + 1] === '-' ? [] : {};
+			if (Array.isArray(container)) {
+				const index = arrayIndex(segment, container, true);
+				if (index === null) {
+					throw new Error(`Cannot edit ${operation.path}: index ${segment} is out of range`);
+				}
+				container[index] = child;
+			} else {
+				container[segment] = child;
+			}
+		}
+		container = child;
+	}
+
+	const last = segments[segments.length - 1];
+	if (!isContainer(container)) {
+		throw new Error(`Cannot edit ${operation.path}: parent is not an object or array`);
+	}
+
+	if (Array.isArray(container)) {
+		const index = arrayIndex(last, container, operation.op === 'set');
+		if (index === null) {
+			throw new Error(operation.op === 'delete' ? `${operation.path} does not exist` : `Cannot set ${operation.path}: index ${last} is out of range`);
+		}
+		if (operation.op === 'delete') {
+			container.splice(index, 1);
+		} else {
+			container[index] = value;
+		}
+		return root;
+	}
+
+	if (operation.op === 'delete') {
+		if (!Object.prototype.hasOwnProperty.call(container, last)) {
+			throw new Error(`${operation.path} does not exist`);
+		}
+		delete container[last];
+	} else {
+		container[last] = value;
+	}
+	return root;
+}
+
+// ============================================================================
+// JSON
+// ============================================================================
+
+/**
+ * Apply operations to JSON text, keeping its indentation, line endings and
+ * final newline
+ */
+export function editJsonText(text: string, operations: EditOperation[]): string {
+	const document = operations.reduce<unknown>(applyOperation, JSON.parse(text));
+
+	const eol = text.includes('\r\n') ? '\r\n' : '\n';
+	const indent = text.trim().includes('\n') ? /\n([ \t]+)\S/.exec(text)?.[1] ?? '  ' : undefined;
+	const finalNewline = /\r?\n$/.test(text) ? eol : '';
+	return JSON.stringify(document, null, indent).replace(/\n/g, eol) + finalNewline;
+}
+
+// ============================================================================
+// YAML
+// ============================================================================
+
+/**
+ * A mapping entry or sequence item in a YAML file
+ */
+interface YamlEntry {
+	/** Unquoted key (mapping entries only) */
+	key?: string;
+	/** Line of the key or dash */
+	line: number;
+	/** Column of the key or dash */
+	indent: number;
+	/** Column where the value starts on that line */
+	valueColumn: number;
+	/** Exclusive end line of the entry (after its last content line) */
+	end: number;
+	/** Value on the key/dash line, without comment */
+	inline: string;
+}
+
+interface YamlEntries {
+	kind: 'map' | 'seq';
+	indent: number;
+	entries: YamlEntry[];
+}
+
+const DASH_PATTERN = /^(\s*)-(?=\s|$)/;
+const KEY_PATTERN = /^(\s*)("(?:[^"\\]|\\.)*"|'(?:[^']|'')*'|[^\s#'"][^#]*?)\s*:(?=\s|$)/;
+
+function isYamlContent(line: string): boolean {
+	const trimmed = line.trim();
+	return trimmed !== '' && !trimmed.startsWith('#') && trimmed !== '---' && trimmed !== '...';
+}
+
+function indentOf(line: string): number {
+	return line.length - line.trimStart().length;
+}
+
+/**
+ * Split a value from a trailing comment (a # after whitespace, outside quotes)
+ */
+function splitComment(text: string): { value: string; comment: string } {
+	let quote: string | null = null;
+	for (let i = 0; i < text.length; i++) {
+		const char = text[i];
+		if (quote) {
+			if (char === quote) {
+				quote = null;
+			}
+		} else if (char === '"' || char === '\'') {
+			quote = char;
+		} else if (char === '#' && (i === 0 || /\s/.test(text[i - 1]))) {
+			return { value: text.slice(0, i).trim(), comment: text.slice(i) };
+		}
+	}
+	return { value: text.trim(), comment: '' };
+}
+
+function unquoteKey(key: string): string {
+	if (key.startsWith('"')) {
+		return JSON.parse(key) as string;
+	}
+	if (key.startsWith('\'')) {
+		return key.slice(1, -1).replace(/''/g, '\'');
+	}
+	return key.trim();
+}
+
+/**
+ * Entries of the block mapping or sequence in lines [from, to)
+ */
+function listEntries(view: string[], from: number, to: number): YamlEntries | null {
+	let first = from;
+	while (first < to && !isYamlContent(view[first])) {
+		first++;
+	}
+	if (first === to) {
+		return null;
+	}
+
+	const indent = indentOf(view[first]);
+	const kind = DASH_PATTERN.test(view[first]) ? 'seq' : 'map';
+	const entries: YamlEntry[] = [];
+
+	for (let line = first; line < to; line++) {
+		if (!isYamlContent(view[line]) || indentOf(view[line]) !== indent) {
+			continue;
+		}
+		if (kind === 'map' && DASH_PATTERN.test(view[line])) {
+			// A sequence at the key's indentation belongs to the key
+			continue;
+		}
+
+		let entry: YamlEntry;
+		if (kind === 'seq') {
+			if (!DASH_PATTERN.test(view[line])) {
+				return null;
+			}
+			const valueColumn = Math.min(indent + 2, view[line].length);
+			entry = { line, indent, valueColumn, end: line + 1, inline: splitComment(view[line].slice(valueColumn)).value };
+		} else {
+			const match = KEY_PATTERN.exec(view[line]);
+			if (!match) {
+				return null;
+			}
+			const valueColumn = match[0].length;
+			entry = {
+				key: unquoteKey(match[2]),
+				line,
+				indent,
+				valueColumn,
+				end: line + 1,
+				inline: splitComment(view[line].slice(valueColumn)).value,
+			};
+		}
+
+		for (let next = line + 1; next < to; next++) {
+			if (!isYamlContent(view[next])) {
+				continue;
+			}
+			const nextIndent = indentOf(view[next]);
+			const ownsSequence = kind === 'map' && nextIndent === indent && DASH_PATTERN.test(view[next]);
+			if (nextIndent < indent || (nextIndent === indent && !ownsSequence)) {
+				break;
+			}
+			entry.end = next + 1;
+		}
+		entries.push(entry);
+	}
+
+	return { kind, indent, entries };
+}
+
+/**
+ * Lines holding an entry's block value; a sequence item that starts a
+ * mapping or sequence on its dash line is read with the dash blanked out
+ */
+function valueRange(view: string[], entry: YamlEntry): { from: number; to: number } | null {
+	if (!entry.inline) {
+		return { from: entry.line + 1, to: entry.end };
+	}
+	if (entry.key === undefined && (KEY_PATTERN.test(entry.inline) || DASH_PATTERN.test(entry.inline))) {
+		view[entry.line] = ' '.repeat(entry.indent + 1) + view[entry.line].slice(entry.indent + 1);
+		return { from: entry.line, to: entry.end };
+	}
+	return null;
+}
+
+/**
+ * Entries of the container at a path (the whole document for []); the view
+ * may have dashes blanked out
+ */
+function findContainer(view: string[], segments: string[]): YamlEntries | null {
+	let range: { from: number; to: number } | null = { from: 0, to: view.length };
+
+	for (const segment of segments) {
+		const container = listEntries(view, range.from, range.to);
+		const entry = container && findEntry(container, segment);
+		range = entry ? valueRange(view, entry) : null;
+		if (!range) {
+			return null;
+		}
+	}
+
+	return listEntries(view, range.from, range.to);
+}
+
+function findEntry(container: YamlEntries, segment: string): YamlEntry | undefined {
+	if (container.kind === 'seq') {
+		return /^\d+$/.test(segment) ? container.entries[Number(segment)] : undefined;
+	}
+	return container.entries.find(entry => entry.key === segment);
+}
+
+/**
+ * Lines of a dumped value
+ */
+function dumpLines(value: unknown): string[] {
+	return yaml.dump(value, { lineWidth: -1, noRefs: true }).replace(/\n$/, '').split('\n');
+}
+
+/**
+ * Lines for a mapping entry or sequence item at an indentation
+ */
+function renderEntry(prefix: string, indent: number, value: unknown, isItem: boolean): string[] {
+	const lines = dumpLines(value);
+	const pad = (count: number) => (line: string) => ' '.repeat(count) + line;
+	const isBlock = isContainer(value) && Object.keys(value).length > 0;
+
+	if (isBlock && !isItem) {
+		return [prefix, ...lines.map(pad(indent + 2))];
+	}
+	if (isBlock) {
+		return [`${prefix} ${lines[0]}`, ...lines.slice(1).map(pad(indent + 2))];
+	}
+	// Scalars; block scalars (|-) keep the dump's own 2-space indent
+	return [`${prefix} ${lines[0]}`, ...lines.slice(1).map(pad(indent))];
+}
+
+function keyPrefix(key: string, indent: number): string {
+	return `${' '.repeat(indent)}${yaml.dump(key, { lineWidth: -1 }).trim()}:`;
+}
+
+/**
+ * Apply one operation to YAML lines in place; null when it can't be done
+ * without rewriting the document
+ */
+function editYamlLines(lines: string[], document: unknown, operation: EditOperation): string[] | null {
+	const segments = parsePath(operation.path);
+	if (segments.length === 0 && operation.op !== 'append') {
+		return null;
+	}
+
+	if (operation.op === 'append') {
+		if (getAtPath(document, segments) === undefined) {
+			return editYamlLines(lines, document, { op: 'set', path: operation.path, value: [operation.value] });
+		}
+		const container = findContainer([...lines], segments);
+		if (!container || container.kind !== 'seq') {
+			return null;
+		}
+		const last = container.entries[container.entries.length - 1];
+		const item = renderEntry(`${' '.repeat(container.indent)}-`, container.indent, operation.value, true);
+		return [...lines.slice(0, last.end), ...item, ...lines.slice(last.end)];
+	}
+
+	const view = [...lines];
+	const parent = findContainer(view, segments.slice(0, -1));
+	const entry = parent && findEntry(parent, segments[segments.length - 1]);
+
+	if (operation.op === 'delete') {
+		if (!entry) {
+			return null;
+		}
+		// A key on a sequence item's dash line hands the dash to the next key
+		const next = parent.entries[parent.entries.indexOf(entry) + 1];
+		if (view[entry.line] !== lines[entry.line]) {
+			if (!next) {
+				return null;
+			}
+			const merged = lines[entry.line].slice(0, entry.indent) + lines[next.line].slice(entry.indent);
+			return [...lines.slice(0, entry.line), merged, ...lines.slice(next.line + 1)];
+		}
+		return [...lines.slice(0, entry.line), ...lines.slice(entry.end)];
+	}
+
+	if (entry) {
+		const line = lines[entry.line];
+		const { comment } = splitComment(line.slice(entry.valueColumn));
+		const replacement = renderEntry(line.slice(0, entry.valueColumn).trimEnd(), entry.indent, operation.value, entry.key === undefined);
+		if (comment && replacement.length === 1) {
+			replacement[0] += ` ${comment}`;
+		}
+		return [...lines.slice(0, entry.line), ...replacement, ...lines.slice(entry.end)];
+	}
+
+	// Missing: insert under the deepest existing container, creating parents
+	for (let depth = segments.length - 1; depth >= 0; depth--) {
+		const existing = depth === 0 && (document === undefined || document === null) ? {} : getAtPath(document, segments.slice(0, depth));
+		if (!isContainer(existing)) {
+			continue;
+		}
+		const container = findContainer([...lines], segments.slice(0, depth));
+		const value = segments.slice(depth + 1).reduceRight<unknown>(
+			(nested, segment) => (segment === '-' ? [nested] : { [segment]: nested }),
+			operation.value
+		);
+
+		if (!container) {
+			// Only an empty document can be extended from nothing
+			if (depth > 0 || Array.isArray(existing) || Object.keys(existing).length > 0) {
+				return null;
+			}
+			const end = lines.length > 0 && lines[lines.length - 1] === '' ? lines.length - 1 : lines.length;
+			return [...lines.slice(0, end), ...renderEntry(keyPrefix(segments[0], 0), 0, value, false), ...lines.slice(end)];
+		}
+
+		const last = container.entries[container.entries.length - 1];
+		const rendered = container.kind === 'seq'
+			? renderEntry(`${' '.repeat(container.indent)}-`, container.indent, value, true)
+			: renderEntry(keyPrefix(segments[depth], container.indent), container.indent, value, false);
+		return [...lines.slice(0, last.end), ...rendered, ...lines.slice(last.end)];
+	}
+
+	return null;
+}
+
+/**
+ * Apply operations to YAML text, in place where possible
+ */
+export function editYamlText(text: string, operations: EditOperation[]): { text: string; formatPreserved: boolean } {
+	let document: unknown;
+	try {
+		document = yaml.load(text);
+	} catch (error) {
+		throw new Error(`Invalid YAML: ${(error as Error).message}`);
+	}
+
+	const eol = text.includes('\r\n') ? '\r\n' : '\n';
+	let lines: string[] | null = text.split(/\r?\n/);
+
+	for (const operation of operations) {
+		const next = applyOperation(document, operation);
+		lines = lines && editYamlLines(lines, document, operation);
+		document = next;
+	}
+
+	if (lines) {
+		const edited = lines.join(eol);
+		try {
+			if (isDeepStrictEqual(yaml.load(edited), document)) {
+				return { text: edited, formatPreserved: true };
+			}
+		} catch {
+			// Fall through to a full dump
+		}
+	}
+
+	return { text: yaml.dump(document, { lineWidth: -1, noRefs: true }).replace(/\n/g, eol), formatPreserved: false };
+}
+
+// ============================================================================
+// Files
+// ============================================================================
+
+/**
+ * Format of a file by extension
+ */
+export function detectStructuredFormat(filePath: string): StructuredFormat | null {
+	const extension = path.extname(filePath).toLowerCase();
+	if (extension === '.json') {
+		return 'json';
+	}
+	if (extension === '.yaml' || extension === '.yml') {
+		return 'yaml';
+	}
+	return null;
+}
+
+/**
+ * Apply operations to the text of a JSON or YAML file
+ *
+ * @throws Error for unsupported files, invalid documents and paths
+ */
+export function applyStructuredEdits(
+	filePath: string,
+	text: string,
+	operations: EditOperation[],
+	format: StructuredFormat | null = detectStructuredFormat(filePath)
+): StructuredEditResult {
+	if (format === 'json') {
+		return { text: editJsonText(text, operations), format, formatPreserved: true };
+	}
+	if (format === 'yaml') {
+		return { ...editYamlText(text, operations), format };
+	}
+	throw new Error(`Unsupported file type: ${path.basename(filePath)} (expected .json, .yaml or .yml)`);
+}
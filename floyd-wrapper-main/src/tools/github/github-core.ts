@@ -0,0 +1,244 @@
+/**
+ * GitHub Core - Floyd Wrapper
+ *
+ * The GitHub side of the PR-ready workflow: fetch an issue as the task
+ * description, name a branch for it, and open a pull request whose body is
+ * built from .floyd/progress.md and the .floyd/branch.md checklist.
+ *
+ * The token comes from FLOYD_GITHUB_TOKEN, GITHUB_TOKEN or github_token in
+ * ~/.floyd/config.toml; FLOYD_GITHUB_API_URL points at GitHub Enterprise.
+ */
+
+import type { ProgressEntry } from 'floyd-agent-core/utils';
+import type { BranchState } from '../../persistence/branch-notes.js';
+import { loadUserConfig, type UserConfig } from '../../utils/user-config.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export interface GitHubRepo {
+	owner: string;
+	repo: string;
+}
+
+export interface GitHubIssue {
+	number: number;
+	title: string;
+	body: string;
+	state: string;
+	url: string;
+	labels: string[];
+	comments: Array<{ author: string; body: string }>;
+}
+
+export interface GitHubPullRequest {
+	number: number;
+	url: string;
+	draft: boolean;
+}
+
+export interface PullRequestInput {
+	title: string;
+	head: string;
+	base: string;
+	body: string;
+	draft?: boolean;
+}
+
+type FetchLike = (url: string, init: { method: string; headers: Record<string, string>; body?: string }) => Promise<{
+	ok: boolean;
+	status: number;
+	json(): Promise<any>;
+}>;
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+const DEFAULT_API_URL = 'https://api.github.com';
+
+/**
+ * Progress entries put in a PR body when the branch start is unknown
+ */
+const DEFAULT_PROGRESS_ENTRIES = 10;
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * GitHub token from the environment or the user config
+ */
+export function getGitHubToken(config: UserConfig | null = loadUserConfig()): string | undefined {
+	return process.env.FLOYD_GITHUB_TOKEN || process.env.GITHUB_TOKEN || config?.githubToken || undefined;
+}
+
+/**
+ * Owner and repository of a GitHub remote URL (https, ssh or scp-style)
+ */
+export function parseGitHubRemote(url: string): GitHubRepo | null {
+	const match = url.trim().match(/^(?:https?:\/\/(?:[^@/]+@)?|ssh:\/\/git@|git@)[^/:]+[/:]([^/]+)\/([^/]+?)(?:\.git)?\/?$/);
+	return match ? { owner: match[1], repo: match[2] } : null;
+}
+
+/**
+ * Branch name for work on an issue, e.g. issue-42-fix-date-parsing
+ */
+export function issueBranchName(issue: Pick<GitHubIssue, 'number' | 'title'>): string {
+	const slug = issue.title.toLowerCase().replace(/[^a-z0-9]+/g, '-').replace(/^-|-$/g, '').slice(0, 40).replace(/-$/, '');
+	return slug ? `issue-${issue.number}-${slug}` : `issue-${issue.number}`;
+}
+
+/**
+ * An issue written up as a task description for the model
+ */
+export function formatIssueTask(issue: GitHubIssue): string {
+	const lines = [`# Issue #${issue.number}: ${issue.title}`, '', issue.url];
+	if (issue.labels.length > 0) {
+		lines.push(`Labels: ${issue.labels.join(', ')}`);
+	}
+	lines.push('', issue.body.trim() || '(no description)');
+	if (issue.comments.length > 0) {
+		lines.push('', '## Comments');
+		for (const comment of issue.comments) {
+			lines.push('', `**${comment.author}:**`, comment.body.trim());
+		}
+	}
+	return lines.join('\n') + '\n';
+}
+
+/**
+ * Progress entries logged since a branch was started
+ *
+ * @param since - "YYYY-MM-DD HH:MM:SS" of the branch's first commit; the
+ *   latest entries are used when unknown
+ */
+export function progressSince(entries: ProgressEntry[], since?: string): ProgressEntry[] {
+	if (!since) {
+		return entries.slice(-DEFAULT_PROGRESS_ENTRIES);
+	}
+	return entries.filter(entry => entry.timestamp >= since);
+}
+
+/**
+ * Pull request body from commits, progress log entries and the branch checklist
+ */
+export function renderPrBody(options: {
+	commits: string[];
+	progress: ProgressEntry[];
+	state: BranchState;
+	issue?: number;
+}): string {
+	const lines = ['## Summary', ''];
+	lines.push(...(options.commits.length > 0 ? options.commits.map(c => `- ${c}`) : ['- (no commits yet)']));
+
+	if (options.progress.length > 0) {
+		lines.push('', '## Progress', '');
+		for (const entry of options.progress) {
+			const next = entry.next && entry.next !== '-' ? ` (next: ${entry.next})` : '';
+			lines.push(`- ${entry.action}: ${entry.result}${next}`);
+		}
+	}
+
+	if (options.state.checklist.length > 0) {
+		lines.push('', '## Checklist', '');
+		lines.push(...options.state.checklist.map(item => `- [${item.done ? 'x' : ' '}] ${item.label}`));
+	}
+
+	if (options.issue) {
+		lines.push('', `Closes #${options.issue}`);
+	}
+
+	return lines.join('\n') + '\n';
+}
+
+/**
+ * Issue number in a branch made by issueBranchName, if any
+ */
+export function issueFromBranch(branch: string): number | undefined {
+	const match = branch.match(/(?:^|\/)issue-(\d+)(?:-|$)/);
+	return match ? parseInt(match[1], 10) : undefined;
+}
+
+// ============================================================================
+// GitHub Client
+// ============================================================================
+
+/**
+ * Minimal GitHub REST client for issues and pull requests
+ */
+export class GitHubClient {
+	private readonly token: string;
+	private readonly apiUrl: string;
+	private readonly fetchImpl: FetchLike;
+
+	constructor(token: string, options: { apiUrl?: string; fetch?: FetchLike } = {}) {
+		this.token = token;
+		this.apiUrl = (options.apiUrl ?? process.env.FLOYD_GITHUB_API_URL ?? DEFAULT_API_URL).replace(/\/$/, '');
+		this.fetchImpl = options.fetch ?? (globalThis.fetch as unknown as FetchLike);
+	}
+
+	/**
+	 * An issue with its comments
+	 */
+	async getIssue(repo: GitHubRepo, number: number): Promise<GitHubIssue> {
+		const issue = await this.request('GET', `/repos/${repo.owner}/${repo.repo}/issues/${number}`);
+		if (issue.pull_request) {
+			throw new Error(`#${number} is a pull request, not an issue`);
+		}
+		const comments = issue.comments > 0
+			? await this.request('GET', `/repos/${repo.owner}/${repo.repo}/issues/${number}/comments?per_page=50`)
+			: [];
+
+		return {
+			number: issue.number,
+			title: issue.title,
+			body: issue.body ?? '',
+			state: issue.state,
+			url: issue.html_url,
+			labels: (issue.labels ?? []).map((label: string | { name: string }) => typeof label === 'string' ? label : label.name),
+			comments: comments.map((comment: { user?: { login: string }; body?: string }) => ({
+				author: comment.user?.login ?? 'unknown',
+				body: comment.body ?? '',
+			})),
+		};
+	}
+
+	/**
+	 * Open a pull request
+	 */
+	async createPullRequest(repo: GitHubRepo, input: PullRequestInput): Promise<GitHubPullRequest> {
+		const pr = await this.request('POST', `/repos/${repo.owner}/${repo.repo}/pulls`, {
+			title: input.title,
+			head: input.head,
+			base: input.base,
+			body: input.body,
+			draft: input.draft ?? false,
+		});
+		return { number: pr.number, url: pr.html_url, draft: Boolean(pr.draft) };
+	}
+
+	private async request(method: string, route: string, body?: unknown): Promise<any> {
+		const response = await this.fetchImpl(`${this.apiUrl}${route}`, {
+			method,
+			headers: {
+				Accept: 'application/vnd.github+json',
+				Authorization: `Bearer ${this.token}`,
+				'X-GitHub-Api-Version': '2022-11-28',
+				'User-Agent': 'floyd-wrapper',
+				...(body !== undefined ? { 'Content-Type': 'application/json' } : {}),
+			},
+			body: body !== undefined ? JSON.stringify(body) : undefined,
+		});
+
+		const data = await response.json().catch(() => ({}));
+		if (!response.ok) {
+			const details = Array.isArray(data?.errors)
+				? `: ${data.errors.map((e: { message?: string; code?: string }) => e.message ?? e.code).join('; ')}`
+				: '';
+			throw new Error(`GitHub ${method} ${route} failed (${response.status}): ${data?.message ?? 'unknown error'}${details}`);
+		}
+		return data;
+	}
+}
@@ -0,0 +1,170 @@
+/**
+ * GitHub Tools - Floyd Wrapper
+ *
+ * github_issue turns an issue into the task description (optionally on a
+ * fresh issue branch); github_pr pushes the current branch and opens a pull
+ * request described from .floyd/progress.md and the branch checklist.
+ */
+
+import { z } from 'zod';
+import { readProgress } from 'floyd-agent-core/utils';
+import type { ToolDefinition, ToolResult } from '../../types.js';
+import { getGitInstance, isProtectedBranch } from '../git/git-core.js';
+import { BranchNotes } from '../../persistence/branch-notes.js';
+import {
+	GitHubClient,
+	getGitHubToken,
+	parseGitHubRemote,
+	issueBranchName,
+	issueFromBranch,
+	formatIssueTask,
+	progressSince,
+	renderPrBody,
+	type GitHubRepo,
+} from './github-core.js';
+
+function failure(code: string, message: string, details?: unknown): ToolResult {
+	return { success: false, error: { code, message, details } };
+}
+
+/**
+ * Token and repository for a working copy, or the error to report
+ */
+async function connect(cwd: string, remote: string): Promise<{ client: GitHubClient; repo: GitHubRepo } | ToolResult> {
+	const token = getGitHubToken();
+	if (!token) {
+		return failure('GITHUB_NO_TOKEN', 'No GitHub token. Set FLOYD_GITHUB_TOKEN (or GITHUB_TOKEN), or github_token in ~/.floyd/config.toml');
+	}
+
+	let url: string;
+	try {
+		url = (await getGitInstance(cwd).remote(['get-url', remote]) || '').trim();
+	} catch (error) {
+		return failure('GITHUB_NO_REMOTE', `No git remote "${remote}": ${(error as Error).message}`, { repoPath: cwd });
+	}
+
+	const repo = parseGitHubRemote(url);
+	if (!repo) {
+		return failure('GITHUB_NO_REMOTE', `Remote "${remote}" is not a GitHub repository: ${url}`, { repoPath: cwd });
+	}
+	return { client: new GitHubClient(token), repo };
+}
+
+// ============================================================================
+// github_issue
+// ============================================================================
+
+const issueSchema = z.object({
+	number: z.number().int().positive(),
+	create_branch: z.boolean().optional().default(false),
+	remote: z.string().optional().default('origin'),
+	repoPath: z.string().optional(),
+});
+
+export const githubIssueTool: ToolDefinition = {
+	name: 'github_issue',
+	description: 'Fetch a GitHub issue (title, body, labels, comments) of this repository to use as the task description. With create_branch, also create and switch to an issue-<number>-<slug> branch for the work.',
+	category: 'git',
+	inputSchema: issueSchema,
+	permission: 'moderate',
+	execute: async (input) => {
+		const { number, create_branch, remote, repoPath } = input as z.infer<typeof issueSchema>;
+		const cwd = repoPath || process.cwd();
+
+		const connection = await connect(cwd, remote);
+		if (!('client' in connection)) {
+			return connection;
+		}
+
+		try {
+			const issue = await connection.client.getIssue(connection.repo, number);
+			let branch: string | undefined;
+			if (create_branch) {
+				branch = issueBranchName(issue);
+				await getGitInstance(cwd).checkoutLocalBranch(branch);
+				await new BranchNotes(cwd).recordBranch();
+			}
+
+			return {
+				success: true,
+				data: {
+					number: issue.number,
+					state: issue.state,
+					url: issue.url,
+					branch,
+					task: formatIssueTask(issue),
+				},
+			};
+		} catch (error) {
+			return failure('GITHUB_ISSUE_ERROR', (error as Error).message, { number, repo: connection.repo });
+		}
+	},
+} as ToolDefinition;
+
+// ============================================================================
+// github_pr
+// ============================================================================
+
+const prSchema = z.object({
+	title: z.string().min(1),
+	base: z.string().optional(),
+	draft: z.boolean().optional().default(false),
+	remote: z.string().optional().default('origin'),
+	repoPath: z.string().optional(),
+});
+
+export const githubPrTool: ToolDefinition = {
+	name: 'github_pr',
+	description: 'Push the current branch and open a GitHub pull request. The body is built from the branch commits, .floyd/progress.md and the .floyd/branch.md PR checklist; issue branches get "Closes #n". Run tests and lint first so the checklist is ticked.',
+	category: 'git',
+	inputSchema: prSchema,
+	permission: 'dangerous',
+	execute: async (input) => {
+		const { title, base: baseInput, draft, remote, repoPath } = input as z.infer<typeof prSchema>;
+		const cwd = repoPath || process.cwd();
+		const git = getGitInstance(cwd);
+
+		const connection = await connect(cwd, remote);
+		if (!('client' in connection)) {
+			return connection;
+		}
+
+		try {
+			const branch = (await git.revparse(['--abbrev-ref', 'HEAD'])).trim();
+			if (branch === 'HEAD') {
+				return failure('GITHUB_PR_ERROR', 'HEAD is detached; check out a branch first');
+			}
+			if (isProtectedBranch(branch)) {
+				return failure('GITHUB_PROTECTED_BRANCH', `Refusing to open a PR from "${branch}"; create a working branch first`, { branch });
+			}
+
+			const notes = new BranchNotes(cwd);
+			await notes.recordBranch();
+			const state = await notes.read() ?? { checklist: [] };
+			const base = baseInput ?? state.base ?? 'main';
+
+			const log = (await git.raw(['log', '--reverse', '--format=%ci%x09%s', `${base}..${branch}`])).trim();
+			const commits = log ? log.split('\n').map(line => line.split('\t')) : [];
+			const since = commits[0]?.[0]?.slice(0, 19);
+
+			const body = renderPrBody({
+				commits: commits.map(([, subject]) => subject),
+				progress: progressSince(readProgress(cwd), since),
+				state,
+				issue: issueFromBranch(branch),
+			});
+
+			await git.push(['-u', remote, branch]);
+			const pr = await connection.client.createPullRequest(connection.repo, { title, head: branch, base, body, draft });
+			await notes.setStatus('ready for review');
+
+			const open = state.checklist.filter(item => !item.done).map(item => item.label);
+			return {
+				success: true,
+				data: { number: pr.number, url: pr.url, draft: pr.draft, branch, base, openChecklistItems: open },
+			};
+		} catch (error) {
+			return failure('GITHUB_PR_ERROR', (error as Error).message, { repo: connection.repo });
+		}
+	},
+} as ToolDefinition;
@@ -6,6 +6,7 @@
  */
 
 import { toolRegistry } from './tool-registry.js';
+import { logger } from '../utils/logger.js';
 
 // ============================================================================
 // Import all tools
@@ -22,6 +23,10 @@ import { gitBranchTool } from './git/branch.js';
 import { isProtectedBranchTool } from './git/is-protected.js';
 import { gitMergeTool } from './git/merge.js';
 
+// GitHub tools
+import { githubIssueTool, githubPrTool } from './github/index.js';
+import { dbQueryTool } from './database/index.js';
+
 // Cache tools
 import { cacheStoreTool, cacheRetrieveTool, cacheDeleteTool, cacheClearTool, cacheListTool, cacheSearchTool, cacheStatsTool, cachePruneTool, cacheStorePatternTool, cacheStoreReasoningTool, cacheLoadReasoningTool, cacheArchiveReasoningTool } from './cache/index.js';
 
@@ -30,19 +35,34 @@ import { readFileTool, writeTool, editFileTool, searchReplaceTool } from './file
 import { listDirectoryTool } from './file/list-directory.js';
 import { deleteFileTool } from './file/delete-file.js';
 import { moveFileTool } from './file/move-file.js';
+import { jsonEditTool } from './file/json-edit.js';
 
 // Search tools
-import { grepTool, codebaseSearchTool } from './search/index.js';
+import { grepTool, codebaseSearchTool, symbolsTool } from './search/index.js';
 
 // System tools
 import { runTool, askUserTool } from './system/index.js';
 import { fetchTool } from './system/fetch.js';
+import { httpRequestTool } from './system/http-request.js';
+import { shellSessionTool } from './system/shell-session.js';
+import { psPortsTool } from './system/ps-ports.js';
+import { envTool } from './system/env.js';
+import { formatTool } from './system/format.js';
 
 // Special tools
 import { verifyTool, safeRefactorTool, impactSimulateTool } from './special/index.js';
 
 // Browser tools
-import { browserStatusTool, browserNavigateTool, browserReadPageTool, browserScreenshotTool, browserClickTool, browserTypeTool, browserFindTool, browserGetTabsTool, browserCreateTabTool } from './browser/index.js';
+import { browserStatusTool, browserNavigateTool, browserReadPageTool, browserScreenshotTool, browserClickTool, browserTypeTool, browserFindTool, browserGetTabsTool, browserCreateTabTool, browserJavascriptExecTool, browserNetworkLogTool } from './browser/index.js';
+
+// Memory tools
+import { rememberTool } from './memory/index.js';
+
+// Todo tool
+import { todoTool } from './todo/index.js';
+
+// Custom tools
+import { loadCustomTools } from './custom/index.js';
 
 // Patch tools
 import { applyUnifiedDiffTool, editRangeTool, insertAtTool, deleteRangeTool, assessPatchRiskTool } from './patch/index.js';
@@ -60,16 +80,30 @@ export { gitStageTool } from './git/stage.js';
 export { gitUnstageTool } from './git/unstage.js';
 export { gitBranchTool } from './git/branch.js';
 export { isProtectedBranchTool } from './git/is-protected.js';
+export * from './github/github-core.js';
+export { githubIssueTool, githubPrTool } from './github/index.js';
+export { dbQueryTool } from './database/index.js';
 export * from './cache/cache-core.js';
 export { cacheStoreTool, cacheRetrieveTool, cacheDeleteTool, cacheClearTool, cacheListTool, cacheSearchTool, cacheStatsTool, cachePruneTool, cacheStorePatternTool, cacheStoreReasoningTool, cacheLoadReasoningTool, cacheArchiveReasoningTool } from './cache/index.js';
 export * from './file/file-core.js';
 export { readFileTool } from './file/index.js';
 export * from './search/search-core.js';
-export { grepTool, codebaseSearchTool } from './search/index.js';
-export { runTool, askUserTool } from './system/index.js';
-export { browserStatusTool, browserNavigateTool, browserReadPageTool, browserScreenshotTool, browserClickTool, browserTypeTool, browserFindTool, browserGetTabsTool, browserCreateTabTool } from './browser/index.js';
+export * from './search/symbols-core.js';
+export { grepTool, codebaseSearchTool, symbolsTool } from './search/index.js';
+export { runTool, askUserTool, setAskUserHandler, type AskUserHandler } from './system/index.js';
+export { shellSessionTool, closeAllShellSessions } from './system/shell-session.js';
+export { psPortsTool } from './system/ps-ports.js';
+export { envTool, setEnvValueHandler, type EnvValueHandler } from './system/env.js';
+export { formatTool, setAutoFormat } from './system/format.js';
+export { browserStatusTool, browserNavigateTool, browserReadPageTool, browserScreenshotTool, browserClickTool, browserTypeTool, browserFindTool, browserGetTabsTool, browserCreateTabTool, browserJavascriptExecTool, browserNetworkLogTool } from './browser/index.js';
+export { CdpClient } from './browser/cdp-client.js';
 export * from './patch/patch-core.js';
 export { applyUnifiedDiffTool, editRangeTool, insertAtTool, deleteRangeTool, assessPatchRiskTool } from './patch/index.js';
+export { rememberTool } from './memory/index.js';
+export * from './todo/todo-core.js';
+export { todoTool } from './todo/index.js';
+export * from './custom/custom-core.js';
+export { createCustomTool, loadCustomTools } from './custom/index.js';
 
 // ============================================================================
 // Tool Registration
@@ -114,21 +148,27 @@ export function registerCoreTools(): void {
 	toolRegistry.register(cacheLoadReasoningTool);
 	toolRegistry.register(cacheArchiveReasoningTool);
 
-	// File tools (4 tools)
+	// File tools (5 tools)
 	toolRegistry.register(readFileTool);
 	toolRegistry.register(writeTool);
 	toolRegistry.register(editFileTool);
 	toolRegistry.register(searchReplaceTool);
+	toolRegistry.register(jsonEditTool);
 
-	// Search tools (2 tools)
+	// Search tools (3 tools)
 	toolRegistry.register(grepTool);
 	toolRegistry.register(codebaseSearchTool);
+	toolRegistry.register(symbolsTool);
 
-	// System tools (2 tools)
+	// System tools (6 tools)
 	toolRegistry.register(runTool);
+	toolRegistry.register(shellSessionTool);
+	toolRegistry.register(psPortsTool);
+	toolRegistry.register(envTool);
+	toolRegistry.register(formatTool);
 	toolRegistry.register(askUserTool);
 
-	// Browser tools (9 tools)
+	// Browser tools (11 tools)
 	toolRegistry.register(browserStatusTool);
 	toolRegistry.register(browserNavigateTool);
 	toolRegistry.register(browserReadPageTool);
@@ -138,6 +178,8 @@ export function registerCoreTools(): void {
 	toolRegistry.register(browserFindTool);
 	toolRegistry.register(browserGetTabsTool);
 	toolRegistry.register(browserCreateTabTool);
+	toolRegistry.register(browserJavascriptExecTool);
+	toolRegistry.register(browserNetworkLogTool);
 
 	// Patch tools (5 tools)
 	toolRegistry.register(applyUnifiedDiffTool);
@@ -158,13 +200,57 @@ export function registerCoreTools(): void {
 	// Git tools (1 new tool: #46)
 	toolRegistry.register(gitMergeTool);
 
-	// System tools (1 new tool: #47)
+	// GitHub tools
+	toolRegistry.register(githubIssueTool);
+	toolRegistry.register(githubPrTool);
+
+	// Database tools
+	toolRegistry.register(dbQueryTool);
+
+	// Network tools (fetch #47, http_request)
 	toolRegistry.register(fetchTool);
+	toolRegistry.register(httpRequestTool);
 
 	// Special tools (3 new tools: #48-50)
 	toolRegistry.register(verifyTool);
 	toolRegistry.register(safeRefactorTool);
 	toolRegistry.register(impactSimulateTool);
+
+	// Memory tools
+	toolRegistry.register(rememberTool);
+
+	// Todo tool
+	toolRegistry.register(todoTool);
+
+	// User-defined tools from ~/.floyd/tools/*.yaml
+	registerCustomTools();
+}
+
+/**
+ * Register the tools described by the manifests in ~/.floyd/tools; invalid
+ * manifests and names taken by other tools are logged and skipped
+ *
+ * @returns Names of the registered tools
+ */
+export function registerCustomTools(dir?: string): string[] {
+	const { tools, errors } = loadCustomTools(dir);
+	for (const error of errors) {
+		logger.warn(`Skipping custom tool manifest: ${error}`);
+	}
+
+	const registered: string[] = [];
+	for (const tool of tools) {
+		if (toolRegistry.has(tool.name)) {
+			logger.warn(`Skipping custom tool "${tool.name}": a tool with that name already exists`);
+			continue;
+		}
+		toolRegistry.register(tool);
+		registered.push(tool.name);
+	}
+	if (registered.length > 0) {
+		logger.info('Registered custom tools', { tools: registered });
+	}
+	return registered;
 }
 
 // ============================================================================
@@ -0,0 +1,56 @@
+/**
+ * Memory Tools - Floyd Wrapper
+ *
+ * Lets the model save facts, decisions and file summaries to long-term
+ * memory (.floyd/memory/). Relevant memories come back in the system prompt
+ * of later requests.
+ */
+
+import { z } from 'zod';
+import type { ToolDefinition } from '../../types.js';
+import { getMemoryStore, isMemoryEnabled, MEMORY_KINDS } from '../../memory/index.js';
+
+// ============================================================================
+// Remember Tool
+// ============================================================================
+
+export const rememberTool: ToolDefinition = {
+	name: 'remember',
+	description: 'Save something worth knowing in later sessions to long-term memory: a fact about the project, a decision and its reason, or a short summary of a file (kind file_summary, with source set to its path). Keep each memory to one or two sentences.',
+	category: 'cache',
+	inputSchema: z.object({
+		kind: z.enum(MEMORY_KINDS as [string, ...string[]]),
+		text: z.string().min(1, 'Text is required'),
+		source: z.string().optional(),
+	}),
+	permission: 'none',
+	execute: async (input) => {
+		const { kind, text, source } = input as { kind: typeof MEMORY_KINDS[number]; text: string; source?: string };
+
+		if (!isMemoryEnabled()) {
+			return {
+				success: false,
+				error: {
+					code: 'MEMORY_DISABLED',
+					message: 'Long-term memory is off (FLOYD_MEMORY=off)',
+				},
+			};
+		}
+
+		try {
+			const entry = await getMemoryStore().add(kind, text, source);
+			return {
+				success: true,
+				data: { id: entry.id, kind: entry.kind, saved: entry.text },
+			};
+		} catch (error) {
+			return {
+				success: false,
+				error: {
+					code: 'MEMORY_ERROR',
+					message: `Failed to save memory: ${(error as Error).message}`,
+				},
+			};
+		}
+	},
+} as ToolDefinition;
@@ -0,0 +1,154 @@
+/**
+ * Tool Result Cache - Floyd Wrapper
+ *
+ * Reading the same unchanged file twice, or listing the same directory
+ * again, puts the same output into the conversation twice. The engine keeps
+ * one cache per session: results are keyed by tool, input and the target's
+ * mtime/size, and a repeat call gets a short stub pointing back at the
+ * earlier result. Passing `fresh: true` (or FLOYD_TOOL_CACHE=off) bypasses it.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import type { ToolResult } from '../types.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Stub returned instead of a repeated result
+ */
+export interface UnchangedResult {
+  unchanged: true;
+  message: string;
+}
+
+/**
+ * Tools whose results are cached, with the input field naming their target
+ */
+const CACHED_TOOLS: Record<string, string> = {
+  read_file: 'file_path',
+  list_directory: 'path',
+};
+
+/**
+ * Stub message prefix
+ */
+export const UNCHANGED_PREFIX = '(unchanged, previously read)';
+
+// ============================================================================
+// Tool Result Cache Class
+// ============================================================================
+
+/**
+ * Remembers which tool results the model has already seen this session
+ */
+export class ToolResultCache {
+  /** Cache key -> fingerprint of the target when the result was returned */
+  private seen = new Map<string, string>();
+  private readonly enabled: boolean;
+
+  constructor(enabled: boolean = isToolCacheEnabled()) {
+    this.enabled = enabled;
+  }
+
+  /**
+   * A stub if the model already has this exact result, otherwise null
+   */
+  async lookup(toolName: string, input: Record<string, unknown>): Promise<ToolResult<UnchangedResult> | null> {
+    const key = this.getKey(toolName, input);
+    if (!key || input.fresh === true) {
+      return null;
+    }
+
+    const fingerprint = await getFingerprint(input[CACHED_TOOLS[toolName]]);
+    if (!fingerprint || this.seen.get(key) !== fingerprint) {
+      return null;
+    }
+
+    const target = String(input[CACHED_TOOLS[toolName]] ?? '.');
+    return {
+      success: true,
+      data: {
+        unchanged: true,
+        message: `${UNCHANGED_PREFIX} ${target} has not changed since the same ${toolName} call earlier in this session; use that result. Pass fresh: true to get it again.`,
+      },
+    };
+  }
+
+  /**
+   * Remember a result the model is about to see
+   */
+  async store(toolName: string, input: Record<string, unknown>, result: ToolResult): Promise<void> {
+    const key = this.getKey(toolName, input);
+    if (!key) {
+      return;
+    }
+
+    const fingerprint = result.success ? await getFingerprint(input[CACHED_TOOLS[toolName]]) : null;
+    if (fingerprint) {
+      this.seen.set(key, fingerprint);
+    } else {
+      this.seen.delete(key);
+    }
+  }
+
+  /**
+   * Forget everything, e.g. when the conversation is cleared
+   */
+  reset(): void {
+    this.seen.clear();
+  }
+
+  /**
+   * Number of remembered results
+   */
+  get size(): number {
+    return this.seen.size;
+  }
+
+  /**
+   * Cache key for a call, or null if the call is not cached
+   * Recursive listings are skipped: a directory's mtime does not change
+   * when something deeper in the tree does.
+   */
+  private getKey(toolName: string, input: Record<string, unknown>): string | null {
+    if (!this.enabled || !(toolName in CACHED_TOOLS) || input.recursive === true) {
+      return null;
+    }
+
+    const { fresh: _fresh, ...rest } = input;
+    const target = rest[CACHED_TOOLS[toolName]];
+    const normalized = {
+      ...rest,
+      [CACHED_TOOLS[toolName]]: path.resolve(typeof target === 'string' ? target : '.'),
+    };
+    const sorted = Object.keys(normalized).sort().map(field => [field, normalized[field]]);
+    return `${toolName}:${JSON.stringify(sorted)}`;
+  }
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Whether caching is on (FLOYD_TOOL_CACHE=off turns it off)
+ */
+export function isToolCacheEnabled(): boolean {
+  const value = process.env.FLOYD_TOOL_CACHE?.toLowerCase();
+  return value !== 'off' && value !== 'false' && value !== '0';
+}
+
+/**
+ * mtime and size of a path, or null if it cannot be read
+ */
+async function getFingerprint(target: unknown): Promise<string | null> {
+  try {
+    const stat = await fs.stat(path.resolve(typeof target === 'string' ? target : '.'));
+    return `${stat.mtimeMs}:${stat.size}`;
+  } catch {
+    return null;
+  }
+}
@@ -7,6 +7,8 @@
 import { z } from 'zod';
 import type { ToolDefinition } from '../../types.js';
 import * as searchCore from './search-core.js';
+import * as symbolsCore from './symbols-core.js';
+import { toolRegistry } from '../tool-registry.js';
 
 // ============================================================================
 // Grep Tool
@@ -14,7 +16,7 @@ import * as searchCore from './search-core.js';
 
 export const grepTool: ToolDefinition = {
 	name: 'grep',
-	description: 'Search file contents with regex patterns',
+	description: 'Search file contents with regex patterns. Files matched by .gitignore or .floydignore (and node_modules, dist) are skipped unless include_ignored: true',
 	category: 'search',
 	inputSchema: z.object({
 		pattern: z.string(),
@@ -22,11 +24,12 @@ export const grepTool: ToolDefinition = {
 		filePattern: z.string().optional().default('**/*'),
 		caseInsensitive: z.boolean().optional().default(false),
 		outputMode: z.enum(['content', 'files_with_matches', 'count']).optional().default('content'),
+		include_ignored: z.boolean().optional().default(false),
 	}),
 	permission: 'none',
 	execute: async (input) => {
 		const params = input as z.infer<typeof grepTool.inputSchema>;
-		const result = await searchCore.grep(params.pattern, params);
+		const result = await searchCore.grep(params.pattern, { ...params, includeIgnored: params.include_ignored });
 		if (result.success) {
 			return { success: true, data: result };
 		}
@@ -40,20 +43,65 @@ export const grepTool: ToolDefinition = {
 
 export const codebaseSearchTool: ToolDefinition = {
 	name: 'codebase_search',
-	description: 'Search entire codebase with semantic understanding',
+	description: 'Search entire codebase with semantic understanding. Files matched by .gitignore or .floydignore are skipped unless include_ignored: true',
 	category: 'search',
 	inputSchema: z.object({
 		query: z.string(),
 		path: z.string().optional().default('.'),
 		maxResults: z.number().optional().default(20),
+		include_ignored: z.boolean().optional().default(false),
 	}),
 	permission: 'none',
 	execute: async (input) => {
 		const params = input as z.infer<typeof codebaseSearchTool.inputSchema>;
-		const result = await searchCore.codebaseSearch(params.query, params);
+		const result = await searchCore.codebaseSearch(params.query, { ...params, includeIgnored: params.include_ignored });
 		if (result.success) {
 			return { success: true, data: result };
 		}
 		return { success: false, error: { code: 'CODEBASE_SEARCH_ERROR', message: result.error || 'Unknown error', details: params } };
 	}
 } as ToolDefinition;
+
+// ============================================================================
+// Symbols Tool
+// ============================================================================
+
+const SYMBOL_KINDS = ['function', 'method', 'class', 'interface', 'type', 'enum', 'struct', 'trait', 'variable', 'constant', 'module'] as const;
+
+export const symbolsTool: ToolDefinition = {
+	name: 'symbols',
+	description: 'Language-aware code navigation for TypeScript/JavaScript, Go, Python and Rust with exact file:line results. action "definition": where a symbol is declared (name, or Type.method for one type\'s method). action "references": whole-word uses of a name outside comments and its declarations. action "list": every declaration in a file or directory (path). Prefer this over grep for finding definitions and callers.',
+	category: 'search',
+	inputSchema: z.object({
+		action: z.enum(['definition', 'references', 'list']),
+		name: z.string().optional(),
+		path: z.string().optional().default('.'),
+		kind: z.enum(SYMBOL_KINDS).optional(),
+		maxResults: z.number().optional(),
+	}),
+	permission: 'none',
+	execute: async (input) => {
+		const params = input as { action: 'definition' | 'references' | 'list'; name?: string; path: string; kind?: symbolsCore.SymbolKind; maxResults?: number };
+		const options = {
+			path: params.path,
+			kind: params.kind,
+			maxResults: params.maxResults,
+			ignore: toolRegistry.getIgnorePatterns(),
+		};
+
+		if (params.action !== 'list' && !params.name?.trim()) {
+			return { success: false, error: { code: 'SYMBOLS_ERROR', message: `name is required for action "${params.action}"`, details: params } };
+		}
+
+		try {
+			const matches = params.action === 'definition'
+				? await symbolsCore.findDefinition(params.name!, options)
+				: params.action === 'references'
+					? await symbolsCore.findReferences(params.name!, options)
+					: await symbolsCore.listSymbols(params.path, options);
+			return { success: true, data: { action: params.action, matches, total: matches.length } };
+		} catch (error) {
+			return { success: false, error: { code: 'SYMBOLS_ERROR', message: (error as Error).message, details: params } };
+		}
+	}
+} as ToolDefinition;
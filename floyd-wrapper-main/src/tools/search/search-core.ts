@@ -7,18 +7,22 @@
 import { globby } from 'globby';
 import { execSync } from 'child_process';
 import { toolRegistry } from '../tool-registry.js';
+import { createIgnoreFilter, ignoreGlobOptions } from '../../utils/ignore-files.js';
 
 export async function grep(pattern: string, options: {
 	path?: string;
 	filePattern?: string;
 	caseInsensitive?: boolean;
 	outputMode?: 'content' | 'files_with_matches' | 'count';
+	/** Also search files matched by .gitignore / .floydignore */
+	includeIgnored?: boolean;
 }): Promise<{ success: boolean; matches?: Array<{ file: string; line: number; content: string; matchStart: number; matchEnd: number }>; totalMatches?: number; filesWithMatches?: number; error?: string }> {
 	try {
-		const { path: searchPath = '.', filePattern = '**/*', caseInsensitive = false, outputMode = 'content' } = options;
+		const { path: searchPath = '.', filePattern = '**/*', caseInsensitive = false, outputMode = 'content', includeIgnored = false } = options;
 		
 		// Get ignore patterns
 		const ignorePatterns = toolRegistry.getIgnorePatterns();
+		const defaultIgnores = includeIgnored ? ['.git'] : ['node_modules', '.git', 'dist'];
 
 		// Check if we should use native grep (faster for large searches)
 		// Use native grep for filePattern operations and when outputMode allows it
@@ -43,9 +47,9 @@ export async function grep(pattern: string, options: {
 				}
 				
 				// Add default excludes
-				grepArgs.push('--exclude-dir="node_modules"');
-				grepArgs.push('--exclude-dir=".git"');
-				grepArgs.push('--exclude-dir="dist"');
+				for (const dir of defaultIgnores) {
+					grepArgs.push(`--exclude-dir="${dir}"`);
+				}
 
 				// Build file pattern for grep
 				let grepPattern = filePattern;
@@ -62,8 +66,15 @@ export async function grep(pattern: string, options: {
 					cwd: searchPath
 				});
 
+				// grep knows nothing of .gitignore / .floydignore; drop matches in ignored files
+				const isIgnored = includeIgnored ? null : await createIgnoreFilter(searchPath);
+				const outputLines = output.trim().split('\n').filter(Boolean).filter(line => {
+					const file = outputMode === 'files_with_matches' ? line : line.substring(0, line.indexOf(':'));
+					return !isIgnored?.(file);
+				});
+
 				if (outputMode === 'files_with_matches') {
-					const files = output.trim().split('\n').filter(Boolean);
+					const files = outputLines;
 					return {
 						success: true,
 						matches: files.map(f => ({ file: f, line: 0, content: '', matchStart: 0, matchEnd: 0 })),
@@ -71,7 +82,7 @@ export async function grep(pattern: string, options: {
 						filesWithMatches: files.length
 					};
 				} else if (outputMode === 'count') {
-					const lines = output.trim().split('\n').filter(Boolean);
+					const lines = outputLines;
 					let totalMatches = 0;
 					const matches = lines.map(line => {
 						const [file, count] = line.split(':');
@@ -87,9 +98,7 @@ export async function grep(pattern: string, options: {
 				} else {
 					// Content mode - parse grep output
 					const matches: Array<{ file: string; line: number; content: string; matchStart: number; matchEnd: number }> = [];
-					const lines = output.trim().split('\n').filter(Boolean);
-
-					for (const line of lines) {
+					for (const line of outputLines) {
 						// Grep output format: file:line:content (or just file:content if no line numbers)
 						// But we used -n so it should be file:line:content
 						// Handle potential colons in filename (though unusual) or content
@@ -128,7 +137,8 @@ export async function grep(pattern: string, options: {
 		// Fallback: JavaScript-based grep (for specific file patterns or when grep fails)
 		const files = await globby(filePattern, { 
 			cwd: searchPath,
-			ignore: [...ignorePatterns, 'node_modules', '.git', 'dist'] 
+			ignore: [...ignorePatterns, ...defaultIgnores],
+			...ignoreGlobOptions(includeIgnored),
 		});
 
 		// Limit to 100 files to prevent performance issues
@@ -174,17 +184,20 @@ export async function grep(pattern: string, options: {
 export async function codebaseSearch(query: string, options: {
 	path?: string;
 	maxResults?: number;
+	/** Also search files matched by .gitignore / .floydignore */
+	includeIgnored?: boolean;
 }): Promise<{ success: boolean; results?: Array<{ file: string; score: number; line: number; content: string; context: string }>; totalResults?: number; error?: string }> {
 	try {
-		const { path: searchPath = '.', maxResults = 20 } = options;
+		const { path: searchPath = '.', maxResults = 20, includeIgnored = false } = options;
 		const keywords = query.split(' ').filter(k => k.length > 2);
 		
 		const ignorePatterns = toolRegistry.getIgnorePatterns();
-		const defaultIgnores = ['node_modules', 'dist', 'target', '.git'];
+		const defaultIgnores = includeIgnored ? ['.git'] : ['node_modules', 'dist', 'target', '.git'];
 		
 		const files = await globby('**/*.{ts,tsx,rs,js,md}', { 
 			cwd: searchPath, 
-			ignore: [...defaultIgnores, ...ignorePatterns] 
+			ignore: [...defaultIgnores, ...ignorePatterns],
+			...ignoreGlobOptions(includeIgnored),
 		});
 		
 		const results: Array<{ file: string; score: number; line: number; content: string; context: string }> = [];
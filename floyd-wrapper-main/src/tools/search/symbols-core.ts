@@ -0,0 +1,422 @@
+/**
+ * Symbols Core - Floyd Wrapper
+ *
+ * Language-aware lookup of definitions and references for TypeScript/JavaScript,
+ * Go, Python and Rust. Declarations are recognised line by line from each
+ * language's declaration syntax, so results carry an exact file:line and kind
+ * instead of whatever a grep pattern happens to match.
+ */
+
+import { globby } from 'globby';
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export type SymbolKind = 'function' | 'method' | 'class' | 'interface' | 'type' | 'enum' | 'struct' | 'trait' | 'variable' | 'constant' | 'module';
+
+/**
+ * A declaration found in a source file
+ */
+export interface SymbolDefinition {
+	name: string;
+	kind: SymbolKind;
+	/** 1-based line of the declaration */
+	line: number;
+	exported: boolean;
+	/** Class, receiver or impl type a method belongs to */
+	container?: string;
+	/** The declaration line, trimmed */
+	signature: string;
+}
+
+/**
+ * A symbol result with its location
+ */
+export interface SymbolMatch {
+	file: string;
+	line: number;
+	/** file:line */
+	location: string;
+	name: string;
+	kind: SymbolKind | 'reference';
+	container?: string;
+	text: string;
+}
+
+export interface SymbolSearchOptions {
+	/** File or directory to search (default: current directory) */
+	path?: string;
+	kind?: SymbolKind;
+	maxResults?: number;
+	ignore?: string[];
+}
+
+// ============================================================================
+// Declaration Patterns
+// ============================================================================
+
+interface DeclarationPattern {
+	regex: RegExp;
+	kind: SymbolKind | ((match: RegExpMatchArray) => SymbolKind);
+	/** Group holding the name (default 1) */
+	nameGroup?: number;
+	/** Group holding the container, e.g. a Go receiver type */
+	containerGroup?: number;
+	/** Only match outside of any block */
+	topLevel?: boolean;
+	/** Only match directly inside a class/impl body */
+	member?: boolean;
+}
+
+interface LanguageSpec {
+	extensions: string[];
+	patterns: DeclarationPattern[];
+	isExported: (name: string, line: string) => boolean;
+	/** Opens a block whose indented declarations are members */
+	container?: RegExp;
+	comment: RegExp;
+}
+
+const TS_KEYWORDS = new Set(['if', 'for', 'while', 'switch', 'catch', 'return', 'function', 'constructor', 'super', 'new', 'await', 'typeof']);
+
+const LANGUAGES: LanguageSpec[] = [
+	{
+		extensions: ['.ts', '.tsx', '.js', '.jsx', '.mjs', '.cjs', '.mts', '.cts'],
+		patterns: [
+			{ regex: /^(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:async\s+)?function\*?\s+([A-Za-z_$][\w$]*)/, kind: 'function', topLevel: true },
+			{ regex: /^(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)/, kind: 'class', topLevel: true },
+			{ regex: /^(?:export\s+)?(?:declare\s+)?interface\s+([A-Za-z_$][\w$]*)/, kind: 'interface', topLevel: true },
+			{ regex: /^(?:export\s+)?(?:declare\s+)?type\s+([A-Za-z_$][\w$]*)\s*(?:<[^=]*>)?\s*=/, kind: 'type', topLevel: true },
+			{ regex: /^(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+([A-Za-z_$][\w$]*)/, kind: 'enum', topLevel: true },
+			{ regex: /^(?:export\s+)?(?:declare\s+)?namespace\s+([A-Za-z_$][\w$.]*)/, kind: 'module', topLevel: true },
+			{
+				regex: /^(?:export\s+)?(?:declare\s+)?(const|let|var)\s+([A-Za-z_$][\w$]*)\s*(?::[^=]+)?=\s*(async\s+)?(\(|function|[A-Za-z_$][\w$]*\s*=>)?/,
+				kind: match => match[4] ? 'function' : match[1] === 'const' ? 'constant' : 'variable',
+				nameGroup: 2,
+				topLevel: true,
+			},
+			{
+				regex: /^\s+(?:(?:public|private|protected|static|readonly|abstract|override|async|get|set)\s+)*\*?([A-Za-z_$#][\w$]*)\s*(?:<[^>]*>)?\s*\(/,
+				kind: 'method',
+				member: true,
+			},
+		],
+		isExported: (_name, line) => /^export\s/.test(line),
+		container: /^(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)/,
+		comment: /^\s*(?:\/\/|\/\*|\*)/,
+	},
+	{
+		extensions: ['.go'],
+		patterns: [
+			{ regex: /^func\s+\(\s*(?:\w+\s+)?\*?(\w+)(?:\[[^\]]*\])?\s*\)\s*(\w+)/, kind: 'method', nameGroup: 2, containerGroup: 1 },
+			{ regex: /^func\s+(\w+)/, kind: 'function' },
+			{ regex: /^type\s+(\w+)(?:\[[^\]]*\])?\s+(struct|interface)\b/, kind: match => match[2] === 'struct' ? 'struct' : 'interface' },
+			{ regex: /^type\s+(\w+)(?:\[[^\]]*\])?\s*=?\s*\S/, kind: 'type' },
+			{ regex: /^const\s+(\w+)/, kind: 'constant' },
+			{ regex: /^var\s+(\w+)/, kind: 'variable' },
+		],
+		isExported: name => /^[A-Z]/.test(name),
+		comment: /^\s*\/\//,
+	},
+	{
+		extensions: ['.py'],
+		patterns: [
+			{ regex: /^(?:async\s+)?def\s+([A-Za-z_]\w*)/, kind: 'function' },
+			{ regex: /^class\s+([A-Za-z_]\w*)/, kind: 'class' },
+			{ regex: /^\s+(?:async\s+)?def\s+([A-Za-z_]\w*)/, kind: 'method', member: true },
+			{ regex: /^([A-Z][A-Z0-9_]*)\s*(?::[^=]+)?=/, kind: 'constant' },
+		],
+		isExported: name => !name.startsWith('_'),
+		container: /^class\s+([A-Za-z_]\w*)/,
+		comment: /^\s*#/,
+	},
+	{
+		extensions: ['.rs'],
+		patterns: [
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+(\w+)/, kind: 'function' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?struct\s+(\w+)/, kind: 'struct' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?enum\s+(\w+)/, kind: 'enum' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?trait\s+(\w+)/, kind: 'trait' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?type\s+(\w+)/, kind: 'type' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?mod\s+(\w+)/, kind: 'module' },
+			{ regex: /^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const|static)\s+(?:mut\s+)?(\w+)/, kind: 'constant' },
+			{ regex: /^\s+(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+(\w+)/, kind: 'method', member: true },
+		],
+		isExported: (_name, line) => /^\s*pub\b/.test(line),
+		container: /^\s*impl(?:<[^>]*>)?\s+(?:[\w:<>]+\s+for\s+)?(\w+)/,
+		comment: /^\s*\/\//,
+	},
+];
+
+/**
+ * Files larger than this are skipped
+ */
+const MAX_FILE_BYTES = 512 * 1024;
+
+/**
+ * Files scanned per query
+ */
+const MAX_FILES = 5000;
+
+const DEFAULT_IGNORE = ['**/node_modules/**', '**/.git/**', '**/dist/**', '**/build/**', '**/target/**', '**/vendor/**', '**/.floyd/**'];
+
+const SOURCE_GLOB = `**/*.{${LANGUAGES.flatMap(lang => lang.extensions.map(ext => ext.slice(1))).join(',')}}`;
+
+function languageFor(filePath: string): LanguageSpec | undefined {
+	const ext = path.extname(filePath);
+	return LANGUAGES.find(lang => lang.extensions.includes(ext));
+}
+
+// ============================================================================
+// Definitions
+// ============================================================================
+
+/**
+ * Declarations in a source file, in file order
+ */
+export function findDefinitions(filePath: string, content: string): SymbolDefinition[] {
+	const lang = languageFor(filePath);
+	if (!lang) {
+		return [];
+	}
+
+	const definitions: SymbolDefinition[] = [];
+	const isGo = lang.extensions.includes('.go');
+	const isTs = lang.extensions.includes('.ts');
+	// Open class/impl body; members sit at the indent of its first line
+	let container: { name: string; indent: number; memberIndent?: number } | null = null;
+	// Go groups consts, vars and types in parenthesised blocks
+	let goBlock: SymbolKind | null = null;
+
+	const lines = content.split('\n');
+	for (let i = 0; i < lines.length; i++) {
+		const line = lines[i]!.replace(/\r$/, '');
+		if (!line.trim() || lang.comment.test(line)) {
+			continue;
+		}
+		const indent = line.length - line.trimStart().length;
+		const push = (name: string, kind: SymbolKind, owner?: string) => definitions.push({
+			name,
+			kind,
+			line: i + 1,
+			exported: lang.isExported(name, line),
+			...(owner && { container: owner }),
+			signature: line.trim().replace(/\s*\{$/, ''),
+		});
+
+		if (isGo) {
+			const block = line.match(/^(const|var|type)\s*\($/);
+			if (block) {
+				goBlock = block[1] === 'const' ? 'constant' : block[1] === 'var' ? 'variable' : 'type';
+				continue;
+			}
+			if (goBlock) {
+				const entry = line.match(/^\t(\w+)\b/);
+				if (line.startsWith(')')) {
+					goBlock = null;
+				} else if (entry && entry[1] !== '_') {
+					const shape = goBlock === 'type' ? line.match(/\b(struct|interface)\b/) : null;
+					push(entry[1]!, shape ? (shape[1] === 'struct' ? 'struct' : 'interface') : goBlock);
+				}
+				continue;
+			}
+		}
+
+		if (container && indent <= container.indent && !/^\s*[}\])]/.test(line)) {
+			container = null;
+		}
+
+		if (container && indent > container.indent) {
+			container.memberIndent ??= indent;
+			if (indent !== container.memberIndent) {
+				continue;
+			}
+			for (const pattern of lang.patterns) {
+				if (!pattern.member) {
+					continue;
+				}
+				const name = line.match(pattern.regex)?.[pattern.nameGroup ?? 1];
+				if (name && !(isTs && TS_KEYWORDS.has(name))) {
+					push(name, 'method', container.name);
+					break;
+				}
+			}
+			continue;
+		}
+
+		for (const pattern of lang.patterns) {
+			if (pattern.member || (pattern.topLevel && indent > 0)) {
+				continue;
+			}
+			const match = line.match(pattern.regex);
+			const name = match?.[pattern.nameGroup ?? 1];
+			if (match && name) {
+				push(name, typeof pattern.kind === 'function' ? pattern.kind(match) : pattern.kind, pattern.containerGroup ? match[pattern.containerGroup] : undefined);
+				break;
+			}
+		}
+
+		const opened = lang.container?.exec(line);
+		if (opened?.[1]) {
+			container = { name: opened[1], indent };
+		}
+	}
+
+	return definitions;
+}
+
+/**
+ * Source files under a file or directory, relative paths for display
+ */
+async function listSourceFiles(target: string, ignore: string[]): Promise<Array<{ absolute: string; display: string }>> {
+	const absolute = path.resolve(target);
+	const stat = await fs.stat(absolute).catch(() => null);
+	if (!stat) {
+		throw new Error(`Path not found: ${target}`);
+	}
+
+	const display = (file: string) => path.relative(process.cwd(), file) || path.basename(file);
+	if (stat.isFile()) {
+		return [{ absolute, display: display(absolute) }];
+	}
+
+	const files = await globby(SOURCE_GLOB, {
+		cwd: absolute,
+		ignore: [...DEFAULT_IGNORE, ...ignore],
+		gitignore: true,
+		absolute: true,
+	});
+	return files
+		.filter(file => !file.endsWith('.d.ts'))
+		.sort()
+		.slice(0, MAX_FILES)
+		.map(file => ({ absolute: file, display: display(file) }));
+}
+
+async function readSource(file: string): Promise<string | null> {
+	const stat = await fs.stat(file).catch(() => null);
+	if (!stat || stat.size > MAX_FILE_BYTES) {
+		return null;
+	}
+	return fs.readFile(file, 'utf-8').catch(() => null);
+}
+
+/**
+ * Split "Type.method" into its container and name
+ */
+function parseName(query: string): { name: string; container?: string } {
+	const dot = query.lastIndexOf('.');
+	if (dot > 0 && dot < query.length - 1) {
+		return { container: query.slice(0, dot), name: query.slice(dot + 1) };
+	}
+	return { name: query };
+}
+
+function toMatch(file: string, def: SymbolDefinition): SymbolMatch {
+	return {
+		file,
+		line: def.line,
+		location: `${file}:${def.line}`,
+		name: def.container ? `${def.container}.${def.name}` : def.name,
+		kind: def.kind,
+		...(def.container && { container: def.container }),
+		text: def.signature,
+	};
+}
+
+// ============================================================================
+// Queries
+// ============================================================================
+
+/**
+ * Where a symbol is defined; "Type.method" narrows to one type's method
+ */
+export async function findDefinition(query: string, options: SymbolSearchOptions = {}): Promise<SymbolMatch[]> {
+	const { name, container } = parseName(query.trim());
+	const { maxResults = 50, kind } = options;
+	const results: SymbolMatch[] = [];
+
+	for (const file of await listSourceFiles(options.path ?? '.', options.ignore ?? [])) {
+		const content = await readSource(file.absolute);
+		if (!content || !content.includes(name)) {
+			continue;
+		}
+		for (const def of findDefinitions(file.absolute, content)) {
+			if (def.name === name && (!container || def.container === container) && (!kind || def.kind === kind)) {
+				results.push(toMatch(file.display, def));
+				if (results.length >= maxResults) {
+					return results;
+				}
+			}
+		}
+	}
+	return results;
+}
+
+/**
+ * Declarations in a file or directory, optionally of one kind
+ */
+export async function listSymbols(target: string, options: SymbolSearchOptions = {}): Promise<SymbolMatch[]> {
+	const { maxResults = 200, kind } = options;
+	const results: SymbolMatch[] = [];
+
+	for (const file of await listSourceFiles(target, options.ignore ?? [])) {
+		const content = await readSource(file.absolute);
+		if (!content) {
+			continue;
+		}
+		for (const def of findDefinitions(file.absolute, content)) {
+			if (!kind || def.kind === kind) {
+				results.push(toMatch(file.display, def));
+				if (results.length >= maxResults) {
+					return results;
+				}
+			}
+		}
+	}
+	return results;
+}
+
+/**
+ * Whole-word uses of a symbol outside comments and its own definitions
+ */
+export async function findReferences(query: string, options: SymbolSearchOptions = {}): Promise<SymbolMatch[]> {
+	const { name } = parseName(query.trim());
+	const { maxResults = 100 } = options;
+	const word = new RegExp(`(?<![\\w$])${name.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')}(?![\\w$])`);
+	const results: SymbolMatch[] = [];
+
+	for (const file of await listSourceFiles(options.path ?? '.', options.ignore ?? [])) {
+		const content = await readSource(file.absolute);
+		if (!content || !content.includes(name)) {
+			continue;
+		}
+		const lang = languageFor(file.absolute)!;
+		const definitionLines = new Set(
+			findDefinitions(file.absolute, content).filter(def => def.name === name).map(def => def.line),
+		);
+
+		const lines = content.split('\n');
+		for (let i = 0; i < lines.length; i++) {
+			const line = lines[i]!;
+			if (definitionLines.has(i + 1) || lang.comment.test(line) || !word.test(line)) {
+				continue;
+			}
+			results.push({
+				file: file.display,
+				line: i + 1,
+				location: `${file.display}:${i + 1}`,
+				name,
+				kind: 'reference',
+				text: line.trim(),
+			});
+			if (results.length >= maxResults) {
+				return results;
+			}
+		}
+	}
+	return results;
+}
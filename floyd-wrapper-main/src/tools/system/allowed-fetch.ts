@@ -0,0 +1,94 @@
+/**
+ * Allowed Fetch - Floyd Wrapper
+ *
+ * fetch() for the fetch and http_request tools that keeps redirects inside
+ * FLOYD_FETCH_ALLOWED_DOMAINS. SafetyEnforcer only sees the URL the model
+ * asked for; with automatic redirects an allowed host could send the request
+ * anywhere, so redirects are followed here one hop at a time and every
+ * Location is checked against the allowlist first.
+ */
+
+import { getSafetyEnforcer, type SafetyViolation } from '../../permissions/safety-enforcer.js';
+
+/**
+ * Redirects followed before giving up (fetch's own limit)
+ */
+export const MAX_REDIRECTS = 20;
+
+const REDIRECT_STATUSES = new Set([301, 302, 303, 307, 308]);
+
+/**
+ * Headers not sent on to another origin
+ */
+const CREDENTIAL_HEADERS = ['authorization', 'cookie', 'proxy-authorization'];
+
+/**
+ * A redirect that left the domain allowlist or went on too long
+ */
+export class RedirectBlockedError extends Error {
+	constructor(readonly violation: SafetyViolation) {
+		super(`Redirect blocked by safety rule: ${violation.reason}`);
+		this.name = 'RedirectBlockedError';
+	}
+}
+
+/**
+ * Response of the last hop, its URL and whether a redirect was followed
+ */
+export interface AllowedFetchResult {
+	response: Response;
+	url: string;
+	redirected: boolean;
+}
+
+/**
+ * Fetch a URL, following redirects only to allowed domains
+ *
+ * @throws RedirectBlockedError when a redirect leaves the allowlist or
+ * exceeds maxRedirects
+ */
+export async function fetchAllowed(
+	url: string,
+	init: RequestInit = {},
+	maxRedirects: number = MAX_REDIRECTS
+): Promise<AllowedFetchResult> {
+	let current = url;
+	let request: RequestInit = { ...init, headers: new Headers(init.headers) };
+
+	for (let hop = 0; ; hop++) {
+		const response = await fetch(current, { ...request, redirect: 'manual' });
+		const location = response.headers.get('location');
+		if (!REDIRECT_STATUSES.has(response.status) || !location) {
+			return { response, url: current, redirected: hop > 0 };
+		}
+		await response.body?.cancel();
+
+		const next = new URL(location, current).toString();
+		if (hop === maxRedirects) {
+			throw new RedirectBlockedError({ rule: 'too-many-redirects', reason: `More than ${maxRedirects} redirects`, target: next });
+		}
+		const enforcer = getSafetyEnforcer();
+		const violation = enforcer.isEnabled() ? enforcer.checkUrl(next) : null;
+		if (violation) {
+			throw new RedirectBlockedError(violation);
+		}
+
+		// Same rules as fetch's own redirects: 303 (and 301/302 after a POST)
+		// turn into a GET without a body
+		const method = (request.method || 'GET').toUpperCase();
+		if (response.status === 303 ? method !== 'HEAD' : (response.status <= 302 && method === 'POST')) {
+			const headers = new Headers(request.headers);
+			headers.delete('content-type');
+			headers.delete('content-length');
+			request = { ...request, method: 'GET', body: undefined, headers };
+		}
+		if (new URL(next).origin !== new URL(current).origin) {
+			const headers = new Headers(request.headers);
+			for (const name of CREDENTIAL_HEADERS) {
+				headers.delete(name);
+			}
+			request = { ...request, headers };
+		}
+		current = next;
+	}
+}
@@ -3,10 +3,39 @@
  *
  * HTTP requests with timeout and error handling
  * Tool #47 of 50
+ *
+ * GET requests are returned as markdown by default: HTML pages are stripped of
+ * boilerplate and converted, long pages are truncated, and pages are kept in
+ * the project cache tier so documentation is not downloaded twice in a day.
+ * Domains are limited by FLOYD_FETCH_ALLOWED_DOMAINS (see SafetyEnforcer),
+ * redirects included (see allowed-fetch.ts).
  */
 
 import { z } from 'zod';
 import type { ToolDefinition, ToolResult } from '../../types.js';
+import { getCacheManager } from '../cache/index.js';
+import { htmlToMarkdown, extractTitle, truncateText } from '../../utils/html-to-markdown.js';
+import { fetchAllowed, RedirectBlockedError } from './allowed-fetch.js';
+
+/**
+ * Default size of returned page content (FLOYD_FETCH_MAX_CHARS)
+ */
+const DEFAULT_MAX_CHARS = 20000;
+
+export function getFetchMaxChars(): number {
+	return parseInt(process.env.FLOYD_FETCH_MAX_CHARS || '', 10) || DEFAULT_MAX_CHARS;
+}
+
+/**
+ * A page converted for the model, as cached
+ */
+interface FetchedPage {
+	url: string;
+	status: number;
+	contentType: string;
+	title?: string;
+	content: string;
+}
 
 // ============================================================================
 // Zod Schema
@@ -18,14 +47,78 @@ const inputSchema = z.object({
 	headers: z.record(z.string()).optional(),
 	body: z.string().optional(),
 	timeout_ms: z.number().optional().default(30000),
+	format: z.enum(['markdown', 'raw']).optional().default('markdown'),
+	max_chars: z.number().int().positive().optional(),
+	fresh: z.boolean().optional(),
 });
 
 // ============================================================================
 // Tool Execution
 // ============================================================================
 
+/**
+ * Page content as markdown (HTML), pretty JSON or plain text
+ */
+function toMarkdown(text: string, contentType: string, url: string): { title?: string; content: string } {
+	if (contentType.includes('html') || /^\s*<(!doctype html|html)\b/i.test(text)) {
+		return { title: extractTitle(text), content: htmlToMarkdown(text, url) };
+	}
+	if (contentType.includes('json')) {
+		try {
+			return { content: '```json\n' + JSON.stringify(JSON.parse(text), null, 2) + '\n```' };
+		} catch {
+			// Not valid JSON after all; return it as text
+		}
+	}
+	return { content: text };
+}
+
+/**
+ * Page from the project cache tier, if fetched before
+ */
+async function readCachedPage(url: string): Promise<FetchedPage | null> {
+	try {
+		const cached = await getCacheManager().retrieve('project', `fetch:${url}`);
+		return cached ? JSON.parse(cached) as FetchedPage : null;
+	} catch {
+		return null;
+	}
+}
+
+async function cachePage(page: FetchedPage): Promise<void> {
+	try {
+		await getCacheManager().store('project', `fetch:${page.url}`, JSON.stringify(page), { source: 'fetch' });
+	} catch {
+		// Caching only saves a download
+	}
+}
+
+function pageResult(page: FetchedPage, maxChars: number, cached: boolean): ToolResult {
+	const { text, truncated } = truncateText(page.content, maxChars);
+	return {
+		success: true,
+		data: {
+			url: page.url,
+			status: page.status,
+			...(page.title && { title: page.title }),
+			content: text,
+			truncated,
+			cached,
+		},
+	};
+}
+
 async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult> {
-	const { url, method, headers, body, timeout_ms } = input;
+	const { url, method, headers, body, timeout_ms, format, max_chars, fresh } = input;
+	const asMarkdown = format === 'markdown' && method === 'GET';
+	const maxChars = max_chars ?? getFetchMaxChars();
+
+	if (asMarkdown && !fresh) {
+		const cached = await readCachedPage(url);
+		if (cached) {
+			return pageResult(cached, maxChars, true);
+		}
+	}
 
 	try {
 		// Create abort controller for timeout
@@ -42,11 +135,23 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 			fetchOptions.body = body;
 		}
 
-		const response = await fetch(url, fetchOptions);
+		const { response, url: finalUrl } = await fetchAllowed(url, fetchOptions);
 		clearTimeout(timeoutId);
 
-		// Read response body
 		const contentType = response.headers.get('content-type') || '';
+
+		if (asMarkdown && response.ok) {
+			const page: FetchedPage = {
+				url,
+				status: response.status,
+				contentType,
+				...toMarkdown(await response.text(), contentType, finalUrl),
+			};
+			await cachePage(page);
+			return pageResult(page, maxChars, false);
+		}
+
+		// Read response body
 		let responseData: unknown;
 
 		if (contentType.includes('application/json')) {
@@ -81,6 +186,17 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 			},
 		};
 	} catch (error) {
+		if (error instanceof RedirectBlockedError) {
+			return {
+				success: false,
+				error: {
+					code: 'PERMISSION_DENIED',
+					message: error.message,
+					details: error.violation,
+				},
+			};
+		}
+
 		const errorMessage = (error as Error).message || String(error);
 
 		// Check for timeout (abort)
@@ -124,7 +240,7 @@ async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult>
 
 export const fetchTool: ToolDefinition = {
 	name: 'fetch',
-	description: 'Make HTTP requests with timeout and error handling. Supports all HTTP methods.',
+	description: 'Make HTTP requests with timeout and error handling. Supports all HTTP methods. GET returns the page as markdown (boilerplate removed, truncated to max_chars) and is cached for a day; set fresh to re-download or format "raw" for the unconverted response with headers. Use it to consult documentation.',
 	category: 'build',
 	inputSchema,
 	permission: 'moderate',
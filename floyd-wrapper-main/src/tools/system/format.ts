@@ -0,0 +1,359 @@
+/**
+ * Format Tool - Floyd Wrapper
+ *
+ * Runs the project's code formatter on files so agent-written code matches
+ * the project style, and reports what the formatter changed as diffs:
+ *
+ *   .go                        goimports, else gofmt
+ *   .js .ts .css .md .yaml ... prettier (when the project configures it)
+ *   .py                        black (when the project configures it)
+ *   .rs                        rustfmt (edition from Cargo.toml)
+ *
+ * Formatters get the file on stdin, so check mode can report diffs without
+ * touching the file. gofmt and rustfmt define their languages' style and
+ * always run; prettier and black only run in projects that use them, so a
+ * file is never reformatted into a style the project doesn't follow.
+ * Project-local binaries (node_modules/.bin) win over the PATH.
+ *
+ * With FLOYD_AUTO_FORMAT=true, the diff preview shows write and edit
+ * changes already formatted, ToolRegistry formats every file those tools
+ * changed and reports the formatter's diff with the tool result, so the
+ * model's next edit matches the file on disk. ToolRegistry also journals,
+ * sandboxes and checkpoints the files the format tool rewrites, like any
+ * other write.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { execa } from 'execa';
+import { z } from 'zod';
+import type { ToolDefinition } from '../../types.js';
+import { createPreviewDiff } from '../../permissions/diff-preview.js';
+import { getChangeJournal } from '../../rewind/change-journal.js';
+import { budgetToolOutput } from '../../streaming/tool-output.js';
+import { logger } from '../../utils/logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A formatter program for some file types
+ */
+interface Formatter {
+	name: string;
+	extensions: string[];
+	/** Arguments to format stdin to stdout for a file */
+	args: (filePath: string) => Promise<string[]>;
+	/** Whether the project a file is in uses this formatter (always when unset) */
+	usedBy?: (filePath: string) => Promise<boolean>;
+}
+
+/**
+ * What formatting one file did
+ */
+export interface FormatOutcome {
+	file: string;
+	formatter?: string;
+	changed: boolean;
+	/** The formatter's changes as a unified diff */
+	diff?: string;
+	/** Why no formatter ran */
+	skipped?: string;
+	/** Formatter failure, e.g. a syntax error */
+	error?: string;
+}
+
+const FORMAT_TIMEOUT_MS = 30_000;
+
+/**
+ * Tools whose changed files are formatted with FLOYD_AUTO_FORMAT
+ */
+export const AUTO_FORMAT_TOOLS = new Set([
+	'write', 'write_file', 'edit_file', 'search_replace',
+	'apply_unified_diff', 'edit_range', 'insert_at', 'delete_range',
+]);
+
+// ============================================================================
+// Project Detection
+// ============================================================================
+
+/**
+ * Directories from a file's directory up to the repository (or filesystem)
+ * root
+ */
+function ancestorDirs(filePath: string): string[] {
+	const dirs: string[] = [];
+	let dir = path.dirname(path.resolve(filePath));
+	for (;;) {
+		dirs.push(dir);
+		const parent = path.dirname(dir);
+		if (parent === dir || fs.existsSync(path.join(dir, '.git'))) {
+			return dirs;
+		}
+		dir = parent;
+	}
+}
+
+/**
+ * Whether any of the named files near a file exists and (optionally)
+ * matches a pattern
+ */
+async function hasProjectFile(filePath: string, names: string[], pattern?: RegExp): Promise<boolean> {
+	for (const dir of ancestorDirs(filePath)) {
+		for (const name of names) {
+			const candidate = path.join(dir, name);
+			if (!await fs.pathExists(candidate)) {
+				continue;
+			}
+			if (!pattern || pattern.test(await fs.readFile(candidate, 'utf-8').catch(() => ''))) {
+				return true;
+			}
+		}
+	}
+	return false;
+}
+
+const PRETTIER_CONFIGS = [
+	'.prettierrc', '.prettierrc.json', '.prettierrc.json5', '.prettierrc.yaml', '.prettierrc.yml', '.prettierrc.toml',
+	'.prettierrc.js', '.prettierrc.cjs', '.prettierrc.mjs', 'prettier.config.js', 'prettier.config.cjs', 'prettier.config.mjs',
+];
+
+async function usesPrettier(filePath: string): Promise<boolean> {
+	return await hasProjectFile(filePath, PRETTIER_CONFIGS) ||
+		hasProjectFile(filePath, ['package.json'], /"prettier"\s*:/);
+}
+
+async function usesBlack(filePath: string): Promise<boolean> {
+	return hasProjectFile(
+		filePath,
+		['pyproject.toml', 'setup.cfg', 'tox.ini', '.pre-commit-config.yaml', 'requirements-dev.txt', 'requirements.txt'],
+		/\bblack\b/
+	);
+}
+
+/**
+ * Rust edition from the nearest Cargo.toml (rustfmt defaults to 2015)
+ */
+async function rustEdition(filePath: string): Promise<string | null> {
+	for (const dir of ancestorDirs(filePath)) {
+		const manifest = await fs.readFile(path.join(dir, 'Cargo.toml'), 'utf-8').catch(() => null);
+		if (manifest !== null) {
+			return /^\s*edition\s*=\s*"(\d{4})"/m.exec(manifest)?.[1] ?? null;
+		}
+	}
+	return null;
+}
+
+// ============================================================================
+// Formatters
+// ============================================================================
+
+/**
+ * Formatters in order of preference
+ */
+const FORMATTERS: Formatter[] = [
+	{ name: 'goimports', extensions: ['.go'], args: async file => ['-srcdir', path.dirname(file)] },
+	{ name: 'gofmt', extensions: ['.go'], args: async () => [] },
+	{
+		name: 'prettier',
+		extensions: [
+			'.js', '.jsx', '.mjs', '.cjs', '.ts', '.tsx', '.mts', '.cts', '.json', '.css', '.scss', '.less',
+			'.html', '.vue', '.md', '.mdx', '.yaml', '.yml', '.graphql',
+		],
+		args: async file => ['--stdin-filepath', file],
+		usedBy: usesPrettier,
+	},
+	{ name: 'black', extensions: ['.py', '.pyi'], args: async file => ['-q', '--stdin-filename', file, '-'], usedBy: usesBlack },
+	{
+		name: 'rustfmt',
+		extensions: ['.rs'],
+		args: async file => {
+			const edition = await rustEdition(file);
+			return edition ? ['--edition', edition] : [];
+		},
+	},
+];
+
+/**
+ * Path of a program: project-local node_modules/.bin first, then the PATH
+ */
+export async function findExecutable(name: string, near: string = process.cwd()): Promise<string | null> {
+	const names = process.platform === 'win32' ? [`${name}.cmd`, `${name}.exe`, name] : [name];
+	const localDirs = ancestorDirs(path.join(near, 'x')).map(dir => path.join(dir, 'node_modules', '.bin'));
+	const pathDirs = (process.env.PATH || '').split(path.delimiter).filter(Boolean);
+
+	for (const dir of [...localDirs, ...pathDirs]) {
+		for (const candidate of names) {
+			const full = path.join(dir, candidate);
+			try {
+				await fs.access(full, fs.constants.X_OK);
+				return full;
+			} catch {
+				// Not here
+			}
+		}
+	}
+	return null;
+}
+
+/**
+ * Formatted content of a file (stdin to stdout); skipped when no formatter
+ * for its type is available or used by the project
+ *
+ * @throws Error when the formatter fails, e.g. on a syntax error
+ */
+export async function formatText(
+	filePath: string,
+	text: string
+): Promise<{ formatter: string; output: string } | { skipped: string }> {
+	const extension = path.extname(filePath).toLowerCase();
+	const candidates = FORMATTERS.filter(formatter => formatter.extensions.includes(extension));
+	if (candidates.length === 0) {
+		return { skipped: `no formatter for ${extension || 'files without an extension'}` };
+	}
+
+	for (const formatter of candidates) {
+		if (formatter.usedBy && !await formatter.usedBy(filePath)) {
+			continue;
+		}
+		const program = await findExecutable(formatter.name, path.dirname(path.resolve(filePath)));
+		if (!program) {
+			continue;
+		}
+
+		const result = await execa(program, await formatter.args(path.resolve(filePath)), {
+			input: text,
+			cwd: path.dirname(path.resolve(filePath)),
+			timeout: FORMAT_TIMEOUT_MS,
+			reject: false,
+			stripFinalNewline: false,
+		});
+		if (result.exitCode !== 0) {
+			throw new Error(`${formatter.name}: ${(result.stderr || result.stdout).trim() || `exited with code ${result.exitCode}`}`);
+		}
+		return { formatter: formatter.name, output: result.stdout };
+	}
+
+	const names = candidates.map(formatter => formatter.name).join(' or ');
+	return { skipped: `${names} is not installed or not used by this project` };
+}
+
+/**
+ * Format a file in place (or only report the diff in check mode)
+ */
+export async function formatFile(file: string, options: { check?: boolean } = {}): Promise<FormatOutcome> {
+	let before: string;
+	try {
+		before = await fs.readFile(file, 'utf-8');
+	} catch {
+		return { file, changed: false, error: 'file not found' };
+	}
+
+	try {
+		const result = await formatText(file, before);
+		if ('skipped' in result) {
+			return { file, changed: false, skipped: result.skipped };
+		}
+		if (result.output === before) {
+			return { file, formatter: result.formatter, changed: false };
+		}
+
+		if (!options.check) {
+			await fs.writeFile(file, result.output, 'utf-8');
+		}
+		return {
+			file,
+			formatter: result.formatter,
+			changed: true,
+			diff: budgetToolOutput(createPreviewDiff(path.resolve(file), before, result.output)),
+		};
+	} catch (error) {
+		return { file, changed: false, error: (error as Error).message };
+	}
+}
+
+// ============================================================================
+// Auto Format
+// ============================================================================
+
+let autoFormat = false;
+
+/**
+ * Format files after write/edit tools (FLOYD_AUTO_FORMAT, set by the CLI)
+ */
+export function setAutoFormat(enabled: boolean): void {
+	autoFormat = enabled;
+}
+
+export function isAutoFormatEnabled(): boolean {
+	return autoFormat;
+}
+
+/**
+ * Content as it will be after auto-formatting, for the diff preview;
+ * unchanged when no formatter applies or formatting fails
+ */
+export async function autoFormatContent(filePath: string, content: string): Promise<string> {
+	try {
+		const result = await formatText(filePath, content);
+		return 'output' in result ? result.output : content;
+	} catch {
+		return content;
+	}
+}
+
+/**
+ * Format the files a tool just changed; only files the formatter changed
+ * are returned
+ */
+export async function autoFormatFiles(files: string[]): Promise<FormatOutcome[]> {
+	const outcomes = await Promise.all(files.map(file => formatFile(file)));
+	for (const outcome of outcomes.filter(o => o.error)) {
+		logger.debug('Auto-format failed', { file: outcome.file, error: outcome.error });
+	}
+	return outcomes.filter(outcome => outcome.changed);
+}
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+/**
+ * Files changed in this session (the default targets), resolved when the
+ * input is validated so ToolRegistry sees the files the tool will rewrite
+ */
+function changedFiles(): string[] {
+	return getChangeJournal()
+		.summarizeSince(0)
+		.filter(change => change.type !== 'deleted')
+		.map(change => path.relative(process.cwd(), change.path) || change.path);
+}
+
+const inputSchema = z.object({
+	files: z.array(z.string()).default(changedFiles),
+	check: z.boolean().optional().default(false),
+});
+
+export const formatTool: ToolDefinition = {
+	name: 'format',
+	description: 'Run the project formatter (gofmt/goimports, prettier, black, rustfmt) on files and report the changes as diffs. ' +
+		'Without files, formats every file changed in this session. check reports the diffs without writing. ' +
+		'Re-read a file before editing it again if it was reformatted.',
+	category: 'build',
+	inputSchema,
+	permission: 'moderate',
+	execute: async (input) => {
+		const { files: targets, check } = input as z.infer<typeof inputSchema>;
+
+		if (targets.length === 0) {
+			return { success: true, data: { results: [], changed: 0, note: 'No files were changed in this session' } };
+		}
+
+		const results = await Promise.all(targets.map(file => formatFile(file, { check })));
+		return {
+			success: results.every(result => !result.error),
+			data: { results, changed: results.filter(result => result.changed).length, check },
+		};
+	},
+};
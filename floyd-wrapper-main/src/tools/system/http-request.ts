@@ -0,0 +1,164 @@
+/**
+ * HTTP Request Tool - Floyd Wrapper
+ *
+ * Raw HTTP calls for smoke-testing APIs without building curl commands: the
+ * response comes back as status, headers and body (JSON pretty-printed,
+ * long bodies truncated) and error statuses are results rather than
+ * failures, since a 404 or 500 is often what the agent is checking for.
+ * Unlike fetch nothing is converted or cached. Domains are limited by
+ * FLOYD_FETCH_ALLOWED_DOMAINS like fetch, redirects included (see
+ * SafetyEnforcer and allowed-fetch.ts); add localhost to test local servers
+ * when an allowlist is set.
+ */
+
+import { z } from 'zod';
+import type { ToolDefinition, ToolResult } from '../../types.js';
+import { truncateText } from '../../utils/html-to-markdown.js';
+import { fetchAllowed, RedirectBlockedError } from './allowed-fetch.js';
+
+/**
+ * Default size of the returned body
+ */
+const DEFAULT_MAX_BODY_CHARS = 10000;
+
+// ============================================================================
+// Zod Schema
+// ============================================================================
+
+const inputSchema = z.object({
+	url: z.string().url('Invalid URL'),
+	method: z.enum(['GET', 'POST', 'PUT', 'PATCH', 'DELETE', 'HEAD', 'OPTIONS']).optional().default('GET'),
+	headers: z.record(z.string()).optional(),
+	body: z.union([z.string(), z.record(z.unknown()), z.array(z.unknown())]).optional(),
+	timeout_ms: z.number().int().positive().optional().default(30000),
+	max_body_chars: z.number().int().positive().optional(),
+});
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Request body and headers; objects and arrays are sent as JSON
+ */
+export function buildRequestBody(
+	body: z.infer<typeof inputSchema>['body'],
+	headers: Record<string, string> = {}
+): { body?: string; headers: Record<string, string> } {
+	if (body === undefined) {
+		return { headers };
+	}
+	if (typeof body === 'string') {
+		return { body, headers };
+	}
+
+	const hasContentType = Object.keys(headers).some(name => name.toLowerCase() === 'content-type');
+	return {
+		body: JSON.stringify(body),
+		headers: hasContentType ? headers : { ...headers, 'content-type': 'application/json' },
+	};
+}
+
+/**
+ * Response body for the model: JSON pretty-printed, then truncated
+ */
+export function formatResponseBody(text: string, contentType: string, maxChars: number): { body: string; truncated: boolean } {
+	let formatted = text;
+	if (contentType.includes('json')) {
+		try {
+			formatted = JSON.stringify(JSON.parse(text), null, 2);
+		} catch {
+			// Not valid JSON after all; return it as text
+		}
+	}
+	const { text: body, truncated } = truncateText(formatted, maxChars);
+	return { body, truncated };
+}
+
+// ============================================================================
+// Tool Execution
+// ============================================================================
+
+async function execute(input: z.infer<typeof inputSchema>): Promise<ToolResult> {
+	const { url, method, timeout_ms, max_body_chars } = input;
+	const request = buildRequestBody(input.body, input.headers);
+	const started = Date.now();
+
+	try {
+		const { response, url: finalUrl, redirected } = await fetchAllowed(url, {
+			method,
+			headers: request.headers,
+			body: method === 'GET' || method === 'HEAD' ? undefined : request.body,
+			signal: AbortSignal.timeout(timeout_ms),
+		});
+		const text = method === 'HEAD' ? '' : await response.text();
+		const contentType = response.headers.get('content-type') || '';
+		const { body, truncated } = formatResponseBody(text, contentType, max_body_chars ?? DEFAULT_MAX_BODY_CHARS);
+
+		return {
+			success: true,
+			data: {
+				status: response.status,
+				statusText: response.statusText,
+				ok: response.ok,
+				...(redirected && { url: finalUrl }),
+				durationMs: Date.now() - started,
+				headers: Object.fromEntries(response.headers.entries()),
+				body,
+				bodyLength: text.length,
+				truncated,
+			},
+		};
+	} catch (error) {
+		if (error instanceof RedirectBlockedError) {
+			return {
+				success: false,
+				error: {
+					code: 'PERMISSION_DENIED',
+					message: error.message,
+					details: error.violation,
+				},
+			};
+		}
+
+		const err = error as Error & { cause?: { code?: string; message?: string } };
+
+		if (err.name === 'TimeoutError' || err.name === 'AbortError') {
+			return {
+				success: false,
+				error: {
+					code: 'TIMEOUT',
+					message: `Request timed out after ${timeout_ms}ms`,
+					details: { url, timeout_ms },
+				},
+			};
+		}
+
+		// fetch reports connection problems as "fetch failed" with the cause
+		const reason = err.cause?.code ?? err.cause?.message ?? err.message;
+		return {
+			success: false,
+			error: {
+				code: 'NETWORK_ERROR',
+				message: `${method} ${url} failed: ${reason}`,
+				details: { url, method },
+			},
+		};
+	}
+}
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+export const httpRequestTool: ToolDefinition = {
+	name: 'http_request',
+	description: 'Send an HTTP request to test an API (e.g. one you just built) and get status, headers, timing and body back. ' +
+		'Error statuses are returned as results, not failures. Objects and arrays in body are sent as JSON; ' +
+		'JSON responses are pretty-printed and long bodies truncated to max_body_chars (default 10000). ' +
+		'Use fetch to read documentation pages instead.',
+	category: 'build',
+	inputSchema,
+	permission: 'moderate',
+	execute,
+} as ToolDefinition;
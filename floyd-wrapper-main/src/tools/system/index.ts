@@ -7,17 +7,20 @@
 import { z } from 'zod';
 import type { ToolDefinition } from '../../types.js';
 import { execa } from 'execa';
+import { createToolOutputStream, budgetToolOutput } from '../../streaming/tool-output.js';
+import { getShutdownController } from '../../interrupts/shutdown-controller.js';
+import { loadShellConfig, shellInvocation, buildCommandEnv } from './shell-config.js';
 
 // ============================================================================
 // Run Tool
 // ============================================================================
 
-// Persistent CWD session
-let sessionCwd = process.cwd();
+// Persistent CWD session (set by "cd"; the configured or process cwd until then)
+let sessionCwd: string | null = null;
 
 export const runTool: ToolDefinition = {
 	name: 'run',
-	description: 'Execute terminal commands (supports persistent "cd")',
+	description: 'Execute terminal commands (supports persistent "cd"). With a shell configured in .floyd/shell.toml, a command line without args runs through that shell',
 	category: 'build',
 	inputSchema: z.object({
 		command: z.string(),
@@ -29,9 +32,10 @@ export const runTool: ToolDefinition = {
 	permission: 'dangerous',
 	execute: async (input) => {
 		const { command, args = [], cwd, timeout, env } = input as z.infer<typeof runTool.inputSchema>;
+		const shellConfig = loadShellConfig();
 
-		// Use provided CWD or session CWD
-		const executionCwd = cwd || sessionCwd;
+		// Use provided CWD, then session CWD, then the configured one
+		const executionCwd = cwd || sessionCwd || shellConfig.cwd || process.cwd();
 
 		// Handle "cd" manually as it's a shell builtin
 		if (command.trim() === 'cd') {
@@ -69,20 +73,41 @@ export const runTool: ToolDefinition = {
 			}
 		}
 
+		// Stream output to the UI while the command runs; the model gets
+		// the budgeted copy below
+		const output = createToolOutputStream('run');
+		const startedAt = Date.now();
+
+		// Command lines go through the configured shell; argv-style calls
+		// run the program directly
+		const { file, args: argv } = shellConfig.shell && args.length === 0
+			? shellInvocation(shellConfig.shell, command)
+			: { file: command, args };
+
 		try {
-			const result = await execa(command, args, {
+			const subprocess = execa(file, argv, {
 				cwd: executionCwd,
 				timeout,
-				env: { ...process.env, ...env },
+				env: buildCommandEnv(shellConfig, env),
+				extendEnv: false,
+				// Own process group, so shutdown and Ctrl+C can stop the
+				// command together with everything it started
+				detached: process.platform !== 'win32',
 			});
+			getShutdownController().trackProcess(subprocess);
+			subprocess.stdout?.on('data', (chunk: Buffer) => output.write(chunk.toString()));
+			subprocess.stderr?.on('data', (chunk: Buffer) => output.write(chunk.toString()));
+
+			const result = await subprocess;
 
 			return {
 				success: result.exitCode === 0,
 				data: {
 					exitCode: result.exitCode ?? null,
-					stdout: result.stdout || '',
-					stderr: result.stderr || '',
-					duration: 0
+					stdout: budgetToolOutput(result.stdout || ''),
+					stderr: budgetToolOutput(result.stderr || ''),
+					lines: output.getTotalLines(),
+					duration: Date.now() - startedAt
 				}
 			};
 		} catch (error) {
@@ -91,10 +116,13 @@ export const runTool: ToolDefinition = {
 				data: {
 					exitCode: null,
 					stdout: '',
-					stderr: (error as Error).message,
-					duration: 0
+					stderr: budgetToolOutput((error as Error).message),
+					lines: output.getTotalLines(),
+					duration: Date.now() - startedAt
 				}
 			};
+		} finally {
+			output.end();
 		}
 	}
 } as ToolDefinition;
@@ -103,21 +131,43 @@ export const runTool: ToolDefinition = {
 // Ask User Tool
 // ============================================================================
 
+/**
+ * Handler that presents a question to the user and resolves with their reply
+ */
+export type AskUserHandler = (question: string, options?: string[]) => Promise<string>;
+
+let askUserHandler: AskUserHandler | null = null;
+
+/**
+ * Register the interactive handler used by ask_user (set by the CLI)
+ */
+export function setAskUserHandler(handler: AskUserHandler | null): void {
+	askUserHandler = handler;
+}
+
 export const askUserTool: ToolDefinition = {
 	name: 'ask_user',
-	description: 'Ask the user for input',
+	description: 'Ask the user a clarifying question when requirements are ambiguous. Execution pauses until they answer. Provide options for multiple-choice questions.',
 	category: 'build',
 	inputSchema: z.object({
 		question: z.string(),
+		options: z.array(z.string()).optional(),
 	}),
 	permission: 'none',
 	execute: async (input) => {
-		const { question } = input as z.infer<typeof askUserTool.inputSchema>;
-		// In CLI mode, this would prompt the user
-		// For now, return a placeholder
+		const { question, options } = input as z.infer<typeof askUserTool.inputSchema>;
+
+		if (!askUserHandler) {
+			return {
+				success: true,
+				data: { question, response: 'User input not available in non-interactive mode' }
+			};
+		}
+
+		const response = await askUserHandler(question, options);
 		return {
 			success: true,
-			data: { question, response: 'User input not available in non-interactive mode' }
+			data: { question, response }
 		};
 	}
 } as ToolDefinition;
@@ -0,0 +1,363 @@
+/**
+ * Ports & Processes Tool - Floyd Wrapper
+ *
+ * Structured answers to "why is port 3000 busy" and "is the dev server still
+ * running" without shell pipelines: listening TCP ports and bound UDP ports
+ * with the process that owns them, and running processes filtered by name
+ * or pid.
+ *
+ * Linux reads /proc directly; macOS and other Unixes parse `lsof` and `ps`;
+ * Windows parses `netstat -ano` and `tasklist`. Sockets of other users'
+ * processes may show without a pid when FLOYD lacks the permission to see
+ * them.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { execa } from 'execa';
+import { z } from 'zod';
+import type { ToolDefinition } from '../../types.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A listening (TCP) or bound (UDP) port
+ */
+export interface PortEntry {
+	protocol: 'tcp' | 'tcp6' | 'udp' | 'udp6';
+	address: string;
+	port: number;
+	pid: number | null;
+	process: string | null;
+}
+
+/**
+ * A running process
+ */
+export interface ProcessEntry {
+	pid: number;
+	ppid: number | null;
+	name: string;
+	command: string;
+	cpuPercent?: number;
+	memPercent?: number;
+	/** Elapsed time as printed by ps ([[dd-]hh:]mm:ss) */
+	elapsed?: string;
+}
+
+/**
+ * Results are capped so a busy machine doesn't flood the context
+ */
+const MAX_ENTRIES = 200;
+
+// ============================================================================
+// Parsers
+// ============================================================================
+
+function hexToIpv4(hex: string): string {
+	// Stored as a little-endian 32-bit word
+	const bytes = hex.match(/../g)!.map(byte => parseInt(byte, 16)).reverse();
+	return bytes.join('.');
+}
+
+function hexToIpv6(hex: string): string {
+	// Four little-endian 32-bit words
+	const words = hex.match(/.{8}/g)!.map(word => word.match(/../g)!.reverse().join(''));
+	const groups = words.join('').match(/.{4}/g)!.map(group => parseInt(group, 16).toString(16));
+	const address = groups.join(':').replace(/(^|:)0(:0)+(:|$)/, '::');
+	const mapped = /^::ffff:([0-9a-f]+):([0-9a-f]+)$/.exec(address);
+	if (mapped) {
+		const high = parseInt(mapped[1], 16);
+		const low = parseInt(mapped[2], 16);
+		return `::ffff:${high >> 8}.${high & 255}.${low >> 8}.${low & 255}`;
+	}
+	return address;
+}
+
+/**
+ * Listening sockets in a /proc/net/{tcp,tcp6,udp,udp6} table, with socket
+ * inodes to find their process
+ */
+export function parseProcNet(text: string, protocol: PortEntry['protocol']): Array<PortEntry & { inode: string }> {
+	const entries: Array<PortEntry & { inode: string }> = [];
+	const isTcp = protocol.startsWith('tcp');
+
+	for (const line of text.split('\n').slice(1)) {
+		const fields = line.trim().split(/\s+/);
+		if (fields.length < 10) {
+			continue;
+		}
+		const [, local, , state] = fields;
+		// TCP_LISTEN, or an unconnected UDP socket
+		if (state !== (isTcp ? '0A' : '07')) {
+			continue;
+		}
+		const [addressHex, portHex] = local.split(':');
+		entries.push({
+			protocol,
+			address: addressHex.length === 8 ? hexToIpv4(addressHex) : hexToIpv6(addressHex),
+			port: parseInt(portHex, 16),
+			pid: null,
+			process: null,
+			inode: fields[9],
+		});
+	}
+
+	return entries;
+}
+
+function splitHostPort(name: string): { address: string; port: number } | null {
+	const match = /^\[?(.*?)\]?:(\d+)$/.exec(name);
+	return match ? { address: match[1], port: parseInt(match[2], 10) } : null;
+}
+
+/**
+ * Ports in `lsof -nP -i -F pcPtn` output (one field per line)
+ */
+export function parseLsof(output: string): PortEntry[] {
+	const entries: PortEntry[] = [];
+	let pid: number | null = null;
+	let command: string | null = null;
+	let protocol = 'tcp';
+	let ipv6 = false;
+
+	for (const line of output.split('\n')) {
+		const value = line.slice(1);
+		switch (line[0]) {
+			case 'p':
+				pid = parseInt(value, 10);
+				break;
+			case 'c':
+				command = value;
+				break;
+			case 't':
+				ipv6 = value === 'IPv6';
+				break;
+			case 'P':
+				protocol = value.toLowerCase();
+				break;
+			case 'n': {
+				// Connected sockets (local->remote) are not listening
+				if (value.includes('->')) {
+					break;
+				}
+				const hostPort = splitHostPort(value);
+				if (hostPort && (protocol === 'tcp' || protocol === 'udp')) {
+					entries.push({
+						protocol: `${protocol}${ipv6 ? '6' : ''}` as PortEntry['protocol'],
+						address: hostPort.address,
+						port: hostPort.port,
+						pid,
+						process: command,
+					});
+				}
+				break;
+			}
+		}
+	}
+
+	return entries;
+}
+
+/**
+ * Listening ports in Windows `netstat -ano` output
+ */
+export function parseNetstat(output: string): PortEntry[] {
+	const entries: PortEntry[] = [];
+
+	for (const line of output.split('\n')) {
+		const fields = line.trim().split(/\s+/);
+		const protocol = fields[0]?.toLowerCase();
+		const isListening = protocol === 'tcp' && fields[3] === 'LISTENING';
+		const isBound = protocol === 'udp' && fields[2] === '*:*';
+		if (!isListening && !isBound) {
+			continue;
+		}
+		const hostPort = splitHostPort(fields[1]);
+		if (!hostPort) {
+			continue;
+		}
+		entries.push({
+			protocol: `${protocol}${fields[1].startsWith('[') ? '6' : ''}` as PortEntry['protocol'],
+			address: hostPort.address,
+			port: hostPort.port,
+			pid: parseInt(fields[fields.length - 1], 10) || null,
+			process: null,
+		});
+	}
+
+	return entries;
+}
+
+/**
+ * Processes in `ps -eo pid=,ppid=,pcpu=,pmem=,etime=,args=` output
+ *
+ * The name is the program of the command line, so programs with spaces in
+ * their path get a shortened name (the command keeps the full line).
+ */
+export function parsePs(output: string): ProcessEntry[] {
+	const entries: ProcessEntry[] = [];
+
+	for (const line of output.split('\n')) {
+		const match = /^\s*(\d+)\s+(\d+)\s+([\d.]+)\s+([\d.]+)\s+(\S+)\s+(.+)$/.exec(line);
+		if (!match) {
+			continue;
+		}
+		entries.push({
+			pid: parseInt(match[1], 10),
+			ppid: parseInt(match[2], 10),
+			cpuPercent: parseFloat(match[3]),
+			memPercent: parseFloat(match[4]),
+			elapsed: match[5],
+			name: path.basename(match[6].split(' ')[0]),
+			command: match[6].trim(),
+		});
+	}
+
+	return entries;
+}
+
+/**
+ * Processes in Windows `tasklist /fo csv /nh` output
+ */
+export function parseTasklist(output: string): ProcessEntry[] {
+	return output
+		.split('\n')
+		.map(line => line.trim().match(/"([^"]*)"/g)?.map(field => field.slice(1, -1)))
+		.filter((fields): fields is string[] => Boolean(fields && fields.length >= 2 && /^\d+$/.test(fields[1])))
+		.map(([name, pid]) => ({ pid: parseInt(pid, 10), ppid: null, name, command: name }));
+}
+
+// ============================================================================
+// Collection
+// ============================================================================
+
+/**
+ * Map socket inodes to the pids holding them (Linux)
+ */
+async function socketOwners(inodes: Set<string>): Promise<Map<string, number>> {
+	const owners = new Map<string, number>();
+	const pids = (await fs.readdir('/proc')).filter(entry => /^\d+$/.test(entry));
+
+	await Promise.all(pids.map(async pid => {
+		const fdDir = path.join('/proc', pid, 'fd');
+		const fds = await fs.readdir(fdDir).catch(() => [] as string[]);
+		for (const fd of fds) {
+			const target = await fs.readlink(path.join(fdDir, fd)).catch(() => '');
+			const inode = /^socket:\[(\d+)\]$/.exec(target)?.[1];
+			if (inode && inodes.has(inode)) {
+				owners.set(inode, parseInt(pid, 10));
+			}
+		}
+	}));
+
+	return owners;
+}
+
+async function readProcName(pid: number): Promise<string | null> {
+	const comm = await fs.readFile(`/proc/${pid}/comm`, 'utf-8').catch(() => null);
+	return comm?.trim() || null;
+}
+
+/**
+ * Listening ports on this machine
+ */
+export async function listPorts(): Promise<PortEntry[]> {
+	if (process.platform === 'linux' && await fs.pathExists('/proc/net/tcp')) {
+		const tables = await Promise.all((['tcp', 'tcp6', 'udp', 'udp6'] as const).map(async protocol => {
+			const text = await fs.readFile(`/proc/net/${protocol}`, 'utf-8').catch(() => '');
+			return parseProcNet(text, protocol);
+		}));
+		const sockets = tables.flat();
+		const owners = await socketOwners(new Set(sockets.map(socket => socket.inode)));
+
+		return Promise.all(sockets.map(async ({ inode, ...entry }) => {
+			const pid = owners.get(inode) ?? null;
+			return { ...entry, pid, process: pid ? await readProcName(pid) : null };
+		}));
+	}
+
+	if (process.platform === 'win32') {
+		const { stdout } = await execa('netstat', ['-ano'], { reject: false });
+		return parseNetstat(stdout);
+	}
+
+	const [tcp, udp] = await Promise.all([
+		execa('lsof', ['-nP', '-iTCP', '-sTCP:LISTEN', '-F', 'pctPn'], { reject: false }),
+		execa('lsof', ['-nP', '-iUDP', '-F', 'pctPn'], { reject: false }),
+	]);
+	return [...parseLsof(tcp.stdout), ...parseLsof(udp.stdout)];
+}
+
+/**
+ * Processes running on this machine
+ */
+export async function listProcesses(): Promise<ProcessEntry[]> {
+	if (process.platform === 'win32') {
+		const { stdout } = await execa('tasklist', ['/fo', 'csv', '/nh'], { reject: false });
+		return parseTasklist(stdout);
+	}
+	const { stdout } = await execa('ps', ['-eo', 'pid=,ppid=,pcpu=,pmem=,etime=,args='], { reject: false });
+	return parsePs(stdout);
+}
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+const inputSchema = z.object({
+	action: z.enum(['ports', 'processes']).default('ports'),
+	port: z.number().int().positive().optional(),
+	pid: z.number().int().positive().optional(),
+	name: z.string().optional(),
+});
+
+export const psPortsTool: ToolDefinition = {
+	name: 'ps_ports',
+	description: 'Inspect listening ports and running processes without shell pipelines. ' +
+		'action "ports" lists listening TCP / bound UDP ports with the owning pid and process ' +
+		'(filter by port, pid or process name); action "processes" lists processes with pid, parent, ' +
+		'CPU/memory and command line (filter by pid or name).',
+	category: 'build',
+	inputSchema,
+	permission: 'none',
+	execute: async (input) => {
+		const { action, port, pid, name } = input as z.infer<typeof inputSchema>;
+		const nameFilter = name?.toLowerCase();
+
+		try {
+			if (action === 'ports') {
+				const ports = (await listPorts())
+					.filter(entry => port === undefined || entry.port === port)
+					.filter(entry => pid === undefined || entry.pid === pid)
+					.filter(entry => !nameFilter || entry.process?.toLowerCase().includes(nameFilter))
+					.sort((a, b) => a.port - b.port || a.protocol.localeCompare(b.protocol));
+
+				return {
+					success: true,
+					data: {
+						ports: ports.slice(0, MAX_ENTRIES),
+						total: ports.length,
+						...(port !== undefined && ports.length === 0 ? { note: `Nothing is listening on port ${port}` } : {}),
+					},
+				};
+			}
+
+			const processes = (await listProcesses())
+				.filter(entry => pid === undefined || entry.pid === pid)
+				.filter(entry => !nameFilter || entry.name.toLowerCase().includes(nameFilter) || entry.command.toLowerCase().includes(nameFilter));
+
+			return { success: true, data: { processes: processes.slice(0, MAX_ENTRIES), total: processes.length } };
+		} catch (error) {
+			return {
+				success: false,
+				error: {
+					code: 'TOOL_EXECUTION_FAILED',
+					message: `Could not inspect ${action}: ${(error as Error).message}`,
+				},
+			};
+		}
+	},
+};
@@ -0,0 +1,144 @@
+/**
+ * Shell Config - Floyd Wrapper
+ *
+ * Per-project settings for the run tool in .floyd/shell.toml:
+ *
+ *   shell = "zsh"              # bash, zsh, fish, pwsh, sh, cmd or a path
+ *   cwd = "packages/api"       # default directory, relative to the project
+ *   inherit_env = false        # start from PATH, HOME, USER, ... only
+ *
+ *   [env]
+ *   NODE_ENV = "test"
+ *
+ * With a shell set, command lines (a command without args) run through it,
+ * so pipes, globs and && work; argv-style calls still run the program
+ * directly. Without the file the run tool behaves as before.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { parseToml } from 'floyd-agent-core/ui';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export interface ShellConfig {
+	/** Shell for command lines; programs run directly when unset */
+	shell?: string;
+	/** Absolute default working directory */
+	cwd?: string;
+	/** Pass FLOYD's own environment on to commands */
+	inheritEnv: boolean;
+	/** Extra variables for every command */
+	env: Record<string, string>;
+}
+
+export const SHELL_CONFIG_FILE = path.join('.floyd', 'shell.toml');
+
+/**
+ * Variables kept when inherit_env is false, so commands can still be found
+ * and behave normally
+ */
+const BASE_ENV_VARS = ['PATH', 'HOME', 'USER', 'LOGNAME', 'SHELL', 'LANG', 'LC_ALL', 'TERM', 'TMPDIR', 'TEMP', 'TMP', 'SystemRoot', 'ComSpec', 'PATHEXT'];
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+/**
+ * Settings in a shell.toml document
+ *
+ * @throws Error for values of the wrong type
+ */
+export function parseShellConfig(source: string, projectRoot: string): ShellConfig {
+	const raw = parseToml(source);
+	const config: ShellConfig = { inheritEnv: true, env: {} };
+
+	if (raw.shell !== undefined) {
+		if (typeof raw.shell !== 'string' || !raw.shell.trim()) {
+			throw new Error('"shell" must be a shell name or path');
+		}
+		config.shell = raw.shell.trim();
+	}
+	if (raw.cwd !== undefined) {
+		if (typeof raw.cwd !== 'string') {
+			throw new Error('"cwd" must be a path');
+		}
+		config.cwd = path.resolve(projectRoot, raw.cwd);
+	}
+	if (raw.inherit_env !== undefined) {
+		if (typeof raw.inherit_env !== 'boolean') {
+			throw new Error('"inherit_env" must be true or false');
+		}
+		config.inheritEnv = raw.inherit_env;
+	}
+	if (raw.env !== undefined) {
+		if (typeof raw.env !== 'object') {
+			throw new Error('"env" must be a table, e.g. [env]');
+		}
+		for (const [name, value] of Object.entries(raw.env)) {
+			if (typeof value === 'object') {
+				throw new Error(`env.${name} must be a string, number or boolean`);
+			}
+			config.env[name] = String(value);
+		}
+	}
+
+	return config;
+}
+
+/**
+ * Run tool settings of a project; defaults when .floyd/shell.toml is
+ * missing or invalid
+ */
+export function loadShellConfig(projectRoot: string = process.cwd()): ShellConfig {
+	const filePath = path.join(projectRoot, SHELL_CONFIG_FILE);
+	if (!fs.existsSync(filePath)) {
+		return { inheritEnv: true, env: {} };
+	}
+
+	try {
+		return parseShellConfig(fs.readFileSync(filePath, 'utf-8'), projectRoot);
+	} catch (error) {
+		console.warn(`Ignoring ${SHELL_CONFIG_FILE}: ${(error as Error).message}`);
+		return { inheritEnv: true, env: {} };
+	}
+}
+
+// ============================================================================
+// Invocation
+// ============================================================================
+
+/**
+ * Program and arguments that run a command line in a shell
+ */
+export function shellInvocation(shell: string, commandLine: string): { file: string; args: string[] } {
+	const name = path.basename(shell).toLowerCase().replace(/\.exe$/, '');
+
+	switch (name) {
+		case 'pwsh':
+		case 'powershell':
+			return { file: shell, args: ['-NoLogo', '-NoProfile', '-NonInteractive', '-Command', commandLine] };
+		case 'cmd':
+			return { file: shell, args: ['/d', '/s', '/c', commandLine] };
+		default:
+			// sh, bash, zsh, fish, dash, ksh, ...
+			return { file: shell, args: ['-c', commandLine] };
+	}
+}
+
+/**
+ * Environment for a command: inherited or base variables, then the
+ * configured ones, then the call's own
+ */
+export function buildCommandEnv(
+	config: ShellConfig,
+	extra: Record<string, string> = {},
+	parent: NodeJS.ProcessEnv = process.env
+): Record<string, string | undefined> {
+	const base = config.inheritEnv
+		? { ...parent }
+		: Object.fromEntries(BASE_ENV_VARS.filter(name => parent[name] !== undefined).map(name => [name, parent[name]]));
+	return { ...base, ...config.env, ...extra };
+}
@@ -0,0 +1,443 @@
+/**
+ * Shell Session Tool - Floyd Wrapper
+ *
+ * A long-lived shell per FLOYD session, so `cd`, exported variables,
+ * activated virtualenvs and shell functions carry over from one call to the
+ * next (each `run` call starts a fresh process). The shell runs in a PTY, so
+ * commands see a terminal and prompts (sudo, ssh, `read`) are answered with
+ * action "send". Commands are written to the terminal and their end is
+ * detected with a marker line carrying the exit status; output can be read
+ * incrementally while a command is still running, e.g. for dev servers and
+ * watchers.
+ *
+ * On start the shell's prompts, line editing and terminal echo are turned
+ * off so reads return only command output. Shells are stopped on exit, or
+ * with action "close".
+ */
+
+import crypto from 'node:crypto';
+import path from 'node:path';
+import * as pty from 'node-pty';
+import { z } from 'zod';
+import type { ToolDefinition } from '../../types.js';
+import { budgetToolOutput } from '../../streaming/tool-output.js';
+import { getShutdownController } from '../../interrupts/shutdown-controller.js';
+import { loadShellConfig, buildCommandEnv } from './shell-config.js';
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * How long run and read wait for a command to finish before returning the
+ * output so far
+ */
+const DEFAULT_WAIT_MS = 30_000;
+
+/**
+ * How long send waits for the shell to react
+ */
+const SEND_WAIT_MS = 500;
+
+/**
+ * Unread output kept per shell; older output is dropped past this
+ */
+export const MAX_BUFFER_CHARS = 1_000_000;
+
+// ============================================================================
+// Shell Session
+// ============================================================================
+
+/**
+ * Output collected since the last read
+ */
+export interface ShellOutput {
+	output: string;
+	/** Whether the last command finished (false while it is still running) */
+	done: boolean;
+	exitCode: number | null;
+	/** Whether the shell itself has exited */
+	closed: boolean;
+}
+
+/**
+ * Shell input that runs a command and then prints the end marker with its
+ * exit status
+ *
+ * The command is grouped with the marker so the shell parses both before
+ * running either; a command reading stdin then gets the input sent to it,
+ * not the marker.
+ */
+export function wrapCommand(shell: string, command: string, marker: string): string {
+	const name = path.basename(shell).toLowerCase().replace(/\.exe$/, '');
+	if (name === 'fish') {
+		return `begin\n${command}\nend; printf '\\n${marker}:%s\\n' $status\n`;
+	}
+	if (name === 'pwsh' || name === 'powershell') {
+		return `${command}\nWrite-Output "\`n${marker}:$(if ($?) { 0 } elseif ($LASTEXITCODE) { $LASTEXITCODE } else { 1 })"\n`;
+	}
+	return `{ ${command}\n}; printf '\\n${marker}:%s\\n' "$?"\n`;
+}
+
+/**
+ * Arguments that start the shell without rc files, which could print
+ * banners or prompt decorations
+ */
+function shellArgs(name: string): string[] {
+	if (name === 'pwsh' || name === 'powershell') {
+		return ['-NoLogo', '-NoProfile', '-Command', '-'];
+	}
+	if (name === 'bash') {
+		return ['--noprofile', '--norc'];
+	}
+	if (name === 'zsh') {
+		return ['-f'];
+	}
+	return [];
+}
+
+/**
+ * First command: turns off terminal echo, line editing and prompts so only
+ * command output is left (null for shells that need none of it)
+ */
+function initCommand(name: string): string | null {
+	if (name === 'zsh') {
+		return 'stty -echo 2>/dev/null; unsetopt zle prompt_sp prompt_cr; PS1= PS2= RPS1=';
+	}
+	if (name === 'fish' || name === 'pwsh' || name === 'powershell') {
+		return null;
+	}
+	return 'stty -echo 2>/dev/null; set +o emacs +o vi 2>/dev/null; PS1= PS2=';
+}
+
+/**
+ * A long-lived shell process
+ */
+export class ShellSession {
+	private readonly terminal: pty.IPty;
+	private buffer = '';
+	/** Characters dropped from the front of the buffer since the last read */
+	private dropped = 0;
+	/** Marker of the init command; its output is discarded */
+	private initMarker: string | null = null;
+	/** Input held back until the init command has run, so it isn't echoed */
+	private pending = '';
+	private marker: string | null = null;
+	private exitCode: number | null = null;
+	private shellExitCode: number | null = null;
+	private closed = false;
+	private waiters: Array<() => void> = [];
+
+	constructor(
+		readonly shell: string,
+		cwd: string,
+		env: Record<string, string | undefined>
+	) {
+		if (getShutdownController().isShuttingDown()) {
+			throw new Error('FLOYD is shutting down');
+		}
+
+		const name = path.basename(shell).toLowerCase().replace(/\.exe$/, '');
+		const definedEnv: Record<string, string> = {};
+		for (const [key, value] of Object.entries(env)) {
+			if (value !== undefined) {
+				definedEnv[key] = value;
+			}
+		}
+
+		this.terminal = pty.spawn(shell, shellArgs(name), {
+			name: 'xterm-256color',
+			cols: 120,
+			rows: 40,
+			cwd,
+			env: definedEnv,
+		});
+		this.terminal.onData(data => this.append(data));
+		this.terminal.onExit(({ exitCode }) => {
+			this.shellExitCode = exitCode;
+			if (this.marker) {
+				// The running command took the shell with it
+				this.exitCode = exitCode;
+				this.marker = null;
+			}
+			this.closed = true;
+			this.notify();
+		});
+
+		const init = initCommand(name);
+		if (init) {
+			this.initMarker = `__FLOYD_READY_${crypto.randomBytes(6).toString('hex')}__`;
+			this.terminal.write(wrapCommand(shell, init, this.initMarker));
+		}
+	}
+
+	/**
+	 * Whether a command is still running
+	 */
+	isBusy(): boolean {
+		return this.marker !== null && !this.closed;
+	}
+
+	isClosed(): boolean {
+		return this.closed;
+	}
+
+	/**
+	 * Exit code of the shell itself (null while it is running)
+	 */
+	getShellExitCode(): number | null {
+		return this.shellExitCode;
+	}
+
+	/**
+	 * Start a command; its end is recognized by a marker line
+	 */
+	run(command: string): void {
+		this.marker = `__FLOYD_DONE_${crypto.randomBytes(6).toString('hex')}__`;
+		this.exitCode = null;
+		this.write(wrapCommand(this.shell, command, this.marker));
+	}
+
+	/**
+	 * Write raw input, e.g. an answer to a prompt of the running command
+	 */
+	send(input: string): void {
+		this.write(input.endsWith('\n') ? input : `${input}\n`);
+	}
+
+	/**
+	 * Wait until the running command finishes, output arrives (when
+	 * `untilOutput`), the shell exits or the time is up
+	 */
+	async wait(ms: number, untilOutput = false): Promise<void> {
+		const deadline = Date.now() + ms;
+		while (this.isBusy() && Date.now() < deadline && !(untilOutput && this.hasOutput())) {
+			await new Promise<void>(resolve => {
+				const timer = setTimeout(resolve, Math.max(0, deadline - Date.now()));
+				this.waiters.push(() => {
+					clearTimeout(timer);
+					resolve();
+				});
+			});
+		}
+	}
+
+	/**
+	 * Take the output collected since the last read
+	 */
+	read(): ShellOutput {
+		let output = '';
+		if (this.hasOutput()) {
+			output = this.dropped > 0 ? `[${this.dropped} earlier characters dropped]\n${this.buffer}` : this.buffer;
+			this.buffer = '';
+			this.dropped = 0;
+		}
+		return { output, done: !this.isBusy(), exitCode: this.exitCode, closed: this.closed };
+	}
+
+	/**
+	 * Stop the shell and everything it started
+	 */
+	close(): void {
+		if (this.closed) {
+			return;
+		}
+		this.closed = true;
+		try {
+			// The shell leads its own process group; SIGHUP is what closing a
+			// terminal sends, and interactive shells ignore SIGTERM
+			if (process.platform !== 'win32') {
+				process.kill(-this.terminal.pid, 'SIGHUP');
+			} else {
+				this.terminal.kill();
+			}
+		} catch {
+			// Already gone
+		}
+		this.notify();
+	}
+
+	private hasOutput(): boolean {
+		return this.initMarker === null && (this.buffer !== '' || this.dropped > 0);
+	}
+
+	private write(text: string): void {
+		if (this.closed) {
+			throw new Error('Shell session has exited');
+		}
+		if (this.initMarker) {
+			this.pending += text;
+		} else {
+			this.terminal.write(text);
+		}
+	}
+
+	private append(text: string): void {
+		const chunk = text.replace(/\r\n/g, '\n');
+		if (chunk.startsWith('\n') && this.buffer.endsWith('\r')) {
+			this.buffer = this.buffer.slice(0, -1);
+		}
+		this.buffer += chunk;
+
+		if (this.initMarker) {
+			const match = new RegExp(`\\n?${this.initMarker}:-?\\d+\\r?\\n`).exec(this.buffer);
+			if (!match) {
+				return;
+			}
+			this.buffer = this.buffer.slice(match.index + match[0].length);
+			this.initMarker = null;
+			if (this.pending) {
+				this.terminal.write(this.pending);
+				this.pending = '';
+			}
+		}
+
+		if (this.marker) {
+			const match = new RegExp(`\\n?${this.marker}:(-?\\d+)\\r?\\n`).exec(this.buffer);
+			if (match) {
+				this.exitCode = parseInt(match[1], 10);
+				this.buffer = this.buffer.slice(0, match.index) + this.buffer.slice(match.index + match[0].length);
+				this.marker = null;
+			}
+		}
+
+		const excess = this.buffer.length - MAX_BUFFER_CHARS;
+		if (excess > 0) {
+			this.buffer = this.buffer.slice(excess);
+			this.dropped += excess;
+		}
+		this.notify();
+	}
+
+	private notify(): void {
+		const waiters = this.waiters;
+		this.waiters = [];
+		for (const wake of waiters) {
+			wake();
+		}
+	}
+}
+
+// ============================================================================
+// Sessions
+// ============================================================================
+
+const sessions = new Map<string, ShellSession>();
+
+/**
+ * The named shell, started on first use with the shell, directory and
+ * environment from .floyd/shell.toml
+ *
+ * A shell that has exited is returned as is, so its last output and exit
+ * code can still be reported; it is replaced once removed with
+ * `sessions.delete`.
+ */
+export function getShellSession(name: string = 'default'): ShellSession {
+	let session = sessions.get(name);
+	if (!session) {
+		const config = loadShellConfig();
+		const shell = config.shell || (process.platform === 'win32' ? 'pwsh' : process.env.SHELL || 'sh');
+		session = new ShellSession(shell, config.cwd || process.cwd(), buildCommandEnv(config));
+		sessions.set(name, session);
+	}
+	return session;
+}
+
+/**
+ * Stop every shell session (on exit)
+ */
+export function closeAllShellSessions(): void {
+	for (const session of sessions.values()) {
+		session.close();
+	}
+	sessions.clear();
+}
+
+// ============================================================================
+// Tool Definition
+// ============================================================================
+
+const inputSchema = z.object({
+	action: z.enum(['run', 'send', 'read', 'close']).default('run'),
+	command: z.string().optional(),
+	input: z.string().optional(),
+	session: z.string().optional().default('default'),
+	wait_ms: z.number().int().nonnegative().optional(),
+});
+
+export const shellSessionTool: ToolDefinition = {
+	name: 'shell_session',
+	description: 'Persistent shell: cd, exported variables and activated environments carry over between calls. ' +
+		'action "run" executes a command and waits up to wait_ms (default 30s); if it is still running, ' +
+		'use "read" to get new output, "send" to answer a prompt, "close" to stop the shell. ' +
+		'Runs in a terminal, but output is read as text: avoid full-screen programs.',
+	category: 'build',
+	inputSchema,
+	permission: 'dangerous',
+	execute: async (input) => {
+		const { action, command, input: text, session: name, wait_ms } = input as z.infer<typeof inputSchema>;
+
+		if (action === 'close') {
+			const session = sessions.get(name);
+			session?.close();
+			sessions.delete(name);
+			return { success: true, data: { session: name, closed: true } };
+		}
+
+		let session: ShellSession;
+		try {
+			session = getShellSession(name);
+		} catch (error) {
+			return { success: false, error: { code: 'TOOL_EXECUTION_FAILED', message: (error as Error).message } };
+		}
+
+		if (session.isClosed()) {
+			// Report the exit once; the next call starts a new shell
+			sessions.delete(name);
+			const result = session.read();
+			return {
+				success: false,
+				error: {
+					code: 'TOOL_EXECUTION_FAILED',
+					message: `Shell "${name}" exited with code ${session.getShellExitCode() ?? 'unknown'}; the next call starts a new shell`,
+					details: { session: name, ...result, output: budgetToolOutput(result.output) },
+				},
+			};
+		}
+
+		try {
+			if (action === 'run') {
+				if (!command) {
+					return { success: false, error: { code: 'INVALID_INPUT', message: 'action "run" needs a command' } };
+				}
+				if (session.isBusy()) {
+					return {
+						success: false,
+						error: {
+							code: 'CONFLICT',
+							message: `A command is still running in shell "${name}"; read its output, send input, or close the shell`,
+						},
+					};
+				}
+				session.run(command);
+				await session.wait(wait_ms ?? DEFAULT_WAIT_MS);
+			} else if (action === 'send') {
+				if (text === undefined) {
+					return { success: false, error: { code: 'INVALID_INPUT', message: 'action "send" needs input' } };
+				}
+				session.send(text);
+				await session.wait(wait_ms ?? SEND_WAIT_MS, true);
+			} else {
+				await session.wait(wait_ms ?? DEFAULT_WAIT_MS, true);
+			}
+		} catch (error) {
+			return { success: false, error: { code: 'TOOL_EXECUTION_FAILED', message: (error as Error).message } };
+		}
+
+		const result = session.read();
+		return {
+			success: !result.done || result.exitCode === 0,
+			data: { session: name, ...result, output: budgetToolOutput(result.output) },
+		};
+	},
+};
@@ -0,0 +1,88 @@
+/**
+ * Todo Tool - Floyd Wrapper
+ *
+ * Lets the model keep a task list for the current goal. The list is shown
+ * to the user as it changes and mirrored to .floyd/master_plan.md; finished
+ * tasks are logged to .floyd/progress.md.
+ */
+
+import { z } from 'zod';
+import { appendProgress } from 'floyd-agent-core/utils';
+import type { ToolDefinition } from '../../types.js';
+import { getTodoList, mirrorToMasterPlan, todoProgress, formatTodoLines, TODO_STATUSES } from './todo-core.js';
+
+// ============================================================================
+// Todo Tool
+// ============================================================================
+
+export const todoTool: ToolDefinition = {
+	name: 'todo',
+	description: 'Track the task list for the current goal so the user can follow progress. At the start of multi-step work, call it with `todos` (the whole list, replacing any previous one) and an optional `goal`. As you work, call it with `update` to mark tasks in_progress (one at a time) and completed right after finishing them. Skip it for single-step requests.',
+	category: 'special',
+	inputSchema: z.object({
+		goal: z.string().optional(),
+		todos: z.array(z.object({
+			text: z.string().min(1, 'Task text is required'),
+			status: z.enum(TODO_STATUSES).optional(),
+		})).optional(),
+		update: z.array(z.object({
+			id: z.number().int().positive(),
+			status: z.enum(TODO_STATUSES),
+		})).optional(),
+	}),
+	permission: 'none',
+	execute: async (input) => {
+		const { goal, todos, update } = input as {
+			goal?: string;
+			todos?: Array<{ text: string; status?: typeof TODO_STATUSES[number] }>;
+			update?: Array<{ id: number; status: typeof TODO_STATUSES[number] }>;
+		};
+		if (!todos && !update) {
+			return {
+				success: false,
+				error: {
+					code: 'INVALID_INPUT',
+					message: 'Provide todos (to set the list) or update (to change task status)',
+				},
+			};
+		}
+
+		const list = getTodoList();
+		try {
+			if (todos) {
+				list.set(todos, goal);
+			}
+			const before = list.snapshot().items;
+			const snapshot = update ? list.update(update) : list.snapshot();
+			const planPath = await mirrorToMasterPlan(snapshot);
+
+			// Log tasks this call finished
+			const next = snapshot.items.find(item => item.status !== 'completed');
+			for (const item of snapshot.items) {
+				const previous = before.find(candidate => candidate.id === item.id);
+				if (update && item.status === 'completed' && previous?.status !== 'completed') {
+					appendProgress({ action: item.text, result: 'Completed', next: next?.text ?? '' });
+				}
+			}
+			const { done, total } = todoProgress(snapshot);
+
+			return {
+				success: true,
+				data: {
+					goal: snapshot.goal,
+					progress: `${done}/${total} completed`,
+					tasks: formatTodoLines(snapshot),
+					mirroredTo: planPath,
+				},
+			};
+		} catch (error) {
+			return {
+				success: false,
+				error: {
+					code: 'TODO_ERROR',
+					message: (error as Error).message,
+				},
+			};
+		}
+	},
+} as ToolDefinition;
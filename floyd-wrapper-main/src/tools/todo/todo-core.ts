@@ -0,0 +1,185 @@
+/**
+ * Todo Core - Floyd Wrapper
+ *
+ * The task list for the current goal, kept by the model through the `todo`
+ * tool. Every change is mirrored to a checkbox section of
+ * .floyd/master_plan.md (so /continue can pick it up later) and announced to
+ * listeners, which render the live list in the terminal.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export const TODO_STATUSES = ['pending', 'in_progress', 'completed'] as const;
+
+export type TodoStatus = typeof TODO_STATUSES[number];
+
+export interface TodoItem {
+	/** 1-based position in the list */
+	id: number;
+	text: string;
+	status: TodoStatus;
+}
+
+export interface TodoSnapshot {
+	goal: string;
+	items: TodoItem[];
+}
+
+export type TodoListener = (snapshot: TodoSnapshot) => void;
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Markers around the mirrored section; the rest of master_plan.md is left alone
+ */
+const PLAN_START = '<!-- floyd:todo:start -->';
+const PLAN_END = '<!-- floyd:todo:end -->';
+
+// ============================================================================
+// Todo List
+// ============================================================================
+
+export class TodoList {
+	private goal = '';
+	private items: TodoItem[] = [];
+	private listeners = new Set<TodoListener>();
+
+	/**
+	 * Replace the list (and the goal, when given)
+	 */
+	set(items: Array<{ text: string; status?: TodoStatus }>, goal?: string): TodoSnapshot {
+		if (goal !== undefined) {
+			this.goal = goal.trim();
+		}
+		this.items = items
+			.filter(item => item.text.trim())
+			.map((item, index) => ({ id: index + 1, text: item.text.trim(), status: item.status ?? 'pending' }));
+		return this.changed();
+	}
+
+	/**
+	 * Change the status of items by ID
+	 *
+	 * @throws When an ID is not in the list
+	 */
+	update(changes: Array<{ id: number; status: TodoStatus }>): TodoSnapshot {
+		for (const change of changes) {
+			const item = this.items.find(candidate => candidate.id === change.id);
+			if (!item) {
+				throw new Error(`No task #${change.id} (the list has ${this.items.length})`);
+			}
+			item.status = change.status;
+		}
+		return this.changed();
+	}
+
+	clear(): void {
+		this.goal = '';
+		this.items = [];
+		this.changed();
+	}
+
+	snapshot(): TodoSnapshot {
+		return { goal: this.goal, items: this.items.map(item => ({ ...item })) };
+	}
+
+	/**
+	 * Be told about every change
+	 *
+	 * @returns Unsubscribe function
+	 */
+	subscribe(listener: TodoListener): () => void {
+		this.listeners.add(listener);
+		return () => this.listeners.delete(listener);
+	}
+
+	private changed(): TodoSnapshot {
+		const snapshot = this.snapshot();
+		for (const listener of this.listeners) {
+			listener(snapshot);
+		}
+		return snapshot;
+	}
+}
+
+let todoList: TodoList | null = null;
+
+/**
+ * Get the task list of this process
+ */
+export function getTodoList(): TodoList {
+	if (!todoList) {
+		todoList = new TodoList();
+	}
+	return todoList;
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+/**
+ * Completed and total counts
+ */
+export function todoProgress(snapshot: TodoSnapshot): { done: number; total: number } {
+	return {
+		done: snapshot.items.filter(item => item.status === 'completed').length,
+		total: snapshot.items.length,
+	};
+}
+
+/**
+ * Plain-text lines of the list, e.g. "[x] 1. Add the route"
+ */
+export function formatTodoLines(snapshot: TodoSnapshot): string[] {
+	const marks: Record<TodoStatus, string> = { pending: '[ ]', in_progress: '[~]', completed: '[x]' };
+	return snapshot.items.map(item => `${marks[item.status]} ${item.id}. ${item.text}`);
+}
+
+/**
+ * The list as a master_plan.md section, between markers
+ */
+export function renderPlanSection(snapshot: TodoSnapshot): string {
+	const { done, total } = todoProgress(snapshot);
+	const lines = [
+		PLAN_START,
+		`## Current Tasks${snapshot.goal ? `: ${snapshot.goal}` : ''}`,
+		'',
+		`_Kept by FLOYD's todo tool - ${done}/${total} done._`,
+		'',
+		...snapshot.items.map(item => `- [${item.status === 'completed' ? 'x' : ' '}] ${item.text}${item.status === 'in_progress' ? ' _(in progress)_' : ''}`),
+		PLAN_END,
+	];
+	return lines.join('\n');
+}
+
+/**
+ * Write the list into .floyd/master_plan.md, replacing the previous copy of
+ * the section or appending it
+ */
+export async function mirrorToMasterPlan(snapshot: TodoSnapshot, cwd: string = process.cwd()): Promise<string> {
+	const planPath = path.join(cwd, '.floyd', 'master_plan.md');
+	const existing = await fs.readFile(planPath, 'utf-8').catch(() => '');
+	const section = renderPlanSection(snapshot);
+
+	const start = existing.indexOf(PLAN_START);
+	const end = existing.indexOf(PLAN_END, start);
+	let content: string;
+	if (start !== -1 && end !== -1) {
+		content = existing.slice(0, start) + section + existing.slice(end + PLAN_END.length);
+	} else if (existing.trim()) {
+		content = `${existing.trimEnd()}\n\n${section}\n`;
+	} else {
+		content = `# Master Plan\n\n${section}\n`;
+	}
+
+	await fs.outputFile(planPath, content);
+	return planPath;
+}
@@ -5,11 +5,56 @@
  */
 
 import { z } from 'zod';
+import fs from 'fs-extra';
+import path from 'node:path';
 import type { ToolDefinition, ToolResult, ToolCategory, ToolReceipt, ReceiptType, Receipt } from '../types.js';
 import { logger } from '../utils/logger.js';
 import { ToolExecutionError } from '../utils/errors.js';
-import { getCheckpointManager, DANGEROUS_TOOLS, type Checkpoint } from '../rewind/index.js';
+import { getCheckpointManager, getChangeJournal, countFileLines, hashFile, DANGEROUS_TOOLS, type Checkpoint } from '../rewind/index.js';
 import { getSandboxManager } from '../sandbox/index.js';
+import { getSafetyEnforcer } from '../permissions/safety-enforcer.js';
+import { getDiffPreviewer, computeProposedChange } from '../permissions/diff-preview.js';
+import { isAutoFormatEnabled, autoFormatFiles, autoFormatContent, AUTO_FORMAT_TOOLS } from './system/format.js';
+import { isDryRun, isReadOnlyTool, simulateToolCall } from '../permissions/dry-run.js';
+import { getBranchNotes } from '../persistence/branch-notes.js';
+import { getEventBroadcaster } from '../streaming/event-broadcaster.js';
+import { ToolMetrics, type ToolStats } from 'floyd-agent-core/utils';
+import { isRedactionEnabled, countPlaceholders, PLACEHOLDER_PREFIX } from '../utils/redaction.js';
+
+/**
+ * Tools whose file changes are recorded in the change journal
+ */
+const JOURNALED_TOOLS = [
+  'write', 'write_file', 'edit_file', 'search_replace', 'json_edit',
+  'apply_unified_diff', 'edit_range', 'insert_at', 'delete_range',
+  'delete_file', 'move_file', 'format',
+];
+
+/**
+ * Input fields holding text a file-writing tool puts into a file
+ */
+const WRITTEN_TEXT_FIELDS: Record<string, string> = {
+  write: 'content',
+  write_file: 'content',
+  edit_file: 'new_string',
+  search_replace: 'replace_string',
+  edit_range: 'content',
+  insert_at: 'content',
+  apply_unified_diff: 'diff',
+};
+
+/**
+ * Tools that modify files - need sandbox translation
+ */
+const SANDBOXED_TOOLS = [
+  'write', 'write_file', 'edit_file', 'create_file',
+  'delete', 'delete_file', 'remove', 'rm',
+  'move', 'move_file', 'rename', 'mv',
+  'replace_in_file', 'search_replace', 'json_edit',
+  'apply_unified_diff', 'patch',
+  'edit_range', 'insert_at', 'delete_range',
+  'format',
+];
 
 // ============================================================================
 // Tool Registry Class
@@ -39,6 +84,12 @@ export class ToolRegistry {
    */
   private ignorePatterns: string[] = [];
 
+  /**
+   * Calls, failures and latency per tool; time spent in permission prompts
+   * and diff previews is not counted
+   */
+  private metrics = new ToolMetrics();
+
   constructor() {
     this.tools = new Map();
     this.toolsByCategory = new Map();
@@ -46,7 +97,7 @@ export class ToolRegistry {
     this.ignorePatterns = [];
 
     // Initialize category sets
-    const categories: ToolCategory[] = ['file', 'search', 'build', 'git', 'browser', 'cache', 'patch', 'special'];
+    const categories: ToolCategory[] = ['file', 'search', 'build', 'git', 'browser', 'cache', 'patch', 'special', 'custom'];
     for (const category of categories) {
       this.toolsByCategory.set(category, new Set());
     }
@@ -300,7 +351,7 @@ export class ToolRegistry {
     input: unknown,
     options: { permissionGranted?: boolean } = {}
   ): Promise<ToolResult> {
-    const tool = this.tools.get(name);
+    let tool = this.tools.get(name);
 
     if (!tool) {
       return {
@@ -344,6 +395,44 @@ export class ToolRegistry {
       };
     }
 
+    // Safety rules apply even when permission was granted upfront
+    const violation = getSafetyEnforcer().checkAction(name, validatedInput);
+    if (violation) {
+      logger.warn(`Safety rule "${violation.rule}" blocked tool: ${name}`, { target: violation.target });
+
+      return {
+        success: false,
+        error: {
+          code: 'PERMISSION_DENIED',
+          message: `Blocked by safety rule: ${violation.reason}`,
+          details: violation,
+        },
+      };
+    }
+
+    // The model only saw placeholders for the secrets in what it read;
+    // writing those back would replace the real values
+    const redactedTarget = await this.findRedactedWrite(name, validatedInput as Record<string, unknown>);
+    if (redactedTarget !== null) {
+      logger.warn(`Blocked write of redaction placeholders: ${name}`, { target: redactedTarget });
+
+      return {
+        success: false,
+        error: {
+          code: 'VALIDATION_ERROR',
+          message: `Refusing to write ${PLACEHOLDER_PREFIX}...] placeholders to ${redactedTarget}: they stand for secrets you were not shown, ` +
+            'and writing them would destroy the real values. Edit around those lines instead, or ask the user to change the secrets.',
+          details: { tool: name, target: redactedTarget },
+        },
+      };
+    }
+
+    // In a dry run, report what the call would do instead of doing it
+    if (isDryRun() && !isReadOnlyTool(name)) {
+      logger.info(`Dry run: simulated tool ${name}`);
+      return { success: true, data: await simulateToolCall(name, validatedInput) };
+    }
+
     // Check permissions
     if (tool.permission !== 'none' && !options.permissionGranted) {
       if (!this.shouldGrantPermission(tool)) {
@@ -363,20 +452,71 @@ export class ToolRegistry {
       }
     }
 
+    // Let the user review file changes before they reach the disk, formatted
+    // as they will be written when auto-format is on
+    const autoFormatting = isAutoFormatEnabled() && AUTO_FORMAT_TOOLS.has(name);
+    const review = await getDiffPreviewer().review(name, validatedInput, autoFormatting ? autoFormatContent : undefined);
+    if (!review.approved) {
+      logger.info(`Change rejected in diff preview: ${name}`);
+
+      return {
+        success: false,
+        error: {
+          code: 'PERMISSION_DENIED',
+          message: review.reason
+            ? `Change rejected by user: ${review.reason}`
+            : 'Change rejected by user in diff preview',
+          details: { tool: name },
+        },
+      };
+    }
+
+    // An edited change is applied as a plain write of the user's content
+    const writeTool = this.tools.get('write');
+    if (review.editedContent !== undefined && writeTool) {
+      tool = writeTool;
+      name = 'write';
+      validatedInput = { file_path: review.filePath, content: review.editedContent, overwrite: true };
+    }
+
     // Execute tool
     logger.debug(`Executing tool: ${name}`);
 
     // FIX #1: Translate paths to sandbox if active (YOLO mode)
     const inputForExecution = this.translateInputToSandbox(name, validatedInput as Record<string, unknown>);
 
+    // Capture line counts and hashes before the change for the change journal
+    // and the audit log
+    const journalTargets = this.getJournalTargets(name, validatedInput as Record<string, unknown>, inputForExecution);
+    const linesBefore = await Promise.all(
+      journalTargets.map(target => countFileLines(target.executedPath))
+    );
+    const hashesBefore = await Promise.all(
+      journalTargets.map(target => hashFile(target.executedPath))
+    );
+
+    const startedAt = Date.now();
     try {
-      const result = await tool.execute(inputForExecution);
+      let result = await tool.execute(inputForExecution);
+      this.metrics.record(name, Date.now() - startedAt, Boolean(result?.success));
+
+      // Reformat what the tool wrote, before the journal records the result;
+      // content the user edited in the preview is written as they left it
+      if (result?.success && autoFormatting && review.editedContent === undefined) {
+        result = await this.autoFormat(result, journalTargets.map(target => target.executedPath));
+      }
+
+      if (result?.success && journalTargets.length > 0) {
+        await this.recordJournalChanges(name, journalTargets, linesBefore, hashesBefore);
+      }
 
       // FIX #1: Track sandbox changes after execution
       if (result && typeof result === 'object') {
         this.trackSandboxChanges(name, inputForExecution as Record<string, unknown>, result as unknown);
       }
 
+      await this.recordBranchVerification(name, validatedInput as Record<string, unknown>, result);
+
       logger.debug(`Tool ${name} completed successfully`);
       logger.tool(name, validatedInput, result);
 
@@ -384,6 +524,7 @@ export class ToolRegistry {
     } catch (error) {
       const errorMessage = error instanceof Error ? error.message : String(error);
 
+      this.metrics.record(name, Date.now() - startedAt, false);
       logger.error(`Tool ${name} failed: ${errorMessage}`);
 
       return {
@@ -397,6 +538,126 @@ export class ToolRegistry {
     }
   }
 
+  /**
+   * Per-tool call counts, error rates and latency since start (or the last reset)
+   */
+  getStats(): ToolStats[] {
+    return this.metrics.stats();
+  }
+
+  /**
+   * Start the tool stats over
+   */
+  resetStats(): void {
+    this.metrics.reset();
+  }
+
+  /**
+   * File a write or edit would fill with more redaction placeholders than it
+   * already contains (null when there is none)
+   */
+  private async findRedactedWrite(name: string, input: Record<string, unknown>): Promise<string | null> {
+    if (!isRedactionEnabled() || !(Object.hasOwn(WRITTEN_TEXT_FIELDS, name) || name === 'json_edit')) {
+      return null;
+    }
+
+    // The resulting file, where it can be worked out
+    const change = await computeProposedChange(name, input);
+    if (change) {
+      return countPlaceholders(change.after) > countPlaceholders(change.before ?? '') ? change.filePath : null;
+    }
+
+    // Otherwise the text being written, against the file as it is
+    const field = WRITTEN_TEXT_FIELDS[name];
+    let written = field ? String(input[field] ?? '') : JSON.stringify(input.operations ?? []);
+    if (name === 'apply_unified_diff') {
+      written = written.split('\n').filter(line => line.startsWith('+') && !line.startsWith('+++')).join('\n');
+    }
+    const target = [input.file_path, input.filePath].find((value): value is string => typeof value === 'string');
+    const before = target ? await fs.readFile(path.resolve(target), 'utf-8').catch(() => '') : '';
+    return countPlaceholders(written) > countPlaceholders(before) ? (target ?? 'the patched files') : null;
+  }
+
+  /**
+   * Files a journaled tool changes, as given and as executed (in the
+   * sandbox), from the path fields and the files list
+   */
+  private getJournalTargets(
+    name: string,
+    input: Record<string, unknown>,
+    executedInput: Record<string, unknown>
+  ): Array<{ path: string; executedPath: string }> {
+    // format only reports diffs in check mode
+    if (!JOURNALED_TOOLS.includes(name) || (name === 'format' && input.check === true)) {
+      return [];
+    }
+
+    const targets = ['file_path', 'filePath', 'path', 'source', 'destination']
+      .filter(field => typeof executedInput[field] === 'string')
+      .map(field => ({ path: String(input[field]), executedPath: String(executedInput[field]) }));
+
+    if (Array.isArray(input.files) && Array.isArray(executedInput.files)) {
+      const files = input.files;
+      executedInput.files.forEach((file, i) => targets.push({ path: String(files[i]), executedPath: String(file) }));
+    }
+    return targets;
+  }
+
+  /**
+   * Run the project formatter on files a tool changed and tell the model
+   * what it changed, so its next edit matches the file on disk
+   */
+  private async autoFormat(result: ToolResult, files: string[]): Promise<ToolResult> {
+    const formatted = await autoFormatFiles(files);
+    if (formatted.length === 0) {
+      return result;
+    }
+
+    const data = result.data && typeof result.data === 'object' && !Array.isArray(result.data)
+      ? result.data
+      : { result: result.data };
+    return {
+      ...result,
+      data: {
+        ...data,
+        formatted: formatted.map(({ file, formatter, diff }) => ({ file, formatter, diff })),
+      },
+    };
+  }
+
+  /**
+   * Record post-execution line counts in the change journal and announce
+   * each changed file with its content hashes (for the audit log)
+   */
+  private async recordJournalChanges(
+    name: string,
+    targets: Array<{ path: string; executedPath: string }>,
+    linesBefore: Array<number | null>,
+    hashesBefore: Array<string | null>
+  ): Promise<void> {
+    const journal = getChangeJournal();
+
+    for (let i = 0; i < targets.length; i++) {
+      const linesAfter = await countFileLines(targets[i].executedPath);
+      journal.record({
+        path: targets[i].path,
+        toolName: name,
+        linesBefore: linesBefore[i],
+        linesAfter,
+      });
+
+      const hashAfter = await hashFile(targets[i].executedPath);
+      if (hashAfter !== hashesBefore[i]) {
+        getEventBroadcaster().emit('file_change', {
+          tool: name,
+          path: targets[i].path,
+          sha256Before: hashesBefore[i],
+          sha256After: hashAfter,
+        });
+      }
+    }
+  }
+
   /**
    * Get tool count
    */
@@ -546,6 +807,7 @@ export class ToolRegistry {
       'cache': 'cache',
       'patch': 'file_write',
       'special': 'command',
+      'custom': 'command',
     };
 
     return categoryMap[category] || 'command';
@@ -754,6 +1016,16 @@ export class ToolRegistry {
   // FIX #1: Sandbox Integration for YOLO Mode
   // ==========================================================================
 
+  /**
+   * Whether tool paths should be redirected into the sandbox
+   * (directory sandboxes in YOLO mode, overlay sandboxes in any mode)
+   */
+  private isSandboxRouting(): boolean {
+    const sandboxManager = getSandboxManager();
+    return sandboxManager.isActive()
+      && (process.env.FLOYD_MODE === 'yolo' || sandboxManager.getSession()?.type === 'overlay');
+  }
+
   /**
    * Translate input paths to sandbox paths when sandbox is active
    * This ensures file operations in YOLO mode go to the sandbox, not real project
@@ -761,28 +1033,28 @@ export class ToolRegistry {
   private translateInputToSandbox(name: string, input: Record<string, unknown>): Record<string, unknown> {
     const sandboxManager = getSandboxManager();
 
-    // Only translate if sandbox is active and mode is YOLO
-    if (!sandboxManager.isActive() || process.env.FLOYD_MODE !== 'yolo') {
-      return input;
-    }
-
-    // Tools that modify files - need sandbox translation
-    const fileModifyingTools = [
-      'write', 'write_file', 'edit_file', 'create_file',
-      'delete', 'delete_file', 'remove', 'rm',
-      'move', 'move_file', 'rename', 'mv',
-      'replace_in_file', 'search_replace',
-      'apply_unified_diff', 'patch',
-    ];
-
-    // Only translate for file-modifying tools
-    if (!fileModifyingTools.includes(name)) {
+    // Only translate if sandbox is active and mode is YOLO (or an overlay is active)
+    if (!this.isSandboxRouting()) {
       return input;
     }
 
     const translated = { ...input };
     const pathFields = ['file_path', 'filePath', 'path', 'source', 'destination'];
 
+    // Only translate for file-modifying tools; overlays also redirect reads
+    // of files already written in the overlay
+    if (!SANDBOXED_TOOLS.includes(name)) {
+      if (sandboxManager.getSession()?.type !== 'overlay') {
+        return input;
+      }
+      for (const field of pathFields) {
+        if (typeof translated[field] === 'string') {
+          translated[field] = sandboxManager.resolveReadPath(String(translated[field]));
+        }
+      }
+      return translated;
+    }
+
     for (const field of pathFields) {
       if (translated[field] && typeof translated[field] === 'string') {
         try {
@@ -814,6 +1086,22 @@ export class ToolRegistry {
     return translated;
   }
 
+  /**
+   * Tick (or untick) branch.md checklist items for test/lint commands
+   */
+  private async recordBranchVerification(name: string, input: Record<string, unknown>, result: ToolResult): Promise<void> {
+    let command: string | null = null;
+    if (name === 'run') {
+      command = [input.command, ...((input.args as string[] | undefined) ?? [])].join(' ');
+    } else if (name === 'verify' && input.type === 'command_succeeds') {
+      command = String(input.target);
+    }
+
+    if (command) {
+      await getBranchNotes().recordCommand(command, !!result?.success);
+    }
+  }
+
   /**
    * Track sandbox changes after tool execution
    * Called after a file-modifying tool completes in YOLO mode with sandbox active
@@ -821,31 +1109,35 @@ export class ToolRegistry {
   private trackSandboxChanges(name: string, input: Record<string, unknown>, _result: unknown): void {
     const sandboxManager = getSandboxManager();
 
-    if (!sandboxManager.isActive() || process.env.FLOYD_MODE !== 'yolo') {
+    if (!this.isSandboxRouting() || !SANDBOXED_TOOLS.includes(name)) {
       return;
     }
 
     // Track the change based on tool type
-    const pathFields = ['file_path', 'filePath', 'path', 'source'];
-    for (const field of pathFields) {
-      if (input[field] && typeof input[field] === 'string') {
-        const sandboxPath = String(input[field]);
-        try {
-          const realPath = sandboxManager.translateToReal(sandboxPath);
-
-          // Determine change type based on tool name
-          let changeType: 'created' | 'modified' | 'deleted' = 'modified';
-          if (name.includes('delete') || name.includes('remove') || name.includes('rm')) {
-            changeType = 'deleted';
-          } else if (name.includes('create') || name.includes('write')) {
-            changeType = 'created';
-          }
+    const pathFields = ['file_path', 'filePath', 'path', 'source', 'destination'];
+    const sandboxPaths = pathFields
+      .filter(field => input[field] && typeof input[field] === 'string')
+      .map(field => String(input[field]));
+    if (Array.isArray(input.files)) {
+      sandboxPaths.push(...input.files.filter((p): p is string => typeof p === 'string'));
+    }
+
+    for (const sandboxPath of sandboxPaths) {
+      try {
+        const realPath = sandboxManager.translateToReal(sandboxPath);
 
-          sandboxManager.trackChange(sandboxPath, changeType);
-          logger.debug(`[SANDBOX] Tracked ${changeType}: ${realPath}`);
-        } catch {
-          // Path outside sandbox
+        // Determine change type based on tool name
+        let changeType: 'created' | 'modified' | 'deleted' = 'modified';
+        if (name.includes('delete') || name.includes('remove') || name === 'rm') {
+          changeType = 'deleted';
+        } else if (name.includes('create') || name.includes('write')) {
+          changeType = 'created';
         }
+
+        sandboxManager.trackChange(sandboxPath, changeType);
+        logger.debug(`[SANDBOX] Tracked ${changeType}: ${realPath}`);
+      } catch {
+        // Path outside sandbox
       }
     }
   }
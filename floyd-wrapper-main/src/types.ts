@@ -44,7 +44,8 @@ export type ToolCategory =
   | 'browser'
   | 'cache'
   | 'patch'
-  | 'special';
+  | 'special'
+  | 'custom';
 
 /**
  * Definition of a single tool in the tool registry
@@ -231,8 +232,28 @@ export interface FloydConfig {
   cacheEnabled: boolean;
   /** Permission handling strategy */
   permissionLevel: PermissionLevel;
+  /** Preview diffs of write/edit tools before applying them */
+  diffPreview?: boolean;
+  /** Run the project formatter on files changed by write/edit tools */
+  autoFormat?: boolean;
+  /** Replace secrets in prompts and tool results before they reach the LLM or session files */
+  redactSecrets?: boolean;
   /** Default execution mode */
   mode: ExecutionMode;
+  /** Name of the active run profile */
+  profile?: string;
+  /** Tool names or categories the model may use (all when unset) */
+  allowedTools?: string[];
+  /** Name of the tool profile the session is locked to (reader, editor, admin, ...) */
+  toolProfile?: string;
+  /** Per-run limits from the profile or FLOYD_MAX_RUN_* (unlimited when unset) */
+  runBudget?: {
+    maxTurns?: number;
+    maxTokens?: number;
+    maxToolCalls?: number;
+    maxDurationMs?: number;
+    maxCostUsd?: number;
+  };
   /** Global ignore patterns from .floydignore */
   floydIgnorePatterns?: string[];
   /** Project context from FLOYD.md */
@@ -257,6 +278,16 @@ export interface FloydConfig {
   useJsonPlanning?: boolean;
   /** Disable reasoning for simple tasks (GLM-4.7 optimization) */
   disableReasoning?: boolean;
+  /** Secondary provider endpoint for first-token racing (racing disabled when unset) */
+  raceApiEndpoint?: string;
+  /** Secondary provider API key (defaults to glmApiKey) */
+  raceApiKey?: string;
+  /** Secondary provider model (defaults to glmModel) */
+  raceModel?: string;
+  /** Only race requests whose estimated prompt size is below this many tokens */
+  raceMaxInputTokens?: number;
+  /** Summarize older turns once the estimated prompt passes this many tokens (0 disables) */
+  autoCompactTokens?: number;
 }
 
 // ============================================================================
@@ -369,6 +400,10 @@ export interface SessionMetadata {
   description?: string;
   tags?: string[];
   lastActiveMode?: string;
+  /** Session this one was branched from */
+  parentSessionId?: string;
+  /** Conversation checkpoint (in the parent session) the branch starts at */
+  forkCheckpointId?: string;
   [key: string]: unknown;
 }
 
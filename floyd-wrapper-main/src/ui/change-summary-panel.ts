@@ -0,0 +1,39 @@
+/**
+ * Change Summary Panel - Floyd Wrapper
+ *
+ * Renders the files a run created, modified or deleted as a diffstat panel,
+ * printed when the run ends (the same changes are logged to
+ * .floyd/progress.md by the engine).
+ */
+
+import chalk from 'chalk';
+import { buildDiffStat, type FileChange, type FileChangeType } from 'floyd-agent-core/utils';
+import { CRUSH_THEME } from '../constants.js';
+
+const colors = CRUSH_THEME.colors;
+
+const MARKERS: Record<FileChangeType, string> = {
+  created: chalk.hex(colors.success)('A'),
+  modified: chalk.hex(colors.accent)('M'),
+  deleted: chalk.hex(colors.error)('D'),
+};
+
+/**
+ * The changes as a panel: one diffstat line per file, then the totals
+ */
+export function renderChangeSummaryPanel(changes: FileChange[], width: number = process.stdout.columns || 100): string {
+  // Room for the border, marker, path and count before the bar
+  const pathWidth = Math.min(Math.max(...changes.map(change => change.path.length)), Math.max(20, width - 30));
+  const stat = buildDiffStat(changes, Math.max(10, Math.min(30, width - pathWidth - 16)));
+  const countWidth = Math.max(...stat.lines.map(line => String(line.count).length));
+  const border = chalk.hex(colors.secondary);
+
+  const lines = [border('╭─ ') + chalk.hex(colors.primary).bold('Changes this run')];
+  for (const line of stat.lines) {
+    const shown = line.path.length > pathWidth ? `…${line.path.slice(-(pathWidth - 1))}` : line.path.padEnd(pathWidth);
+    const bar = chalk.hex(colors.success)('+'.repeat(line.plus)) + chalk.hex(colors.error)('-'.repeat(line.minus));
+    lines.push(`${border('│')} ${MARKERS[line.type]} ${chalk.hex(colors.textPrimary)(shown)} ${border('|')} ${String(line.count).padStart(countWidth)} ${bar}`);
+  }
+  lines.push(`${border('╰─')} ${chalk.hex(colors.muted)(stat.totals)}`);
+  return lines.join('\n');
+}
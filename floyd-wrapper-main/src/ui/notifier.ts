@@ -0,0 +1,133 @@
+/**
+ * Notifier - Floyd Wrapper
+ *
+ * Gets the user's attention when an autonomous run finishes, fails or waits
+ * for approval: a terminal bell and, if enabled, an OS notification
+ * (osascript on macOS, notify-send on Linux). Settings come from
+ * ~/.floyd/config.toml (notify_bell, notify_desktop, notify_after). This is
+ * the only place that rings the bell; the run status file stays silent.
+ */
+
+import { execa } from 'execa';
+import { logger } from '../utils/logger.js';
+import type { UserConfig } from '../utils/user-config.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export type NotificationEvent = 'complete' | 'error' | 'approval';
+
+export interface NotifierSettings {
+  bell: boolean;
+  desktop: boolean;
+  /** Runs shorter than this (ms) finish silently; approvals always notify */
+  minRunMs: number;
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+const DEFAULT_NOTIFY_AFTER_SECONDS = 30;
+
+const TITLES: Record<NotificationEvent, string> = {
+  complete: 'FLOYD finished',
+  error: 'FLOYD stopped with an error',
+  approval: 'FLOYD needs approval',
+};
+
+/**
+ * Notifier settings from the user config
+ */
+export function notifierSettings(config: UserConfig | null): NotifierSettings {
+  return {
+    bell: config?.notifyBell ?? true,
+    desktop: config?.notifyDesktop ?? false,
+    minRunMs: (config?.notifyAfter ?? DEFAULT_NOTIFY_AFTER_SECONDS) * 1000,
+  };
+}
+
+/**
+ * Command that shows an OS notification, or null where none is known
+ */
+export function osNotificationCommand(
+  platform: NodeJS.Platform,
+  title: string,
+  message: string
+): { command: string; args: string[] } | null {
+  if (platform === 'darwin') {
+    const quote = (text: string) => JSON.stringify(text);
+    return {
+      command: 'osascript',
+      args: ['-e', `display notification ${quote(message)} with title ${quote(title)}`],
+    };
+  }
+  if (platform === 'linux') {
+    return { command: 'notify-send', args: ['--app-name=FLOYD', title, message] };
+  }
+  return null;
+}
+
+/**
+ * Write to stdout when it is a terminal (no bells in piped output)
+ */
+function writeToTerminal(text: string): void {
+  if (process.stdout.isTTY) {
+    process.stdout.write(text);
+  }
+}
+
+// ============================================================================
+// Notifier Class
+// ============================================================================
+
+export class Notifier {
+  private readonly settings: NotifierSettings;
+  private readonly write: (text: string) => void;
+
+  constructor(settings: NotifierSettings, write: (text: string) => void = writeToTerminal) {
+    this.settings = settings;
+    this.write = write;
+  }
+
+  /**
+   * Whether a run of this length is announced when it ends
+   */
+  shouldNotifyRun(durationMs: number): boolean {
+    return durationMs >= this.settings.minRunMs;
+  }
+
+  /**
+   * Ring the bell and show an OS notification, as configured
+   */
+  notify(event: NotificationEvent, message: string): void {
+    if (this.settings.bell) {
+      this.write('\x07');
+    }
+    if (!this.settings.desktop) {
+      return;
+    }
+
+    const os = osNotificationCommand(process.platform, TITLES[event], message.slice(0, 200));
+    if (!os) {
+      return;
+    }
+    execa(os.command, os.args, { reject: false, timeout: 5000 })
+      .then(result => {
+        if (result.exitCode !== 0) {
+          logger.debug('OS notification failed', { command: os.command, stderr: result.stderr });
+        }
+      })
+      .catch(error => logger.debug('OS notification failed', { command: os.command, error }));
+  }
+
+  /**
+   * Announce the end of a run if it took long enough
+   */
+  runEnded(event: 'complete' | 'error', durationMs: number, message: string): void {
+    if (this.shouldNotifyRun(durationMs)) {
+      this.notify(event, message);
+    }
+  }
+}
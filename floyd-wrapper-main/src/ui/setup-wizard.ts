@@ -0,0 +1,218 @@
+/**
+ * Setup Wizard - Floyd Wrapper
+ *
+ * Runs on the first launch (no ~/.floyd/config.toml and no API key) and via
+ * `floyd setup`: asks for provider, API key, default model and theme, checks
+ * the key with a ping request, stores it in the keychain, writes
+ * ~/.floyd/config.toml and offers to set up .floyd/ in the current repo.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import readline from 'node:readline';
+import chalk from 'chalk';
+import { getCredentialStore, maskKey, validateApiKey, PROVIDER_ENV_VARS, type KeyCheck } from 'floyd-agent-core/utils';
+import { PROVIDER_DEFAULTS, type Provider } from 'floyd-agent-core';
+import { promptSecret } from '../commands/auth-commands.js';
+import { getStoredApiKey } from '../utils/config.js';
+import { PROVIDER_BASE_URLS } from '../utils/profiles.js';
+import { INSTRUCTION_FILE_NAMES } from '../utils/project-instructions.js';
+import { getUserConfigPath, loadUserConfig, saveUserConfig, type UserConfig } from '../utils/user-config.js';
+import { getThemeManager } from './theme.js';
+import { CRUSH_THEME } from '../constants.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * How the wizard talks to the user (replaced in tests)
+ */
+export interface WizardIO {
+  /** Ask a question; an empty answer returns the default */
+  ask(question: string, defaultValue?: string): Promise<string>;
+  /** Ask without echoing the answer */
+  askSecret(question: string): Promise<string>;
+  print(line: string): void;
+}
+
+export interface WizardOptions {
+  io?: WizardIO;
+  /** Repository to offer .floyd/ setup for */
+  projectRoot?: string;
+  configPath?: string;
+  /** Key check (default: a ping request to the provider) */
+  validate?: (provider: Provider, key: string, endpoint: string, model: string) => Promise<KeyCheck>;
+}
+
+/**
+ * Default model per provider, for the models FLOYD is tuned for
+ */
+const DEFAULT_MODELS: Record<Provider, string> = {
+  zai: 'glm-4.7',
+  anthropic: PROVIDER_DEFAULTS.anthropic.model,
+  openai: PROVIDER_DEFAULTS.openai.model,
+  deepseek: PROVIDER_DEFAULTS.deepseek.model,
+};
+
+const PROVIDER_LABELS: Record<Provider, string> = {
+  zai: 'Z.ai GLM coding plan',
+  anthropic: 'Anthropic',
+  openai: 'OpenAI',
+  deepseek: 'DeepSeek',
+};
+
+/**
+ * Runtime files under .floyd/ that should not be committed
+ */
+const FLOYD_GITIGNORE = ['cache/', 'temp/', '.cache/', 'logs/', 'runs/', 'sandbox/', 'exports/', 'floyd.db', 'history', ''].join('\n');
+
+// ============================================================================
+// First Run
+// ============================================================================
+
+/**
+ * Whether this looks like a first launch: no user config and no key anywhere
+ */
+export function needsSetup(configPath: string = getUserConfigPath()): boolean {
+  return !fs.existsSync(configPath) && !process.env.FLOYD_GLM_API_KEY && !getStoredApiKey('zai');
+}
+
+/**
+ * Prompts on the terminal
+ */
+export function createTerminalIO(): WizardIO {
+  return {
+    ask: (question, defaultValue) => new Promise(resolve => {
+      const rl = readline.createInterface({ input: process.stdin, output: process.stdout });
+      const hint = defaultValue ? chalk.dim(` [${defaultValue}]`) : '';
+      rl.question(`${question}${hint} `, (answer) => {
+        rl.close();
+        resolve(answer.trim() || defaultValue || '');
+      });
+    }),
+    askSecret: question => promptSecret(`${question} `),
+    print: line => console.log(line),
+  };
+}
+
+// ============================================================================
+// Wizard
+// ============================================================================
+
+/**
+ * Walk through the setup questions and save the answers
+ *
+ * @returns The saved config, or null when the user cancelled
+ */
+export async function runSetupWizard(options: WizardOptions = {}): Promise<UserConfig | null> {
+  const io = options.io ?? createTerminalIO();
+  const validate = options.validate
+    ?? ((provider, key, endpoint, model) => validateApiKey(provider, key, { endpoint, model }));
+  const providers = Object.keys(PROVIDER_ENV_VARS) as Provider[];
+
+  io.print(chalk.hex(CRUSH_THEME.colors.primary).bold('\nWelcome to FLOYD - let\'s get you set up.'));
+  io.print(chalk.dim('Press Enter to accept a default. Everything can be changed later in ~/.floyd/config.toml.\n'));
+
+  // 1. Provider
+  providers.forEach((provider, index) => io.print(`  ${index + 1}. ${PROVIDER_LABELS[provider]} (${provider})`));
+  const choice = await io.ask('Provider:', '1');
+  const provider = providers[parseInt(choice, 10) - 1] ?? providers.find(p => p === choice.toLowerCase());
+  if (!provider) {
+    io.print(chalk.red(`Unknown provider "${choice}" - setup cancelled. Run \`floyd setup\` to try again.`));
+    return null;
+  }
+  const baseUrl = PROVIDER_BASE_URLS[provider];
+
+  // 2. Default model (asked before the key so the ping uses it)
+  const model = await io.ask('Default model:', DEFAULT_MODELS[provider]);
+
+  // 3. API key, checked with a ping request
+  const existing = getCredentialStore().resolve(provider);
+  let key: string | null = null;
+  while (!key) {
+    const entered = await io.askSecret(existing
+      ? `API key (Enter keeps ${maskKey(existing)}):`
+      : 'API key (Enter to skip and start offline):');
+    if (!entered) {
+      break;
+    }
+    io.print(chalk.dim('Checking the key...'));
+    const check = await validate(provider, entered, baseUrl, model);
+    if (check.ok) {
+      io.print(chalk.green(`✓ ${check.message}`));
+      key = entered;
+    } else {
+      io.print(chalk.red(`✗ ${check.message}`));
+      const answer = (await io.ask('Try again, keep it anyway, or skip? (t/k/s)', 't')).toLowerCase();
+      if (answer.startsWith('k')) {
+        key = entered;
+      } else if (answer.startsWith('s')) {
+        break;
+      }
+    }
+  }
+  if (key) {
+    const store = getCredentialStore();
+    store.set(provider, key);
+    io.print(chalk.dim(`Key stored in ${store.backend.name}`));
+  }
+
+  // 4. Theme
+  const themes = getThemeManager().list().map(theme => theme.name);
+  const themeAnswer = await io.ask(`Theme (${themes.join(', ')}):`, getThemeManager().current.name);
+  const theme = themes.find(name => name.toLowerCase() === themeAnswer.toLowerCase()) ?? getThemeManager().current.name;
+  getThemeManager().use(theme);
+
+  const configPath = options.configPath ?? getUserConfigPath();
+  // Settings the wizard does not ask about (e.g. notifications) are kept
+  const config: UserConfig = { ...loadUserConfig(configPath), provider, model, baseUrl, theme };
+  await saveUserConfig(config, configPath);
+  io.print(chalk.green(`✓ Saved ${configPath}`));
+
+  // 5. Project setup
+  const projectRoot = options.projectRoot ?? process.cwd();
+  if (await fs.pathExists(path.join(projectRoot, '.git')) && !(await fs.pathExists(path.join(projectRoot, '.floyd')))) {
+    const answer = (await io.ask(`Set up .floyd/ in ${projectRoot}? (y/n)`, 'y')).toLowerCase();
+    if (answer.startsWith('y')) {
+      for (const file of await initProjectDir(projectRoot)) {
+        io.print(chalk.dim(`  created ${file}`));
+      }
+    }
+  }
+
+  if (!key && !existing) {
+    io.print(chalk.yellow('No API key yet - FLOYD starts offline. Add one later with `floyd auth login`.'));
+  }
+  io.print('');
+  return config;
+}
+
+/**
+ * Create .floyd/ in a repository: a .gitignore for runtime files and a
+ * FLOYD.md for project instructions when the repo has none
+ *
+ * @returns Paths created, relative to the project root
+ */
+export async function initProjectDir(projectRoot: string): Promise<string[]> {
+  const created: string[] = [];
+
+  const gitignore = path.join(projectRoot, '.floyd', '.gitignore');
+  if (!(await fs.pathExists(gitignore))) {
+    await fs.outputFile(gitignore, FLOYD_GITIGNORE);
+    created.push(path.join('.floyd', '.gitignore'));
+  }
+
+  const hasInstructions = await Promise.all(
+    INSTRUCTION_FILE_NAMES.map(name => fs.pathExists(path.join(projectRoot, name)))
+  );
+  if (!hasInstructions.some(Boolean)) {
+    await fs.outputFile(
+      path.join(projectRoot, 'FLOYD.md'),
+      '# Project Instructions\n\nNotes for FLOYD: how to build and test, conventions to follow, areas to avoid.\n'
+    );
+    created.push('FLOYD.md');
+  }
+
+  return created;
+}
@@ -0,0 +1,75 @@
+/**
+ * Themes - Floyd Wrapper
+ *
+ * Applies the shared theme palette (floyd-agent-core/ui) to CRUSH_THEME.
+ * Everything in the wrapper reads colors from CRUSH_THEME at print time, so
+ * switching or reloading a theme takes effect on the next line printed.
+ * The Ink TUI loads the same ~/.floyd/themes/ files.
+ */
+
+import {
+  ThemeManager,
+  BUILTIN_THEMES,
+  deriveThemeRoles,
+  type ThemeDefinition,
+  type ThemeManagerOptions,
+} from 'floyd-agent-core/ui';
+import { CRUSH_THEME } from '../constants.js';
+
+// ============================================================================
+// Palette
+// ============================================================================
+
+/**
+ * Copy a theme's colors into CRUSH_THEME
+ */
+export function applyTheme(theme: ThemeDefinition): void {
+  const c = theme.colors;
+  Object.assign(CRUSH_THEME.colors, {
+    primary: c.primary,
+    secondary: c.secondary,
+    accent: c.accent,
+    highlight: c.highlight,
+    info: c.info,
+    success: c.success,
+    error: c.error,
+    warning: c.warning,
+    muted: c.textSecondary,
+    bgBase: c.bgBase,
+    bgElevated: c.bgElevated,
+    bgOverlay: c.bgOverlay,
+    bgModal: c.bgModal,
+    textPrimary: c.textPrimary,
+    textSecondary: c.textSecondary,
+    textSubtle: c.textSubtle,
+    textInverse: c.textInverse,
+  });
+  Object.assign(CRUSH_THEME.semantic, deriveThemeRoles(c));
+}
+
+// ============================================================================
+// Singleton Instance
+// ============================================================================
+
+let themeManager: ThemeManager | null = null;
+
+/**
+ * Get or create the global theme manager
+ * The active theme is applied immediately and again on every change.
+ */
+export function getThemeManager(options?: ThemeManagerOptions): ThemeManager {
+  if (!themeManager) {
+    themeManager = new ThemeManager(options);
+    applyTheme(themeManager.current);
+    themeManager.onChange(applyTheme);
+  }
+  return themeManager;
+}
+
+/**
+ * Reset the global theme manager and restore the default palette (for testing)
+ */
+export function resetThemeManager(): void {
+  themeManager = null;
+  applyTheme(BUILTIN_THEMES[0]);
+}
@@ -0,0 +1,40 @@
+/**
+ * Todo Panel - Floyd Wrapper
+ *
+ * Renders the task list the model keeps with the `todo` tool as a small
+ * status panel. It is printed whenever the list changes (sequential output,
+ * like the rest of the terminal UI) and on /todo.
+ */
+
+import chalk from 'chalk';
+import { CRUSH_THEME } from '../constants.js';
+import { todoProgress, type TodoSnapshot, type TodoStatus } from '../tools/todo/todo-core.js';
+
+const colors = CRUSH_THEME.colors;
+
+const ICONS: Record<TodoStatus, string> = {
+  pending: chalk.hex(colors.muted)('○'),
+  in_progress: chalk.hex(colors.accent)('▸'),
+  completed: chalk.hex(colors.success)('✓'),
+};
+
+/**
+ * The list as a panel: a title with progress, then one line per task
+ */
+export function renderTodoPanel(snapshot: TodoSnapshot): string {
+  const { done, total } = todoProgress(snapshot);
+  const border = chalk.hex(colors.secondary);
+  const title = `Tasks ${done}/${total}${snapshot.goal ? ` · ${snapshot.goal}` : ''}`;
+
+  const lines = [border('╭─ ') + chalk.hex(colors.primary).bold(title)];
+  for (const item of snapshot.items) {
+    const text = item.status === 'completed'
+      ? chalk.hex(colors.muted).strikethrough(item.text)
+      : item.status === 'in_progress'
+        ? chalk.hex(colors.textPrimary).bold(item.text)
+        : chalk.hex(colors.textPrimary)(item.text);
+    lines.push(`${border('│')} ${ICONS[item.status]} ${text}`);
+  }
+  lines.push(border('╰─'));
+  return lines.join('\n');
+}
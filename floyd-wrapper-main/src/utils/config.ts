@@ -6,6 +6,11 @@
 
 import path from 'path';
 import fs from 'fs-extra';
+import { getCredentialStore } from 'floyd-agent-core/utils';
+import { inferProviderFromEndpoint, type Provider } from 'floyd-agent-core';
+import { loadProjectInstructions } from './project-instructions.js';
+import { loadUserConfig } from './user-config.js';
+import { isRedactionEnabled } from './redaction.js';
 
 export interface FloydConfig {
   // GLM API Configuration
@@ -29,16 +34,36 @@ export interface FloydConfig {
   useJsonPlanning: boolean;
   disableReasoning: boolean;
 
+  // Provider Racing (optional secondary endpoint)
+  raceApiEndpoint?: string;
+  raceApiKey?: string;
+  raceModel?: string;
+  raceMaxInputTokens?: number;
+
+  // Context
+  autoCompactTokens?: number;
+
   // Logging & Monitoring
   logLevel: LogLevel;
   cacheEnabled: boolean;
 
   // Permissions
   permissionLevel: PermissionLevel;
+  diffPreview: boolean;
+  autoFormat: boolean;
+  redactSecrets: boolean;
 
   // Execution Mode
   mode: ExecutionMode;
 
+  // Run Profile (see profiles.ts)
+  profile?: string;
+  allowedTools?: string[];
+  runBudget?: RunBudget;
+
+  // Tool Profile (see tool-profiles.ts)
+  toolProfile?: string;
+
   // Project Context
   cwd: string;
   floydIgnorePatterns?: string[];
@@ -46,6 +71,17 @@ export interface FloydConfig {
 }
 
 export type LogLevel = 'debug' | 'info' | 'warn' | 'error';
+/**
+ * Per-run limits (see agent/budget-manager.ts); unset limits never stop a run
+ */
+export interface RunBudget {
+  maxTurns?: number;
+  maxTokens?: number;
+  maxToolCalls?: number;
+  maxDurationMs?: number;
+  /** Estimated cost in USD */
+  maxCostUsd?: number;
+}
 export type PermissionLevel = 'auto' | 'ask' | 'deny';
 export type ExecutionMode = 'ask' | 'yolo' | 'plan' | 'auto' | 'dialogue' | 'fuckit';
 
@@ -53,11 +89,14 @@ export type ExecutionMode = 'ask' | 'yolo' | 'plan' | 'auto' | 'dialogue' | 'fuc
  * Load configuration from environment variables and config files
  */
 export function loadConfig(): FloydConfig {
+  // Defaults chosen in `floyd setup` (~/.floyd/config.toml)
+  const user = loadUserConfig() ?? {};
+
   return {
-    // GLM API Configuration
-    glmApiKey: process.env.FLOYD_GLM_API_KEY || '',
-    glmApiEndpoint: process.env.FLOYD_GLM_ENDPOINT || 'https://api.z.ai/api/coding/paas/v4',
-    glmModel: process.env.FLOYD_GLM_MODEL || 'glm-4.7',
+    // GLM API Configuration - keys stored with `floyd auth login` are used when the variables are unset
+    glmApiKey: process.env.FLOYD_GLM_API_KEY || getStoredApiKey(user.provider ?? 'zai') || '',
+    glmApiEndpoint: process.env.FLOYD_GLM_ENDPOINT || user.baseUrl || 'https://api.z.ai/api/coding/paas/v4',
+    glmModel: process.env.FLOYD_GLM_MODEL || user.model || 'glm-4.7',
 
     // Model Behavior
     maxTokens: parseInt(process.env.FLOYD_MAX_TOKENS || '100000'),
@@ -75,16 +114,38 @@ export function loadConfig(): FloydConfig {
     useJsonPlanning: process.env.FLOYD_JSON_PLANNING !== 'false',
     disableReasoning: process.env.FLOYD_DISABLE_REASONING === 'true',
 
+    // Provider Racing - set FLOYD_RACE_ENDPOINT to race a second provider for first token
+    raceApiEndpoint: process.env.FLOYD_RACE_ENDPOINT || undefined,
+    raceApiKey: process.env.FLOYD_RACE_API_KEY
+      || (process.env.FLOYD_RACE_ENDPOINT ? getStoredApiKey(inferProviderFromEndpoint(process.env.FLOYD_RACE_ENDPOINT)) : undefined)
+      || undefined,
+    raceModel: process.env.FLOYD_RACE_MODEL || undefined,
+    raceMaxInputTokens: getEnvNumber('FLOYD_RACE_MAX_INPUT_TOKENS', 8000),
+
+    // Auto-compaction - summarize older turns past this many estimated prompt tokens (0 disables)
+    autoCompactTokens: getEnvNumber('FLOYD_AUTO_COMPACT_TOKENS', 120000),
+
     // Logging & Monitoring
     logLevel: (process.env.FLOYD_LOG_LEVEL as LogLevel) || 'info',
     cacheEnabled: process.env.FLOYD_CACHE_ENABLED !== 'false',
 
     // Permissions
     permissionLevel: (process.env.FLOYD_PERMISSION_LEVEL as PermissionLevel) || 'ask',
+    diffPreview: process.env.FLOYD_DIFF_PREVIEW !== 'false',
+    autoFormat: process.env.FLOYD_AUTO_FORMAT === 'true',
+
+    // Secret redaction - prompts and tool results, audited in .floyd/logs/redactions.log
+    redactSecrets: isRedactionEnabled(),
 
     // Execution Mode
     mode: (process.env.FLOYD_MODE as ExecutionMode) || 'ask',
 
+    // Run budget - FLOYD_MAX_RUN_TOKENS, _TOOL_CALLS, _SECONDS, _COST (profiles can also set these)
+    runBudget: loadRunBudgetFromEnv(),
+
+    // Tool profile - FLOYD_TOOL_PROFILE locks the session to a subset of the tools (reader, editor, admin)
+    toolProfile: process.env.FLOYD_TOOL_PROFILE || undefined,
+
     // Project Context
     cwd: process.cwd(),
     floydIgnorePatterns: [],
@@ -93,21 +154,37 @@ export function loadConfig(): FloydConfig {
 }
 
 /**
- * Load project context from FLOYD.md if it exists
+ * Key stored for a provider in the keychain (or encrypted file), if any
  */
-export function loadProjectContext(projectRoot?: string): string | undefined {
-  const root = projectRoot || process.cwd();
-  const floydMdPath = path.join(root, 'FLOYD.md');
-
-  if (fs.existsSync(floydMdPath)) {
-    try {
-      return fs.readFileSync(floydMdPath, 'utf-8');
-    } catch (error) {
-      console.warn(`Failed to read FLOYD.md: ${error}`);
-    }
+export function getStoredApiKey(provider: Provider): string | undefined {
+  try {
+    return getCredentialStore().get(provider) ?? undefined;
+  } catch {
+    return undefined;
   }
+}
+
+/**
+ * Run budget from FLOYD_MAX_RUN_* variables (undefined when none is set)
+ */
+export function loadRunBudgetFromEnv(): RunBudget | undefined {
+  const budget: RunBudget = {};
+  const set = (limit: keyof RunBudget, key: string, scale = 1) => {
+    const value = getEnvNumber(key, 0);
+    if (value > 0) budget[limit] = value * scale;
+  };
+  set('maxTokens', 'FLOYD_MAX_RUN_TOKENS');
+  set('maxToolCalls', 'FLOYD_MAX_RUN_TOOL_CALLS');
+  set('maxDurationMs', 'FLOYD_MAX_RUN_SECONDS', 1000);
+  set('maxCostUsd', 'FLOYD_MAX_RUN_COST');
+  return Object.keys(budget).length > 0 ? budget : undefined;
+}
 
-  return undefined;
+/**
+ * Load project context from FLOYD.md, AGENTS.md and CLAUDE.md at the project root
+ */
+export function loadProjectContext(projectRoot?: string): string | undefined {
+  return loadProjectInstructions(projectRoot || process.cwd());
 }
 
 /**
@@ -244,7 +321,7 @@ export function validateConfig(config: FloydConfig): { valid: boolean; errors: s
   const errors: string[] = [];
 
   if (!config.glmApiKey) {
-    errors.push('FLOYD_GLM_API_KEY is required');
+    errors.push('FLOYD_GLM_API_KEY is required (or store a key with `floyd auth login glm`)');
   }
 
   if (config.maxTokens < 1) {
@@ -0,0 +1,94 @@
+/**
+ * Context Watcher - Floyd Wrapper
+ *
+ * The project context (instruction files, .floyd/master_plan.md, stack.md)
+ * is read into the system prompt once. This watches those files so an edit
+ * made outside FLOYD - e.g. ticking off the master plan in an editor -
+ * reaches the model with the next request instead of the next session.
+ */
+
+import chokidar from 'chokidar';
+import path from 'node:path';
+import { projectContextFiles } from './project-instructions.js';
+import { logger } from './logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Called once per burst of changes with the changed files (relative paths)
+ */
+export type ContextChangeHandler = (files: string[]) => void;
+
+/**
+ * Quiet period before a burst of changes is reported; editors often write
+ * a file several times when saving
+ */
+const DEBOUNCE_MS = 250;
+
+// ============================================================================
+// Context Watcher Class
+// ============================================================================
+
+/**
+ * Watches the files that make up the project context
+ */
+export class ContextWatcher {
+  private readonly projectRoot: string;
+  private readonly onChange: ContextChangeHandler;
+  private watcher: chokidar.FSWatcher | null = null;
+  private pending = new Set<string>();
+  private timer: NodeJS.Timeout | null = null;
+
+  constructor(projectRoot: string, onChange: ContextChangeHandler) {
+    this.projectRoot = path.resolve(projectRoot);
+    this.onChange = onChange;
+  }
+
+  /**
+   * Start watching; files that do not exist yet are picked up when created
+   */
+  start(): void {
+    if (this.watcher) {
+      return;
+    }
+
+    const files = projectContextFiles().map(file => path.join(this.projectRoot, file));
+    this.watcher = chokidar.watch(files, {
+      ignoreInitial: true,
+      persistent: false,
+      awaitWriteFinish: { stabilityThreshold: 100, pollInterval: 50 },
+    });
+
+    this.watcher.on('all', (_event, filePath) => this.queue(filePath));
+    this.watcher.on('error', (error) => logger.debug('Context watcher error', { error }));
+    logger.debug('Watching project context', { files: files.length });
+  }
+
+  /**
+   * Stop watching and drop changes not reported yet
+   */
+  async stop(): Promise<void> {
+    if (this.timer) {
+      clearTimeout(this.timer);
+      this.timer = null;
+    }
+    this.pending.clear();
+    await this.watcher?.close();
+    this.watcher = null;
+  }
+
+  private queue(filePath: string): void {
+    this.pending.add(path.relative(this.projectRoot, filePath));
+    if (this.timer) {
+      clearTimeout(this.timer);
+    }
+    this.timer = setTimeout(() => {
+      this.timer = null;
+      const files = [...this.pending];
+      this.pending.clear();
+      this.onChange(files);
+    }, DEBOUNCE_MS);
+  }
+}
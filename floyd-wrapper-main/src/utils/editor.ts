@@ -0,0 +1,73 @@
+/**
+ * Editor Launcher - Floyd Wrapper
+ *
+ * Opens files in the user's $VISUAL / $EDITOR without going through a
+ * shell. The variable may carry arguments ("code --wait"), so it is split
+ * into argv here; the file path is always passed as its own argument, so
+ * nothing in it is ever interpreted by a shell.
+ */
+
+import { spawnSync } from 'node:child_process';
+
+/**
+ * Split a command line into arguments, honouring single and double quotes
+ * and backslash escapes (no expansion of any kind)
+ */
+export function splitCommandLine(command: string): string[] {
+  const args: string[] = [];
+  let current = '';
+  let inArg = false;
+  let quote: '"' | "'" | null = null;
+
+  for (let i = 0; i < command.length; i++) {
+    const char = command[i];
+    if (quote) {
+      if (char === quote) {
+        quote = null;
+      } else if (char === '\\' && quote === '"' && i + 1 < command.length) {
+        current += command[++i];
+      } else {
+        current += char;
+      }
+    } else if (char === '"' || char === "'") {
+      quote = char;
+      inArg = true;
+    } else if (char === '\\' && i + 1 < command.length) {
+      current += command[++i];
+      inArg = true;
+    } else if (/\s/.test(char)) {
+      if (inArg) {
+        args.push(current);
+        current = '';
+        inArg = false;
+      }
+    } else {
+      current += char;
+      inArg = true;
+    }
+  }
+  if (inArg) {
+    args.push(current);
+  }
+
+  return args;
+}
+
+/**
+ * The user's editor as argv ($VISUAL, then $EDITOR, then vi)
+ */
+export function editorCommand(env: NodeJS.ProcessEnv = process.env): string[] {
+  const args = splitCommandLine(env.VISUAL || env.EDITOR || '');
+  return args.length > 0 ? args : ['vi'];
+}
+
+/**
+ * Open a file in the user's editor and wait for it to close
+ *
+ * @returns The editor's exit status (null when it could not be started)
+ */
+export function openInEditor(filePath: string): number | null {
+  const [program, ...args] = editorCommand();
+  const result = spawnSync(program, [...args, filePath], { stdio: 'inherit' });
+  return result.error ? null : result.status;
+}
@@ -0,0 +1,185 @@
+/**
+ * HTML to Markdown - Floyd Wrapper
+ *
+ * Turns a downloaded page into readable markdown for the model: scripts,
+ * styles, navigation, headers, footers and forms are dropped, the main
+ * content is kept when the page marks it, and headings, lists, links, code
+ * and tables become their markdown forms.
+ */
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Elements removed with everything inside them
+ */
+const BOILERPLATE_TAGS = ['script', 'style', 'noscript', 'template', 'svg', 'canvas', 'iframe', 'nav', 'header', 'footer', 'aside', 'form', 'button', 'select'];
+
+/**
+ * Named entities worth decoding; numeric ones are decoded generically
+ */
+const ENTITIES: Record<string, string> = {
+  amp: '&',
+  lt: '<',
+  gt: '>',
+  quot: '"',
+  apos: "'",
+  nbsp: ' ',
+  mdash: '—',
+  ndash: '–',
+  hellip: '…',
+  copy: '©',
+  reg: '®',
+  trade: '™',
+  laquo: '«',
+  raquo: '»',
+  rsquo: '’',
+  lsquo: '‘',
+  rdquo: '”',
+  ldquo: '“',
+};
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Decode HTML entities
+ */
+export function decodeEntities(text: string): string {
+  return text.replace(/&(#x[0-9a-f]+|#\d+|[a-z]+);/gi, (entity, code: string) => {
+    if (code[0] === '#') {
+      const value = code[1]?.toLowerCase() === 'x' ? parseInt(code.slice(2), 16) : parseInt(code.slice(1), 10);
+      return Number.isNaN(value) || value > 0x10ffff ? entity : String.fromCodePoint(value);
+    }
+    return ENTITIES[code.toLowerCase()] ?? entity;
+  });
+}
+
+function stripTags(html: string): string {
+  return html.replace(/<[^>]*>/g, '');
+}
+
+function attribute(tag: string, name: string): string | undefined {
+  return tag.match(new RegExp(`\\s${name}\\s*=\\s*(?:"([^"]*)"|'([^']*)'|([^\\s>]+))`, 'i'))?.slice(1).find(value => value !== undefined);
+}
+
+/**
+ * Content of the first element of a tag, when the page has one
+ */
+function innerOf(html: string, tag: string): string | undefined {
+  const match = html.match(new RegExp(`<${tag}\\b[^>]*>([\\s\\S]*?)</${tag}>`, 'i'));
+  return match?.[1];
+}
+
+/**
+ * Resolve a link against the page URL
+ */
+function resolveHref(href: string, baseUrl?: string): string {
+  if (!baseUrl) {
+    return href;
+  }
+  try {
+    return new URL(href, baseUrl).toString();
+  } catch {
+    return href;
+  }
+}
+
+function convertTable(table: string): string {
+  const rows = [...table.matchAll(/<tr\b[^>]*>([\s\S]*?)<\/tr>/gi)].map(row =>
+    [...row[1]!.matchAll(/<t[hd]\b[^>]*>([\s\S]*?)<\/t[hd]>/gi)]
+      .map(cell => decodeEntities(stripTags(cell[1]!)).replace(/\s+/g, ' ').replace(/\|/g, '\\|').trim()));
+  if (rows.length === 0) {
+    return '';
+  }
+  const width = Math.max(...rows.map(row => row.length));
+  const line = (cells: string[]) => `| ${Array.from({ length: width }, (_, i) => cells[i] ?? '').join(' | ')} |`;
+  return [line(rows[0]!), line(Array(width).fill('---')), ...rows.slice(1).map(line)].join('\n');
+}
+
+// ============================================================================
+// Conversion
+// ============================================================================
+
+/**
+ * Page title, if any
+ */
+export function extractTitle(html: string): string | undefined {
+  const title = innerOf(html, 'title');
+  return title ? decodeEntities(stripTags(title)).replace(/\s+/g, ' ').trim() || undefined : undefined;
+}
+
+/**
+ * Convert an HTML page to markdown
+ *
+ * @param html - Page source
+ * @param baseUrl - Page URL, for resolving relative links
+ */
+export function htmlToMarkdown(html: string, baseUrl?: string): string {
+  let body = html.replace(/<!--[\s\S]*?-->/g, '');
+  for (const tag of BOILERPLATE_TAGS) {
+    body = body.replace(new RegExp(`<${tag}\\b[^>]*>[\\s\\S]*?</${tag}>`, 'gi'), '');
+  }
+  body = innerOf(body, 'main') ?? innerOf(body, 'article') ?? innerOf(body, 'body') ?? body;
+
+  // Code blocks are set aside so their whitespace survives
+  const blocks: string[] = [];
+  const keep = (text: string) => `\u0000${blocks.push(text) - 1}\u0000`;
+
+  body = body.replace(/<pre\b[^>]*>([\s\S]*?)<\/pre>/gi, (_, inner: string) => {
+    const language = inner.match(/class\s*=\s*["'][^"']*(?:language|lang)-([\w+-]+)/i)?.[1] ?? '';
+    const code = decodeEntities(stripTags(inner.replace(/<br\s*\/?>/gi, '\n'))).replace(/\n+$/, '');
+    return `\n\n${keep(`\`\`\`${language}\n${code}\n\`\`\``)}\n\n`;
+  });
+  body = body.replace(/<table\b[^>]*>([\s\S]*?)<\/table>/gi, (_, inner: string) => `\n\n${keep(convertTable(inner))}\n\n`);
+
+  body = body
+    .replace(/<h([1-6])\b[^>]*>([\s\S]*?)<\/h\1>/gi, (_, level: string, inner: string) =>
+      `\n\n${'#'.repeat(Number(level))} ${stripTags(inner).replace(/\s+/g, ' ').trim()}\n\n`)
+    .replace(/<a\b([^>]*)>([\s\S]*?)<\/a>/gi, (tag, attrs: string, inner: string) => {
+      const text = stripTags(inner).replace(/\s+/g, ' ').trim();
+      const href = attribute(`<a${attrs}>`, 'href');
+      if (!text) {
+        return '';
+      }
+      return href && !href.startsWith('#') && !href.startsWith('javascript:') ? `[${text}](${resolveHref(href, baseUrl)})` : text;
+    })
+    .replace(/<img\b[^>]*>/gi, tag => {
+      const alt = attribute(tag, 'alt');
+      return alt ? `[image: ${alt}]` : '';
+    })
+    .replace(/<code\b[^>]*>([\s\S]*?)<\/code>/gi, (_, inner: string) => `\`${stripTags(inner)}\``)
+    .replace(/<(strong|b)\b[^>]*>([\s\S]*?)<\/\1>/gi, (_, __, inner: string) => `**${inner}**`)
+    .replace(/<(em|i)\b[^>]*>([\s\S]*?)<\/\1>/gi, (_, __, inner: string) => `*${inner}*`)
+    .replace(/<li\b[^>]*>/gi, '\n- ')
+    .replace(/<blockquote\b[^>]*>/gi, '\n\n> ')
+    .replace(/<br\s*\/?>/gi, '\n')
+    .replace(/<hr\b[^>]*>/gi, '\n\n---\n\n')
+    .replace(/<\/?(p|div|section|ul|ol|dl|dt|dd|blockquote|figure|figcaption|details|summary)\b[^>]*>/gi, '\n\n');
+
+  const text = decodeEntities(stripTags(body))
+    .split('\n')
+    .map(line => line.replace(/[ \t\u00a0]+/g, ' ').trim())
+    .join('\n')
+    .replace(/\n{3,}/g, '\n\n')
+    .trim();
+
+  return text.replace(/\u0000(\d+)\u0000/g, (_, index: string) => blocks[Number(index)]!);
+}
+
+/**
+ * Shorten text to a character budget at a line break where possible
+ */
+export function truncateText(text: string, maxChars: number): { text: string; truncated: boolean } {
+  if (text.length <= maxChars) {
+    return { text, truncated: false };
+  }
+  const cut = text.lastIndexOf('\n', maxChars);
+  const end = cut > maxChars * 0.8 ? cut : maxChars;
+  return {
+    text: `${text.slice(0, end)}\n\n[Truncated: ${text.length - end} more characters]`,
+    truncated: true,
+  };
+}
@@ -0,0 +1,60 @@
+/**
+ * Ignore Files - Floyd Wrapper
+ *
+ * The .gitignore and .floydignore rules the file and search tools honor, so
+ * list_directory, grep and codebase_search skip dependencies, build output
+ * and anything else the project ignores. Tools take include_ignored: true to
+ * look at ignored files anyway.
+ */
+
+import path from 'node:path';
+import { isIgnoredByIgnoreFiles } from 'globby';
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+/**
+ * Ignore files read at any depth below the searched directory
+ */
+export const IGNORE_FILES = ['**/.gitignore', '**/.floydignore'];
+
+// ============================================================================
+// Filter
+// ============================================================================
+
+/**
+ * A predicate telling whether a path (absolute, or relative to `cwd`) is
+ * ignored; pass `isDirectory` so "build/" style rules match
+ */
+export type IgnoreFilter = (filePath: string, isDirectory?: boolean) => boolean;
+
+/**
+ * Build the filter for a directory from the ignore files below it
+ */
+export async function createIgnoreFilter(cwd: string): Promise<IgnoreFilter> {
+  const root = path.resolve(cwd);
+  const isIgnored = await isIgnoredByIgnoreFiles(IGNORE_FILES, { cwd: root });
+
+  return (filePath, isDirectory = false) => {
+    const relative = path.relative(root, path.resolve(root, filePath)).split(path.sep).join('/');
+    if (!relative || relative.startsWith('..')) {
+      return false;
+    }
+    // Skipped even in a project without ignore files
+    const segments = relative.split('/');
+    if (segments.includes('node_modules') || segments.includes('.git')) {
+      return true;
+    }
+    return isIgnored(isDirectory ? `${relative}/` : relative);
+  };
+}
+
+/**
+ * globby options that apply the same rules, unless ignored files are wanted
+ */
+export function ignoreGlobOptions(includeIgnored = false): { gitignore: boolean; ignoreFiles: string[] } {
+  return includeIgnored
+    ? { gitignore: false, ignoreFiles: [] }
+    : { gitignore: true, ignoreFiles: ['**/.floydignore'] };
+}
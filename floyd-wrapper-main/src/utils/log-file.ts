@@ -0,0 +1,191 @@
+/**
+ * Log File - Floyd Wrapper
+ *
+ * Rotating JSON-lines log file behind the logger. Every record carries a
+ * timestamp, level, message and optional structured data, so /logs can
+ * filter by level and tools like jq can read it directly.
+ *
+ * File: .floyd/logs/floyd.log (override with FLOYD_LOG_FILE, "off" disables)
+ * Rotation: floyd.log -> floyd.log.1 -> ... once FLOYD_LOG_MAX_SIZE bytes
+ * (default 5 MB) is reached, keeping FLOYD_LOG_MAX_FILES files (default 5).
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import type { LogLevel } from '../types.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One line of the log file
+ */
+export interface LogRecord {
+  time: string;
+  level: LogLevel;
+  msg: string;
+  data?: unknown;
+}
+
+/**
+ * Rotation settings
+ */
+export interface LogRotationOptions {
+  /** Rotate once the active file reaches this size (bytes) */
+  maxBytes?: number;
+  /** Number of files kept, including the active one */
+  maxFiles?: number;
+}
+
+const LEVEL_PRIORITY: Record<LogLevel, number> = { debug: 0, info: 1, warn: 2, error: 3 };
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Location of the log file, or null when file logging is off
+ */
+export function getLogFilePath(cwd: string = process.cwd()): string | null {
+  const configured = process.env.FLOYD_LOG_FILE;
+  if (configured === 'off' || configured === 'false') {
+    return null;
+  }
+  return configured || path.join(cwd, '.floyd', 'logs', 'floyd.log');
+}
+
+/**
+ * Make log arguments JSON-safe (errors keep their message and stack)
+ */
+export function toLogData(args: unknown[]): unknown {
+  const convert = (value: unknown): unknown => {
+    if (value instanceof Error) {
+      return { name: value.name, message: value.message, stack: value.stack };
+    }
+    if (value && typeof value === 'object' && !Array.isArray(value)) {
+      return Object.fromEntries(Object.entries(value).map(([key, v]) => [key, v instanceof Error ? convert(v) : v]));
+    }
+    return value;
+  };
+
+  if (args.length === 0) {
+    return undefined;
+  }
+  return args.length === 1 ? convert(args[0]) : args.map(convert);
+}
+
+/**
+ * Read the last records of a log file, optionally at or above a level
+ */
+export async function readLogTail(filePath: string, count = 50, minLevel: LogLevel = 'debug'): Promise<LogRecord[]> {
+  const content = await fs.readFile(filePath, 'utf-8').catch(() => '');
+  const records: LogRecord[] = [];
+
+  for (const line of content.split('\n')) {
+    if (!line.trim()) {
+      continue;
+    }
+    try {
+      const record = JSON.parse(line) as LogRecord;
+      if (LEVEL_PRIORITY[record.level] >= LEVEL_PRIORITY[minLevel]) {
+        records.push(record);
+      }
+    } catch {
+      // Partial line from a concurrent writer
+    }
+  }
+
+  return records.slice(-count);
+}
+
+/**
+ * Format a record as a single display line
+ */
+export function formatLogRecord(record: LogRecord): string {
+  const time = record.time.slice(11, 19);
+  let data = '';
+  if (record.data !== undefined) {
+    try {
+      data = ` ${JSON.stringify(record.data)}`;
+    } catch {
+      data = ' [unserializable]';
+    }
+  }
+  return `${time} ${record.level.toUpperCase().padEnd(5)} ${record.msg}${data}`;
+}
+
+// ============================================================================
+// Rotating Log File Class
+// ============================================================================
+
+/**
+ * Appends JSON-lines records and rotates by size
+ */
+export class RotatingLogFile {
+  private readonly filePath: string;
+  private readonly maxBytes: number;
+  private readonly maxFiles: number;
+  private size = -1;
+  private broken = false;
+
+  constructor(filePath: string, options: LogRotationOptions = {}) {
+    this.filePath = filePath;
+    this.maxBytes = options.maxBytes ?? (parseInt(process.env.FLOYD_LOG_MAX_SIZE || '', 10) || 5 * 1024 * 1024);
+    this.maxFiles = Math.max(1, options.maxFiles ?? (parseInt(process.env.FLOYD_LOG_MAX_FILES || '', 10) || 5));
+  }
+
+  getPath(): string {
+    return this.filePath;
+  }
+
+  /**
+   * Append a record, rotating first if the file is full
+   */
+  write(record: LogRecord): void {
+    if (this.broken) {
+      return;
+    }
+
+    let line: string;
+    try {
+      line = JSON.stringify(record) + '\n';
+    } catch {
+      line = JSON.stringify({ ...record, data: '[unserializable]' }) + '\n';
+    }
+
+    try {
+      if (this.size < 0) {
+        fs.ensureDirSync(path.dirname(this.filePath));
+        this.size = fs.existsSync(this.filePath) ? fs.statSync(this.filePath).size : 0;
+      }
+
+      if (this.size > 0 && this.size + line.length > this.maxBytes) {
+        this.rotate();
+      }
+
+      fs.appendFileSync(this.filePath, line);
+      this.size += Buffer.byteLength(line);
+    } catch {
+      // A read-only or missing workspace must never break a run
+      this.broken = true;
+    }
+  }
+
+  /**
+   * Shift floyd.log -> floyd.log.1 -> floyd.log.2 ..., dropping the oldest
+   */
+  private rotate(): void {
+    for (let i = this.maxFiles - 1; i >= 1; i--) {
+      const from = i === 1 ? this.filePath : `${this.filePath}.${i - 1}`;
+      const to = `${this.filePath}.${i}`;
+      if (fs.existsSync(from)) {
+        fs.renameSync(from, to);
+      }
+    }
+    if (this.maxFiles === 1) {
+      fs.removeSync(this.filePath);
+    }
+    this.size = 0;
+  }
+}
@@ -2,10 +2,14 @@
  * Logger - Floyd Wrapper
  *
  * Color-coded logging system with level filtering and tool execution logging.
+ * Records are also written to a rotating log file (.floyd/logs/floyd.log)
+ * with its own level, so the console can stay quiet while /logs keeps detail.
  */
 
 import chalk from 'chalk';
 import type { LogLevel } from '../types.js';
+import { RotatingLogFile, getLogFilePath, toLogData } from './log-file.js';
+import { redactValue } from './redaction.js';
 
 // ============================================================================
 // Logger Class
@@ -30,10 +34,40 @@ export class FloydLogger {
     error: 3,
   };
 
+  /**
+   * Optional log file and the level it records at
+   */
+  private file: RotatingLogFile | null = null;
+  private fileLevel: LogLevel = 'info';
+
   constructor(level: LogLevel = 'info') {
     this.level = level;
   }
 
+  /**
+   * Write records to a log file (null disables file logging)
+   */
+  setFile(file: RotatingLogFile | null, level: LogLevel = 'info'): void {
+    this.file = file;
+    this.fileLevel = level;
+  }
+
+  /**
+   * Get the active log file, if any
+   */
+  getFile(): RotatingLogFile | null {
+    return this.file;
+  }
+
+  /**
+   * Append a structured record to the log file, secrets redacted
+   */
+  private record(level: LogLevel, message: string, args: unknown[]): void {
+    if (this.file && this.levelPriority[level] >= this.levelPriority[this.fileLevel]) {
+      this.file.write({ time: this.getTimestamp(), level, msg: redactValue(message), data: redactValue(toLogData(args)) });
+    }
+  }
+
   /**
    * Set the log level
    */
@@ -67,6 +101,7 @@ export class FloydLogger {
    * Log debug message
    */
   debug(message: string, ...args: unknown[]): void {
+    this.record('debug', message, args);
     if (this.shouldLog('debug')) {
       console.error(chalk.gray(`${this.getTimestamp()} [DEBUG]`), message, ...args);
     }
@@ -76,6 +111,7 @@ export class FloydLogger {
    * Log info message
    */
   info(message: string, ...args: unknown[]): void {
+    this.record('info', message, args);
     if (this.shouldLog('info')) {
       console.error(chalk.blue(`${this.getTimestamp()} [INFO]`), message, ...args);
     }
@@ -85,6 +121,7 @@ export class FloydLogger {
    * Log warning message
    */
   warn(message: string, ...args: unknown[]): void {
+    this.record('warn', message, args);
     if (this.shouldLog('warn')) {
       console.error(chalk.yellow(`${this.getTimestamp()} [WARN]`), message, ...args);
     }
@@ -94,6 +131,7 @@ export class FloydLogger {
    * Log error message
    */
   error(message: string, error?: Error | unknown): void {
+    this.record('error', message, error === undefined ? [] : [error]);
     if (this.shouldLog('error')) {
       console.error(chalk.red(`${this.getTimestamp()} [ERROR]`), message);
 
@@ -109,6 +147,7 @@ export class FloydLogger {
    * Log tool execution
    */
   tool(toolName: string, input: unknown, output: unknown): void {
+    this.record('debug', `[TOOL] ${toolName}`, [{ input, output }]);
     if (this.shouldLog('debug')) {
       console.error(chalk.cyan(`${this.getTimestamp()} [TOOL]`), toolName);
 
@@ -256,12 +295,20 @@ export class FloydScopedLogger {
 export const logger = new FloydLogger();
 
 /**
- * Set global log level from environment variable
+ * Set global log level and log file from environment variables
  */
-export function initLogger(): void {
+export function initLogger(cwd: string = process.cwd()): void {
+  const levels = ['debug', 'info', 'warn', 'error'];
   const logLevel = process.env.FLOYD_LOG_LEVEL as LogLevel | undefined;
 
-  if (logLevel && ['debug', 'info', 'warn', 'error'].includes(logLevel)) {
+  if (logLevel && levels.includes(logLevel)) {
     logger.setLevel(logLevel);
   }
+
+  const filePath = getLogFilePath(cwd);
+  const fileLevel = process.env.FLOYD_LOG_FILE_LEVEL as LogLevel | undefined;
+  logger.setFile(
+    filePath ? new RotatingLogFile(filePath) : null,
+    fileLevel && levels.includes(fileLevel) ? fileLevel : 'info'
+  );
 }
@@ -0,0 +1,85 @@
+/**
+ * Offline Mode - Floyd Wrapper
+ *
+ * Decides at startup whether model calls are possible. Without an API key,
+ * without a reachable endpoint, or when asked to (--offline or
+ * FLOYD_OFFLINE=true), FLOYD starts anyway with model calls disabled and
+ * tools available directly through slash commands (/run, /read, /tool).
+ */
+
+import type { FloydConfig } from '../types.js';
+
+type ApiSettings = Pick<FloydConfig, 'glmApiKey' | 'glmApiEndpoint'>;
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Why model calls are disabled
+ */
+export type OfflineReason = 'requested' | 'no-api-key' | 'no-network';
+
+/**
+ * Time allowed for the endpoint reachability check
+ */
+const PROBE_TIMEOUT_MS = 2000;
+
+// ============================================================================
+// Detection
+// ============================================================================
+
+/**
+ * Whether the endpoint answers at all (any HTTP status counts as reachable)
+ */
+export async function isEndpointReachable(endpoint: string, timeoutMs = PROBE_TIMEOUT_MS): Promise<boolean> {
+  try {
+    await fetch(endpoint, { method: 'HEAD', signal: AbortSignal.timeout(timeoutMs) });
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Work out whether to start offline
+ *
+ * @param requested - --offline flag given
+ * @param probe - Reachability check (replaceable in tests)
+ * @returns Reason to start offline, or null when model calls can be made
+ */
+export async function detectOffline(
+  config: ApiSettings,
+  requested = false,
+  probe: (endpoint: string) => Promise<boolean> = isEndpointReachable
+): Promise<OfflineReason | null> {
+  if (requested || process.env.FLOYD_OFFLINE === 'true') {
+    return 'requested';
+  }
+  if (!config.glmApiKey) {
+    return 'no-api-key';
+  }
+  if (!(await probe(config.glmApiEndpoint))) {
+    return 'no-network';
+  }
+  return null;
+}
+
+/**
+ * One-line explanation shown at startup and when a message can't be sent
+ */
+export function describeOffline(reason: OfflineReason, config?: ApiSettings): string {
+  switch (reason) {
+    case 'requested':
+      return 'Offline mode: model calls are disabled.';
+    case 'no-api-key':
+      return 'Offline mode: FLOYD_GLM_API_KEY is not set and no key is stored (`floyd auth login glm`), so model calls are disabled.';
+    case 'no-network':
+      return `Offline mode: ${config?.glmApiEndpoint || 'the model endpoint'} is unreachable, so model calls are disabled.`;
+  }
+}
+
+/**
+ * What still works offline
+ */
+export const OFFLINE_HINT = 'Tools still work: /run <command>, /read <file>, /tool <name> <json>. Restart FLOYD once the API is available.';
@@ -0,0 +1,294 @@
+/**
+ * Run Profiles - Floyd Wrapper
+ *
+ * A profile bundles the settings that usually change together - model, tool
+ * set, run budgets, approval policy and verbosity - under one name, so a run
+ * can be started with `floyd --profile deep-refactor` (or switched with
+ * /profile) instead of tweaking each setting.
+ *
+ * Built-in profiles can be overridden or extended in .floyd/profiles.json
+ * (project) and ~/.floyd/profiles.json (user); project entries win:
+ *
+ *   { "review": { "description": "Read-only review", "mode": "plan", "tools": ["file", "search", "git_diff"] } }
+ *
+ * A profile can also switch the connection - provider, base URL, model and
+ * temperature - e.g. for a work and a personal account. Keys never go in the
+ * file: they come from the provider's keychain entry (`floyd auth login`) or
+ * the environment variable named by apiKeyEnv:
+ *
+ *   { "work": { "provider": "anthropic", "apiKeyEnv": "WORK_ANTHROPIC_KEY", "model": "claude-sonnet-4-20250514" } }
+ */
+
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { getCredentialStore, parseProvider } from 'floyd-agent-core/utils';
+import type { Provider } from 'floyd-agent-core';
+import { loadRunBudgetFromEnv, type ExecutionMode, type FloydConfig, type LogLevel, type RunBudget } from './config.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * A named bundle of run settings; unset fields keep the current value
+ */
+export interface RunProfile {
+  name: string;
+  description: string;
+  /** Model name (FLOYD_GLM_MODEL) */
+  model?: string;
+  /** Provider whose stored or env key is used (zai/glm, anthropic, openai, deepseek) */
+  provider?: Provider;
+  /** OpenAI-compatible base URL (FLOYD_GLM_ENDPOINT); defaults to the provider's */
+  baseUrl?: string;
+  /** Environment variable holding the key, instead of the provider's */
+  apiKeyEnv?: string;
+  /** Sampling temperature (0-2) */
+  temperature?: number;
+  /** Tool names or categories (file, search, git, ...) the model may use */
+  tools?: string[];
+  /** Tool profile (reader, editor, admin, ...) to lock the session to */
+  toolProfile?: string;
+  /** Stop a run after this many loop iterations */
+  maxTurns?: number;
+  /** Stop a run after it has used this many tokens */
+  tokenBudget?: number;
+  /** Stop a run after this many tool calls */
+  toolCallBudget?: number;
+  /** Stop a run after this many seconds */
+  timeBudgetSeconds?: number;
+  /** Stop a run once its estimated cost reaches this many USD */
+  costBudgetUsd?: number;
+  /** Execution mode, i.e. approval policy (ask, plan, auto, yolo, ...) */
+  mode?: ExecutionMode;
+  /** Preview diffs before write/edit tools apply */
+  diffPreview?: boolean;
+  /** Log level */
+  verbosity?: LogLevel;
+}
+
+/**
+ * Profiles shipped with FLOYD
+ */
+export const BUILTIN_PROFILES: Record<string, RunProfile> = {
+  'quick-answer': {
+    name: 'quick-answer',
+    description: 'Fast, cheap answers: flash model, read-only tools, short runs',
+    model: 'glm-4-flash',
+    tools: ['file', 'search', 'git_status', 'git_log', 'git_diff'],
+    maxTurns: 5,
+    tokenBudget: 50_000,
+    mode: 'plan',
+    verbosity: 'warn',
+  },
+  'deep-refactor': {
+    name: 'deep-refactor',
+    description: 'Long multi-file changes: full tool set, large budgets, diff previews',
+    model: 'glm-4.7',
+    maxTurns: 100,
+    tokenBudget: 2_000_000,
+    mode: 'auto',
+    diffPreview: true,
+    verbosity: 'info',
+  },
+  'glm-coding': {
+    name: 'glm-coding',
+    description: 'Z.ai GLM coding plan (the default connection)',
+    provider: 'zai',
+    model: 'glm-4.7',
+  },
+  'anthropic': {
+    name: 'anthropic',
+    description: 'Native Anthropic account (key from `floyd auth login anthropic` or ANTHROPIC_API_KEY)',
+    provider: 'anthropic',
+    model: 'claude-sonnet-4-20250514',
+  },
+  'ci-safe': {
+    name: 'ci-safe',
+    description: 'Unattended CI runs: no prompts, no browser, network or git writes',
+    tools: ['read_file', 'write', 'edit_file', 'search_replace', 'list_directory', 'search', 'patch', 'run', 'verify', 'git_status', 'git_diff', 'git_log'],
+    maxTurns: 30,
+    tokenBudget: 500_000,
+    mode: 'ask',
+    diffPreview: false,
+    verbosity: 'info',
+  },
+};
+
+/**
+ * OpenAI-compatible base URLs FLOYD can talk to, per provider
+ */
+export const PROVIDER_BASE_URLS: Record<Provider, string> = {
+  zai: 'https://api.z.ai/api/coding/paas/v4',
+  anthropic: 'https://api.anthropic.com/v1',
+  openai: 'https://api.openai.com/v1',
+  deepseek: 'https://api.deepseek.com/v1',
+};
+
+const EXECUTION_MODES: ExecutionMode[] = ['ask', 'yolo', 'plan', 'auto', 'dialogue', 'fuckit'];
+const LOG_LEVELS: LogLevel[] = ['debug', 'info', 'warn', 'error'];
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+/**
+ * Check a profile from a config file, dropping invalid fields
+ */
+export function normalizeProfile(name: string, raw: Record<string, unknown>): RunProfile {
+  const profile: RunProfile = {
+    name,
+    description: typeof raw.description === 'string' ? raw.description : '',
+  };
+
+  if (typeof raw.model === 'string') profile.model = raw.model;
+  const provider = typeof raw.provider === 'string' ? parseProvider(raw.provider) : null;
+  if (provider) profile.provider = provider;
+  if (typeof raw.baseUrl === 'string' && /^https?:\/\//.test(raw.baseUrl)) profile.baseUrl = raw.baseUrl.replace(/\/+$/, '');
+  if (typeof raw.apiKeyEnv === 'string' && raw.apiKeyEnv) profile.apiKeyEnv = raw.apiKeyEnv;
+  if (typeof raw.temperature === 'number' && raw.temperature >= 0 && raw.temperature <= 2) profile.temperature = raw.temperature;
+  if (Array.isArray(raw.tools)) profile.tools = raw.tools.filter((t): t is string => typeof t === 'string');
+  if (typeof raw.toolProfile === 'string' && raw.toolProfile) profile.toolProfile = raw.toolProfile;
+  if (typeof raw.maxTurns === 'number' && raw.maxTurns > 0) profile.maxTurns = raw.maxTurns;
+  if (typeof raw.tokenBudget === 'number' && raw.tokenBudget > 0) profile.tokenBudget = raw.tokenBudget;
+  if (typeof raw.toolCallBudget === 'number' && raw.toolCallBudget > 0) profile.toolCallBudget = raw.toolCallBudget;
+  if (typeof raw.timeBudgetSeconds === 'number' && raw.timeBudgetSeconds > 0) profile.timeBudgetSeconds = raw.timeBudgetSeconds;
+  if (typeof raw.costBudgetUsd === 'number' && raw.costBudgetUsd > 0) profile.costBudgetUsd = raw.costBudgetUsd;
+  if (EXECUTION_MODES.includes(raw.mode as ExecutionMode)) profile.mode = raw.mode as ExecutionMode;
+  if (typeof raw.diffPreview === 'boolean') profile.diffPreview = raw.diffPreview;
+  if (LOG_LEVELS.includes(raw.verbosity as LogLevel)) profile.verbosity = raw.verbosity as LogLevel;
+
+  return profile;
+}
+
+/**
+ * Read a profiles.json file ({} when missing or invalid)
+ */
+function readProfileFile(filePath: string): Record<string, RunProfile> {
+  try {
+    const raw = fs.readJsonSync(filePath) as Record<string, Record<string, unknown>>;
+    return Object.fromEntries(
+      Object.entries(raw)
+        .filter(([, value]) => value && typeof value === 'object')
+        .map(([name, value]) => [name, normalizeProfile(name, value)])
+    );
+  } catch (error) {
+    if (fs.existsSync(filePath)) {
+      console.warn(`Failed to read ${filePath}: ${error}`);
+    }
+    return {};
+  }
+}
+
+/**
+ * All available profiles: built-ins, then user, then project files
+ */
+export function loadProfiles(projectRoot: string = process.cwd()): Record<string, RunProfile> {
+  return {
+    ...BUILTIN_PROFILES,
+    ...readProfileFile(path.join(os.homedir(), '.floyd', 'profiles.json')),
+    ...readProfileFile(path.join(projectRoot, '.floyd', 'profiles.json')),
+  };
+}
+
+/**
+ * Look up a profile by name
+ */
+export function getProfile(name: string, projectRoot?: string): RunProfile | undefined {
+  return loadProfiles(projectRoot)[name];
+}
+
+// ============================================================================
+// Applying
+// ============================================================================
+
+/**
+ * Apply a profile on top of a configuration
+ *
+ * Returns a new config; mode is also mirrored to FLOYD_MODE, which the
+ * engine and permission prompts read at runtime.
+ */
+export function applyProfile(config: FloydConfig, profile: RunProfile): FloydConfig {
+  const next: FloydConfig = { ...config, profile: profile.name };
+
+  if (profile.model) next.glmModel = profile.model;
+  if (profile.temperature !== undefined) next.temperature = profile.temperature;
+
+  // A connection profile brings its own endpoint and key; a missing key is
+  // left empty rather than sending another provider's key
+  if (profile.provider || profile.baseUrl || profile.apiKeyEnv) {
+    const baseUrl = profile.baseUrl ?? (profile.provider ? PROVIDER_BASE_URLS[profile.provider] : undefined);
+    if (baseUrl) next.glmApiEndpoint = baseUrl;
+    if (profile.provider || profile.apiKeyEnv) next.glmApiKey = resolveProfileKey(profile) ?? '';
+  }
+
+  if (profile.mode) {
+    next.mode = profile.mode;
+    process.env.FLOYD_MODE = profile.mode;
+  }
+  if (profile.diffPreview !== undefined) next.diffPreview = profile.diffPreview;
+  if (profile.verbosity) next.logLevel = profile.verbosity;
+
+  next.allowedTools = profile.tools;
+  if (profile.toolProfile) next.toolProfile = profile.toolProfile;
+  // Profile limits override FLOYD_MAX_RUN_* ones
+  const budget: RunBudget = { ...loadRunBudgetFromEnv() };
+  if (profile.maxTurns) budget.maxTurns = profile.maxTurns;
+  if (profile.tokenBudget) budget.maxTokens = profile.tokenBudget;
+  if (profile.toolCallBudget) budget.maxToolCalls = profile.toolCallBudget;
+  if (profile.timeBudgetSeconds) budget.maxDurationMs = profile.timeBudgetSeconds * 1000;
+  if (profile.costBudgetUsd) budget.maxCostUsd = profile.costBudgetUsd;
+  next.runBudget = Object.keys(budget).length > 0 ? budget : undefined;
+  if (profile.maxTurns) next.maxTurns = profile.maxTurns;
+
+  return next;
+}
+
+/**
+ * Key for a connection profile: its apiKeyEnv variable, else the provider's
+ */
+function resolveProfileKey(profile: RunProfile): string | undefined {
+  if (profile.apiKeyEnv && process.env[profile.apiKeyEnv]) {
+    return process.env[profile.apiKeyEnv];
+  }
+  if (!profile.provider) {
+    return undefined;
+  }
+  try {
+    return getCredentialStore().resolve(profile.provider) ?? undefined;
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * Describe a profile's settings on one line
+ */
+export function formatProfile(profile: RunProfile): string {
+  const parts = [
+    profile.provider && `provider ${profile.provider}`,
+    profile.baseUrl && `url ${profile.baseUrl}`,
+    profile.apiKeyEnv && `key $${profile.apiKeyEnv}`,
+    profile.model && `model ${profile.model}`,
+    profile.temperature !== undefined && `temperature ${profile.temperature}`,
+    profile.mode && `mode ${profile.mode}`,
+    profile.tools && `tools ${profile.tools.join(',')}`,
+    profile.toolProfile && `tool profile ${profile.toolProfile}`,
+    profile.maxTurns && `≤${profile.maxTurns} turns`,
+    profile.tokenBudget && `≤${profile.tokenBudget.toLocaleString('en-US')} tokens`,
+    profile.toolCallBudget && `≤${profile.toolCallBudget} tool calls`,
+    profile.timeBudgetSeconds && `≤${profile.timeBudgetSeconds}s`,
+    profile.costBudgetUsd && `≤$${profile.costBudgetUsd.toFixed(2)}`,
+    profile.diffPreview !== undefined && `diff preview ${profile.diffPreview ? 'on' : 'off'}`,
+    profile.verbosity && `log ${profile.verbosity}`,
+  ].filter(Boolean);
+  return parts.join(', ');
+}
+
+/**
+ * Whether a profile's tool list permits a tool (by name or category)
+ */
+export function isToolAllowed(tool: { name: string; category: string }, allowed?: string[]): boolean {
+  return !allowed || allowed.includes(tool.name) || allowed.includes(tool.category);
+}
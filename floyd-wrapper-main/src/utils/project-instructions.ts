@@ -0,0 +1,172 @@
+/**
+ * Project Instructions - Floyd Wrapper
+ *
+ * Repositories carry agent instructions in FLOYD.md, AGENTS.md or CLAUDE.md.
+ * Files at the project root are merged into the system prompt at startup.
+ * Files in subdirectories (e.g. packages/api/AGENTS.md) only apply to work in
+ * that part of the tree, so they are attached to the first tool result that
+ * touches a path below them instead of bloating every prompt.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * An instruction file found in the project
+ */
+export interface InstructionFile {
+  /** Path relative to the project root */
+  relativePath: string;
+  content: string;
+}
+
+/**
+ * Recognized file names, in merge order
+ */
+export const INSTRUCTION_FILE_NAMES = ['FLOYD.md', 'AGENTS.md', 'CLAUDE.md'];
+
+/**
+ * Personal instructions for the agent in this project, editable with /prompt edit
+ */
+export const AGENT_INSTRUCTIONS_FILE = path.join('.floyd', 'AGENT_INSTRUCTIONS.md');
+
+/**
+ * Workspace state kept in .floyd/ that is part of the project context
+ */
+export const WORKSPACE_STATE_FILES = [
+  path.join('.floyd', 'master_plan.md'),
+  path.join('.floyd', 'stack.md'),
+];
+
+/**
+ * Characters kept per file; instruction files are meant to be short
+ */
+const MAX_FILE_CHARS = 20_000;
+
+// ============================================================================
+// Reading
+// ============================================================================
+
+/**
+ * Read the instruction files in one directory
+ *
+ * CLAUDE.md is often a symlink to (or copy of) AGENTS.md; duplicates are
+ * only included once.
+ */
+export function readInstructionFiles(dir: string, projectRoot: string): InstructionFile[] {
+  const files: InstructionFile[] = [];
+  const seen = new Set<string>();
+
+  for (const name of INSTRUCTION_FILE_NAMES) {
+    const filePath = path.join(dir, name);
+    try {
+      if (!fs.statSync(filePath).isFile()) {
+        continue;
+      }
+      const raw = fs.readFileSync(filePath, 'utf-8').trim();
+      if (!raw || seen.has(raw)) {
+        continue;
+      }
+      seen.add(raw);
+      files.push({
+        relativePath: path.relative(projectRoot, filePath),
+        content: raw.length > MAX_FILE_CHARS
+          ? `${raw.slice(0, MAX_FILE_CHARS)}\n\n[... ${raw.length - MAX_FILE_CHARS} characters truncated]`
+          : raw,
+      });
+    } catch (error) {
+      if ((error as NodeJS.ErrnoException).code !== 'ENOENT') {
+        console.warn(`Failed to read ${filePath}: ${error}`);
+      }
+    }
+  }
+
+  return files;
+}
+
+/**
+ * Merge instruction files into one prompt section, each under its path
+ */
+export function formatInstructionFiles(files: InstructionFile[]): string {
+  return files.map(file => `### ${file.relativePath}\n\n${file.content}`).join('\n\n');
+}
+
+/**
+ * Load the root instruction files, .floyd/AGENT_INSTRUCTIONS.md and the
+ * workspace state (master plan, stack) as project context (undefined when none)
+ */
+export function loadProjectInstructions(projectRoot: string = process.cwd()): string | undefined {
+  const files = readInstructionFiles(projectRoot, projectRoot);
+  for (const relativePath of [AGENT_INSTRUCTIONS_FILE, ...WORKSPACE_STATE_FILES]) {
+    const filePath = path.join(projectRoot, relativePath);
+    if (fs.existsSync(filePath)) {
+      const content = fs.readFileSync(filePath, 'utf-8').trim();
+      if (content) {
+        files.push({ relativePath, content: content.slice(0, MAX_FILE_CHARS) });
+      }
+    }
+  }
+  return files.length > 0 ? formatInstructionFiles(files) : undefined;
+}
+
+/**
+ * Files whose changes alter the project context, relative to the project root
+ */
+export function projectContextFiles(): string[] {
+  return [...INSTRUCTION_FILE_NAMES, AGENT_INSTRUCTIONS_FILE, ...WORKSPACE_STATE_FILES];
+}
+
+// ============================================================================
+// Nested Instructions
+// ============================================================================
+
+/**
+ * Tracks which nested instruction files have been shown during a session
+ */
+export class NestedInstructions {
+  private readonly projectRoot: string;
+  private readonly visited = new Set<string>();
+
+  constructor(projectRoot: string = process.cwd()) {
+    this.projectRoot = path.resolve(projectRoot);
+  }
+
+  /**
+   * Instruction files between the project root and a path that have not
+   * been shown yet, outermost first
+   */
+  collect(targetPath: string): InstructionFile[] {
+    const resolved = path.resolve(this.projectRoot, targetPath);
+    const relative = path.relative(this.projectRoot, resolved);
+    if (!relative || relative.startsWith('..') || path.isAbsolute(relative)) {
+      return [];
+    }
+
+    // Directories from just below the root down to the target's directory
+    const isDir = fs.existsSync(resolved) && fs.statSync(resolved).isDirectory();
+    const parts = (isDir ? relative : path.dirname(relative)).split(path.sep).filter(p => p && p !== '.');
+
+    const files: InstructionFile[] = [];
+    let dir = this.projectRoot;
+    for (const part of parts) {
+      dir = path.join(dir, part);
+      if (this.visited.has(dir)) {
+        continue;
+      }
+      this.visited.add(dir);
+      files.push(...readInstructionFiles(dir, this.projectRoot));
+    }
+    return files;
+  }
+
+  /**
+   * Forget what has been shown (e.g. for a new session)
+   */
+  reset(): void {
+    this.visited.clear();
+  }
+}
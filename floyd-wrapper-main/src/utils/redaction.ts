@@ -0,0 +1,213 @@
+/**
+ * Secret Redaction - Floyd Wrapper
+ *
+ * Replaces secrets (API keys, tokens, private keys, passwords) in prompts and
+ * tool results with placeholders like [REDACTED:github-token] before they are
+ * sent to the LLM or saved in the session database, and in everything
+ * written to the log file, the event stream and run recordings. Known key formats are
+ * matched by pattern; values assigned to names like "password" or "api_key"
+ * are redacted when they look random enough (Shannon entropy).
+ *
+ * The model never sees the secrets, so writing back what it read would save
+ * the placeholders over them: ToolRegistry rejects writes and edits that add
+ * placeholders a file doesn't already contain.
+ *
+ * Every redaction is appended to .floyd/logs/redactions.log with the kind,
+ * length and a short SHA-256 fingerprint of the secret, never the secret.
+ * FLOYD_REDACT_SECRETS=false turns redaction off.
+ */
+
+import crypto from 'node:crypto';
+import fs from 'fs-extra';
+import path from 'node:path';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * One secret that was replaced
+ */
+export interface Redaction {
+  /** Kind of secret, as shown in the placeholder */
+  kind: string;
+  /** First 12 hex characters of the secret's SHA-256, to tell secrets apart */
+  fingerprint: string;
+  length: number;
+}
+
+/**
+ * Text with its secrets replaced
+ */
+export interface RedactionResult {
+  text: string;
+  redactions: Redaction[];
+}
+
+// ============================================================================
+// Patterns
+// ============================================================================
+
+/**
+ * Secrets with a recognizable format, most specific first
+ */
+const SECRET_PATTERNS: Array<{ kind: string; pattern: RegExp }> = [
+  { kind: 'private-key', pattern: /-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----[\s\S]*?-----END (?:[A-Z]+ )*PRIVATE KEY-----/g },
+  { kind: 'anthropic-key', pattern: /\bsk-ant-[A-Za-z0-9_-]{20,}/g },
+  { kind: 'openai-key', pattern: /\bsk-(?:proj-)?[A-Za-z0-9_-]{32,}/g },
+  { kind: 'github-token', pattern: /\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})/g },
+  { kind: 'aws-access-key', pattern: /\b(?:AKIA|ASIA)[0-9A-Z]{16}\b/g },
+  { kind: 'slack-token', pattern: /\bxox[abposr]-[A-Za-z0-9-]{10,}/g },
+  { kind: 'google-api-key', pattern: /\bAIza[0-9A-Za-z_-]{35}/g },
+  { kind: 'stripe-key', pattern: /\b(?:sk|rk)_(?:live|test)_[0-9A-Za-z]{16,}/g },
+  { kind: 'jwt', pattern: /\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}/g },
+];
+
+/**
+ * `name = value` / `"name": "value"` where the name suggests a secret; quotes
+ * may be JSON-escaped since tool results are serialized
+ */
+const ASSIGNMENT_PATTERN =
+  /\b([A-Za-z0-9_.-]*(?:api[_-]?key|secret|token|passw(?:or)?d|credential|auth[_-]?key)[A-Za-z0-9_.-]*)(\\?["'])?(\s*[:=]\s*)(\\?["'])?([^\s"'\\,;()<>]{8,})/gi;
+
+/**
+ * Entropy (bits per character) above which an assigned value is treated as
+ * a secret rather than a word, path or reference
+ */
+export const MIN_SECRET_ENTROPY = 3;
+
+/**
+ * Assigned values that point at a secret instead of containing one
+ */
+const REFERENCE_VALUE = /^(?:process\.env|env\.|os\.environ|\$\{?|\[REDACTED)/;
+
+// ============================================================================
+// Redaction
+// ============================================================================
+
+/**
+ * Whether redaction is on (FLOYD_REDACT_SECRETS=false turns it off)
+ */
+export function isRedactionEnabled(): boolean {
+  return process.env.FLOYD_REDACT_SECRETS !== 'false';
+}
+
+/**
+ * Shannon entropy of a string in bits per character
+ */
+export function shannonEntropy(value: string): number {
+  const counts = new Map<string, number>();
+  for (const char of value) {
+    counts.set(char, (counts.get(char) ?? 0) + 1);
+  }
+  let entropy = 0;
+  for (const count of counts.values()) {
+    const p = count / value.length;
+    entropy -= p * Math.log2(p);
+  }
+  return entropy;
+}
+
+/**
+ * Start of every placeholder
+ */
+export const PLACEHOLDER_PREFIX = '[REDACTED:';
+
+function placeholder(kind: string): string {
+  return `${PLACEHOLDER_PREFIX}${kind}]`;
+}
+
+/**
+ * Number of placeholders in a text
+ */
+export function countPlaceholders(text: string): number {
+  return text.split(PLACEHOLDER_PREFIX).length - 1;
+}
+
+function describe(kind: string, secret: string): Redaction {
+  return {
+    kind,
+    fingerprint: crypto.createHash('sha256').update(secret).digest('hex').slice(0, 12),
+    length: secret.length,
+  };
+}
+
+/**
+ * Replace the secrets in a text with placeholders
+ */
+export function redactSecrets(text: string): RedactionResult {
+  const redactions: Redaction[] = [];
+  let result = text;
+
+  for (const { kind, pattern } of SECRET_PATTERNS) {
+    result = result.replace(pattern, secret => {
+      redactions.push(describe(kind, secret));
+      return placeholder(kind);
+    });
+  }
+
+  result = result.replace(ASSIGNMENT_PATTERN, (match, name: string, openQuote = '', separator: string, valueQuote = '', value: string) => {
+    if (REFERENCE_VALUE.test(value) || shannonEntropy(value) < MIN_SECRET_ENTROPY) {
+      return match;
+    }
+    redactions.push(describe('secret', value));
+    return `${name}${openQuote}${separator}${valueQuote}${placeholder('secret')}`;
+  });
+
+  return { text: result, redactions };
+}
+
+/**
+ * Serialized JSON (a log line or event) with its secrets replaced; unchanged
+ * when redaction is off
+ */
+export function redactSerialized(json: string): string {
+  return isRedactionEnabled() ? redactSecrets(json).text : json;
+}
+
+/**
+ * A JSON-compatible value with the secrets in its strings replaced;
+ * unchanged when redaction is off or the value can't be serialized
+ */
+export function redactValue<T>(value: T): T {
+  if (value === undefined || !isRedactionEnabled()) {
+    return value;
+  }
+  try {
+    const json = JSON.stringify(value);
+    const { text, redactions } = redactSecrets(json);
+    return redactions.length > 0 ? JSON.parse(text) as T : value;
+  } catch {
+    return value;
+  }
+}
+
+// ============================================================================
+// Audit Log
+// ============================================================================
+
+/**
+ * Location of the redaction audit log
+ */
+export function getRedactionLogPath(cwd: string = process.cwd()): string {
+  return path.join(cwd, '.floyd', 'logs', 'redactions.log');
+}
+
+/**
+ * Append redactions to the audit log, one JSON line each; `source` says
+ * where the text came from (e.g. "prompt" or "tool:read_file")
+ */
+export function auditRedactions(redactions: Redaction[], source: string, cwd: string = process.cwd()): void {
+  if (redactions.length === 0) {
+    return;
+  }
+  const time = new Date().toISOString();
+  const lines = redactions.map(redaction => JSON.stringify({ time, source, ...redaction })).join('\n');
+  try {
+    const file = getRedactionLogPath(cwd);
+    fs.ensureDirSync(path.dirname(file));
+    fs.appendFileSync(file, `${lines}\n`);
+  } catch {
+    // The audit log must never break a run
+  }
+}
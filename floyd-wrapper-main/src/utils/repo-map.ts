@@ -0,0 +1,389 @@
+/**
+ * Repository Map - Floyd Wrapper
+ *
+ * A compact outline of the project for the system prompt: modules (package.json,
+ * go.mod, Cargo.toml, pyproject.toml), the directory tree and the exported
+ * symbols of each source file. Built before the first request and cached in
+ * .floyd/repo-map.json; later requests only re-read files whose mtime
+ * changed. Each request gets the slice of the map most relevant to it, so the
+ * model can pick the right files and tools on the first try.
+ */
+
+import fs from 'fs-extra';
+import path from 'node:path';
+import { globby } from 'globby';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Exported symbols of one source file
+ */
+export interface RepoMapFile {
+  /** Path relative to the project root, with forward slashes */
+  path: string;
+  mtimeMs: number;
+  symbols: string[];
+}
+
+/**
+ * A module manifest found in the project
+ */
+export interface RepoModule {
+  /** Manifest path relative to the project root */
+  manifest: string;
+  name: string;
+}
+
+/**
+ * The full map
+ */
+export interface RepoMap {
+  version: 1;
+  files: RepoMapFile[];
+  modules: RepoModule[];
+}
+
+/**
+ * Heading of the system prompt section with the map
+ */
+export const REPO_MAP_SECTION_HEADING = '## Repository Map';
+
+/**
+ * Characters of map put into the prompt (FLOYD_REPO_MAP_CHARS)
+ */
+export const DEFAULT_REPO_MAP_CHARS = 6000;
+
+/**
+ * Files listed at most; larger trees are mapped partially
+ */
+const MAX_FILES = 3000;
+
+/**
+ * Files larger than this are listed without symbols
+ */
+const MAX_PARSE_BYTES = 256 * 1024;
+
+const IGNORE = ['**/node_modules/**', '**/.git/**', '**/dist/**', '**/build/**', '**/target/**', '**/vendor/**', '**/.floyd/**', '**/coverage/**'];
+
+const MANIFESTS = ['package.json', 'go.mod', 'Cargo.toml', 'pyproject.toml'];
+
+// ============================================================================
+// Symbol Extraction
+// ============================================================================
+
+/**
+ * Exported symbol patterns per file extension; functions are shown as name()
+ */
+const SYMBOL_PATTERNS: Array<{ extensions: string[]; patterns: Array<{ regex: RegExp; fn?: boolean }> }> = [
+  {
+    extensions: ['.ts', '.tsx', '.js', '.jsx', '.mjs', '.cjs', '.mts', '.cts'],
+    patterns: [
+      { regex: /^export\s+(?:default\s+)?(?:declare\s+)?(?:async\s+)?function\*?\s+([A-Za-z_$][\w$]*)/gm, fn: true },
+      { regex: /^export\s+(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:class|interface|type|enum|namespace)\s+([A-Za-z_$][\w$]*)/gm },
+      { regex: /^export\s+(?:declare\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)/gm },
+    ],
+  },
+  {
+    extensions: ['.go'],
+    patterns: [
+      { regex: /^func\s+(?:\(\s*(?:\w+\s+)?\*?(\w+)(?:\[[^\]]*\])?\s*\)\s*)?([A-Z]\w*)/gm, fn: true },
+      { regex: /^type\s+([A-Z]\w*)/gm },
+    ],
+  },
+  {
+    extensions: ['.py'],
+    patterns: [
+      { regex: /^(?:async\s+)?def\s+([A-Za-z]\w*)/gm, fn: true },
+      { regex: /^class\s+([A-Za-z]\w*)/gm },
+    ],
+  },
+  {
+    extensions: ['.rs'],
+    patterns: [
+      { regex: /^\s*pub\s+(?:async\s+)?fn\s+(\w+)/gm, fn: true },
+      { regex: /^\s*pub\s+(?:struct|enum|trait|type|mod)\s+(\w+)/gm },
+    ],
+  },
+];
+
+/**
+ * Whether symbols are extracted from a file
+ */
+export function isMappedSource(filePath: string): boolean {
+  const ext = path.extname(filePath);
+  return !filePath.endsWith('.d.ts')
+    && !/(_test\.go|\.test\.[jt]sx?|\.spec\.[jt]sx?)$/.test(filePath)
+    && SYMBOL_PATTERNS.some(group => group.extensions.includes(ext));
+}
+
+/**
+ * Exported symbols of a source file, in file order
+ */
+export function extractSymbols(filePath: string, content: string): string[] {
+  const group = SYMBOL_PATTERNS.find(g => g.extensions.includes(path.extname(filePath)));
+  if (!group) {
+    return [];
+  }
+
+  const found: Array<{ index: number; symbol: string }> = [];
+  for (const { regex, fn } of group.patterns) {
+    for (const match of content.matchAll(regex)) {
+      // Go methods are shown with their receiver type
+      const name = match[1] && match[2] ? `${match[1]}.${match[2]}` : match[2] ?? match[1];
+      if (name) {
+        found.push({ index: match.index ?? 0, symbol: fn ? `${name}()` : name });
+      }
+    }
+  }
+
+  return [...new Set(found.sort((a, b) => a.index - b.index).map(f => f.symbol))];
+}
+
+/**
+ * Module name declared by a manifest
+ */
+export function readModuleName(manifest: string, content: string): string | undefined {
+  switch (path.basename(manifest)) {
+    case 'package.json':
+      try {
+        return JSON.parse(content).name || undefined;
+      } catch {
+        return undefined;
+      }
+    case 'go.mod':
+      return content.match(/^module\s+(\S+)/m)?.[1];
+    default:
+      // Cargo.toml [package] / pyproject.toml [project]
+      return content.match(/^\[(?:package|project)\][^[]*?^name\s*=\s*"([^"]+)"/ms)?.[1];
+  }
+}
+
+// ============================================================================
+// Repo Map Class
+// ============================================================================
+
+/**
+ * The map of one project, kept up to date lazily
+ */
+export class RepoMapIndex {
+  private readonly root: string;
+  private readonly cachePath: string;
+  private map: RepoMap | null = null;
+
+  constructor(root: string = process.cwd()) {
+    this.root = path.resolve(root);
+    this.cachePath = path.join(this.root, '.floyd', 'repo-map.json');
+  }
+
+  /**
+   * Bring the map up to date, re-reading only new or changed files
+   *
+   * @returns Whether anything changed
+   */
+  async refresh(): Promise<boolean> {
+    const previous = this.map ?? await this.loadCache();
+    const known = new Map((previous?.files ?? []).map(file => [file.path, file]));
+
+    const paths = (await globby('**/*', {
+      cwd: this.root,
+      ignore: IGNORE,
+      gitignore: true,
+      onlyFiles: true,
+      dot: false,
+    })).sort().slice(0, MAX_FILES);
+
+    let changed = !previous || previous.files.length !== paths.filter(isMappedSource).length;
+    const files: RepoMapFile[] = [];
+    const modules: RepoModule[] = [];
+
+    for (const relative of paths) {
+      const absolute = path.join(this.root, relative);
+
+      if (MANIFESTS.includes(path.basename(relative))) {
+        const name = readModuleName(relative, await fs.readFile(absolute, 'utf-8').catch(() => ''));
+        if (name) {
+          modules.push({ manifest: relative, name });
+        }
+      }
+
+      if (!isMappedSource(relative)) {
+        continue;
+      }
+
+      const stat = await fs.stat(absolute).catch(() => null);
+      if (!stat) {
+        continue;
+      }
+
+      const cached = known.get(relative);
+      if (cached && cached.mtimeMs === stat.mtimeMs) {
+        files.push(cached);
+        continue;
+      }
+
+      changed = true;
+      const symbols = stat.size <= MAX_PARSE_BYTES
+        ? extractSymbols(relative, await fs.readFile(absolute, 'utf-8').catch(() => ''))
+        : [];
+      files.push({ path: relative, mtimeMs: stat.mtimeMs, symbols });
+    }
+
+    changed ||= JSON.stringify(modules) !== JSON.stringify(previous?.modules ?? []);
+    this.map = { version: 1, files, modules };
+    if (changed) {
+      await this.saveCache();
+    }
+    return changed;
+  }
+
+  /**
+   * The current map (refresh() first)
+   */
+  getMap(): RepoMap {
+    return this.map ?? { version: 1, files: [], modules: [] };
+  }
+
+  /**
+   * The part of the map most relevant to a request, within a character budget
+   */
+  getSlice(query: string, maxChars: number = DEFAULT_REPO_MAP_CHARS): string {
+    return formatRepoMap(this.getMap(), query, maxChars);
+  }
+
+  private async loadCache(): Promise<RepoMap | null> {
+    try {
+      const map = await fs.readJson(this.cachePath) as RepoMap;
+      return map.version === 1 && Array.isArray(map.files) ? map : null;
+    } catch {
+      return null;
+    }
+  }
+
+  private async saveCache(): Promise<void> {
+    try {
+      await fs.ensureDir(path.dirname(this.cachePath));
+      await fs.writeFile(this.cachePath, JSON.stringify(this.map));
+    } catch {
+      // The cache only saves time on the next start
+    }
+  }
+}
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+/**
+ * Words of a request worth matching against paths and symbols
+ */
+function queryTerms(query: string): string[] {
+  return [...new Set(
+    query
+      .replace(/([a-z])([A-Z])/g, '$1 $2')
+      .toLowerCase()
+      .split(/[^a-z0-9]+/)
+      .filter(word => word.length >= 3),
+  )];
+}
+
+/**
+ * How well a file matches the request's terms
+ */
+function scoreFile(file: RepoMapFile, terms: string[]): number {
+  if (terms.length === 0) {
+    return 0;
+  }
+  const pathText = file.path.toLowerCase();
+  const symbolText = file.symbols.join(' ').replace(/([a-z])([A-Z])/g, '$1 $2').toLowerCase();
+  return terms.reduce((score, term) =>
+    score + (pathText.includes(term) ? 2 : 0) + (symbolText.includes(term) ? 1 : 0), 0);
+}
+
+/**
+ * Directory tree with file counts, two levels deep
+ */
+function formatTree(files: RepoMapFile[]): string[] {
+  const counts = new Map<string, number>();
+  for (const file of files) {
+    const parts = file.path.split('/').slice(0, -1);
+    for (let depth = 1; depth <= Math.min(parts.length, 2); depth++) {
+      const dir = parts.slice(0, depth).join('/');
+      counts.set(dir, (counts.get(dir) ?? 0) + 1);
+    }
+  }
+  return [...counts.keys()].sort().map(dir => {
+    const depth = dir.split('/').length - 1;
+    return `${'  '.repeat(depth)}${path.posix.basename(dir)}/ (${counts.get(dir)} files)`;
+  });
+}
+
+/**
+ * Map section for the system prompt; files most relevant to the query first
+ */
+export function formatRepoMap(map: RepoMap, query: string, maxChars: number = DEFAULT_REPO_MAP_CHARS): string {
+  if (map.files.length === 0 && map.modules.length === 0) {
+    return '';
+  }
+
+  const lines: string[] = [REPO_MAP_SECTION_HEADING, ''];
+  if (map.modules.length > 0) {
+    lines.push('Modules:', ...map.modules.map(module => `- ${module.name} (${module.manifest})`), '');
+  }
+
+  const tree = formatTree(map.files);
+  if (tree.length > 0) {
+    lines.push('Directories:', ...tree.slice(0, 60), '');
+  }
+
+  const terms = queryTerms(query);
+  const ranked = map.files
+    .filter(file => file.symbols.length > 0)
+    .map(file => ({ file, score: scoreFile(file, terms) }))
+    .sort((a, b) =>
+      b.score - a.score
+      || a.file.path.split('/').length - b.file.path.split('/').length
+      || a.file.path.localeCompare(b.file.path));
+
+  lines.push('Exported symbols (most relevant first):');
+  let length = lines.join('\n').length;
+  let shown = 0;
+  for (const { file } of ranked) {
+    const line = `- ${file.path}: ${file.symbols.slice(0, 12).join(', ')}${file.symbols.length > 12 ? ', ...' : ''}`;
+    if (length + line.length + 1 > maxChars) {
+      break;
+    }
+    lines.push(line);
+    length += line.length + 1;
+    shown++;
+  }
+  if (shown < ranked.length) {
+    lines.push(`(${ranked.length - shown} more files not shown; use list_directory or grep to explore)`);
+  }
+
+  return lines.join('\n');
+}
+
+/**
+ * A system prompt without its repository map section
+ */
+export function stripRepoMapSection(prompt: string): string {
+  const index = prompt.indexOf(`\n\n${REPO_MAP_SECTION_HEADING}\n`);
+  return index === -1 ? prompt : prompt.slice(0, index);
+}
+
+/**
+ * Whether the map is on (FLOYD_REPO_MAP=off turns it off)
+ */
+export function isRepoMapEnabled(): boolean {
+  const value = process.env.FLOYD_REPO_MAP?.toLowerCase();
+  return value !== 'off' && value !== 'false' && value !== '0';
+}
+
+/**
+ * Prompt budget for the map (FLOYD_REPO_MAP_CHARS)
+ */
+export function getRepoMapChars(): number {
+  return parseInt(process.env.FLOYD_REPO_MAP_CHARS || '', 10) || DEFAULT_REPO_MAP_CHARS;
+}
@@ -0,0 +1,182 @@
+/**
+ * Run Status - Floyd Wrapper
+ *
+ * Publishes the current run state (idle/running/awaiting-approval/done) to a
+ * small status file so shell prompts and tmux status lines can show it via
+ * `floyd status --porcelain`. Attention (bell, OS notification) is left to
+ * the Notifier so notify_bell and notify_after apply to every alert.
+ *
+ * Status file: ~/.floyd/status.json (override with FLOYD_STATUS_FILE)
+ */
+
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { getEventBroadcaster } from '../streaming/event-broadcaster.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+/**
+ * Run states reported to external status lines
+ */
+export type RunState = 'idle' | 'running' | 'awaiting-approval' | 'done';
+
+/**
+ * Contents of the status file
+ */
+export interface RunStatus {
+  state: RunState;
+  /** When the state was entered (ms since epoch) */
+  since: number;
+  /** Process that wrote the status */
+  pid: number;
+  /** Workspace the process runs in */
+  cwd: string;
+  /** Optional detail (e.g. tool awaiting approval) */
+  detail?: string;
+  /** Live event stream for `floyd watch`, when enabled */
+  eventsUrl?: string;
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Location of the status file
+ */
+export function getStatusFilePath(): string {
+  return process.env.FLOYD_STATUS_FILE || path.join(os.homedir(), '.floyd', 'status.json');
+}
+
+/**
+ * Whether a process is still alive
+ */
+function isProcessAlive(pid: number): boolean {
+  try {
+    process.kill(pid, 0);
+    return true;
+  } catch (error) {
+    return (error as NodeJS.ErrnoException).code === 'EPERM';
+  }
+}
+
+/**
+ * Read the status file. Status left behind by a dead process reads as idle.
+ */
+export async function readRunStatus(filePath: string = getStatusFilePath()): Promise<RunStatus | null> {
+  try {
+    const status = await fs.readJson(filePath) as RunStatus;
+    if (!isProcessAlive(status.pid)) {
+      return { ...status, state: 'idle', detail: 'process exited' };
+    }
+    return status;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Format a status for scripts: `state<TAB>seconds-in-state<TAB>pid<TAB>cwd`
+ */
+export function formatRunStatusPorcelain(status: RunStatus | null, now: number = Date.now()): string {
+  if (!status) {
+    return 'idle\t0\t-\t-';
+  }
+  const seconds = Math.max(0, Math.floor((now - status.since) / 1000));
+  return `${status.state}\t${seconds}\t${status.pid}\t${status.cwd}`;
+}
+
+/**
+ * Format a status for humans
+ */
+export function formatRunStatus(status: RunStatus | null, now: number = Date.now()): string {
+  if (!status) {
+    return 'FLOYD is not running';
+  }
+  const seconds = Math.max(0, Math.floor((now - status.since) / 1000));
+  const elapsed = seconds >= 60 ? `${Math.floor(seconds / 60)}m${seconds % 60}s` : `${seconds}s`;
+  const detail = status.detail ? ` (${status.detail})` : '';
+  return `FLOYD is ${status.state}${detail} for ${elapsed} in ${status.cwd}`;
+}
+
+// ============================================================================
+// Run Status Reporter Class
+// ============================================================================
+
+/**
+ * Writes state changes for the current process to the status file
+ */
+export class RunStatusReporter {
+  private state: RunState | null = null;
+  private readonly filePath: string;
+
+  constructor(filePath: string = getStatusFilePath()) {
+    this.filePath = filePath;
+  }
+
+  /**
+   * Current state, or null if never set
+   */
+  getState(): RunState | null {
+    return this.state;
+  }
+
+  /**
+   * Record a new state
+   */
+  set(state: RunState, detail?: string): void {
+    if (state === this.state && !detail) {
+      return;
+    }
+    this.state = state;
+
+    const status: RunStatus = {
+      state,
+      since: Date.now(),
+      pid: process.pid,
+      cwd: process.cwd(),
+      detail,
+      eventsUrl: getEventBroadcaster().getUrl() ?? undefined,
+    };
+    try {
+      fs.ensureDirSync(path.dirname(this.filePath));
+      fs.writeJsonSync(this.filePath, status);
+    } catch {
+      // Status reporting must never break a run
+    }
+  }
+
+  /**
+   * Remove the status file if this process owns it
+   */
+  clear(): void {
+    try {
+      const status = fs.readJsonSync(this.filePath) as RunStatus;
+      if (status.pid === process.pid) {
+        fs.removeSync(this.filePath);
+      }
+    } catch {
+      // Nothing to clear
+    }
+    this.state = null;
+  }
+}
+
+// ============================================================================
+// Singleton
+// ============================================================================
+
+let defaultRunStatusReporter: RunStatusReporter | null = null;
+
+/**
+ * Get the shared run status reporter
+ */
+export function getRunStatusReporter(): RunStatusReporter {
+  if (!defaultRunStatusReporter) {
+    defaultRunStatusReporter = new RunStatusReporter();
+  }
+  return defaultRunStatusReporter;
+}
@@ -0,0 +1,212 @@
+/**
+ * Tool Profiles - Floyd Wrapper
+ *
+ * A tool profile names a subset of the tool registry, so a session, a batch
+ * task or a CI invocation can be locked to what it needs: a reviewer can't
+ * run shell commands and a CI check can be kept read-only.
+ *
+ *   reader  read, list, search and inspect only (no writes, commands or network)
+ *   editor  reader plus file edits, patches and staging (no shell, browser,
+ *           network or git history changes); tools that run commands or
+ *           other tools (verify, safe_refactor, format, custom YAML tools)
+ *           and db_query are left out too
+ *   admin   every tool
+ *
+ * Selected with `floyd --tool-profile reader`, FLOYD_TOOL_PROFILE, /toolprofile,
+ * a run profile's toolProfile or a batch task's `tools`. Profiles can be added
+ * in .floyd/tool-profiles.json (project) and ~/.floyd/tool-profiles.json
+ * (user); project entries win. The built-in names can't be redefined, so a
+ * checked-out repository can't widen reader or editor:
+ *
+ *   { "docs": { "description": "Docs only", "tools": ["file", "search"], "maxPermission": "dangerous", "exclude": ["delete_file"] } }
+ *
+ * A tool profile applies on top of a run profile's tool list: a tool must be
+ * allowed by both.
+ */
+
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import type { ToolDefinition } from '../types.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+type ToolPermission = ToolDefinition['permission'];
+
+/**
+ * A named subset of the tool registry
+ */
+export interface ToolProfile {
+  name: string;
+  description: string;
+  /** Tool names or categories included (all when unset) */
+  tools?: string[];
+  /** Tool names or categories left out, even if included above */
+  exclude?: string[];
+  /** Highest permission level included ('none' is read-only) */
+  maxPermission?: ToolPermission;
+}
+
+/**
+ * Tool profiles shipped with FLOYD
+ */
+export const BUILTIN_TOOL_PROFILES: Record<string, ToolProfile> = {
+  reader: {
+    name: 'reader',
+    description: 'Read, list, search and inspect only; no writes, commands or network',
+    maxPermission: 'none',
+    // Read-only by permission but not free of side effects: verify can run
+    // commands, todo writes the plan and progress files, the cache tools
+    // (remember included) write entries and even lookups update hit
+    // counters, and custom tools run shell commands whatever permission
+    // they declare
+    exclude: ['verify', 'todo', 'cache', 'custom'],
+  },
+  editor: {
+    name: 'editor',
+    description: 'Read and edit files; no shell, browser, network or git history changes',
+    exclude: [
+      'run', 'shell_session', 'browser', 'fetch', 'http_request', 'db_query',
+      'git_commit', 'git_merge', 'git_branch', 'github_issue', 'github_pr',
+      // verify runs commands, safe_refactor runs any tool with permission
+      // granted, format runs the project's formatter (which loads config
+      // files editor could have written) and custom tools go through the shell
+      'verify', 'safe_refactor', 'format', 'custom',
+    ],
+  },
+  admin: {
+    name: 'admin',
+    description: 'Every tool',
+  },
+};
+
+const PERMISSION_ORDER: ToolPermission[] = ['none', 'moderate', 'dangerous'];
+
+// ============================================================================
+// Loading
+// ============================================================================
+
+function stringList(value: unknown): string[] | undefined {
+  return Array.isArray(value) ? value.filter((v): v is string => typeof v === 'string') : undefined;
+}
+
+/**
+ * Check a tool profile from a config file, dropping invalid fields
+ */
+export function normalizeToolProfile(name: string, raw: Record<string, unknown>): ToolProfile {
+  const profile: ToolProfile = {
+    name,
+    description: typeof raw.description === 'string' ? raw.description : '',
+  };
+
+  const tools = stringList(raw.tools);
+  if (tools) profile.tools = tools;
+  const exclude = stringList(raw.exclude);
+  if (exclude) profile.exclude = exclude;
+  if (PERMISSION_ORDER.includes(raw.maxPermission as ToolPermission)) profile.maxPermission = raw.maxPermission as ToolPermission;
+
+  return profile;
+}
+
+/**
+ * Read a tool-profiles.json file ({} when missing or invalid)
+ */
+function readToolProfileFile(filePath: string): Record<string, ToolProfile> {
+  try {
+    const raw = fs.readJsonSync(filePath) as Record<string, Record<string, unknown>>;
+    return Object.fromEntries(
+      Object.entries(raw)
+        .filter(([, value]) => value && typeof value === 'object')
+        .map(([name, value]) => [name, normalizeToolProfile(name, value)])
+    );
+  } catch (error) {
+    if (fs.existsSync(filePath)) {
+      console.warn(`Failed to read ${filePath}: ${error}`);
+    }
+    return {};
+  }
+}
+
+/**
+ * All available tool profiles: user, then project files, then the built-ins,
+ * which file entries can't replace
+ */
+export function loadToolProfiles(projectRoot: string = process.cwd()): Record<string, ToolProfile> {
+  const profiles: Record<string, ToolProfile> = {};
+
+  for (const filePath of [
+    path.join(os.homedir(), '.floyd', 'tool-profiles.json'),
+    path.join(projectRoot, '.floyd', 'tool-profiles.json'),
+  ]) {
+    for (const [name, profile] of Object.entries(readToolProfileFile(filePath))) {
+      if (Object.hasOwn(BUILTIN_TOOL_PROFILES, name)) {
+        console.warn(`Ignoring tool profile "${name}" in ${filePath}: built-in profiles can't be redefined`);
+        continue;
+      }
+      profiles[name] = profile;
+    }
+  }
+
+  return { ...profiles, ...BUILTIN_TOOL_PROFILES };
+}
+
+/**
+ * Look up a tool profile by name
+ */
+export function getToolProfile(name: string, projectRoot?: string): ToolProfile | undefined {
+  return loadToolProfiles(projectRoot)[name];
+}
+
+// ============================================================================
+// Filtering
+// ============================================================================
+
+/**
+ * Whether a tool profile includes a tool
+ */
+export function isToolInProfile(
+  tool: Pick<ToolDefinition, 'name' | 'category' | 'permission'>,
+  profile: ToolProfile
+): boolean {
+  const matches = (list: string[]) => list.includes(tool.name) || list.includes(tool.category);
+
+  if (profile.tools && !matches(profile.tools)) {
+    return false;
+  }
+  if (profile.exclude && matches(profile.exclude)) {
+    return false;
+  }
+  if (profile.maxPermission && PERMISSION_ORDER.indexOf(tool.permission) > PERMISSION_ORDER.indexOf(profile.maxPermission)) {
+    return false;
+  }
+  return true;
+}
+
+/**
+ * Filter for the tool profile a session is locked to; an unknown name
+ * allows nothing rather than everything
+ */
+export function createToolProfileFilter(
+  name: string | undefined,
+  projectRoot?: string
+): (tool: Pick<ToolDefinition, 'name' | 'category' | 'permission'>) => boolean {
+  if (!name) {
+    return () => true;
+  }
+  const profile = getToolProfile(name, projectRoot);
+  return profile ? tool => isToolInProfile(tool, profile) : () => false;
+}
+
+/**
+ * Describe a tool profile's rules on one line
+ */
+export function formatToolProfile(profile: ToolProfile): string {
+  const parts = [
+    profile.tools && `tools ${profile.tools.join(',')}`,
+    profile.exclude && `except ${profile.exclude.join(',')}`,
+    profile.maxPermission && `up to ${profile.maxPermission === 'none' ? 'read-only' : profile.maxPermission}`,
+  ].filter(Boolean);
+  return parts.length > 0 ? parts.join(', ') : 'all tools';
+}
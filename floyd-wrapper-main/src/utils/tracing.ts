@@ -0,0 +1,331 @@
+/**
+ * Tracing - Floyd Wrapper
+ *
+ * Optional OpenTelemetry-compatible tracing for the agent loop. Each run,
+ * loop iteration, LLM call and tool execution becomes a span (with tool
+ * name, duration and error attributes), exported in batches as OTLP/HTTP
+ * JSON to any OpenTelemetry collector (Jaeger, Tempo, Honeycomb, ...).
+ *
+ * Enabled when FLOYD_OTEL=true or an OTLP endpoint is configured with the
+ * standard OTEL_EXPORTER_OTLP_ENDPOINT / OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+ * variables (OTEL_EXPORTER_OTLP_HEADERS and OTEL_SERVICE_NAME are honoured).
+ * When disabled, spans are no-ops, so call sites need no checks.
+ */
+
+import { randomBytes } from 'node:crypto';
+import { logger } from './logger.js';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export type SpanAttributeValue = string | number | boolean;
+
+export type SpanAttributes = Record<string, SpanAttributeValue | undefined>;
+
+/**
+ * OTLP span kinds used here
+ */
+export type SpanKind = 'internal' | 'client';
+
+/**
+ * Settings for the tracer
+ */
+export interface TracerOptions {
+  enabled: boolean;
+  /** Full OTLP/HTTP traces URL (…/v1/traces) */
+  url: string;
+  headers: Record<string, string>;
+  serviceName: string;
+  /** Export once this many spans are buffered */
+  batchSize?: number;
+  /** Export buffered spans after this delay (ms) */
+  flushIntervalMs?: number;
+}
+
+/**
+ * A finished span, ready for export
+ */
+export interface FinishedSpan {
+  traceId: string;
+  spanId: string;
+  parentSpanId?: string;
+  name: string;
+  kind: SpanKind;
+  startTimeUnixNano: string;
+  endTimeUnixNano: string;
+  attributes: Record<string, SpanAttributeValue>;
+  error?: string;
+}
+
+const OTLP_SPAN_KIND: Record<SpanKind, number> = { internal: 1, client: 3 };
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+/**
+ * Current time in nanoseconds since the epoch, as OTLP expects
+ */
+function nowUnixNano(): string {
+  return (BigInt(Date.now()) * 1_000_000n).toString();
+}
+
+/**
+ * Parse "key=value,key2=value2" (OTEL_EXPORTER_OTLP_HEADERS format)
+ */
+export function parseOtlpHeaders(value: string | undefined): Record<string, string> {
+  const headers: Record<string, string> = {};
+  for (const pair of (value ?? '').split(',')) {
+    const index = pair.indexOf('=');
+    if (index > 0) {
+      headers[decodeURIComponent(pair.slice(0, index).trim())] = decodeURIComponent(pair.slice(index + 1).trim());
+    }
+  }
+  return headers;
+}
+
+/**
+ * Read tracer settings from the environment
+ */
+export function getTracerOptionsFromEnv(env: NodeJS.ProcessEnv = process.env): TracerOptions {
+  const tracesEndpoint = env.OTEL_EXPORTER_OTLP_TRACES_ENDPOINT;
+  const endpoint = env.OTEL_EXPORTER_OTLP_ENDPOINT;
+  const flag = env.FLOYD_OTEL;
+
+  return {
+    enabled: flag === 'false' ? false : flag === 'true' || Boolean(tracesEndpoint || endpoint),
+    url: tracesEndpoint || `${(endpoint || 'http://localhost:4318').replace(/\/+$/, '')}/v1/traces`,
+    headers: parseOtlpHeaders(env.OTEL_EXPORTER_OTLP_HEADERS),
+    serviceName: env.OTEL_SERVICE_NAME || 'floyd',
+  };
+}
+
+/**
+ * Encode finished spans as an OTLP/HTTP JSON export request
+ */
+export function toOtlpPayload(spans: FinishedSpan[], serviceName: string): unknown {
+  const encodeValue = (value: SpanAttributeValue) => {
+    if (typeof value === 'boolean') {
+      return { boolValue: value };
+    }
+    if (typeof value === 'number') {
+      return Number.isInteger(value) ? { intValue: String(value) } : { doubleValue: value };
+    }
+    return { stringValue: value };
+  };
+
+  return {
+    resourceSpans: [{
+      resource: {
+        attributes: [{ key: 'service.name', value: { stringValue: serviceName } }],
+      },
+      scopeSpans: [{
+        scope: { name: 'floyd-wrapper' },
+        spans: spans.map(span => ({
+          traceId: span.traceId,
+          spanId: span.spanId,
+          parentSpanId: span.parentSpanId,
+          name: span.name,
+          kind: OTLP_SPAN_KIND[span.kind],
+          startTimeUnixNano: span.startTimeUnixNano,
+          endTimeUnixNano: span.endTimeUnixNano,
+          attributes: Object.entries(span.attributes).map(([key, value]) => ({ key, value: encodeValue(value) })),
+          status: span.error ? { code: 2, message: span.error } : { code: 1 },
+        })),
+      }],
+    }],
+  };
+}
+
+// ============================================================================
+// Span Class
+// ============================================================================
+
+/**
+ * A timed operation; a no-op when tracing is disabled
+ */
+export class Span {
+  readonly traceId: string;
+  readonly spanId: string;
+  private readonly startedAt = Date.now();
+  private readonly startTimeUnixNano = nowUnixNano();
+  private readonly attributes: Record<string, SpanAttributeValue> = {};
+  private error?: string;
+  private ended = false;
+
+  constructor(
+    private readonly tracer: Tracer | null,
+    readonly name: string,
+    private readonly kind: SpanKind,
+    private readonly parent?: Span
+  ) {
+    this.traceId = parent?.traceId ?? randomBytes(16).toString('hex');
+    this.spanId = randomBytes(8).toString('hex');
+  }
+
+  /**
+   * Whether this span will be exported
+   */
+  isRecording(): boolean {
+    return this.tracer !== null && !this.ended;
+  }
+
+  /**
+   * Set attributes (undefined values are skipped)
+   */
+  setAttributes(attributes: SpanAttributes): this {
+    for (const [key, value] of Object.entries(attributes)) {
+      if (value !== undefined) {
+        this.attributes[key] = value;
+      }
+    }
+    return this;
+  }
+
+  /**
+   * Mark the span as failed
+   */
+  recordError(error: unknown): this {
+    this.error = error instanceof Error ? error.message : String(error);
+    this.attributes['error'] = true;
+    if (error instanceof Error) {
+      this.attributes['error.type'] = error.name;
+    }
+    return this;
+  }
+
+  /**
+   * Finish the span and hand it to the exporter
+   */
+  end(): void {
+    if (this.ended) {
+      return;
+    }
+    this.ended = true;
+    this.attributes['duration_ms'] = Date.now() - this.startedAt;
+
+    this.tracer?.onSpanEnd({
+      traceId: this.traceId,
+      spanId: this.spanId,
+      parentSpanId: this.parent?.spanId,
+      name: this.name,
+      kind: this.kind,
+      startTimeUnixNano: this.startTimeUnixNano,
+      endTimeUnixNano: nowUnixNano(),
+      attributes: { ...this.attributes },
+      error: this.error,
+    });
+  }
+}
+
+// ============================================================================
+// Tracer Class
+// ============================================================================
+
+/**
+ * Creates spans and exports them over OTLP/HTTP
+ */
+export class Tracer {
+  private readonly options: TracerOptions;
+  private buffer: FinishedSpan[] = [];
+  private timer: NodeJS.Timeout | null = null;
+  private exporting: Promise<void> = Promise.resolve();
+
+  constructor(options: TracerOptions) {
+    this.options = options;
+  }
+
+  isEnabled(): boolean {
+    return this.options.enabled;
+  }
+
+  /**
+   * Start a span, optionally as a child of another
+   */
+  startSpan(name: string, parent?: Span, attributes: SpanAttributes = {}, kind: SpanKind = 'internal'): Span {
+    return new Span(this.options.enabled ? this : null, name, kind, parent).setAttributes(attributes);
+  }
+
+  /**
+   * Run a function inside a span, recording thrown errors
+   */
+  async withSpan<T>(
+    name: string,
+    parent: Span | undefined,
+    attributes: SpanAttributes,
+    fn: (span: Span) => Promise<T>
+  ): Promise<T> {
+    const span = this.startSpan(name, parent, attributes);
+    try {
+      return await fn(span);
+    } catch (error) {
+      span.recordError(error);
+      throw error;
+    } finally {
+      span.end();
+    }
+  }
+
+  /**
+   * Buffer a finished span (called by Span.end)
+   */
+  onSpanEnd(span: FinishedSpan): void {
+    this.buffer.push(span);
+
+    if (this.buffer.length >= (this.options.batchSize ?? 64)) {
+      void this.flush();
+    } else if (!this.timer) {
+      this.timer = setTimeout(() => void this.flush(), this.options.flushIntervalMs ?? 5000);
+      this.timer.unref();
+    }
+  }
+
+  /**
+   * Export all buffered spans
+   */
+  async flush(): Promise<void> {
+    if (this.timer) {
+      clearTimeout(this.timer);
+      this.timer = null;
+    }
+
+    const spans = this.buffer;
+    this.buffer = [];
+    if (spans.length > 0) {
+      this.exporting = this.exporting.then(() => this.export(spans));
+    }
+    await this.exporting;
+  }
+
+  /**
+   * POST spans to the collector; export failures never affect the run
+   */
+  private async export(spans: FinishedSpan[]): Promise<void> {
+    try {
+      const response = await fetch(this.options.url, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json', ...this.options.headers },
+        body: JSON.stringify(toOtlpPayload(spans, this.options.serviceName)),
+        signal: AbortSignal.timeout(10_000),
+      });
+      if (!response.ok) {
+        logger.debug('OTLP export rejected', { status: response.status, spans: spans.length });
+      }
+    } catch (error) {
+      logger.debug('OTLP export failed', { url: this.options.url, error });
+    }
+  }
+}
+
+let defaultTracer: Tracer | null = null;
+
+/**
+ * Get the shared tracer (configured from the environment)
+ */
+export function getTracer(): Tracer {
+  if (!defaultTracer) {
+    defaultTracer = new Tracer(getTracerOptionsFromEnv());
+  }
+  return defaultTracer;
+}
@@ -0,0 +1,119 @@
+/**
+ * User Config - Floyd Wrapper
+ *
+ * ~/.floyd/config.toml holds the defaults picked in the setup wizard:
+ * provider, model, base URL and theme. Environment variables still win, and
+ * the API key itself is kept in the keychain (see `floyd auth`), never here.
+ *
+ *   provider = "zai"
+ *   model = "glm-4.7"
+ *   base_url = "https://api.z.ai/api/coding/paas/v4"
+ *   theme = "crush"
+ *
+ *   # When a run finishes, fails or waits for approval
+ *   notify_bell = true         # terminal bell
+ *   notify_desktop = false     # osascript / notify-send
+ *   notify_after = 30          # seconds a run must take before finishing is announced
+ *
+ *   # GitHub token for the github_issue / github_pr tools
+ *   # (FLOYD_GITHUB_TOKEN or GITHUB_TOKEN take precedence)
+ *   github_token = "ghp_..."
+ */
+
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { parseToml } from 'floyd-agent-core/ui';
+import { parseProvider } from 'floyd-agent-core/utils';
+import type { Provider } from 'floyd-agent-core';
+
+// ============================================================================
+// Types
+// ============================================================================
+
+export interface UserConfig {
+  provider?: Provider;
+  model?: string;
+  /** OpenAI-compatible endpoint */
+  baseUrl?: string;
+  theme?: string;
+  /** Ring the terminal bell on notifications */
+  notifyBell?: boolean;
+  /** Show an OS notification as well */
+  notifyDesktop?: boolean;
+  /** Seconds a run must take before its end is notified */
+  notifyAfter?: number;
+  /** Token for the GitHub API (issues, pull requests) */
+  githubToken?: string;
+}
+
+// ============================================================================
+// Reading & Writing
+// ============================================================================
+
+/**
+ * Location of the user config (FLOYD_CONFIG overrides it)
+ */
+export function getUserConfigPath(): string {
+  return process.env.FLOYD_CONFIG || path.join(os.homedir(), '.floyd', 'config.toml');
+}
+
+/**
+ * Read the user config; null when there is none yet (first run)
+ */
+export function loadUserConfig(filePath: string = getUserConfigPath()): UserConfig | null {
+  if (!fs.existsSync(filePath)) {
+    return null;
+  }
+
+  try {
+    const raw = parseToml(fs.readFileSync(filePath, 'utf-8'));
+    const config: UserConfig = {};
+    const provider = typeof raw.provider === 'string' ? parseProvider(raw.provider) : null;
+    if (provider) config.provider = provider;
+    if (typeof raw.model === 'string' && raw.model) config.model = raw.model;
+    if (typeof raw.base_url === 'string' && /^https?:\/\//.test(raw.base_url)) config.baseUrl = raw.base_url;
+    if (typeof raw.theme === 'string' && raw.theme) config.theme = raw.theme;
+    if (typeof raw.notify_bell === 'boolean') config.notifyBell = raw.notify_bell;
+    if (typeof raw.notify_desktop === 'boolean') config.notifyDesktop = raw.notify_desktop;
+    if (typeof raw.notify_after === 'number' && raw.notify_after >= 0) config.notifyAfter = raw.notify_after;
+    if (typeof raw.github_token === 'string' && raw.github_token) config.githubToken = raw.github_token;
+    return config;
+  } catch (error) {
+    console.warn(`Failed to read ${filePath}: ${error instanceof Error ? error.message : error}`);
+    return {};
+  }
+}
+
+/**
+ * Render a user config as TOML
+ */
+export function formatUserConfig(config: UserConfig): string {
+  const lines = [
+    '# FLOYD settings (written by `floyd setup`). Environment variables override them;',
+    '# API keys live in the OS keychain - see `floyd auth`.',
+  ];
+  const entries: Array<[string, string | number | boolean | undefined]> = [
+    ['provider', config.provider],
+    ['model', config.model],
+    ['base_url', config.baseUrl],
+    ['theme', config.theme],
+    ['notify_bell', config.notifyBell],
+    ['notify_desktop', config.notifyDesktop],
+    ['notify_after', config.notifyAfter],
+    ['github_token', config.githubToken],
+  ];
+  for (const [key, value] of entries) {
+    if (value !== undefined) {
+      lines.push(`${key} = ${JSON.stringify(value)}`);
+    }
+  }
+  return `${lines.join('\n')}\n`;
+}
+
+/**
+ * Write the user config
+ */
+export async function saveUserConfig(config: UserConfig, filePath: string = getUserConfigPath()): Promise<void> {
+  await fs.outputFile(filePath, formatUserConfig(config));
+}
@@ -0,0 +1,82 @@
+/**
+ * Unit Tests: Auto Compact
+ *
+ * Tests for src/agent/auto-compact.ts
+ */
+
+import test from 'ava';
+import {
+  compactConversation,
+  estimateMessageTokens,
+  splitForCompaction,
+} from '../../../dist/agent/auto-compact.js';
+
+type Role = 'system' | 'user' | 'assistant' | 'tool';
+
+function message(role: Role, content: string, timestamp = 1) {
+  return { role, content, timestamp };
+}
+
+/**
+ * System prompt followed by `turns` user/assistant/tool/assistant exchanges
+ */
+function conversation(turns: number) {
+  const messages = [message('system', 'prompt')];
+  for (let i = 1; i <= turns; i++) {
+    messages.push(
+      message('user', `task ${i}`, i),
+      message('assistant', `calling a tool for ${i}`, i),
+      message('tool', `result ${i}`, i),
+      message('assistant', `done ${i}`, i),
+    );
+  }
+  return messages;
+}
+
+function clientReplying(text: string) {
+  return {
+    requests: [] as unknown[],
+    async *streamChat(options: { messages: unknown[] }) {
+      this.requests.push(options.messages);
+      yield { type: 'token' as const, content: text };
+      yield { type: 'done' as const, content: '' };
+    },
+  };
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: auto_compact - estimates tokens from message length', (t) => {
+  t.is(estimateMessageTokens([message('user', 'x'.repeat(10)), message('assistant', 'y'.repeat(6))]), 4);
+});
+
+test('unit: auto_compact - keeps the system prompt and starts the kept part at a user message', (t) => {
+  const split = splitForCompaction(conversation(5), 6);
+
+  t.truthy(split);
+  t.deepEqual(split!.head.map(m => m.content), ['prompt']);
+  // The last 6 messages start mid-turn, so the boundary moves back to "task 4"
+  t.is(split!.recent[0].content, 'task 4');
+  t.is(split!.older.length, 12);
+  t.is(splitForCompaction(conversation(1), 10), null);
+});
+
+test('unit: auto_compact - replaces older turns with the model summary', async (t) => {
+  const messages = conversation(5);
+  const client = clientReplying('- Goal: five tasks\n- Done: 1-3');
+  const result = await compactConversation(messages, client, { keepRecent: 8, archiveNote: 'Archived as x.' });
+
+  t.truthy(result);
+  t.is(client.requests.length, 1);
+  t.is(result!.compactedCount, 12);
+  t.deepEqual(result!.messages.map(m => m.role).slice(0, 3), ['system', 'system', 'user']);
+  t.regex(result!.messages[1].content, /12 earlier messages were compacted.*Archived as x\./);
+  t.regex(result!.messages[1].content, /Done: 1-3/);
+  t.is(result!.messages.length, 10);
+});
+
+test('unit: auto_compact - keeps the history when the model returns no summary', async (t) => {
+  t.is(await compactConversation(conversation(5), clientReplying('  '), { keepRecent: 8 }), null);
+});
@@ -0,0 +1,70 @@
+/**
+ * Unit Tests: Batch Runner
+ *
+ * Tests for src/agent/batch-runner.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { parseTaskFile, taskEnv, runBatch } from '../../../dist/agent/batch-runner.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: batch runner - parses YAML and JSON task files', (t) => {
+  const tasks = parseTaskFile([
+    '- goal: Fix the date tests',
+    '  cwd: packages/api',
+    '  model: glm-4.7',
+    '  tools: editor',
+    '  budget: { tokens: 200000, seconds: 600 }',
+    '- Update the README',
+  ].join('\n'), '/work');
+
+  t.deepEqual(tasks.map(task => [task.index, task.goal, task.cwd]), [
+    [1, 'Fix the date tests', path.resolve('/work', 'packages/api')],
+    [2, 'Update the README', path.resolve('/work')],
+  ]);
+  t.deepEqual(tasks[0].budget, { maxTokens: 200000, maxDurationMs: 600000 });
+  t.deepEqual(taskEnv(tasks[0]), {
+    FLOYD_GLM_MODEL: 'glm-4.7',
+    FLOYD_TOOL_PROFILE: 'editor',
+    FLOYD_MAX_RUN_TOKENS: '200000',
+    FLOYD_MAX_RUN_SECONDS: '600',
+  });
+
+  const json = parseTaskFile('{"tasks": [{"goal": "Bump deps", "budget": 2}]}', '/work');
+  t.deepEqual(json[0].budget, { maxCostUsd: 2 });
+
+  t.throws(() => parseTaskFile('[]', '/work'), { message: /non-empty list/ });
+  t.throws(() => parseTaskFile('- goal: x\n  dir: y', '/work'), { message: /task 1/ });
+});
+
+test('unit: batch runner - bounded parallelism, transcripts and summary', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-batch-'));
+  const tasks = parseTaskFile('- one\n- two\n- three\n', dir);
+  let running = 0;
+  let peak = 0;
+
+  const { results } = await runBatch(tasks, async (task) => {
+    running++;
+    peak = Math.max(peak, running);
+    await new Promise(resolve => setTimeout(resolve, task.index === 1 ? 40 : 10));
+    running--;
+    return { exitCode: task.goal === 'two' ? 1 : 0, output: `working\n${task.goal} finished\n` };
+  }, { parallel: 2, outDir: dir });
+
+  t.is(peak, 2);
+  t.deepEqual(results.map(result => [result.task.goal, result.status, result.lastLine]), [
+    ['one', 'done', 'one finished'],
+    ['two', 'failed', 'two finished'],
+    ['three', 'done', 'three finished'],
+  ]);
+  t.true((await fs.readFile(path.join(dir, '02-two.md'), 'utf-8')).includes('two finished'));
+  t.true((await fs.readFile(path.join(dir, 'summary.md'), 'utf-8')).includes('2/3 tasks done'));
+  t.is((await fs.readJson(path.join(dir, 'summary.json'))).length, 3);
+  await fs.remove(dir);
+});
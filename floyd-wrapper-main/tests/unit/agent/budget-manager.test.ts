@@ -0,0 +1,72 @@
+/**
+ * Budget Manager Unit Tests
+ *
+ * Tests for run limits, cost estimates and the continue/raise/abort decisions.
+ */
+
+import test from 'ava';
+import { BudgetManager, estimateCost, getModelPricing } from '../../../dist/agent/budget-manager.js';
+
+test('check: nothing is reached without limits', (t) => {
+  const budget = new BudgetManager();
+  budget.recordTurn();
+  budget.recordUsage(1_000_000, 1_000_000);
+  budget.recordToolCall();
+  t.is(budget.check(), null);
+});
+
+test('check: reports the first limit reached', (t) => {
+  const budget = new BudgetManager({ maxTokens: 1000, maxToolCalls: 2 });
+  budget.recordUsage(600, 100);
+  budget.recordToolCall();
+  t.is(budget.check(), null);
+
+  budget.recordToolCall();
+  budget.recordUsage(300, 0);
+  const exceeded = budget.check();
+  t.is(exceeded?.limit, 'maxTokens');
+  t.is(exceeded?.used, 1000);
+  t.is(exceeded?.message, 'token budget (1,000)');
+});
+
+test('check: wall-clock limit uses elapsed time', (t) => {
+  let now = 10_000;
+  const budget = new BudgetManager({ maxDurationMs: 60_000 }, 'glm-4.7', () => now);
+  now += 59_000;
+  t.is(budget.check(), null);
+  now += 1_000;
+  t.is(budget.check()?.message, 'time limit (60s)');
+});
+
+test('resolve: continue waives the limit, raise doubles it, abort stops', (t) => {
+  const budget = new BudgetManager({ maxToolCalls: 2, maxTurns: 1 });
+  budget.recordTurn();
+  budget.recordToolCall();
+  budget.recordToolCall();
+
+  const turns = budget.check()!;
+  t.is(turns.limit, 'maxTurns');
+  t.true(budget.resolve(turns, 'continue'));
+
+  const tools = budget.check()!;
+  t.is(tools.limit, 'maxToolCalls');
+  t.true(budget.resolve(tools, 'raise'));
+  t.is(budget.getBudget().maxToolCalls, 4);
+  t.is(budget.check(), null);
+
+  budget.recordToolCall();
+  budget.recordToolCall();
+  t.false(budget.resolve(budget.check()!, 'abort'));
+});
+
+test('cost: estimated from the model list prices', (t) => {
+  t.deepEqual(getModelPricing('GLM-4.5-Air'), { input: 0.2, output: 1.1 });
+  t.is(getModelPricing('glm-4-flash').output, 0);
+  t.is(estimateCost(1_000_000, 500_000, { input: 0.6, output: 2.2 }), 1.7);
+
+  const budget = new BudgetManager({ maxCostUsd: 1 }, 'glm-4.7');
+  budget.recordUsage(500_000, 200_000);
+  t.is(budget.check(), null);
+  budget.recordUsage(0, 150_000);
+  t.is(budget.check()?.message, 'cost limit ($1.00)');
+});
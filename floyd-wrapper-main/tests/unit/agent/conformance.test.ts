@@ -0,0 +1,119 @@
+/**
+ * Conformance Unit Tests
+ *
+ * Runs the floyd-agent-core conformance suites against FakeLLMClient, the
+ * OpenAI-compatible client (on a stubbed fetch) and the registered tools.
+ */
+
+import test from 'ava';
+import {
+  runLLMClientConformance,
+  runToolConformance,
+  assertConformance,
+  formatConformanceReport,
+  type ToolProvider,
+} from 'floyd-agent-core/testing';
+import { FakeLLMClient, OpenAICompatibleClient } from 'floyd-agent-core';
+import { toolRegistry } from '../../../dist/tools/tool-registry.js';
+import { registerCoreTools } from '../../../dist/tools/index.js';
+
+const BASE_URL = 'https://api.example.invalid/v1';
+
+test('conformance: FakeLLMClient', async (t) => {
+  const report = await runLLMClientConformance(
+    new FakeLLMClient(['OK', { toolCalls: [{ name: 'get_weather', input: { city: 'Paris' } }] }]),
+    { createFailingClient: () => new FakeLLMClient([{ error: 'Authentication failed' }]) },
+  );
+
+  t.notThrows(() => assertConformance(report), formatConformanceReport(report));
+  t.is(report.results.find(r => r.name.startsWith('chat: tool calls'))?.status, 'passed');
+});
+
+/**
+ * OpenAI chat completions backend: streamed or full replies, a get_weather
+ * call when tools are offered, 401 for the key "bad"
+ */
+async function fakeOpenAI(_url: string | URL | Request, init?: RequestInit): Promise<Response> {
+  const headers = new Headers(init?.headers);
+  if (headers.get('authorization') === 'Bearer bad') {
+    return Response.json({ error: { message: 'Incorrect API key provided', type: 'invalid_request_error' } }, { status: 401 });
+  }
+
+  const body = JSON.parse(String(init?.body));
+  const base = { id: 'chatcmpl-1', created: 0, model: body.model };
+  const toolCall = { id: 'call_1', type: 'function', function: { name: 'get_weather', arguments: '{"city":"Paris"}' } };
+  const usage = { prompt_tokens: 5, completion_tokens: 2, total_tokens: 7 };
+
+  if (!body.stream) {
+    const message = body.tools
+      ? { role: 'assistant', content: null, tool_calls: [toolCall] }
+      : { role: 'assistant', content: 'OK' };
+    return Response.json({
+      ...base,
+      object: 'chat.completion',
+      choices: [{ index: 0, message, finish_reason: body.tools ? 'tool_calls' : 'stop' }],
+      usage,
+    });
+  }
+
+  const deltas = body.tools
+    ? [
+      { tool_calls: [{ index: 0, id: toolCall.id, type: 'function', function: { name: 'get_weather', arguments: '' } }] },
+      { tool_calls: [{ index: 0, function: { arguments: toolCall.function.arguments } }] },
+    ]
+    : [{ role: 'assistant', content: 'O' }, { content: 'K' }];
+  const events = [
+    ...deltas.map(delta => ({ ...base, object: 'chat.completion.chunk', choices: [{ index: 0, delta, finish_reason: null }] })),
+    { ...base, object: 'chat.completion.chunk', choices: [{ index: 0, delta: {}, finish_reason: body.tools ? 'tool_calls' : 'stop' }], usage },
+  ];
+  const sse = events.map(event => `data: ${JSON.stringify(event)}\n\n`).join('') + 'data: [DONE]\n\n';
+  return new Response(sse, { headers: { 'content-type': 'text/event-stream' } });
+}
+
+test.serial('conformance: OpenAI-compatible client', async (t) => {
+  const original = globalThis.fetch;
+  globalThis.fetch = fakeOpenAI as typeof fetch;
+  try {
+    const client = new OpenAICompatibleClient({ apiKey: 'test', baseURL: BASE_URL, model: 'glm-4.7' });
+    const report = await runLLMClientConformance(client, {
+      createFailingClient: () => new OpenAICompatibleClient({ apiKey: 'bad', baseURL: BASE_URL, model: 'glm-4.7' }),
+    });
+
+    t.notThrows(() => assertConformance(report), formatConformanceReport(report));
+    t.false(report.results.some(r => r.status === 'skipped'), formatConformanceReport(report));
+  } finally {
+    globalThis.fetch = original;
+  }
+});
+
+/**
+ * The wrapper's tool registry as a conformance tool provider
+ */
+const registryProvider: ToolProvider = {
+  listTools: async () => toolRegistry.toAPIDefinitions().map(tool => ({
+    name: tool.name,
+    description: tool.description,
+    inputSchema: tool.input_schema,
+  })),
+  callTool: async (name, args) => {
+    const result = await toolRegistry.execute(name, args, { permissionGranted: true });
+    const text = result.success
+      ? (typeof result.data === 'string' ? result.data : JSON.stringify(result.data))
+      : result.error?.message ?? 'failed';
+    return { content: [{ type: 'text', text }], isError: !result.success };
+  },
+};
+
+test.serial('conformance: registered tools', async (t) => {
+  registerCoreTools();
+
+  const report = await runToolConformance(registryProvider, {
+    cases: [
+      { tool: 'read_file', input: {}, expectError: true },
+      { tool: 'read_file', input: { file_path: 'package.json' }, expectText: 'floyd-wrapper' },
+      { tool: 'read_file', input: { file_path: 'no-such-file.txt' }, expectError: true },
+    ],
+  });
+
+  t.notThrows(() => assertConformance(report), formatConformanceReport(report));
+});
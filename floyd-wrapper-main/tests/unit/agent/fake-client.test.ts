@@ -0,0 +1,84 @@
+/**
+ * Unit Tests: Fake Client
+ *
+ * Tests for the scriptable model client in src/llm/fake-client.ts, driving
+ * the engine loop without an API key.
+ */
+
+import test from 'ava';
+import { FakeClient } from '../../../dist/llm/fake-client.js';
+import { FloydAgentEngine } from '../../../dist/agent/execution-engine.js';
+import type { FloydConfig } from '../../../dist/types.js';
+
+function createTestConfig(): FloydConfig {
+  return {
+    glmApiKey: 'unused',
+    glmApiEndpoint: 'https://fake.invalid/v1',
+    glmModel: 'glm-4.7',
+    maxTokens: 100000,
+    temperature: 0.7,
+    maxTurns: 20,
+    logLevel: 'error',
+    cacheEnabled: false,
+    permissionLevel: 'auto',
+    cwd: process.cwd(),
+  } as FloydConfig;
+}
+
+async function collect(client: FakeClient): Promise<string[]> {
+  const events: string[] = [];
+  for await (const event of client.streamChat({ messages: [{ role: 'user', content: 'hi', timestamp: 0 }] })) {
+    events.push(event.type === 'token' ? event.content : event.type);
+  }
+  return events;
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: fake_client - streams text in chunks, then tool calls, then done', async (t) => {
+  const client = new FakeClient([
+    { text: 'Hello world', toolCalls: [{ name: 'grep', input: { pattern: 'x' } }] },
+  ], { chunkSize: 5 });
+
+  t.deepEqual(await collect(client), ['Hello', ' worl', 'd', 'tool_use', 'done']);
+  t.is(client.requests.length, 1);
+  t.is(client.remaining(), 0);
+  t.true(client.getTokenUsage().totalTokens > 0);
+});
+
+test('unit: fake_client - scripted errors, fallback and exhaustion', async (t) => {
+  const client = new FakeClient([{ chunks: ['partial'], error: 'overloaded' }, { throws: 'connection reset' }]);
+
+  t.deepEqual(await collect(client), ['partial', 'error']);
+  await t.throwsAsync(() => collect(client), { message: 'connection reset' });
+  await t.throwsAsync(() => collect(client), { message: /no scripted response for request 3/ });
+
+  const fallback = new FakeClient([], { fallback: 'ok' });
+  t.deepEqual(await collect(fallback), ['ok', 'done']);
+});
+
+test('unit: fake_client - drives the engine through a tool call to a final answer', async (t) => {
+  const client = new FakeClient([
+    { text: 'Checking.', toolCalls: [{ name: 'no_such_tool', input: {} }] },
+    'That tool does not exist.',
+  ]);
+  const tools: string[] = [];
+  const engine = new FloydAgentEngine(createTestConfig(), {
+    onToolComplete: (tool) => tools.push(tool),
+  }, undefined, client);
+
+  const response = await engine.execute('Use the tool');
+
+  t.is(response, 'That tool does not exist.');
+  t.deepEqual(tools, ['no_such_tool']);
+  t.is(client.requests.length, 2);
+  t.true(client.requests[1]!.some(message => message.role === 'tool' && message.content.includes('not found')));
+});
+
+test('unit: fake_client - a thrown error becomes the engine reply', async (t) => {
+  const engine = new FloydAgentEngine(createTestConfig(), {}, undefined, new FakeClient([{ throws: 'rate limited' }]));
+
+  t.regex(await engine.execute('hello'), /rate limited/);
+});
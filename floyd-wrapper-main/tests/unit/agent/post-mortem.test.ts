@@ -0,0 +1,58 @@
+/**
+ * Unit Tests: Post-Mortem
+ *
+ * Tests for src/agent/post-mortem.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  recordToolCall,
+  getPostMortemReason,
+  renderPostMortem,
+  writePostMortem,
+} from '../../../dist/agent/post-mortem.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: post_mortem - only failed, aborted or errored runs need one', (t) => {
+  const passingTests = recordToolCall('run', { command: 'npm', args: ['test'] }, { success: true });
+  const failingTests = recordToolCall('run', { command: 'npm', args: ['test'] }, { success: false, data: { stderr: '1 test failed' } });
+
+  t.true(failingTests.verification);
+  t.is(failingTests.error, '1 test failed');
+  t.is(getPostMortemReason(false, [passingTests]), null);
+  t.is(getPostMortemReason(false, [passingTests, failingTests]), 'verification-failed');
+  t.is(getPostMortemReason(false, [failingTests, passingTests]), null);
+  t.is(getPostMortemReason(true, []), 'aborted');
+  t.is(getPostMortemReason(false, [], 'timeout'), 'error');
+});
+
+test('unit: post_mortem - renders failures and appends under Post-mortems', async (t) => {
+  const edit = recordToolCall('edit_file', { file_path: 'src/a.ts' }, {
+    success: false,
+    error: { code: 'TOOL_EXECUTION_FAILED', message: 'old_string not found' },
+  });
+  const markdown = renderPostMortem({
+    reason: 'aborted',
+    task: 'Rename the helper',
+    turns: 3,
+    tools: [edit, edit],
+    changeSummary: '',
+  });
+
+  t.true(markdown.includes('edit_file `src/a.ts`: [TOOL_EXECUTION_FAILED] old_string not found'));
+  t.true(markdown.includes('Repeated failures'));
+
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-postmortem-'));
+  const scratchpad = await writePostMortem(dir, markdown);
+  await writePostMortem(dir, markdown);
+  const content = await fs.readFile(scratchpad, 'utf-8');
+  t.is(content.split('## Post-mortems').length, 2);
+  t.is(content.split('### Post-mortem: run aborted').length, 3);
+  await fs.remove(dir);
+});
@@ -0,0 +1,109 @@
+/**
+ * Provider Race Unit Tests
+ *
+ * Tests for racing two providers: the first to stream wins and the slower
+ * request is aborted.
+ */
+
+import test from 'ava';
+import { ProviderRace } from '../../../dist/llm/provider-race.js';
+import type { FloydConfig, StreamEvent } from '../../../dist/types.js';
+
+const PRIMARY = 'https://primary.invalid/v1';
+const SECONDARY = 'https://secondary.invalid/v1';
+
+function createTestConfig(): FloydConfig {
+  return {
+    glmApiKey: 'test',
+    glmApiEndpoint: PRIMARY,
+    glmModel: 'glm-4.7',
+    maxTokens: 1000,
+    temperature: 0.7,
+    maxTurns: 20,
+    logLevel: 'error',
+    cacheEnabled: false,
+    permissionLevel: 'auto',
+    raceApiEndpoint: SECONDARY,
+  } as FloydConfig;
+}
+
+/**
+ * Replace fetch: the primary streams "OK" at once, the secondary hangs
+ * until its request is aborted. Returns each request's signal and a restore.
+ */
+function stubFetch(): { signals: Map<string, AbortSignal>; restore: () => void } {
+  const original = globalThis.fetch;
+  const signals = new Map<string, AbortSignal>();
+
+  globalThis.fetch = (async (url: string | URL | Request, init?: RequestInit) => {
+    const signal = init?.signal ?? undefined;
+    const endpoint = String(url).startsWith(SECONDARY) ? SECONDARY : PRIMARY;
+    if (signal) {
+      signals.set(endpoint, signal);
+    }
+    if (signal?.aborted) {
+      throw new DOMException('aborted', 'AbortError');
+    }
+    if (endpoint === SECONDARY) {
+      return new Promise<Response>((_resolve, reject) => {
+        signal?.addEventListener('abort', () => reject(new DOMException('aborted', 'AbortError')));
+      });
+    }
+    const sse = `data: ${JSON.stringify({ choices: [{ index: 0, delta: { content: 'OK' } }] })}\n\ndata: [DONE]\n\n`;
+    return new Response(sse, { headers: { 'content-type': 'text/event-stream' } });
+  }) as typeof fetch;
+
+  return {
+    signals,
+    restore: () => {
+      globalThis.fetch = original;
+    },
+  };
+}
+
+test.serial('streamChat: the winner streams and the loser request is aborted', async (t) => {
+  const stub = stubFetch();
+  try {
+    const errors: string[] = [];
+    let completions = 0;
+    const events: StreamEvent[] = [];
+
+    for await (const event of new ProviderRace(createTestConfig()).streamChat({
+      messages: [{ role: 'user', content: 'hi', timestamp: 0 }],
+      onError: error => errors.push(error.message),
+      onComplete: () => completions++,
+    })) {
+      events.push(event);
+    }
+
+    t.deepEqual(events.map(e => e.type), ['token', 'done']);
+    t.false(stub.signals.get(PRIMARY)?.aborted);
+    t.true(stub.signals.get(SECONDARY)?.aborted);
+    t.deepEqual(errors, []);
+    t.is(completions, 1);
+  } finally {
+    stub.restore();
+  }
+});
+
+test.serial('streamChat: the caller signal aborts both requests', async (t) => {
+  const stub = stubFetch();
+  try {
+    const controller = new AbortController();
+    controller.abort();
+
+    const events: StreamEvent[] = [];
+    for await (const event of new ProviderRace(createTestConfig()).streamChat({
+      messages: [{ role: 'user', content: 'hi', timestamp: 0 }],
+      abortSignal: controller.signal,
+    })) {
+      events.push(event);
+    }
+
+    t.deepEqual(events.map(e => e.type), ['error']);
+    t.true(stub.signals.get(PRIMARY)?.aborted);
+    t.true(stub.signals.get(SECONDARY)?.aborted);
+  } finally {
+    stub.restore();
+  }
+});
@@ -0,0 +1,75 @@
+/**
+ * Unit Tests: Replay Harness
+ *
+ * Golden-run tests for the agent loop over src/agent/replay-harness.ts:
+ * recorded runs in tests/fixtures/golden are replayed through the engine
+ * without an API key and must emit the same event sequence.
+ */
+
+import test from 'ava';
+import path from 'node:path';
+import { readRecording } from '../../../dist/streaming/run-recorder.js';
+import {
+  replayRun,
+  responsesFromRecording,
+  summarizeEvents,
+  ReplayClient,
+} from '../../../dist/agent/replay-harness.js';
+import type { FloydConfig } from '../../../dist/types.js';
+
+const GOLDEN_DIR = path.join(process.cwd(), 'tests', 'fixtures', 'golden');
+
+function createTestConfig(): FloydConfig {
+  return {
+    glmApiKey: 'unused',
+    glmApiEndpoint: 'https://replay.invalid/v1',
+    glmModel: 'glm-4.7',
+    maxTokens: 100000,
+    temperature: 0.7,
+    maxTurns: 20,
+    logLevel: 'error',
+    cacheEnabled: false,
+    permissionLevel: 'auto',
+    cwd: process.cwd(),
+  } as FloydConfig;
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: replay_harness - recording splits into one response per turn', async (t) => {
+  const recording = await readRecording(path.join(GOLDEN_DIR, 'read-then-answer.jsonl'));
+  const responses = responsesFromRecording(recording);
+
+  t.is(responses.length, 2);
+  t.deepEqual(responses[0]!.toolUses, [{ name: 'read_file', input: { file_path: 'package.json' } }]);
+  t.is(responses[1]!.tokens.join(''), 'The package is `floyd-wrapper`.');
+  t.is(responses[1]!.usage?.totalTokens, 1312);
+});
+
+test('unit: replay_harness - replay client fails loudly when the loop asks for more', async (t) => {
+  const client = new ReplayClient([{ tokens: ['hi'], toolUses: [] }]);
+  const events: string[] = [];
+  for await (const event of client.streamChat({ messages: [] })) {
+    events.push(event.type);
+  }
+  t.deepEqual(events, ['token', 'done']);
+  t.is(client.remaining(), 0);
+
+  await t.throwsAsync(async () => {
+    for await (const _ of client.streamChat({ messages: [] })) {
+      // drain
+    }
+  }, { message: /no recorded response for request 2/ });
+});
+
+test('unit: replay_harness - golden run emits the recorded event sequence', async (t) => {
+  const recording = await readRecording(path.join(GOLDEN_DIR, 'read-then-answer.jsonl'));
+  const result = await replayRun(recording, createTestConfig());
+
+  t.deepEqual(summarizeEvents(result.events), summarizeEvents(recording));
+  t.is(result.response, 'The package is `floyd-wrapper`.');
+  t.is(result.requests.length, 2);
+  t.is(result.unusedResponses, 0);
+});
@@ -0,0 +1,91 @@
+/**
+ * Unit Tests: Scratchpad
+ *
+ * Tests for src/agent/scratchpad.ts
+ */
+
+import test from 'ava';
+import { recordToolCall, renderPostMortem } from '../../../dist/agent/post-mortem.js';
+import {
+  parseScratchpad,
+  searchScratchpad,
+  clusterErrors,
+  findErrorCluster,
+  errorSignature,
+  formatErrorCluster,
+} from '../../../dist/agent/scratchpad.js';
+
+function postMortem(task: string, tools: Array<[string, Record<string, unknown>, string]>, now: Date): string {
+  return renderPostMortem({
+    reason: 'aborted',
+    task,
+    turns: 2,
+    tools: tools.map(([tool, input, message]) =>
+      recordToolCall(tool, input, { success: false, error: { code: 'TOOL_EXECUTION_FAILED', message } })
+    ),
+    changeSummary: '',
+  }, now);
+}
+
+const SCRATCHPAD = [
+  '# Current Thinking & Scratchpad (FLOYD)',
+  '',
+  '## Post-mortems',
+  '',
+  postMortem('Rename the helper', [['edit_file', { file_path: 'src/a.ts' }, 'old_string "foo()" not found in src/a.ts']], new Date('2026-10-01T09:00:00Z')),
+  postMortem('Fix the build', [['run', { command: 'npm' }, 'exit code 2']], new Date('2026-10-02T09:00:00Z')),
+  postMortem('Rename again', [['edit_file', { file_path: 'src/b.ts' }, 'old_string "bar(x)" not found in src/b.ts']], new Date('2026-10-03T09:00:00Z')),
+].join('\n');
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: scratchpad - parses post-mortems with their failed tools', (t) => {
+  const entries = parseScratchpad(SCRATCHPAD);
+
+  t.is(entries.length, 3);
+  t.is(entries[0].timestamp, '2026-10-01T09:00:00.000Z');
+  t.deepEqual(entries[0].failures, [{
+    tool: 'edit_file',
+    target: 'src/a.ts',
+    code: 'TOOL_EXECUTION_FAILED',
+    message: 'old_string "foo()" not found in src/a.ts',
+  }]);
+  t.true(entries[0].suggestions.length > 0);
+});
+
+test('unit: scratchpad - search matches every word, newest first', (t) => {
+  const entries = parseScratchpad(SCRATCHPAD);
+
+  t.deepEqual(searchScratchpad(entries, 'RENAME old_string').map(entry => entry.timestamp), [
+    '2026-10-03T09:00:00.000Z',
+    '2026-10-01T09:00:00.000Z',
+  ]);
+  t.is(searchScratchpad(entries, 'rename npm').length, 0);
+});
+
+test('unit: scratchpad - clusters the same error on different inputs', (t) => {
+  t.is(errorSignature('old_string "foo()" not found in src/a.ts'), errorSignature("old_string 'x' not found in lib/b.js"));
+
+  const clusters = clusterErrors(parseScratchpad(SCRATCHPAD));
+
+  t.is(clusters.length, 2);
+  t.is(clusters[0].tool, 'edit_file');
+  t.is(clusters[0].count, 2);
+  t.deepEqual(clusters[0].targets, ['src/a.ts', 'src/b.ts']);
+  t.is(clusters[0].lastSeen, '2026-10-03T09:00:00.000Z');
+});
+
+test('unit: scratchpad - finds only the cluster of a new failure', (t) => {
+  const clusters = clusterErrors(parseScratchpad(SCRATCHPAD));
+
+  const cluster = findErrorCluster(clusters, 'edit_file', 'old_string "baz" not found in src/c.ts');
+  t.is(cluster?.count, 2);
+  t.is(findErrorCluster(clusters, 'write', 'old_string "baz" not found in src/c.ts'), null);
+  t.is(findErrorCluster(clusters, 'edit_file', 'permission denied'), null);
+
+  const note = formatErrorCluster(cluster!);
+  t.regex(note, /failed 2 time\(s\)/);
+  t.false(note.includes('exit code'));
+});
@@ -0,0 +1,228 @@
+/**
+ * Streaming Fallback Unit Tests
+ *
+ * Tests for when floyd-agent-core switches to non-streaming requests, and
+ * for reading text and tool calls from full (non-streamed) responses.
+ */
+
+import test from 'ava';
+import {
+  StreamingFallbackClient,
+  OpenAICompatibleClient,
+  AnthropicClient,
+  isStreamingFailure,
+  type LLMClient,
+  type StreamChunk,
+  type StreamingFallbackEvent,
+} from 'floyd-agent-core';
+
+/**
+ * Backend whose streamed requests all end with `streamed` and whose
+ * non-streaming requests reply "OK" (or fail when `completeWorks` is false)
+ */
+class ScriptedClient implements LLMClient {
+  chatCalls = 0;
+  completeCalls = 0;
+
+  constructor(public streamed: StreamChunk[], public completeWorks = true) {}
+
+  getModel(): string {
+    return 'scripted';
+  }
+
+  getBaseURL(): string {
+    return 'https://proxy.invalid/v1';
+  }
+
+  async *chat(): AsyncGenerator<StreamChunk, void, unknown> {
+    this.chatCalls++;
+    yield* this.streamed;
+  }
+
+  async *complete(): AsyncGenerator<StreamChunk, void, unknown> {
+    this.completeCalls++;
+    if (!this.completeWorks) {
+      yield { error: 'Network connection failed', error_code: 'NETWORK_ERROR', done: true };
+      return;
+    }
+    yield { token: 'OK' };
+    yield { done: true, stop_reason: 'stop' };
+  }
+}
+
+async function collect(client: LLMClient): Promise<StreamChunk[]> {
+  const chunks: StreamChunk[] = [];
+  for await (const chunk of client.chat([{ role: 'user', content: 'hi' }], [])) {
+    chunks.push(chunk);
+  }
+  return chunks;
+}
+
+const REPLY: StreamChunk[] = [{ token: 'hello' }, { done: true }];
+
+// ============================================================================
+// Fallback decision
+// ============================================================================
+
+test('isStreamingFailure: only errors that can mean "no streaming"', (t) => {
+  t.true(isStreamingFailure({ error: 'Empty streamed response', done: true }));
+  t.true(isStreamingFailure({ error: 'Failed to process server response', error_code: 'PARSE_ERROR' }));
+  t.true(isStreamingFailure({ error: 'An unexpected error occurred', error_code: 'UNKNOWN_ERROR' }));
+  t.false(isStreamingFailure({ error: 'API rate limit reached', error_code: 'RATE_LIMITED' }));
+  t.false(isStreamingFailure({ error: 'Network connection failed', error_code: 'NETWORK_ERROR' }));
+  t.false(isStreamingFailure({ error: 'The API service is temporarily unavailable', error_code: 'SERVER_ERROR' }));
+});
+
+test('fallback: an empty stream switches to non-streaming requests', async (t) => {
+  const inner = new ScriptedClient([]);
+  const events: StreamingFallbackEvent[] = [];
+  const client = new StreamingFallbackClient(inner, 'auto', { onEvent: event => events.push(event) });
+
+  t.deepEqual((await collect(client)).map(c => c.token ?? (c.done ? 'done' : '?')), ['OK', 'done']);
+  t.false(client.supportsStreaming());
+  t.deepEqual(events, [{ type: 'fallback', baseURL: 'https://proxy.invalid/v1', reason: 'Empty streamed response', retryAfter: 20 }]);
+
+  await collect(client);
+  t.is(inner.chatCalls, 1);
+});
+
+test('fallback: transient errors are reported, streaming stays on', async (t) => {
+  const inner = new ScriptedClient([{ error: 'API rate limit reached', error_code: 'RATE_LIMITED', done: true }]);
+  const events: StreamingFallbackEvent[] = [];
+  const errors: string[] = [];
+  const client = new StreamingFallbackClient(inner, 'auto', { onEvent: event => events.push(event) });
+
+  const chunks: StreamChunk[] = [];
+  for await (const chunk of client.chat([], [], { onError: error => errors.push(error.message) })) {
+    chunks.push(chunk);
+  }
+
+  t.is(chunks[0].error_code, 'RATE_LIMITED');
+  t.deepEqual(errors, ['API rate limit reached']);
+  t.is(client.supportsStreaming(), null);
+  t.is(inner.completeCalls, 0);
+  t.deepEqual(events, []);
+});
+
+test('fallback: not taken when non-streaming requests fail too', async (t) => {
+  const inner = new ScriptedClient([{ error: 'An unexpected error occurred', error_code: 'UNKNOWN_ERROR', done: true }], false);
+  const client = new StreamingFallbackClient(inner);
+
+  const chunks = await collect(client);
+
+  t.is(chunks[0].error, 'An unexpected error occurred');
+  t.is(client.supportsStreaming(), null);
+});
+
+test('fallback: streaming is tried again later and restored when it works', async (t) => {
+  const inner = new ScriptedClient([]);
+  const events: StreamingFallbackEvent[] = [];
+  const client = new StreamingFallbackClient(inner, 'auto', { retryAfter: 2, onEvent: event => events.push(event) });
+
+  await collect(client);
+  t.false(client.supportsStreaming());
+
+  await collect(client);
+  t.is(inner.chatCalls, 1);
+
+  // Second request after the fallback streams again
+  inner.streamed = REPLY;
+  t.is((await collect(client))[0].token, 'hello');
+  t.is(inner.chatCalls, 2);
+  t.true(client.supportsStreaming());
+  t.deepEqual(events.map(event => event.type), ['fallback', 'restored']);
+});
+
+test('fallback: mode off never streams, mode auto remembers a working stream', async (t) => {
+  const off = new ScriptedClient(REPLY);
+  await collect(new StreamingFallbackClient(off, 'off'));
+  t.is(off.chatCalls, 0);
+
+  const auto = new StreamingFallbackClient(new ScriptedClient(REPLY));
+  await collect(auto);
+  t.true(auto.supportsStreaming());
+});
+
+// ============================================================================
+// Full responses
+// ============================================================================
+
+/**
+ * Replace fetch with one answering every request with `body`
+ */
+function stubFetch(body: unknown): () => void {
+  const original = globalThis.fetch;
+  globalThis.fetch = (async () => new Response(JSON.stringify(body), {
+    status: 200,
+    headers: { 'content-type': 'application/json' },
+  })) as typeof fetch;
+  return () => {
+    globalThis.fetch = original;
+  };
+}
+
+test.serial('complete: OpenAI-compatible tool calls are parsed from the full response', async (t) => {
+  const restore = stubFetch({
+    id: 'chatcmpl-1',
+    object: 'chat.completion',
+    created: 0,
+    model: 'glm-4.7',
+    choices: [{
+      index: 0,
+      finish_reason: 'tool_calls',
+      message: {
+        role: 'assistant',
+        content: 'Reading it.',
+        tool_calls: [
+          { id: 'call_1', type: 'function', function: { name: 'read_file', arguments: '{"file_path":"a.ts"}' } },
+          { id: 'call_2', type: 'function', function: { name: 'grep', arguments: '{not json' } },
+        ],
+      },
+    }],
+    usage: { prompt_tokens: 12, completion_tokens: 7, total_tokens: 19 },
+  });
+  try {
+    const client = new OpenAICompatibleClient({ apiKey: 'test', baseURL: 'https://api.example.invalid/v1', model: 'glm-4.7' });
+    const chunks: StreamChunk[] = [];
+    for await (const chunk of client.complete([{ role: 'user', content: 'read a.ts' }], [])) {
+      chunks.push(chunk);
+    }
+
+    t.is(chunks[0].token, 'Reading it.');
+    const completed = chunks.filter(c => c.tool_use_complete).map(c => c.tool_call);
+    t.deepEqual(completed[0], { id: 'call_1', name: 'read_file', input: { file_path: 'a.ts' } });
+    t.deepEqual(completed[1]?.input, { _parseError: true, _raw: '{not json' });
+    t.deepEqual(chunks[chunks.length - 1], { done: true, stop_reason: 'tool_calls', usage: { inputTokens: 12, outputTokens: 7 } });
+  } finally {
+    restore();
+  }
+});
+
+test.serial('complete: Anthropic tool_use blocks are read from the full response', async (t) => {
+  const restore = stubFetch({
+    id: 'msg_1',
+    type: 'message',
+    role: 'assistant',
+    model: 'claude-sonnet-4-20250514',
+    content: [
+      { type: 'text', text: 'Reading it.' },
+      { type: 'tool_use', id: 'toolu_1', name: 'read_file', input: { file_path: 'a.ts' } },
+    ],
+    stop_reason: 'tool_use',
+    stop_sequence: null,
+    usage: { input_tokens: 12, output_tokens: 7 },
+  });
+  try {
+    const client = new AnthropicClient({ apiKey: 'test', baseURL: 'https://api.anthropic.invalid', model: 'claude-sonnet-4-20250514' });
+    const chunks: StreamChunk[] = [];
+    for await (const chunk of client.complete([{ role: 'user', content: 'read a.ts' }], [])) {
+      chunks.push(chunk);
+    }
+
+    t.is(chunks[0].token, 'Reading it.');
+    t.deepEqual(chunks.find(c => c.tool_use_complete)?.tool_call, { id: 'toolu_1', name: 'read_file', input: { file_path: 'a.ts' } });
+    t.deepEqual(chunks[chunks.length - 1], { done: true, stop_reason: 'tool_use', usage: { inputTokens: 12, outputTokens: 7 } });
+  } finally {
+    restore();
+  }
+});
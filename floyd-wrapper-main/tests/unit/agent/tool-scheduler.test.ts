@@ -0,0 +1,84 @@
+/**
+ * Tool Scheduler Unit Tests
+ *
+ * Tests for the concurrent tool call scheduler in floyd-agent-core.
+ */
+
+import test from 'ava';
+import path from 'node:path';
+import {
+  scheduleToolCalls,
+  describeAccess,
+  isReadOnlyTool,
+  conflicts,
+} from 'floyd-agent-core/agent';
+
+const cwd = path.resolve('/work/project');
+
+test('isReadOnlyTool: explicit list, MCP prefixes removed', (t) => {
+  t.true(isReadOnlyTool('read_file'));
+  t.true(isReadOnlyTool('filesystem__list_directory'));
+  t.true(isReadOnlyTool('git:git_diff'));
+  t.false(isReadOnlyTool('query_database'));
+  t.false(isReadOnlyTool('fetch_and_save'));
+  t.false(isReadOnlyTool('write'));
+});
+
+test('describeAccess: paths resolved against the working directory', (t) => {
+  const relative = describeAccess({ name: 'write', input: { file_path: 'src/a.ts' } }, undefined, cwd);
+  const dotted = describeAccess({ name: 'read_file', input: { file_path: './src/../src/a.ts' } }, undefined, cwd);
+  const absolute = describeAccess({ name: 'edit_file', input: { file_path: path.join(cwd, 'src/a.ts') } }, undefined, cwd);
+
+  t.deepEqual(relative.paths, [path.join(cwd, 'src', 'a.ts')]);
+  t.deepEqual(dotted.paths, relative.paths);
+  t.deepEqual(absolute.paths, relative.paths);
+  t.true(conflicts(relative, dotted));
+  t.true(conflicts(absolute, dotted));
+});
+
+test('conflicts: reads run together, writes wait for overlapping paths', (t) => {
+  const access = (name: string, input: unknown) => describeAccess({ name, input }, undefined, cwd);
+
+  t.false(conflicts(access('read_file', { file_path: 'a.ts' }), access('grep', { path: '.' })));
+  t.true(conflicts(access('write', { file_path: 'src/a.ts' }), access('list_directory', { path: 'src' })));
+  t.false(conflicts(access('write', { file_path: 'src/a.ts' }), access('write', { file_path: 'src/ab.ts' })));
+  t.true(conflicts(access('run', { command: 'npm test' }), access('read_file', { file_path: 'a.ts' })));
+  // Unknown tools without paths may touch anything
+  t.true(conflicts(access('query_database', { sql: 'select 1' }), access('read_file', { file_path: 'a.ts' })));
+});
+
+test('scheduleToolCalls: a write and a read of the same file never overlap', async (t) => {
+  const events: string[] = [];
+  const calls = [
+    { name: 'write', input: { file_path: 'src/a.ts' } },
+    { name: 'read_file', input: { file_path: './src/../src/a.ts' } },
+    { name: 'read_file', input: { file_path: 'src/b.ts' } },
+  ];
+
+  const results = await scheduleToolCalls(calls, async (call, index) => {
+    events.push(`start ${index}`);
+    await new Promise(resolve => setTimeout(resolve, 10));
+    events.push(`end ${index}`);
+    return call.name;
+  }, { cwd });
+
+  t.true(events.indexOf('end 0') < events.indexOf('start 1'));
+  // The unrelated read starts alongside the write
+  t.true(events.indexOf('start 2') < events.indexOf('end 0'));
+  t.deepEqual(results.map(r => r.status === 'fulfilled' && r.value), ['write', 'read_file', 'read_file']);
+});
+
+test('scheduleToolCalls: failures settle without blocking later calls', async (t) => {
+  const results = await scheduleToolCalls([
+    { name: 'write', input: { file_path: 'a.ts' } },
+    { name: 'write', input: { file_path: 'a.ts' } },
+  ], async (_call, index) => {
+    if (index === 0) {
+      throw new Error('boom');
+    }
+    return index;
+  }, { cwd, concurrency: 1 });
+
+  t.is(results[0].status, 'rejected');
+  t.deepEqual(results[1], { status: 'fulfilled', value: 1 });
+});
@@ -0,0 +1,19 @@
+/**
+ * Prompt Command Unit Tests
+ *
+ * Tests for the section breakdown and token estimates shown by /prompt.
+ */
+
+import test from 'ava';
+import { splitPromptSections, estimateTokens } from '../../../dist/commands/prompt-commands.js';
+
+test('splitPromptSections: one entry per ## heading after the preamble', (t) => {
+  const prompt = 'You are Floyd.\n\n## Working Context\nDirectory: /work\n\n## Execution Mode: ASK\nStep by step.';
+  t.deepEqual(splitPromptSections(prompt).map(section => section.title), ['Preamble', 'Working Context', 'Execution Mode: ASK']);
+  t.is(splitPromptSections(prompt).map(section => section.text).join('\n'), prompt);
+});
+
+test('estimateTokens: four characters per token, rounded up', (t) => {
+  t.is(estimateTokens(''), 0);
+  t.is(estimateTokens('abcde'), 2);
+});
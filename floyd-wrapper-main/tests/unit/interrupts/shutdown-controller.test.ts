@@ -0,0 +1,115 @@
+/**
+ * Shutdown Controller Unit Tests
+ *
+ * Tests for step ordering, timeouts, concurrent shutdown requests and
+ * stopping child process groups.
+ */
+
+import test from 'ava';
+import { spawn, type ChildProcess } from 'node:child_process';
+import { ShutdownController } from '../../../dist/interrupts/shutdown-controller.js';
+
+function groupAlive(child: ChildProcess): boolean {
+  try {
+    process.kill(-child.pid!, 0);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+function waitForSpawn(child: ChildProcess): Promise<void> {
+  return new Promise(resolve => child.once('spawn', () => resolve()));
+}
+
+test('ShutdownController: runs phases in order and aborts the signal first', async (t) => {
+  const controller = new ShutdownController();
+  const order: string[] = [];
+
+  controller.register('restore', 'terminal', () => { order.push('restore'); });
+  controller.register('persist', 'session', async () => { order.push('persist'); });
+  controller.register('cancel', 'stream', () => { order.push(`cancel:${controller.signal.aborted}`); });
+
+  const report = await controller.shutdown('test');
+  t.deepEqual(order, ['cancel:true', 'persist', 'restore']);
+  t.deepEqual(report, { reason: 'test', timedOut: [], failed: [], killed: 0 });
+});
+
+test('ShutdownController: concurrent requests share one run', async (t) => {
+  const controller = new ShutdownController();
+  let runs = 0;
+  controller.register('persist', 'session', async () => {
+    runs++;
+    await new Promise(resolve => setTimeout(resolve, 20));
+  });
+
+  const reports = await Promise.all([
+    controller.shutdown('quit'),
+    controller.shutdown('SIGTERM'),
+    Promise.resolve().then(() => controller.shutdown('close')),
+  ]);
+
+  t.is(runs, 1);
+  t.is(reports[0], reports[1]);
+  t.is(reports[0], reports[2]);
+  t.is(reports[0].reason, 'quit');
+});
+
+test('ShutdownController: hung and failing steps do not block later phases', async (t) => {
+  const controller = new ShutdownController({ stepTimeout: 50 });
+  let restored = false;
+
+  controller.register('cancel', 'hung', () => new Promise(() => {}));
+  controller.register('persist', 'broken', () => { throw new Error('disk full'); });
+  controller.register('restore', 'terminal', () => { restored = true; });
+
+  const report = await controller.shutdown();
+  t.true(restored);
+  t.deepEqual(report.timedOut, ['hung']);
+  t.deepEqual(report.failed, ['broken: disk full']);
+});
+
+test('ShutdownController: stops child process groups, including grandchildren', async (t) => {
+  if (process.platform === 'win32') {
+    t.pass('process groups are POSIX only');
+    return;
+  }
+
+  const controller = new ShutdownController({ killGracePeriod: 200 });
+
+  // Exits on SIGTERM; its background sleep would be orphaned without group signalling
+  const polite = spawn('sh', ['-c', 'sleep 30 & wait'], { detached: true, stdio: 'ignore' });
+  // Ignores SIGTERM, so it needs SIGKILL after the grace period
+  const stubborn = spawn('sh', ['-c', 'trap "" TERM; sleep 30 & wait; sleep 30'], { detached: true, stdio: 'ignore' });
+  await Promise.all([waitForSpawn(polite), waitForSpawn(stubborn)]);
+
+  controller.trackProcess(polite);
+  controller.trackProcess(stubborn);
+  t.is(controller.getProcessCount(), 2);
+
+  const report = await controller.shutdown();
+  t.true(report.killed >= 1);
+
+  // Give the kernel a moment to reap the group
+  await new Promise(resolve => setTimeout(resolve, 100));
+  t.false(groupAlive(polite));
+  t.false(groupAlive(stubborn));
+  t.is(controller.getProcessCount(), 0);
+});
+
+test('ShutdownController: processes started during shutdown are killed', async (t) => {
+  if (process.platform === 'win32') {
+    t.pass('process groups are POSIX only');
+    return;
+  }
+
+  const controller = new ShutdownController();
+  void controller.shutdown();
+
+  const late = spawn('sleep', ['30'], { detached: true, stdio: 'ignore' });
+  await waitForSpawn(late);
+  const exited = new Promise(resolve => late.once('exit', (_code, signal) => resolve(signal)));
+  controller.trackProcess(late);
+
+  t.is(await exited, 'SIGKILL');
+});
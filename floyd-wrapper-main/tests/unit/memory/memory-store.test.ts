@@ -0,0 +1,111 @@
+/**
+ * Memory Store Unit Tests
+ *
+ * Tests for saving, recalling and formatting long-term memories, using a
+ * word-count embedder instead of an embeddings endpoint.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  MemoryStore,
+  cosineSimilarity,
+  formatMemorySection,
+  stripMemorySection,
+} from '../../../dist/memory/memory-store.js';
+
+const VOCABULARY = ['postgres', 'database', 'migrations', 'react', 'components', 'tests', 'ava', 'parser'];
+
+/**
+ * Embeds text as counts of a few known words
+ */
+function wordEmbedder(model = 'words') {
+  return {
+    model,
+    calls: 0,
+    async embed(texts: string[]): Promise<number[][]> {
+      this.calls++;
+      return texts.map(text => {
+        const words = text.toLowerCase().split(/\W+/);
+        return VOCABULARY.map(word => words.filter(w => w === word).length);
+      });
+    },
+  };
+}
+
+async function makeDir(): Promise<string> {
+  return fs.mkdtemp(path.join(os.tmpdir(), 'floyd-memory-'));
+}
+
+test('cosineSimilarity: identical, orthogonal and zero vectors', (t) => {
+  t.is(cosineSimilarity([1, 2], [2, 4]), 1);
+  t.is(cosineSimilarity([1, 0], [0, 1]), 0);
+  t.is(cosineSimilarity([0, 0], [1, 1]), 0);
+});
+
+test('search: returns the most similar memories above the threshold', async (t) => {
+  const dir = await makeDir();
+  const store = new MemoryStore(dir, wordEmbedder());
+
+  await store.add('decision', 'Use postgres for the database, with migrations in db/');
+  await store.add('fact', 'React components live in src/components');
+  await store.add('fact', 'Tests use ava');
+
+  const matches = await store.search('add a database migrations step', 2);
+  t.is(matches.length, 1);
+  t.is(matches[0]!.entry.kind, 'decision');
+
+  // Persisted under the memory directory and readable by a new store
+  const reopened = new MemoryStore(dir, wordEmbedder());
+  t.is((await reopened.list()).length, 3);
+  t.true(await fs.pathExists(path.join(dir, 'index.json')));
+});
+
+test('add: a near-duplicate replaces the existing memory', async (t) => {
+  const store = new MemoryStore(await makeDir(), wordEmbedder());
+
+  const first = await store.add('fact', 'Tests use ava');
+  const second = await store.add('fact', 'tests: ava');
+  t.is(second.id, first.id);
+  t.is((await store.list()).length, 1);
+  t.is((await store.list())[0]!.text, 'tests: ava');
+});
+
+test('search: skips the embedder when there is nothing to recall', async (t) => {
+  const embedder = wordEmbedder();
+  const store = new MemoryStore(await makeDir(), embedder);
+
+  t.deepEqual(await store.search('anything'), []);
+  t.is(embedder.calls, 0);
+});
+
+test('search: ignores vectors from another embedding model', async (t) => {
+  const dir = await makeDir();
+  await new MemoryStore(dir, wordEmbedder('old-model')).add('fact', 'Tests use ava');
+
+  const store = new MemoryStore(dir, wordEmbedder('new-model'));
+  t.deepEqual(await store.search('which tests runner, ava?'), []);
+});
+
+test('remove: deletes by id', async (t) => {
+  const store = new MemoryStore(await makeDir(), wordEmbedder());
+  const entry = await store.add('fact', 'The parser is hand written');
+
+  t.true(await store.remove(entry.id));
+  t.false(await store.remove(entry.id));
+  t.deepEqual(await store.list(), []);
+});
+
+test('formatMemorySection: replaces the previous section when stripped', async (t) => {
+  const store = new MemoryStore(await makeDir(), wordEmbedder());
+  await store.add('file_summary', 'Parser for the tests DSL', 'src/parser.ts');
+
+  const section = formatMemorySection(await store.search('parser tests'));
+  t.regex(section, /\[file summary \(src\/parser\.ts\)\] Parser for the tests DSL/);
+
+  const prompt = `You are Floyd.\n\n${section}`;
+  t.is(stripMemorySection(prompt), 'You are Floyd.');
+  t.is(stripMemorySection('You are Floyd.'), 'You are Floyd.');
+});
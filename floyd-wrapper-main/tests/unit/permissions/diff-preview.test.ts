@@ -0,0 +1,56 @@
+/**
+ * Unit Tests: Diff Preview
+ *
+ * Tests for src/permissions/diff-preview.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { DiffPreviewer, computeProposedChange } from '../../../dist/permissions/diff-preview.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: diff_preview - computes edit_file and search_replace results', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-diff-'));
+  const file = path.join(dir, 'a.txt');
+  await fs.writeFile(file, 'one two one');
+
+  const edit = await computeProposedChange('edit_file', { file_path: file, old_string: 'two', new_string: '2' });
+  t.is(edit?.after, 'one 2 one');
+
+  const replaceAll = await computeProposedChange('search_replace', {
+    file_path: file, search_string: 'one', replace_string: '1', replace_all: true,
+  });
+  t.is(replaceAll?.after, '1 two 1');
+
+  // Ambiguous edits fail in the tool itself, so there is nothing to preview
+  t.is(await computeProposedChange('edit_file', { file_path: file, old_string: 'one', new_string: '1' }), null);
+  await fs.remove(dir);
+});
+
+test('unit: diff_preview - reject, edit and accept decisions', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-diff-'));
+  const file = path.join(dir, 'new.txt');
+  const previewer = new DiffPreviewer();
+  previewer.setEnabled(true);
+
+  previewer.setHandler(async (preview) => {
+    t.true(preview.isNewFile);
+    t.true(preview.diff.includes('+hello'));
+    return { action: 'reject' };
+  });
+  t.false((await previewer.review('write', { file_path: file, content: 'hello\n' })).approved);
+
+  previewer.setHandler(async () => ({ action: 'edit', content: 'edited\n' }));
+  const edited = await previewer.review('write', { file_path: file, content: 'hello\n' });
+  t.true(edited.approved);
+  t.is(edited.editedContent, 'edited\n');
+
+  previewer.setEnabled(false);
+  t.true((await previewer.review('write', { file_path: file, content: 'x' })).approved);
+  await fs.remove(dir);
+});
@@ -0,0 +1,77 @@
+/**
+ * Unit Tests: Dry Run
+ *
+ * Tests for src/permissions/dry-run.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  simulateToolCall,
+  formatDryRunSection,
+  stripDryRunSection,
+  isReadOnlyTool,
+  setDryRun,
+} from '../../../dist/permissions/dry-run.js';
+import { toolRegistry } from '../../../dist/tools/tool-registry.js';
+import { registerCoreTools } from '../../../dist/tools/index.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: dry_run - commands are reported, not run', async (t) => {
+  const call = await simulateToolCall('run', { command: 'rm', args: ['-rf', 'build'], cwd: 'pkg' });
+
+  t.true(call.simulated);
+  t.is(call.command, 'rm -rf build');
+  t.is(call.summary, 'Would run: rm -rf build (in pkg)');
+});
+
+test('unit: dry_run - edits come with a diff and leave the file alone', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-dry-run-'));
+  const file = path.join(dir, 'a.txt');
+  await fs.writeFile(file, 'one\ntwo\n');
+
+  const call = await simulateToolCall('edit_file', { file_path: file, old_string: 'two', new_string: '2' });
+  t.is(call.summary, `Would modify ${file}`);
+  t.deepEqual(call.targets, [file]);
+  t.true(call.diff!.includes('-two\n+2'));
+  t.is(await fs.readFile(file, 'utf-8'), 'one\ntwo\n');
+
+  const other = await simulateToolCall('delete_file', { path: file });
+  t.is(other.summary, `Would call delete_file on ${file}`);
+  t.is(other.diff, undefined);
+
+  await fs.remove(dir);
+});
+
+test.serial('unit: dry_run - tools that write without permission are simulated too', async (t) => {
+  registerCoreTools();
+  setDryRun(true);
+  try {
+    const todo = await toolRegistry.execute('todo', { goal: 'Audit', todos: [{ text: 'Read the code' }] }, { permissionGranted: true });
+    t.true(todo.success);
+    t.true((todo.data as { simulated?: boolean }).simulated);
+
+    const read = await toolRegistry.execute('read_file', { file_path: 'package.json' }, { permissionGranted: true });
+    t.true(read.success);
+    t.falsy((read.data as { simulated?: boolean }).simulated);
+  } finally {
+    setDryRun(false);
+  }
+
+  for (const name of ['todo', 'remember', 'cache_store', 'cache_store_pattern', 'cache_store_reasoning', 'verify']) {
+    t.false(isReadOnlyTool(name), name);
+  }
+});
+
+test('unit: dry_run - system prompt section can be swapped out', (t) => {
+  const prompt = `Base prompt\n\n${formatDryRunSection()}`;
+
+  t.true(prompt.includes('DRY RUN'));
+  t.is(stripDryRunSection(prompt), 'Base prompt');
+  t.is(stripDryRunSection('Base prompt'), 'Base prompt');
+});
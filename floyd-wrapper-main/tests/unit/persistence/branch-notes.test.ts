@@ -0,0 +1,64 @@
+/**
+ * Unit Tests: Branch Notes
+ *
+ * Tests for .floyd/branch.md maintenance in src/persistence/branch-notes.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  BranchNotes,
+  classifyVerification,
+  parseBranchNotes,
+  renderPrDraft,
+} from '../../../dist/persistence/branch-notes.js';
+
+const NOTES = `## Working Branch
+- Name: [e.g., feat/feature-name]
+- Base: main
+
+## PR Checklist (Non-Negotiable)
+- [ ] Lint/typecheck pass
+- [ ] Tests added
+- [ ] PR summary written
+`;
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: branch_notes - classifies test and lint commands', (t) => {
+  t.is(classifyVerification('npm test'), 'tests');
+  t.is(classifyVerification('go test ./...'), 'tests');
+  t.is(classifyVerification('npx tsc --noEmit'), 'lint');
+  t.is(classifyVerification('npm run lint'), 'lint');
+  t.is(classifyVerification('ls -la'), null);
+});
+
+test('unit: branch_notes - ticks checklist items as verifications pass and fail', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-branch-'));
+  await fs.outputFile(path.join(dir, '.floyd', 'branch.md'), NOTES);
+  const notes = new BranchNotes(dir);
+
+  await notes.recordCommand('npm test', true);
+  await notes.recordCommand('npm run lint', false);
+  let state = await notes.read();
+  t.deepEqual(state?.checklist.map(item => item.done), [false, true, false]);
+  t.is(state?.name, undefined);
+
+  await notes.recordCommand('npm test', false);
+  state = await notes.read();
+  t.false(state?.checklist[1].done);
+  await fs.remove(dir);
+});
+
+test('unit: branch_notes - PR draft carries commits and checklist', (t) => {
+  const state = { ...parseBranchNotes(NOTES), name: 'feat/diff-preview' };
+  const draft = renderPrDraft(state, ['Add diff preview'], '');
+
+  t.true(draft.startsWith('# Diff preview\n'));
+  t.true(draft.includes('- Add diff preview'));
+  t.true(draft.includes('- [ ] Tests added'));
+});
@@ -0,0 +1,82 @@
+/**
+ * Unit Tests: Conversation Branches
+ *
+ * Tests for conversation checkpoints and branching in src/persistence/session-manager.ts
+ * and the tree rendering in src/commands/conversation-commands.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { SessionManager } from '../../../dist/persistence/session-manager.js';
+import { renderBranchTree } from '../../../dist/commands/conversation-commands.js';
+
+async function createManager(): Promise<SessionManager> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-branches-'));
+  return new SessionManager(dir);
+}
+
+const MESSAGES = [
+  { role: 'system' as const, content: 'prompt', timestamp: 1000 },
+  { role: 'user' as const, content: 'add a login page', timestamp: 1000 },
+  { role: 'assistant' as const, content: 'done', timestamp: 1001 },
+];
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: conversation_branches - saves and finds checkpoints by name or id prefix', async (t) => {
+  const manager = await createManager();
+  manager.createSession('main');
+
+  const checkpoint = manager.createConversationCheckpoint('login', MESSAGES);
+  t.is(checkpoint.messageCount, 3);
+  t.deepEqual(manager.listConversationCheckpoints().map(cp => cp.name), ['login']);
+  t.deepEqual(manager.getConversationCheckpoint('login')?.messages, MESSAGES);
+  t.is(manager.getConversationCheckpoint(checkpoint.id.slice(0, 8))?.name, 'login');
+  t.is(manager.getConversationCheckpoint('missing'), null);
+  t.throws(() => manager.createConversationCheckpoint('login', MESSAGES), { message: /already exists/ });
+});
+
+test('unit: conversation_branches - forking copies the checkpoint into a child session', async (t) => {
+  const manager = await createManager();
+  const main = manager.createSession('main');
+  manager.updateSessionTags({ add: ['auth'] });
+  const checkpoint = manager.createConversationCheckpoint('login', MESSAGES);
+  await manager.saveMessage('user', 'use OAuth instead');
+
+  const branch = manager.forkSession('login', 'try sessions');
+  t.is(manager.getCurrentSessionId(), branch.id);
+  t.is(branch.metadata.parentSessionId, main.id);
+  t.is(branch.metadata.forkCheckpointId, checkpoint.id);
+  t.deepEqual(branch.metadata.tags, ['auth']);
+  t.deepEqual(manager.getHistory().map(m => m.content), ['prompt', 'add a login page', 'done']);
+
+  // The original path is untouched
+  manager.loadSession(main.id);
+  t.is(manager.getHistory().length, 1);
+  t.is(manager.getHistory()[0].content, 'use OAuth instead');
+});
+
+test('unit: conversation_branches - builds the branch tree from any session in it', async (t) => {
+  const manager = await createManager();
+  const main = manager.createSession('main');
+  manager.createConversationCheckpoint('start', MESSAGES);
+  const first = manager.forkSession('start', 'first');
+  manager.createConversationCheckpoint('deeper', MESSAGES);
+  const nested = manager.forkSession('deeper', 'nested');
+  manager.createSession('unrelated');
+
+  const tree = manager.getBranchTree(nested.id);
+  t.is(tree?.session.id, main.id);
+  t.deepEqual(tree?.children.map(child => child.session.id), [first.id]);
+  t.deepEqual(tree?.children[0].children.map(child => child.session.id), [nested.id]);
+
+  const lines = renderBranchTree(tree!, nested.id, id => manager.listConversationCheckpoints(id));
+  t.is(lines[0], `main [${main.id.slice(0, 8)}]`);
+  t.is(lines[1], '  checkpoints: start');
+  t.is(lines[2], `└─ first [${first.id.slice(0, 8)}]`);
+  t.is(lines[4], `   └─ nested [${nested.id.slice(0, 8)}] (current)`);
+});
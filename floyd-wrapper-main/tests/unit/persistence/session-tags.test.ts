@@ -0,0 +1,54 @@
+/**
+ * Unit Tests: Session Tags
+ *
+ * Tests for tagging sessions and tag filters in src/persistence/session-manager.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { SessionManager, normalizeTag } from '../../../dist/persistence/session-manager.js';
+
+async function createManager(): Promise<SessionManager> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-tags-'));
+  return new SessionManager(dir);
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: session_tags - normalizes tags and rejects invalid ones', (t) => {
+  t.is(normalizeTag('#Auth-Refactor'), 'auth-refactor');
+  t.is(normalizeTag('bug-123'), 'bug-123');
+  t.is(normalizeTag('#'), null);
+  t.is(normalizeTag('has space'), null);
+});
+
+test('unit: session_tags - adds and removes tags on the current session', async (t) => {
+  const manager = await createManager();
+  manager.createSession('tags');
+
+  t.deepEqual(manager.updateSessionTags({ add: ['#bug-123', '#auth-refactor', 'BUG-123'] }), ['auth-refactor', 'bug-123']);
+  t.deepEqual(manager.updateSessionTags({ remove: ['#bug-123'] }), ['auth-refactor']);
+  t.deepEqual(manager.getSessionTags(), ['auth-refactor']);
+  t.throws(() => manager.updateSessionTags({ add: ['not valid'] }));
+});
+
+test('unit: session_tags - filters sessions and history search by tag', async (t) => {
+  const manager = await createManager();
+  manager.createSession('untagged');
+  await manager.saveMessage('user', 'fix the login redirect');
+  manager.createSession('tagged');
+  manager.updateSessionTags({ add: ['#auth'] });
+  await manager.saveMessage('user', 'refactor the login flow');
+
+  t.deepEqual(manager.listSessions({ tag: '#auth' }).map(s => s.name), ['tagged']);
+  t.is(manager.listSessions().length, 2);
+
+  t.is(manager.searchHistory('LOGIN').length, 2);
+  const tagged = manager.searchHistory('login', { tag: 'auth' });
+  t.deepEqual(tagged.map(r => r.sessionName), ['tagged']);
+  t.is(tagged[0].content, 'refactor the login flow');
+});
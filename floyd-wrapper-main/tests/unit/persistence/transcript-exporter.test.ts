@@ -0,0 +1,81 @@
+/**
+ * Transcript Exporter Unit Tests
+ *
+ * Tests for the Markdown/HTML transcript renderer in floyd-agent-core and
+ * for exporting wrapper sessions with it.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  parseTranscriptFormat,
+  renderTranscriptMarkdown,
+  renderTranscriptHtml,
+  type TranscriptMessage,
+} from 'floyd-agent-core/utils';
+import { TranscriptExporter } from '../../../dist/persistence/transcript-exporter.js';
+
+const MESSAGES: TranscriptMessage[] = [
+  { role: 'user', content: 'List the files', timestamp: 0 },
+  {
+    role: 'assistant',
+    content: [
+      { type: 'text', text: 'Sure:\n```sh\nls <dir>\n```' },
+      { type: 'tool_use', id: 't1', name: 'list_directory', input: { path: '.' } },
+    ],
+    timestamp: 1,
+  },
+  { role: 'tool', name: 'list_directory', content: '["a.ts","b.ts"]', timestamp: 2 },
+];
+
+// ============================================================================
+// Rendering
+// ============================================================================
+
+test('parseTranscriptFormat: defaults to markdown and rejects unknown formats', (t) => {
+  t.is(parseTranscriptFormat(), 'md');
+  t.is(parseTranscriptFormat('html'), 'html');
+  t.is(parseTranscriptFormat('pdf'), null);
+});
+
+test('renderTranscriptMarkdown: includes turns, tool calls and results', (t) => {
+  const md = renderTranscriptMarkdown(MESSAGES);
+  t.true(md.includes('## User'));
+  t.true(md.includes('List the files'));
+  t.true(md.includes('### Tool call: list_directory'));
+  t.true(md.includes('"path": "."'));
+  t.true(md.includes('### Tool result: list_directory'));
+  t.true(md.includes('"a.ts"'));
+});
+
+test('renderTranscriptHtml: produces a standalone escaped document', (t) => {
+  const html = renderTranscriptHtml(MESSAGES);
+  t.true(html.startsWith('<!DOCTYPE html>'));
+  t.true(html.includes('<pre><code>ls &lt;dir&gt;</code></pre>'));
+  t.false(html.includes('<dir>'));
+  t.true(html.includes('Tool call: list_directory'));
+});
+
+// ============================================================================
+// Wrapper sessions
+// ============================================================================
+
+test('exportSession: writes wrapper messages with tool input, without the system prompt', async (t) => {
+  const outputDir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-export-'));
+
+  const filepath = await TranscriptExporter.exportSession(null, [
+    { role: 'system', content: 'You are FLOYD', timestamp: 0 },
+    { role: 'user', content: 'Read a.ts', timestamp: 1 },
+    { role: 'tool', content: 'export {}', timestamp: 2, toolName: 'read_file', toolInput: { file_path: 'a.ts' } },
+  ], { format: 'md', outputDir });
+
+  const md = await fs.readFile(filepath, 'utf-8');
+  t.true(filepath.endsWith('_Floyd_Conversation.md'));
+  t.false(md.includes('You are FLOYD'));
+  t.true(md.includes('### Tool call: read_file'));
+  t.true(md.includes('"file_path": "a.ts"'));
+  t.true(md.includes('### Tool result: read_file'));
+  await fs.remove(outputDir);
+});
@@ -0,0 +1,98 @@
+/**
+ * Workspace Snapshot Unit Tests
+ *
+ * Tests for /snapshot and /restore against a temporary git repository.
+ */
+
+import test from 'ava';
+import os from 'node:os';
+import path from 'node:path';
+import fs from 'fs-extra';
+import { execa } from 'execa';
+import {
+  WorkspaceSnapshotManager,
+  SnapshotConflictError,
+} from '../../../dist/rewind/workspace-snapshot.js';
+import { getChangeJournal } from '../../../dist/rewind/change-journal.js';
+
+async function gitRepo(): Promise<string> {
+  const dir = await fs.realpath(await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-snapshot-')));
+  await execa('git', ['init', '-q'], { cwd: dir });
+  await fs.writeFile(path.join(dir, 'app.ts'), 'v1\n');
+  await fs.writeFile(path.join(dir, 'notes.md'), 'user notes\n');
+  await execa('git', ['add', '.'], { cwd: dir });
+  await execa('git', ['-c', 'user.name=test', '-c', 'user.email=test@example.com', 'commit', '-qm', 'init'], { cwd: dir });
+  return dir;
+}
+
+/**
+ * Write a file the way a tool would, recording it in the change journal
+ */
+async function agentWrite(filePath: string, content: string): Promise<void> {
+  const existed = await fs.pathExists(filePath);
+  await fs.writeFile(filePath, content);
+  getChangeJournal().record({ path: filePath, toolName: 'write', linesBefore: existed ? 1 : null, linesAfter: 1 });
+}
+
+test.afterEach.always(() => {
+  getChangeJournal().clear();
+});
+
+test.serial('snapshot: pins the dirty tracked tree without touching it', async (t) => {
+  const dir = await gitRepo();
+  await fs.writeFile(path.join(dir, 'app.ts'), 'v2\n');
+  const manager = new WorkspaceSnapshotManager(dir);
+
+  const snapshot = await manager.create('before refactor');
+
+  t.truthy(snapshot.gitCommit);
+  const { stdout: pinned } = await execa('git', ['rev-parse', `refs/floyd/snapshots/${snapshot.id}`], { cwd: dir });
+  t.is(pinned.trim(), snapshot.gitCommit);
+  const { stdout: content } = await execa('git', ['show', `${snapshot.gitCommit}:app.ts`], { cwd: dir });
+  t.is(content, 'v2');
+  t.is(await fs.readFile(path.join(dir, 'app.ts'), 'utf-8'), 'v2\n');
+  t.is((await manager.find('before refactor'))?.id, snapshot.id);
+  await fs.remove(dir);
+});
+
+test.serial('restore: rolls back agent edits and removes files the agent created', async (t) => {
+  const dir = await gitRepo();
+  const manager = new WorkspaceSnapshotManager(dir);
+  const snapshot = await manager.create();
+
+  await agentWrite(path.join(dir, 'app.ts'), 'broken\n');
+  await agentWrite(path.join(dir, 'generated.ts'), 'new\n');
+  await fs.writeFile(path.join(dir, 'scratch.txt'), 'user file\n');
+
+  const result = await manager.restore(snapshot);
+
+  t.true(result.gitRestored);
+  t.deepEqual(result.failed, []);
+  t.is(await fs.readFile(path.join(dir, 'app.ts'), 'utf-8'), 'v1\n');
+  t.false(await fs.pathExists(path.join(dir, 'generated.ts')));
+  t.deepEqual(result.deleted, [path.join(dir, 'generated.ts')]);
+  // Untracked files the agent never touched are left alone
+  t.is(await fs.readFile(path.join(dir, 'scratch.txt'), 'utf-8'), 'user file\n');
+  await fs.remove(dir);
+});
+
+test.serial('restore: refuses while files the agent did not touch have changed', async (t) => {
+  const dir = await gitRepo();
+  const manager = new WorkspaceSnapshotManager(dir);
+  const snapshot = await manager.create();
+
+  await agentWrite(path.join(dir, 'app.ts'), 'broken\n');
+  await fs.writeFile(path.join(dir, 'notes.md'), 'edited by the user\n');
+
+  t.deepEqual(await manager.unrelatedChanges(snapshot), [path.join(dir, 'notes.md')]);
+  const error = await t.throwsAsync(manager.restore(snapshot), { instanceOf: SnapshotConflictError });
+  t.deepEqual(error?.paths, [path.join(dir, 'notes.md')]);
+  // Nothing was rolled back
+  t.is(await fs.readFile(path.join(dir, 'app.ts'), 'utf-8'), 'broken\n');
+  t.is(await fs.readFile(path.join(dir, 'notes.md'), 'utf-8'), 'edited by the user\n');
+
+  await manager.restore(snapshot, { force: true });
+  t.is(await fs.readFile(path.join(dir, 'app.ts'), 'utf-8'), 'v1\n');
+  t.is(await fs.readFile(path.join(dir, 'notes.md'), 'utf-8'), 'user notes\n');
+  await fs.remove(dir);
+});
@@ -0,0 +1,47 @@
+/**
+ * Unit Tests: Overlay Sandbox
+ *
+ * Tests for copy-on-write overlays in src/sandbox/sandbox-manager.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { SandboxManager } from '../../../dist/sandbox/index.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: overlay_sandbox - copies on write and leaves the live repo untouched', async (t) => {
+  const project = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-overlay-'));
+  const file = path.join(project, 'src', 'a.txt');
+  await fs.outputFile(file, 'original\n');
+
+  const sandbox = new SandboxManager();
+  const session = await sandbox.start(project, 'overlay');
+  t.is(session.sandboxRoot, path.join(project, '.floyd', 'sandbox', session.id));
+
+  // Unwritten files are read from the live repo
+  t.is(sandbox.resolveReadPath(file), file);
+
+  const overlayPath = sandbox.translatePath(file);
+  t.is(await fs.readFile(overlayPath, 'utf-8'), 'original\n');
+
+  await fs.writeFile(overlayPath, 'changed\n');
+  sandbox.trackChange(overlayPath, 'modified');
+
+  t.is(sandbox.resolveReadPath(file), overlayPath);
+  t.is(await fs.readFile(file, 'utf-8'), 'original\n');
+
+  const patch = await sandbox.createPatch();
+  t.true(patch.includes('--- a/src/a.txt'));
+  t.true(patch.includes('+changed'));
+
+  const result = await sandbox.commit();
+  t.true(result.success);
+  t.truthy(result.patchPath);
+  t.is(await fs.readFile(file, 'utf-8'), 'changed\n');
+  await fs.remove(project);
+});
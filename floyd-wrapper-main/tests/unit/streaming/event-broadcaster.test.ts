@@ -0,0 +1,47 @@
+/**
+ * Unit Tests: Event Broadcaster
+ *
+ * Tests for read-only session viewing over src/streaming/event-broadcaster.ts
+ */
+
+import test from 'ava';
+import { WebSocket } from 'ws';
+import { EventBroadcaster } from '../../../dist/streaming/event-broadcaster.js';
+import { formatViewerEvent } from '../../../dist/streaming/session-viewer.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: event_broadcaster - late joiners get session info and a replay of the current run', async (t) => {
+  const broadcaster = new EventBroadcaster();
+  broadcaster.start(0);
+  while (!broadcaster.getUrl()) {
+    await new Promise(resolve => setTimeout(resolve, 10));
+  }
+
+  broadcaster.emit('run_start', { message: 'old run' });
+  broadcaster.emit('run_start', { message: 'fix the build' });
+  broadcaster.emit('token', { token: 'On it' });
+
+  const socket = new WebSocket(broadcaster.getUrl()!);
+  const received: string[] = [];
+  await new Promise<void>((resolve) => {
+    socket.on('message', (raw) => {
+      received.push(JSON.parse(raw.toString()).type);
+      if (received.length === 3) resolve();
+    });
+  });
+
+  // Messages from viewers are ignored rather than closing the stream
+  socket.send('typing is not allowed');
+
+  t.deepEqual(received, ['session_info', 'run_start', 'token']);
+  socket.close();
+  await broadcaster.stop();
+});
+
+test('unit: event_broadcaster - viewer renders tokens verbatim and skips usage', (t) => {
+  t.is(formatViewerEvent({ type: 'token', timestamp: 0, data: { token: 'hello' } }), 'hello');
+  t.is(formatViewerEvent({ type: 'usage', timestamp: 0, data: {} }), null);
+});
@@ -0,0 +1,71 @@
+/**
+ * Unit Tests: Run Recorder
+ *
+ * Tests for recording runs to .floyd/runs and replaying them, over
+ * src/streaming/run-recorder.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { PassThrough } from 'node:stream';
+import { EventBroadcaster } from '../../../dist/streaming/event-broadcaster.js';
+import {
+  RunRecorder,
+  resolveRecording,
+  readRecording,
+  replayRecording,
+} from '../../../dist/streaming/run-recorder.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: run_recorder - each run is written to its own file, without a WebSocket server', async (t) => {
+  const runsDir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-runs-'));
+  const broadcaster = new EventBroadcaster();
+  const recorder = new RunRecorder(runsDir);
+  recorder.attach(broadcaster);
+
+  broadcaster.emit('token', { token: 'before any run' });
+  broadcaster.emit('run_start', { message: 'fix the build' });
+  broadcaster.emit('request', { turn: 1, messages: [{ role: 'user', content: 'fix the build' }] });
+  broadcaster.emit('tool_start', { tool: 'read_file', input: { file_path: 'a.ts' } });
+  broadcaster.emit('tool_complete', { tool: 'read_file', result: { success: true } });
+  broadcaster.emit('run_complete', { aborted: false, turns: 1 });
+  await new Promise(resolve => setTimeout(resolve, 5));
+  broadcaster.emit('run_start', { message: 'second run' });
+  await recorder.detach();
+
+  const [first, second] = recorder.getRecorded();
+  t.is(path.dirname(first!), runsDir);
+  t.deepEqual((await readRecording(first!)).map(e => e.type), ['run_start', 'request', 'tool_start', 'tool_complete', 'run_complete']);
+  t.deepEqual((await readRecording(second!)).map(e => e.type), ['run_start']);
+
+  // The latest recording is the default, and ids resolve without the extension
+  t.is(await resolveRecording(undefined, runsDir), second);
+  t.is(await resolveRecording(path.basename(first!, '.jsonl'), runsDir), first);
+  t.is(await resolveRecording('missing', runsDir), null);
+});
+
+test('unit: run_recorder - replay renders the run and skips malformed lines', async (t) => {
+  const file = path.join(await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-runs-')), 'run.jsonl');
+  await fs.writeFile(file, [
+    JSON.stringify({ type: 'run_start', timestamp: 1, data: { message: 'hi' } }),
+    JSON.stringify({ type: 'token', timestamp: 2, data: { token: 'Hello there' } }),
+    JSON.stringify({ type: 'tool_start', timestamp: 3, data: { tool: 'grep', input: {} } }),
+    '{"type": "token", "timest',
+  ].join('\n'));
+
+  const events = await readRecording(file);
+  t.is(events.length, 3);
+
+  const output = new PassThrough();
+  let text = '';
+  output.on('data', (chunk) => { text += chunk.toString(); });
+  await replayRecording(events, { output, speed: 0 });
+
+  t.true(text.includes('Hello there'));
+  t.true(text.includes('grep'));
+});
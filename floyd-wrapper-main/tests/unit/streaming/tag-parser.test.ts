@@ -0,0 +1,115 @@
+/**
+ * Stream Tag Parser Unit Tests
+ *
+ * Regression tests for <thinking> parsing across streamed chunks.
+ */
+
+import test from 'ava';
+import { StreamTagParser, type TagEvent } from '../../../dist/streaming/tag-parser.js';
+
+/**
+ * Feed chunks through a parser, then flush it
+ */
+function parse(chunks: string[], tags?: string[]): TagEvent[] {
+  const parser = new StreamTagParser(tags);
+  const events: TagEvent[] = [];
+  for (const chunk of chunks) {
+    events.push(...parser.process(chunk));
+  }
+  events.push(...parser.flush());
+  return events;
+}
+
+/**
+ * Events with adjacent text merged, as a display would show them
+ */
+function merged(events: TagEvent[]): TagEvent[] {
+  const result: TagEvent[] = [];
+  for (const event of events) {
+    const last = result[result.length - 1];
+    if (event.type === 'text' && last?.type === 'text') {
+      last.content += event.content ?? '';
+    } else {
+      result.push({ ...event });
+    }
+  }
+  return result;
+}
+
+const THOUGHT: TagEvent[] = [
+  { type: 'text', content: 'before ' },
+  { type: 'tag_open', tagName: 'thinking' },
+  { type: 'text', content: 'plan' },
+  { type: 'tag_close', tagName: 'thinking' },
+  { type: 'text', content: ' after' },
+];
+
+test('tags in one chunk', (t) => {
+  t.deepEqual(parse(['before <thinking>plan</thinking> after']), THOUGHT);
+});
+
+test('tags split across chunks at every position', (t) => {
+  const text = 'before <thinking>plan</thinking> after';
+  for (let i = 1; i < text.length; i++) {
+    t.deepEqual(merged(parse([text.slice(0, i), text.slice(i)])), THOUGHT, `split at ${i}`);
+  }
+  t.deepEqual(merged(parse([...text])), THOUGHT, 'one character per chunk');
+});
+
+test('partial tags are held back, not shown as text', (t) => {
+  const parser = new StreamTagParser();
+
+  t.deepEqual([...parser.process('answer <thin')], [{ type: 'text', content: 'answer ' }]);
+  t.deepEqual([...parser.process('king>x</think')], [
+    { type: 'tag_open', tagName: 'thinking' },
+    { type: 'text', content: 'x' },
+  ]);
+  t.deepEqual([...parser.process('ing>')], [{ type: 'tag_close', tagName: 'thinking' }]);
+});
+
+test('text that only looked like a tag is released', (t) => {
+  t.deepEqual(merged(parse(['a <', 'b'])), [{ type: 'text', content: 'a <b' }]);
+  t.deepEqual(merged(parse(['x <thinkin', 'g is fun'])), [{ type: 'text', content: 'x <thinking is fun' }]);
+});
+
+test('flush releases a partial tag at the end of the stream', (t) => {
+  const parser = new StreamTagParser();
+
+  t.deepEqual([...parser.process('1 < 2 and 3 <')], [{ type: 'text', content: '1 < 2 and 3 ' }]);
+  t.deepEqual(parser.flush(), [{ type: 'text', content: '<' }]);
+  t.deepEqual(parser.flush(), []);
+});
+
+test('flush closes a tag the stream never closed', (t) => {
+  const parser = new StreamTagParser();
+
+  t.deepEqual([...parser.process('<thinking>cut off </thin')], [
+    { type: 'tag_open', tagName: 'thinking' },
+    { type: 'text', content: 'cut off ' },
+  ]);
+  t.deepEqual(parser.flush(), [
+    { type: 'text', content: '</thin' },
+    { type: 'tag_close', tagName: 'thinking' },
+  ]);
+  // The next stream starts outside any tag
+  t.deepEqual([...parser.process('<b>')], [{ type: 'text', content: '<b>' }]);
+});
+
+test('other tags inside a tag are content', (t) => {
+  t.deepEqual(merged(parse(['<thinking>a <think>b</think> c</thinking>'], ['thinking', 'think'])), [
+    { type: 'tag_open', tagName: 'thinking' },
+    { type: 'text', content: 'a <think>b</think> c' },
+    { type: 'tag_close', tagName: 'thinking' },
+  ]);
+});
+
+test('the earliest of several tags opens first', (t) => {
+  t.deepEqual(merged(parse(['<think>x</think><thinking>y</thinking>'], ['thinking', 'think'])), [
+    { type: 'tag_open', tagName: 'think' },
+    { type: 'text', content: 'x' },
+    { type: 'tag_close', tagName: 'think' },
+    { type: 'tag_open', tagName: 'thinking' },
+    { type: 'text', content: 'y' },
+    { type: 'tag_close', tagName: 'thinking' },
+  ]);
+});
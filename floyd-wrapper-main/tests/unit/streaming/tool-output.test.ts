@@ -0,0 +1,75 @@
+/**
+ * Tool Output Streaming Unit Tests
+ *
+ * Tests for rate-limited output batches and the conversation budget.
+ */
+
+import test from 'ava';
+import {
+  ToolOutputStream,
+  budgetToolOutput,
+  formatLineCount,
+  type ToolOutputBatch,
+} from '../../../dist/streaming/tool-output.js';
+
+test('formatLineCount: groups thousands', (t) => {
+  t.is(formatLineCount(1245), '… 1,245 lines');
+  t.is(formatLineCount(1), '… 1 line');
+});
+
+test('ToolOutputStream: buffers partial lines until complete', (t) => {
+  const batches: ToolOutputBatch[] = [];
+  const stream = new ToolOutputStream('run', batch => batches.push(batch), { intervalMs: 10_000 });
+
+  stream.write('hel');
+  stream.write('lo\nwor');
+  t.is(stream.getTotalLines(), 2);
+  stream.end();
+
+  t.is(batches.length, 1);
+  t.deepEqual(batches[0].lines, ['hello', 'world']);
+  t.is(batches[0].totalLines, 2);
+  t.true(batches[0].done);
+});
+
+test('ToolOutputStream: shows at most a burst of the newest lines and counts the rest', (t) => {
+  const batches: ToolOutputBatch[] = [];
+  const stream = new ToolOutputStream('run', batch => batches.push(batch), {
+    burst: 5,
+    linesPerSecond: 0,
+    intervalMs: 10_000,
+  });
+
+  stream.write(Array.from({ length: 1000 }, (_, i) => `line ${i}`).join('\n') + '\n');
+  stream.write('more\n');
+  stream.end();
+
+  const final = batches[batches.length - 1];
+  t.deepEqual(final.lines, ['line 996', 'line 997', 'line 998', 'line 999', 'more']);
+  t.is(final.totalLines, 1001);
+  t.is(final.skippedLines, 996);
+});
+
+test('ToolOutputStream: ignores writes after end', (t) => {
+  const batches: ToolOutputBatch[] = [];
+  const stream = new ToolOutputStream('run', batch => batches.push(batch));
+  stream.end();
+  stream.write('late\n');
+  stream.end();
+  t.is(batches.length, 1);
+  t.is(batches[0].totalLines, 0);
+});
+
+test('budgetToolOutput: keeps short output unchanged', (t) => {
+  t.is(budgetToolOutput('ok\n', 100), 'ok\n');
+});
+
+test('budgetToolOutput: keeps head and tail of long output', (t) => {
+  const text = Array.from({ length: 500 }, (_, i) => `line ${i}`).join('\n');
+  const budgeted = budgetToolOutput(text, 200);
+
+  t.true(budgeted.startsWith('line 0\n'));
+  t.true(budgeted.endsWith('line 499'));
+  t.regex(budgeted, /… \[\d+ lines, [\d,]+ chars omitted\] …/);
+  t.true(budgeted.length < 300);
+});
@@ -0,0 +1,77 @@
+/**
+ * Allowed Fetch Unit Tests
+ *
+ * Tests for following redirects only within the fetch domain allowlist.
+ */
+
+import test from 'ava';
+import http from 'node:http';
+import type { AddressInfo } from 'node:net';
+import { fetchAllowed, RedirectBlockedError } from '../../../dist/tools/system/allowed-fetch.js';
+import { fetchTool } from '../../../dist/tools/system/fetch.js';
+import { getSafetyEnforcer } from '../../../dist/permissions/safety-enforcer.js';
+
+let server: http.Server;
+let port: number;
+
+test.before(async () => {
+  server = http.createServer((req, res) => {
+    const [route, target] = (req.url ?? '').split('?to=');
+    if (route === '/redirect') {
+      res.writeHead(req.method === 'POST' ? 303 : 302, { location: decodeURIComponent(target) });
+      res.end();
+      return;
+    }
+    if (route === '/loop') {
+      res.writeHead(302, { location: '/loop' });
+      res.end();
+      return;
+    }
+    res.writeHead(200, { 'content-type': 'text/plain' });
+    res.end(`${req.method} ${req.url} auth=${req.headers.authorization ?? ''}`);
+  });
+  await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve));
+  port = (server.address() as AddressInfo).port;
+});
+
+test.after.always(() => {
+  server.close();
+  getSafetyEnforcer().setAllowedDomains([]);
+});
+
+test.serial('fetchAllowed: follows redirects within the allowlist', async (t) => {
+  getSafetyEnforcer().setAllowedDomains(['127.0.0.1']);
+  const { response, url, redirected } = await fetchAllowed(`http://127.0.0.1:${port}/redirect?to=/final`, { method: 'POST', body: 'x' });
+
+  t.true(redirected);
+  t.is(url, `http://127.0.0.1:${port}/final`);
+  t.is(await response.text(), 'GET /final auth=');
+});
+
+test.serial('fetchAllowed: blocks redirects to other domains and endless redirects', async (t) => {
+  getSafetyEnforcer().setAllowedDomains(['127.0.0.1']);
+  const away = encodeURIComponent(`http://localhost:${port}/final`);
+
+  const blocked = await t.throwsAsync(fetchAllowed(`http://127.0.0.1:${port}/redirect?to=${away}`), { instanceOf: RedirectBlockedError });
+  t.is(blocked?.violation.rule, 'domain-not-allowed');
+
+  const looping = await t.throwsAsync(fetchAllowed(`http://127.0.0.1:${port}/loop`, {}, 3), { instanceOf: RedirectBlockedError });
+  t.is(looping?.violation.rule, 'too-many-redirects');
+});
+
+test.serial('fetchAllowed: credentials are not sent to another origin', async (t) => {
+  getSafetyEnforcer().setAllowedDomains([]);
+  const away = encodeURIComponent(`http://localhost:${port}/final`);
+  const { response } = await fetchAllowed(`http://127.0.0.1:${port}/redirect?to=${away}`, { headers: { authorization: 'token x' } });
+
+  t.is(await response.text(), 'GET /final auth=');
+});
+
+test.serial('fetchTool: a redirect off the allowlist is denied', async (t) => {
+  getSafetyEnforcer().setAllowedDomains(['127.0.0.1']);
+  const away = encodeURIComponent(`http://localhost:${port}/final`);
+  const result = await fetchTool.execute(fetchTool.inputSchema.parse({ url: `http://127.0.0.1:${port}/redirect?to=${away}`, format: 'raw' }));
+
+  t.false(result.success);
+  t.is(result.error?.code, 'PERMISSION_DENIED');
+});
@@ -0,0 +1,108 @@
+/**
+ * CDP Client Unit Tests
+ *
+ * Tests for driving a tab over the Chrome DevTools Protocol, against a fake
+ * debugging endpoint.
+ */
+
+import test from 'ava';
+import http from 'node:http';
+import type { AddressInfo } from 'node:net';
+import { WebSocketServer } from 'ws';
+import { CdpClient } from '../../../../dist/tools/browser/cdp-client.js';
+
+/**
+ * Fake Chrome: one page target that answers Runtime.evaluate and reports a
+ * request when it navigates
+ */
+async function startFakeChrome(): Promise<{ url: string; commands: string[]; close: () => void }> {
+  const commands: string[] = [];
+  const server = http.createServer();
+  const wss = new WebSocketServer({ server, path: '/devtools/page/1' });
+
+  await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve));
+  const { port } = server.address() as AddressInfo;
+
+  server.on('request', (req, res) => {
+    if (req.url === '/json/version') {
+      res.end(JSON.stringify({ Browser: 'Chrome/130' }));
+    } else if (req.url === '/json/list') {
+      res.end(JSON.stringify([
+        { id: 'bg', type: 'service_worker', title: 'worker', url: 'chrome-extension://x' },
+        { id: '1', type: 'page', title: 'Example', url: 'https://example.com/', webSocketDebuggerUrl: `ws://127.0.0.1:${port}/devtools/page/1` },
+      ]));
+    } else {
+      res.statusCode = 404;
+      res.end();
+    }
+  });
+
+  wss.on('connection', (socket) => {
+    socket.on('message', (data) => {
+      const { id, method, params } = JSON.parse(data.toString());
+      commands.push(method);
+      const reply = (result: unknown) => socket.send(JSON.stringify({ id, result }));
+
+      if (method === 'Runtime.evaluate') {
+        if (params.expression === 'throw') {
+          socket.send(JSON.stringify({ id, result: { result: {}, exceptionDetails: { text: 'Uncaught', exception: { description: 'Error: boom' } } } }));
+        } else {
+          reply({ result: { type: 'object', value: { url: 'https://example.com/docs', title: 'Docs', expression: params.expression } } });
+        }
+      } else if (method === 'Page.navigate') {
+        reply({ frameId: 'f1' });
+        socket.send(JSON.stringify({ method: 'Network.requestWillBeSent', params: { requestId: 'r1', type: 'Document', request: { method: 'GET', url: params.url } } }));
+        socket.send(JSON.stringify({ method: 'Network.responseReceived', params: { requestId: 'r1', response: { status: 200, mimeType: 'text/html' } } }));
+        socket.send(JSON.stringify({ method: 'Network.loadingFinished', params: { requestId: 'r1' } }));
+        socket.send(JSON.stringify({ method: 'Page.loadEventFired', params: {} }));
+      } else {
+        reply({});
+      }
+    });
+  });
+
+  return {
+    url: `http://127.0.0.1:${port}`,
+    commands,
+    close: () => { wss.close(); server.close(); },
+  };
+}
+
+test('isAvailable: false when nothing listens on the debugging port', async (t) => {
+  t.false(await new CdpClient('http://127.0.0.1:9').isAvailable());
+});
+
+test('listTabs: only page targets', async (t) => {
+  const chrome = await startFakeChrome();
+  t.teardown(chrome.close);
+
+  const client = new CdpClient(chrome.url);
+  t.true(await client.isAvailable());
+  t.deepEqual((await client.listTabs()).map(tab => tab.id), ['1']);
+});
+
+test('navigate: waits for the load and records the request in the network log', async (t) => {
+  const chrome = await startFakeChrome();
+  const client = new CdpClient(chrome.url);
+  t.teardown(() => { client.close(); chrome.close(); });
+
+  const page = await client.navigate('https://example.com/docs');
+  t.true(page.loaded);
+  t.is(page.title, 'Docs');
+  t.true(chrome.commands.includes('Network.enable'));
+
+  const log = await client.networkLog();
+  t.is(log.length, 1);
+  t.like(log[0], { method: 'GET', url: 'https://example.com/docs', status: 200, resourceType: 'Document' });
+  t.deepEqual(await client.networkLog({ filter: 'other.com' }), []);
+});
+
+test('evaluate: returns the value and surfaces exceptions', async (t) => {
+  const chrome = await startFakeChrome();
+  const client = new CdpClient(chrome.url);
+  t.teardown(() => { client.close(); chrome.close(); });
+
+  t.like(await client.evaluate('1 + 1') as object, { expression: '1 + 1' });
+  await t.throwsAsync(client.evaluate('throw'), { message: 'Error: boom' });
+  await t.throwsAsync(client.evaluate('1', 5), { message: /No tab 5/ });
+});
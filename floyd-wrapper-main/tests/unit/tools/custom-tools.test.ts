@@ -0,0 +1,84 @@
+/**
+ * Unit Tests: Custom Tools
+ *
+ * Tests for src/tools/custom/custom-core.ts and src/tools/custom/index.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  loadManifests,
+  renderCommand,
+  jsonSchemaToZod,
+} from '../../../dist/tools/custom/custom-core.js';
+import { loadCustomTools } from '../../../dist/tools/custom/index.js';
+
+const GREET = `name: greet
+description: Print a greeting
+parameters:
+  type: object
+  properties:
+    who: { type: string }
+    times: { type: integer }
+  required: [who]
+command: echo hello {{who}}
+permission: none
+`;
+
+async function toolsDir(files: Record<string, string>): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-tools-'));
+  for (const [name, content] of Object.entries(files)) {
+    await fs.outputFile(path.join(dir, name), content);
+  }
+  return dir;
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: custom_tools - loads manifests and reports invalid ones', async (t) => {
+  const dir = await toolsDir({
+    'greet.yaml': GREET,
+    'broken.yml': 'name: 1bad\ndescription: x\ncommand: true\n',
+    'notes.txt': 'ignored',
+  });
+
+  const { manifests, errors } = loadManifests(dir);
+
+  t.deepEqual(manifests.map(manifest => manifest.name), ['greet']);
+  t.is(manifests[0].permission, 'none');
+  t.is(errors.length, 1);
+  t.regex(errors[0], /broken\.yml: name/);
+  await fs.remove(dir);
+});
+
+test('unit: custom_tools - shell-quotes values in the command', (t) => {
+  t.is(renderCommand('echo {{who}}', { who: "O'Brien; rm -rf /" }), `echo 'O'\\''Brien; rm -rf /'`);
+  t.is(renderCommand('make {{ targets }} {{missing}}', { targets: ['build', 'test'] }), 'make build test ');
+});
+
+test('unit: custom_tools - validates input against the schema', (t) => {
+  const schema = jsonSchemaToZod({
+    type: 'object',
+    properties: { who: { type: 'string' }, times: { type: 'integer' } },
+    required: ['who'],
+  });
+
+  t.true(schema.safeParse({ who: 'team' }).success);
+  t.false(schema.safeParse({ times: 2 }).success);
+  t.false(schema.safeParse({ who: 'team', times: 1.5 }).success);
+});
+
+test('unit: custom_tools - runs the command template', async (t) => {
+  const dir = await toolsDir({ 'greet.yaml': GREET });
+  const [tool] = loadCustomTools(dir).tools;
+
+  const result = await tool.execute({ who: 'world' });
+
+  t.true(result.success);
+  t.is((result.data as { stdout: string }).stdout.trim(), 'hello world');
+  await fs.remove(dir);
+});
@@ -0,0 +1,118 @@
+/**
+ * Database Tool Unit Tests
+ *
+ * Tests for connection config, SQLite queries and result formatting.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import Database from 'better-sqlite3';
+import {
+  parseDatabaseConfig,
+  runSqliteQuery,
+  runPostgresQuery,
+  splitSqlStatements,
+  postgresConnection,
+  parseCsv,
+  formatMarkdownTable,
+} from '../../../dist/tools/database/db-core.js';
+
+async function createDatabase(): Promise<{ dir: string; file: string }> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-db-'));
+  const file = path.join(dir, 'app.db');
+  const db = new Database(file);
+  db.exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, note TEXT); INSERT INTO users (name, note) VALUES ('ada', NULL), ('grace', 'a|b'), ('linus', 'x')");
+  db.close();
+  return { dir, file };
+}
+
+test('parseDatabaseConfig: connections from dsn and dsn_env', (t) => {
+  const source = [
+    '[databases.app]',
+    'dsn = "sqlite:data/app.db"',
+    '',
+    '[databases.staging]',
+    'dsn_env = "STAGING_URL"',
+    'max_rows = 20',
+    'read_only = false',
+    '',
+    '[databases.unset]',
+    'dsn_env = "NOT_SET"',
+  ].join('\n');
+
+  const connections = parseDatabaseConfig(source, '/repo', { STAGING_URL: 'postgres://u:p@db/app' });
+
+  t.deepEqual(Object.keys(connections), ['app', 'staging']);
+  t.deepEqual(connections.app, { name: 'app', driver: 'sqlite', dsn: path.resolve('/repo', 'data/app.db'), readOnly: true, maxRows: 100 });
+  t.like(connections.staging, { driver: 'postgres', dsn: 'postgres://u:p@db/app', readOnly: false, maxRows: 20 });
+});
+
+test('parseDatabaseConfig: rejects invalid entries', (t) => {
+  t.throws(() => parseDatabaseConfig('[databases.app]\nmax_rows = 5', '/repo'), { message: /needs a dsn/ });
+  t.throws(() => parseDatabaseConfig('[databases.app]\ndsn = "mysql://db"', '/repo'), { message: /unsupported DSN/ });
+  t.deepEqual(parseDatabaseConfig('theme = "crush"', '/repo'), {});
+});
+
+test('runSqliteQuery: rows capped at maxRows', async (t) => {
+  const { dir, file } = await createDatabase();
+  const connection = { name: 'app', driver: 'sqlite' as const, dsn: file, readOnly: true, maxRows: 100 };
+
+  const result = runSqliteQuery(connection, 'SELECT id, name FROM users ORDER BY id', 2);
+  t.deepEqual(result, { columns: ['id', 'name'], rows: [[1, 'ada'], [2, 'grace']], truncated: true });
+
+  await fs.remove(dir);
+});
+
+test('runSqliteQuery: read-only unless configured otherwise', async (t) => {
+  const { dir, file } = await createDatabase();
+  const connection = { name: 'app', driver: 'sqlite' as const, dsn: file, readOnly: true, maxRows: 100 };
+
+  t.throws(() => runSqliteQuery(connection, "DELETE FROM users WHERE name = 'ada'", 10), { message: /readonly/i });
+  t.is(runSqliteQuery({ ...connection, readOnly: false }, "DELETE FROM users WHERE name = 'ada'", 10).changes, 1);
+
+  await fs.remove(dir);
+});
+
+test('splitSqlStatements: semicolons in strings, comments and dollar quotes', (t) => {
+  t.deepEqual(splitSqlStatements('SELECT 1;'), ['SELECT 1']);
+  t.deepEqual(splitSqlStatements('BEGIN READ WRITE; DELETE FROM users'), ['BEGIN READ WRITE', 'DELETE FROM users']);
+  t.deepEqual(splitSqlStatements("SELECT ';' AS x -- ; note"), ["SELECT ';' AS x -- ; note"]);
+  t.deepEqual(splitSqlStatements('SELECT $$a;b$$, $fn$ c; $fn$ /* d; /* e; */ f; */'), ['SELECT $$a;b$$, $fn$ c; $fn$ /* d; /* e; */ f; */']);
+  t.deepEqual(splitSqlStatements("SELECT E'it\\'s; one'"), ["SELECT E'it\\'s; one'"]);
+  t.deepEqual(splitSqlStatements('-- nothing; here'), []);
+});
+
+test('runPostgresQuery: rejects several statements before running psql', async (t) => {
+  const connection = { name: 'pg', driver: 'postgres' as const, dsn: 'postgres://localhost/app', readOnly: true, maxRows: 10 };
+
+  await t.throwsAsync(runPostgresQuery(connection, 'SET default_transaction_read_only = off; DELETE FROM users', 10), {
+    message: /one SQL statement/,
+  });
+  await t.throwsAsync(runPostgresQuery(connection, '\\! id', 10), { message: /meta-commands/ });
+});
+
+test('postgresConnection: password moves to PGPASSWORD', (t) => {
+  t.deepEqual(postgresConnection('postgres://bob:s%40cret@db:5432/app?sslmode=require'), {
+    dbname: 'postgres://bob@db:5432/app?sslmode=require',
+    env: { PGPASSWORD: 's@cret' },
+  });
+  t.deepEqual(postgresConnection('postgresql://db/app?password=pw'), { dbname: 'postgresql://db/app', env: { PGPASSWORD: 'pw' } });
+});
+
+test('parseCsv: quoted fields', (t) => {
+  t.deepEqual(parseCsv('id,note\n1,"a, ""b"""\n2,"multi\nline"\n'), [
+    ['id', 'note'],
+    ['1', 'a, "b"'],
+    ['2', 'multi\nline'],
+  ]);
+});
+
+test('formatMarkdownTable: escapes cells', (t) => {
+  t.is(
+    formatMarkdownTable(['id', 'note'], [[1, null], [2, 'a|b\nc']]),
+    '| id | note |\n|---|---|\n| 1 | NULL |\n| 2 | a\\|b c |'
+  );
+  t.is(formatMarkdownTable([], []), '');
+});
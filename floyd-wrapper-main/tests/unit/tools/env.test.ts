@@ -0,0 +1,80 @@
+/**
+ * Environment Tool Unit Tests
+ *
+ * Tests for secret masking and variable requests in the env tool.
+ */
+
+import test from 'ava';
+import {
+  isSensitiveEnvName,
+  describeEnvVar,
+  listEnv,
+  envTool,
+  setEnvValueHandler,
+} from '../../../dist/tools/system/env.js';
+
+test('isSensitiveEnvName: credentials by name', (t) => {
+  for (const name of ['GITHUB_TOKEN', 'AWS_SECRET_ACCESS_KEY', 'DB_PASSWORD', 'STRIPE_API_KEY', 'SENTRY_DSN', 'NPM_AUTH']) {
+    t.true(isSensitiveEnvName(name), name);
+  }
+  for (const name of ['PATH', 'HOME', 'PWD', 'OLDPWD', 'NODE_ENV', 'KEYBOARD_LAYOUT']) {
+    t.false(isSensitiveEnvName(name), name);
+  }
+});
+
+test('describeEnvVar: masks secrets by name and by value', (t) => {
+  t.deepEqual(describeEnvVar('GITHUB_TOKEN', 'abc123'), { name: 'GITHUB_TOKEN', value: '[MASKED]', masked: true, length: 6 });
+  t.true(describeEnvVar('OPENAI', `sk-proj-${'a1B2c3D4'.repeat(5)}`).masked);
+  t.deepEqual(describeEnvVar('NODE_ENV', 'test'), { name: 'NODE_ENV', value: 'test', masked: false, length: 4 });
+});
+
+test('describeEnvVar: masks only the password of a URL', (t) => {
+  const entry = describeEnvVar('DATABASE_URL', 'postgres://app:hunter2@db:5432/app');
+
+  t.is(entry.value, 'postgres://app:[MASKED]@db:5432/app');
+  t.true(entry.masked);
+});
+
+test('listEnv: sorted and filtered', (t) => {
+  const env = { B_VAR: '2', A_VAR: '1', OTHER: '3', UNSET: undefined };
+
+  t.deepEqual(listEnv(env).map(entry => entry.name), ['A_VAR', 'B_VAR', 'OTHER']);
+  t.deepEqual(listEnv(env, { prefix: 'B_' }).map(entry => entry.name), ['B_VAR']);
+  t.deepEqual(listEnv(env, { names: ['OTHER', 'MISSING'] }).map(entry => entry.name), ['OTHER']);
+});
+
+test.serial('envTool: list reports missing names', async (t) => {
+  const result = await envTool.execute({ action: 'list', names: ['PATH', 'FLOYD_TEST_MISSING_VAR'] });
+
+  t.true(result.success);
+  t.deepEqual((result.data as { missing: string[] }).missing, ['FLOYD_TEST_MISSING_VAR']);
+});
+
+test.serial('envTool: request sets values without returning them', async (t) => {
+  const asked: Array<[string, boolean]> = [];
+  setEnvValueHandler(async (name: string, _reason: string | undefined, sensitive: boolean) => {
+    asked.push([name, sensitive]);
+    return name === 'FLOYD_TEST_API_KEY' ? 'value-from-user' : null;
+  });
+
+  const result = await envTool.execute({
+    action: 'request',
+    names: ['FLOYD_TEST_API_KEY', 'FLOYD_TEST_REGION', 'PATH'],
+    reason: 'Needed for the integration tests',
+  });
+  setEnvValueHandler(null);
+
+  t.deepEqual(asked, [['FLOYD_TEST_API_KEY', true], ['FLOYD_TEST_REGION', false]]);
+  t.deepEqual(result.data, { set: ['FLOYD_TEST_API_KEY'], skipped: ['FLOYD_TEST_REGION'], alreadySet: ['PATH'] });
+  t.false(JSON.stringify(result).includes('value-from-user'));
+  t.is(process.env.FLOYD_TEST_API_KEY, 'value-from-user');
+
+  delete process.env.FLOYD_TEST_API_KEY;
+});
+
+test.serial('envTool: request fails without an interactive user', async (t) => {
+  const result = await envTool.execute({ action: 'request', names: ['FLOYD_TEST_MISSING_VAR'] });
+
+  t.false(result.success);
+  t.is(result.error?.code, 'USER_INPUT_UNAVAILABLE');
+});
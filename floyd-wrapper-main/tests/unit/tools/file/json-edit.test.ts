@@ -0,0 +1,125 @@
+/**
+ * Unit Tests: JSON Edit Tool
+ *
+ * Tests for src/tools/file/structured-edit.ts and src/tools/file/json-edit.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  parsePath,
+  applyOperation,
+  editJsonText,
+  editYamlText,
+} from '../../../../dist/tools/file/structured-edit.js';
+import { jsonEditTool } from '../../../../dist/tools/file/json-edit.js';
+
+const PIPELINE = `# CI pipeline
+name: ci # shown in the UI
+on:
+  push:
+    branches: [main]
+steps:
+- run: npm ci
+  name: install
+- run: npm test
+`;
+
+// ============================================================================
+// Paths & Operations
+// ============================================================================
+
+test('unit: json_edit - JSON Pointer and JSONPath paths', (t) => {
+  t.deepEqual(parsePath('/scripts/a~1b/~0c/-'), ['scripts', 'a/b', '~c', '-']);
+  t.deepEqual(parsePath("$.scripts['lint.fix'].args[0]"), ['scripts', 'lint.fix', 'args', '0']);
+  t.deepEqual(parsePath('$'), []);
+  t.throws(() => parsePath('$..name'), { message: /Unsupported JSONPath/ });
+  t.throws(() => parsePath('scripts.test'), { message: /Invalid path/ });
+});
+
+test('unit: json_edit - set, delete and append on a copy', (t) => {
+  const document = { scripts: { test: 'ava' }, files: ['dist'] };
+
+  t.deepEqual(applyOperation(document, { op: 'set', path: '/config/port', value: 3000 }), { ...document, config: { port: 3000 } });
+  t.deepEqual(applyOperation(document, { op: 'delete', path: '/scripts/test' }), { scripts: {}, files: ['dist'] });
+  t.deepEqual(applyOperation(document, { op: 'append', path: '/files', value: 'src' }), { ...document, files: ['dist', 'src'] });
+  t.deepEqual(document, { scripts: { test: 'ava' }, files: ['dist'] });
+
+  t.throws(() => applyOperation(document, { op: 'delete', path: '/nope' }), { message: /does not exist/ });
+  t.throws(() => applyOperation(document, { op: 'append', path: '/scripts' }), { message: /not an array/ });
+  t.throws(() => applyOperation(document, { op: 'set', path: '/files/5', value: 1 }), { message: /out of range/ });
+});
+
+// ============================================================================
+// Formatting
+// ============================================================================
+
+test('unit: json_edit - JSON keeps indentation, line endings and key order', (t) => {
+  const text = '{\r\n    "name": "app",\r\n    "scripts": {\r\n        "test": "ava"\r\n    }\r\n}\r\n';
+
+  t.is(
+    editJsonText(text, [{ op: 'set', path: '$.scripts.lint', value: 'eslint .' }]),
+    '{\r\n    "name": "app",\r\n    "scripts": {\r\n        "test": "ava",\r\n        "lint": "eslint ."\r\n    }\r\n}\r\n'
+  );
+  t.is(editJsonText('{"a":1,"b":2}', [{ op: 'delete', path: '/a' }]), '{"b":2}');
+});
+
+test('unit: json_edit - YAML edits keep comments and layout', (t) => {
+  const result = editYamlText(PIPELINE, [
+    { op: 'set', path: '/steps/0/name', value: 'Install' },
+    { op: 'append', path: '/steps', value: { run: 'npm run build' } },
+    { op: 'set', path: '/env/NODE_ENV', value: 'test' },
+    { op: 'set', path: '/name', value: 'build' },
+  ]);
+
+  t.true(result.formatPreserved);
+  t.is(result.text, `# CI pipeline
+name: build # shown in the UI
+on:
+  push:
+    branches: [main]
+steps:
+- run: npm ci
+  name: Install
+- run: npm test
+- run: npm run build
+env:
+  NODE_ENV: test
+`);
+});
+
+test('unit: json_edit - YAML delete hands a sequence dash to the next key', (t) => {
+  const result = editYamlText(PIPELINE, [{ op: 'delete', path: '/steps/0/run' }]);
+
+  t.true(result.formatPreserved);
+  t.true(result.text.includes('steps:\n- name: install\n- run: npm test\n'));
+});
+
+test('unit: json_edit - YAML flow style falls back to a full dump', (t) => {
+  const result = editYamlText(PIPELINE, [{ op: 'append', path: '/on/push/branches', value: 'release' }]);
+
+  t.false(result.formatPreserved);
+  t.false(result.text.includes('# CI pipeline'));
+  t.true(result.text.includes('- release'));
+});
+
+// ============================================================================
+// Tool
+// ============================================================================
+
+test('unit: json_edit - tool edits the file', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-json-edit-'));
+  const file = path.join(dir, 'package.json');
+  await fs.writeFile(file, '{\n  "name": "app"\n}\n');
+
+  const result = await jsonEditTool.execute({ file_path: file, operations: [{ op: 'set', path: '/scripts/test', value: 'ava' }] });
+  t.true(result.success);
+  t.is(await fs.readFile(file, 'utf-8'), '{\n  "name": "app",\n  "scripts": {\n    "test": "ava"\n  }\n}\n');
+
+  const failed = await jsonEditTool.execute({ file_path: path.join(dir, 'notes.txt'), operations: [{ op: 'delete', path: '/a' }] });
+  t.is(failed.error?.code, 'FILE_NOT_FOUND');
+
+  await fs.remove(dir);
+});
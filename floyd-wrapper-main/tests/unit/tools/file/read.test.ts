@@ -258,3 +258,60 @@ test('unit: read_file - handles file with special characters', async (t) => {
     t.true(result.data.content.includes('\t'), 'Should preserve tabs');
   }
 });
+
+test('unit: read_file - pages long files with a footer', async (t) => {
+  const longFilePath = path.join(testDir, 'long.txt');
+  const lines = Array.from({ length: 5000 }, (_, i) => `Line ${i + 1}`);
+  await fs.writeFile(longFilePath, lines.join('\n'), 'utf-8');
+
+  const result = await readFileTool.execute({ file_path: longFilePath });
+
+  t.true(result.success, 'Should successfully read long file');
+
+  if (result.success) {
+    t.true(result.data.content.startsWith('Line 1\n'), 'Should start at the first line');
+    t.true(result.data.content.includes('Line 2000\n'), 'Should include the default page');
+    t.false(result.data.content.includes('Line 2001'), 'Should stop after the default page');
+    t.true(result.data.content.endsWith('[Showing lines 1-2000 of 5,000. Use offset 2000 to read more.]'), 'Should end with a footer');
+    t.is(result.data.lineCount, 5000, 'Line count should cover the whole file');
+    t.true(result.data.truncated, 'Should be marked truncated');
+  }
+});
+
+test('unit: read_file - caps a page by bytes', async (t) => {
+  const wideFilePath = path.join(testDir, 'wide.txt');
+  await fs.writeFile(wideFilePath, Array.from({ length: 100 }, () => 'x'.repeat(10_000)).join('\n'), 'utf-8');
+
+  const saved = process.env.FLOYD_READ_MAX_BYTES;
+  process.env.FLOYD_READ_MAX_BYTES = '50000';
+  const result = await readFileTool.execute({ file_path: wideFilePath, offset: 10, limit: 50 });
+  if (saved === undefined) {
+    delete process.env.FLOYD_READ_MAX_BYTES;
+  } else {
+    process.env.FLOYD_READ_MAX_BYTES = saved;
+  }
+
+  t.true(result.success, 'Should successfully read wide file');
+
+  if (result.success) {
+    t.is(result.data.startLine, 11, 'Should start at the offset');
+    t.is(result.data.endLine, 14, 'Should stop at the byte budget');
+    t.true(result.data.content.endsWith('[Showing lines 11-14 of 100. Use offset 14 to read more.]'), 'Should end with a footer');
+  }
+});
+
+test('unit: read_file - summarizes binary files', async (t) => {
+  const binaryFilePath = path.join(testDir, 'image.png');
+  await fs.writeFile(binaryFilePath, Buffer.from([0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d]));
+
+  const result = await readFileTool.execute({ file_path: binaryFilePath });
+
+  t.true(result.success, 'Should successfully read binary file');
+
+  if (result.success) {
+    t.true(result.data.binary, 'Should be marked binary');
+    t.is(result.data.size, 12, 'Should report the file size');
+    t.true(result.data.content.startsWith('[Binary file: PNG image, 12 Bytes'), 'Should describe the file');
+    t.false(result.data.content.includes('PNG\r\n'), 'Should not include raw bytes');
+  }
+});
@@ -73,7 +73,8 @@ test.serial('write:execute - overwrites existing file', async (t) => {
 	const newContent = 'Updated content';
 	const result = await writeTool.execute({
 		file_path: TEST_FILE,
-		content: newContent
+		content: newContent,
+		overwrite: true
 	}) as { success: boolean };
 
 	t.true(result.success, 'Overwrite should succeed');
@@ -104,7 +105,8 @@ test.serial('write:execute - creates parent directories', async (t) => {
 test.serial('write:execute - handles empty content', async (t) => {
 	const result = await writeTool.execute({
 		file_path: TEST_FILE,
-		content: ''
+		content: '',
+		overwrite: true
 	}) as { success: boolean; data?: { bytes_written: number } };
 
 	t.true(result.success, 'Empty content write should succeed');
@@ -115,7 +117,8 @@ test.serial('write:execute - handles special characters', async (t) => {
 	const content = 'Line 1\nLine 2\tTabbed\nSpecial: @#$%^&*()';
 	const result = await writeTool.execute({
 		file_path: TEST_FILE,
-		content
+		content,
+		overwrite: true
 	}) as { success: boolean };
 
 	t.true(result.success, 'Special characters should be preserved');
@@ -129,7 +132,8 @@ test.serial('write:execute - handles large content', async (t) => {
 	const largeContent = 'x'.repeat(10000);
 	const result = await writeTool.execute({
 		file_path: TEST_FILE,
-		content: largeContent
+		content: largeContent,
+		overwrite: true
 	}) as { success: boolean; data?: { bytes_written: number } };
 
 	t.true(result.success, 'Large content write should succeed');
@@ -140,7 +144,8 @@ test.serial('write:execute - handles Unicode content', async (t) => {
 	const content = 'Hello 世界 🌍 Привет мир';
 	const result = await writeTool.execute({
 		file_path: TEST_FILE,
-		content
+		content,
+		overwrite: true
 	}) as { success: boolean };
 
 	t.true(result.success, 'Unicode content should be preserved');
@@ -150,6 +155,40 @@ test.serial('write:execute - handles Unicode content', async (t) => {
 	t.is(fileContent, content, 'Unicode should be preserved correctly');
 });
 
+test.serial('write:execute - refuses to replace an existing file without overwrite', async (t) => {
+	await writeFile(TEST_FILE, 'Keep me');
+
+	const result = await writeTool.execute({
+		file_path: TEST_FILE,
+		content: 'Replaced'
+	}) as { success: boolean; error?: { code: string; message: string } };
+
+	t.false(result.success, 'Write should be refused');
+	t.is(result.error?.code, 'FILE_EXISTS', 'Should return FILE_EXISTS');
+	t.true(result.error?.message.includes('overwrite: true'), 'Should explain how to overwrite');
+
+	const { readFile } = await import('node:fs/promises');
+	const fileContent = await readFile(TEST_FILE, 'utf-8');
+	t.is(fileContent, 'Keep me', 'File should be unchanged');
+});
+
+test.serial('write:execute - reports created vs modified', async (t) => {
+	const newFile = join(TEST_DIR, `created-${randomUUID()}.txt`);
+
+	const created = await writeTool.execute({
+		file_path: newFile,
+		content: 'first'
+	}) as { success: boolean; data?: { action: string } };
+	t.is(created.data?.action, 'created', 'New file should be reported as created');
+
+	const modified = await writeTool.execute({
+		file_path: newFile,
+		content: 'second',
+		overwrite: true
+	}) as { success: boolean; data?: { action: string } };
+	t.is(modified.data?.action, 'modified', 'Existing file should be reported as modified');
+});
+
 test.serial('write:execute - returns validation error for missing file_path', async (t) => {
 	const result = await writeTool.execute({
 		content: 'test'
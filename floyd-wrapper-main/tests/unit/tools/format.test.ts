@@ -0,0 +1,119 @@
+/**
+ * Format Tool Unit Tests
+ *
+ * Tests for formatter selection and formatting with stand-in formatters in
+ * a project's node_modules/.bin.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { formatFile, findExecutable, autoFormatContent } from '../../../dist/tools/system/format.js';
+import { DiffPreviewer } from '../../../dist/permissions/diff-preview.js';
+import { getChangeJournal } from '../../../dist/rewind/change-journal.js';
+import { toolRegistry } from '../../../dist/tools/tool-registry.js';
+import { registerCoreTools } from '../../../dist/tools/index.js';
+
+test.before(() => {
+  registerCoreTools();
+});
+
+/**
+ * A project directory with a fake goimports that collapses repeated spaces
+ * and fails on "syntax error"
+ */
+async function createProject(): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-format-'));
+  await fs.ensureDir(path.join(dir, '.git'));
+  const bin = path.join(dir, 'node_modules', '.bin', 'goimports');
+  await fs.outputFile(bin, '#!/bin/sh\ninput=$(cat)\ncase "$input" in *"syntax error"*) echo "1:1: expected declaration" >&2; exit 2;; esac\nprintf "%s\\n" "$input" | sed "s/  */ /g"\n');
+  await fs.chmod(bin, 0o755);
+  return dir;
+}
+
+test('findExecutable: project binaries before the PATH', async (t) => {
+  const dir = await createProject();
+
+  t.is(await findExecutable('goimports', path.join(dir, 'src')), path.join(dir, 'node_modules', '.bin', 'goimports'));
+  t.is(await findExecutable('floyd-no-such-formatter', dir), null);
+
+  await fs.remove(dir);
+});
+
+test('formatFile: rewrites the file and reports a diff', async (t) => {
+  const dir = await createProject();
+  const file = path.join(dir, 'main.go');
+  await fs.writeFile(file, 'package  main\n');
+
+  const outcome = await formatFile(file);
+
+  t.like(outcome, { formatter: 'goimports', changed: true });
+  t.true(outcome.diff!.includes('-package  main\n+package main'));
+  t.is(await fs.readFile(file, 'utf-8'), 'package main\n');
+  t.like(await formatFile(file), { changed: false });
+
+  await fs.remove(dir);
+});
+
+test('formatFile: check mode leaves the file alone', async (t) => {
+  const dir = await createProject();
+  const file = path.join(dir, 'main.go');
+  await fs.writeFile(file, 'package  main\n');
+
+  t.like(await formatFile(file, { check: true }), { changed: true });
+  t.is(await fs.readFile(file, 'utf-8'), 'package  main\n');
+
+  await fs.remove(dir);
+});
+
+test('formatFile: formatter errors and skipped files', async (t) => {
+  const dir = await createProject();
+  await fs.writeFile(path.join(dir, 'bad.go'), 'syntax error\n');
+  await fs.writeFile(path.join(dir, 'app.ts'), 'const  a = 1\n');
+  await fs.writeFile(path.join(dir, 'notes.txt'), 'text\n');
+
+  t.regex((await formatFile(path.join(dir, 'bad.go'))).error!, /goimports: 1:1: expected declaration/);
+  // No prettier config in the project
+  t.regex((await formatFile(path.join(dir, 'app.ts'))).skipped!, /prettier is not installed or not used/);
+  t.regex((await formatFile(path.join(dir, 'notes.txt'))).skipped!, /no formatter for \.txt/);
+
+  await fs.remove(dir);
+});
+
+test('format tool: journaled by the registry and kept off protected paths', async (t) => {
+  const dir = await createProject();
+  const file = path.join(dir, 'main.go');
+  await fs.writeFile(file, 'package  main\n');
+  const mark = getChangeJournal().mark();
+
+  const result = await toolRegistry.execute('format', { files: [file] }, { permissionGranted: true });
+
+  t.true(result.success);
+  t.deepEqual(getChangeJournal().getEntriesSince(mark).map(entry => [entry.path, entry.toolName]), [[file, 'format']]);
+
+  const blocked = await toolRegistry.execute('format', { files: [file, '/etc/hosts'] }, { permissionGranted: true });
+  t.is(blocked.error?.code, 'PERMISSION_DENIED');
+
+  await fs.remove(dir);
+});
+
+test('autoFormatContent: the diff preview shows the formatted content', async (t) => {
+  const dir = await createProject();
+  const file = path.join(dir, 'main.go');
+  const previewer = new DiffPreviewer();
+  previewer.setEnabled(true);
+  let proposed: string | undefined;
+  previewer.setHandler(async (preview) => {
+    proposed = preview.proposedContent;
+    return { action: 'accept' };
+  });
+
+  await previewer.review('write', { file_path: file, content: 'package  main\n' }, autoFormatContent);
+
+  t.is(proposed, 'package main\n');
+  t.is(await autoFormatContent(path.join(dir, 'notes.txt'), 'a  b\n'), 'a  b\n');
+  t.is(await autoFormatContent(path.join(dir, 'bad.go'), 'syntax error\n'), 'syntax error\n');
+
+  await fs.remove(dir);
+});
@@ -0,0 +1,92 @@
+/**
+ * Unit Tests: GitHub Tools
+ *
+ * Tests for src/tools/github/github-core.ts
+ */
+
+import test from 'ava';
+import {
+  GitHubClient,
+  parseGitHubRemote,
+  issueBranchName,
+  issueFromBranch,
+  formatIssueTask,
+  progressSince,
+  renderPrBody,
+} from '../../../dist/tools/github/github-core.js';
+
+function fakeFetch(routes: Record<string, { status?: number; body: unknown }>, calls: Array<{ url: string; method: string; body?: string }>) {
+  return async (url: string, init: { method: string; body?: string }) => {
+    calls.push({ url, method: init.method, body: init.body });
+    const route = routes[`${init.method} ${url.replace('https://api.github.com', '')}`];
+    const status = route?.status ?? (route ? 200 : 404);
+    return {
+      ok: status < 400,
+      status,
+      json: async () => route?.body ?? { message: 'Not Found' },
+    };
+  };
+}
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: github - parses remotes and names issue branches', (t) => {
+  t.deepEqual(parseGitHubRemote('git@github.com:CaptainPhantasy/Floyd.git'), { owner: 'CaptainPhantasy', repo: 'Floyd' });
+  t.deepEqual(parseGitHubRemote('https://github.com/octo/hello-world'), { owner: 'octo', repo: 'hello-world' });
+  t.deepEqual(parseGitHubRemote('ssh://git@github.com/octo/app.git'), { owner: 'octo', repo: 'app' });
+  t.is(parseGitHubRemote('/srv/git/app'), null);
+
+  t.is(issueBranchName({ number: 42, title: 'Fix date parsing (UTC offsets)!' }), 'issue-42-fix-date-parsing-utc-offsets');
+  t.is(issueFromBranch('issue-42-fix-date-parsing'), 42);
+  t.is(issueFromBranch('feat/login'), undefined);
+});
+
+test('unit: github - PR body from commits, progress and checklist', (t) => {
+  const progress = progressSince([
+    { timestamp: '2026-10-01 09:00:00', action: 'Old work', result: 'done', next: '-' },
+    { timestamp: '2026-10-14 10:00:00', action: 'Fix parser', result: 'tests pass', next: 'open PR' },
+  ], '2026-10-14 09:30:00');
+
+  const body = renderPrBody({
+    commits: ['Fix UTC offset parsing'],
+    progress,
+    state: { checklist: [{ label: 'Tests added', done: true }, { label: 'Env vars documented', done: false }] },
+    issue: 42,
+  });
+
+  t.true(body.includes('- Fix UTC offset parsing'));
+  t.true(body.includes('- Fix parser: tests pass (next: open PR)'));
+  t.false(body.includes('Old work'));
+  t.true(body.includes('- [x] Tests added'));
+  t.true(body.includes('- [ ] Env vars documented'));
+  t.true(body.trimEnd().endsWith('Closes #42'));
+});
+
+test('unit: github - client fetches issues and opens pull requests', async (t) => {
+  const calls: Array<{ url: string; method: string; body?: string }> = [];
+  const client = new GitHubClient('token', {
+    apiUrl: 'https://api.github.com',
+    fetch: fakeFetch({
+      'GET /repos/octo/app/issues/7': {
+        body: { number: 7, title: 'Crash on empty config', body: 'Steps...', state: 'open', html_url: 'https://github.com/octo/app/issues/7', labels: [{ name: 'bug' }], comments: 1 },
+      },
+      'GET /repos/octo/app/issues/7/comments?per_page=50': { body: [{ user: { login: 'ana' }, body: 'Also on 0.2' }] },
+      'POST /repos/octo/app/pulls': { status: 201, body: { number: 8, html_url: 'https://github.com/octo/app/pull/8', draft: true } },
+    }, calls),
+  });
+  const repo = { owner: 'octo', repo: 'app' };
+
+  const issue = await client.getIssue(repo, 7);
+  const task = formatIssueTask(issue);
+  t.true(task.startsWith('# Issue #7: Crash on empty config'));
+  t.true(task.includes('Labels: bug'));
+  t.true(task.includes('**ana:**'));
+
+  const pr = await client.createPullRequest(repo, { title: 'Fix crash', head: 'issue-7-crash', base: 'main', body: 'x', draft: true });
+  t.deepEqual(pr, { number: 8, url: 'https://github.com/octo/app/pull/8', draft: true });
+  t.is(JSON.parse(calls[2].body!).head, 'issue-7-crash');
+
+  await t.throwsAsync(client.getIssue(repo, 9), { message: /failed \(404\): Not Found/ });
+});
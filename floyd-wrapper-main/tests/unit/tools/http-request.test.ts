@@ -0,0 +1,109 @@
+/**
+ * HTTP Request Tool Unit Tests
+ *
+ * Tests for the http_request tool against a local server.
+ */
+
+import test from 'ava';
+import http from 'node:http';
+import type { AddressInfo } from 'node:net';
+import {
+  httpRequestTool,
+  buildRequestBody,
+  formatResponseBody,
+} from '../../../dist/tools/system/http-request.js';
+import { getSafetyEnforcer } from '../../../dist/permissions/safety-enforcer.js';
+
+// Inputs go through the schema like in the registry, for the defaults
+const request = (input: Record<string, unknown>) => httpRequestTool.execute(httpRequestTool.inputSchema.parse(input));
+
+let server: http.Server;
+let baseUrl: string;
+
+test.before(async () => {
+  server = http.createServer((req, res) => {
+    let body = '';
+    req.on('data', chunk => { body += chunk; });
+    req.on('end', () => {
+      if (req.url?.startsWith('/redirect?to=')) {
+        res.writeHead(302, { location: decodeURIComponent(req.url.slice('/redirect?to='.length)) });
+        res.end();
+        return;
+      }
+      if (req.url === '/missing') {
+        res.writeHead(404, { 'content-type': 'text/plain' });
+        res.end('not here');
+        return;
+      }
+      res.writeHead(200, { 'content-type': 'application/json', 'x-test': 'yes' });
+      res.end(JSON.stringify({ method: req.method, contentType: req.headers['content-type'] ?? null, body }));
+    });
+  });
+  await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve));
+  baseUrl = `http://127.0.0.1:${(server.address() as AddressInfo).port}`;
+});
+
+test.after.always(() => {
+  server.close();
+  getSafetyEnforcer().setAllowedDomains([]);
+});
+
+test('buildRequestBody: objects are sent as JSON', (t) => {
+  t.deepEqual(buildRequestBody({ a: 1 }), { body: '{"a":1}', headers: { 'content-type': 'application/json' } });
+  t.deepEqual(buildRequestBody({ a: 1 }, { 'Content-Type': 'application/vnd.api+json' }).headers, { 'Content-Type': 'application/vnd.api+json' });
+  t.deepEqual(buildRequestBody('raw'), { body: 'raw', headers: {} });
+});
+
+test('formatResponseBody: pretty-prints JSON and truncates', (t) => {
+  t.is(formatResponseBody('{"a":1}', 'application/json', 100).body, '{\n  "a": 1\n}');
+  t.true(formatResponseBody('x'.repeat(50), 'text/plain', 10).truncated);
+});
+
+test('httpRequestTool: returns status, headers and body', async (t) => {
+  const result = await request({ url: `${baseUrl}/items`, method: 'POST', body: { name: 'floyd' } });
+  const data = result.data as { status: number; ok: boolean; headers: Record<string, string>; body: string };
+
+  t.true(result.success);
+  t.is(data.status, 200);
+  t.is(data.headers['x-test'], 'yes');
+  t.deepEqual(JSON.parse(data.body), { method: 'POST', contentType: 'application/json', body: '{"name":"floyd"}' });
+});
+
+test('httpRequestTool: error statuses are results', async (t) => {
+  const result = await request({ url: `${baseUrl}/missing` });
+  const data = result.data as { status: number; ok: boolean; body: string };
+
+  t.true(result.success);
+  t.is(data.status, 404);
+  t.false(data.ok);
+  t.is(data.body, 'not here');
+});
+
+test('httpRequestTool: connection failures are errors', async (t) => {
+  const closed = http.createServer();
+  await new Promise<void>(resolve => closed.listen(0, '127.0.0.1', resolve));
+  const { port } = closed.address() as AddressInfo;
+  await new Promise(resolve => closed.close(resolve));
+
+  const result = await request({ url: `http://127.0.0.1:${port}/` });
+
+  t.false(result.success);
+  t.is(result.error?.code, 'NETWORK_ERROR');
+});
+
+test.serial('httpRequestTool: redirects are followed only within the allowlist', async (t) => {
+  getSafetyEnforcer().setAllowedDomains(['127.0.0.1']);
+  try {
+    const followed = await request({ url: `${baseUrl}/redirect?to=/items` });
+    t.true(followed.success);
+    t.is((followed.data as { url: string }).url, `${baseUrl}/items`);
+
+    const away = encodeURIComponent(baseUrl.replace('127.0.0.1', 'localhost') + '/items');
+    const blocked = await request({ url: `${baseUrl}/redirect?to=${away}` });
+    t.false(blocked.success);
+    t.is(blocked.error?.code, 'PERMISSION_DENIED');
+    t.is((blocked.error?.details as { rule: string }).rule, 'domain-not-allowed');
+  } finally {
+    getSafetyEnforcer().setAllowedDomains([]);
+  }
+});
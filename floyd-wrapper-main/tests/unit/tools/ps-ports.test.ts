@@ -0,0 +1,82 @@
+/**
+ * Ports & Processes Unit Tests
+ *
+ * Tests for the parsers behind the ps_ports tool.
+ */
+
+import test from 'ava';
+import {
+  parseProcNet,
+  parseLsof,
+  parseNetstat,
+  parsePs,
+  parseTasklist,
+} from '../../../dist/tools/system/ps-ports.js';
+
+const PROC_HEADER = '  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode';
+
+test('parseProcNet: listening TCP sockets with their inode', (t) => {
+  const text = [
+    PROC_HEADER,
+    '   0: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0 100 0 0 10 0',
+    '   1: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 4243 1 0 100 0 0 10 0',
+  ].join('\n');
+
+  t.deepEqual(parseProcNet(text, 'tcp'), [
+    { protocol: 'tcp', address: '127.0.0.1', port: 3000, pid: null, process: null, inode: '4242' },
+  ]);
+});
+
+test('parseProcNet: IPv6 addresses', (t) => {
+  const text = [
+    PROC_HEADER,
+    '   0: 00000000000000000000000001000000:1F90 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 77 1 0 100 0 0 10 0',
+    '   1: 00000000000000000000000000000000:0035 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 78 2 0',
+  ].join('\n');
+
+  t.like(parseProcNet(text, 'tcp6')[0], { address: '::1', port: 8080 });
+  t.like(parseProcNet(text, 'udp6')[0], { address: '::', port: 53 });
+});
+
+test('parseLsof: listening ports per process', (t) => {
+  const output = ['p501', 'cnode', 'f23', 'tIPv6', 'PTCP', 'n*:3000', 'p77', 'cpostgres', 'f7', 'tIPv4', 'PTCP', 'n127.0.0.1:5432', 'f8', 'n127.0.0.1:5432->127.0.0.1:60000'].join('\n');
+
+  t.deepEqual(parseLsof(output), [
+    { protocol: 'tcp6', address: '*', port: 3000, pid: 501, process: 'node' },
+    { protocol: 'tcp', address: '127.0.0.1', port: 5432, pid: 77, process: 'postgres' },
+  ]);
+});
+
+test('parseNetstat: listening TCP and bound UDP ports', (t) => {
+  const output = [
+    '  Proto  Local Address          Foreign Address        State           PID',
+    '  TCP    0.0.0.0:3000           0.0.0.0:0              LISTENING       4120',
+    '  TCP    127.0.0.1:3000         127.0.0.1:51000        ESTABLISHED     4120',
+    '  TCP    [::]:445               [::]:0                 LISTENING       4',
+    '  UDP    0.0.0.0:5353           *:*                                    2210',
+  ].join('\r\n');
+
+  t.deepEqual(parseNetstat(output).map(entry => [entry.protocol, entry.address, entry.port, entry.pid]), [
+    ['tcp', '0.0.0.0', 3000, 4120],
+    ['tcp6', '::', 445, 4],
+    ['udp', '0.0.0.0', 5353, 2210],
+  ]);
+});
+
+test('parsePs and parseTasklist: processes', (t) => {
+  const ps = parsePs('  4120     1  12.5  1.3    01:02:03 /usr/local/bin/node server.js --port 3000\n    1     0   0.0  0.1 10-00:00:00 /sbin/init\n');
+  t.deepEqual(ps[0], {
+    pid: 4120,
+    ppid: 1,
+    cpuPercent: 12.5,
+    memPercent: 1.3,
+    elapsed: '01:02:03',
+    name: 'node',
+    command: '/usr/local/bin/node server.js --port 3000',
+  });
+  t.is(ps[1].name, 'init');
+
+  t.deepEqual(parseTasklist('"node.exe","4120","Console","1","52,000 K"\r\n'), [
+    { pid: 4120, ppid: null, name: 'node.exe', command: 'node.exe' },
+  ]);
+});
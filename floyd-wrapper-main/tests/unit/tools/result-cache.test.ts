@@ -0,0 +1,80 @@
+/**
+ * Tool Result Cache Unit Tests
+ *
+ * Tests for stubbing repeated reads and listings of unchanged targets.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { ToolResultCache, UNCHANGED_PREFIX } from '../../../dist/tools/result-cache.js';
+
+const ok = { success: true, data: { content: 'hello' } };
+
+async function makeFile(content: string): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-result-cache-'));
+  const file = path.join(dir, 'notes.txt');
+  await fs.writeFile(file, content);
+  return file;
+}
+
+test('repeated read of an unchanged file returns a stub', async (t) => {
+  const cache = new ToolResultCache(true);
+  const file = await makeFile('hello');
+
+  t.is(await cache.lookup('read_file', { file_path: file }), null);
+  await cache.store('read_file', { file_path: file }, ok);
+
+  const stub = await cache.lookup('read_file', { file_path: file });
+  t.true(stub?.success);
+  t.true(stub?.data?.unchanged);
+  t.true(stub?.data?.message.startsWith(UNCHANGED_PREFIX));
+
+  // A different page is a different result
+  t.is(await cache.lookup('read_file', { file_path: file, offset: 10 }), null);
+});
+
+test('a changed file is read again', async (t) => {
+  const cache = new ToolResultCache(true);
+  const file = await makeFile('hello');
+  await cache.store('read_file', { file_path: file }, ok);
+
+  await fs.writeFile(file, 'hello, world');
+  t.is(await cache.lookup('read_file', { file_path: file }), null);
+});
+
+test('fresh: true, failed results and disabled caches bypass the stub', async (t) => {
+  const file = await makeFile('hello');
+
+  const cache = new ToolResultCache(true);
+  await cache.store('read_file', { file_path: file }, ok);
+  t.is(await cache.lookup('read_file', { file_path: file, fresh: true }), null);
+
+  await cache.store('read_file', { file_path: file }, { success: false, error: { code: 'X', message: 'failed' } });
+  t.is(await cache.lookup('read_file', { file_path: file }), null);
+
+  const disabled = new ToolResultCache(false);
+  await disabled.store('read_file', { file_path: file }, ok);
+  t.is(disabled.size, 0);
+});
+
+test('only non-recursive listings and reads are cached', async (t) => {
+  const cache = new ToolResultCache(true);
+  const dir = path.dirname(await makeFile('hello'));
+
+  await cache.store('list_directory', { path: dir }, ok);
+  t.not(await cache.lookup('list_directory', { path: dir }), null);
+
+  await cache.store('list_directory', { path: dir, recursive: true }, ok);
+  await cache.store('grep', { pattern: 'hello', path: dir }, ok);
+  t.is(cache.size, 1);
+
+  // A new entry in the directory changes its listing
+  await fs.writeFile(path.join(dir, 'more.txt'), 'more');
+  await fs.utimes(dir, new Date(), new Date(Date.now() + 5000));
+  t.is(await cache.lookup('list_directory', { path: dir }), null);
+
+  cache.reset();
+  t.is(cache.size, 0);
+});
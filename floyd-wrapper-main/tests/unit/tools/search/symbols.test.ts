@@ -0,0 +1,122 @@
+/**
+ * Symbols Core Unit Tests
+ *
+ * Tests for finding declarations, definitions and references with file:line
+ * locations.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  findDefinitions,
+  findDefinition,
+  findReferences,
+  listSymbols,
+} from '../../../../dist/tools/search/symbols-core.js';
+
+const GO_SOURCE = `package eth
+
+const (
+	ProtocolName = "eth"
+	maxPeers     = 50
+)
+
+type ProtocolManager struct {
+	peers int
+}
+
+// NewProtocolManager creates a ProtocolManager
+func NewProtocolManager() *ProtocolManager {
+	return &ProtocolManager{}
+}
+
+func (pm *ProtocolManager) Start() error {
+	return nil
+}
+`;
+
+const TS_SOURCE = `import { NewThing } from './thing';
+
+export class Engine {
+  private readonly cache = new Map();
+
+  constructor(private name: string) {}
+
+  async execute(message: string): Promise<void> {
+    if (message) {
+      this.start();
+    }
+  }
+}
+
+export function createEngine(): Engine {
+  return new Engine('floyd');
+}
+
+const DEFAULT_NAME = 'floyd';
+`;
+
+async function makeProject(): Promise<string> {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-symbols-'));
+  await fs.outputFile(path.join(root, 'eth', 'handler.go'), GO_SOURCE);
+  await fs.outputFile(path.join(root, 'eth', 'peer.go'), 'package eth\n\nfunc attach(pm *ProtocolManager) {\n\tpm.Start()\n}\n');
+  await fs.outputFile(path.join(root, 'src', 'engine.ts'), TS_SOURCE);
+  return root;
+}
+
+test('findDefinitions: Go declarations with kinds, receivers and lines', (t) => {
+  const defs = findDefinitions('handler.go', GO_SOURCE).map(d => `${d.line} ${d.kind} ${d.container ? `${d.container}.` : ''}${d.name}`);
+  t.deepEqual(defs, [
+    '4 constant ProtocolName',
+    '5 constant maxPeers',
+    '8 struct ProtocolManager',
+    '13 function NewProtocolManager',
+    '17 method ProtocolManager.Start',
+  ]);
+  t.false(findDefinitions('handler.go', GO_SOURCE).find(d => d.name === 'maxPeers')!.exported);
+});
+
+test('findDefinitions: TypeScript classes, methods and functions, not statements', (t) => {
+  const defs = findDefinitions('engine.ts', TS_SOURCE).map(d => `${d.line} ${d.kind} ${d.container ? `${d.container}.` : ''}${d.name}`);
+  t.deepEqual(defs, [
+    '3 class Engine',
+    '8 method Engine.execute',
+    '15 function createEngine',
+    '19 constant DEFAULT_NAME',
+  ]);
+});
+
+test('findDefinition: locates a type and narrows methods by receiver', async (t) => {
+  const root = await makeProject();
+
+  const types = await findDefinition('ProtocolManager', { path: root });
+  t.is(types.length, 1);
+  t.is(types[0]!.kind, 'struct');
+  t.true(types[0]!.location.endsWith(path.join('eth', 'handler.go') + ':8'));
+
+  const methods = await findDefinition('ProtocolManager.Start', { path: root });
+  t.is(methods.length, 1);
+  t.is(methods[0]!.line, 17);
+  t.deepEqual(await findDefinition('Engine.Start', { path: root }), []);
+});
+
+test('findReferences: skips comments and the declaration itself', async (t) => {
+  const root = await makeProject();
+  const refs = await findReferences('ProtocolManager', { path: root });
+
+  t.deepEqual(refs.map(ref => `${path.basename(ref.file)}:${ref.line}`), [
+    'handler.go:13',
+    'handler.go:14',
+    'handler.go:17',
+    'peer.go:3',
+  ]);
+});
+
+test('listSymbols: filters a directory by kind', async (t) => {
+  const root = await makeProject();
+  const functions = await listSymbols(path.join(root, 'eth'), { kind: 'function' });
+
+  t.deepEqual(functions.map(fn => fn.name), ['NewProtocolManager', 'attach']);
+});
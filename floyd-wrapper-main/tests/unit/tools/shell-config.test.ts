@@ -0,0 +1,67 @@
+/**
+ * Shell Config Unit Tests
+ *
+ * Tests for the run tool settings in .floyd/shell.toml.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  parseShellConfig,
+  loadShellConfig,
+  shellInvocation,
+  buildCommandEnv,
+} from '../../../dist/tools/system/shell-config.js';
+
+test('parseShellConfig: shell, cwd, env inheritance and extra variables', (t) => {
+  const config = parseShellConfig([
+    'shell = "zsh"',
+    'cwd = "packages/api"',
+    'inherit_env = false',
+    '',
+    '[env]',
+    'NODE_ENV = "test"',
+    'RETRIES = 3',
+  ].join('\n'), '/work');
+
+  t.deepEqual(config, {
+    shell: 'zsh',
+    cwd: path.resolve('/work', 'packages/api'),
+    inheritEnv: false,
+    env: { NODE_ENV: 'test', RETRIES: '3' },
+  });
+});
+
+test('parseShellConfig: rejects values of the wrong type', (t) => {
+  t.throws(() => parseShellConfig('inherit_env = "no"', '/work'), { message: /inherit_env/ });
+  t.throws(() => parseShellConfig('env = "A=1"', '/work'), { message: /table/ });
+});
+
+test('loadShellConfig: defaults without a file', async (t) => {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-shell-'));
+  t.deepEqual(loadShellConfig(root), { inheritEnv: true, env: {} });
+
+  await fs.outputFile(path.join(root, '.floyd', 'shell.toml'), 'shell = "fish"\n');
+  t.is(loadShellConfig(root).shell, 'fish');
+
+  await fs.remove(root);
+});
+
+test('shellInvocation: flags per shell', (t) => {
+  t.deepEqual(shellInvocation('bash', 'ls | wc -l'), { file: 'bash', args: ['-c', 'ls | wc -l'] });
+  t.deepEqual(shellInvocation('/usr/local/bin/fish', 'echo hi'), { file: '/usr/local/bin/fish', args: ['-c', 'echo hi'] });
+  t.is(shellInvocation('pwsh', 'Get-ChildItem').args.at(-2), '-Command');
+  t.deepEqual(shellInvocation('C:\\Windows\\System32\\cmd.exe', 'dir').args, ['/d', '/s', '/c', 'dir']);
+});
+
+test('buildCommandEnv: inherit or keep only the base variables', (t) => {
+  const parent = { PATH: '/bin', HOME: '/home/me', AWS_SECRET_ACCESS_KEY: 'x' };
+
+  const inherited = buildCommandEnv({ inheritEnv: true, env: { NODE_ENV: 'test' } }, { CI: '1' }, parent);
+  t.like(inherited, { PATH: '/bin', AWS_SECRET_ACCESS_KEY: 'x', NODE_ENV: 'test', CI: '1' });
+
+  const isolated = buildCommandEnv({ inheritEnv: false, env: { NODE_ENV: 'test' } }, {}, parent);
+  t.deepEqual(isolated, { PATH: '/bin', HOME: '/home/me', NODE_ENV: 'test' });
+});
@@ -0,0 +1,100 @@
+/**
+ * Shell Session Unit Tests
+ *
+ * Tests for the persistent shell behind the shell_session tool.
+ */
+
+import test from 'ava';
+import os from 'node:os';
+import {
+  MAX_BUFFER_CHARS,
+  ShellSession,
+  closeAllShellSessions,
+  shellSessionTool,
+  wrapCommand,
+} from '../../../dist/tools/system/shell-session.js';
+
+test('wrapCommand: groups the command with the end marker', (t) => {
+  t.is(wrapCommand('/bin/bash', 'npm test', 'M'), `{ npm test\n}; printf '\\nM:%s\\n' "$?"\n`);
+  t.true(wrapCommand('fish', 'ls', 'M').startsWith('begin\nls\nend;'));
+  t.true(wrapCommand('pwsh', 'dir', 'M').includes('Write-Output'));
+});
+
+test('ShellSession: directory and variables persist between commands', async (t) => {
+  const session = new ShellSession('sh', os.tmpdir(), process.env);
+
+  session.run('cd / && export FLOYD_TEST_VAR=kept');
+  await session.wait(5000);
+  t.like(session.read(), { done: true, exitCode: 0 });
+
+  session.run('pwd; echo $FLOYD_TEST_VAR; false');
+  await session.wait(5000);
+  t.deepEqual(session.read(), { output: '/\nkept\n', done: true, exitCode: 1, closed: false });
+
+  session.close();
+});
+
+test('ShellSession: output is read incrementally and prompts get sent input', async (t) => {
+  const session = new ShellSession('sh', os.tmpdir(), process.env);
+
+  session.run('read answer; sleep 0.2; echo "got $answer"');
+  await session.wait(50);
+  t.like(session.read(), { output: '', done: false });
+
+  session.send('yes');
+  await session.wait(5000);
+  t.like(session.read(), { output: 'got yes\n', done: true, exitCode: 0 });
+
+  session.close();
+  t.true(session.isClosed());
+});
+
+test('ShellSession: commands run in a terminal', async (t) => {
+  const session = new ShellSession('sh', os.tmpdir(), process.env);
+
+  session.run('test -t 0 && test -t 1 && echo tty');
+  await session.wait(5000);
+  t.like(session.read(), { output: 'tty\n', done: true, exitCode: 0 });
+
+  session.close();
+});
+
+test('ShellSession: unread output is capped, dropping the oldest', async (t) => {
+  const session = new ShellSession('sh', os.tmpdir(), process.env);
+
+  session.run(`head -c ${MAX_BUFFER_CHARS + 1000} /dev/zero | tr '\\0' a; echo END`);
+  await session.wait(20000);
+  const result = session.read();
+  t.true(result.done);
+  t.true(result.output.startsWith('['));
+  t.true(result.output.includes('earlier characters dropped'));
+  t.true(result.output.endsWith('aaaEND\n'));
+  t.true(result.output.length < MAX_BUFFER_CHARS + 100);
+
+  session.close();
+});
+
+test('ShellSession: exiting the shell reports its exit code', async (t) => {
+  const session = new ShellSession('sh', os.tmpdir(), process.env);
+
+  session.run('exit 3');
+  await session.wait(5000);
+  t.like(session.read(), { done: true, exitCode: 3, closed: true });
+  t.is(session.getShellExitCode(), 3);
+});
+
+test.serial('shell_session: an exited shell is reported once, then replaced', async (t) => {
+  const run = (action: string, command?: string) =>
+    shellSessionTool.execute({ action, command, session: 'exit-test', wait_ms: 5000 });
+
+  await run('run', 'exit 4');
+
+  const exited = await run('read');
+  t.false(exited.success);
+  t.true(exited.error?.message.includes('exited with code 4'));
+
+  const restarted = await run('run', 'echo again');
+  t.like(restarted, { success: true, data: { output: 'again\n', exitCode: 0 } });
+
+  closeAllShellSessions();
+});
@@ -0,0 +1,58 @@
+/**
+ * Unit Tests: Todo Tool
+ *
+ * Tests for src/tools/todo/todo-core.ts and the todo tool in src/tools/todo/index.ts
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  TodoList,
+  formatTodoLines,
+  mirrorToMasterPlan,
+  todoProgress,
+} from '../../../dist/tools/todo/todo-core.js';
+import { todoTool } from '../../../dist/tools/todo/index.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: todo - sets and updates the list and notifies listeners', (t) => {
+  const list = new TodoList();
+  const seen: number[] = [];
+  list.subscribe(snapshot => seen.push(todoProgress(snapshot).done));
+
+  list.set([{ text: 'Add the route' }, { text: '  ' }, { text: 'Write tests' }], 'Login page');
+  const snapshot = list.update([{ id: 1, status: 'completed' }, { id: 2, status: 'in_progress' }]);
+
+  t.is(snapshot.goal, 'Login page');
+  t.deepEqual(formatTodoLines(snapshot), ['[x] 1. Add the route', '[~] 2. Write tests']);
+  t.deepEqual(seen, [0, 1]);
+  t.throws(() => list.update([{ id: 3, status: 'completed' }]), { message: /No task #3/ });
+});
+
+test('unit: todo - mirrors the list into master_plan.md without touching the rest', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-todo-'));
+  const planPath = path.join(dir, '.floyd', 'master_plan.md');
+  await fs.outputFile(planPath, '# Master Plan\n\n## Strategic Steps\n- [ ] Ship it\n');
+
+  const list = new TodoList();
+  await mirrorToMasterPlan(list.set([{ text: 'Add the route' }, { text: 'Write tests' }], 'Login'), dir);
+  await mirrorToMasterPlan(list.update([{ id: 1, status: 'completed' }]), dir);
+
+  const plan = await fs.readFile(planPath, 'utf-8');
+  t.true(plan.startsWith('# Master Plan\n\n## Strategic Steps\n- [ ] Ship it\n'));
+  t.is(plan.match(/## Current Tasks: Login/g)?.length, 1);
+  t.regex(plan, /- \[x\] Add the route\n- \[ \] Write tests/);
+  t.regex(plan, /1\/2 done/);
+});
+
+test('unit: todo - tool rejects calls without todos or update', async (t) => {
+  const result = await todoTool.execute({ goal: 'nothing' });
+
+  t.false(result.success);
+  t.is(result.error?.code, 'INVALID_INPUT');
+});
@@ -0,0 +1,63 @@
+/**
+ * Tool Stats Unit Tests
+ *
+ * Tests for the per-tool call, failure and latency numbers kept by the
+ * tool registry and shown by /stats.
+ */
+
+import test from 'ava';
+import { z } from 'zod';
+import { ToolRegistry } from '../../../dist/tools/tool-registry.js';
+import { formatToolStats } from 'floyd-agent-core/utils';
+
+function fakeTool(name: string, run: () => Promise<unknown>) {
+  return {
+    name,
+    description: `Fake ${name}`,
+    category: 'special' as const,
+    inputSchema: z.object({}),
+    permission: 'none' as const,
+    execute: run,
+  };
+}
+
+test('getStats: counts calls, failures and thrown errors per tool', async (t) => {
+  const registry = new ToolRegistry();
+  registry.register(fakeTool('ok_tool', async () => ({ success: true, data: {} })));
+  registry.register(fakeTool('flaky_tool', async () => ({ success: false, error: { code: 'X', message: 'no' } })));
+  registry.register(fakeTool('broken_tool', async () => { throw new Error('boom'); }));
+
+  await registry.execute('ok_tool', {});
+  await registry.execute('ok_tool', {});
+  await registry.execute('flaky_tool', {});
+  await registry.execute('broken_tool', {});
+
+  const stats = Object.fromEntries(registry.getStats().map(s => [s.tool, s]));
+  t.like(stats.ok_tool, { calls: 2, successes: 2, errors: 0, errorRate: 0 });
+  t.like(stats.flaky_tool, { calls: 1, errors: 1, errorRate: 1 });
+  t.like(stats.broken_tool, { calls: 1, errors: 1 });
+  t.is(stats.ok_tool!.histogram.reduce((sum: number, n: number) => sum + n, 0), 2);
+});
+
+test('getStats: calls rejected before running are not counted', async (t) => {
+  const registry = new ToolRegistry();
+  registry.register(fakeTool('ok_tool', async () => ({ success: true })));
+
+  await registry.execute('missing_tool', {});
+  await registry.execute('ok_tool', 'not an object');
+
+  t.deepEqual(registry.getStats(), []);
+});
+
+test('resetStats and formatToolStats', async (t) => {
+  const registry = new ToolRegistry();
+  registry.register(fakeTool('ok_tool', async () => ({ success: true })));
+  await registry.execute('ok_tool', {});
+
+  const lines = formatToolStats(registry.getStats());
+  t.regex(lines[0]!, /^1 tool call, 0 failed/);
+  t.regex(lines[3]!, /^ok_tool\s+1\s+0/);
+
+  registry.resetStats();
+  t.deepEqual(formatToolStats(registry.getStats()), ['No tools have run yet.']);
+});
@@ -0,0 +1,47 @@
+/**
+ * Unit Tests: Notifier
+ *
+ * Tests for src/ui/notifier.ts and the notification keys of ~/.floyd/config.toml
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { Notifier, notifierSettings, osNotificationCommand } from '../../../dist/ui/notifier.js';
+import { loadUserConfig, saveUserConfig } from '../../../dist/utils/user-config.js';
+
+// ============================================================================
+// Test Cases
+// ============================================================================
+
+test('unit: notifier - rings only for runs that took long enough', (t) => {
+  const written: string[] = [];
+  const notifier = new Notifier({ bell: true, desktop: false, minRunMs: 30_000 }, text => written.push(text));
+
+  notifier.runEnded('complete', 5_000, 'quick');
+  notifier.runEnded('error', 45_000, 'slow');
+  notifier.notify('approval', 'waiting');
+
+  t.deepEqual(written, ['\x07', '\x07']);
+});
+
+test('unit: notifier - builds OS notification commands', (t) => {
+  t.deepEqual(osNotificationCommand('darwin', 'FLOYD finished', 'Said "hi"'), {
+    command: 'osascript',
+    args: ['-e', 'display notification "Said \\"hi\\"" with title "FLOYD finished"'],
+  });
+  t.is(osNotificationCommand('linux', 'FLOYD finished', 'done')?.command, 'notify-send');
+  t.is(osNotificationCommand('win32', 'FLOYD finished', 'done'), null);
+});
+
+test('unit: notifier - settings come from config.toml with defaults', async (t) => {
+  t.deepEqual(notifierSettings(null), { bell: true, desktop: false, minRunMs: 30_000 });
+
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-notify-'));
+  const configPath = path.join(dir, 'config.toml');
+  await saveUserConfig({ theme: 'crush', notifyBell: false, notifyDesktop: true, notifyAfter: 120 }, configPath);
+
+  t.deepEqual(notifierSettings(loadUserConfig(configPath)), { bell: false, desktop: true, minRunMs: 120_000 });
+  await fs.remove(dir);
+});
@@ -0,0 +1,60 @@
+/**
+ * Setup Wizard Unit Tests
+ *
+ * Tests for the first-run wizard: answers end up in config.toml, the key is
+ * only kept once it passes the check, and .floyd/ is set up on request.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { runSetupWizard, initProjectDir } from '../../../dist/ui/setup-wizard.js';
+import { loadUserConfig } from '../../../dist/utils/user-config.js';
+
+function scriptedIO(answers: string[], secrets: string[]) {
+  const printed: string[] = [];
+  return {
+    printed,
+    io: {
+      ask: async (_question: string, defaultValue?: string) => answers.shift() || defaultValue || '',
+      askSecret: async () => secrets.shift() ?? '',
+      print: (line: string) => printed.push(line),
+    },
+  };
+}
+
+test('runSetupWizard: saves provider, model and theme and skips a rejected key', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-setup-'));
+  const configPath = path.join(dir, 'config.toml');
+  const checked: string[] = [];
+
+  // Anthropic, default model, a bad key then skip, default theme
+  const { io } = scriptedIO(['2', '', 's', ''], ['bad-key']);
+  const config = await runSetupWizard({
+    io,
+    configPath,
+    projectRoot: dir,
+    validate: async (_provider: string, key: string) => {
+      checked.push(key);
+      return { ok: false, status: 401, message: 'Key rejected' };
+    },
+  });
+
+  t.deepEqual(checked, ['bad-key']);
+  t.like(config, { provider: 'anthropic', baseUrl: 'https://api.anthropic.com/v1' });
+  t.deepEqual(loadUserConfig(configPath), config);
+  t.regex(await fs.readFile(configPath, 'utf-8'), /^provider = "anthropic"$/m);
+
+  await fs.remove(dir);
+});
+
+test('initProjectDir: adds .floyd/.gitignore and FLOYD.md only when missing', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-init-'));
+
+  t.deepEqual(await initProjectDir(dir), [path.join('.floyd', '.gitignore'), 'FLOYD.md']);
+  t.deepEqual(await initProjectDir(dir), []);
+  t.regex(await fs.readFile(path.join(dir, '.floyd', '.gitignore'), 'utf-8'), /^runs\/$/m);
+
+  await fs.remove(dir);
+});
@@ -0,0 +1,75 @@
+/**
+ * Theme Unit Tests
+ *
+ * Tests for loading theme files from a directory, live reload, and applying
+ * the shared palette to CRUSH_THEME.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { getThemeManager, resetThemeManager } from '../../../dist/ui/theme.js';
+import { CRUSH_THEME } from '../../../dist/constants.js';
+
+async function makeThemesDir(): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-themes-'));
+  await fs.writeFile(
+    path.join(dir, 'dusk.toml'),
+    [
+      '# Warmer accents',
+      'name = "dusk"',
+      'description = "Warm"',
+      '',
+      '[colors]',
+      'primary = "#FF985A"  # Tang',
+      'success = "#00FFB2"',
+    ].join('\n'),
+  );
+  await fs.writeFile(path.join(dir, 'paper.json'), JSON.stringify({ colors: { bgBase: '#FFFAF1' } }));
+  await fs.writeFile(path.join(dir, 'broken.toml'), '[colors]\nprimary = "purple"\n');
+  return dir;
+}
+
+test.afterEach(() => {
+  resetThemeManager();
+});
+
+test.serial('loads TOML and JSON themes alongside the built-in one', async (t) => {
+  const themes = getThemeManager({ dir: await makeThemesDir(), theme: 'crush' });
+
+  t.deepEqual(themes.list().map(theme => theme.name), ['crush', 'dusk', 'paper']);
+  t.is(themes.find('dusk')?.description, 'Warm');
+  // Colors a file leaves out come from CRUSH
+  t.is(themes.find('paper')?.colors.primary, '#6B50FF');
+  t.is(themes.errors.length, 1);
+  t.regex(themes.errors[0]!, /broken\.toml: color "primary" must be a hex value/);
+});
+
+test.serial('switching theme updates CRUSH_THEME colors and roles', async (t) => {
+  const themes = getThemeManager({ dir: await makeThemesDir(), theme: 'crush' });
+
+  themes.use('dusk');
+  t.is(CRUSH_THEME.colors.primary, '#FF985A');
+  t.is(CRUSH_THEME.semantic.userLabel, '#00FFB2');
+
+  t.throws(() => themes.use('nope'), { message: /Unknown theme "nope"/ });
+  t.is(themes.current.name, 'dusk');
+});
+
+test.serial('reload picks up edited files and keeps the active theme', async (t) => {
+  const dir = await makeThemesDir();
+  const themes = getThemeManager({ dir, theme: 'dusk' });
+  t.is(CRUSH_THEME.colors.primary, '#FF985A');
+
+  await fs.writeFile(path.join(dir, 'dusk.toml'), '[colors]\nprimary = "#D36C64"\n');
+  themes.reload();
+  t.is(themes.current.name, 'dusk');
+  t.is(CRUSH_THEME.colors.primary, '#D36C64');
+
+  await fs.remove(path.join(dir, 'dusk.toml'));
+  const problems = themes.reload();
+  t.is(themes.current.name, 'crush');
+  t.true(problems.some(problem => problem.includes('"dusk" is gone')));
+  t.is(CRUSH_THEME.colors.primary, '#6B50FF');
+});
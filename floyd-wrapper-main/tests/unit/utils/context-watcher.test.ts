@@ -0,0 +1,33 @@
+/**
+ * Context Watcher Unit Tests
+ *
+ * Tests for reporting changes to the files of the project context.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { ContextWatcher } from '../../../dist/utils/context-watcher.js';
+
+const sleep = (ms: number) => new Promise(resolve => setTimeout(resolve, ms));
+
+test('ContextWatcher: reports a burst of plan edits once', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-context-'));
+  const plan = path.join(dir, '.floyd', 'master_plan.md');
+  await fs.outputFile(plan, '# Master Plan\n');
+
+  const seen: string[][] = [];
+  const watcher = new ContextWatcher(dir, files => seen.push(files));
+  watcher.start();
+  await sleep(300);
+
+  await fs.appendFile(plan, '- [ ] One\n');
+  await fs.appendFile(plan, '- [ ] Two\n');
+  await fs.writeFile(path.join(dir, 'README.md'), 'not context');
+  await sleep(1000);
+
+  t.deepEqual(seen, [[path.join('.floyd', 'master_plan.md')]]);
+  await watcher.stop();
+  await fs.remove(dir);
+});
@@ -0,0 +1,114 @@
+/**
+ * Credential Store Unit Tests
+ *
+ * Tests for the API key store behind `floyd auth` and /auth: the encrypted
+ * file fallback, env precedence and key validation.
+ */
+
+import test from 'ava';
+import http from 'node:http';
+import os from 'node:os';
+import path from 'node:path';
+import fs from 'fs-extra';
+import {
+  CredentialStore,
+  EncryptedFileBackend,
+  MacKeychainBackend,
+  validateApiKey,
+  parseProvider,
+  maskKey,
+  isSecretCommand,
+} from 'floyd-agent-core/utils';
+
+async function tempFile(): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-credentials-'));
+  return path.join(dir, 'credentials.enc');
+}
+
+test('EncryptedFileBackend: round-trips keys without storing them in plain text', async (t) => {
+  const file = await tempFile();
+  const backend = new EncryptedFileBackend(file, 'passphrase');
+
+  backend.set('zai', 'glm-secret-key');
+  backend.set('openai', 'sk-other');
+  t.is(backend.get('zai'), 'glm-secret-key');
+  t.false((await fs.readFile(file, 'utf8')).includes('glm-secret-key'));
+  t.is((await fs.stat(file)).mode & 0o777, 0o600);
+
+  t.true(backend.delete('openai'));
+  t.false(backend.delete('openai'));
+  t.is(new EncryptedFileBackend(file, 'passphrase').get('zai'), 'glm-secret-key');
+});
+
+test.serial('MacKeychainBackend: the key goes to security on stdin, not in argv', async (t) => {
+  if (process.platform === 'win32') {
+    t.pass();
+    return;
+  }
+  // Stand-in for macOS security that records its arguments and input
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-keychain-'));
+  await fs.outputFile(path.join(dir, 'security'), `#!/bin/sh\necho "$@" > "${dir}/args"\ncat > "${dir}/stdin"\n`);
+  await fs.chmod(path.join(dir, 'security'), 0o755);
+  const pathBefore = process.env.PATH;
+  process.env.PATH = `${dir}${path.delimiter}${pathBefore}`;
+
+  try {
+    new MacKeychainBackend().set('openai', 'sk-secret-key');
+  } finally {
+    process.env.PATH = pathBefore;
+  }
+
+  const args = await fs.readFile(path.join(dir, 'args'), 'utf8');
+  t.false(args.includes('sk-secret-key'));
+  t.true(args.trim().endsWith('-w'));
+  t.is((await fs.readFile(path.join(dir, 'stdin'), 'utf8')).split('\n')[0], 'sk-secret-key');
+  await fs.remove(dir);
+});
+
+test('CredentialStore: a wrong passphrase reads as no key', async (t) => {
+  const file = await tempFile();
+  new EncryptedFileBackend(file, 'right').set('zai', 'glm-secret-key');
+
+  const store = new CredentialStore([new EncryptedFileBackend(file, 'wrong')]);
+  t.is(store.get('zai'), null);
+});
+
+test('CredentialStore: environment variables win over stored keys', async (t) => {
+  const store = new CredentialStore([new EncryptedFileBackend(await tempFile(), 'passphrase')]);
+  store.set('zai', '  stored-key-1234  ');
+
+  t.is(store.resolve('zai', {}), 'stored-key-1234');
+  t.is(store.resolve('zai', { GLM_API_KEY: 'env-key' }), 'env-key');
+
+  const status = store.status({ OPENAI_API_KEY: 'sk-from-env-5678' });
+  t.like(status.find(entry => entry.provider === 'zai'), { masked: 'stor…1234' });
+  t.like(status.find(entry => entry.provider === 'openai'), { source: 'OPENAI_API_KEY', masked: 'sk-f…5678' });
+  t.like(status.find(entry => entry.provider === 'deepseek'), { source: null, masked: null });
+});
+
+test('parseProvider, maskKey and isSecretCommand', (t) => {
+  t.is(parseProvider('glm'), 'zai');
+  t.is(parseProvider('Anthropic'), 'anthropic');
+  t.is(parseProvider('nope'), null);
+  t.is(maskKey('short'), '*****');
+  t.true(isSecretCommand('/auth login glm abc123'));
+  t.false(isSecretCommand('/auth status'));
+  t.false(isSecretCommand('/auth login glm'));
+});
+
+test('validateApiKey: accepts a working key and reports a rejected one', async (t) => {
+  const server = http.createServer((req, res) => {
+    res.statusCode = req.headers.authorization === 'Bearer good' ? 200 : 401;
+    res.end('{}');
+  });
+  await new Promise<void>(resolve => server.listen(0, '127.0.0.1', resolve));
+  const { port } = server.address() as { port: number };
+  const endpoint = `http://127.0.0.1:${port}/v1`;
+
+  try {
+    t.like(await validateApiKey('openai', 'good', { endpoint }), { ok: true, status: 200 });
+    t.like(await validateApiKey('openai', 'bad', { endpoint }), { ok: false, status: 401 });
+  } finally {
+    server.close();
+  }
+});
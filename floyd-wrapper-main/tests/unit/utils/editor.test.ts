@@ -0,0 +1,25 @@
+/**
+ * Editor Launcher Unit Tests
+ *
+ * Tests for turning $VISUAL / $EDITOR into argv without a shell.
+ */
+
+import test from 'ava';
+import { splitCommandLine, editorCommand } from '../../../dist/utils/editor.js';
+
+test('splitCommandLine: splits on whitespace and honours quotes', (t) => {
+  t.deepEqual(splitCommandLine('code --wait'), ['code', '--wait']);
+  t.deepEqual(splitCommandLine(`"/Applications/My Editor/bin/ed" -n 'a b'`), ['/Applications/My Editor/bin/ed', '-n', 'a b']);
+  t.deepEqual(splitCommandLine('vim\\ x ""'), ['vim x', '']);
+});
+
+test('splitCommandLine: shell syntax stays literal', (t) => {
+  t.deepEqual(splitCommandLine('vi; rm -rf ~ $(id)'), ['vi;', 'rm', '-rf', '~', '$(id)']);
+});
+
+test('editorCommand: VISUAL, then EDITOR, then vi', (t) => {
+  t.deepEqual(editorCommand({ VISUAL: 'code -w', EDITOR: 'nano' }), ['code', '-w']);
+  t.deepEqual(editorCommand({ EDITOR: 'nano' }), ['nano']);
+  t.deepEqual(editorCommand({ EDITOR: '  ' }), ['vi']);
+  t.deepEqual(editorCommand({}), ['vi']);
+});
@@ -0,0 +1,57 @@
+/**
+ * HTML to Markdown Unit Tests
+ *
+ * Tests for converting fetched pages for the model.
+ */
+
+import test from 'ava';
+import { htmlToMarkdown, extractTitle, truncateText, decodeEntities } from '../../../dist/utils/html-to-markdown.js';
+
+const PAGE = `<!doctype html>
+<html>
+<head><title>Fetch &amp; Go</title><style>.nav { color: red; }</style></head>
+<body>
+<header><nav><a href="/">Home</a> <a href="/blog">Blog</a></nav></header>
+<main>
+<h1>The <code>fetch</code> API</h1>
+<p>Use <a href="/docs/fetch">fetch</a> to make <strong>HTTP</strong> requests&nbsp;&mdash; see <em>below</em>.</p>
+<ul><li>GET</li><li>POST &lt;body&gt;</li></ul>
+<pre><code class="language-js">if (res.ok) {
+  console.log(&quot;ok&quot;);
+}</code></pre>
+<table><tr><th>Option</th><th>Type</th></tr><tr><td>method</td><td>string</td></tr></table>
+<script>track();</script>
+</main>
+<footer>Copyright 2026</footer>
+</body>
+</html>`;
+
+test('htmlToMarkdown: keeps the main content and drops boilerplate', (t) => {
+  const markdown = htmlToMarkdown(PAGE, 'https://example.com/guide/');
+
+  t.true(markdown.startsWith('# The fetch API'));
+  t.notRegex(markdown, /Home|Blog|Copyright|track\(\)|color: red/);
+});
+
+test('htmlToMarkdown: converts links, emphasis, lists, code and tables', (t) => {
+  const markdown = htmlToMarkdown(PAGE, 'https://example.com/guide/');
+
+  t.regex(markdown, /Use \[fetch\]\(https:\/\/example\.com\/docs\/fetch\) to make \*\*HTTP\*\* requests — see \*below\*\./);
+  t.regex(markdown, /- GET\n- POST <body>/);
+  t.regex(markdown, /```js\nif \(res\.ok\) \{\n {2}console\.log\("ok"\);\n\}\n```/);
+  t.regex(markdown, /\| Option \| Type \|\n\| --- \| --- \|\n\| method \| string \|/);
+});
+
+test('extractTitle and decodeEntities', (t) => {
+  t.is(extractTitle(PAGE), 'Fetch & Go');
+  t.is(extractTitle('<p>no title</p>'), undefined);
+  t.is(decodeEntities('&#60;&#x3e;&unknown;'), '<>&unknown;');
+});
+
+test('truncateText: cuts at a line break and notes what was dropped', (t) => {
+  t.deepEqual(truncateText('short', 10), { text: 'short', truncated: false });
+
+  const { text, truncated } = truncateText('aaaa\nbbbb\ncccc', 9);
+  t.true(truncated);
+  t.is(text, 'aaaa\nbbbb\n\n[Truncated: 5 more characters]');
+});
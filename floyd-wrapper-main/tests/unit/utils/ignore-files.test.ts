@@ -0,0 +1,61 @@
+/**
+ * Ignore Files Unit Tests
+ *
+ * Tests for the .gitignore / .floydignore rules honored by the file and
+ * search tools.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import { createIgnoreFilter, ignoreGlobOptions } from '../../../dist/utils/ignore-files.js';
+
+async function makeProject(): Promise<string> {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-ignore-'));
+  await fs.outputFile(path.join(root, '.gitignore'), 'build/\n*.log\n');
+  await fs.outputFile(path.join(root, '.floydignore'), 'fixtures/\n');
+  await fs.outputFile(path.join(root, 'packages', 'api', '.gitignore'), 'generated.ts\n');
+  return root;
+}
+
+test('createIgnoreFilter: honors .gitignore and .floydignore', async (t) => {
+  const root = await makeProject();
+  const isIgnored = await createIgnoreFilter(root);
+
+  t.true(isIgnored('build', true));
+  t.true(isIgnored('debug.log'));
+  t.true(isIgnored('fixtures', true));
+  t.false(isIgnored('src/index.ts'));
+  t.false(isIgnored('build'));
+
+  await fs.remove(root);
+});
+
+test('createIgnoreFilter: nested ignore files apply below them only', async (t) => {
+  const root = await makeProject();
+  const isIgnored = await createIgnoreFilter(root);
+
+  t.true(isIgnored('packages/api/generated.ts'));
+  t.false(isIgnored('generated.ts'));
+  t.true(isIgnored(path.join(root, 'packages', 'api', 'generated.ts')));
+
+  await fs.remove(root);
+});
+
+test('createIgnoreFilter: node_modules and .git are always skipped', async (t) => {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-ignore-'));
+  const isIgnored = await createIgnoreFilter(root);
+
+  t.true(isIgnored('node_modules', true));
+  t.true(isIgnored('./node_modules/lodash/index.js'));
+  t.true(isIgnored('.git/config'));
+  t.false(isIgnored('../outside.ts'));
+
+  await fs.remove(root);
+});
+
+test('ignoreGlobOptions: turns the rules off for include_ignored', (t) => {
+  t.deepEqual(ignoreGlobOptions(), { gitignore: true, ignoreFiles: ['**/.floydignore'] });
+  t.deepEqual(ignoreGlobOptions(true), { gitignore: false, ignoreFiles: [] });
+});
@@ -0,0 +1,60 @@
+/**
+ * Input History Unit Tests
+ *
+ * Tests for the prompt history and fuzzy reverse search in floyd-agent-core,
+ * shared by both CLIs.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  fuzzyScore,
+  searchInputHistory,
+  loadInputHistory,
+  appendInputHistory,
+} from 'floyd-agent-core/utils';
+
+// ============================================================================
+// Fuzzy Search
+// ============================================================================
+
+test('fuzzyScore: requires characters in order', (t) => {
+  t.not(fuzzyScore('rft', 'refactor tests'), null);
+  t.is(fuzzyScore('tfr', 'refactor'), null);
+});
+
+test('fuzzyScore: substring matches beat scattered matches', (t) => {
+  const substring = fuzzyScore('test', 'run tests')!;
+  const scattered = fuzzyScore('test', 'the eslint setup')!;
+  t.true(substring > scattered);
+});
+
+test('searchInputHistory: ranks matches and dedupes, preferring recent entries', (t) => {
+  const history = ['fix login bug', 'run tests', 'fix lint', 'run tests'];
+  t.deepEqual(searchInputHistory(history, 'run'), ['run tests']);
+  t.deepEqual(searchInputHistory(history, 'fix', 1), ['fix lint']);
+  t.deepEqual(searchInputHistory(history, 'zzz'), []);
+  t.deepEqual(searchInputHistory(history, ''), ['run tests', 'fix lint', 'fix login bug']);
+});
+
+// ============================================================================
+// Persistence
+// ============================================================================
+
+test('appendInputHistory: persists single-line entries across loads', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-history-'));
+  const file = path.join(dir, 'history');
+
+  await appendInputHistory('first prompt', file);
+  await appendInputHistory('multi\nline', file);
+  await appendInputHistory('   ', file);
+
+  t.deepEqual(await loadInputHistory(file), ['first prompt', 'multi line']);
+  await fs.remove(dir);
+});
+
+test('loadInputHistory: returns empty list for missing file', async (t) => {
+  t.deepEqual(await loadInputHistory(path.join(os.tmpdir(), 'floyd-missing-history')), []);
+});
@@ -0,0 +1,103 @@
+/**
+ * Log File Unit Tests
+ *
+ * Tests for the rotating JSON-lines log behind /logs.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  RotatingLogFile,
+  readLogTail,
+  formatLogRecord,
+  toLogData,
+} from '../../../dist/utils/log-file.js';
+import { FloydLogger } from '../../../dist/utils/logger.js';
+
+const record = (level: 'debug' | 'info' | 'warn' | 'error', msg: string) => ({
+  time: '2026-01-02T03:04:05.000Z',
+  level,
+  msg,
+});
+
+test('RotatingLogFile: rotates once the file reaches maxBytes', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-log-'));
+  const file = path.join(dir, 'logs', 'floyd.log');
+  const log = new RotatingLogFile(file, { maxBytes: 200, maxFiles: 3 });
+
+  for (let i = 0; i < 10; i++) {
+    log.write(record('info', `message ${i}`));
+  }
+
+  t.true(await fs.pathExists(file));
+  t.true(await fs.pathExists(`${file}.1`));
+  t.true(await fs.pathExists(`${file}.2`));
+  t.false(await fs.pathExists(`${file}.3`));
+  t.true((await fs.stat(file)).size <= 200);
+
+  await fs.remove(dir);
+});
+
+test('readLogTail: returns the last records at or above a level', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-log-'));
+  const file = path.join(dir, 'floyd.log');
+  const log = new RotatingLogFile(file);
+  log.write(record('debug', 'a'));
+  log.write(record('warn', 'b'));
+  log.write(record('error', 'c'));
+  log.write(record('info', 'd'));
+  await fs.appendFile(file, '{"partial');
+
+  const all = await readLogTail(file, 2);
+  t.deepEqual(all.map(r => r.msg), ['c', 'd']);
+
+  const warnings = await readLogTail(file, 10, 'warn');
+  t.deepEqual(warnings.map(r => r.msg), ['b', 'c']);
+
+  t.deepEqual(await readLogTail(path.join(dir, 'missing.log')), []);
+  await fs.remove(dir);
+});
+
+test('formatLogRecord: time, padded level, message and data', (t) => {
+  t.is(formatLogRecord({ ...record('warn', 'slow'), data: { ms: 5 } }), '03:04:05 WARN  slow {"ms":5}');
+});
+
+test('toLogData: keeps error message and stack', (t) => {
+  const data = toLogData([{ error: new Error('boom') }]) as { error: { message: string; stack?: string } };
+  t.is(data.error.message, 'boom');
+  t.truthy(data.error.stack);
+  t.is(toLogData([]), undefined);
+});
+
+test('FloydLogger: secrets in tool input and output never reach the log file', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-log-'));
+  const file = path.join(dir, 'floyd.log');
+  const secret = 'ghp_' + 'a1B2c3D4e5F6g7H8i9J0k1L2m3N4o5P6q7R8';
+  const logger = new FloydLogger('error');
+  logger.setFile(new RotatingLogFile(file), 'debug');
+
+  logger.tool('run', { command: `curl -H "Authorization: token ${secret}" api` }, { output: `password=${'Zx9!qW3#rT7$yU1'}` });
+  logger.info(`Using ${secret}`);
+
+  const content = await fs.readFile(file, 'utf-8');
+  t.false(content.includes(secret));
+  t.false(content.includes('Zx9!qW3#rT7$yU1'));
+  t.true(content.includes('[REDACTED:github-token]'));
+
+  await fs.remove(dir);
+});
+
+test('FloydLogger: file records at info by default', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-log-'));
+  const file = path.join(dir, 'floyd.log');
+  const logger = new FloydLogger('error');
+  logger.setFile(new RotatingLogFile(file));
+
+  logger.tool('read_file', { file_path: 'a.ts' }, { content: 'x' });
+  logger.info('kept');
+
+  t.deepEqual((await readLogTail(file)).map(r => r.msg), ['kept']);
+  await fs.remove(dir);
+});
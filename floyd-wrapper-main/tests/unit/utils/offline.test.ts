@@ -0,0 +1,47 @@
+/**
+ * Offline Mode Unit Tests
+ *
+ * Tests for deciding when FLOYD starts with model calls disabled.
+ */
+
+import test from 'ava';
+import { detectOffline, describeOffline } from '../../../dist/utils/offline.js';
+import { OfflineClient } from '../../../dist/llm/offline-client.js';
+
+const online = async () => true;
+const unreachable = async () => false;
+const config = { glmApiKey: 'key', glmApiEndpoint: 'https://api.z.ai/api/coding/paas/v4' };
+
+test.serial('detectOffline: requested, missing key, unreachable endpoint, online', async (t) => {
+  const previous = process.env.FLOYD_OFFLINE;
+  delete process.env.FLOYD_OFFLINE;
+  try {
+    t.is(await detectOffline(config, true, online), 'requested');
+    t.is(await detectOffline({ ...config, glmApiKey: '' }, false, online), 'no-api-key');
+    t.is(await detectOffline(config, false, unreachable), 'no-network');
+    t.is(await detectOffline(config, false, online), null);
+
+    process.env.FLOYD_OFFLINE = 'true';
+    t.is(await detectOffline(config, false, online), 'requested');
+  } finally {
+    if (previous === undefined) {
+      delete process.env.FLOYD_OFFLINE;
+    } else {
+      process.env.FLOYD_OFFLINE = previous;
+    }
+  }
+});
+
+test('describeOffline: names the cause', (t) => {
+  t.regex(describeOffline('no-api-key'), /FLOYD_GLM_API_KEY/);
+  t.regex(describeOffline('no-network', config), /api\.z\.ai/);
+});
+
+test('OfflineClient: model calls fail with the explanation', async (t) => {
+  const client = new OfflineClient(describeOffline('requested'));
+  await t.throwsAsync(async () => {
+    for await (const _ of client.streamChat()) {
+      // never yields
+    }
+  }, { name: 'OfflineError', message: /model calls are disabled/ });
+});
@@ -0,0 +1,102 @@
+/**
+ * Run Profiles Unit Tests
+ *
+ * Tests for loading, validating and applying named run profiles.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  BUILTIN_PROFILES,
+  loadProfiles,
+  normalizeProfile,
+  applyProfile,
+  isToolAllowed,
+} from '../../../dist/utils/profiles.js';
+import { getDefaultConfig } from '../../../dist/utils/config.js';
+
+test('loadProfiles: project profiles extend and override built-ins', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-profiles-'));
+  await fs.outputJson(path.join(dir, '.floyd', 'profiles.json'), {
+    'review': { description: 'Read-only review', mode: 'plan', tools: ['file', 'search'] },
+    'quick-answer': { description: 'Local model', model: 'local-7b' },
+  });
+
+  const profiles = loadProfiles(dir);
+  t.truthy(profiles['deep-refactor']);
+  t.is(profiles['review'].mode, 'plan');
+  t.is(profiles['quick-answer'].model, 'local-7b');
+  t.is(profiles['quick-answer'].maxTurns, undefined);
+
+  await fs.remove(dir);
+});
+
+test('normalizeProfile: drops invalid fields', (t) => {
+  const profile = normalizeProfile('odd', { mode: 'turbo', maxTurns: -1, verbosity: 'warn', tools: ['file', 3] });
+  t.deepEqual(profile, { name: 'odd', description: '', verbosity: 'warn', tools: ['file'] });
+});
+
+test('applyProfile: sets model, tools, budgets and mode', (t) => {
+  const saved = process.env.FLOYD_MODE;
+  const config = { ...getDefaultConfig(), cwd: '/work' } as any;
+
+  const next = applyProfile(config, BUILTIN_PROFILES['quick-answer']);
+  t.is(next.profile, 'quick-answer');
+  t.is(next.glmModel, 'glm-4-flash');
+  t.is(next.mode, 'plan');
+  t.is(process.env.FLOYD_MODE, 'plan');
+  t.deepEqual(next.runBudget, { maxTurns: 5, maxTokens: 50_000 });
+  t.is(config.glmModel, 'glm-4.7');
+
+  // Switching again replaces the tool set instead of keeping the old one
+  const deep = applyProfile(next, BUILTIN_PROFILES['deep-refactor']);
+  t.is(deep.allowedTools, undefined);
+
+  if (saved === undefined) {
+    delete process.env.FLOYD_MODE;
+  } else {
+    process.env.FLOYD_MODE = saved;
+  }
+});
+
+test('normalizeProfile: keeps connection settings', (t) => {
+  const profile = normalizeProfile('work', {
+    provider: 'glm',
+    baseUrl: 'https://llm.example.com/v1/',
+    apiKeyEnv: 'WORK_KEY',
+    temperature: 0.2,
+  });
+  t.like(profile, { provider: 'zai', baseUrl: 'https://llm.example.com/v1', apiKeyEnv: 'WORK_KEY', temperature: 0.2 });
+  t.deepEqual(normalizeProfile('odd', { provider: 'nope', baseUrl: 'ftp://x', temperature: 5 }), { name: 'odd', description: '' });
+});
+
+test('applyProfile: switches endpoint, key and temperature for a connection profile', (t) => {
+  const saved = process.env.WORK_KEY;
+  process.env.WORK_KEY = 'work-secret';
+  const config = { ...getDefaultConfig(), glmApiKey: 'glm-key' } as any;
+
+  const work = applyProfile(config, normalizeProfile('work', { provider: 'anthropic', apiKeyEnv: 'WORK_KEY', temperature: 0.1 }));
+  t.is(work.glmApiEndpoint, 'https://api.anthropic.com/v1');
+  t.is(work.glmApiKey, 'work-secret');
+  t.is(work.temperature, 0.1);
+
+  // Profiles without connection settings keep the current one
+  t.is(applyProfile(work, BUILTIN_PROFILES['deep-refactor']).glmApiKey, 'work-secret');
+
+  if (saved === undefined) {
+    delete process.env.WORK_KEY;
+  } else {
+    process.env.WORK_KEY = saved;
+  }
+});
+
+test('isToolAllowed: matches tool names and categories', (t) => {
+  const grep = { name: 'grep', category: 'search' };
+  const commit = { name: 'git_commit', category: 'git' };
+  t.true(isToolAllowed(grep, undefined));
+  t.true(isToolAllowed(grep, ['search']));
+  t.false(isToolAllowed(commit, ['search', 'git_status']));
+  t.true(isToolAllowed(commit, ['git_commit']));
+});
@@ -0,0 +1,107 @@
+/**
+ * Progress Log Unit Tests
+ *
+ * Tests for the structured .floyd/progress.md API in floyd-agent-core and
+ * the /progress table.
+ */
+
+import test from 'ava';
+import os from 'node:os';
+import path from 'node:path';
+import fs from 'fs-extra';
+import {
+  parseProgress,
+  appendProgress,
+  queryProgress,
+  readProgress,
+  formatProgressRow,
+  parseProgressArgs,
+} from 'floyd-agent-core/utils';
+import { renderProgressTable } from '../../../dist/commands/progress-commands.js';
+
+const LOG = `# Progress
+
+| Timestamp | Action | Result | Next Step |
+|---|---|---|---|
+| 2026-10-01 09:00:00 | Add login route | Done | Write tests |
+| 2026-10-02 10:30:00 | Write tests | 2 failing | Fix redirect |
+
+Notes below the table are kept.
+`;
+
+async function projectWith(content?: string): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-progress-'));
+  if (content !== undefined) {
+    await fs.outputFile(path.join(dir, '.floyd', 'progress.md'), content);
+  }
+  return dir;
+}
+
+test('progress: parses entries by column name', (t) => {
+  const entries = parseProgress(LOG);
+
+  t.is(entries.length, 2);
+  t.deepEqual(entries[1], {
+    timestamp: '2026-10-02 10:30:00',
+    action: 'Write tests',
+    result: '2 failing',
+    next: 'Fix redirect',
+  });
+});
+
+test('progress: escapes pipes and line breaks in cells', (t) => {
+  const row = formatProgressRow({ timestamp: 't', action: 'a | b', result: 'line\nbreak', next: '' });
+
+  t.is(row, '| t | a \\| b | line break |  |');
+  t.is(parseProgress(`| Timestamp | Action | Result | Next |\n|---|---|---|---|\n${row}`)[0].action, 'a | b');
+});
+
+test('progress: appends after the last row and skips repeats', async (t) => {
+  const dir = await projectWith(LOG);
+
+  const written = appendProgress({ action: 'Fix redirect', result: 'Done', next: '' }, dir);
+  t.truthy(written);
+  t.is(appendProgress({ action: 'fix  redirect', result: 'done' }, dir), null);
+
+  const content = await fs.readFile(path.join(dir, '.floyd', 'progress.md'), 'utf-8');
+  t.regex(content, /Fix redirect \| Done \|  \|\n\nNotes below the table are kept\./);
+  t.deepEqual(readProgress(dir).map(entry => entry.action), ['Add login route', 'Write tests', 'Fix redirect']);
+});
+
+test('progress: creates the log with a header', async (t) => {
+  const dir = await projectWith();
+
+  appendProgress({ action: 'Start', timestamp: '2026-10-03 08:00:00' }, dir);
+
+  t.deepEqual(readProgress(dir), [{ timestamp: '2026-10-03 08:00:00', action: 'Start', result: '', next: '' }]);
+});
+
+test('progress: queries by date, text and count', (t) => {
+  const entries = parseProgress(LOG);
+
+  t.is(queryProgress(entries, { date: '2026-10-02' }).length, 1);
+  t.is(queryProgress(entries, { search: 'REDIRECT' })[0].action, 'Write tests');
+  t.deepEqual(queryProgress(entries, { last: 1 }).map(entry => entry.action), ['Write tests']);
+  t.is(queryProgress(entries, { last: 0 }).length, 0);
+});
+
+test('progress: /progress arguments (shared by both CLIs)', (t) => {
+  t.deepEqual(parseProgressArgs([]), {});
+  t.deepEqual(parseProgressArgs(['5', '--date', '2026-10', 'login', 'route']), { last: 5, date: '2026-10', search: 'login route' });
+  t.deepEqual(parseProgressArgs(['fix', '2']), { search: 'fix 2' });
+  t.deepEqual(parseProgressArgs(['-d', '2026-10-01', '3']), { date: '2026-10-01', last: 3 });
+});
+
+test('progress: template headers map to their fields', (t) => {
+  const [entry] = parseProgress('| Timestamp | Next Step | Action Taken | Result/Status |\n|---|---|---|---|\n| t | n | a | r |');
+
+  t.deepEqual(entry, { timestamp: 't', action: 'a', result: 'r', next: 'n' });
+});
+
+test('progress: table fits the width, keeping timestamps whole', (t) => {
+  const lines = renderProgressTable(parseProgress(LOG), 60);
+
+  t.true(lines[0].startsWith('Timestamp'));
+  t.true(lines.every(line => line.length <= 60));
+  t.true(lines[2].startsWith('2026-10-01 09:00:00'));
+});
@@ -0,0 +1,93 @@
+/**
+ * Project Instructions Unit Tests
+ *
+ * Tests for discovering FLOYD.md, AGENTS.md and CLAUDE.md at the project
+ * root and in nested directories.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  loadProjectInstructions,
+  NestedInstructions,
+} from '../../../dist/utils/project-instructions.js';
+
+async function makeProject(files: Record<string, string>): Promise<string> {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-instructions-'));
+  for (const [name, content] of Object.entries(files)) {
+    await fs.outputFile(path.join(dir, name), content);
+  }
+  return dir;
+}
+
+test('loadProjectInstructions: merges root files and skips duplicates', async (t) => {
+  const dir = await makeProject({
+    'FLOYD.md': 'Use tabs.',
+    'AGENTS.md': 'Run npm test before committing.',
+    'CLAUDE.md': 'Run npm test before committing.',
+  });
+
+  t.is(
+    loadProjectInstructions(dir),
+    '### FLOYD.md\n\nUse tabs.\n\n### AGENTS.md\n\nRun npm test before committing.'
+  );
+  await fs.remove(dir);
+});
+
+test('loadProjectInstructions: adds .floyd/AGENT_INSTRUCTIONS.md last', async (t) => {
+  const dir = await makeProject({
+    'FLOYD.md': 'Use tabs.',
+    '.floyd/AGENT_INSTRUCTIONS.md': 'Answer in British English.\n',
+  });
+
+  t.is(
+    loadProjectInstructions(dir),
+    `### FLOYD.md\n\nUse tabs.\n\n### ${path.join('.floyd', 'AGENT_INSTRUCTIONS.md')}\n\nAnswer in British English.`
+  );
+  await fs.remove(dir);
+});
+
+test('loadProjectInstructions: includes the master plan and stack', async (t) => {
+  const dir = await makeProject({
+    '.floyd/master_plan.md': '# Master Plan\n- [ ] Ship it\n',
+    '.floyd/stack.md': 'Node 20, ava',
+  });
+
+  t.is(
+    loadProjectInstructions(dir),
+    `### ${path.join('.floyd', 'master_plan.md')}\n\n# Master Plan\n- [ ] Ship it\n\n### ${path.join('.floyd', 'stack.md')}\n\nNode 20, ava`
+  );
+  await fs.remove(dir);
+});
+
+test('loadProjectInstructions: returns undefined without instruction files', async (t) => {
+  const dir = await makeProject({ 'README.md': 'Hello' });
+  t.is(loadProjectInstructions(dir), undefined);
+  await fs.remove(dir);
+});
+
+test('NestedInstructions: returns files between root and target once', async (t) => {
+  const dir = await makeProject({
+    'AGENTS.md': 'root rules',
+    'packages/AGENTS.md': 'package rules',
+    'packages/api/CLAUDE.md': 'api rules',
+    'packages/api/src/index.ts': '',
+  });
+  const nested = new NestedInstructions(dir);
+
+  const first = nested.collect('packages/api/src/index.ts');
+  t.deepEqual(first.map(f => f.relativePath), [
+    path.join('packages', 'AGENTS.md'),
+    path.join('packages', 'api', 'CLAUDE.md'),
+  ]);
+
+  // Already shown, and paths outside the project are ignored
+  t.deepEqual(nested.collect(path.join(dir, 'packages', 'api')), []);
+  t.deepEqual(nested.collect('/etc/hosts'), []);
+
+  nested.reset();
+  t.is(nested.collect('packages/api').length, 2);
+  await fs.remove(dir);
+});
@@ -0,0 +1,87 @@
+/**
+ * Repository Map Unit Tests
+ *
+ * Tests for symbol extraction, module detection, slicing the map for a
+ * request and refreshing it after files change.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  RepoMapIndex,
+  extractSymbols,
+  readModuleName,
+  stripRepoMapSection,
+} from '../../../dist/utils/repo-map.js';
+
+async function makeProject(): Promise<string> {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-repo-map-'));
+  await fs.outputJson(path.join(root, 'package.json'), { name: 'demo-app' });
+  await fs.outputFile(path.join(root, 'go.mod'), 'module example.com/demo\n\ngo 1.22\n');
+  await fs.outputFile(path.join(root, 'src', 'parser.ts'), 'export function parseQuery() {}\nexport class Tokenizer {}\nfunction helper() {}\n');
+  await fs.outputFile(path.join(root, 'src', 'server.ts'), 'export const startServer = () => {};\n');
+  await fs.outputFile(path.join(root, 'src', 'parser.test.ts'), 'export const ignored = 1;\n');
+  await fs.outputFile(path.join(root, 'pkg', 'store', 'store.go'), 'package store\n\ntype Store struct{}\n\nfunc (s *Store) Get() {}\nfunc New() *Store { return nil }\nfunc private() {}\n');
+  return root;
+}
+
+test('extractSymbols: exported TypeScript and Go symbols only', (t) => {
+  t.deepEqual(
+    extractSymbols('a.ts', 'export function parseQuery() {}\nexport interface Options {}\nconst local = 1;\n'),
+    ['parseQuery()', 'Options'],
+  );
+  t.deepEqual(
+    extractSymbols('a.go', 'type Store struct{}\nfunc (s *Store) Get() {}\nfunc New() {}\nfunc hidden() {}\n'),
+    ['Store', 'Store.Get()', 'New()'],
+  );
+});
+
+test('readModuleName: package.json and go.mod', (t) => {
+  t.is(readModuleName('package.json', '{"name":"demo-app"}'), 'demo-app');
+  t.is(readModuleName('go.mod', 'module example.com/demo\n'), 'example.com/demo');
+  t.is(readModuleName('package.json', 'not json'), undefined);
+});
+
+test('getSlice: lists modules and puts files matching the request first', async (t) => {
+  const index = new RepoMapIndex(await makeProject());
+  t.true(await index.refresh());
+
+  const slice = index.getSlice('fix the store Get method');
+  t.regex(slice, /- demo-app \(package\.json\)/);
+  t.regex(slice, /- example\.com\/demo \(go\.mod\)/);
+  t.notRegex(slice, /parser\.test\.ts/);
+
+  const files = slice.split('\n').filter(line => line.startsWith('- ') && line.includes(': '));
+  t.true(files[0]!.startsWith('- pkg/store/store.go: Store, Store.Get(), New()'));
+
+  t.is(stripRepoMapSection(`You are Floyd.\n\n${slice}`), 'You are Floyd.');
+});
+
+test('getSlice: stays within the character budget', async (t) => {
+  const index = new RepoMapIndex(await makeProject());
+  await index.refresh();
+
+  const slice = index.getSlice('', 200);
+  t.regex(slice, /more files not shown/);
+});
+
+test('refresh: re-reads only changed files and reuses the cache', async (t) => {
+  const root = await makeProject();
+  await new RepoMapIndex(root).refresh();
+  t.true(await fs.pathExists(path.join(root, '.floyd', 'repo-map.json')));
+
+  // A new index starts from the cache: nothing changed
+  const index = new RepoMapIndex(root);
+  t.false(await index.refresh());
+
+  const server = path.join(root, 'src', 'server.ts');
+  await fs.writeFile(server, 'export function stopServer() {}\n');
+  const later = new Date(Date.now() + 5000);
+  await fs.utimes(server, later, later);
+
+  t.true(await index.refresh());
+  const file = index.getMap().files.find(entry => entry.path === 'src/server.ts');
+  t.deepEqual(file?.symbols, ['stopServer()']);
+});
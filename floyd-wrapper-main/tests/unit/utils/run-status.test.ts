@@ -0,0 +1,58 @@
+/**
+ * Run Status Unit Tests
+ *
+ * Tests for the status file read by `floyd status --porcelain`.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  RunStatusReporter,
+  readRunStatus,
+  formatRunStatusPorcelain,
+} from '../../../dist/utils/run-status.js';
+
+test('formatRunStatusPorcelain: reports idle when no status file exists', (t) => {
+  t.is(formatRunStatusPorcelain(null), 'idle\t0\t-\t-');
+});
+
+test('formatRunStatusPorcelain: tab-separated state, seconds, pid and cwd', (t) => {
+  const line = formatRunStatusPorcelain({ state: 'running', since: 0, pid: 42, cwd: '/work' }, 90_000);
+  t.is(line, 'running\t90\t42\t/work');
+});
+
+test('RunStatusReporter: writes state and clears its own file', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-status-'));
+  const file = path.join(dir, 'status.json');
+  const reporter = new RunStatusReporter(file);
+
+  reporter.set('awaiting-approval', 'dangerous');
+  const status = await readRunStatus(file);
+  t.is(status?.state, 'awaiting-approval');
+  t.is(status?.detail, 'dangerous');
+  t.is(status?.pid, process.pid);
+
+  reporter.clear();
+  t.false(await fs.pathExists(file));
+  await fs.remove(dir);
+});
+
+test.serial('RunStatusReporter: never rings the bell itself', async (t) => {
+  const dir = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-status-'));
+  const reporter = new RunStatusReporter(path.join(dir, 'status.json'));
+  const written: string[] = [];
+  const write = process.stdout.write;
+  process.stdout.write = ((chunk: string) => written.push(String(chunk)) > 0) as typeof process.stdout.write;
+  try {
+    reporter.set('awaiting-approval', 'diff');
+    reporter.set('done');
+  } finally {
+    process.stdout.write = write;
+  }
+
+  t.false(written.join('').includes('\x07'));
+  reporter.clear();
+  await fs.remove(dir);
+});
@@ -0,0 +1,113 @@
+/**
+ * Tool Profiles Unit Tests
+ *
+ * Tests for the named tool subsets (reader, editor, admin) a session or
+ * batch task can be locked to.
+ */
+
+import test from 'ava';
+import fs from 'fs-extra';
+import os from 'node:os';
+import path from 'node:path';
+import {
+  BUILTIN_TOOL_PROFILES,
+  normalizeToolProfile,
+  loadToolProfiles,
+  isToolInProfile,
+  createToolProfileFilter,
+} from '../../../dist/utils/tool-profiles.js';
+import { toolRegistry } from '../../../dist/tools/tool-registry.js';
+import { registerCoreTools } from '../../../dist/tools/index.js';
+
+const readFile = { name: 'read_file', category: 'file', permission: 'none' } as const;
+const editFile = { name: 'edit_file', category: 'file', permission: 'dangerous' } as const;
+const run = { name: 'run', category: 'build', permission: 'dangerous' } as const;
+const verify = { name: 'verify', category: 'special', permission: 'none' } as const;
+const safeRefactor = { name: 'safe_refactor', category: 'special', permission: 'dangerous' } as const;
+const dbQuery = { name: 'db_query', category: 'build', permission: 'moderate' } as const;
+const customTool = { name: 'deploy_preview', category: 'custom', permission: 'none' } as const;
+const format = { name: 'format', category: 'build', permission: 'moderate' } as const;
+
+/**
+ * Tools that only read: no files, caches or git state written, no commands
+ * run. env may set a variable for the session, but only with a value the
+ * user types in.
+ */
+const SIDE_EFFECT_FREE = new Set([
+  'read_file', 'list_directory', 'grep', 'codebase_search', 'symbols',
+  'git_status', 'git_diff', 'git_log', 'is_protected_branch',
+  'ps_ports', 'env', 'ask_user', 'browser_status',
+  'impact_simulate', 'assess_patch_risk',
+]);
+
+test('isToolInProfile: reader is read-only', (t) => {
+  const reader = BUILTIN_TOOL_PROFILES.reader;
+  t.true(isToolInProfile(readFile, reader));
+  t.false(isToolInProfile(editFile, reader));
+  t.false(isToolInProfile(run, reader));
+  t.false(isToolInProfile(verify, reader));
+});
+
+test('isToolInProfile: editor edits files but runs no commands, admin has everything', (t) => {
+  t.true(isToolInProfile(editFile, BUILTIN_TOOL_PROFILES.editor));
+  t.false(isToolInProfile(run, BUILTIN_TOOL_PROFILES.editor));
+  t.true(isToolInProfile(run, BUILTIN_TOOL_PROFILES.admin));
+});
+
+test('isToolInProfile: editor and reader leave out tools that reach a shell or the network', (t) => {
+  for (const tool of [verify, safeRefactor, dbQuery, customTool]) {
+    t.false(isToolInProfile(tool, BUILTIN_TOOL_PROFILES.editor), tool.name);
+  }
+  t.false(isToolInProfile(customTool, BUILTIN_TOOL_PROFILES.reader));
+});
+
+test('isToolInProfile: editor can not run the formatter', (t) => {
+  t.false(isToolInProfile(format, BUILTIN_TOOL_PROFILES.editor));
+});
+
+test('isToolInProfile: every registered tool reader allows is side-effect free', (t) => {
+  registerCoreTools();
+
+  const allowed = toolRegistry.getAll().filter(tool => isToolInProfile(tool, BUILTIN_TOOL_PROFILES.reader));
+  t.true(allowed.length > 0);
+  for (const tool of allowed) {
+    t.true(SIDE_EFFECT_FREE.has(tool.name), `reader allows ${tool.name}`);
+  }
+  for (const name of ['todo', 'remember', 'cache_store', 'cache_store_pattern', 'cache_store_reasoning', 'cache_retrieve']) {
+    t.false(allowed.some(tool => tool.name === name), name);
+  }
+});
+
+test('isToolInProfile: tools and exclude match names or categories', (t) => {
+  const docs = normalizeToolProfile('docs', { tools: ['file'], exclude: ['edit_file'] });
+  t.true(isToolInProfile(readFile, docs));
+  t.false(isToolInProfile(editFile, docs));
+  t.false(isToolInProfile(run, docs));
+});
+
+test('normalizeToolProfile: drops invalid fields', (t) => {
+  const profile = normalizeToolProfile('odd', { maxPermission: 'root', tools: ['file', 3], exclude: 'run' });
+  t.deepEqual(profile, { name: 'odd', description: '', tools: ['file'] });
+});
+
+test('loadToolProfiles: project file adds profiles but can not redefine built-ins', async (t) => {
+  const root = await fs.mkdtemp(path.join(os.tmpdir(), 'floyd-tool-profiles-'));
+  await fs.outputJson(path.join(root, '.floyd', 'tool-profiles.json'), {
+    reviewer: { description: 'Review', maxPermission: 'none' },
+    reader: { description: 'Everything', maxPermission: 'dangerous' },
+    editor: { description: 'Everything' },
+  });
+
+  const profiles = loadToolProfiles(root);
+  t.is(profiles.reviewer.maxPermission, 'none');
+  t.deepEqual(profiles.reader, BUILTIN_TOOL_PROFILES.reader);
+  t.deepEqual(profiles.editor, BUILTIN_TOOL_PROFILES.editor);
+
+  await fs.remove(root);
+});
+
+test('createToolProfileFilter: no profile allows all, an unknown one allows nothing', (t) => {
+  t.true(createToolProfileFilter(undefined)(run));
+  t.false(createToolProfileFilter('no-such-profile')(readFile));
+  t.true(createToolProfileFilter('reader')(readFile));
+});
@@ -0,0 +1,85 @@
+/**
+ * Tracing Unit Tests
+ *
+ * Tests for span creation and OTLP/HTTP JSON encoding.
+ */
+
+import test from 'ava';
+import {
+  Tracer,
+  getTracerOptionsFromEnv,
+  parseOtlpHeaders,
+  toOtlpPayload,
+  type FinishedSpan,
+} from '../../../dist/utils/tracing.js';
+
+const options = { url: 'http://collector/v1/traces', headers: {}, serviceName: 'floyd' };
+
+test('getTracerOptionsFromEnv: disabled unless an endpoint or FLOYD_OTEL is set', (t) => {
+  t.false(getTracerOptionsFromEnv({}).enabled);
+  t.true(getTracerOptionsFromEnv({ FLOYD_OTEL: 'true' }).enabled);
+  t.is(getTracerOptionsFromEnv({ FLOYD_OTEL: 'true' }).url, 'http://localhost:4318/v1/traces');
+
+  const fromEndpoint = getTracerOptionsFromEnv({ OTEL_EXPORTER_OTLP_ENDPOINT: 'https://otel.example.com/' });
+  t.true(fromEndpoint.enabled);
+  t.is(fromEndpoint.url, 'https://otel.example.com/v1/traces');
+
+  t.false(getTracerOptionsFromEnv({ OTEL_EXPORTER_OTLP_ENDPOINT: 'http://x', FLOYD_OTEL: 'false' }).enabled);
+});
+
+test('parseOtlpHeaders: splits comma-separated key=value pairs', (t) => {
+  t.deepEqual(parseOtlpHeaders('a=1, b=x%3Dy'), { a: '1', b: 'x=y' });
+  t.deepEqual(parseOtlpHeaders(undefined), {});
+});
+
+test('Tracer: child spans share the trace and record errors', async (t) => {
+  const tracer = new Tracer({ ...options, enabled: true, batchSize: 1000 });
+  const ended: FinishedSpan[] = [];
+  tracer.onSpanEnd = (span: FinishedSpan) => { ended.push(span); };
+
+  const run = tracer.startSpan('agent.run');
+  const tool = tracer.startSpan('tool.execute', run, { 'tool.name': 'read_file', skipped: undefined });
+  tool.recordError(new Error('ENOENT'));
+  tool.end();
+  tool.end();
+  run.end();
+
+  t.is(ended.length, 2);
+  t.is(ended[0].traceId, run.traceId);
+  t.is(ended[0].parentSpanId, run.spanId);
+  t.is(ended[0].attributes['tool.name'], 'read_file');
+  t.false('skipped' in ended[0].attributes);
+  t.is(ended[0].error, 'ENOENT');
+  t.is(typeof ended[0].attributes['duration_ms'], 'number');
+  t.is(ended[1].parentSpanId, undefined);
+});
+
+test('Tracer: spans are no-ops when disabled', (t) => {
+  const tracer = new Tracer({ ...options, enabled: false });
+  const span = tracer.startSpan('agent.run');
+  t.false(span.isRecording());
+  t.notThrows(() => span.end());
+});
+
+test('toOtlpPayload: encodes attributes and status', (t) => {
+  const payload = toOtlpPayload([{
+    traceId: 'a'.repeat(32),
+    spanId: 'b'.repeat(16),
+    name: 'tool.execute',
+    kind: 'internal',
+    startTimeUnixNano: '1',
+    endTimeUnixNano: '2',
+    attributes: { 'tool.name': 'run', 'duration_ms': 12, ratio: 0.5, 'tool.success': false },
+    error: 'exit 1',
+  }], 'floyd') as any;
+
+  t.deepEqual(payload.resourceSpans[0].resource.attributes[0], { key: 'service.name', value: { stringValue: 'floyd' } });
+  const span = payload.resourceSpans[0].scopeSpans[0].spans[0];
+  t.deepEqual(span.attributes, [
+    { key: 'tool.name', value: { stringValue: 'run' } },
+    { key: 'duration_ms', value: { intValue: '12' } },
+    { key: 'ratio', value: { doubleValue: 0.5 } },
+    { key: 'tool.success', value: { boolValue: false } },
+  ]);
+  t.deepEqual(span.status, { code: 2, message: 'exit 1' });
+});
@@ -8,12 +8,15 @@
 
 import type { MCPClientManager } from '../mcp/client-manager.js';
 import type { ISessionManager, IPermissionManager, IConfig, SessionData } from './interfaces.js';
-import type { Message, ToolCall, AgentEvent } from './types.js';
-import { createLLMClient, type LLMClient, type StreamChunk, type LLMMessage, type LLMTool } from '../llm/index.js';
+import type { Message, ToolCall, AgentEvent, TimingEvent } from './types.js';
+import { createLLMClient, type LLMClient, type StreamChunk, type LLMMessage, type LLMImage, type LLMTool, type StreamingMode, type StreamingFallbackEvent } from '../llm/index.js';
 import { PROVIDER_DEFAULTS, inferProviderFromEndpoint, type Provider } from '../constants.js';
+import { INTERRUPTED_MARKER, markStoppedReply, type StopReason } from '../ui/chat-state.js';
+import { ToolMetrics, type ToolStats } from '../utils/tool-metrics.js';
+import { scheduleToolCalls, getToolConcurrency } from './tool-scheduler.js';
 
 // Re-export types from types.ts for convenience
-export type { Message, ToolCall, AgentEvent } from './types.js';
+export type { Message, ToolCall, AgentEvent, TimingEvent } from './types.js';
 export type { StreamChunk } from '../llm/types.js';
 // Re-export interfaces for consumers
 export type { ISessionManager, IPermissionManager, IConfig, SessionData } from './interfaces.js';
@@ -27,15 +30,54 @@ export interface AgentEngineOptions {
   // Provider-specific options
   defaultHeaders?: Record<string, string>;
   temperature?: number;
+  /** Text that ends a reply when the model writes it */
+  stopSequences?: string[];
   enableThinkingMode?: boolean;
   outputFormat?: 'ansi' | 'plain' | 'markdown';
   provider?: Provider;
+  /** Streaming behavior; 'auto' falls back to non-streaming when a proxy rejects it */
+  streaming?: StreamingMode;
+  /** Told when the client falls back to non-streaming requests or resumes streaming */
+  onStreamingFallback?: (event: StreamingFallbackEvent) => void;
+  /** Tool calls from one reply run at once (default FLOYD_TOOL_CONCURRENCY or 4); 1 runs them in order */
+  toolConcurrency?: number;
+  /** Client to use instead of one built from the options (e.g. FakeLLMClient in tests) */
+  llmClient?: LLMClient;
+}
+
+/**
+ * Request settings used for every following message (e.g. /set max_tokens)
+ */
+export interface RequestParams {
+  maxTokens: number;
+  temperature: number;
+  stopSequences: string[];
+}
+
+/**
+ * Settings that differ for one sendMessage() call (e.g. /retry --temp 0.2)
+ */
+export interface RequestOverrides {
+  model?: string;
+  temperature?: number;
+}
+
+/**
+ * A user message taken back out of the history, to be sent again
+ */
+export interface RewoundMessage {
+  content: string;
+  images?: LLMImage[];
 }
 
 export interface AgentCallbacks {
   onChunk?: (chunk: string) => void;
+  /** Reasoning the model streams before its answer (kept out of the reply) */
+  onThinking?: (text: string) => void;
   onToolStart?: (toolCall: ToolCall) => void;
   onToolComplete?: (toolCall: ToolCall) => void;
+  /** Request and tool timings (request sent, first token, completion) */
+  onTiming?: (event: TimingEvent) => void;
   onDone?: () => void;
   onError?: (error: Error) => void;
 }
@@ -63,16 +105,24 @@ export class AgentEngine {
   private config: IConfig;
   private currentSession: SessionData | null = null;
   public history: Message[] = [];
+  // Reply text of the request being streamed, until it is added to the history
+  private partialContent: string | null = null;
+  private toolMetrics = new ToolMetrics();
+  // Settings the client was built with, for clients with overridden settings
+  private clientOptions: Parameters<typeof createLLMClient>[0];
+  private customClient: boolean;
 
   // Options
   private model: string;
   private maxTokens: number;
   private maxTurns: number;
   private temperature: number;
+  private stopSequences: string[];
   private enableThinkingMode: boolean;
   private outputFormat: 'ansi' | 'plain' | 'markdown';
   private provider: Provider;
   private baseURL: string;
+  private toolConcurrency: number;
 
   constructor(
     options: AgentEngineOptions,
@@ -96,19 +146,26 @@ export class AgentEngine {
     this.maxTokens = options.maxTokens ?? 8192;
     this.maxTurns = options.maxTurns ?? 10;
     this.temperature = options.temperature ?? 0.2;
+    this.stopSequences = options.stopSequences ?? [];
     this.enableThinkingMode = options.enableThinkingMode ?? true;
     this.outputFormat = options.outputFormat ?? 'plain';
+    this.toolConcurrency = options.toolConcurrency ?? getToolConcurrency();
 
     // Create LLM client using factory
-    this.llmClient = createLLMClient({
+    this.clientOptions = {
       apiKey: options.apiKey,
       baseURL: this.baseURL,
       model: this.model,
       maxTokens: this.maxTokens,
       temperature: this.temperature,
+      stopSequences: this.stopSequences,
       defaultHeaders: options.defaultHeaders,
       provider: this.provider,
-    });
+      streaming: options.streaming,
+      onStreamingFallback: options.onStreamingFallback,
+    };
+    this.customClient = options.llmClient !== undefined;
+    this.llmClient = options.llmClient ?? createLLMClient(this.clientOptions);
   }
 
   /**
@@ -239,6 +296,7 @@ export class AgentEngine {
 
       // Handle simple text messages
       let content: string;
+      let images: LLMImage[] | undefined;
       if (typeof msg.content === 'string') {
         content = msg.content;
       } else if (Array.isArray(msg.content)) {
@@ -247,6 +305,11 @@ export class AgentEngine {
           .filter((block: any) => block.type === 'text')
           .map((block: any) => block.text)
           .join('\n');
+        // Image blocks from sendMessage(..., images)
+        const imageBlocks = msg.content.filter((block: any) => block.type === 'image');
+        if (imageBlocks.length > 0) {
+          images = imageBlocks.map((block: any) => ({ mediaType: block.source.media_type, data: block.source.data }));
+        }
       } else {
         content = String(msg.content);
       }
@@ -254,6 +317,7 @@ export class AgentEngine {
       return {
         role: msg.role as 'system' | 'user' | 'assistant',
         content,
+        ...(images && { images }),
       };
     });
   }
@@ -263,12 +327,41 @@ export class AgentEngine {
    *
    * This is the main method for interacting with the agent.
    * It yields chunks of the response as they arrive.
+   *
+   * @param images - Images to send with this message (kept in the history
+   *   as Anthropic-style image blocks)
+   * @param overrides - Model or temperature for this message only (ignored
+   *   when the engine was given its own llmClient)
    */
-  async *sendMessage(content: string, callbacks?: AgentCallbacks): AsyncGenerator<string, void, unknown> {
+  async *sendMessage(
+    content: string,
+    callbacks?: AgentCallbacks,
+    images?: LLMImage[],
+    overrides?: RequestOverrides
+  ): AsyncGenerator<string, void, unknown> {
     console.log('[AgentEngine] sendMessage called with:', content.slice(0, 50));
 
+    const overridden = !this.customClient && (overrides?.model !== undefined || overrides?.temperature !== undefined);
+    const model = (overridden && overrides?.model) || this.model;
+    const llmClient = overridden
+      ? createLLMClient({ ...this.clientOptions, model, temperature: overrides?.temperature ?? this.temperature })
+      : this.llmClient;
+
     // Add user message to history
-    this.history.push({ role: 'user', content });
+    if (images?.length) {
+      this.history.push({
+        role: 'user',
+        content: [
+          ...images.map((image) => ({
+            type: 'image',
+            source: { type: 'base64', media_type: image.mediaType, data: image.data },
+          })),
+          { type: 'text', text: content },
+        ],
+      });
+    } else {
+      this.history.push({ role: 'user', content });
+    }
 
     // Save session with user message
     if (this.currentSession) {
@@ -302,16 +395,39 @@ export class AgentEngine {
       console.log('[AgentEngine] Converted to', messages.length, 'LLM messages');
 
       let assistantContent = '';
+      this.partialContent = '';
       let toolCalls: ToolCall[] = [];
       let currentToolId: string | null = null;
 
+      // Request timing
+      const requestStart = Date.now();
+      let firstTokenMs: number | undefined;
+      let usage: StreamChunk['usage'];
+      let requestError: string | undefined;
+      callbacks?.onTiming?.({ type: 'request_sent', turn: turns, model, at: requestStart });
+
       // Stream from LLM client
       try {
         console.log('[AgentEngine] Calling llmClient.chat...');
-        for await (const chunk of this.llmClient.chat(messages, tools)) {
+        for await (const chunk of llmClient.chat(messages, tools)) {
+          if (firstTokenMs === undefined && (chunk.token || chunk.thinking || chunk.tool_call)) {
+            const now = Date.now();
+            firstTokenMs = now - requestStart;
+            callbacks?.onTiming?.({ type: 'first_token', turn: turns, at: now, latencyMs: firstTokenMs });
+          }
+          if (chunk.usage) {
+            usage = chunk.usage;
+          }
+
+          // Reasoning goes to its own callback, not into the reply
+          if (chunk.thinking) {
+            callbacks?.onThinking?.(chunk.thinking);
+          }
+
           // Handle text tokens
           if (chunk.token) {
             assistantContent += chunk.token;
+            this.partialContent = assistantContent;
             yield chunk.token;
             callbacks?.onChunk?.(chunk.token);
           }
@@ -335,6 +451,7 @@ export class AgentEngine {
 
           // Handle errors
           if (chunk.error) {
+            requestError = chunk.error;
             const error = new Error(chunk.error);
             callbacks?.onError?.(error);
             yield `\n[Error: ${chunk.error}]\n`;
@@ -347,12 +464,15 @@ export class AgentEngine {
         }
       } catch (error: any) {
         const errorMessage = error?.message || String(error);
+        this.emitRequestComplete(callbacks, turns, requestStart, firstTokenMs, usage, errorMessage);
         callbacks?.onError?.(error instanceof Error ? error : new Error(errorMessage));
         yield `\n[Error: ${errorMessage}]\n`;
         currentTurnDone = true;
         continue;
       }
 
+      this.emitRequestComplete(callbacks, turns, requestStart, firstTokenMs, usage, requestError);
+
       // Build assistant message
       const assistantMessage: Message = {
         role: 'assistant',
@@ -374,6 +494,7 @@ export class AgentEngine {
 
       // Add assistant message to history
       this.history.push(assistantMessage);
+      this.partialContent = null;
       if (this.currentSession) {
         this.currentSession.messages = this.history;
         await this.sessionManager.saveSession(this.currentSession);
@@ -381,67 +502,48 @@ export class AgentEngine {
 
       // Process tool calls if any
       if (toolCalls.length > 0) {
-        for (const tc of toolCalls) {
+        // Results go into the history in call order once the batch is done
+        const results: Message[] = new Array(toolCalls.length);
+        const allowed: Array<{ tc: ToolCall; index: number; name: string; input: unknown }> = [];
+
+        // Permission checks may prompt, so they stay one at a time
+        for (const [index, tc] of toolCalls.entries()) {
           yield `\n[Requesting tool: ${tc.name}]\n`;
 
-          // Permission check
           const permission = await this.permissionManager.checkPermission(tc.name);
           if (permission === 'deny') {
             yield `\n[Permission denied for tool: ${tc.name}]\n`;
-            this.history.push({
-              role: 'user',
-              content: [
-                {
-                  type: 'tool_result',
-                  tool_use_id: tc.id,
-                  content: 'Error: Permission denied by user configuration.',
-                },
-              ],
-            });
+            results[index] = this.toolResultMessage(tc.id, 'Error: Permission denied by user configuration.');
             continue;
           }
+          allowed.push({ tc, index, name: tc.name, input: tc.input });
+        }
 
-          // Execute tool
+        // Read-only calls run side by side; calls on the same paths keep their order
+        await scheduleToolCalls(allowed, async ({ tc, index }) => {
           callbacks?.onToolStart?.(tc);
+          const toolStart = Date.now();
 
           try {
             const result = await this.mcpManager.callTool(tc.name, tc.input);
             const rawOutput = typeof result === 'string' ? result : JSON.stringify(result);
             const truncatedOutput = this.truncateOutput(rawOutput);
 
-            const toolResultMessage: Message = {
-              role: 'user',
-              content: [
-                {
-                  type: 'tool_result',
-                  tool_use_id: tc.id,
-                  content: truncatedOutput,
-                },
-              ],
-            };
-
-            this.history.push(toolResultMessage);
+            results[index] = this.toolResultMessage(tc.id, truncatedOutput);
 
             tc.status = 'completed';
             tc.output = truncatedOutput;
-            callbacks?.onToolComplete?.(tc);
           } catch (error: any) {
-            this.history.push({
-              role: 'user',
-              content: [
-                {
-                  type: 'tool_result',
-                  tool_use_id: tc.id,
-                  content: `Error: ${error.message}`,
-                },
-              ],
-            });
+            results[index] = this.toolResultMessage(tc.id, `Error: ${error.message}`);
 
             tc.status = 'failed';
             tc.error = error.message;
-            callbacks?.onToolComplete?.(tc);
           }
-        }
+          this.emitToolTiming(callbacks, tc, toolStart);
+          callbacks?.onToolComplete?.(tc);
+        }, { concurrency: this.toolConcurrency });
+
+        this.history.push(...results);
       } else {
         currentTurnDone = true;
       }
@@ -450,6 +552,180 @@ export class AgentEngine {
     callbacks?.onDone?.();
   }
 
+  /**
+   * Repair the history after the caller stopped a sendMessage() stream early
+   * (to steer with a new instruction, or cancelled with `reason`
+   * 'cancelled'). Call it once the generator has returned. The partial reply
+   * is kept, so "keep going" continues it, and tool calls that never ran get
+   * a "cancelled" result so the next request is well-formed.
+   */
+  async recordInterruption(reason: StopReason = 'interrupted'): Promise<void> {
+    if (this.partialContent !== null) {
+      const partial = this.partialContent;
+      this.partialContent = null;
+      this.history.push({
+        role: 'assistant',
+        content: markStoppedReply(partial, reason),
+      });
+    } else {
+      const lastAssistant = this.history.map(m => m.role).lastIndexOf('assistant');
+      const blocks = lastAssistant === -1 ? [] : this.history[lastAssistant].content;
+      const answered = new Set(
+        this.history
+          .slice(lastAssistant + 1)
+          .flatMap(m => (Array.isArray(m.content) ? m.content : []))
+          .filter((block: any) => block.type === 'tool_result')
+          .map((block: any) => block.tool_use_id)
+      );
+      for (const block of Array.isArray(blocks) ? blocks : []) {
+        if (block.type === 'tool_use' && !answered.has(block.id)) {
+          this.history.push({
+            role: 'user',
+            content: [{ type: 'tool_result', tool_use_id: block.id, content: `Cancelled: ${INTERRUPTED_MARKER}` }],
+          });
+        }
+      }
+    }
+
+    if (this.currentSession) {
+      this.currentSession.messages = this.history;
+      await this.sessionManager.saveSession(this.currentSession);
+    }
+  }
+
+  /**
+   * Take the last exchange back out of the history: the last user message
+   * and everything after it (the reply, its tool calls and their results).
+   * Returns the user message so it can be sent again, or null when there is
+   * none.
+   */
+  async rewindLastTurn(): Promise<RewoundMessage | null> {
+    return this.rewindTo(0);
+  }
+
+  /**
+   * Like rewindLastTurn(), but from an earlier prompt: `fromEnd` counts the
+   * user's prompts back from the last one (0 is the last). Everything from
+   * that prompt on is removed, e.g. to send an edited version of it.
+   */
+  async rewindTo(fromEnd: number): Promise<RewoundMessage | null> {
+    const index = this.findPrompt(fromEnd);
+    if (index < 0) {
+      return null;
+    }
+
+    const [message] = this.history.splice(index);
+    this.partialContent = null;
+    await this.saveHistory();
+
+    if (!Array.isArray(message.content)) {
+      return { content: message.content };
+    }
+    const blocks = message.content as any[];
+    const images: LLMImage[] = blocks
+      .filter(block => block.type === 'image')
+      .map(block => ({ mediaType: block.source.media_type, data: block.source.data }));
+    const text = blocks
+      .filter(block => block.type === 'text')
+      .map(block => block.text)
+      .join('\n');
+    return images.length > 0 ? { content: text, images } : { content: text };
+  }
+
+  /**
+   * Remove one exchange from the history: the prompt `fromEnd` prompts back
+   * from the last one, its reply and tool calls, up to the next prompt.
+   * Returns false when there is no such prompt.
+   */
+  async deleteTurn(fromEnd: number): Promise<boolean> {
+    const index = this.findPrompt(fromEnd);
+    if (index < 0) {
+      return false;
+    }
+    const next = fromEnd > 0 ? this.findPrompt(fromEnd - 1) : this.history.length;
+    this.history.splice(index, next - index);
+    await this.saveHistory();
+    return true;
+  }
+
+  /**
+   * Index of a prompt in the history, counted back from the last one (-1 when
+   * there are fewer prompts). Tool results are sent as user messages, so
+   * they are skipped.
+   */
+  private findPrompt(fromEnd: number): number {
+    const isPrompt = (message: Message) =>
+      message.role === 'user' &&
+      !(Array.isArray(message.content) && message.content.some((block: any) => block.type === 'tool_result'));
+    let seen = -1;
+    for (let index = this.history.length - 1; index >= 0; index--) {
+      if (isPrompt(this.history[index]) && ++seen === fromEnd) {
+        return index;
+      }
+    }
+    return -1;
+  }
+
+  private async saveHistory(): Promise<void> {
+    if (this.currentSession) {
+      this.currentSession.messages = this.history;
+      await this.sessionManager.saveSession(this.currentSession);
+    }
+  }
+
+  /**
+   * Report a finished LLM request
+   */
+  private emitRequestComplete(
+    callbacks: AgentCallbacks | undefined,
+    turn: number,
+    start: number,
+    firstTokenMs: number | undefined,
+    usage: StreamChunk['usage'],
+    error?: string
+  ): void {
+    const at = Date.now();
+    callbacks?.onTiming?.({
+      type: 'request_complete',
+      turn,
+      at,
+      durationMs: at - start,
+      firstTokenMs,
+      inputTokens: usage?.inputTokens,
+      outputTokens: usage?.outputTokens,
+      error,
+    });
+  }
+
+  /**
+   * Report a finished tool call
+   */
+  private emitToolTiming(callbacks: AgentCallbacks | undefined, tc: ToolCall, start: number): void {
+    const at = Date.now();
+    this.toolMetrics.record(tc.name, at - start, tc.status === 'completed', at);
+    callbacks?.onTiming?.({
+      type: 'tool_complete',
+      tool: tc.name,
+      toolCallId: tc.id,
+      at,
+      durationMs: at - start,
+      success: tc.status === 'completed',
+    });
+  }
+
+  private toolResultMessage(toolUseId: string, content: string): Message {
+    return {
+      role: 'user',
+      content: [
+        {
+          type: 'tool_result',
+          tool_use_id: toolUseId,
+          content,
+        },
+      ],
+    };
+  }
+
   /**
    * Truncate large output to prevent context window overflow
    */
@@ -485,6 +761,20 @@ export class AgentEngine {
     }));
   }
 
+  /**
+   * Per-tool call counts, error rates and latency for this engine
+   */
+  getToolStats(): ToolStats[] {
+    return this.toolMetrics.stats();
+  }
+
+  /**
+   * Start the tool stats over
+   */
+  resetToolStats(): void {
+    this.toolMetrics.reset();
+  }
+
   /**
    * Call a tool directly
    */
@@ -506,6 +796,33 @@ export class AgentEngine {
   async reset(): Promise<void> {
     this.history = [];
     this.currentSession = null;
+    this.toolMetrics.reset();
+  }
+
+  /**
+   * Get the request settings in use
+   */
+  getRequestParams(): RequestParams {
+    return { maxTokens: this.maxTokens, temperature: this.temperature, stopSequences: [...this.stopSequences] };
+  }
+
+  /**
+   * Change request settings for the following messages of this engine
+   * (ignored by a client passed in as llmClient)
+   */
+  setRequestParams(params: Partial<RequestParams>): void {
+    this.maxTokens = params.maxTokens ?? this.maxTokens;
+    this.temperature = params.temperature ?? this.temperature;
+    this.stopSequences = params.stopSequences ?? this.stopSequences;
+    this.clientOptions = {
+      ...this.clientOptions,
+      maxTokens: this.maxTokens,
+      temperature: this.temperature,
+      stopSequences: this.stopSequences,
+    };
+    if (!this.customClient) {
+      this.llmClient = createLLMClient(this.clientOptions);
+    }
   }
 
   /**
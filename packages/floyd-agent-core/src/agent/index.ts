@@ -5,6 +5,19 @@ export type {
   ToolCall,
   StreamChunk,
   AgentEvent,
+  TimingEvent,
   AgentEngineOptions,
   AgentCallbacks,
+  RequestParams,
+  RequestOverrides,
+  RewoundMessage,
 } from './AgentEngine.js';
+export {
+  scheduleToolCalls,
+  describeAccess,
+  isReadOnlyTool,
+  conflicts,
+  getToolConcurrency,
+  DEFAULT_TOOL_CONCURRENCY,
+} from './tool-scheduler.js';
+export type { ScheduledCall, ToolAccess, ToolSchedulerOptions } from './tool-scheduler.js';
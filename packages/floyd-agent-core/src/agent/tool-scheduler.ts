@@ -0,0 +1,183 @@
+// Tool Scheduler - runs one turn's tool calls concurrently where it is safe
+// Read-only calls run side by side; calls that change files are ordered after
+// earlier calls touching the same paths, and shell commands wait for
+// everything before them, so a build never races the write it depends on.
+
+import path from 'node:path';
+
+/**
+ * Default number of tool calls in flight at once
+ */
+export const DEFAULT_TOOL_CONCURRENCY = 4;
+
+/**
+ * Tools known to only look at the workspace, matched against the name with
+ * any MCP server prefix removed. Anything else is assumed to change
+ * something: a name like query_* or fetch_* says nothing about side effects.
+ */
+const READ_ONLY_TOOLS = new Set([
+  // Files
+  'read_file', 'read_text_file', 'read_media_file', 'read_multiple_files',
+  'list_directory', 'list_directory_with_sizes', 'directory_tree', 'list_allowed_directories',
+  'get_file_info', 'search_files', 'grep', 'glob',
+  // Code navigation
+  'codebase_search', 'semantic_search', 'symbols', 'list_symbols', 'ast_navigator',
+  'project_map', 'detect_project', 'dependency_xray', 'check_diagnostics',
+  // Git
+  'git_status', 'git_diff', 'git_log', 'is_protected_branch',
+]);
+
+/**
+ * Tools that run arbitrary commands and may touch anything
+ */
+const SHELL_PATTERN = /(^|_)(bash|shell|exec|execute|run|command|terminal)(_|$)/i;
+
+/**
+ * Input fields that name a file or directory
+ */
+const PATH_FIELDS = ['path', 'file_path', 'filePath', 'file', 'filename', 'directory', 'dir', 'cwd', 'source', 'destination'];
+const PATH_LIST_FIELDS = ['paths', 'files', 'file_paths', 'filePaths'];
+
+export interface ScheduledCall {
+  name: string;
+  input: unknown;
+}
+
+export interface ToolAccess {
+  readOnly: boolean;
+  /** Paths the call touches; empty means it may touch anything */
+  paths: string[];
+}
+
+export interface ToolSchedulerOptions {
+  /** Calls in flight at once (default DEFAULT_TOOL_CONCURRENCY) */
+  concurrency?: number;
+  /** Overrides the built-in list of read-only tools */
+  isReadOnly?: (name: string) => boolean;
+  /** Directory relative paths are resolved against (default process.cwd()) */
+  cwd?: string;
+}
+
+function baseName(name: string): string {
+  const parts = name.split(/__|[:/]/);
+  return parts[parts.length - 1] ?? name;
+}
+
+/**
+ * Whether a tool is on the built-in read-only list
+ */
+export function isReadOnlyTool(name: string): boolean {
+  return READ_ONLY_TOOLS.has(baseName(name).toLowerCase());
+}
+
+/**
+ * Work out what a call reads or changes from its name and input; paths are
+ * resolved against cwd so different spellings of a file overlap
+ */
+export function describeAccess(
+  call: ScheduledCall,
+  isReadOnly: (name: string) => boolean = isReadOnlyTool,
+  cwd: string = process.cwd(),
+): ToolAccess {
+  const name = baseName(call.name);
+  const input = call.input && typeof call.input === 'object' ? call.input as Record<string, unknown> : {};
+  const normalizePath = (value: string) => path.resolve(cwd, value);
+
+  const readOnly = isReadOnly(call.name);
+  if (!readOnly && SHELL_PATTERN.test(name)) {
+    return { readOnly, paths: [] };
+  }
+
+  const paths: string[] = [];
+  for (const field of PATH_FIELDS) {
+    if (typeof input[field] === 'string' && input[field]) {
+      paths.push(normalizePath(input[field] as string));
+    }
+  }
+  for (const field of PATH_LIST_FIELDS) {
+    const value = input[field];
+    if (Array.isArray(value)) {
+      paths.push(...value.filter((p): p is string => typeof p === 'string' && p !== '').map(normalizePath));
+    }
+  }
+
+  return { readOnly, paths };
+}
+
+function pathsOverlap(a: string, b: string): boolean {
+  const within = (child: string, parent: string) =>
+    child.startsWith(parent.endsWith(path.sep) ? parent : parent + path.sep);
+  return a === b || within(a, b) || within(b, a);
+}
+
+/**
+ * Whether two calls must not run at the same time
+ */
+export function conflicts(a: ToolAccess, b: ToolAccess): boolean {
+  if (a.readOnly && b.readOnly) {
+    return false;
+  }
+  if (a.paths.length === 0 || b.paths.length === 0) {
+    return true;
+  }
+  return a.paths.some(p => b.paths.some(q => pathsOverlap(p, q)));
+}
+
+/**
+ * Run calls with as much concurrency as their access allows
+ *
+ * A call starts once every earlier call it conflicts with has finished and a
+ * slot is free. Results come back in the order of `calls`; a call that throws
+ * settles as rejected without holding up the others.
+ */
+export async function scheduleToolCalls<C extends ScheduledCall, R>(
+  calls: C[],
+  run: (call: C, index: number) => Promise<R>,
+  options: ToolSchedulerOptions = {},
+): Promise<PromiseSettledResult<R>[]> {
+  const concurrency = Math.max(1, Math.floor(options.concurrency ?? DEFAULT_TOOL_CONCURRENCY));
+  const access = calls.map(call => describeAccess(call, options.isReadOnly, options.cwd));
+  const dependsOn = calls.map((_, j) =>
+    calls.slice(0, j).map((__, i) => i).filter(i => conflicts(access[i], access[j])));
+
+  const results: PromiseSettledResult<R>[] = new Array(calls.length);
+  const done = new Set<number>();
+  const started = new Set<number>();
+  let running = 0;
+
+  return new Promise(resolve => {
+    const startReady = () => {
+      if (done.size === calls.length) {
+        resolve(results);
+        return;
+      }
+      for (let j = 0; j < calls.length && running < concurrency; j++) {
+        if (started.has(j) || !dependsOn[j].every(i => done.has(i))) {
+          continue;
+        }
+        started.add(j);
+        running++;
+        Promise.resolve()
+          .then(() => run(calls[j], j))
+          .then(
+            value => { results[j] = { status: 'fulfilled', value }; },
+            reason => { results[j] = { status: 'rejected', reason }; },
+          )
+          .finally(() => {
+            running--;
+            done.add(j);
+            startReady();
+          });
+      }
+    };
+    startReady();
+  });
+}
+
+/**
+ * Concurrency from FLOYD_TOOL_CONCURRENCY, falling back to the default
+ */
+export function getToolConcurrency(): number {
+  const value = Number(process.env.FLOYD_TOOL_CONCURRENCY);
+  return Number.isFinite(value) && value >= 1 ? Math.floor(value) : DEFAULT_TOOL_CONCURRENCY;
+}
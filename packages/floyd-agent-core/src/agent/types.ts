@@ -25,9 +25,29 @@ export type StreamChunk = {
   error?: Error;
 };
 
+// Timing of LLM requests and tool calls (times are epoch ms, durations ms).
+// A slow first token with a normal token rate points at the network or the
+// provider's queue; a slow token rate points at the model itself.
+export type TimingEvent =
+  | { type: 'request_sent'; turn: number; model: string; at: number }
+  | { type: 'first_token'; turn: number; at: number; latencyMs: number }
+  | {
+      type: 'request_complete';
+      turn: number;
+      at: number;
+      durationMs: number;
+      /** Undefined when the request produced no output */
+      firstTokenMs?: number;
+      inputTokens?: number;
+      outputTokens?: number;
+      error?: string;
+    }
+  | { type: 'tool_complete'; tool: string; toolCallId: string; at: number; durationMs: number; success: boolean };
+
 export type AgentEvent =
   | { type: 'chunk'; token: string }
   | { type: 'tool_start'; toolCall: ToolCall }
   | { type: 'tool_complete'; toolCall: ToolCall }
+  | { type: 'timing'; timing: TimingEvent }
   | { type: 'done' }
   | { type: 'error'; error: Error };
@@ -16,9 +16,28 @@ export { Config } from './utils/config.js';
 // Error handling utilities
 export { humanizeError, formatHumanizedError, getSeverityEmoji, type HumanizedError } from './utils/error-humanizer.js';
 
+// Per-tool execution metrics
+export { ToolMetrics, formatToolStats, LATENCY_BUCKETS_MS, type ToolStats } from './utils/tool-metrics.js';
+
+// API keys in the OS keychain or an encrypted file
+export {
+  CredentialStore,
+  getCredentialStore,
+  validateApiKey,
+  parseProvider,
+  maskKey,
+  isSecretCommand,
+  PROVIDER_ENV_VARS,
+  type CredentialStatus,
+  type KeyCheck,
+} from './utils/credentials.js';
+
 // LLM Client exports
-export { createLLMClient, OpenAICompatibleClient, AnthropicClient } from './llm/index.js';
-export type { LLMClient, LLMClientOptions, LLMMessage, LLMTool, StreamChunk, LLMChatCallbacks } from './llm/index.js';
+export { createLLMClient, OpenAICompatibleClient, AnthropicClient, StreamingFallbackClient, isStreamingFailure } from './llm/index.js';
+export type { LLMClient, LLMClientOptions, LLMMessage, LLMImage, LLMTool, StreamChunk, LLMChatCallbacks, StreamingMode, StreamingFallbackEvent } from './llm/index.js';
+
+// Scriptable client for tests
+export { FakeLLMClient, type FakeLLMResponse, type FakeLLMClientOptions, type FakeLLMCall } from './llm/index.js';
 
 // Constants exports
 export { 
@@ -33,7 +52,8 @@ export {
 } from './constants.js';
 
 // Re-export types
-export type { Message, ToolCall } from './agent/types.js';
+export type { Message, ToolCall, TimingEvent } from './agent/types.js';
+export type { RequestParams, RequestOverrides, RewoundMessage } from './agent/AgentEngine.js';
 export type { MCPTool, MCPResource } from './mcp/types.js';
 
 // Re-export interfaces for Dependency Inversion (consumers implement these)
@@ -14,12 +14,16 @@ export class AnthropicClient implements LLMClient {
   private client: Anthropic;
   private model: string;
   private maxTokens: number;
+  private temperature?: number;
+  private stopSequences?: string[];
   private baseURL: string;
 
   constructor(options: LLMClientOptions) {
     this.baseURL = options.baseURL ?? DEFAULT_ANTHROPIC_CONFIG.endpoint;
     this.model = options.model ?? DEFAULT_ANTHROPIC_CONFIG.model;
     this.maxTokens = options.maxTokens ?? 8192;
+    this.temperature = options.temperature;
+    this.stopSequences = options.stopSequences?.length ? options.stopSequences : undefined;
 
     this.client = new Anthropic({
       apiKey: options.apiKey,
@@ -42,25 +46,14 @@ export class AnthropicClient implements LLMClient {
     callbacks?: LLMChatCallbacks
   ): AsyncGenerator<StreamChunk, void, unknown> {
     try {
-      // Convert tools to Anthropic format
-      const anthropicTools: Anthropic.Tool[] = tools.map((tool) => ({
-        name: tool.name,
-        description: tool.description,
-        input_schema: tool.inputSchema as Anthropic.Tool.InputSchema,
-      }));
-
-      // Separate system message from other messages
-      const systemMessage = messages.find((m) => m.role === 'system')?.content;
-      const chatMessages: Anthropic.MessageParam[] = messages
-        .filter((m) => m.role !== 'system')
-        .map((msg) => ({
-          role: msg.role as 'user' | 'assistant',
-          content: msg.content,
-        }));
+      const anthropicTools = this.toAnthropicTools(tools);
+      const { systemMessage, chatMessages } = this.toAnthropicMessages(messages);
 
       const stream = await this.client.messages.stream({
         model: this.model,
         max_tokens: this.maxTokens,
+        temperature: this.temperature,
+        stop_sequences: this.stopSequences,
         system: systemMessage,
         messages: chatMessages,
         tools: anthropicTools.length > 0 ? anthropicTools : undefined,
@@ -149,10 +142,105 @@ export class AnthropicClient implements LLMClient {
       const userMessage = formatHumanizedError(humanized, false);
       const errorChunk: StreamChunk = {
         error: userMessage,
+        error_code: humanized.code,
         done: true,
       };
       callbacks?.onError?.(new Error(userMessage));
       yield errorChunk;
     }
   }
+
+  /**
+   * Non-streaming request; tool calls are read from the full response
+   */
+  async *complete(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown> {
+    try {
+      const anthropicTools = this.toAnthropicTools(tools);
+      const { systemMessage, chatMessages } = this.toAnthropicMessages(messages);
+
+      const response = await this.client.messages.create({
+        model: this.model,
+        max_tokens: this.maxTokens,
+        temperature: this.temperature,
+        stop_sequences: this.stopSequences,
+        system: systemMessage,
+        messages: chatMessages,
+        tools: anthropicTools.length > 0 ? anthropicTools : undefined,
+        stream: false,
+      });
+
+      for (const block of response.content) {
+        if (block.type === 'text') {
+          const textChunk: StreamChunk = { token: block.text };
+          callbacks?.onChunk?.(textChunk);
+          yield textChunk;
+        } else if (block.type === 'thinking') {
+          yield { thinking: block.thinking };
+        } else if (block.type === 'tool_use') {
+          const tool = {
+            id: block.id,
+            name: block.name,
+            input: (block.input ?? {}) as Record<string, unknown>,
+          };
+          callbacks?.onToolStart?.(tool);
+          yield { tool_call_id: tool.id, tool_call: { ...tool, input: {} } };
+          yield { tool_call_id: tool.id, tool_call: tool, tool_use_complete: true };
+        }
+      }
+
+      callbacks?.onDone?.();
+      yield {
+        done: true,
+        stop_reason: response.stop_reason ?? undefined,
+        usage: {
+          inputTokens: response.usage.input_tokens,
+          outputTokens: response.usage.output_tokens,
+        },
+      };
+    } catch (error) {
+      const humanized = humanizeError(error instanceof Error ? error : String(error));
+      const userMessage = formatHumanizedError(humanized, false);
+      callbacks?.onError?.(new Error(userMessage));
+      yield { error: userMessage, error_code: humanized.code, done: true };
+    }
+  }
+
+  private toAnthropicTools(tools: LLMTool[]): Anthropic.Tool[] {
+    return tools.map((tool) => ({
+      name: tool.name,
+      description: tool.description,
+      input_schema: tool.inputSchema as Anthropic.Tool.InputSchema,
+    }));
+  }
+
+  /**
+   * Separate the system message from the conversation
+   */
+  private toAnthropicMessages(messages: LLMMessage[]): {
+    systemMessage: string | undefined;
+    chatMessages: Anthropic.MessageParam[];
+  } {
+    return {
+      systemMessage: messages.find((m) => m.role === 'system')?.content,
+      chatMessages: messages
+        .filter((m) => m.role !== 'system')
+        .map((msg) => ({
+          role: msg.role as 'user' | 'assistant',
+          // Images go before the text, as Anthropic recommends
+          content: msg.images?.length
+            ? [
+                ...msg.images.map((image) => ({
+                  type: 'image' as const,
+                  source: { type: 'base64' as const, media_type: image.mediaType, data: image.data },
+                })),
+                { type: 'text' as const, text: msg.content },
+              ]
+            : msg.content,
+        })),
+    };
+  }
 }
@@ -5,13 +5,16 @@
  * This is the main entry point for getting an LLM client.
  */
 
-import type { LLMClient, LLMClientOptions } from './types.js';
+import type { LLMClient, LLMClientOptions, StreamingMode } from './types.js';
 import { OpenAICompatibleClient } from './openai-client.js';
 import { AnthropicClient } from './anthropic-client.js';
+import { StreamingFallbackClient, type StreamingFallbackEvent } from './streaming-fallback.js';
 import { isOpenAICompatible, inferProviderFromEndpoint, PROVIDER_DEFAULTS, type Provider } from '../constants.js';
 
 export interface CreateLLMClientOptions extends LLMClientOptions {
   provider?: Provider;
+  /** Told when the client stops or resumes streaming (auto mode) */
+  onStreamingFallback?: (event: StreamingFallbackEvent) => void;
 }
 
 /**
@@ -20,6 +23,9 @@ export interface CreateLLMClientOptions extends LLMClientOptions {
  * Automatically selects the correct client implementation based on:
  * 1. Explicit provider option
  * 2. Endpoint URL inference
+ *
+ * Unless streaming is 'on', the client falls back to non-streaming requests
+ * for backends that reject stream=true (see StreamingFallbackClient).
  * 
  * @example
  * // GLM via api.z.ai (uses OpenAI-compatible client)
@@ -54,16 +60,29 @@ export function createLLMClient(options: CreateLLMClientOptions): LLMClient {
 
   // Select client implementation
   // Only Anthropic direct API uses AnthropicClient
-  if (!isOpenAICompatible(finalOptions.baseURL!)) {
-    return new AnthropicClient(finalOptions);
-  } else {
-    return new OpenAICompatibleClient(finalOptions);
-  }
+  const client: LLMClient = !isOpenAICompatible(finalOptions.baseURL!)
+    ? new AnthropicClient(finalOptions)
+    : new OpenAICompatibleClient(finalOptions);
+
+  const streaming = options.streaming ?? getStreamingModeFromEnv();
+  return streaming === 'on'
+    ? client
+    : new StreamingFallbackClient(client, streaming, { onEvent: options.onStreamingFallback });
+}
+
+/**
+ * Streaming mode from FLOYD_STREAMING (auto, on, off; default auto)
+ */
+function getStreamingModeFromEnv(): StreamingMode {
+  const value = process.env['FLOYD_STREAMING'];
+  return value === 'on' || value === 'off' ? value : 'auto';
 }
 
 /**
  * Re-export types for convenience
  */
-export type { LLMClient, LLMClientOptions, LLMMessage, LLMTool, StreamChunk, LLMChatCallbacks } from './types.js';
+export type { LLMClient, LLMClientOptions, LLMMessage, LLMImage, LLMTool, StreamChunk, LLMChatCallbacks, StreamingMode } from './types.js';
 export { OpenAICompatibleClient } from './openai-client.js';
 export { AnthropicClient } from './anthropic-client.js';
+export { StreamingFallbackClient } from './streaming-fallback.js';
+export type { StreamingFallbackEvent, StreamingFallbackOptions } from './streaming-fallback.js';
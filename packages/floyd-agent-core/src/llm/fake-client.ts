@@ -0,0 +1,164 @@
+/**
+ * Fake LLM Client
+ *
+ * Scriptable in-memory LLMClient for deterministic tests of the agent loop
+ * and the UIs built on it. Each chat() call takes the next scripted
+ * response: text chunks, thinking, tool calls, a stream error or a thrown
+ * error, with optional latency. Calls are kept for assertions.
+ *
+ * @example
+ * const client = new FakeLLMClient([
+ *   { toolCalls: [{ name: 'read_file', input: { path: 'a.ts' } }] },
+ *   'The file exports one function.',
+ * ]);
+ * const engine = new AgentEngine({ apiKey: 'unused', llmClient: client }, ...);
+ */
+
+import type { LLMClient, LLMMessage, LLMTool, LLMChatCallbacks, StreamChunk } from './types.js';
+
+/**
+ * One scripted response
+ */
+export interface FakeLLMResponse {
+  /** Text streamed as-is, one chunk per entry */
+  chunks?: string[];
+  /** Text streamed in chunks of `chunkSize` characters (after `chunks`) */
+  text?: string;
+  /** Reasoning streamed before the text */
+  thinking?: string;
+  /** Tool calls made after the text; ids default to fake_<call>_<n> */
+  toolCalls?: Array<{ name: string; input?: Record<string, unknown>; id?: string }>;
+  /** Error chunk that ends the reply after the content (as real clients report failures) */
+  error?: string;
+  /** Error thrown instead of streaming anything */
+  throws?: string | Error;
+  /** Latency before the first chunk (ms) */
+  delayMs?: number;
+  /** Latency between chunks (ms) */
+  chunkDelayMs?: number;
+  /** Usage reported with the final chunk */
+  usage?: { inputTokens: number; outputTokens: number };
+}
+
+export interface FakeLLMClientOptions {
+  /** Characters per chunk when streaming `text` (default 8) */
+  chunkSize?: number;
+  /** Response used once the script runs out (default: throw) */
+  fallback?: FakeLLMResponse | string;
+  model?: string;
+}
+
+/**
+ * Messages and tools of one chat() call
+ */
+export interface FakeLLMCall {
+  messages: LLMMessage[];
+  tools: string[];
+}
+
+function toResponse(response: FakeLLMResponse | string): FakeLLMResponse {
+  return typeof response === 'string' ? { text: response } : response;
+}
+
+function sleep(ms?: number): Promise<void> {
+  return ms && ms > 0 ? new Promise(resolve => setTimeout(resolve, ms)) : Promise.resolve();
+}
+
+export class FakeLLMClient implements LLMClient {
+  /** Every chat() call, in order */
+  readonly calls: FakeLLMCall[] = [];
+  private script: FakeLLMResponse[];
+
+  constructor(responses: Array<FakeLLMResponse | string> = [], private options: FakeLLMClientOptions = {}) {
+    this.script = responses.map(toResponse);
+  }
+
+  /**
+   * Add responses to the end of the script
+   */
+  enqueue(...responses: Array<FakeLLMResponse | string>): this {
+    this.script.push(...responses.map(toResponse));
+    return this;
+  }
+
+  /**
+   * Scripted responses not used yet
+   */
+  remaining(): number {
+    return this.script.length;
+  }
+
+  async *chat(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown> {
+    this.calls.push({ messages: [...messages], tools: tools.map(tool => tool.name) });
+    const call = this.calls.length;
+
+    const next = this.script.shift()
+      ?? (this.options.fallback !== undefined ? toResponse(this.options.fallback) : undefined);
+    if (!next) {
+      throw new Error(`FakeLLMClient has no scripted response for call ${call}`);
+    }
+
+    await sleep(next.delayMs);
+    if (next.throws !== undefined) {
+      const error = typeof next.throws === 'string' ? new Error(next.throws) : next.throws;
+      callbacks?.onError?.(error);
+      throw error;
+    }
+
+    const chunkSize = Math.max(1, this.options.chunkSize ?? 8);
+    const texts = [...(next.chunks ?? [])];
+    for (let i = 0; next.text && i < next.text.length; i += chunkSize) {
+      texts.push(next.text.slice(i, i + chunkSize));
+    }
+
+    const chunks: StreamChunk[] = [];
+    if (next.thinking) {
+      chunks.push({ thinking: next.thinking });
+    }
+    chunks.push(...texts.map(token => ({ token })));
+    for (const [index, toolCall] of (next.toolCalls ?? []).entries()) {
+      const tool = { id: toolCall.id ?? `fake_${call}_${index}`, name: toolCall.name, input: toolCall.input ?? {} };
+      chunks.push({ tool_call: tool, tool_call_id: tool.id });
+      chunks.push({ tool_call: tool, tool_call_id: tool.id, tool_use_complete: true });
+    }
+    if (next.error !== undefined) {
+      chunks.push({ error: next.error, done: true });
+    } else {
+      chunks.push({
+        done: true,
+        stop_reason: next.toolCalls?.length ? 'tool_use' : 'end_turn',
+        usage: next.usage,
+      });
+    }
+
+    for (const [index, chunk] of chunks.entries()) {
+      if (index > 0) {
+        await sleep(next.chunkDelayMs);
+      }
+      if (chunk.error !== undefined) {
+        callbacks?.onError?.(new Error(chunk.error));
+      } else {
+        callbacks?.onChunk?.(chunk);
+      }
+      if (chunk.tool_call && !chunk.tool_use_complete) {
+        callbacks?.onToolStart?.(chunk.tool_call);
+      }
+      yield chunk;
+    }
+    if (next.error === undefined) {
+      callbacks?.onDone?.();
+    }
+  }
+
+  getModel(): string {
+    return this.options.model ?? 'fake-model';
+  }
+
+  getBaseURL(): string {
+    return 'fake://llm';
+  }
+}
@@ -8,3 +8,6 @@ export * from './types.js';
 export * from './factory.js';
 export { OpenAICompatibleClient } from './openai-client.js';
 export { AnthropicClient } from './anthropic-client.js';
+export { StreamingFallbackClient, isStreamingFailure } from './streaming-fallback.js';
+export { FakeLLMClient } from './fake-client.js';
+export type { FakeLLMResponse, FakeLLMClientOptions, FakeLLMCall } from './fake-client.js';
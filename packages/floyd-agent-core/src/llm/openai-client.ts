@@ -14,6 +14,8 @@ export class OpenAICompatibleClient implements LLMClient {
   private client: OpenAI;
   private model: string;
   private maxTokens: number;
+  private temperature?: number;
+  private stopSequences?: string[];
   private baseURL: string;
   private defaultHeaders: Record<string, string>;
 
@@ -21,6 +23,8 @@ export class OpenAICompatibleClient implements LLMClient {
     this.baseURL = options.baseURL ?? DEFAULT_GLM_CONFIG.endpoint;
     this.model = options.model ?? DEFAULT_GLM_CONFIG.model;
     this.maxTokens = options.maxTokens ?? 8192;
+    this.temperature = options.temperature;
+    this.stopSequences = options.stopSequences?.length ? options.stopSequences : undefined;
     this.defaultHeaders = options.defaultHeaders ?? {};
 
     this.client = new OpenAI({
@@ -50,21 +54,8 @@ export class OpenAICompatibleClient implements LLMClient {
     try {
       console.log('[OpenAIClient] Starting chat with', messages.length, 'messages and', tools.length, 'tools');
 
-      // Convert tools to OpenAI format
-      const openaiTools: OpenAI.ChatCompletionTool[] = tools.map((tool) => ({
-        type: 'function' as const,
-        function: {
-          name: tool.name,
-          description: tool.description,
-          parameters: tool.inputSchema,
-        },
-      }));
-
-      // Convert messages to OpenAI format
-      const openaiMessages: OpenAI.ChatCompletionMessageParam[] = messages.map((msg) => ({
-        role: msg.role,
-        content: msg.content,
-      }));
+      const openaiTools = this.toOpenAITools(tools);
+      const openaiMessages = this.toOpenAIMessages(messages);
 
       console.log('[OpenAIClient] Sending request to', this.baseURL, 'with model', this.model);
 
@@ -73,6 +64,8 @@ export class OpenAICompatibleClient implements LLMClient {
         messages: openaiMessages,
         tools: openaiTools.length > 0 ? openaiTools : undefined,
         max_tokens: this.maxTokens,
+        temperature: this.temperature,
+        stop: this.stopSequences,
         stream: true,
       });
 
@@ -85,6 +78,12 @@ export class OpenAICompatibleClient implements LLMClient {
       for await (const chunk of stream) {
         const delta = chunk.choices[0]?.delta;
 
+        // Reasoning output (DeepSeek and GLM send it as reasoning_content)
+        const reasoning = (delta as { reasoning_content?: string } | undefined)?.reasoning_content;
+        if (reasoning) {
+          yield { thinking: reasoning };
+        }
+
         // Handle text content
         if (delta?.content) {
           const streamChunk: StreamChunk = {
@@ -172,10 +171,116 @@ export class OpenAICompatibleClient implements LLMClient {
       const userMessage = formatHumanizedError(humanized, false);
       const errorChunk: StreamChunk = {
         error: userMessage,
+        error_code: humanized.code,
         done: true,
       };
       callbacks?.onError?.(new Error(userMessage));
       yield errorChunk;
     }
   }
+
+  /**
+   * Non-streaming request; tool calls are parsed from the full response
+   */
+  async *complete(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown> {
+    try {
+      const openaiTools = this.toOpenAITools(tools);
+      const response = await this.client.chat.completions.create({
+        model: this.model,
+        messages: this.toOpenAIMessages(messages),
+        tools: openaiTools.length > 0 ? openaiTools : undefined,
+        max_tokens: this.maxTokens,
+        temperature: this.temperature,
+        stop: this.stopSequences,
+        stream: false,
+      });
+
+      const choice = response.choices[0];
+      const message = choice?.message;
+
+      const reasoning = (message as { reasoning_content?: string } | undefined)?.reasoning_content;
+      if (reasoning) {
+        yield { thinking: reasoning };
+      }
+
+      if (message?.content) {
+        const textChunk: StreamChunk = { token: message.content };
+        callbacks?.onChunk?.(textChunk);
+        yield textChunk;
+      }
+
+      for (const toolCall of message?.tool_calls ?? []) {
+        if (toolCall.type !== 'function') {
+          continue;
+        }
+
+        let parsedInput: Record<string, unknown> = {};
+        try {
+          parsedInput = JSON.parse(toolCall.function.arguments || '{}');
+        } catch {
+          console.warn('[OpenAIClient] Failed to parse tool arguments:', toolCall.function.arguments);
+          parsedInput = { _parseError: true, _raw: toolCall.function.arguments };
+        }
+
+        const tool = { id: toolCall.id, name: toolCall.function.name, input: parsedInput };
+        callbacks?.onToolStart?.(tool);
+        yield { tool_call_id: tool.id, tool_call: { ...tool, input: {} } };
+        yield { tool_call_id: tool.id, tool_call: tool, tool_use_complete: true };
+      }
+
+      const doneChunk: StreamChunk = {
+        done: true,
+        stop_reason: choice?.finish_reason ?? undefined,
+      };
+      if (response.usage) {
+        doneChunk.usage = {
+          inputTokens: response.usage.prompt_tokens,
+          outputTokens: response.usage.completion_tokens,
+        };
+      }
+      callbacks?.onDone?.();
+      yield doneChunk;
+    } catch (error) {
+      const humanized = humanizeError(error instanceof Error ? error : String(error));
+      const userMessage = formatHumanizedError(humanized, false);
+      callbacks?.onError?.(new Error(userMessage));
+      yield { error: userMessage, error_code: humanized.code, done: true };
+    }
+  }
+
+  private toOpenAITools(tools: LLMTool[]): OpenAI.ChatCompletionTool[] {
+    return tools.map((tool) => ({
+      type: 'function' as const,
+      function: {
+        name: tool.name,
+        description: tool.description,
+        parameters: tool.inputSchema,
+      },
+    }));
+  }
+
+  private toOpenAIMessages(messages: LLMMessage[]): OpenAI.ChatCompletionMessageParam[] {
+    return messages.map((msg): OpenAI.ChatCompletionMessageParam => {
+      if (msg.role === 'user' && msg.images?.length) {
+        return {
+          role: 'user',
+          content: [
+            { type: 'text', text: msg.content },
+            ...msg.images.map((image) => ({
+              type: 'image_url' as const,
+              image_url: { url: `data:${image.mediaType};base64,${image.data}` },
+            })),
+          ],
+        };
+      }
+      return {
+        role: msg.role,
+        content: msg.content,
+      };
+    });
+  }
 }
@@ -0,0 +1,202 @@
+/**
+ * Streaming fallback LLM client
+ *
+ * Some proxies reject stream=true (or answer it with a plain JSON body).
+ * This client streams as usual, but when a request fails before producing
+ * any output in a way that points at streaming (no events, an unreadable
+ * body, an unrecognized rejection) and a non-streaming request works, it
+ * switches to complete(). Rate limits, network, auth and server errors
+ * never cause a switch. In auto mode streaming is tried again after a
+ * number of non-streaming requests, so one bad moment does not disable it
+ * for the whole session. Callers see the same StreamChunk sequence either
+ * way, so the agent loop and the TUI keep working against non-SSE backends.
+ */
+
+import type { LLMClient, LLMMessage, LLMTool, StreamChunk, LLMChatCallbacks, StreamingMode } from './types.js';
+
+const PROBE_MESSAGES: LLMMessage[] = [{ role: 'user', content: 'Reply with OK.' }];
+
+/**
+ * Marker for a stream that ended without a single event
+ */
+export const EMPTY_STREAM_ERROR = 'Empty streamed response';
+
+/**
+ * Error codes that can mean the backend does not stream: the SSE parser
+ * could not read the body, or the request was rejected for a reason the
+ * error humanizer does not recognize (e.g. "stream is not supported")
+ */
+const STREAMING_ERROR_CODES = new Set(['PARSE_ERROR', 'UNKNOWN_ERROR']);
+
+/**
+ * Non-streaming requests made before streaming is tried again (auto mode)
+ */
+export const DEFAULT_STREAMING_RETRY_AFTER = 20;
+
+/**
+ * Reported when the client stops or resumes streaming
+ */
+export type StreamingFallbackEvent =
+  | { type: 'fallback'; baseURL: string; reason: string; retryAfter: number }
+  | { type: 'restored'; baseURL: string };
+
+export interface StreamingFallbackOptions {
+  /** Non-streaming requests before streaming is retried (auto mode) */
+  retryAfter?: number;
+  onEvent?: (event: StreamingFallbackEvent) => void;
+}
+
+type ChatMethod = (
+  messages: LLMMessage[],
+  tools: LLMTool[],
+  callbacks?: LLMChatCallbacks
+) => AsyncGenerator<StreamChunk, void, unknown>;
+
+/**
+ * Whether a failed streamed request may have failed because of streaming
+ * itself (as opposed to a transient or unrelated error)
+ */
+export function isStreamingFailure(chunk: StreamChunk): boolean {
+  return chunk.error === EMPTY_STREAM_ERROR
+    || (chunk.error_code !== undefined && STREAMING_ERROR_CODES.has(chunk.error_code));
+}
+
+export class StreamingFallbackClient implements LLMClient {
+  private inner: LLMClient;
+  private mode: StreamingMode;
+  /** Whether the backend streams; null until known */
+  private streaming: boolean | null;
+  private retryAfter: number;
+  /** Non-streaming requests left before streaming is retried */
+  private requestsUntilRetry = 0;
+  /** Whether the current streaming attempt follows a fallback */
+  private retrying = false;
+  private onEvent?: (event: StreamingFallbackEvent) => void;
+
+  constructor(inner: LLMClient, mode: StreamingMode = 'auto', options: StreamingFallbackOptions = {}) {
+    this.inner = inner;
+    this.mode = mode;
+    this.streaming = mode === 'off' ? false : mode === 'on' ? true : null;
+    this.retryAfter = options.retryAfter ?? DEFAULT_STREAMING_RETRY_AFTER;
+    this.onEvent = options.onEvent;
+  }
+
+  getModel(): string {
+    return this.inner.getModel();
+  }
+
+  getBaseURL(): string {
+    return this.inner.getBaseURL();
+  }
+
+  /**
+   * Whether streaming is in use (null while undetermined)
+   */
+  supportsStreaming(): boolean | null {
+    return this.streaming;
+  }
+
+  async *chat(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown> {
+    if (this.streaming === false && this.mode === 'auto' && --this.requestsUntilRetry <= 0) {
+      this.streaming = null;
+      this.retrying = true;
+    }
+    if (this.streaming === false && this.inner.complete) {
+      yield* this.inner.complete(messages, tools, callbacks);
+      return;
+    }
+
+    // Errors are held back until we know whether streaming itself failed
+    const { onError, ...streamCallbacks } = callbacks ?? {};
+    let produced = false;
+    let failure: StreamChunk | null = null;
+
+    for await (const chunk of this.inner.chat(messages, tools, streamCallbacks)) {
+      if (chunk.error && !produced) {
+        failure = chunk;
+        break;
+      }
+      if (chunk.token || chunk.tool_call || chunk.thinking || chunk.done) {
+        produced = true;
+      }
+      if (chunk.error) {
+        onError?.(new Error(chunk.error));
+      }
+      yield chunk;
+    }
+
+    if (produced) {
+      if (this.streaming === null && this.retrying) {
+        this.onEvent?.({ type: 'restored', baseURL: this.getBaseURL() });
+      }
+      this.streaming ??= true;
+      this.retrying = false;
+      return;
+    }
+
+    // Nothing at all usually means the SSE parser found no events in a JSON body
+    failure ??= { error: EMPTY_STREAM_ERROR, done: true };
+
+    if (this.streaming === null && this.inner.complete && isStreamingFailure(failure) && await this.probeComplete()) {
+      this.streaming = false;
+      this.retrying = false;
+      this.requestsUntilRetry = this.retryAfter;
+      this.onEvent?.({
+        type: 'fallback',
+        baseURL: this.getBaseURL(),
+        reason: failure.error_code ?? failure.error ?? EMPTY_STREAM_ERROR,
+        retryAfter: this.retryAfter,
+      });
+      yield* this.inner.complete(messages, tools, callbacks);
+      return;
+    }
+
+    onError?.(new Error(failure.error));
+    yield failure;
+  }
+
+  complete(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown> {
+    return this.inner.complete
+      ? this.inner.complete(messages, tools, callbacks)
+      : this.inner.chat(messages, tools, callbacks);
+  }
+
+  /**
+   * Capability probe: does a minimal streamed request produce output?
+   */
+  async probeStreaming(): Promise<boolean> {
+    return this.probe(this.inner.chat.bind(this.inner));
+  }
+
+  /**
+   * Capability probe: does a minimal non-streaming request produce output?
+   * If not, the failure is not about streaming (auth, network, ...)
+   */
+  private async probeComplete(): Promise<boolean> {
+    return this.inner.complete ? this.probe(this.inner.complete.bind(this.inner)) : false;
+  }
+
+  private async probe(method: ChatMethod): Promise<boolean> {
+    try {
+      for await (const chunk of method(PROBE_MESSAGES, [])) {
+        if (chunk.error) {
+          return false;
+        }
+        if (chunk.token || chunk.done) {
+          return true;
+        }
+      }
+    } catch {
+      return false;
+    }
+    return false;
+  }
+}
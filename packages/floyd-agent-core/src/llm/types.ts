@@ -6,6 +6,17 @@
 export interface LLMMessage {
   role: 'system' | 'user' | 'assistant';
   content: string;
+  /** Images sent with the text (user messages only) */
+  images?: LLMImage[];
+}
+
+/**
+ * Image content block, base64-encoded
+ */
+export interface LLMImage {
+  mediaType: 'image/png' | 'image/jpeg' | 'image/gif' | 'image/webp';
+  /** Base64 data without the data: URL prefix */
+  data: string;
 }
 
 export interface LLMTool {
@@ -43,6 +54,8 @@ export interface StreamChunk {
 
   // Errors
   error?: string;
+  /** Error category (HumanizedError code, e.g. RATE_LIMITED) */
+  error_code?: string;
 
   // Usage
   usage?: {
@@ -51,14 +64,28 @@ export interface StreamChunk {
   };
 }
 
+/**
+ * Whether to request streamed responses
+ *
+ * - auto: stream, but fall back to non-streaming requests when the backend
+ *   (typically a proxy) rejects stream=true
+ * - on: always stream
+ * - off: never stream
+ */
+export type StreamingMode = 'auto' | 'on' | 'off';
+
 export interface LLMClientOptions {
   apiKey: string;
   baseURL?: string;
   model?: string;
   maxTokens?: number;
   temperature?: number;
+  /** Text that ends the reply when the model writes it */
+  stopSequences?: string[];
   defaultHeaders?: Record<string, string>;
   enableThinkingMode?: boolean;
+  /** Streaming behavior (default: FLOYD_STREAMING or 'auto') */
+  streaming?: StreamingMode;
 }
 
 export interface LLMChatCallbacks {
@@ -85,6 +112,18 @@ export interface LLMClient {
     callbacks?: LLMChatCallbacks
   ): AsyncGenerator<StreamChunk, void, unknown>;
 
+  /**
+   * Send a chat message without streaming (stream=false)
+   *
+   * Yields the same chunks as chat(), all at once from the full response,
+   * so callers can switch between the two transparently.
+   */
+  complete?(
+    messages: LLMMessage[],
+    tools: LLMTool[],
+    callbacks?: LLMChatCallbacks
+  ): AsyncGenerator<StreamChunk, void, unknown>;
+
   /**
    * Get the model name
    */
@@ -0,0 +1,140 @@
+/**
+ * Conformance harness
+ *
+ * Runs a list of named checks and collects the results into a report.
+ * It has no test-framework dependency: call assertConformance() from any
+ * runner (ava, vitest, node:test, ...) or print formatConformanceReport().
+ */
+
+export interface ConformanceCheck {
+  name: string;
+  run: () => Promise<void>;
+}
+
+export interface ConformanceResult {
+  name: string;
+  status: 'passed' | 'failed' | 'skipped';
+  /** Failure message or skip reason */
+  message?: string;
+  durationMs: number;
+}
+
+export interface ConformanceReport {
+  /** What was checked, e.g. the client's model name */
+  subject: string;
+  passed: boolean;
+  results: ConformanceResult[];
+}
+
+export interface ConformanceOptions {
+  /** Per-check timeout in ms (default: 60000, live backends can be slow) */
+  timeoutMs?: number;
+  /** Only run checks whose name contains one of these strings */
+  only?: string[];
+}
+
+/**
+ * Thrown by checks that cannot run against this implementation
+ */
+export class ConformanceSkip extends Error {
+  constructor(reason: string) {
+    super(reason);
+    this.name = 'ConformanceSkip';
+  }
+}
+
+/**
+ * Thrown by assertConformance() when a check failed
+ */
+export class ConformanceError extends Error {
+  readonly report: ConformanceReport;
+
+  constructor(report: ConformanceReport) {
+    super(formatConformanceReport(report));
+    this.name = 'ConformanceError';
+    this.report = report;
+  }
+}
+
+/**
+ * Fail the current check
+ */
+export function check(condition: unknown, message: string): asserts condition {
+  if (!condition) {
+    throw new Error(message);
+  }
+}
+
+/**
+ * Skip the current check
+ */
+export function skip(reason: string): never {
+  throw new ConformanceSkip(reason);
+}
+
+/**
+ * Run checks one after another (they may share a backend)
+ */
+export async function runChecks(
+  subject: string,
+  checks: ConformanceCheck[],
+  options: ConformanceOptions = {}
+): Promise<ConformanceReport> {
+  const timeoutMs = options.timeoutMs ?? 60_000;
+  const selected = options.only
+    ? checks.filter(c => options.only!.some(part => c.name.includes(part)))
+    : checks;
+
+  const results: ConformanceResult[] = [];
+  for (const c of selected) {
+    const start = Date.now();
+    let timer: NodeJS.Timeout | undefined;
+    try {
+      await Promise.race([
+        c.run(),
+        new Promise<never>((_, reject) => {
+          timer = setTimeout(() => reject(new Error(`timed out after ${timeoutMs}ms`)), timeoutMs);
+        }),
+      ]);
+      results.push({ name: c.name, status: 'passed', durationMs: Date.now() - start });
+    } catch (error) {
+      results.push({
+        name: c.name,
+        status: error instanceof ConformanceSkip ? 'skipped' : 'failed',
+        message: error instanceof Error ? error.message : String(error),
+        durationMs: Date.now() - start,
+      });
+    } finally {
+      clearTimeout(timer);
+    }
+  }
+
+  return {
+    subject,
+    passed: results.every(r => r.status !== 'failed'),
+    results,
+  };
+}
+
+/**
+ * Throw a ConformanceError if any check failed
+ */
+export function assertConformance(report: ConformanceReport): void {
+  if (!report.passed) {
+    throw new ConformanceError(report);
+  }
+}
+
+/**
+ * One line per check, failures with their message
+ */
+export function formatConformanceReport(report: ConformanceReport): string {
+  const marks = { passed: '✓', failed: '✗', skipped: '-' };
+  const lines = report.results.map(r => {
+    const detail = r.message ? ` — ${r.message}` : '';
+    return `  ${marks[r.status]} ${r.name}${detail}`;
+  });
+  const count = (status: ConformanceResult['status']) => report.results.filter(r => r.status === status).length;
+  const summary = `${count('passed')} passed, ${count('failed')} failed, ${count('skipped')} skipped`;
+  return [`Conformance: ${report.subject} (${summary})`, ...lines].join('\n');
+}
@@ -0,0 +1,16 @@
+/**
+ * Conformance test suites for code that embeds the agent
+ *
+ * Run them from any test framework to check a custom LLMClient or tool
+ * provider against the contracts AgentEngine relies on:
+ *
+ *   import { runLLMClientConformance, assertConformance } from 'floyd-agent-core/testing';
+ *
+ *   test('my client conforms', async () => {
+ *     assertConformance(await runLLMClientConformance(new MyClient(options)));
+ *   });
+ */
+
+export * from './harness.js';
+export { runLLMClientConformance, type LLMClientConformanceOptions } from './llm-conformance.js';
+export { runToolConformance, type ToolProvider, type ToolCase, type ToolConformanceOptions } from './tool-conformance.js';
@@ -0,0 +1,196 @@
+/**
+ * LLMClient conformance checks
+ *
+ * Exercises an LLMClient implementation against the contract AgentEngine
+ * relies on:
+ * - a reply ends with exactly one done chunk and nothing is emitted after it
+ * - tool calls are announced (tool_call) and later completed
+ *   (tool_use_complete) under the same id, with an object input
+ * - failures are yielded as { error, done: true } chunks, never thrown
+ * - callbacks mirror the stream (onChunk for text, onDone/onError once)
+ * - complete(), when implemented, yields the same chunk sequence
+ *
+ * The checks make real requests, so point the client at a test backend
+ * (or a local mock server) rather than a production account.
+ */
+
+import type { LLMClient, LLMMessage, LLMTool, StreamChunk, LLMChatCallbacks } from '../llm/types.js';
+import { check, skip, runChecks, type ConformanceCheck, type ConformanceOptions, type ConformanceReport } from './harness.js';
+
+export interface LLMClientConformanceOptions extends ConformanceOptions {
+  /** Prompt that should get a short text reply */
+  prompt?: string;
+  /** Tools offered in the tool call check */
+  tools?: LLMTool[];
+  /** Prompt that should make the model call one of the tools */
+  toolPrompt?: string;
+  /** Client whose requests fail (bad key, unreachable URL, ...) */
+  createFailingClient?: () => LLMClient;
+}
+
+const DEFAULT_TOOLS: LLMTool[] = [
+  {
+    name: 'get_weather',
+    description: 'Get the current weather for a city',
+    inputSchema: {
+      type: 'object',
+      properties: { city: { type: 'string', description: 'City name' } },
+      required: ['city'],
+    },
+  },
+];
+
+interface Transcript {
+  chunks: StreamChunk[];
+  onChunk: StreamChunk[];
+  onDone: number;
+  errors: Error[];
+  thrown?: unknown;
+}
+
+/**
+ * Drain a chat()/complete() stream, recording chunks and callbacks
+ */
+async function record(
+  stream: (callbacks: LLMChatCallbacks) => AsyncGenerator<StreamChunk, void, unknown>
+): Promise<Transcript> {
+  const transcript: Transcript = { chunks: [], onChunk: [], onDone: 0, errors: [] };
+  try {
+    for await (const chunk of stream({
+      onChunk: chunk => transcript.onChunk.push(chunk),
+      onDone: () => transcript.onDone++,
+      onError: error => transcript.errors.push(error),
+    })) {
+      transcript.chunks.push(chunk);
+    }
+  } catch (error) {
+    transcript.thrown = error;
+  }
+  return transcript;
+}
+
+/**
+ * Shared checks for a successful text reply
+ */
+function checkTextReply(t: Transcript, method: string): void {
+  check(t.thrown === undefined, `${method}() threw instead of yielding an error chunk: ${String(t.thrown)}`);
+  const failure = t.chunks.find(c => c.error);
+  check(!failure, `${method}() failed: ${failure?.error}`);
+
+  const doneIndex = t.chunks.findIndex(c => c.done);
+  check(doneIndex >= 0, `${method}() never yielded a done chunk`);
+  check(t.chunks.filter(c => c.done).length === 1, `${method}() yielded more than one done chunk`);
+  const late = t.chunks.slice(doneIndex + 1).find(c => c.token || c.tool_call || c.thinking);
+  check(!late, `${method}() yielded content after the done chunk`);
+
+  for (const chunk of t.chunks) {
+    if (chunk.token !== undefined) {
+      check(typeof chunk.token === 'string' && chunk.token.length > 0, `${method}() yielded an empty token`);
+    }
+    if (chunk.usage) {
+      check(chunk.usage.inputTokens >= 0 && chunk.usage.outputTokens >= 0, `${method}() reported negative usage`);
+    }
+  }
+  check(t.chunks.some(c => c.token), `${method}() produced no text`);
+  check(t.onDone === 1, `onDone was called ${t.onDone} times (expected 1)`);
+  check(t.errors.length === 0, `onError was called: ${t.errors[0]?.message}`);
+}
+
+/**
+ * Run the LLMClient conformance checks
+ */
+export async function runLLMClientConformance(
+  client: LLMClient,
+  options: LLMClientConformanceOptions = {}
+): Promise<ConformanceReport> {
+  const messages: LLMMessage[] = [{ role: 'user', content: options.prompt ?? 'Reply with the single word OK.' }];
+  const tools = options.tools ?? DEFAULT_TOOLS;
+  const toolMessages: LLMMessage[] = [{
+    role: 'user',
+    content: options.toolPrompt ?? `Call the ${tools[0]?.name} tool now. Do not answer in text.`,
+  }];
+
+  // The text reply is shared by several checks
+  let reply: Promise<Transcript> | null = null;
+  const textReply = () => (reply ??= record(callbacks => client.chat(messages, [], callbacks)));
+
+  const checks: ConformanceCheck[] = [
+    {
+      name: 'identity: getModel() and getBaseURL() return strings',
+      run: async () => {
+        check(typeof client.getModel() === 'string' && client.getModel().length > 0, 'getModel() returned an empty value');
+        check(typeof client.getBaseURL() === 'string', 'getBaseURL() did not return a string');
+      },
+    },
+    {
+      name: 'chat: text reply ends with exactly one done chunk',
+      run: async () => checkTextReply(await textReply(), 'chat'),
+    },
+    {
+      name: 'chat: onChunk receives every text chunk',
+      run: async () => {
+        const t = await textReply();
+        const tokens = t.chunks.filter(c => c.token).map(c => c.token);
+        const seen = t.onChunk.filter(c => c.token).map(c => c.token);
+        check(JSON.stringify(seen) === JSON.stringify(tokens), `onChunk saw ${seen.length} text chunks, the stream yielded ${tokens.length}`);
+      },
+    },
+    {
+      name: 'chat: tool calls are announced, then completed under the same id',
+      run: async () => {
+        const t = await record(callbacks => client.chat(toolMessages, tools, callbacks));
+        check(t.thrown === undefined, `chat() threw: ${String(t.thrown)}`);
+        const failure = t.chunks.find(c => c.error);
+        check(!failure, `chat() failed: ${failure?.error}`);
+
+        const calls = t.chunks.filter(c => c.tool_call);
+        if (calls.length === 0) {
+          skip('the model answered without calling a tool');
+        }
+
+        const open = new Set<string>();
+        for (const chunk of calls) {
+          const call = chunk.tool_call!;
+          check(typeof call.id === 'string' && call.id.length > 0, 'tool_call has no id');
+          check(chunk.tool_call_id === call.id, `tool_call_id ${chunk.tool_call_id} does not match tool_call.id ${call.id}`);
+          check(tools.some(tool => tool.name === call.name), `tool_call names unknown tool "${call.name}"`);
+          check(call.input !== null && typeof call.input === 'object' && !Array.isArray(call.input), `tool_call ${call.id} input is not an object`);
+          if (chunk.tool_use_complete) {
+            check(open.delete(call.id), `tool ${call.id} completed without being announced`);
+          } else {
+            open.add(call.id);
+          }
+        }
+        check(open.size === 0, `tool calls never completed: ${[...open].join(', ')}`);
+        check(t.chunks.filter(c => c.done).length === 1, 'tool call reply did not end with exactly one done chunk');
+      },
+    },
+    {
+      name: 'chat: failures are yielded as error chunks, not thrown',
+      run: async () => {
+        if (!options.createFailingClient) {
+          skip('no createFailingClient option');
+        }
+        const failing = options.createFailingClient();
+        const t = await record(callbacks => failing.chat(messages, [], callbacks));
+        check(t.thrown === undefined, `chat() threw: ${String(t.thrown)}`);
+        const failure = t.chunks.find(c => c.error);
+        check(failure, 'chat() reported no error');
+        check(typeof failure.error === 'string' && failure.error.length > 0, 'error chunk has an empty message');
+        check(failure.done === true, 'error chunk is not marked done');
+        check(t.errors.length === 1, `onError was called ${t.errors.length} times (expected 1)`);
+      },
+    },
+    {
+      name: 'complete: yields the same contract as chat',
+      run: async () => {
+        if (!client.complete) {
+          skip('client does not implement complete()');
+        }
+        checkTextReply(await record(callbacks => client.complete!(messages, [], callbacks)), 'complete');
+      },
+    },
+  ];
+
+  return runChecks(`LLMClient ${client.getModel()} @ ${client.getBaseURL()}`, checks, options);
+}
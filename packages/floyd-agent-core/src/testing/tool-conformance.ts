@@ -0,0 +1,167 @@
+/**
+ * Tool conformance checks
+ *
+ * Exercises a tool provider - anything with MCPClientManager's listTools()
+ * and callTool(), such as an MCP server wrapped in a client - against the
+ * contract AgentEngine relies on:
+ * - tool names are unique and accepted by every provider's function-name rules
+ * - each tool has a description and an object JSON schema for its input
+ * - bad input and unknown tools fail cleanly (an isError result or a rejected
+ *   Error) instead of hanging or reporting success
+ * - results are MCP content blocks
+ *
+ * Tools are called with empty input to check validation, so a tool must
+ * reject missing required arguments before doing any work.
+ */
+
+import type { MCPTool, MCPCallResult } from '../mcp/types.js';
+import { check, runChecks, type ConformanceCheck, type ConformanceOptions, type ConformanceReport } from './harness.js';
+
+/**
+ * The tool side of the agent (MCPClientManager implements it)
+ */
+export interface ToolProvider {
+  listTools(): Promise<MCPTool[]>;
+  callTool(name: string, args: Record<string, any>): Promise<MCPCallResult>;
+}
+
+/**
+ * A known call and its expected outcome
+ */
+export interface ToolCase {
+  tool: string;
+  input: Record<string, unknown>;
+  /** Whether the call should fail (default: false) */
+  expectError?: boolean;
+  /** Text the result should contain */
+  expectText?: string | RegExp;
+}
+
+export interface ToolConformanceOptions extends ConformanceOptions {
+  /** Calls to run and verify */
+  cases?: ToolCase[];
+  /** Tools excluded from the empty-input check */
+  skipInvalidInput?: string[];
+}
+
+// Strictest common rule across providers (OpenAI function names)
+const TOOL_NAME_PATTERN = /^[a-zA-Z0-9_-]{1,64}$/;
+
+type Outcome = { result: MCPCallResult } | { error: unknown };
+
+async function call(provider: ToolProvider, name: string, input: Record<string, unknown>): Promise<Outcome> {
+  try {
+    return { result: await provider.callTool(name, input) };
+  } catch (error) {
+    return { error };
+  }
+}
+
+/**
+ * Check a call failed cleanly
+ */
+function checkFailure(outcome: Outcome, what: string): void {
+  if ('error' in outcome) {
+    check(outcome.error instanceof Error && outcome.error.message.length > 0, `${what} rejected with a non-Error or empty message`);
+  } else {
+    check(outcome.result?.isError === true, `${what} reported success`);
+  }
+}
+
+/**
+ * Check result content blocks
+ */
+function checkContent(result: MCPCallResult, what: string): void {
+  check(result && Array.isArray(result.content), `${what} returned no content array`);
+  for (const block of result.content) {
+    check(typeof block.type === 'string' && block.type.length > 0, `${what} returned a content block without a type`);
+    if (block.type === 'text') {
+      check(typeof block.text === 'string', `${what} returned a text block without text`);
+    }
+  }
+}
+
+function resultText(result: MCPCallResult): string {
+  return result.content
+    .filter(block => block.type === 'text')
+    .map(block => block.text)
+    .join('\n');
+}
+
+/**
+ * Run the tool conformance checks
+ */
+export async function runToolConformance(
+  provider: ToolProvider,
+  options: ToolConformanceOptions = {}
+): Promise<ConformanceReport> {
+  let listed: Promise<MCPTool[]> | null = null;
+  const tools = () => (listed ??= provider.listTools());
+
+  const checks: ConformanceCheck[] = [
+    {
+      name: 'listTools: names are unique and provider-safe',
+      run: async () => {
+        const seen = new Set<string>();
+        for (const tool of await tools()) {
+          check(TOOL_NAME_PATTERN.test(tool.name), `tool name "${tool.name}" must match ${TOOL_NAME_PATTERN}`);
+          check(!seen.has(tool.name), `tool name "${tool.name}" is listed twice`);
+          seen.add(tool.name);
+        }
+      },
+    },
+    {
+      name: 'listTools: every tool has a description and an object input schema',
+      run: async () => {
+        for (const tool of await tools()) {
+          check(typeof tool.description === 'string' && tool.description.trim().length > 0, `${tool.name} has no description`);
+          const schema = tool.inputSchema;
+          check(schema && schema.type === 'object', `${tool.name} inputSchema is not { type: "object" }`);
+          const properties = schema.properties ?? {};
+          check(typeof properties === 'object' && !Array.isArray(properties), `${tool.name} inputSchema.properties is not an object`);
+          for (const name of schema.required ?? []) {
+            check(name in properties, `${tool.name} requires "${name}" but does not define it`);
+          }
+        }
+      },
+    },
+    {
+      name: 'callTool: missing required input fails cleanly',
+      run: async () => {
+        const skipped = new Set(options.skipInvalidInput ?? []);
+        for (const tool of await tools()) {
+          if (skipped.has(tool.name) || !tool.inputSchema.required?.length) {
+            continue;
+          }
+          checkFailure(await call(provider, tool.name, {}), `${tool.name} with empty input`);
+        }
+      },
+    },
+    {
+      name: 'callTool: unknown tools fail cleanly',
+      run: async () => {
+        checkFailure(await call(provider, '__conformance_missing_tool__', {}), 'an unknown tool');
+      },
+    },
+    ...(options.cases ?? []).map((c, i): ConformanceCheck => ({
+      name: `case ${i + 1}: ${c.tool}${c.expectError ? ' fails' : ''}`,
+      run: async () => {
+        const outcome = await call(provider, c.tool, c.input);
+        if (c.expectError) {
+          checkFailure(outcome, c.tool);
+          return;
+        }
+        check('result' in outcome, `${c.tool} rejected: ${'error' in outcome ? String(outcome.error) : ''}`);
+        checkContent(outcome.result, c.tool);
+        check(!outcome.result.isError, `${c.tool} failed: ${resultText(outcome.result)}`);
+        if (c.expectText !== undefined) {
+          const text = resultText(outcome.result);
+          const matches = typeof c.expectText === 'string' ? text.includes(c.expectText) : c.expectText.test(text);
+          check(matches, `${c.tool} result does not contain ${c.expectText}`);
+        }
+      },
+    })),
+  ];
+
+  return runChecks('tool provider', checks, options);
+}
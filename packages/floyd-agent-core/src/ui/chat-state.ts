@@ -0,0 +1,384 @@
+/**
+ * Chat State
+ *
+ * Pure state transitions for one agent run (submit → thinking → streaming →
+ * done/failed), shared by the Ink TUI and the wrapper CLI. Each frontend
+ * turns engine output, timers and randomness into RunEvents;
+ * reduceRunEvent() folds them into a RunState, and diffRunState() lists the
+ * updates needed to get from one state to the next. IO stays at the edges,
+ * so tests can replay an event list and assert the exact state without
+ * streams, timers or a renderer.
+ *
+ * @module ui/chat-state
+ */
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Status shown by a frontend's thinking indicator
+ */
+export type RunStatus = 'idle' | 'thinking' | 'streaming' | 'complete' | 'error';
+
+/**
+ * Why a reply stopped before the model finished it: a steering message
+ * ('interrupted') or /cancel and Esc ('cancelled')
+ */
+export type StopReason = 'interrupted' | 'cancelled';
+
+/**
+ * A tool call made during an assistant message, with its full output
+ */
+export interface ChatToolCall {
+  /** Tool call id (from the model, or chosen by the frontend) */
+  id: string;
+  /** Tool name */
+  name: string;
+  /** Execution status */
+  status: 'running' | 'success' | 'error';
+  /** Output as returned to the model */
+  output?: string;
+  /** Error message if the call failed */
+  error?: string;
+  /** When the call started */
+  startedAt: number;
+  /** Duration in milliseconds */
+  duration?: number;
+}
+
+/**
+ * A message added or updated by a run
+ */
+export interface ChatMessage {
+  id: string;
+  role: 'user' | 'assistant' | 'system';
+  content: string;
+  timestamp: number;
+  /** Whether this message is currently being streamed */
+  streaming?: boolean;
+  /** Tools the assistant called while writing this message */
+  toolCalls?: ChatToolCall[];
+  /** Reasoning the model wrote before the reply (shown collapsed) */
+  thinking?: string;
+  /** Set when the user stopped the reply; the partial text is kept */
+  stopReason?: StopReason;
+}
+
+/**
+ * Something that happened during a run
+ *
+ * Timestamps, ids and whimsical phrases are chosen by the caller so the
+ * reducer stays deterministic.
+ */
+export type RunEvent =
+  | {
+      type: 'submitted';
+      text: string;
+      userMessageId: string;
+      assistantMessageId: string;
+      phrase: string;
+      at: number;
+    }
+  | { type: 'thinking_started'; phrase: string }
+  | { type: 'thinking_ended' }
+  | { type: 'text'; text: string }
+  | { type: 'thinking'; text: string }
+  | { type: 'tool_started'; id: string; name: string; at: number }
+  | { type: 'tool_finished'; id: string; output?: string; error?: string; at: number }
+  | { type: 'completed'; at: number }
+  | { type: 'interrupted'; at: number; reason?: StopReason }
+  | { type: 'failed'; message: string; details?: string; errorMessageId: string; at: number }
+  | { type: 'finished' };
+
+export interface RunState {
+  /** Whether a run is in progress (input is blocked) */
+  busy: boolean;
+  /** Status shown by the thinking indicator */
+  status: RunStatus;
+  /** Whimsical phrase for the status bar */
+  phrase: string | null;
+  /** Inside a <thinking> block (text is not shown) */
+  inThinkingBlock: boolean;
+  /** Assistant reply received so far */
+  content: string;
+  /** Messages this run added, in order */
+  messages: ChatMessage[];
+}
+
+/**
+ * A store/UI update derived from a state change
+ */
+export type RunEffect =
+  | { type: 'add_message'; message: ChatMessage }
+  | { type: 'update_message'; id: string; updates: Partial<ChatMessage> }
+  | { type: 'append_streaming'; text: string }
+  | { type: 'clear_streaming' }
+  | { type: 'set_busy'; busy: boolean }
+  | { type: 'set_status'; status: RunStatus }
+  | { type: 'set_phrase'; phrase: string | null };
+
+// ============================================================================
+// REDUCER
+// ============================================================================
+
+/**
+ * Appended to a reply that was cut short by a steering message
+ */
+export const INTERRUPTED_MARKER = '[Interrupted by user]';
+
+/**
+ * Appended to a reply that was cancelled; "keep going" picks it up again
+ */
+export const CANCELLED_MARKER = '[Cancelled by user]';
+
+/**
+ * Partial reply as kept in the conversation, with the marker for why it stopped
+ */
+export function markStoppedReply(content: string, reason: StopReason = 'interrupted'): string {
+  const marker = reason === 'cancelled' ? CANCELLED_MARKER : INTERRUPTED_MARKER;
+  return content ? `${content}\n\n${marker}` : marker;
+}
+
+export function initialRunState(): RunState {
+  return {
+    busy: false,
+    status: 'idle',
+    phrase: null,
+    inThinkingBlock: false,
+    content: '',
+    messages: [],
+  };
+}
+
+function replaceMessage(
+  messages: ChatMessage[],
+  id: string,
+  updates: Partial<ChatMessage>,
+): ChatMessage[] {
+  return messages.map(message => (message.id === id ? { ...message, ...updates } : message));
+}
+
+/**
+ * Update the tool calls of the run's assistant message
+ */
+function updateToolCalls(
+  state: RunState,
+  update: (toolCalls: ChatToolCall[]) => ChatToolCall[],
+): RunState {
+  const assistant = state.messages.find(m => m.role === 'assistant');
+  if (!assistant) {
+    return state;
+  }
+  return {
+    ...state,
+    messages: replaceMessage(state.messages, assistant.id, { toolCalls: update(assistant.toolCalls ?? []) }),
+  };
+}
+
+/**
+ * Apply one event to the run state
+ */
+export function reduceRunEvent(state: RunState, event: RunEvent): RunState {
+  switch (event.type) {
+    case 'submitted':
+      return {
+        ...initialRunState(),
+        busy: true,
+        status: 'thinking',
+        phrase: event.phrase,
+        messages: [
+          { id: event.userMessageId, role: 'user', content: event.text, timestamp: event.at },
+          { id: event.assistantMessageId, role: 'assistant', content: '', timestamp: event.at, streaming: true },
+        ],
+      };
+
+    case 'thinking_started':
+      return { ...state, inThinkingBlock: true, status: 'thinking', phrase: event.phrase };
+
+    case 'thinking_ended':
+      return { ...state, inThinkingBlock: false, status: 'streaming', phrase: null };
+
+    case 'text': {
+      // Thinking content is shown by the thinking indicator, not the reply
+      if (state.inThinkingBlock || !event.text) {
+        return state;
+      }
+      const content = state.content + event.text;
+      const assistant = state.messages.find(m => m.role === 'assistant');
+      return {
+        ...state,
+        content,
+        messages: assistant
+          ? replaceMessage(state.messages, assistant.id, { content, streaming: true })
+          : state.messages,
+      };
+    }
+
+    case 'thinking': {
+      // Reasoning blocks and <thinking> tag content, kept apart from the reply
+      const assistant = state.messages.find(m => m.role === 'assistant');
+      if (!assistant || !event.text) {
+        return state;
+      }
+      return {
+        ...state,
+        messages: replaceMessage(state.messages, assistant.id, {
+          thinking: (assistant.thinking ?? '') + event.text,
+        }),
+      };
+    }
+
+    case 'tool_started':
+      return updateToolCalls(state, toolCalls => [
+        ...toolCalls,
+        { id: event.id, name: event.name, status: 'running', startedAt: event.at },
+      ]);
+
+    case 'tool_finished':
+      return updateToolCalls(state, toolCalls =>
+        toolCalls.map(call =>
+          call.id === event.id
+            ? {
+                ...call,
+                status: event.error ? 'error' : 'success',
+                output: event.output,
+                error: event.error,
+                duration: event.at - call.startedAt,
+              }
+            : call,
+        ),
+      );
+
+    case 'completed': {
+      const assistant = state.messages.find(m => m.role === 'assistant');
+      return {
+        ...state,
+        messages: assistant
+          ? replaceMessage(state.messages, assistant.id, {
+              content: state.content,
+              streaming: false,
+              timestamp: event.at,
+            })
+          : state.messages,
+      };
+    }
+
+    case 'interrupted': {
+      // Stopped to steer or cancelled: keep the partial reply so the
+      // conversation can go on from it
+      const stopReason = event.reason ?? 'interrupted';
+      const content = markStoppedReply(state.content, stopReason);
+      const assistant = state.messages.find(m => m.role === 'assistant');
+      return {
+        ...state,
+        content,
+        messages: assistant
+          ? replaceMessage(state.messages, assistant.id, { content, streaming: false, timestamp: event.at, stopReason })
+          : state.messages,
+      };
+    }
+
+    case 'failed': {
+      // Keep whatever part of the reply arrived before the failure
+      const assistant = state.messages.find(m => m.role === 'assistant');
+      const messages = assistant
+        ? replaceMessage(state.messages, assistant.id, { content: state.content, streaming: false })
+        : state.messages;
+      return {
+        ...state,
+        status: 'error',
+        messages: [
+          ...messages,
+          {
+            id: event.errorMessageId,
+            role: 'assistant',
+            content: `[!] Error: ${event.message}${event.details ? `\n\n${event.details}` : ''}`,
+            timestamp: event.at,
+          },
+        ],
+      };
+    }
+
+    case 'finished':
+      return { ...state, busy: false, status: 'idle', phrase: null, inThinkingBlock: false };
+  }
+}
+
+/**
+ * Fold a list of events into the resulting state
+ */
+export function replayRunEvents(events: RunEvent[], state: RunState = initialRunState()): RunState {
+  return events.reduce(reduceRunEvent, state);
+}
+
+// ============================================================================
+// EFFECTS
+// ============================================================================
+
+/**
+ * List the updates that take a frontend from `prev` to `next`
+ */
+export function diffRunState(prev: RunState, next: RunState): RunEffect[] {
+  const effects: RunEffect[] = [];
+
+  if (next.busy !== prev.busy) {
+    effects.push({ type: 'set_busy', busy: next.busy });
+  }
+  if (next.status !== prev.status) {
+    effects.push({ type: 'set_status', status: next.status });
+  }
+  if (next.phrase !== prev.phrase) {
+    effects.push({ type: 'set_phrase', phrase: next.phrase });
+  }
+
+  // The streaming buffer holds the reply while it is being written
+  const streaming = next.messages.some(m => m.streaming);
+  const wasStreaming = prev.messages.some(m => m.streaming);
+  if (streaming !== wasStreaming) {
+    effects.push({ type: 'clear_streaming' });
+  } else if (streaming && next.content !== prev.content) {
+    effects.push({ type: 'append_streaming', text: next.content.slice(prev.content.length) });
+  }
+
+  for (const message of next.messages) {
+    const before = prev.messages.find(m => m.id === message.id);
+    if (!before) {
+      effects.push({ type: 'add_message', message });
+    } else if (before !== message) {
+      const { id, ...updates } = message;
+      effects.push({ type: 'update_message', id, updates });
+    }
+  }
+
+  return effects;
+}
+
+// ============================================================================
+// ERRORS
+// ============================================================================
+
+/**
+ * Turn a run failure into a message and a hint for common API errors
+ */
+export function describeRunError(error: unknown): { message: string; details?: string } {
+  if (!(error instanceof Error)) {
+    return { message: String(error) };
+  }
+
+  const message = error.message;
+  if (message.includes('fetch') || message.includes('network')) {
+    return { message, details: 'Network error - check your internet connection and API endpoint' };
+  }
+  if (message.includes('401') || message.includes('Unauthorized')) {
+    return { message, details: 'API authentication failed - check your API key' };
+  }
+  if (message.includes('429')) {
+    return { message, details: 'Rate limit exceeded - please wait and try again' };
+  }
+  if (message.includes('500') || message.includes('502') || message.includes('503')) {
+    return { message, details: 'API server error - the service may be temporarily unavailable' };
+  }
+  // Otherwise the first lines of the stack help with debugging
+  return { message, details: error.stack?.split('\n').slice(0, 3).join('\n') };
+}
@@ -0,0 +1,35 @@
+// Frontend-neutral chat model shared by the Ink TUI and the wrapper CLI
+export {
+  initialRunState,
+  reduceRunEvent,
+  replayRunEvents,
+  diffRunState,
+  describeRunError,
+  INTERRUPTED_MARKER,
+  CANCELLED_MARKER,
+  markStoppedReply,
+} from './chat-state.js';
+export type {
+  RunStatus,
+  StopReason,
+  RunEvent,
+  RunState,
+  RunEffect,
+  ChatMessage,
+  ChatToolCall,
+} from './chat-state.js';
+export { StreamTagParser } from './tag-parser.js';
+export type { TagEvent } from './tag-parser.js';
+export {
+  ThemeManager,
+  CRUSH_PALETTE,
+  BUILTIN_THEMES,
+  DEFAULT_THEME_NAME,
+  getThemesDir,
+  deriveThemeRoles,
+  parseThemeFile,
+  loadThemes,
+} from './theme.js';
+export type { ThemePalette, ThemeRoles, ThemeDefinition, ThemeManagerOptions } from './theme.js';
+export { parseToml } from './toml.js';
+export type { TomlTable, TomlValue } from './toml.js';
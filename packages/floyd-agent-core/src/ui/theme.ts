@@ -0,0 +1,341 @@
+/**
+ * Theme Engine
+ *
+ * One palette for both frontends. Themes are the built-in CRUSH palette plus
+ * any *.toml / *.json files in ~/.floyd/themes/; a file only needs the colors
+ * it changes, the rest come from CRUSH. The Ink TUI and the wrapper CLI map
+ * the palette (and the role colors derived from it) onto their own theme
+ * objects, and re-apply it when ThemeManager reports a change.
+ *
+ * Theme file (TOML):
+ *
+ *   name = "dusk"
+ *   description = "Warmer accents"
+ *
+ *   [colors]
+ *   primary = "#FF985A"
+ *   bgBase = "#1A1820"
+ *
+ * JSON files use the same shape.
+ *
+ * @module ui/theme
+ */
+
+import { readFileSync, readdirSync, existsSync } from 'fs';
+import { homedir } from 'os';
+import { basename, extname, join } from 'path';
+import { parseToml } from './toml.js';
+
+// ============================================================================
+// TYPES
+// ============================================================================
+
+/**
+ * Colors every theme defines (hex strings)
+ */
+export interface ThemePalette {
+  /** Main accent (Charple) */
+  primary: string;
+  /** Second accent (Dolly) */
+  secondary: string;
+  /** Third accent (Bok) */
+  accent: string;
+  /** Highlights, thinking, system labels (Zest) */
+  highlight: string;
+  /** Informational accent (Malibu) */
+  info: string;
+  success: string;
+  error: string;
+  warning: string;
+  /** Main background (Pepper) */
+  bgBase: string;
+  /** Elevated elements (BBQ) */
+  bgElevated: string;
+  /** Overlays and borders (Charcoal) */
+  bgOverlay: string;
+  /** Modals and dialogs (Iron) */
+  bgModal: string;
+  /** Body text (Ash) */
+  textPrimary: string;
+  /** Muted text (Squid) */
+  textSecondary: string;
+  /** Softer body text (Smoke) */
+  textTertiary: string;
+  /** Hints and line numbers (Oyster) */
+  textSubtle: string;
+  /** Selected text (Salt) */
+  textSelected: string;
+  /** Text on accent backgrounds (Butter) */
+  textInverse: string;
+}
+
+/**
+ * Colors for specific UI roles, derived from a palette
+ */
+export interface ThemeRoles {
+  headerTitle: string;
+  headerStatus: string;
+  userLabel: string;
+  assistantLabel: string;
+  systemLabel: string;
+  toolLabel: string;
+  thinking: string;
+  inputPrompt: string;
+  hint: string;
+}
+
+export interface ThemeDefinition {
+  name: string;
+  description?: string;
+  /** File the theme was loaded from (undefined for built-ins) */
+  source?: string;
+  colors: ThemePalette;
+}
+
+// ============================================================================
+// BUILT-IN THEMES
+// ============================================================================
+
+/**
+ * The CRUSH palette (CharmTone), default for both frontends
+ */
+export const CRUSH_PALETTE: ThemePalette = {
+  primary: '#6B50FF',
+  secondary: '#FF60FF',
+  accent: '#68FFD6',
+  highlight: '#E8FE96',
+  info: '#00A4FF',
+  success: '#12C78F',
+  error: '#EB4268',
+  warning: '#E8FE96',
+  bgBase: '#201F26',
+  bgElevated: '#2d2c35',
+  bgOverlay: '#3A3943',
+  bgModal: '#4D4C57',
+  textPrimary: '#DFDBDD',
+  textSecondary: '#959AA2',
+  textTertiary: '#BFBCC8',
+  textSubtle: '#706F7B',
+  textSelected: '#F1EFEF',
+  textInverse: '#FFFAF1',
+};
+
+export const DEFAULT_THEME_NAME = 'crush';
+
+export const BUILTIN_THEMES: ThemeDefinition[] = [
+  { name: DEFAULT_THEME_NAME, description: 'CharmTone neon on dark (default)', colors: CRUSH_PALETTE },
+];
+
+/**
+ * Directory user theme files are read from
+ */
+export function getThemesDir(): string {
+  return join(homedir(), '.floyd', 'themes');
+}
+
+/**
+ * Role colors for a palette
+ */
+export function deriveThemeRoles(colors: ThemePalette): ThemeRoles {
+  return {
+    headerTitle: colors.secondary,
+    headerStatus: colors.textPrimary,
+    userLabel: colors.success,
+    assistantLabel: colors.info,
+    systemLabel: colors.highlight,
+    toolLabel: colors.accent,
+    thinking: colors.highlight,
+    inputPrompt: colors.success,
+    hint: colors.textSecondary,
+  };
+}
+
+// ============================================================================
+// PARSING
+// ============================================================================
+
+const HEX_COLOR = /^#([0-9a-f]{3}|[0-9a-f]{6})$/i;
+
+/**
+ * Parse a theme file's contents
+ * Colors the file leaves out are taken from CRUSH; unknown color names and
+ * malformed hex values are errors so typos do not go unnoticed.
+ *
+ * @param fallbackName - Name used when the file has no `name` (usually the file name)
+ */
+export function parseThemeFile(
+  content: string,
+  format: 'toml' | 'json',
+  fallbackName: string,
+): ThemeDefinition {
+  const data = (format === 'toml' ? parseToml(content) : JSON.parse(content)) as Record<string, unknown>;
+  if (!data || typeof data !== 'object') {
+    throw new Error('theme must be an object');
+  }
+
+  const name = typeof data.name === 'string' && data.name.trim() ? data.name.trim() : fallbackName;
+  const colors: ThemePalette = { ...CRUSH_PALETTE };
+  const given = data.colors ?? {};
+  if (typeof given !== 'object') {
+    throw new Error('"colors" must be a table');
+  }
+
+  for (const [key, value] of Object.entries(given as Record<string, unknown>)) {
+    if (!(key in CRUSH_PALETTE)) {
+      throw new Error(`unknown color "${key}"`);
+    }
+    if (typeof value !== 'string' || !HEX_COLOR.test(value)) {
+      throw new Error(`color "${key}" must be a hex value like "#6B50FF"`);
+    }
+    colors[key as keyof ThemePalette] = value;
+  }
+
+  return {
+    name,
+    description: typeof data.description === 'string' ? data.description : undefined,
+    colors,
+  };
+}
+
+/**
+ * Load built-in themes plus every *.toml / *.json file in a directory
+ * Files that fail to parse are reported in `errors` and skipped; a file
+ * whose name matches a built-in replaces it.
+ */
+export function loadThemes(dir: string = getThemesDir()): { themes: ThemeDefinition[]; errors: string[] } {
+  const themes = new Map(BUILTIN_THEMES.map(theme => [theme.name, theme]));
+  const errors: string[] = [];
+
+  const files = existsSync(dir) ? readdirSync(dir).sort() : [];
+  for (const file of files) {
+    const ext = extname(file).toLowerCase();
+    if (ext !== '.toml' && ext !== '.json') {
+      continue;
+    }
+    const path = join(dir, file);
+    try {
+      const theme = parseThemeFile(readFileSync(path, 'utf-8'), ext === '.toml' ? 'toml' : 'json', basename(file, ext));
+      themes.set(theme.name, { ...theme, source: path });
+    } catch (error) {
+      errors.push(`${file}: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+
+  return { themes: [...themes.values()], errors };
+}
+
+// ============================================================================
+// MANAGER
+// ============================================================================
+
+export interface ThemeManagerOptions {
+  /** Directory to load themes from (default ~/.floyd/themes) */
+  dir?: string;
+  /** Theme to start with (default FLOYD_THEME, then crush) */
+  theme?: string;
+}
+
+/**
+ * Holds the loaded themes and the active one
+ * Listeners are called whenever the active theme's colors may have changed
+ * (switching themes or reloading files).
+ */
+export class ThemeManager {
+  private readonly dir: string;
+  private themes: ThemeDefinition[] = BUILTIN_THEMES;
+  private active: ThemeDefinition = BUILTIN_THEMES[0];
+  private listeners = new Set<(theme: ThemeDefinition) => void>();
+  private lastErrors: string[] = [];
+
+  constructor(options: ThemeManagerOptions = {}) {
+    this.dir = options.dir ?? getThemesDir();
+    this.reload({ silent: true });
+
+    const initial = options.theme ?? process.env.FLOYD_THEME;
+    const theme = initial ? this.find(initial) : undefined;
+    if (theme) {
+      this.active = theme;
+    } else if (initial) {
+      this.lastErrors.push(`theme "${initial}" not found, using ${DEFAULT_THEME_NAME}`);
+    }
+  }
+
+  /**
+   * The active theme
+   */
+  get current(): ThemeDefinition {
+    return this.active;
+  }
+
+  /**
+   * Problems from the last load (unparseable files, missing theme)
+   */
+  get errors(): string[] {
+    return this.lastErrors;
+  }
+
+  get directory(): string {
+    return this.dir;
+  }
+
+  list(): ThemeDefinition[] {
+    return this.themes;
+  }
+
+  find(name: string): ThemeDefinition | undefined {
+    const lower = name.toLowerCase();
+    return this.themes.find(theme => theme.name.toLowerCase() === lower);
+  }
+
+  /**
+   * Switch to a theme by name
+   * Throws if no theme has that name
+   */
+  use(name: string): ThemeDefinition {
+    const theme = this.find(name);
+    if (!theme) {
+      throw new Error(`Unknown theme "${name}". Available: ${this.themes.map(t => t.name).join(', ')}`);
+    }
+    this.active = theme;
+    this.emit();
+    return theme;
+  }
+
+  /**
+   * Re-read the theme directory, keeping the active theme if it still exists
+   * Returns the problems found (also available as `errors`)
+   */
+  reload({ silent = false }: { silent?: boolean } = {}): string[] {
+    const { themes, errors } = loadThemes(this.dir);
+    this.themes = themes;
+    this.lastErrors = errors;
+
+    const active = this.find(this.active.name);
+    if (!active) {
+      errors.push(`theme "${this.active.name}" is gone, using ${DEFAULT_THEME_NAME}`);
+    }
+    this.active = active ?? BUILTIN_THEMES[0];
+
+    if (!silent) {
+      this.emit();
+    }
+    return errors;
+  }
+
+  /**
+   * Subscribe to theme changes
+   * Returns a function that unsubscribes
+   */
+  onChange(listener: (theme: ThemeDefinition) => void): () => void {
+    this.listeners.add(listener);
+    return () => {
+      this.listeners.delete(listener);
+    };
+  }
+
+  private emit(): void {
+    for (const listener of this.listeners) {
+      listener(this.active);
+    }
+  }
+}
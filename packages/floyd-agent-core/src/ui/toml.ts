@@ -0,0 +1,97 @@
+/**
+ * Minimal TOML Reader
+ *
+ * Reads the subset of TOML used by theme files: comments, [tables]
+ * (dotted names allowed), and `key = value` pairs where the value is a
+ * basic or literal string, a number, or a boolean. Arrays, inline tables
+ * and multi-line strings are rejected with the line number.
+ *
+ * @module ui/toml
+ */
+
+export type TomlTable = { [key: string]: TomlValue };
+export type TomlValue = string | number | boolean | TomlTable;
+
+/**
+ * Parse a TOML document into nested objects
+ */
+export function parseToml(source: string): TomlTable {
+  const root: TomlTable = {};
+  let table = root;
+
+  source.split(/\r?\n/).forEach((raw, index) => {
+    const lineNo = index + 1;
+    const line = raw.trim();
+    if (!line || line.startsWith('#')) {
+      return;
+    }
+
+    const header = line.match(/^\[([^\]]+)\]\s*(#.*)?$/);
+    if (header) {
+      table = root;
+      for (const key of splitKey(header[1], lineNo)) {
+        const next = table[key];
+        if (next === undefined) {
+          table = table[key] = {};
+        } else if (typeof next === 'object') {
+          table = next;
+        } else {
+          throw new Error(`line ${lineNo}: "${key}" is already a value`);
+        }
+      }
+      return;
+    }
+
+    const eq = line.indexOf('=');
+    if (eq === -1) {
+      throw new Error(`line ${lineNo}: expected key = value`);
+    }
+    const keys = splitKey(line.slice(0, eq), lineNo);
+    const last = keys.pop()!;
+    let target = table;
+    for (const key of keys) {
+      const next = target[key] ?? (target[key] = {});
+      if (typeof next !== 'object') {
+        throw new Error(`line ${lineNo}: "${key}" is already a value`);
+      }
+      target = next;
+    }
+    target[last] = parseValue(line.slice(eq + 1).trim(), lineNo);
+  });
+
+  return root;
+}
+
+function splitKey(key: string, lineNo: number): string[] {
+  const parts = key.split('.').map(part => part.trim().replace(/^"(.*)"$/, '$1'));
+  if (parts.some(part => !part)) {
+    throw new Error(`line ${lineNo}: invalid key "${key.trim()}"`);
+  }
+  return parts;
+}
+
+function parseValue(text: string, lineNo: number): TomlValue {
+  const quote = text[0];
+  if (quote === '"' || quote === "'") {
+    // Find the closing quote, skipping escapes in basic strings
+    let end = 1;
+    while (end < text.length && text[end] !== quote) {
+      end += quote === '"' && text[end] === '\\' ? 2 : 1;
+    }
+    const rest = text.slice(end + 1).trim();
+    if (end >= text.length || (rest && !rest.startsWith('#'))) {
+      throw new Error(`line ${lineNo}: unterminated string`);
+    }
+    const body = text.slice(1, end);
+    return quote === '"' ? (JSON.parse(`"${body}"`) as string) : body;
+  }
+
+  const value = text.replace(/\s+#.*$/, '');
+  if (value === 'true' || value === 'false') {
+    return value === 'true';
+  }
+  if (/^[+-]?\d[\d_]*(\.\d+)?$/.test(value)) {
+    return Number(value.replace(/_/g, ''));
+  }
+  throw new Error(`line ${lineNo}: unsupported value "${value}"`);
+}
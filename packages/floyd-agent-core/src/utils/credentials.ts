@@ -0,0 +1,382 @@
+// API key storage for `floyd auth` and /auth: keys are kept per provider in
+// the OS keychain (macOS Keychain, libsecret via secret-tool on Linux) or,
+// where neither is available, in an AES-256-GCM encrypted file under
+// ~/.floyd. Environment variables still win over stored keys.
+
+import { spawnSync } from 'child_process';
+import crypto from 'crypto';
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { PROVIDER_DEFAULTS, isOpenAICompatible, type Provider } from '../constants.js';
+
+/**
+ * Keychain service name the keys are stored under
+ */
+export const KEYCHAIN_SERVICE = 'floyd';
+
+/**
+ * Environment variables that override a stored key, per provider
+ */
+export const PROVIDER_ENV_VARS: Record<Provider, string[]> = {
+  zai: ['FLOYD_GLM_API_KEY', 'GLM_API_KEY'],
+  anthropic: ['ANTHROPIC_API_KEY'],
+  openai: ['OPENAI_API_KEY'],
+  deepseek: ['DEEPSEEK_API_KEY'],
+};
+
+const PROVIDER_ALIASES: Record<string, Provider> = {
+  glm: 'zai',
+  'z.ai': 'zai',
+};
+
+/**
+ * Provider for a name typed by the user (accepts glm for zai), or null
+ */
+export function parseProvider(name: string | undefined): Provider | null {
+  const key = (name ?? '').toLowerCase();
+  if (key in PROVIDER_DEFAULTS) {
+    return key as Provider;
+  }
+  return PROVIDER_ALIASES[key] ?? null;
+}
+
+/**
+ * Key shortened for display, e.g. sk-a…9f2c
+ */
+export function maskKey(key: string): string {
+  return key.length <= 8 ? '*'.repeat(key.length) : `${key.slice(0, 4)}…${key.slice(-4)}`;
+}
+
+/**
+ * Whether an input line carries a key and must stay out of input history
+ */
+export function isSecretCommand(line: string): boolean {
+  return /^\/auth\s+(login|set)\s+\S+\s+\S+/.test(line.trim());
+}
+
+// ============================================================================
+// Backends
+// ============================================================================
+
+/**
+ * Somewhere keys can be kept; calls are synchronous so config loading can use them
+ */
+export interface CredentialBackend {
+  readonly name: string;
+  isAvailable(): boolean;
+  get(provider: Provider): string | null;
+  set(provider: Provider, key: string): void;
+  /** Returns whether a key was removed */
+  delete(provider: Provider): boolean;
+}
+
+function run(command: string, args: string[], input?: string): { ok: boolean; stdout: string; stderr: string } {
+  const result = spawnSync(command, args, { input, encoding: 'utf8', timeout: 10_000 });
+  return {
+    ok: !result.error && result.status === 0,
+    stdout: result.stdout ?? '',
+    stderr: result.stderr ?? result.error?.message ?? '',
+  };
+}
+
+function hasCommand(command: string): boolean {
+  return run('which', [command]).ok;
+}
+
+/**
+ * macOS Keychain through the `security` tool
+ */
+export class MacKeychainBackend implements CredentialBackend {
+  readonly name = 'macOS Keychain';
+
+  isAvailable(): boolean {
+    return process.platform === 'darwin' && hasCommand('security');
+  }
+
+  get(provider: Provider): string | null {
+    const result = run('security', ['find-generic-password', '-s', KEYCHAIN_SERVICE, '-a', provider, '-w']);
+    return result.ok ? result.stdout.trim() || null : null;
+  }
+
+  set(provider: Provider, key: string): void {
+    // -U updates an existing item instead of failing. -w comes last without
+    // a value, so security reads the key (and its confirmation) from stdin
+    // and it never shows up in the process list
+    const result = run(
+      'security',
+      ['add-generic-password', '-U', '-s', KEYCHAIN_SERVICE, '-a', provider, '-w'],
+      `${key}\n${key}\n`,
+    );
+    if (!result.ok) {
+      throw new Error(`Keychain refused the key: ${result.stderr.trim()}`);
+    }
+  }
+
+  delete(provider: Provider): boolean {
+    return run('security', ['delete-generic-password', '-s', KEYCHAIN_SERVICE, '-a', provider]).ok;
+  }
+}
+
+/**
+ * Secret Service (GNOME Keyring, KWallet) through `secret-tool`
+ */
+export class SecretToolBackend implements CredentialBackend {
+  readonly name = 'Secret Service';
+
+  isAvailable(): boolean {
+    // secret-tool needs a session bus to reach the keyring
+    return process.platform === 'linux' && !!process.env['DBUS_SESSION_BUS_ADDRESS'] && hasCommand('secret-tool');
+  }
+
+  get(provider: Provider): string | null {
+    const result = run('secret-tool', ['lookup', 'service', KEYCHAIN_SERVICE, 'account', provider]);
+    return result.ok ? result.stdout.trim() || null : null;
+  }
+
+  set(provider: Provider, key: string): void {
+    // The secret is read from stdin so it never shows up in the process list
+    const result = run(
+      'secret-tool',
+      ['store', `--label=FLOYD ${provider} API key`, 'service', KEYCHAIN_SERVICE, 'account', provider],
+      key,
+    );
+    if (!result.ok) {
+      throw new Error(`Keyring refused the key: ${result.stderr.trim()}`);
+    }
+  }
+
+  delete(provider: Provider): boolean {
+    if (!this.get(provider)) {
+      return false;
+    }
+    return run('secret-tool', ['clear', 'service', KEYCHAIN_SERVICE, 'account', provider]).ok;
+  }
+}
+
+type EncryptedFile = {
+  version: 1;
+  salt: string;
+  iv: string;
+  tag: string;
+  data: string;
+};
+
+/**
+ * Fallback: one AES-256-GCM encrypted JSON file, readable only by the user.
+ * The key is derived from FLOYD_CREDENTIALS_PASSPHRASE, or from the user and
+ * host name when it isn't set (which keeps keys out of plain sight, not safe
+ * from someone with access to the account).
+ */
+export class EncryptedFileBackend implements CredentialBackend {
+  readonly name: string;
+
+  constructor(
+    private filePath: string = path.join(os.homedir(), '.floyd', 'credentials.enc'),
+    private passphrase: string = process.env['FLOYD_CREDENTIALS_PASSPHRASE'] || `${os.userInfo().username}@${os.hostname()}`,
+  ) {
+    this.name = `encrypted file (${filePath})`;
+  }
+
+  isAvailable(): boolean {
+    return true;
+  }
+
+  get(provider: Provider): string | null {
+    return this.read()[provider] ?? null;
+  }
+
+  set(provider: Provider, key: string): void {
+    this.write({ ...this.read(), [provider]: key });
+  }
+
+  delete(provider: Provider): boolean {
+    const keys = this.read();
+    if (!(provider in keys)) {
+      return false;
+    }
+    delete keys[provider];
+    this.write(keys);
+    return true;
+  }
+
+  private read(): Partial<Record<Provider, string>> {
+    if (!fs.existsSync(this.filePath)) {
+      return {};
+    }
+    const file = JSON.parse(fs.readFileSync(this.filePath, 'utf8')) as EncryptedFile;
+    const decipher = crypto.createDecipheriv('aes-256-gcm', this.deriveKey(file.salt), Buffer.from(file.iv, 'base64'));
+    decipher.setAuthTag(Buffer.from(file.tag, 'base64'));
+    const plain = Buffer.concat([decipher.update(Buffer.from(file.data, 'base64')), decipher.final()]);
+    return JSON.parse(plain.toString('utf8'));
+  }
+
+  private write(keys: Partial<Record<Provider, string>>): void {
+    const salt = crypto.randomBytes(16).toString('base64');
+    const iv = crypto.randomBytes(12);
+    const cipher = crypto.createCipheriv('aes-256-gcm', this.deriveKey(salt), iv);
+    const data = Buffer.concat([cipher.update(JSON.stringify(keys), 'utf8'), cipher.final()]);
+    const file: EncryptedFile = {
+      version: 1,
+      salt,
+      iv: iv.toString('base64'),
+      tag: cipher.getAuthTag().toString('base64'),
+      data: data.toString('base64'),
+    };
+    fs.mkdirSync(path.dirname(this.filePath), { recursive: true, mode: 0o700 });
+    fs.writeFileSync(this.filePath, JSON.stringify(file), { mode: 0o600 });
+  }
+
+  private deriveKey(salt: string): Buffer {
+    return crypto.scryptSync(this.passphrase, Buffer.from(salt, 'base64'), 32);
+  }
+}
+
+// ============================================================================
+// Store
+// ============================================================================
+
+/**
+ * Where a provider's key comes from
+ */
+export type CredentialStatus = {
+  provider: Provider;
+  /** Environment variable or backend name; null when no key is set */
+  source: string | null;
+  masked: string | null;
+};
+
+/**
+ * API keys per provider, in the first available backend
+ */
+export class CredentialStore {
+  readonly backend: CredentialBackend;
+
+  constructor(backends: CredentialBackend[] = [new MacKeychainBackend(), new SecretToolBackend(), new EncryptedFileBackend()]) {
+    const backend = backends.find(candidate => candidate.isAvailable());
+    if (!backend) {
+      throw new Error('No credential backend is available');
+    }
+    this.backend = backend;
+  }
+
+  /**
+   * Stored key of a provider; unreadable stores count as empty
+   */
+  get(provider: Provider): string | null {
+    try {
+      return this.backend.get(provider);
+    } catch {
+      return null;
+    }
+  }
+
+  set(provider: Provider, key: string): void {
+    this.backend.set(provider, key.trim());
+  }
+
+  delete(provider: Provider): boolean {
+    return this.backend.delete(provider);
+  }
+
+  /**
+   * Key for a provider: its environment variables first, then the store
+   */
+  resolve(provider: Provider, env: NodeJS.ProcessEnv = process.env): string | null {
+    for (const name of PROVIDER_ENV_VARS[provider]) {
+      if (env[name]) {
+        return env[name]!;
+      }
+    }
+    return this.get(provider);
+  }
+
+  /**
+   * Where each provider's key would come from
+   */
+  status(env: NodeJS.ProcessEnv = process.env): CredentialStatus[] {
+    return (Object.keys(PROVIDER_ENV_VARS) as Provider[]).map(provider => {
+      const envVar = PROVIDER_ENV_VARS[provider].find(name => env[name]);
+      if (envVar) {
+        return { provider, source: envVar, masked: maskKey(env[envVar]!) };
+      }
+      const stored = this.get(provider);
+      return { provider, source: stored ? this.backend.name : null, masked: stored ? maskKey(stored) : null };
+    });
+  }
+}
+
+let store: CredentialStore | null = null;
+
+/**
+ * Shared credential store
+ */
+export function getCredentialStore(): CredentialStore {
+  if (!store) {
+    store = new CredentialStore();
+  }
+  return store;
+}
+
+/**
+ * Drop the shared store (tests, or after the environment changed)
+ */
+export function resetCredentialStore(): void {
+  store = null;
+}
+
+// ============================================================================
+// Validation
+// ============================================================================
+
+export type KeyCheck = {
+  ok: boolean;
+  /** HTTP status, when the endpoint answered */
+  status?: number;
+  message: string;
+};
+
+/**
+ * Check a key with the smallest possible request to the provider
+ *
+ * @param options.endpoint - Endpoint to test (default: the provider's)
+ * @param options.model - Model to ask for (default: the provider's)
+ */
+export async function validateApiKey(
+  provider: Provider,
+  key: string,
+  options: { endpoint?: string; model?: string; timeoutMs?: number } = {},
+): Promise<KeyCheck> {
+  const endpoint = (options.endpoint ?? PROVIDER_DEFAULTS[provider].endpoint).replace(/\/+$/, '');
+  const model = options.model ?? PROVIDER_DEFAULTS[provider].model;
+  const openAI = isOpenAICompatible(endpoint);
+
+  const url = openAI
+    ? (endpoint.endsWith('/chat/completions') ? endpoint : `${endpoint}/chat/completions`)
+    : `${endpoint.replace(/\/v1$/, '')}/v1/messages`;
+  const headers: Record<string, string> = openAI
+    ? { 'Content-Type': 'application/json', Authorization: `Bearer ${key}` }
+    : { 'Content-Type': 'application/json', 'x-api-key': key, 'anthropic-version': '2023-06-01' };
+
+  try {
+    const response = await fetch(url, {
+      method: 'POST',
+      headers,
+      body: JSON.stringify({ model, max_tokens: 1, messages: [{ role: 'user', content: 'ping' }] }),
+      signal: AbortSignal.timeout(options.timeoutMs ?? 15_000),
+    });
+    if (response.ok) {
+      return { ok: true, status: response.status, message: `Key accepted by ${new URL(url).host}` };
+    }
+    if (response.status === 401 || response.status === 403) {
+      return { ok: false, status: response.status, message: `Key rejected by ${new URL(url).host} (HTTP ${response.status})` };
+    }
+    // Rate limits and model errors still mean the key itself was accepted
+    if (response.status === 429 || response.status === 400) {
+      return { ok: true, status: response.status, message: `Key accepted (HTTP ${response.status} for the test request)` };
+    }
+    return { ok: false, status: response.status, message: `${new URL(url).host} answered HTTP ${response.status}` };
+  } catch (error) {
+    return { ok: false, message: `Could not reach ${url}: ${error instanceof Error ? error.message : String(error)}` };
+  }
+}
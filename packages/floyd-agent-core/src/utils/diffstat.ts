@@ -0,0 +1,103 @@
+// Per-run file change summaries shared by both CLIs: a git-style diffstat
+// for the end-of-run summary box and a one-line description that is logged
+// to .floyd/progress.md.
+
+import { appendProgress, type ProgressEntry } from './progress.js';
+
+export type FileChangeType = 'created' | 'modified' | 'deleted';
+
+export type FileChange = {
+  /** Path as it should be shown, usually relative to the project root */
+  path: string;
+  type: FileChangeType;
+  added: number;
+  removed: number;
+};
+
+export type DiffStat = {
+  /** One line per file: marker, path, change count and +/- bar */
+  lines: Array<{ type: FileChangeType; path: string; count: number; plus: number; minus: number }>;
+  /** e.g. "3 files changed, 20 insertions(+), 5 deletions(-)" */
+  totals: string;
+};
+
+/**
+ * Widest +/- bar in a diffstat
+ */
+const MAX_BAR = 30;
+
+const plural = (count: number, word: string) => `${count} ${word}${count === 1 ? '' : 's'}`;
+
+/**
+ * Diffstat of a run's changes; bars scale so the largest change fits in
+ * `barWidth` characters (like `git diff --stat`)
+ */
+export function buildDiffStat(changes: FileChange[], barWidth: number = MAX_BAR): DiffStat {
+  const largest = Math.max(0, ...changes.map(change => change.added + change.removed));
+  const scale = largest > barWidth ? barWidth / largest : 1;
+
+  const lines = changes.map(change => {
+    const count = change.added + change.removed;
+    // Any change gets at least one mark on its side
+    const plus = change.added > 0 ? Math.max(1, Math.round(change.added * scale)) : 0;
+    const minus = change.removed > 0 ? Math.max(1, Math.round(change.removed * scale)) : 0;
+    return { type: change.type, path: change.path, count, plus, minus };
+  });
+
+  const added = changes.reduce((sum, change) => sum + change.added, 0);
+  const removed = changes.reduce((sum, change) => sum + change.removed, 0);
+  const totals = [
+    `${plural(changes.length, 'file')} changed`,
+    ...(added > 0 ? [`${plural(added, 'insertion')}(+)`] : []),
+    ...(removed > 0 ? [`${plural(removed, 'deletion')}(-)`] : []),
+  ].join(', ');
+
+  return { lines, totals };
+}
+
+/**
+ * Plain-text diffstat, e.g. " M src/app.ts | 12 ++++++++----"
+ */
+export function formatDiffStat(changes: FileChange[], barWidth: number = MAX_BAR): string[] {
+  if (changes.length === 0) {
+    return [];
+  }
+
+  const marker: Record<FileChangeType, string> = { created: 'A', modified: 'M', deleted: 'D' };
+  const stat = buildDiffStat(changes, barWidth);
+  const pathWidth = Math.max(...stat.lines.map(line => line.path.length));
+  const countWidth = Math.max(...stat.lines.map(line => String(line.count).length));
+
+  return [
+    ...stat.lines.map(line =>
+      `${marker[line.type]} ${line.path.padEnd(pathWidth)} | ${String(line.count).padStart(countWidth)} ${'+'.repeat(line.plus)}${'-'.repeat(line.minus)}`.trimEnd()
+    ),
+    stat.totals,
+  ];
+}
+
+/**
+ * One-line description of a run's changes, e.g. "2 files (+14 -3): a.ts, b.ts"
+ */
+export function describeChanges(changes: FileChange[], maxPaths = 5): string {
+  const added = changes.reduce((sum, change) => sum + change.added, 0);
+  const removed = changes.reduce((sum, change) => sum + change.removed, 0);
+  const paths = changes.slice(0, maxPaths).map(change => change.path);
+  const more = changes.length > maxPaths ? `, +${changes.length - maxPaths} more` : '';
+  return `${plural(changes.length, 'file')} (+${added} -${removed}): ${paths.join(', ')}${more}`;
+}
+
+/**
+ * Log a run's changes to .floyd/progress.md; nothing is logged for runs
+ * that changed no files
+ *
+ * @param task - The request the run worked on
+ */
+export function logRunChanges(task: string, changes: FileChange[], cwd: string = process.cwd()): ProgressEntry | null {
+  if (changes.length === 0) {
+    return null;
+  }
+  const firstLine = task.trim().split('\n')[0] ?? '';
+  const action = firstLine.length > 80 ? `${firstLine.slice(0, 79)}…` : firstLine;
+  return appendProgress({ action: action || 'Run', result: describeChanges(changes) }, cwd);
+}
@@ -4,3 +4,64 @@ export type { ConfigOptions, MCPServerConfig } from './config.js';
 // Error handling utilities
 export { humanizeError, formatHumanizedError, getSeverityEmoji } from './error-humanizer.js';
 export type { HumanizedError } from './error-humanizer.js';
+
+// Per-tool execution metrics
+export { ToolMetrics, formatToolStats, LATENCY_BUCKETS_MS } from './tool-metrics.js';
+export type { ToolStats } from './tool-metrics.js';
+
+// API keys in the OS keychain or an encrypted file
+export {
+  CredentialStore,
+  MacKeychainBackend,
+  SecretToolBackend,
+  EncryptedFileBackend,
+  getCredentialStore,
+  resetCredentialStore,
+  validateApiKey,
+  parseProvider,
+  maskKey,
+  isSecretCommand,
+  KEYCHAIN_SERVICE,
+  PROVIDER_ENV_VARS,
+} from './credentials.js';
+export type { CredentialBackend, CredentialStatus, KeyCheck } from './credentials.js';
+
+// Structured .floyd/progress.md log
+export {
+  parseProgress,
+  readProgress,
+  appendProgress,
+  queryProgress,
+  parseProgressArgs,
+  formatProgressRow,
+  formatProgressTimestamp,
+  isDuplicateProgress,
+  PROGRESS_FILE,
+  PROGRESS_COLUMNS,
+  PROGRESS_USAGE,
+} from './progress.js';
+export type { ProgressEntry, ProgressQuery } from './progress.js';
+
+// Per-run file change summaries (diffstat, progress log line)
+export { buildDiffStat, formatDiffStat, describeChanges, logRunChanges } from './diffstat.js';
+export type { FileChange, FileChangeType, DiffStat } from './diffstat.js';
+
+// Markdown/HTML conversation transcripts (/export)
+export {
+  parseTranscriptFormat,
+  renderTranscriptMarkdown,
+  renderTranscriptHtml,
+  exportTranscript,
+  TRANSCRIPT_DIR,
+} from './transcript.js';
+export type { TranscriptFormat, TranscriptMessage, TranscriptExportOptions } from './transcript.js';
+
+// Prompt history in ~/.floyd/history and fuzzy reverse search
+export {
+  loadInputHistory,
+  appendInputHistory,
+  getHistoryFilePath,
+  fuzzyScore,
+  searchInputHistory,
+  MAX_HISTORY_ENTRIES,
+} from './input-history.js';
@@ -0,0 +1,112 @@
+// Prompt history shared by both CLIs across sessions and projects in
+// ~/.floyd/history (one prompt per line, oldest first), plus the fuzzy
+// matching behind Ctrl+R reverse search and @file completion.
+
+import fs from 'fs/promises';
+import os from 'os';
+import path from 'path';
+
+/**
+ * Maximum number of entries kept in memory and on disk
+ */
+export const MAX_HISTORY_ENTRIES = 1000;
+
+/**
+ * Location of the history file (FLOYD_HISTORY_FILE overrides)
+ */
+export function getHistoryFilePath(): string {
+  return process.env.FLOYD_HISTORY_FILE || path.join(os.homedir(), '.floyd', 'history');
+}
+
+/**
+ * Load history entries, oldest first
+ */
+export async function loadInputHistory(filePath: string = getHistoryFilePath()): Promise<string[]> {
+  try {
+    const content = await fs.readFile(filePath, 'utf-8');
+    return content
+      .split('\n')
+      .filter(line => line.trim())
+      .slice(-MAX_HISTORY_ENTRIES);
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * Append an entry, compacting the file once it grows well past the limit
+ */
+export async function appendInputHistory(entry: string, filePath: string = getHistoryFilePath()): Promise<void> {
+  const line = entry.replace(/\r?\n/g, ' ').trim();
+  if (!line) {
+    return;
+  }
+
+  await fs.mkdir(path.dirname(filePath), { recursive: true });
+  await fs.appendFile(filePath, line + '\n');
+
+  const lines = (await fs.readFile(filePath, 'utf-8')).split('\n').filter(l => l.trim());
+  if (lines.length > MAX_HISTORY_ENTRIES * 1.5) {
+    await fs.writeFile(filePath, lines.slice(-MAX_HISTORY_ENTRIES).join('\n') + '\n');
+  }
+}
+
+/**
+ * Score how well a query fuzzy-matches a candidate (higher is better, null for no match).
+ * Characters must appear in order; contiguous runs and word starts score higher.
+ */
+export function fuzzyScore(query: string, candidate: string): number | null {
+  const q = query.toLowerCase();
+  const c = candidate.toLowerCase();
+  if (!q) {
+    return 0;
+  }
+
+  // Exact substring beats any scattered match
+  const substringIndex = c.indexOf(q);
+  if (substringIndex !== -1) {
+    return 1000 - substringIndex;
+  }
+
+  let score = 0;
+  let lastIndex = -1;
+  for (const char of q) {
+    const index = c.indexOf(char, lastIndex + 1);
+    if (index === -1) {
+      return null;
+    }
+    score += index === lastIndex + 1 ? 5 : 1;
+    if (index === 0 || /[\s/_.-]/.test(c[index - 1])) {
+      score += 3;
+    }
+    lastIndex = index;
+  }
+  return score;
+}
+
+/**
+ * Search history for the best fuzzy matches, most relevant (then most recent) first.
+ * An empty query lists the most recent unique entries.
+ */
+export function searchInputHistory(history: string[], query: string, limit = 10): string[] {
+  const seen = new Set<string>();
+  const matches: Array<{ entry: string; score: number; recency: number }> = [];
+
+  for (let i = history.length - 1; i >= 0; i--) {
+    const entry = history[i];
+    if (seen.has(entry)) {
+      continue;
+    }
+    seen.add(entry);
+
+    const score = fuzzyScore(query, entry);
+    if (score !== null) {
+      matches.push({ entry, score, recency: i });
+    }
+  }
+
+  return matches
+    .sort((a, b) => b.score - a.score || b.recency - a.recency)
+    .slice(0, limit)
+    .map(m => m.entry);
+}
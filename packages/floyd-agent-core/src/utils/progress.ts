@@ -0,0 +1,220 @@
+// Structured access to .floyd/progress.md, the execution log both CLIs and
+// the model append to: typed entries (timestamp, action, result, next),
+// appends that skip a repeat of the last entry, and queries for viewers
+// such as /progress (the same command and arguments in both CLIs).
+
+import fs from 'fs';
+import path from 'path';
+
+/**
+ * Location of the log, relative to the project root
+ */
+export const PROGRESS_FILE = path.join('.floyd', 'progress.md');
+
+/**
+ * Columns written for new logs
+ */
+export const PROGRESS_COLUMNS = ['Timestamp', 'Action', 'Result', 'Next'] as const;
+
+export type ProgressEntry = {
+  /** "YYYY-MM-DD HH:MM:SS" (local time) for entries written here */
+  timestamp: string;
+  action: string;
+  result: string;
+  next: string;
+};
+
+export type ProgressQuery = {
+  /** Only entries whose timestamp starts with this prefix, e.g. "2026-10" */
+  date?: string;
+  /** Case-insensitive text to find in action, result or next */
+  search?: string;
+  /** Only the last N matching entries */
+  last?: number;
+};
+
+/**
+ * Usage of /progress, shared by both CLIs
+ */
+export const PROGRESS_USAGE = '/progress [count] [--date YYYY-MM-DD] [text]';
+
+/**
+ * Header names accepted for each field (lower case)
+ */
+const FIELD_HEADERS: Record<keyof ProgressEntry, string[]> = {
+  timestamp: ['timestamp', 'time', 'date'],
+  action: ['action', 'action taken', 'task', 'step'],
+  result: ['result', 'result/status', 'outcome', 'status'],
+  next: ['next', 'next step', 'next steps'],
+};
+
+function splitRow(line: string): string[] {
+  let body = line.trim();
+  if (body.startsWith('|')) body = body.slice(1);
+  if (body.endsWith('|') && !body.endsWith('\\|')) body = body.slice(0, -1);
+  return body.split(/(?<!\\)\|/).map(cell => cell.trim().replace(/\\\|/g, '|'));
+}
+
+function isSeparator(cells: string[]): boolean {
+  return cells.length > 0 && cells.every(cell => /^:?-{2,}:?$/.test(cell));
+}
+
+/**
+ * Entries of the first table in a progress log, in file order. Columns are
+ * matched by header name, falling back to position.
+ */
+export function parseProgress(markdown: string): ProgressEntry[] {
+  const entries: ProgressEntry[] = [];
+  let columns: string[] | null = null;
+
+  for (const line of markdown.split('\n')) {
+    if (!line.trim().startsWith('|')) {
+      if (columns) break;
+      continue;
+    }
+
+    const cells = splitRow(line);
+    if (!columns) {
+      columns = cells.map(cell => cell.toLowerCase());
+      continue;
+    }
+    if (isSeparator(cells)) {
+      continue;
+    }
+
+    const fields = Object.keys(FIELD_HEADERS) as Array<keyof ProgressEntry>;
+    const entry = {} as ProgressEntry;
+    fields.forEach((field, position) => {
+      const index = columns!.findIndex(column => FIELD_HEADERS[field].includes(column));
+      entry[field] = cells[index === -1 ? position : index] ?? '';
+    });
+    entries.push(entry);
+  }
+
+  return entries;
+}
+
+/**
+ * Local time as "YYYY-MM-DD HH:MM:SS"
+ */
+export function formatProgressTimestamp(date: Date = new Date()): string {
+  const pad = (value: number) => String(value).padStart(2, '0');
+  return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())} `
+    + `${pad(date.getHours())}:${pad(date.getMinutes())}:${pad(date.getSeconds())}`;
+}
+
+/**
+ * One table row; pipes and line breaks in cells are escaped
+ */
+export function formatProgressRow(entry: ProgressEntry): string {
+  const cell = (text: string) => text.replace(/\r?\n/g, ' ').replace(/\|/g, '\\|').trim();
+  return `| ${[entry.timestamp, entry.action, entry.result, entry.next].map(cell).join(' | ')} |`;
+}
+
+/**
+ * Whether two entries record the same thing (timestamps aside)
+ */
+export function isDuplicateProgress(a: ProgressEntry, b: ProgressEntry): boolean {
+  const normalize = (text: string) => text.trim().replace(/\s+/g, ' ').toLowerCase();
+  return normalize(a.action) === normalize(b.action)
+    && normalize(a.result) === normalize(b.result)
+    && normalize(a.next) === normalize(b.next);
+}
+
+/**
+ * Read the log of a project; empty when there is none
+ */
+export function readProgress(cwd: string = process.cwd()): ProgressEntry[] {
+  try {
+    return parseProgress(fs.readFileSync(path.join(cwd, PROGRESS_FILE), 'utf-8'));
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * Append an entry after the last row of the log's table (creating the log
+ * when needed), unless it repeats the last entry
+ *
+ * @returns The entry written, or null when it was a duplicate
+ */
+export function appendProgress(
+  entry: Pick<ProgressEntry, 'action'> & Partial<ProgressEntry>,
+  cwd: string = process.cwd(),
+): ProgressEntry | null {
+  const full: ProgressEntry = {
+    timestamp: entry.timestamp ?? formatProgressTimestamp(),
+    action: entry.action,
+    result: entry.result ?? '',
+    next: entry.next ?? '',
+  };
+
+  const filePath = path.join(cwd, PROGRESS_FILE);
+  const existing = fs.existsSync(filePath) ? fs.readFileSync(filePath, 'utf-8') : '';
+  const entries = parseProgress(existing);
+  if (entries.length > 0 && isDuplicateProgress(entries[entries.length - 1], full)) {
+    return null;
+  }
+
+  const row = formatProgressRow(full);
+  const lines = existing.split('\n');
+  const tableStart = lines.findIndex(line => line.trim().startsWith('|'));
+  let content: string;
+
+  if (tableStart === -1) {
+    const header = `| ${PROGRESS_COLUMNS.join(' | ')} |\n|${PROGRESS_COLUMNS.map(() => '---').join('|')}|`;
+    content = existing.trim()
+      ? `${existing.trimEnd()}\n\n${header}\n${row}\n`
+      : `# Progress\n\n${header}\n${row}\n`;
+  } else {
+    let tableEnd = tableStart;
+    while (tableEnd + 1 < lines.length && lines[tableEnd + 1].trim().startsWith('|')) {
+      tableEnd++;
+    }
+    lines.splice(tableEnd + 1, 0, row);
+    content = lines.join('\n');
+  }
+
+  fs.mkdirSync(path.dirname(filePath), { recursive: true });
+  fs.writeFileSync(filePath, content);
+  return full;
+}
+
+/**
+ * Entries matching a query, oldest first
+ */
+export function queryProgress(entries: ProgressEntry[], query: ProgressQuery = {}): ProgressEntry[] {
+  const search = query.search?.toLowerCase();
+  const matches = entries.filter(entry => {
+    if (query.date && !entry.timestamp.startsWith(query.date)) return false;
+    if (search && ![entry.action, entry.result, entry.next].some(text => text.toLowerCase().includes(search))) return false;
+    return true;
+  });
+  if (query.last === undefined) {
+    return matches;
+  }
+  return query.last > 0 ? matches.slice(-query.last) : [];
+}
+
+/**
+ * Query for /progress arguments: a leading count, --date (or -d) and the
+ * remaining words as search text
+ */
+export function parseProgressArgs(args: string[]): ProgressQuery {
+  const query: ProgressQuery = {};
+  const words: string[] = [];
+  for (let i = 0; i < args.length; i++) {
+    const arg = args[i];
+    if ((arg === '--date' || arg === '-d') && args[i + 1]) {
+      query.date = args[++i];
+    } else if (/^\d+$/.test(arg) && words.length === 0 && query.last === undefined) {
+      query.last = parseInt(arg, 10);
+    } else {
+      words.push(arg);
+    }
+  }
+  if (words.length > 0) {
+    query.search = words.join(' ');
+  }
+  return query;
+}
@@ -0,0 +1,158 @@
+// Per-tool execution metrics: call counts, error rates and latency, so the
+// CLIs can show where a session's time went (/stats).
+
+/**
+ * Upper bounds (ms) of the latency histogram buckets; the last is open-ended
+ */
+export const LATENCY_BUCKETS_MS = [100, 500, 1000, 5000, 30000];
+
+/**
+ * Durations kept per tool for percentiles
+ */
+const MAX_SAMPLES = 500;
+
+export type ToolStats = {
+  tool: string;
+  calls: number;
+  successes: number;
+  errors: number;
+  /** errors / calls (0-1) */
+  errorRate: number;
+  totalMs: number;
+  avgMs: number;
+  p50Ms: number;
+  p95Ms: number;
+  maxMs: number;
+  /** Calls per latency bucket, one more entry than LATENCY_BUCKETS_MS */
+  histogram: number[];
+  lastUsed: number;
+};
+
+type ToolRecord = {
+  calls: number;
+  successes: number;
+  totalMs: number;
+  maxMs: number;
+  histogram: number[];
+  samples: number[];
+  lastUsed: number;
+};
+
+function percentile(sorted: number[], p: number): number {
+  if (sorted.length === 0) return 0;
+  return sorted[Math.min(sorted.length - 1, Math.ceil((p / 100) * sorted.length) - 1)];
+}
+
+export class ToolMetrics {
+  private records = new Map<string, ToolRecord>();
+
+  /**
+   * Record one finished tool call
+   */
+  record(tool: string, durationMs: number, success: boolean, at: number = Date.now()): void {
+    let record = this.records.get(tool);
+    if (!record) {
+      record = { calls: 0, successes: 0, totalMs: 0, maxMs: 0, histogram: new Array(LATENCY_BUCKETS_MS.length + 1).fill(0), samples: [], lastUsed: at };
+      this.records.set(tool, record);
+    }
+
+    const duration = Math.max(0, durationMs);
+    record.calls++;
+    if (success) record.successes++;
+    record.totalMs += duration;
+    record.maxMs = Math.max(record.maxMs, duration);
+    record.lastUsed = at;
+
+    const bucket = LATENCY_BUCKETS_MS.findIndex(bound => duration < bound);
+    record.histogram[bucket === -1 ? LATENCY_BUCKETS_MS.length : bucket]++;
+
+    record.samples.push(duration);
+    if (record.samples.length > MAX_SAMPLES) record.samples.shift();
+  }
+
+  /**
+   * Stats per tool, the most total time first
+   */
+  stats(): ToolStats[] {
+    return [...this.records.entries()]
+      .map(([tool, record]) => {
+        const sorted = [...record.samples].sort((a, b) => a - b);
+        const errors = record.calls - record.successes;
+        return {
+          tool,
+          calls: record.calls,
+          successes: record.successes,
+          errors,
+          errorRate: record.calls > 0 ? errors / record.calls : 0,
+          totalMs: record.totalMs,
+          avgMs: record.calls > 0 ? record.totalMs / record.calls : 0,
+          p50Ms: percentile(sorted, 50),
+          p95Ms: percentile(sorted, 95),
+          maxMs: record.maxMs,
+          histogram: [...record.histogram],
+          lastUsed: record.lastUsed,
+        };
+      })
+      .sort((a, b) => b.totalMs - a.totalMs || b.calls - a.calls);
+  }
+
+  reset(): void {
+    this.records.clear();
+  }
+}
+
+function formatMs(ms: number): string {
+  return ms >= 1000 ? `${(ms / 1000).toFixed(1)}s` : `${Math.round(ms)}ms`;
+}
+
+function histogramLabel(index: number): string {
+  return index < LATENCY_BUCKETS_MS.length
+    ? `<${formatMs(LATENCY_BUCKETS_MS[index])}`
+    : `≥${formatMs(LATENCY_BUCKETS_MS[LATENCY_BUCKETS_MS.length - 1])}`;
+}
+
+/**
+ * Plain-text table of tool stats, for /stats and end-of-run summaries
+ */
+export function formatToolStats(stats: ToolStats[], options: { limit?: number; histogram?: boolean } = {}): string[] {
+  if (stats.length === 0) {
+    return ['No tools have run yet.'];
+  }
+
+  const totalCalls = stats.reduce((sum, s) => sum + s.calls, 0);
+  const totalErrors = stats.reduce((sum, s) => sum + s.errors, 0);
+  const totalMs = stats.reduce((sum, s) => sum + s.totalMs, 0);
+  const shown = stats.slice(0, options.limit ?? stats.length);
+  const width = Math.max(4, ...shown.map(s => s.tool.length));
+
+  const lines = [
+    `${totalCalls} tool call${totalCalls === 1 ? '' : 's'}, ${totalErrors} failed, ${formatMs(totalMs)} total`,
+    '',
+    `${'Tool'.padEnd(width)}  Calls  Errors   Total    Avg    p50    p95    Max  Share`,
+  ];
+  for (const s of shown) {
+    const share = totalMs > 0 ? Math.round((s.totalMs / totalMs) * 100) : 0;
+    lines.push([
+      s.tool.padEnd(width),
+      String(s.calls).padStart(5),
+      `${s.errors}${s.errors > 0 ? ` (${Math.round(s.errorRate * 100)}%)` : ''}`.padStart(6),
+      formatMs(s.totalMs).padStart(7),
+      formatMs(s.avgMs).padStart(6),
+      formatMs(s.p50Ms).padStart(6),
+      formatMs(s.p95Ms).padStart(6),
+      formatMs(s.maxMs).padStart(6),
+      `${share}%`.padStart(5),
+    ].join('  '));
+
+    if (options.histogram) {
+      const buckets = s.histogram
+        .map((count, index) => (count > 0 ? `${histogramLabel(index)}: ${count}` : ''))
+        .filter(Boolean);
+      lines.push(`${' '.repeat(width)}  ${buckets.join('  ')}`);
+    }
+  }
+  if (shown.length < stats.length) {
+    lines.push(`… ${stats.length - shown.length} more tool${stats.length - shown.length === 1 ? '' : 's'}`);
+  }
+  return lines;
+}
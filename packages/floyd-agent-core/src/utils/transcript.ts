@@ -0,0 +1,233 @@
+// Conversation transcripts shared by both CLIs (/export, --export): user
+// turns, assistant markdown, tool calls and results rendered as Markdown or
+// a standalone HTML page under .floyd/exports/.
+
+import fs from 'fs/promises';
+import path from 'path';
+import type { Message } from '../agent/types.js';
+
+export type TranscriptFormat = 'md' | 'html';
+
+export type TranscriptMessage = Message & {
+  timestamp: number;
+  /** Tool input, for tool messages that carry their call */
+  input?: unknown;
+};
+
+export type TranscriptExportOptions = {
+  format: TranscriptFormat;
+  /** Heading and file name (default: "Floyd Conversation") */
+  title?: string;
+  /** Output directory (default: <cwd>/.floyd/exports) */
+  outputDir?: string;
+};
+
+/**
+ * Default output directory, relative to the project root
+ */
+export const TRANSCRIPT_DIR = path.join('.floyd', 'exports');
+
+const DEFAULT_TITLE = 'Floyd Conversation';
+
+/**
+ * A normalized block of transcript content
+ */
+type TranscriptBlock =
+  | { kind: 'text'; text: string }
+  | { kind: 'tool_call'; name: string; input: unknown }
+  | { kind: 'tool_result'; name?: string; text: string; isError?: boolean };
+
+/**
+ * Parse an export format argument, defaulting to Markdown
+ */
+export function parseTranscriptFormat(value?: string): TranscriptFormat | null {
+  if (!value || value === 'md' || value === 'markdown') {
+    return 'md';
+  }
+  if (value === 'html' || value === 'htm') {
+    return 'html';
+  }
+  return null;
+}
+
+/**
+ * Pretty-print JSON (or JSON strings), pass anything else through
+ */
+function stringify(value: unknown): string {
+  if (typeof value === 'string') {
+    try {
+      return JSON.stringify(JSON.parse(value), null, 2);
+    } catch {
+      return value;
+    }
+  }
+  return JSON.stringify(value, null, 2);
+}
+
+/**
+ * Flatten a message's content (string or Anthropic-style block array) into blocks
+ */
+function toBlocks(message: TranscriptMessage): TranscriptBlock[] {
+  if (message.role === 'tool') {
+    const blocks: TranscriptBlock[] = [];
+    if (message.input !== undefined) {
+      blocks.push({ kind: 'tool_call', name: message.name ?? 'tool', input: message.input });
+    }
+    blocks.push({ kind: 'tool_result', name: message.name, text: stringify(message.content) });
+    return blocks;
+  }
+
+  if (typeof message.content === 'string') {
+    return message.content.trim() ? [{ kind: 'text', text: message.content }] : [];
+  }
+
+  const blocks: TranscriptBlock[] = [];
+  for (const block of message.content ?? []) {
+    if (block?.type === 'text' && typeof block.text === 'string') {
+      blocks.push({ kind: 'text', text: block.text });
+    } else if (block?.type === 'tool_use') {
+      blocks.push({ kind: 'tool_call', name: block.name, input: block.input });
+    } else if (block?.type === 'tool_result') {
+      const text = Array.isArray(block.content)
+        ? block.content.map((c: { text?: string }) => c.text ?? '').join('\n')
+        : stringify(block.content);
+      blocks.push({ kind: 'tool_result', text, isError: block.is_error });
+    }
+  }
+  return blocks;
+}
+
+function roleLabel(role: Message['role']): string {
+  return role.charAt(0).toUpperCase() + role.slice(1);
+}
+
+function escapeHtml(text: string): string {
+  return text
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;');
+}
+
+/**
+ * Minimal markdown to HTML: fenced code blocks become <pre>, the rest keeps line breaks
+ */
+function markdownToHtml(markdown: string): string {
+  return markdown
+    .split(/```[\w-]*\n?/)
+    .map((part, i) => i % 2 === 1
+      ? `<pre><code>${escapeHtml(part.replace(/\n$/, ''))}</code></pre>`
+      : part.trim() ? `<div class="text">${escapeHtml(part.trim())}</div>` : '')
+    .join('');
+}
+
+/**
+ * Render messages as a Markdown transcript
+ */
+export function renderTranscriptMarkdown(messages: TranscriptMessage[], title = DEFAULT_TITLE): string {
+  const lines: string[] = [`# ${title}`, '', `_Exported ${new Date().toISOString()}_`, ''];
+
+  for (const message of messages) {
+    const blocks = toBlocks(message);
+    if (blocks.length === 0) {
+      continue;
+    }
+
+    if (message.role !== 'tool') {
+      lines.push(`## ${roleLabel(message.role)}`, `_${new Date(message.timestamp).toLocaleString()}_`, '');
+    }
+
+    for (const block of blocks) {
+      if (block.kind === 'text') {
+        lines.push(message.role === 'system' ? `> ${block.text.replace(/\n/g, '\n> ')}` : block.text, '');
+      } else if (block.kind === 'tool_call') {
+        lines.push(`### Tool call: ${block.name}`, '', '```json', stringify(block.input), '```', '');
+      } else {
+        const heading = block.isError ? 'Tool error' : 'Tool result';
+        lines.push(`### ${heading}${block.name ? `: ${block.name}` : ''}`, '', '```', block.text, '```', '');
+      }
+    }
+  }
+
+  return lines.join('\n');
+}
+
+/**
+ * Render messages as a standalone HTML transcript
+ */
+export function renderTranscriptHtml(messages: TranscriptMessage[], title = DEFAULT_TITLE): string {
+  const sections = messages
+    .map(message => {
+      const blocks = toBlocks(message);
+      if (blocks.length === 0) {
+        return '';
+      }
+
+      const body = blocks
+        .map(block => {
+          if (block.kind === 'text') {
+            return markdownToHtml(block.text);
+          }
+          if (block.kind === 'tool_call') {
+            return `<h3>Tool call: ${escapeHtml(block.name)}</h3><pre class="input">${escapeHtml(stringify(block.input))}</pre>`;
+          }
+          const heading = block.isError ? 'Tool error' : 'Tool result';
+          return `<h3>${heading}${block.name ? `: ${escapeHtml(block.name)}` : ''}</h3><pre>${escapeHtml(block.text)}</pre>`;
+        })
+        .join('');
+
+      const time = escapeHtml(new Date(message.timestamp).toLocaleString());
+      return `<section class="msg ${message.role}"><h2>${roleLabel(message.role)} <time>${time}</time></h2>${body}</section>`;
+    })
+    .filter(Boolean)
+    .join('\n');
+
+  return `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>${escapeHtml(title)}</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; background: #201F26; color: #DFDBDD; }
+h1 { color: #FF60FF; }
+h2, h3 { font-size: 1rem; margin: .5rem 0; }
+time { font-weight: normal; color: #858392; font-size: .85rem; }
+.msg { border-left: 3px solid #3A3943; padding: .5rem 1rem; margin: 1rem 0; }
+.user { border-color: #6B50FF; }
+.assistant { border-color: #FF60FF; }
+.tool { border-color: #00A4FF; }
+.system { border-color: #858392; color: #858392; }
+pre { background: #2D2C35; padding: .75rem; overflow-x: auto; border-radius: 4px; }
+.text { white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>${escapeHtml(title)}</h1>
+${sections}
+</body>
+</html>
+`;
+}
+
+/**
+ * Write a transcript to the output directory and return its path
+ */
+export async function exportTranscript(
+  messages: TranscriptMessage[],
+  options: TranscriptExportOptions,
+  cwd: string = process.cwd()
+): Promise<string> {
+  const title = options.title ?? DEFAULT_TITLE;
+  const outputDir = options.outputDir ?? path.join(cwd, TRANSCRIPT_DIR);
+  await fs.mkdir(outputDir, { recursive: true });
+
+  const stamp = new Date().toISOString().replace(/[:.]/g, '-');
+  const safeName = title.replace(/[^a-zA-Z0-9-_]/g, '_');
+  const filePath = path.join(outputDir, `${stamp}_${safeName}.${options.format}`);
+  const content = options.format === 'html'
+    ? renderTranscriptHtml(messages, title)
+    : renderTranscriptMarkdown(messages, title);
+
+  await fs.writeFile(filePath, content, 'utf-8');
+  return filePath;
+}